@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// AccessSpec is temporary access to ask the server for
+type AccessSpec struct {
+	Destination string        // IPv4 CIDR or address
+	Protocol    string        // "any", "tcp", "udp" or "icmp", empty for any
+	PortFrom    int           // 0 for any port
+	PortTo      int           // 0 for PortFrom only
+	Duration    time.Duration // rounded to seconds
+	Reason      string        // shown to the approving admin
+}
+
+// AccessRequestInfo is an access request of the agent. Once an admin
+// approves it, the server grants the access until ExpiresAt.
+type AccessRequestInfo struct {
+	ID          int        `json:"id"`
+	Destination string     `json:"destination"`
+	Protocol    string     `json:"protocol"`
+	PortFrom    int        `json:"port_from,omitempty"`
+	PortTo      int        `json:"port_to,omitempty"`
+	Duration    int        `json:"duration"` // seconds asked for
+	Reason      string     `json:"reason,omitempty"`
+	State       string     `json:"state"` // "pending", "approved", "denied" or "expired"
+	RequestedAt time.Time  `json:"requested_at"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // end of the grant, once approved
+}
+
+// AccessRequests is the control API response to access requests
+type AccessRequests struct {
+	Requests  []AccessRequestInfo `json:"requests"`
+	Error     string              `json:"error,omitempty"`
+	ErrorCode string              `json:"error_code,omitempty"`
+}
+
+// RequestAccess asks the server for temporary access, which an admin has
+// to approve
+func (a *Agent) RequestAccess(ctx context.Context, spec AccessSpec) (*AccessRequestInfo, error) {
+	client, sessionID, err := a.accessClient()
+	if err != nil {
+		return nil, err
+	}
+	if spec.PortFrom < 0 || spec.PortFrom > 65535 || spec.PortTo < 0 || spec.PortTo > 65535 {
+		return nil, &errcode.Error{Code: errcode.InvalidArgument, Err: errors.New("ports must be between 0 and 65535")}
+	}
+
+	resp, err := client.RequestAccess(ctx, &proto.RequestAccessRequest{
+		SessionId:   sessionID,
+		AgentId:     a.agentID,
+		Destination: spec.Destination,
+		Protocol:    spec.Protocol,
+		PortFrom:    uint32(spec.PortFrom),
+		PortTo:      uint32(spec.PortTo),
+		Duration:    int32(spec.Duration.Round(time.Second) / time.Second),
+		Reason:      spec.Reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to request access: %w", err)
+	}
+
+	info := accessRequestInfo(resp)
+	return &info, nil
+}
+
+// AccessRequests returns the access requests of the agent, newest first
+func (a *Agent) AccessRequests(ctx context.Context) ([]AccessRequestInfo, error) {
+	client, sessionID, err := a.accessClient()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.GetAccessRequests(ctx, &proto.AccessRequestsRequest{SessionId: sessionID, AgentId: a.agentID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access requests: %w", err)
+	}
+
+	requests := make([]AccessRequestInfo, 0, len(resp.Requests))
+	for _, r := range resp.Requests {
+		requests = append(requests, accessRequestInfo(r))
+	}
+	return requests, nil
+}
+
+// accessClient returns the client of the current session for access
+// requests, which need a server supporting them
+func (a *Agent) accessClient() (proto.AgentServiceClient, string, error) {
+	client, sessionID := a.current()
+	if client == nil || sessionID == "" {
+		return nil, "", &errcode.Error{Code: errcode.Conflict, Err: errors.New("not connected")}
+	}
+	if !a.serverSupports(proto.CapabilityAccessRequests) {
+		return nil, "", &errcode.Error{Code: errcode.Unsupported, Err: errors.New("server does not support access requests")}
+	}
+	return client, sessionID, nil
+}
+
+// accessRequestInfo converts an access request of the server
+func accessRequestInfo(r *proto.AccessRequest) AccessRequestInfo {
+	info := AccessRequestInfo{
+		ID:          int(r.RequestId),
+		Destination: r.Destination,
+		Protocol:    r.Protocol,
+		PortFrom:    int(r.PortFrom),
+		PortTo:      int(r.PortTo),
+		Duration:    int(r.Duration),
+		Reason:      r.Reason,
+		State:       r.State,
+		RequestedAt: r.RequestedAt.AsTime(),
+		DecidedBy:   r.DecidedBy,
+	}
+	if r.ExpiresAt != nil {
+		expires := r.ExpiresAt.AsTime()
+		info.ExpiresAt = &expires
+	}
+	return info
+}
@@ -2,20 +2,65 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/netip"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/taills/EasyAnyLink/common/config"
 	"github.com/taills/EasyAnyLink/common/crypto"
-	"github.com/taills/EasyAnyLink/common/proto"
+	"github.com/taills/EasyAnyLink/common/errcode"
+	"github.com/taills/EasyAnyLink/common/packet"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// defaultMTU is used when neither the config nor the server sets an MTU
+const defaultMTU = 1400
+
+// maxMessageSize is the largest gRPC message agents accept, announced at
+// registration so the granted MTU fits relayed packets into messages
+const maxMessageSize = 4 << 20
+
+// registerAttempts is how often a registration is sent on transient errors
+const registerAttempts = 3
+
+// tunnelSetupAttempts is how often the tunnel is set up on one session
+const tunnelSetupAttempts = 3
+
+// tunReadSlack leaves room above the MTU for packet information headers
+const tunReadSlack = 64
+
+// statusReportTimeout bounds a status report, it delays shutdown
+const statusReportTimeout = 2 * time.Second
+
+// sendQueueLen is how many packets of each traffic class a relay stream
+// queues before dropping
+const sendQueueLen = 256
+
+// protocolVersion is the protocol version of the easyanylink.v2 API
+const protocolVersion = "2.0.0"
+
+// agentCapabilities are the optional features announced to the server
+var agentCapabilities = []string{
+	proto.CapabilityEcho,
+	proto.CapabilityManagedConfig,
+	proto.CapabilityConfigGeneration,
+	proto.CapabilityGatewayReady,
+	proto.CapabilitySessionHandoff,
+}
+
 // Agent represents the agent instance
 type Agent struct {
 	config       *config.AgentConfig
@@ -25,14 +70,98 @@ type Agent struct {
 	routeManager *RouteManager
 	sessionID    string
 	assignedIP   string
+	gatewayIP    string        // server's overlay IP, the TUN peer address
+	serverMTU    int           // MTU granted by the server, 0 if none
+	serverMsgMax int           // largest message the server accepts, 0 if not announced
+	serverCaps   []string      // optional features the server announced
+	rekeyAfter   time.Duration // connection age after which the session moves to fresh keys, 0 if not asked
+	rekeyBytes   uint64        // bytes relayed after which it does, 0 if not asked
+	rekeys       uint32        // moves of the session to a connection with fresh keys
+	keyUpdates   uint64        // crypto.KeyUpdates when the session was registered
+	sessMu       sync.RWMutex  // guards the session fields above, replaced on reconnect
 	agentID      string
+	serverRoutes map[string]bool // destinations installed from server rules
+	generation   atomic.Uint64   // config generation of the applied route snapshot, set under routesMu
+	routesMu     sync.Mutex      // serializes route table changes
+	killSwitch   *KillSwitch
+	dns          *DNSConfigurator // nil for gateways
+
+	dnsSuspended bool       // set while reconnecting, the tunnel resolvers are unreachable
+	dnsMu        sync.Mutex // guards dnsSuspended and managed, and serializes DNS changes
+
+	managed *proto.ManagedConfig // settings of the agent's group pushed by the server, nil without a group
+
+	killSwitchPaused bool          // set while a captive portal is being authenticated
+	captiveCheck     chan struct{} // requests a captive portal probe
 
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
+	tunWg  sync.WaitGroup // TUN readers, which only return once the TUN is closed
+
+	stopping  atomic.Bool  // set once Stop starts, lost sessions are not reestablished
+	clockSkew atomic.Int64 // local clock minus server clock in nanoseconds, as last measured
 
 	stats   AgentStats
 	statsMu sync.RWMutex
+
+	// Socket owners of relayed flows, nil unless source_uids is set
+	owners     *socketOwners
+	localUsers localUserStats
+
+	// BGP state of a gateway with bgp set
+	bgpLearned   []string // sites learned from bgpd, guarded by bgpMu
+	bgpMu        sync.Mutex
+	bgpAnnounced map[string]bool // networks added to bgpd, owned by the bgp loop until it ends
+	sitesChanged atomic.Bool     // the advertised sites changed, sent with the next heartbeat
+
+	// VPC routes of a gateway with cloud_routes set, owned by the
+	// cloud-routes loop until it ends
+	cloudRouter cloudRouter     // nil until the instance was read
+	cloudRoutes map[string]bool // destinations pointed at the instance
+
+	classCounters packet.ClassCounters // relay send queue counters per traffic class
+
+	keepaliveInterval time.Duration // heartbeat interval set by the server, 0 for the default
+	keepaliveTimeout  time.Duration // dead-link timeout set by the server, 0 for the default
+	keepaliveMu       sync.Mutex
+
+	events *eventBus
+	health healthTracker // failures of supervised subsystems
+
+	lastStatus statusTracker   // last disconnect and error, for the control API
+	history    *trafficHistory // per-minute traffic, for the control API
+
+	senders     []*relaySender // current relay stream per TUN queue, nil while disconnected
+	sendersMu   sync.Mutex
+	echoSeq     atomic.Uint64
+	echoWaiters sync.Map // probe ID -> chan *proto.EchoProbe
+
+	control *controlServer
+	webUI   *webUI      // nil unless web_ui is set
+	tray    *trayServer // nil unless tray is set
+	sandbox string      // applied sandbox policy, empty without one
+
+	identity hardwareKey // signs registrations, nil without hardware_identity
+
+	server         string                   // server of the current session
+	serverFailures map[string]time.Time     // server -> last failed connection
+	busyUntil      map[string]time.Time     // server -> end of the retry delay it asked for while full
+	announced      []string                 // other servers the last server announced, for latency selection
+	serverRTT      map[string]time.Duration // server -> fastest handshake of the last measurement
+	measuredAt     time.Time                // time of the last latency measurement
+	handedOverBy   string                   // server that last handed the session over, see handoffHold
+	handedOverAt   time.Time                // when it did
+	serversMu      sync.Mutex               // guards the fields above and the server settings of config
+	netChanged     chan struct{}            // signals a network change for latency selection to measure again
+	lost           chan struct{}            // signals that the session must be reestablished
+	pause          chan struct{}            // signals that the user disconnected
+	resume         chan struct{}            // signals that the user connects again
+	paused         atomic.Bool              // set while disconnected by the user
+	rekeyDue       chan struct{}            // signals that the session must move to fresh keys
+	handoff        chan string              // signals the server the current server handed the session over to
+	sessCancel     context.CancelFunc       // stops the workers of the current session
+	sessWg         sync.WaitGroup
 }
 
 // AgentStats holds agent statistics
@@ -61,6 +190,39 @@ func NewAgent(cfg *config.AgentConfig) (*Agent, error) {
 		ctx:          ctx,
 		cancel:       cancel,
 		routeManager: NewRouteManager(),
+		serverRoutes: make(map[string]bool),
+		events:       newEventBus(),
+
+		serverFailures: make(map[string]time.Time),
+		busyUntil:      make(map[string]time.Time),
+		lost:           make(chan struct{}, 1),
+		rekeyDue:       make(chan struct{}, 1),
+		handoff:        make(chan string, 1),
+		netChanged:     make(chan struct{}, 1),
+		pause:          make(chan struct{}, 1),
+		resume:         make(chan struct{}, 1),
+	}
+
+	statsFile := cfg.StatsFile
+	if statsFile == "" {
+		statsFile = DefaultStatsFile
+	}
+	agent.history = newTrafficHistory(statsFile)
+
+	// The group of the agent may set resolvers the configuration does not
+	if cfg.Mode == "client" {
+		agent.dns = NewDNSConfigurator()
+	}
+
+	if cfg.KillSwitch && cfg.Mode == "client" {
+		agent.killSwitch = NewKillSwitch()
+		if cfg.CaptivePortal {
+			agent.captiveCheck = make(chan struct{}, 1)
+		}
+	}
+
+	if cfg.SourceUIDs {
+		agent.owners = newSocketOwners()
 	}
 
 	return agent, nil
@@ -70,16 +232,103 @@ func NewAgent(cfg *config.AgentConfig) (*Agent, error) {
 func (a *Agent) Start() error {
 	log.Printf("Starting agent in %s mode", a.config.Mode)
 
-	// Connect to server
-	if err := a.connect(); err != nil {
-		return fmt.Errorf("failed to connect to server: %w", err)
+	// Remove kill switch rules left behind by an agent that crashed, they
+	// would block the connection to a server that changed its address
+	if err := NewKillSwitch().Disable(); err != nil {
+		log.Printf("Warning: failed to remove stale kill switch rules: %v", err)
+	}
+	// Likewise put back the DNS configuration a crashed agent replaced
+	if err := NewDNSConfigurator().Restore(); err != nil {
+		log.Printf("Warning: failed to restore DNS configuration of a previous run: %v", err)
+	}
+
+	if err := a.openIdentity(); err != nil {
+		return err
+	}
+
+	// Connect and register with the first reachable server
+	if err := a.connectAny(); err != nil {
+		return err
+	}
+
+	// A tunnel that failed to come up is set up again on the session the
+	// server created rather than a new one
+	if err := a.setupTunnelRetrying(); err != nil {
+		a.abortStart(err)
+		return err
+	}
+
+	// Start background tasks. TUN readers live as long as the agent and
+	// send through the relay stream of the current session.
+	// Subsystems that fail or panic are restarted, see runSupervised.
+	a.senders = make([]*relaySender, a.tun.NumQueues())
+	a.startSession()
+	for i := 0; i < a.tun.NumQueues(); i++ {
+		queue := i
+		a.goSupervised(&a.tunWg, fmt.Sprintf("tun-reader-%d", queue), func() error {
+			return a.readTUN(queue)
+		})
+	}
+	a.goSupervised(&a.wg, "network-monitor", a.networkMonitorLoop)
+	a.goSupervised(&a.wg, "traffic-history", func() error {
+		a.recordTrafficLoop()
+		return nil
+	})
+	a.goSupervised(&a.wg, "session-supervisor", func() error {
+		a.supervise()
+		return nil
+	})
+
+	if a.config.BGP != nil {
+		a.goSupervised(&a.wg, "bgp", a.bgpLoop)
+	}
+	if a.config.CloudRoutes != nil {
+		a.goSupervised(&a.wg, "cloud-routes", a.cloudRoutesLoop)
+	}
+
+	if a.captiveCheck != nil {
+		a.goSupervised(&a.wg, "captive-portal", func() error {
+			a.captivePortalLoop()
+			return nil
+		})
+	}
+
+	if err := a.startControl(); err != nil {
+		log.Printf("Warning: control API unavailable: %v", err)
+	}
+	if a.config.WebUI != "" {
+		if err := a.startWebUI(); err != nil {
+			log.Printf("Warning: web UI unavailable: %v", err)
+		}
+	}
+	if a.config.Tray != nil {
+		if err := a.startTray(); err != nil {
+			log.Printf("Warning: tray API unavailable: %v", err)
+		}
 	}
 
-	// Register with server
-	if err := a.register(); err != nil {
-		return fmt.Errorf("failed to register: %w", err)
+	// Only route changes still need privileges from here on
+	if err := a.dropPrivileges(); err != nil {
+		err = fmt.Errorf("failed to drop privileges: %w", err)
+		a.abortStart(err)
+		return err
 	}
+	if err := a.applySandbox(); err != nil {
+		err = fmt.Errorf("failed to apply the sandbox: %w", err)
+		a.abortStart(err)
+		return err
+	}
+
+	assignedIP, _ := a.overlayAddrs()
+	log.Printf("Agent started successfully, ID: %s, IP: %s", a.agentID, assignedIP)
+
+	return nil
+}
 
+// setupTunnel creates the TUN interface and, in client mode, installs the
+// routes, kill switch and tunnel DNS. Gateways of a site mesh install the
+// routes to the other sites.
+func (a *Agent) setupTunnel() error {
 	// Create TUN interface
 	if err := a.setupTUN(); err != nil {
 		return fmt.Errorf("failed to setup TUN: %w", err)
@@ -90,40 +339,168 @@ func (a *Agent) Start() error {
 		if err := a.setupRouting(); err != nil {
 			return fmt.Errorf("failed to setup routing: %w", err)
 		}
-	}
 
-	// Start background tasks
-	a.wg.Add(3)
-	go a.heartbeatLoop()
-	go a.readTUN()
-	go a.relayData()
+		// The server's snapshot rather than the local rules is authoritative
+		// for server routes
+		if err := a.syncRoutes(); err != nil {
+			log.Printf("Failed to fetch routes: %v", err)
+		}
 
-	log.Printf("Agent started successfully, ID: %s, IP: %s", a.agentID, a.assignedIP)
+		// Block matched destinations outside the tunnel
+		a.routesMu.Lock()
+		err := a.updateKillSwitch()
+		a.routesMu.Unlock()
+		if err != nil {
+			return err
+		}
 
+		if err := a.applyDNS(); err != nil {
+			return err
+		}
+	} else if a.managesRoutes() {
+		// Routes to the sites of the other gateways of the mesh
+		if err := a.syncRoutes(); err != nil {
+			log.Printf("Failed to fetch site routes: %v", err)
+		}
+	}
 	return nil
 }
 
-// Stop stops the agent
-func (a *Agent) Stop() error {
-	log.Println("Stopping agent...")
-
-	// Cancel context to stop goroutines
-	a.cancel()
+// setupTunnelRetrying sets up the tunnel, undoing what a failed attempt
+// left and trying again on the same session, as for a TUN device another
+// process just released
+func (a *Agent) setupTunnelRetrying() error {
+	for attempt := 1; ; attempt++ {
+		err := a.setupTunnel()
+		if err == nil {
+			return nil
+		}
+		a.teardownTunnel()
+		a.tun = nil
+		if attempt == tunnelSetupAttempts {
+			return err
+		}
+		_, sessionID := a.current()
+		log.Printf("Tunnel setup attempt %d failed, retrying on session %s: %v", attempt, sessionID, err)
+		time.Sleep(time.Duration(attempt) * 2 * time.Second)
+	}
+}
 
-	// Wait for goroutines to finish
-	a.wg.Wait()
+// abortStart ends the session of an agent that failed to start, which the
+// server would otherwise keep online until it times out, and undoes the
+// tunnel setup
+func (a *Agent) abortStart(err error) {
+	a.reportStatus(proto.AgentStatus_ERROR, err.Error())
+	a.teardownTunnel()
+	if a.conn != nil {
+		a.conn.Close()
+	}
+}
 
-	// Cleanup routing
+// teardownTunnel removes the routes, kill switch rules and DNS settings of
+// the tunnel and closes its TUN interface
+func (a *Agent) teardownTunnel() {
 	if err := a.routeManager.Cleanup(); err != nil {
 		log.Printf("Warning: failed to cleanup routes: %v", err)
 	}
+	a.cleanupAppRouting()
+	a.cleanupRouteTable()
+
+	// Remove kill switch rules, the tunnel is intentionally down
+	if a.killSwitch != nil {
+		if err := a.killSwitch.Disable(); err != nil {
+			log.Printf("Warning: failed to disable kill switch: %v", err)
+		}
+	}
+	a.restoreDNS()
 
-	// Close TUN interface
+	// Close TUN interface, which unblocks the readers
 	if a.tun != nil {
 		if err := a.tun.Close(); err != nil {
 			log.Printf("Warning: failed to close TUN: %v", err)
 		}
 	}
+}
+
+// exportedSites returns the sites the gateway advertises, those configured
+// or learned over BGP that pass its site_export filter
+func (a *Agent) exportedSites() []string {
+	a.bgpMu.Lock()
+	configured := append(slices.Clip(a.config.Sites), a.bgpLearned...)
+	a.bgpMu.Unlock()
+
+	filter := a.config.SiteExport
+	if filter == nil {
+		return configured
+	}
+	var sites []string
+	for _, site := range configured {
+		if !filter.Exports(site) {
+			log.Printf("Not advertising site %s, site_export filters it", site)
+			continue
+		}
+		sites = append(sites, site)
+	}
+	return sites
+}
+
+// tunnelNetworks returns the networks the LAN reaches through the gateway:
+// the overlay and the sites of the other gateways of the mesh
+func (a *Agent) tunnelNetworks() map[string]bool {
+	networks := make(map[string]bool)
+	if assigned, _ := a.overlayAddrs(); assigned != "" {
+		// The TUN address is configured with a /16 mask
+		if addr, err := netip.ParseAddr(assigned); err == nil {
+			networks[netip.PrefixFrom(addr, 16).Masked().String()] = true
+		}
+	}
+	a.routesMu.Lock()
+	for destination := range a.serverRoutes {
+		networks[destination] = true
+	}
+	a.routesMu.Unlock()
+	return networks
+}
+
+// managesRoutes reports whether the agent installs forward routes from
+// the server: clients, and gateways advertising sites to the site mesh
+func (a *Agent) managesRoutes() bool {
+	return a.config.Mode == "client" || len(a.config.Sites) > 0 || a.config.BGP != nil
+}
+
+// Stop stops the agent
+func (a *Agent) Stop() error {
+	log.Println("Stopping agent...")
+
+	if a.control != nil {
+		a.control.close()
+	}
+	if a.webUI != nil {
+		a.webUI.close()
+	}
+	if a.tray != nil {
+		a.tray.close()
+	}
+
+	// Tell the server before the streams close, so it records a clean
+	// shutdown rather than a lost connection. The streams it closes in
+	// response must not trigger a reconnect.
+	a.stopping.Store(true)
+	a.reportStatus(proto.AgentStatus_OFFLINE, "agent stopped")
+
+	// Cancel context to stop goroutines
+	a.cancel()
+
+	// Wait for goroutines to finish
+	a.wg.Wait()
+	a.sessWg.Wait()
+
+	// Withdraw the networks announced to the LAN before their routes go
+	a.withdrawBGP()
+	a.withdrawCloudRoutes()
+
+	a.teardownTunnel()
+	a.tunWg.Wait()
 
 	// Close gRPC connection
 	if a.conn != nil {
@@ -131,40 +508,62 @@ func (a *Agent) Stop() error {
 			log.Printf("Warning: failed to close connection: %v", err)
 		}
 	}
+	if a.identity != nil {
+		a.identity.Close()
+	}
+
+	a.events.publish(Event{Type: EventStopped})
+	a.events.close()
 
 	log.Println("Agent stopped")
 	return nil
 }
 
+// Events subscribes to agent state changes. The returned channel is closed
+// when the agent stops or the returned function is called. Events are
+// dropped for subscribers that do not keep up.
+func (a *Agent) Events() (<-chan Event, func()) {
+	return a.events.subscribe()
+}
+
+// emitError publishes an error event and keeps it as the last error
+func (a *Agent) emitError(message string, err error) {
+	a.lastStatus.errorOccurred(message, err)
+	a.events.publish(Event{Type: EventError, Message: message, Error: err.Error(), ErrorCode: string(errcode.Of(err))})
+}
+
 // connect establishes gRPC connection to server using QUIC
-func (a *Agent) connect() error {
+func (a *Agent) connect(server string) error {
 	// Extract server address and hostname
-	host, _, err := net.SplitHostPort(a.config.Server)
+	host, _, err := net.SplitHostPort(server)
 	if err != nil {
 		return fmt.Errorf("invalid server address: %w", err)
 	}
 
 	// Load TLS configuration for QUIC (one-way TLS)
-	tlsConfig, err := crypto.LoadClientTLSConfig(host, a.config.InsecureSkipVerify)
+	tlsConfig, err := crypto.LoadClientTLSConfigWithCA(host, a.config.CAFile, a.config.InsecureSkipVerify)
 	if err != nil {
 		return fmt.Errorf("failed to load TLS configuration: %w", err)
 	}
 
+	// Check the server certificate against the server clock, validity
+	// periods do not account for a local clock that is off
+	tlsConfig.Time = a.serverNow
+
 	// Warn if certificate verification is disabled
 	if a.config.InsecureSkipVerify {
 		log.Println("WARNING: TLS certificate verification is disabled. This should only be used for debugging!")
 	}
 
-	// Create QUIC dialer
-	dialer := crypto.NewQUICDialer(tlsConfig)
-
-	// Create gRPC connection with QUIC transport
+	// Create gRPC connection with QUIC transport, or TLS over the TCP
+	// fallback
 	conn, err := grpc.Dial(
-		a.config.Server,
-		crypto.GRPCDialOption(dialer),
-		grpc.WithInsecure(), // TLS is handled by QUIC layer
+		server,
+		grpc.WithContextDialer(a.transportDialer(tlsConfig)),
+		grpc.WithInsecure(), // TLS is handled by the dialer
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize)),
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
-			Time:                30 * time.Second,
+			Time:                30 * time.Second, // servers enforce keepalive_min_time, at most 30s
 			Timeout:             10 * time.Second,
 			PermitWithoutStream: true,
 		}),
@@ -173,15 +572,18 @@ func (a *Agent) connect() error {
 		return fmt.Errorf("failed to dial server: %w", err)
 	}
 
+	a.sessMu.Lock()
 	a.conn = conn
 	a.client = proto.NewAgentServiceClient(conn)
+	a.sessMu.Unlock()
+	a.events.publish(Event{Type: EventConnected, Message: server})
 
-	log.Printf("Connected to server at %s using QUIC transport", a.config.Server)
+	log.Printf("Connected to server at %s using QUIC transport", server)
 	return nil
 }
 
 // register registers the agent with the server
-func (a *Agent) register() error {
+func (a *Agent) register(userKey string) error {
 	// Note: Certificate fingerprint is not needed for one-way TLS
 	// Server authenticates agent using user_key instead
 
@@ -193,54 +595,156 @@ func (a *Agent) register() error {
 		agentType = proto.AgentType_GATEWAY
 	}
 
+	// A standby server that took over continues the previous session
+	a.sessMu.RLock()
+	previousSession := a.sessionID
+	a.sessMu.RUnlock()
+
 	// Create registration request
 	req := &proto.RegisterRequest{
 		AgentId:         a.agentID,
-		UserKey:         a.config.UserKey,
+		UserKey:         userKey,
 		Type:            agentType,
-		ProtocolVersion: "1.0.0",
+		ProtocolVersion: protocolVersion,
+		Capabilities:    a.capabilities(),
 		Bandwidth:       int32(a.config.Bandwidth),
-		Metadata: &proto.AgentMetadata{
-			Os:       "darwin", // TODO: detect actual OS
-			Arch:     "amd64",  // TODO: detect actual arch
-			Version:  "1.0.0",
-			Hostname: "agent-" + a.agentID[:8],
-		},
+		RequestId:       uuid.New().String(),
+		Metadata:        a.collectMetadata(),
+		Mtu:             int32(a.config.TUN.MTU),
+		MaxMessageSize:  maxMessageSize,
+		SiteSubnets:     a.exportedSites(),
+		ResumeSessionId: previousSession,
 	}
 
-	// Send registration
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// Send registration, retrying transient failures with the same request
+	// ID so the server does not create a second session
+	var resp *proto.RegisterResponse
+	var err error
+	clockCorrected := false
+	for attempt := 1; ; attempt++ {
+		// Every attempt needs a fresh nonce, the server rejects reuse
+		if a.config.PSK != "" {
+			skew := time.Duration(a.clockSkew.Load())
+			if err := crypto.SignRegistration(a.config.PSK, req, skew); err != nil {
+				return err
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		// So does the identity challenge, the server accepts it once
+		err = a.signIdentity(ctx, req)
+		sent := time.Now()
+		if err == nil {
+			resp, err = a.client.Register(ctx, req)
+		}
+		cancel()
+
+		// A timestamp the local clock got wrong is signed again once
+		// against the server time
+		if !clockCorrected && a.clockRejected(err, sent, time.Now()) {
+			clockCorrected = true
+			log.Printf("Registration timestamp was off the server clock, retrying with the server time")
+			continue
+		}
+		if resp.GetServerTime() != nil {
+			a.measureClock(sent, time.Now(), resp.ServerTime.AsTime())
+		}
 
-	resp, err := a.client.Register(ctx, req)
+		code := status.Code(err)
+		if err == nil || attempt == registerAttempts ||
+			(code != codes.Unavailable && code != codes.DeadlineExceeded) {
+			break
+		}
+		log.Printf("Registration attempt %d failed, retrying: %v", attempt, err)
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
 	if err != nil {
 		return fmt.Errorf("registration failed: %w", err)
 	}
 
 	if !resp.Accepted {
-		return fmt.Errorf("registration rejected: %s", resp.ErrorMessage)
+		err := fmt.Errorf("registration rejected: %s", resp.ErrorMessage)
+		if resp.ErrorCode != "" {
+			err = &errcode.Error{Code: errcode.Code(resp.ErrorCode), Err: err}
+		}
+		return err
 	}
 
+	a.sessMu.Lock()
 	a.sessionID = resp.SessionId
 	a.assignedIP = resp.AssignedIp
+	a.serverCaps = resp.Capabilities
+	if resp.ServerConfig != nil {
+		a.gatewayIP = resp.ServerConfig.GatewayIp
+		a.serverMTU = int(resp.ServerConfig.Mtu)
+		a.serverMsgMax = int(resp.ServerConfig.MaxMessageSize)
+	}
+	a.rekeyAfter = time.Duration(resp.GetServerConfig().GetRekeyInterval()) * time.Second
+	a.rekeyBytes = resp.GetServerConfig().GetRekeyBytes()
+	a.rekeys, a.keyUpdates = 0, crypto.KeyUpdates()
+	a.sessMu.Unlock()
+	a.setAnnouncedServers(resp.Servers)
+	if resp.ServerConfig != nil {
+		a.setKeepalive(resp.ServerConfig.KeepaliveInterval, resp.ServerConfig.KeepaliveTimeout)
+	}
+	a.setManagedConfig(resp.ManagedConfig)
+	a.recordOverlayIP(resp.AssignedIp)
+	a.events.publish(Event{Type: EventRegistered, SessionID: resp.SessionId, AssignedIP: resp.AssignedIp})
 
-	log.Printf("Registration successful, session: %s, IP: %s", a.sessionID, a.assignedIP)
+	log.Printf("Registration successful, session: %s, IP: %s", resp.SessionId, resp.AssignedIp)
 
 	return nil
 }
 
 // setupTUN creates and configures the TUN interface
 func (a *Agent) setupTUN() error {
+	// The server grants the configured MTU up to its max_mtu and drops
+	// relayed packets larger than the granted one
+	mtu := a.config.TUN.MTU
+	if mtu == 0 {
+		mtu = a.serverMTU
+	}
+	if a.serverMTU > 0 && mtu > a.serverMTU {
+		log.Printf("Warning: tun.mtu %d exceeds the MTU granted by the server, using %d", mtu, a.serverMTU)
+		mtu = a.serverMTU
+	}
+	if mtu == 0 {
+		mtu = defaultMTU
+	}
+
 	// Create TUN interface
-	tun, err := NewTUNInterface("tun0", 1400)
+	tun, err := NewTUNInterface(a.config.TUN.Name, mtu, a.config.TUN.MultiQueue)
 	if err != nil {
 		return err
 	}
 
 	a.tun = tun
 
+	if err := tun.SetMTU(mtu); err != nil {
+		return err
+	}
+
+	if a.config.TUN.Queues > 1 {
+		if err := tun.OpenQueues(a.config.TUN.Queues); err != nil {
+			return err
+		}
+	}
+
+	if a.config.TUN.TxQueueLen > 0 {
+		if err := tun.SetTxQueueLen(a.config.TUN.TxQueueLen); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	// The overlay carries IPv4 only, keep the OS from sending IPv6 into it
+	if a.config.TUN.Family == "ipv4" {
+		if err := tun.DisableIPv6(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
 	// Set IP address
-	if err := tun.SetIP(a.assignedIP, "255.255.0.0"); err != nil {
+	if err := tun.SetIP(a.assignedIP, a.gatewayIP, "255.255.0.0"); err != nil {
 		return err
 	}
 
@@ -249,13 +753,24 @@ func (a *Agent) setupTUN() error {
 		return err
 	}
 
-	log.Printf("TUN interface %s created with IP %s", tun.Name(), a.assignedIP)
+	if err := a.setupPlatformRoutes(); err != nil {
+		return err
+	}
+
+	log.Printf("TUN interface %s created with IP %s, MTU %d", tun.Name(), a.assignedIP, mtu)
 
 	return nil
 }
 
 // setupRouting configures routing rules
 func (a *Agent) setupRouting() error {
+	if err := a.setupAppRouting(); err != nil {
+		return fmt.Errorf("failed to setup app routing: %w", err)
+	}
+	if err := a.setupRouteTable(); err != nil {
+		return fmt.Errorf("failed to setup routing table: %w", err)
+	}
+
 	if len(a.config.Rules) == 0 {
 		log.Println("No routing rules configured")
 		return nil
@@ -264,10 +779,24 @@ func (a *Agent) setupRouting() error {
 	for _, rule := range a.config.Rules {
 		switch rule.Action {
 		case "forward":
+			// Keep the local network reachable
+			if a.shadowsLAN(rule.Destination) {
+				continue
+			}
+
+			install, err := a.checkRouteConflict(rule.Destination)
+			if err != nil {
+				return err
+			}
+			if !install {
+				continue
+			}
+
 			// Route through overlay
 			if err := a.routeManager.AddRoute(rule.Destination, "", a.tun.Name()); err != nil {
 				return fmt.Errorf("failed to add forward route: %w", err)
 			}
+			a.events.publish(Event{Type: EventRouteInstalled, Route: rule.Destination})
 			log.Printf("Added route: %s via %s", rule.Destination, a.tun.Name())
 
 		case "direct":
@@ -283,24 +812,175 @@ func (a *Agent) setupRouting() error {
 	return nil
 }
 
-// heartbeatLoop sends periodic heartbeats
-func (a *Agent) heartbeatLoop() {
-	defer a.wg.Done()
+// refreshRoutes fetches routing rules from the server and reconciles
+// the forward routes installed through the overlay. The generation of the
+// snapshot counts as applied once every route and setting of it is, the
+// server pushes it again otherwise.
+func (a *Agent) refreshRoutes() error {
+	ctx, cancel := context.WithTimeout(a.ctx, 10*time.Second)
+	defer cancel()
+
+	client, sessionID := a.current()
+	resp, err := client.GetRoutes(ctx, &proto.RouteRequest{
+		SessionId: sessionID,
+		AgentId:   a.agentID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get routes: %w", err)
+	}
+
+	// A slow response may arrive after a newer snapshot was applied
+	if applied := a.generation.Load(); resp.Generation < applied {
+		log.Printf("Ignoring routes of config generation %d, generation %d is applied", resp.Generation, applied)
+		return nil
+	}
+
+	// Group settings travel with the routes
+	failed := false
+	if a.setManagedConfig(resp.ManagedConfig) {
+		if err := a.applyDNS(); err != nil {
+			err = errcode.Wrap(errcode.DNSFailed, err)
+			log.Printf("Failed to apply group DNS: %v", err)
+			a.emitError("failed to apply group DNS", err)
+			failed = true
+		}
+	}
+
+	a.routesMu.Lock()
+	defer a.routesMu.Unlock()
+
+	desired := make(map[string]bool)
+	for _, rule := range resp.Rules {
+		if rule.Enabled && rule.Action == proto.RouteAction_FORWARD {
+			desired[rule.Destination] = true
+		}
+	}
+
+	// Remove routes no longer present on the server
+	added, removed := 0, 0
+	for destination := range a.serverRoutes {
+		if desired[destination] {
+			continue
+		}
+		if err := a.routeManager.DeleteRoute(destination); err != nil {
+			log.Printf("Warning: failed to delete route %s: %v", destination, err)
+			failed = true // kept to retry on the next push
+			continue
+		}
+		delete(a.serverRoutes, destination)
+		a.events.publish(Event{Type: EventRouteRemoved, Route: destination})
+		log.Printf("Removed route: %s", destination)
+		removed++
+	}
+
+	// Install newly added routes
+	for destination := range desired {
+		if a.serverRoutes[destination] || a.shadowsLAN(destination) {
+			continue
+		}
+		install, err := a.checkRouteConflict(destination)
+		if err != nil {
+			err = errcode.Wrap(errcode.RouteFailed, err)
+			log.Printf("Warning: not installing route %s: %v", destination, err)
+			a.emitError("route conflict", err)
+			continue
+		}
+		if !install {
+			continue
+		}
+		if err := a.routeManager.AddRoute(destination, "", a.tun.Name()); err != nil {
+			err = errcode.Wrap(errcode.RouteFailed, err)
+			log.Printf("Warning: failed to add route %s: %v", destination, err)
+			a.emitError("failed to add route "+destination, err)
+			failed = true
+			continue
+		}
+		a.serverRoutes[destination] = true
+		a.events.publish(Event{Type: EventRouteInstalled, Route: destination})
+		log.Printf("Added route: %s via %s", destination, a.tun.Name())
+		added++
+	}
+
+	if err := a.updateKillSwitch(); err != nil {
+		err = errcode.Wrap(errcode.KillSwitchFailed, err)
+		a.emitError("failed to update kill switch", err)
+		return err
+	}
+
+	if failed {
+		return fmt.Errorf("config generation %d applied partially", resp.Generation)
+	}
+	if resp.Generation != a.generation.Load() {
+		log.Printf("Applied config generation %d: %d route(s) added, %d removed", resp.Generation, added, removed)
+		a.generation.Store(resp.Generation)
+	}
+	return nil
+}
+
+// syncRoutes applies the server's snapshot after (re)connecting. Routes of
+// the previous session that vanished from the system table meanwhile are
+// re-added first, so the snapshot is diffed against what is installed, and
+// the generation starts over as the agent may have moved to another server.
+func (a *Agent) syncRoutes() error {
+	a.routesMu.Lock()
+	a.generation.Store(0)
+	restored, err := a.routeManager.RestoreRoutes()
+	a.routesMu.Unlock()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if restored > 0 {
+		log.Printf("Restored %d route(s) missing since the last session", restored)
+	}
+	return a.refreshRoutes()
+}
+
+// heartbeatLoop sends heartbeats at the interval set by the server until
+// ctx ends the session. The session is lost when no response arrives
+// within the server's keepalive timeout.
+func (a *Agent) heartbeatLoop(ctx context.Context) {
+	defer a.sessWg.Done()
+	defer a.recoverSession(ctx, "heartbeat")
+
+	// Cancelling the stream unblocks Send and Recv on a dead link
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	defer cancelStream()
 
-	stream, err := a.client.Heartbeat(a.ctx)
+	client, sessionID := a.current()
+	stream, err := client.Heartbeat(streamCtx)
 	if err != nil {
+		err = errcode.Wrap(errcode.StreamFailed, err)
 		log.Printf("Failed to create heartbeat stream: %v", err)
+		a.emitError("failed to create heartbeat stream", err)
+		a.sessionLost(ctx, err)
 		return
 	}
 
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
+	interval, timeout := a.keepalive()
+	jitter := a.obfuscating()
+	beat := time.NewTimer(heartbeatDelay(interval, timeout, jitter))
+	defer beat.Stop()
+
+	// A QUIC connection can look alive while the path drops everything
+	deadLink := time.AfterFunc(timeout, cancelStream)
+	defer deadLink.Stop()
+	linkError := func(err error) error {
+		if ctx.Err() == nil && streamCtx.Err() != nil {
+			return fmt.Errorf("%w within %s", errNoHeartbeat, timeout)
+		}
+		return err
+	}
+
+	// The first heartbeat of a session sends the metadata again, the TUN
+	// did not exist yet when it was collected for the registration
+	var sent *proto.AgentMetadata
+	var collected time.Time
 
 	for {
 		select {
-		case <-a.ctx.Done():
+		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-beat.C:
 			a.statsMu.RLock()
 			stats := &proto.AgentStats{
 				BytesSent:       a.stats.BytesSent,
@@ -309,92 +989,181 @@ func (a *Agent) heartbeatLoop() {
 				PacketsReceived: uint64(a.stats.PacketsReceived),
 				Errors:          a.stats.Errors,
 				Drops:           a.stats.Drops,
+				Classes:         trafficClassStats(a.classCounters.Stats()),
+				LocalUsers:      a.localUsers.snapshot(),
 			}
 			a.statsMu.RUnlock()
+			a.sessMu.RLock()
+			stats.Rekeys, stats.KeyUpdates = a.rekeys, crypto.KeyUpdates()-a.keyUpdates
+			a.sessMu.RUnlock()
 
+			sentAt := time.Now()
 			req := &proto.HeartbeatRequest{
-				SessionId: a.sessionID,
-				Stats:     stats,
+				SessionId:        sessionID,
+				Timestamp:        timestamppb.New(sentAt),
+				Stats:            stats,
+				Health:           a.healthProto(),
+				ConfigGeneration: a.generation.Load(),
+				ClockSkewMs:      time.Duration(a.clockSkew.Load()).Milliseconds(),
+			}
+			if a.sitesChanged.Swap(false) {
+				req.Sites = &proto.SiteUpdate{Subnets: a.exportedSites()}
+			}
+			if time.Since(collected) >= metadataRefreshInterval {
+				collected = time.Now()
+				if metadata := a.collectMetadata(); !protobuf.Equal(metadata, sent) {
+					req.Metadata = metadata
+					sent = metadata
+				}
 			}
 
 			if err := stream.Send(req); err != nil {
+				err = linkError(err)
+				err = errcode.Wrap(errcode.StreamFailed, err)
 				log.Printf("Failed to send heartbeat: %v", err)
+				a.emitError("failed to send heartbeat", err)
+				a.triggerCaptiveCheck()
+				a.sessionLost(ctx, err)
 				return
 			}
 
-			// Receive response (optional)
-			_, err := stream.Recv()
+			resp, err := stream.Recv()
 			if err != nil {
+				err = linkError(err)
+				err = errcode.Wrap(errcode.StreamFailed, err)
 				log.Printf("Failed to receive heartbeat response: %v", err)
+				a.emitError("failed to receive heartbeat response", err)
+				a.triggerCaptiveCheck()
+				a.sessionLost(ctx, err)
 				return
 			}
-		}
-	}
-}
-
-// readTUN reads packets from TUN and sends to server
-func (a *Agent) readTUN() {
-	defer a.wg.Done()
-
-	buf := make([]byte, 2048)
 
-	for {
-		select {
-		case <-a.ctx.Done():
-			return
-		default:
-			n, err := a.tun.Read(buf)
-			if err != nil {
-				log.Printf("Failed to read from TUN: %v", err)
-				return
+			if resp.Timestamp != nil {
+				a.measureClock(sentAt, time.Now(), resp.Timestamp.AsTime())
 			}
 
-			// Send packet to server
-			// This will be implemented in relayData
+			// Admins can retune the keepalive of connected agents
+			if a.setKeepalive(resp.KeepaliveInterval, resp.KeepaliveTimeout) {
+				interval, timeout = a.keepalive()
+			}
+			beat.Reset(heartbeatDelay(interval, timeout, jitter))
+			deadLink.Reset(timeout)
+
+			if resp.ShouldRefreshRoutes && a.managesRoutes() {
+				if err := a.refreshRoutes(); err != nil {
+					err = errcode.Wrap(errcode.RouteFailed, err)
+					log.Printf("Failed to refresh routes: %v", err)
+					a.emitError("failed to refresh routes", err)
+				}
+			}
 
-			a.statsMu.Lock()
-			a.stats.BytesSent += uint64(n)
-			a.stats.PacketsSent++
-			a.statsMu.Unlock()
+			if resp.HandoffServer != "" {
+				a.requestHandoff(resp.HandoffServer)
+			}
 		}
 	}
 }
 
-// relayData handles data relay with server
-func (a *Agent) relayData() {
-	defer a.wg.Done()
-
-	stream, err := a.client.RelayData(a.ctx)
+// relayData runs the data relay for one TUN queue over its own relay
+// stream, so each queue's flows stay on one stream and queues scale across
+// cores. It runs until ctx ends the session.
+func (a *Agent) relayData(ctx context.Context, queue int) {
+	defer a.sessWg.Done()
+	defer a.recoverSession(ctx, fmt.Sprintf("relay-%d", queue))
+
+	client, sessionID := a.current()
+	var opts []grpc.CallOption
+	a.sessMu.RLock()
+	if a.serverMsgMax > 0 {
+		opts = append(opts, grpc.MaxCallSendMsgSize(a.serverMsgMax))
+	}
+	a.sessMu.RUnlock()
+	stream, err := client.RelayData(ctx, opts...)
 	if err != nil {
+		err = errcode.Wrap(errcode.StreamFailed, err)
 		log.Printf("Failed to create relay stream: %v", err)
+		a.emitError("failed to create relay stream", err)
+		a.sessionLost(ctx, err)
 		return
 	}
 
 	// Send first packet with session info
 	initialPacket := &proto.DataPacket{
-		SessionId:     a.sessionID,
+		SessionId:     sessionID,
 		SourceAgentId: a.agentID,
 	}
 
 	if err := stream.Send(initialPacket); err != nil {
 		log.Printf("Failed to send initial packet: %v", err)
+		a.sessionLost(ctx, err)
 		return
 	}
 
+	q := a.packetQueue(queue)
+	sender := &relaySender{
+		stream:    stream,
+		sessionID: sessionID,
+		pad:       a.obfuscating(),
+		queue:     packet.NewClassQueue[*proto.DataPacket](sendQueueLen, &a.classCounters),
+	}
+	sendCtx, stopSending := context.WithCancel(ctx)
+	defer stopSending()
+	a.sessWg.Add(1)
+	go func() {
+		defer a.sessWg.Done()
+		defer a.recoverSession(ctx, fmt.Sprintf("relay-sender-%d", queue))
+		sender.run(sendCtx)
+	}()
+	defer a.setRelaySender(queue, sender)()
+
 	// Receive packets from server and write to TUN
 	for {
 		select {
-		case <-a.ctx.Done():
+		case <-ctx.Done():
 			return
 		default:
 			packet, err := stream.Recv()
 			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				err = errcode.Wrap(errcode.StreamFailed, err)
 				log.Printf("Failed to receive packet: %v", err)
+				a.emitError("failed to receive packet", err)
+				a.sessionLost(ctx, err)
 				return
 			}
 
+			// Overlay echo probes carry no payload
+			if packet.Echo != nil {
+				a.handleEcho(sender, packet)
+				continue
+			}
+
+			// Answer pings to the overlay IP without the OS stack
+			if reply := a.echoReply(packet.Payload); reply != nil {
+				if err := sender.Send(&proto.DataPacket{
+					SessionId:          sessionID,
+					SourceAgentId:      a.agentID,
+					DestinationAgentId: packet.SourceAgentId,
+					Payload:            reply,
+				}); err != nil {
+					log.Printf("Failed to send echo reply: %v", err)
+				}
+				continue
+			}
+
+			// Never hand the kernel a malformed or oversized packet
+			if err := a.validatePacket(packet.Payload); err != nil {
+				log.Printf("Dropping invalid packet from %s: %v", packet.SourceAgentId, err)
+				a.statsMu.Lock()
+				a.stats.Drops++
+				a.statsMu.Unlock()
+				continue
+			}
+
 			// Write to TUN
-			if _, err := a.tun.Write(packet.Payload); err != nil {
+			if _, err := q.Write(packet.Payload); err != nil {
 				log.Printf("Failed to write to TUN: %v", err)
 				a.statsMu.Lock()
 				a.stats.Drops++
@@ -406,13 +1175,192 @@ func (a *Agent) relayData() {
 			a.stats.BytesReceived += uint64(len(packet.Payload))
 			a.stats.PacketsReceived++
 			a.statsMu.Unlock()
+			if uid, ok := a.owners.lookup(packet.Payload, false); ok {
+				a.localUsers.received(uid, len(packet.Payload))
+			}
+		}
+	}
+}
+
+// relaySender queues the packets of the TUN reader and the ICMP responder
+// by traffic class and writes them to a relay stream, interactive traffic
+// first
+type relaySender struct {
+	stream    proto.AgentService_RelayDataClient
+	sessionID string
+	pad       bool // packets are padded, see proto.Pad
+	queue     *packet.ClassQueue[*proto.DataPacket]
+}
+
+// Send queues a packet for the relay stream, failing if its class queue
+// is full
+func (r *relaySender) Send(dp *proto.DataPacket) error {
+	class := packet.Classify(dp.Payload)
+	if dp.Echo != nil {
+		class = packet.ClassInteractive // latency probes
+	}
+	if !r.queue.Push(class, dp) {
+		return fmt.Errorf("%s send queue full", class)
+	}
+	return nil
+}
+
+// run writes queued packets to the relay stream until ctx ends or the
+// stream fails, the receiving side then notices the broken stream
+func (r *relaySender) run(ctx context.Context) {
+	for {
+		dp, ok := r.queue.Pop(ctx.Done())
+		if !ok {
+			return
+		}
+		if r.pad {
+			proto.Pad(dp)
+		}
+		if err := r.stream.Send(dp); err != nil {
+			log.Printf("Failed to send on relay stream: %v", err)
+			return
 		}
 	}
 }
 
+// readTUN reads packets from a TUN queue and sends them through the
+// relay stream of the current session, dropping them while reconnecting.
+// It returns read errors for the supervisor to restart it.
+func (a *Agent) readTUN(queue int) error {
+	q := a.packetQueue(queue)
+	buf := make([]byte, a.tun.MTU()+tunReadSlack)
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return nil
+		default:
+			n, err := q.Read(buf)
+			if errors.Is(err, packet.ErrBadFrame) {
+				// Layer 2 traffic such as ARP has no place on the overlay
+				a.statsMu.Lock()
+				a.stats.Drops++
+				a.statsMu.Unlock()
+				continue
+			}
+			if err != nil {
+				if a.ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to read from TUN: %w", err)
+			}
+
+			// The overlay is IPv4-only, the server would drop anything else
+			sender := a.queueSender(queue)
+			if sender == nil || a.validatePacket(buf[:n]) != nil {
+				a.statsMu.Lock()
+				a.stats.Drops++
+				a.statsMu.Unlock()
+				continue
+			}
+
+			packet := &proto.DataPacket{
+				SessionId:     sender.sessionID,
+				SourceAgentId: a.agentID,
+				Payload:       append([]byte(nil), buf[:n]...),
+			}
+			if uid, ok := a.owners.lookup(packet.Payload, true); ok {
+				packet.SourceUid = &uid
+				a.localUsers.sent(uid, n)
+			}
+			if err := sender.Send(packet); err != nil {
+				// The session's relay worker notices the broken stream
+				a.statsMu.Lock()
+				a.stats.Drops++
+				a.statsMu.Unlock()
+				continue
+			}
+
+			a.statsMu.Lock()
+			a.stats.BytesSent += uint64(n)
+			a.stats.PacketsSent++
+			a.statsMu.Unlock()
+		}
+	}
+}
+
+// reportStatus tells the server about a lifecycle change of the current
+// session. It is best effort, without it the server ends the session once
+// its streams close; only READY is retried on error.
+func (a *Agent) reportStatus(agentStatus proto.AgentStatus, message string) error {
+	client, sessionID := a.current()
+	if client == nil || sessionID == "" {
+		return errors.New("no session")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusReportTimeout)
+	defer cancel()
+	_, err := client.UpdateStatus(ctx, &proto.StatusUpdate{
+		SessionId: sessionID,
+		AgentId:   a.agentID,
+		Status:    agentStatus,
+		Message:   message,
+	})
+	if err != nil {
+		log.Printf("Failed to report %s status: %v", agentStatus, err)
+	}
+	return err
+}
+
+// current returns the client and ID of the current session
+func (a *Agent) current() (proto.AgentServiceClient, string) {
+	a.sessMu.RLock()
+	defer a.sessMu.RUnlock()
+	return a.client, a.sessionID
+}
+
+// capabilities returns the optional features the agent announces,
+// padding only when configured to obfuscate its traffic
+func (a *Agent) capabilities() []string {
+	if !a.config.Obfuscate {
+		return agentCapabilities
+	}
+	return append(slices.Clip(agentCapabilities), proto.CapabilityPadding)
+}
+
+// obfuscating reports whether the current session pads relayed packets
+// and randomizes heartbeat timing: the agent asked and the server agreed
+func (a *Agent) obfuscating() bool {
+	return a.config.Obfuscate && a.serverSupports(proto.CapabilityPadding)
+}
+
+// serverSupports reports whether the server of the current session
+// announced an optional feature
+func (a *Agent) serverSupports(capability string) bool {
+	a.sessMu.RLock()
+	defer a.sessMu.RUnlock()
+	return slices.Contains(a.serverCaps, capability)
+}
+
+// overlayAddrs returns the assigned overlay IP and the gateway IP of the
+// current session
+func (a *Agent) overlayAddrs() (assigned, gateway string) {
+	a.sessMu.RLock()
+	defer a.sessMu.RUnlock()
+	return a.assignedIP, a.gatewayIP
+}
+
 // GetStats returns current agent statistics
 func (a *Agent) GetStats() AgentStats {
 	a.statsMu.RLock()
 	defer a.statsMu.RUnlock()
 	return a.stats
 }
+
+// trafficClassStats converts traffic class counters to their proto form
+func trafficClassStats(stats []packet.ClassStats) []*proto.TrafficClassStats {
+	result := make([]*proto.TrafficClassStats, 0, len(stats))
+	for _, cs := range stats {
+		result = append(result, &proto.TrafficClassStats{
+			Class:   cs.Class.String(),
+			Queued:  cs.Queued,
+			Dropped: cs.Dropped,
+		})
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+//go:build linux
+
+package agent
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// appRoutingChain is the iptables chain (mangle and nat tables) used to
+// mark and masquerade per-application traffic
+const appRoutingChain = "EASYANYLINK-APPS"
+
+// setupAppRouting marks traffic of the configured uids and cgroups and
+// routes only marked traffic through the tunnel via a policy routing table
+func (a *Agent) setupAppRouting() error {
+	cfg := a.config.AppRouting
+	if cfg == nil {
+		return nil
+	}
+
+	mark := fmt.Sprintf("0x%x", cfg.Mark)
+	table := strconv.Itoa(cfg.Table)
+
+	// Forward routes go into the application table instead of main
+	a.routeManager.SetTable(cfg.Table)
+
+	// iptables -t mangle -N EASYANYLINK-APPS (may already exist)
+	for _, t := range []string{"mangle", "nat"} {
+		privCommand("iptables", "-t", t, "-N", appRoutingChain).Run()
+		if err := iptables("-t", t, "-F", appRoutingChain); err != nil {
+			return err
+		}
+	}
+	if privCommand("iptables", "-t", "mangle", "-C", "OUTPUT", "-j", appRoutingChain).Run() != nil {
+		if err := iptables("-t", "mangle", "-A", "OUTPUT", "-j", appRoutingChain); err != nil {
+			return err
+		}
+	}
+	if privCommand("iptables", "-t", "nat", "-C", "POSTROUTING", "-j", appRoutingChain).Run() != nil {
+		if err := iptables("-t", "nat", "-A", "POSTROUTING", "-j", appRoutingChain); err != nil {
+			return err
+		}
+	}
+
+	for _, uid := range cfg.UIDs {
+		if err := iptables("-t", "mangle", "-A", appRoutingChain,
+			"-m", "owner", "--uid-owner", strconv.Itoa(uid), "-j", "MARK", "--set-mark", mark); err != nil {
+			return err
+		}
+	}
+	for _, cgroup := range cfg.Cgroups {
+		if err := iptables("-t", "mangle", "-A", appRoutingChain,
+			"-m", "cgroup", "--path", cgroup, "-j", "MARK", "--set-mark", mark); err != nil {
+			return err
+		}
+	}
+
+	// Marked packets picked the physical source address before rerouting
+	if err := iptables("-t", "nat", "-A", appRoutingChain,
+		"-o", a.tun.Name(), "-m", "mark", "--mark", mark, "-j", "MASQUERADE"); err != nil {
+		return err
+	}
+
+	// ip rule add fwmark 0x8228 lookup 8228
+	privCommand("ip", "rule", "del", "fwmark", mark, "lookup", table).Run()
+	if output, err := privCommand("ip", "rule", "add", "fwmark", mark, "lookup", table).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to add ip rule: %w: %s", err, output)
+	}
+
+	// Replies arrive on the tunnel for sockets bound to the physical address
+	rpFilter := fmt.Sprintf("/proc/sys/net/ipv4/conf/%s/rp_filter", a.tun.Name())
+	if err := writeSystemFile(rpFilter, []byte("2")); err != nil {
+		log.Printf("Warning: failed to set loose rp_filter on %s: %v", a.tun.Name(), err)
+	}
+
+	log.Printf("Per-application routing enabled: %d uid(s), %d cgroup(s), table %s",
+		len(cfg.UIDs), len(cfg.Cgroups), table)
+	return nil
+}
+
+// cleanupAppRouting removes the marking rules and the ip rule
+func (a *Agent) cleanupAppRouting() {
+	cfg := a.config.AppRouting
+	if cfg == nil {
+		return
+	}
+
+	privCommand("ip", "rule", "del", "fwmark", fmt.Sprintf("0x%x", cfg.Mark), "lookup", strconv.Itoa(cfg.Table)).Run()
+
+	privCommand("iptables", "-t", "mangle", "-D", "OUTPUT", "-j", appRoutingChain).Run()
+	privCommand("iptables", "-t", "nat", "-D", "POSTROUTING", "-j", appRoutingChain).Run()
+	for _, t := range []string{"mangle", "nat"} {
+		privCommand("iptables", "-t", t, "-F", appRoutingChain).Run()
+		privCommand("iptables", "-t", t, "-X", appRoutingChain).Run()
+	}
+}
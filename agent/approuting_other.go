@@ -0,0 +1,16 @@
+//go:build !linux
+
+package agent
+
+import "log"
+
+// setupAppRouting is only supported on Linux
+func (a *Agent) setupAppRouting() error {
+	if a.config.AppRouting != nil {
+		log.Println("app_routing is only supported on Linux, ignoring")
+	}
+	return nil
+}
+
+// cleanupAppRouting is a no-op on this platform
+func (a *Agent) cleanupAppRouting() {}
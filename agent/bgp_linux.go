@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/netip"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// bgpPath is a path to a prefix in the BGP table vtysh shows as JSON
+type bgpPath struct {
+	Valid    bool   `json:"valid"`
+	Bestpath bool   `json:"bestpath"`
+	PeerID   string `json:"peerId"` // "(unspec)" for networks of this router
+}
+
+// bgpLoop syncs with bgpd every bgp.interval until the agent stops. A
+// failed sync is retried with the next one, bgpd may start after the
+// agent.
+func (a *Agent) bgpLoop() error {
+	ticker := time.NewTicker(time.Duration(a.config.BGP.Interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		if err := a.syncBGP(); err != nil {
+			log.Printf("Failed to sync with bgpd: %v", err)
+		}
+		select {
+		case <-a.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncBGP announces the networks reached through the tunnel and takes the
+// prefixes learned from the LAN routers as sites, sent to the server with
+// the next heartbeat when they changed
+func (a *Agent) syncBGP() error {
+	cfg := a.config.BGP
+	if cfg.Announce {
+		if err := a.announceBGP(a.tunnelNetworks()); err != nil {
+			return err
+		}
+	}
+	if len(cfg.Learn) == 0 {
+		return nil
+	}
+
+	learned, err := a.learnBGP()
+	if err != nil {
+		return err
+	}
+	a.bgpMu.Lock()
+	changed := !slices.Equal(learned, a.bgpLearned)
+	a.bgpLearned = learned
+	a.bgpMu.Unlock()
+	if changed {
+		log.Printf("Sites learned over BGP: %v", learned)
+		a.sitesChanged.Store(true)
+	}
+	return nil
+}
+
+// announceBGP adds the desired networks to bgpd and removes those it added
+// before that are no longer desired
+func (a *Agent) announceBGP(desired map[string]bool) error {
+	var commands []string
+	for network := range desired {
+		if !a.bgpAnnounced[network] {
+			commands = append(commands, "network "+network)
+		}
+	}
+	for network := range a.bgpAnnounced {
+		if !desired[network] {
+			commands = append(commands, "no network "+network)
+		}
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+	sort.Strings(commands)
+
+	if err := a.configureBGP(commands); err != nil {
+		return err
+	}
+	a.bgpAnnounced = desired
+	log.Printf("Updated BGP networks: %s", strings.Join(commands, ", "))
+	return nil
+}
+
+// configureBGP runs configuration commands in the IPv4 unicast address
+// family of the router bgp
+func (a *Agent) configureBGP(commands []string) error {
+	args := []string{
+		"-c", "configure terminal",
+		"-c", fmt.Sprintf("router bgp %d", a.config.BGP.ASN),
+		"-c", "address-family ipv4 unicast",
+	}
+	for _, command := range commands {
+		args = append(args, "-c", command)
+	}
+	if out, err := privCommand("vtysh", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("vtysh: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// learnBGP returns the prefixes bgpd learned from its neighbors that lie
+// within bgp.learn, sorted. Networks the gateway announces or has
+// configured as sites are left out.
+func (a *Agent) learnBGP() ([]string, error) {
+	out, err := privCommand("vtysh", "-c", "show bgp ipv4 unicast json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("vtysh: %w", err)
+	}
+	var table struct {
+		Routes map[string][]bgpPath `json:"routes"`
+	}
+	if err := json.Unmarshal(out, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse the BGP table: %w", err)
+	}
+
+	var within []netip.Prefix
+	for _, cidr := range a.config.BGP.Learn {
+		// Prefixes are validated when the configuration is loaded
+		if prefix, err := netip.ParsePrefix(cidr); err == nil {
+			within = append(within, prefix.Masked())
+		}
+	}
+
+	var learned []string
+	for network, paths := range table.Routes {
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil || !prefix.Addr().Is4() {
+			continue
+		}
+		site := prefix.Masked().String()
+		if a.bgpAnnounced[site] || slices.Contains(a.config.Sites, site) {
+			continue
+		}
+		if !slices.ContainsFunc(within, func(p netip.Prefix) bool {
+			return p.Bits() <= prefix.Bits() && p.Contains(prefix.Addr())
+		}) {
+			continue
+		}
+		if slices.ContainsFunc(paths, func(p bgpPath) bool {
+			return p.Valid && p.Bestpath && p.PeerID != "(unspec)"
+		}) {
+			learned = append(learned, site)
+		}
+	}
+	sort.Strings(learned)
+	return learned, nil
+}
+
+// withdrawBGP removes the networks the agent added to bgpd
+func (a *Agent) withdrawBGP() {
+	if len(a.bgpAnnounced) == 0 {
+		return
+	}
+	if err := a.announceBGP(nil); err != nil {
+		log.Printf("Warning: failed to withdraw BGP networks: %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package agent
+
+import "log"
+
+// bgpLoop is only supported on Linux
+func (a *Agent) bgpLoop() error {
+	log.Println("bgp is only supported on Linux, ignoring")
+	return nil
+}
+
+// withdrawBGP is a no-op on this platform
+func (a *Agent) withdrawBGP() {}
@@ -0,0 +1,249 @@
+package agent
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+const (
+	// captiveProbeURL returns a fixed body unless a captive portal intercepts it
+	captiveProbeURL      = "http://captive.apple.com/hotspot-detect.html"
+	captiveProbeExpected = "Success"
+	captiveProbeTimeout  = 5 * time.Second
+
+	// captiveResumeInterval is how often server connectivity is checked while paused
+	captiveResumeInterval = 10 * time.Second
+)
+
+// detectCaptivePortal probes a well-known URL over plain HTTP using dialer
+// for both the lookup and the connection. Redirects or an unexpected body
+// mean the network intercepts traffic.
+func detectCaptivePortal(ctx context.Context, dialer *net.Dialer) (bool, error) {
+	client := &http.Client{
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		Timeout:   captiveProbeTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, captiveProbeURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return true, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return false, err
+	}
+
+	return !strings.Contains(string(body), captiveProbeExpected), nil
+}
+
+// captivePortalLoop pauses the kill switch while the agent sits behind a
+// captive portal and resumes it once the server is reachable again
+func (a *Agent) captivePortalLoop() {
+	ticker := time.NewTicker(captiveResumeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-a.captiveCheck:
+			a.checkCaptivePortal()
+		case <-ticker.C:
+			a.routesMu.Lock()
+			paused := a.killSwitchPaused
+			a.routesMu.Unlock()
+			// A user disconnect turned it off on purpose
+			if paused && !a.paused.Load() && a.serverReachable() {
+				a.resumeKillSwitch("Server reachable again")
+			}
+		}
+	}
+}
+
+// triggerCaptiveCheck requests a captive portal probe without blocking
+func (a *Agent) triggerCaptiveCheck() {
+	if a.captiveCheck == nil {
+		return
+	}
+	select {
+	case a.captiveCheck <- struct{}{}:
+	default:
+	}
+}
+
+// checkCaptivePortal probes for a captive portal and pauses the kill switch
+func (a *Agent) checkCaptivePortal() {
+	a.routesMu.Lock()
+	paused := a.killSwitchPaused
+	a.routesMu.Unlock()
+	if paused {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, captiveProbeTimeout)
+	defer cancel()
+
+	dialer := a.probeDialer()
+	detected, err := detectCaptivePortal(ctx, dialer)
+	if err != nil {
+		// The kill switch itself blocks the probe, and so does a portal
+		// that intercepts DNS. Without the server either, assume a portal
+		// and probe again with the kill switch out of the way.
+		if a.serverReachable() {
+			log.Printf("Captive portal probe failed: %v", err)
+			return
+		}
+		log.Printf("Captive portal probe failed while the server is unreachable, retrying with the kill switch paused: %v", err)
+		if !a.pauseKillSwitch() {
+			return
+		}
+
+		retryCtx, retryCancel := context.WithTimeout(a.ctx, captiveProbeTimeout)
+		defer retryCancel()
+		detected, err = detectCaptivePortal(retryCtx, dialer)
+		if err == nil && !detected {
+			a.resumeKillSwitch("No captive portal found")
+			return
+		}
+		if err != nil {
+			// Possibly a portal; the kill switch resumes with the server
+			log.Printf("Captive portal probe failed again: %v", err)
+		}
+
+		log.Println("Possible captive portal, kill switch paused until the server is reachable")
+		a.events.publish(Event{Type: EventCaptivePortal, Message: "kill switch paused"})
+		return
+	}
+	if !detected {
+		return
+	}
+
+	if !a.pauseKillSwitch() {
+		return
+	}
+	log.Println("Captive portal detected, kill switch paused until the server is reachable")
+	a.events.publish(Event{Type: EventCaptivePortal, Message: "kill switch paused"})
+}
+
+// pauseKillSwitch removes the kill switch rules for a captive portal
+func (a *Agent) pauseKillSwitch() bool {
+	a.routesMu.Lock()
+	defer a.routesMu.Unlock()
+
+	if err := a.killSwitch.Disable(); err != nil {
+		err = errcode.Wrap(errcode.KillSwitchFailed, err)
+		log.Printf("Failed to pause kill switch: %v", err)
+		a.emitError("failed to pause kill switch", err)
+		return false
+	}
+	a.killSwitchPaused = true
+	return true
+}
+
+// resumeKillSwitch re-enables the kill switch after a captive portal pause
+func (a *Agent) resumeKillSwitch(reason string) {
+	a.routesMu.Lock()
+	defer a.routesMu.Unlock()
+
+	a.killSwitchPaused = false
+	if err := a.updateKillSwitch(); err != nil {
+		err = errcode.Wrap(errcode.KillSwitchFailed, err)
+		log.Printf("Failed to resume kill switch: %v", err)
+		a.emitError("failed to resume kill switch", err)
+		return
+	}
+
+	log.Printf("%s, kill switch resumed", reason)
+	a.events.publish(Event{Type: EventPortalCleared, Message: "kill switch resumed"})
+}
+
+// probeDialer returns a dialer for the captive portal probe that bypasses
+// the tunnel. It is bound to the physical interface carrying the server
+// traffic, so the probe and its DNS lookup leave outside the overlay even
+// in full-tunnel mode; on Linux it also carries the mark the kill switch
+// lets through.
+func (a *Agent) probeDialer() *net.Dialer {
+	dialer := &net.Dialer{Timeout: captiveProbeTimeout}
+
+	iface := a.physicalInterface()
+	if iface == nil {
+		log.Println("Warning: physical interface not found, captive portal probe may use the tunnel")
+	}
+	dialer.Control = probeControl(iface)
+	dialer.Resolver = &net.Resolver{PreferGo: true, Dial: dialer.DialContext}
+	return dialer
+}
+
+// physicalInterface returns the interface the kernel picks to reach the
+// server, or nil if that is the tunnel or cannot be determined
+func (a *Agent) physicalInterface() *net.Interface {
+	servers, err := a.serverEndpoints()
+	if err != nil {
+		return nil
+	}
+
+	for _, server := range servers {
+		// Connecting a UDP socket selects a route without sending anything
+		conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: server.IP, Port: server.Port})
+		if err != nil {
+			continue
+		}
+		local := conn.LocalAddr().(*net.UDPAddr).IP
+		conn.Close()
+
+		ifaces, err := net.Interfaces()
+		if err != nil {
+			return nil
+		}
+		for i := range ifaces {
+			if ifaces[i].Name == a.tun.Name() {
+				continue
+			}
+			addrs, err := ifaces[i].Addrs()
+			if err != nil {
+				continue
+			}
+			for _, addr := range addrs {
+				if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.Equal(local) {
+					return &ifaces[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// serverReachable checks connectivity to the server with a unary call
+func (a *Agent) serverReachable() bool {
+	ctx, cancel := context.WithTimeout(a.ctx, captiveProbeTimeout)
+	defer cancel()
+
+	client, sessionID := a.current()
+	_, err := client.GetRoutes(ctx, &proto.RouteRequest{
+		SessionId: sessionID,
+		AgentId:   a.agentID,
+	})
+	return err == nil
+}
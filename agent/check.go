@@ -0,0 +1,247 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+)
+
+// CheckStatus is the outcome of a self-test check
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+	CheckSkip CheckStatus = "skip" // a check it depends on failed, or nothing to check
+)
+
+// CheckResult is the result of a self-test check, with a hint on what to
+// do about a failure
+type CheckResult struct {
+	Name   string      `json:"name"`
+	Status CheckStatus `json:"status"`
+	Detail string      `json:"detail"`
+	Hint   string      `json:"hint,omitempty"`
+}
+
+// checkTimeout bounds each network check
+const checkTimeout = 10 * time.Second
+
+// Addresses of the TUN and route the permission checks add and remove, in
+// the benchmarking range nothing routes
+const (
+	checkTUNAddr  = "198.18.255.1"
+	checkTUNPeer  = "198.18.255.2"
+	checkTUNMask  = "255.255.255.252"
+	checkRouteDst = "198.19.255.0/24"
+)
+
+// overlayIPFileName is the file beside the traffic history the overlay IP
+// of the last session is kept in, for SelfCheck
+const overlayIPFileName = "overlay-ip"
+
+// SelfCheck verifies what the agent needs to run with cfg: resolving,
+// reaching over QUIC and trusting each server, creating a TUN and routes,
+// and an overlay IP no local interface has. The TUN and route it creates
+// are removed again. running is the status of an agent running with cfg,
+// nil if none is.
+func SelfCheck(ctx context.Context, cfg *config.AgentConfig, running *ConnectionStatus) []CheckResult {
+	var results []CheckResult
+	for _, server := range cfg.ServerList() {
+		results = append(results, checkServer(ctx, cfg, server)...)
+	}
+	results = append(results, checkTUNAndRoutes()...)
+	results = append(results, checkOverlayIP(cfg, running))
+	return results
+}
+
+// checkServer resolves a server and completes a QUIC handshake with it
+func checkServer(ctx context.Context, cfg *config.AgentConfig, server string) []CheckResult {
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		return []CheckResult{{Name: "server " + server, Status: CheckFail, Detail: err.Error(), Hint: "servers are host:port"}}
+	}
+
+	resolve := CheckResult{Name: "resolve " + host}
+	rctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	addrs, err := net.DefaultResolver.LookupHost(rctx, host)
+	cancel()
+	if err != nil {
+		resolve.Status, resolve.Detail = CheckFail, err.Error()
+		resolve.Hint = "check the DNS resolvers of this host, or use the server's IP address"
+		return []CheckResult{resolve, {Name: "QUIC " + server, Status: CheckSkip, Detail: "server not resolved"}}
+	}
+	resolve.Status, resolve.Detail = CheckOK, strings.Join(addrs, ", ")
+
+	handshake := CheckResult{Name: "QUIC " + server}
+	tlsConfig, err := crypto.LoadClientTLSConfigWithCA(host, cfg.CAFile, cfg.InsecureSkipVerify)
+	if err != nil {
+		handshake.Status, handshake.Detail = CheckFail, err.Error()
+		handshake.Hint = "fix ca_file"
+		return []CheckResult{resolve, handshake}
+	}
+	dctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	conn, err := crypto.NewQUICDialer(tlsConfig).DialContext(dctx, server)
+	cancel()
+	switch {
+	case err == nil:
+		conn.Close()
+		handshake.Status, handshake.Detail = CheckOK, "handshake completed, certificate trusted"
+		if cfg.InsecureSkipVerify {
+			handshake.Status, handshake.Detail = CheckWarn, "handshake completed, certificate not verified"
+			handshake.Hint = "unset insecure_skip_verify and trust the server CA with ca_file"
+		}
+	case strings.Contains(err.Error(), "certificate") || strings.Contains(err.Error(), "x509"):
+		handshake.Status, handshake.Detail = CheckFail, err.Error()
+		handshake.Hint = fmt.Sprintf("for a private CA run \"easyanylink-agent trust -server %s -fingerprint <sha256>\" and set ca_file", server)
+	default:
+		handshake.Status, handshake.Detail = CheckFail, err.Error()
+		handshake.Hint = fmt.Sprintf("UDP port %s may be blocked on the way to the server; set tcp_port to fall back to TCP", port)
+		if cfg.TCPPort != 0 {
+			handshake.Status = CheckWarn
+			handshake.Hint = fmt.Sprintf("UDP port %s may be blocked on the way to the server, the agent falls back to TCP port %d", port, cfg.TCPPort)
+		}
+	}
+	return []CheckResult{resolve, handshake}
+}
+
+// checkTUNAndRoutes creates a TUN and adds and removes a route through it
+func checkTUNAndRoutes() []CheckResult {
+	const privilegeHint = "run the agent as root (sudo); with user set it keeps a root helper for this"
+
+	tunCheck := CheckResult{Name: "create TUN"}
+	tun, err := NewTUNInterface(checkTUNName(), defaultMTU, false)
+	if err != nil {
+		tunCheck.Status, tunCheck.Detail, tunCheck.Hint = CheckFail, err.Error(), privilegeHint
+		if runtime.GOOS == "windows" {
+			tunCheck.Hint = "run as Administrator with wintun.dll next to the agent"
+		}
+		return []CheckResult{tunCheck, {Name: "add route", Status: CheckSkip, Detail: "no TUN to route through"}}
+	}
+	defer tun.Close()
+	tunCheck.Status, tunCheck.Detail = CheckOK, "created "+tun.Name()
+
+	route := CheckResult{Name: "add route"}
+	err = tun.SetIP(checkTUNAddr, checkTUNPeer, checkTUNMask)
+	if err == nil {
+		err = tun.Up()
+	}
+	if err != nil {
+		route.Status, route.Detail, route.Hint = CheckFail, err.Error(), privilegeHint
+		return []CheckResult{tunCheck, route}
+	}
+	rm := NewRouteManager()
+	if err := rm.AddRoute(checkRouteDst, "", tun.Name()); err != nil {
+		route.Status, route.Detail, route.Hint = CheckFail, err.Error(), privilegeHint
+		return []CheckResult{tunCheck, route}
+	}
+	if err := rm.DeleteRoute(checkRouteDst); err != nil {
+		route.Status, route.Detail = CheckWarn, fmt.Sprintf("added %s but failed to remove it: %v", checkRouteDst, err)
+		return []CheckResult{tunCheck, route}
+	}
+	route.Status, route.Detail = CheckOK, "added and removed "+checkRouteDst
+	return []CheckResult{tunCheck, route}
+}
+
+// checkTUNName names the TUN of the permission check apart from the
+// agent's own
+func checkTUNName() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "" // the next free utun unit
+	case "windows":
+		return "EasyAnyLink Check"
+	}
+	return "ealcheck0"
+}
+
+// checkOverlayIP checks that no local interface other than the running
+// agent's TUN has the overlay IP of the last session, and that no local
+// network contains it
+func checkOverlayIP(cfg *config.AgentConfig, running *ConnectionStatus) CheckResult {
+	result := CheckResult{Name: "overlay IP"}
+	data, err := os.ReadFile(overlayIPFile(cfg))
+	if errors.Is(err, os.ErrNotExist) {
+		result.Status, result.Detail = CheckSkip, "no session recorded yet"
+		return result
+	}
+	ip, perr := netip.ParseAddr(strings.TrimSpace(string(data)))
+	if err != nil || perr != nil {
+		result.Status, result.Detail = CheckSkip, "failed to read the overlay IP of the last session"
+		return result
+	}
+	ours := running != nil && running.Connected && running.AssignedIP == ip.String()
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		result.Status, result.Detail = CheckSkip, err.Error()
+		return result
+	}
+	for _, iface := range ifaces {
+		addrs, _ := iface.Addrs()
+		holds := false
+		var prefixes []netip.Prefix
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			local, _ := netip.AddrFromSlice(ipNet.IP)
+			ones, _ := ipNet.Mask.Size()
+			holds = holds || local.Unmap() == ip
+			prefixes = append(prefixes, netip.PrefixFrom(local.Unmap(), ones).Masked())
+		}
+		if holds && ours {
+			continue // the TUN of the running agent
+		}
+		if holds {
+			result.Status, result.Detail = CheckFail, fmt.Sprintf("%s is already assigned to %s", ip, iface.Name)
+			result.Hint = "remove the address from the interface, or have an admin assign the agent another IP"
+			return result
+		}
+		for _, prefix := range prefixes {
+			if prefix.Bits() > 0 && prefix.Contains(ip) {
+				result.Status, result.Detail = CheckWarn, fmt.Sprintf("%s lies in %s of %s, traffic to the overlay may stay local", ip, prefix, iface.Name)
+				result.Hint = "move the overlay_cidr of the server or the local network apart"
+				return result
+			}
+		}
+	}
+	result.Status, result.Detail = CheckOK, ip.String()+" is free"
+	if ours {
+		result.Detail = ip.String() + " is in use by the running agent"
+	}
+	return result
+}
+
+// overlayIPFile is the path of the file keeping the overlay IP of the
+// last session
+func overlayIPFile(cfg *config.AgentConfig) string {
+	statsFile := cfg.StatsFile
+	if statsFile == "" {
+		statsFile = DefaultStatsFile
+	}
+	return filepath.Join(filepath.Dir(statsFile), overlayIPFileName)
+}
+
+// recordOverlayIP keeps the overlay IP of a new session for SelfCheck,
+// best effort
+func (a *Agent) recordOverlayIP(ip string) {
+	path := overlayIPFile(a.config)
+	if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) == ip {
+		return
+	}
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(ip+"\n"), 0644)
+}
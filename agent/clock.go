@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"log"
+	"time"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// clockSkewWarning is how far the local clock may be off the server's
+// before the agent logs it, the server default of clock_skew_warning
+const clockSkewWarning = 30 * time.Second
+
+// measureClock records the skew of the local clock against the server
+// clock from a response sent at server time and taking the round trip
+// from sent to received. Half the round trip is assumed per direction.
+func (a *Agent) measureClock(sent, received, server time.Time) time.Duration {
+	skew := sent.Add(received.Sub(sent) / 2).Sub(server)
+	previous := time.Duration(a.clockSkew.Swap(int64(skew)))
+	if outside(skew, clockSkewWarning) && !outside(previous, clockSkewWarning) {
+		log.Printf("Warning: local clock is %s the server clock", describeSkew(skew))
+	}
+	return skew
+}
+
+// serverNow returns the current time of the server clock as last
+// measured. Certificates are checked against it, so that a local clock
+// that is off does not fail the handshake.
+func (a *Agent) serverNow() time.Time {
+	return time.Now().Add(-time.Duration(a.clockSkew.Load()))
+}
+
+// clockRejected reports whether the server refused a registration for its
+// timestamp, measuring the skew from the server time it returned
+func (a *Agent) clockRejected(err error, sent, received time.Time) bool {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return false
+	}
+	for _, detail := range st.Details() {
+		if ts, ok := detail.(*timestamppb.Timestamp); ok {
+			a.measureClock(sent, received, ts.AsTime())
+			return true
+		}
+	}
+	return false
+}
+
+// outside reports whether skew is off by more than limit either way
+func outside(skew, limit time.Duration) bool {
+	return skew > limit || skew < -limit
+}
+
+// describeSkew tells how far and which way the local clock is off
+func describeSkew(skew time.Duration) string {
+	if skew < 0 {
+		return (-skew).Round(time.Millisecond).String() + " behind"
+	}
+	return skew.Round(time.Millisecond).String() + " ahead of"
+}
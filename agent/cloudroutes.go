@@ -0,0 +1,196 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// cloudRouter programs the routes of a VPC route table pointing at the
+// instance the gateway runs on
+type cloudRouter interface {
+	// setRoute points destination at the instance, replacing another route
+	setRoute(ctx context.Context, destination string) error
+	// deleteRoute removes the route to destination, if any
+	deleteRoute(ctx context.Context, destination string) error
+}
+
+// cloudRequestTimeout bounds a request to a metadata service or cloud API
+const cloudRequestTimeout = 15 * time.Second
+
+// metadataClient reaches the link-local metadata service, never through a
+// proxy
+var metadataClient = &http.Client{
+	Timeout:   cloudRequestTimeout,
+	Transport: &http.Transport{Proxy: nil},
+}
+
+// cloudClient calls the cloud APIs
+var cloudClient = &http.Client{Timeout: cloudRequestTimeout}
+
+// cloudError is an error response of a metadata service or cloud API
+type cloudError struct {
+	status int
+	body   string
+}
+
+func (e *cloudError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.status, e.body)
+}
+
+// hasStatus reports whether err is a cloud error response with status
+func hasStatus(err error, status int) bool {
+	var ce *cloudError
+	return errors.As(err, &ce) && ce.status == status
+}
+
+// cloudDo sends a request and returns the body of a successful response
+func cloudDo(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &cloudError{status: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	return body, nil
+}
+
+// metadataGet reads a value of the metadata service
+func metadataGet(ctx context.Context, url string, header http.Header) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	body, err := cloudDo(metadataClient, req)
+	if err != nil {
+		return "", fmt.Errorf("instance metadata %s: %w", url, err)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// cloudToken is an access token of the instance with its expiry
+type cloudToken struct {
+	value   string
+	expires time.Time
+}
+
+// valid reports whether the token can still be used for a request
+func (t *cloudToken) valid() bool {
+	return t.value != "" && time.Until(t.expires) > time.Minute
+}
+
+// cloudRouteName names the route of a gateway to destination where the
+// provider names routes, e.g. easyanylink-1a2b3c4d-10-200-0-0-16, unique
+// per agent so gateways sharing a network keep apart
+func cloudRouteName(agentID, destination string) string {
+	return fmt.Sprintf("easyanylink-%08x-%s", crc32.ChecksumIEEE([]byte(agentID)),
+		strings.NewReplacer(".", "-", "/", "-").Replace(destination))
+}
+
+// newCloudRouter reads the instance from the metadata service of the
+// configured provider
+func (a *Agent) newCloudRouter(ctx context.Context) (cloudRouter, error) {
+	cfg := a.config.CloudRoutes
+	switch cfg.Provider {
+	case "aws":
+		return newAWSRouter(ctx, cfg.RouteTable)
+	case "gcp":
+		return newGCPRouter(ctx, cfg.RouteTable, a.agentID)
+	case "azure":
+		return newAzureRouter(ctx, cfg.RouteTable, a.agentID)
+	}
+	return nil, fmt.Errorf("unknown cloud provider %q", cfg.Provider)
+}
+
+// cloudRoutesLoop points the routes of the VPC route table at the instance
+// every cloud_routes.interval until the agent stops. Failing to read the
+// instance fails the subsystem, which is restarted; failing to change a
+// route is retried with the next sync.
+func (a *Agent) cloudRoutesLoop() error {
+	ctx, cancel := context.WithTimeout(a.ctx, cloudRequestTimeout)
+	router, err := a.newCloudRouter(ctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to read the cloud instance: %w", err)
+	}
+	a.cloudRouter = router
+	if a.cloudRoutes == nil {
+		a.cloudRoutes = make(map[string]bool)
+	}
+	log.Printf("Programming %s route table %s", a.config.CloudRoutes.Provider, a.config.CloudRoutes.RouteTable)
+
+	ticker := time.NewTicker(time.Duration(a.config.CloudRoutes.Interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		if err := a.syncCloudRoutes(a.ctx); err != nil {
+			log.Printf("Failed to sync cloud routes: %v", err)
+		}
+		select {
+		case <-a.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncCloudRoutes points the networks reached through the tunnel at the
+// instance and removes the routes it added for networks no longer reached
+func (a *Agent) syncCloudRoutes(ctx context.Context) error {
+	desired := a.tunnelNetworks()
+	var errs []error
+	for destination := range desired {
+		if a.cloudRoutes[destination] {
+			continue
+		}
+		if err := a.cloudRouter.setRoute(ctx, destination); err != nil {
+			errs = append(errs, fmt.Errorf("route %s: %w", destination, err))
+			continue
+		}
+		a.cloudRoutes[destination] = true
+		log.Printf("Added cloud route %s via this instance", destination)
+	}
+	for destination := range a.cloudRoutes {
+		if desired[destination] {
+			continue
+		}
+		if err := a.cloudRouter.deleteRoute(ctx, destination); err != nil {
+			errs = append(errs, fmt.Errorf("route %s: %w", destination, err))
+			continue
+		}
+		delete(a.cloudRoutes, destination)
+		log.Printf("Removed cloud route %s", destination)
+	}
+	return errors.Join(errs...)
+}
+
+// withdrawCloudRoutes removes the routes the agent added, so the VPC does
+// not send traffic to a stopped gateway
+func (a *Agent) withdrawCloudRoutes() {
+	if len(a.cloudRoutes) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*cloudRequestTimeout)
+	defer cancel()
+	for destination := range a.cloudRoutes {
+		if err := a.cloudRouter.deleteRoute(ctx, destination); err != nil {
+			log.Printf("Warning: failed to remove cloud route %s: %v", destination, err)
+			continue
+		}
+		delete(a.cloudRoutes, destination)
+	}
+}
@@ -0,0 +1,193 @@
+package agent
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// awsMetadata is the base URL of the EC2 instance metadata service
+const awsMetadata = "http://169.254.169.254/latest/"
+
+// awsRouter programs an EC2 route table with the credentials of the
+// instance role, signing EC2 API requests with Signature Version 4
+type awsRouter struct {
+	routeTable string
+	instanceID string
+	region     string
+
+	mu    sync.Mutex
+	creds awsCredentials
+}
+
+// awsCredentials are the temporary credentials of the instance role
+type awsCredentials struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	Token           string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+func newAWSRouter(ctx context.Context, routeTable string) (*awsRouter, error) {
+	r := &awsRouter{routeTable: routeTable}
+	header, err := awsMetadataHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.instanceID, err = metadataGet(ctx, awsMetadata+"meta-data/instance-id", header); err != nil {
+		return nil, err
+	}
+	if r.region, err = metadataGet(ctx, awsMetadata+"meta-data/placement/region", header); err != nil {
+		return nil, err
+	}
+
+	// The instance forwards packets of other hosts only without the check
+	err = r.call(ctx, url.Values{
+		"Action":                []string{"ModifyInstanceAttribute"},
+		"InstanceId":            []string{r.instanceID},
+		"SourceDestCheck.Value": []string{"false"},
+	})
+	if err != nil {
+		log.Printf("Warning: failed to disable the source/destination check of %s: %v", r.instanceID, err)
+	}
+	return r, nil
+}
+
+// awsMetadataHeader returns the header with an IMDSv2 session token
+func awsMetadataHeader(ctx context.Context) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, awsMetadata+"api/token", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "300")
+	token, err := cloudDo(metadataClient, req)
+	if err != nil {
+		return nil, fmt.Errorf("instance metadata token: %w", err)
+	}
+	return http.Header{"X-Aws-Ec2-Metadata-Token": []string{string(token)}}, nil
+}
+
+// credentials returns the credentials of the instance role, refreshed
+// before they expire
+func (r *awsRouter) credentials(ctx context.Context) (awsCredentials, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.creds.AccessKeyID != "" && time.Until(r.creds.Expiration) > 5*time.Minute {
+		return r.creds, nil
+	}
+
+	header, err := awsMetadataHeader(ctx)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	base := awsMetadata + "meta-data/iam/security-credentials/"
+	role, err := metadataGet(ctx, base, header)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	// The first line names the role of the instance profile
+	role, _, _ = strings.Cut(role, "\n")
+	data, err := metadataGet(ctx, base+role, header)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	var creds awsCredentials
+	if err := json.Unmarshal([]byte(data), &creds); err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to parse the credentials of role %s: %w", role, err)
+	}
+	r.creds = creds
+	return creds, nil
+}
+
+func (r *awsRouter) setRoute(ctx context.Context, destination string) error {
+	params := url.Values{
+		"RouteTableId":         []string{r.routeTable},
+		"DestinationCidrBlock": []string{destination},
+		"InstanceId":           []string{r.instanceID},
+	}
+	params.Set("Action", "CreateRoute")
+	err := r.call(ctx, params)
+	if err != nil && strings.Contains(err.Error(), "RouteAlreadyExists") {
+		params.Set("Action", "ReplaceRoute")
+		err = r.call(ctx, params)
+	}
+	return err
+}
+
+func (r *awsRouter) deleteRoute(ctx context.Context, destination string) error {
+	err := r.call(ctx, url.Values{
+		"Action":               []string{"DeleteRoute"},
+		"RouteTableId":         []string{r.routeTable},
+		"DestinationCidrBlock": []string{destination},
+	})
+	if err != nil && strings.Contains(err.Error(), "InvalidRoute.NotFound") {
+		return nil
+	}
+	return err
+}
+
+// call sends an EC2 Query API request
+func (r *awsRouter) call(ctx context.Context, params url.Values) error {
+	creds, err := r.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	params.Set("Version", "2016-11-15")
+	body := params.Encode()
+	host := "ec2." + r.region + ".amazonaws.com"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+host+"/", strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	const contentType = "application/x-www-form-urlencoded; charset=utf-8"
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Security-Token", creds.Token)
+
+	// Signature Version 4 over the headers set above
+	const signedHeaders = "content-type;host;x-amz-date;x-amz-security-token"
+	canonical := strings.Join([]string{
+		http.MethodPost, "/", "",
+		"content-type:" + contentType,
+		"host:" + host,
+		"x-amz-date:" + amzDate,
+		"x-amz-security-token:" + creds.Token,
+		"",
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+	scope := now.Format("20060102") + "/" + r.region + "/ec2/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex(canonical)
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, part := range []string{now.Format("20060102"), r.region, "ec2", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, toSign))))
+
+	_, err = cloudDo(cloudClient, req)
+	return err
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
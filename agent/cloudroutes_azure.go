@@ -0,0 +1,109 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// azureMetadata is the base URL of the Azure instance metadata service
+const azureMetadata = "http://169.254.169.254/metadata/"
+
+// azureNetworkAPI is the API version of the route requests
+const azureNetworkAPI = "2023-04-01"
+
+// azureRouter programs a route table with the managed identity of the VM,
+// sending traffic to its private IP as a virtual appliance
+type azureRouter struct {
+	routeTable string // resource ID of the route table
+	privateIP  string
+	agentID    string
+
+	mu    sync.Mutex
+	token cloudToken
+}
+
+var azureMetadataHeader = http.Header{"Metadata": []string{"true"}}
+
+func newAzureRouter(ctx context.Context, routeTable, agentID string) (*azureRouter, error) {
+	ip, err := metadataGet(ctx, azureMetadata+"instance/network/interface/0/ipv4/ipAddress/0/privateIpAddress?api-version=2021-02-01&format=text", azureMetadataHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &azureRouter{routeTable: strings.TrimSuffix(routeTable, "/"), privateIP: ip, agentID: agentID}, nil
+}
+
+// accessToken returns the token of the managed identity, refreshed before
+// it expires
+func (r *azureRouter) accessToken(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token.valid() {
+		return r.token.value, nil
+	}
+	data, err := metadataGet(ctx, azureMetadata+"identity/oauth2/token?api-version=2018-02-01&resource="+
+		url.QueryEscape("https://management.azure.com/"), azureMetadataHeader)
+	if err != nil {
+		return "", err
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"` // Unix seconds
+	}
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		return "", fmt.Errorf("failed to parse the managed identity token: %w", err)
+	}
+	expires, _ := strconv.ParseInt(token.ExpiresOn, 10, 64)
+	r.token = cloudToken{value: token.AccessToken, expires: time.Unix(expires, 0)}
+	return r.token.value, nil
+}
+
+func (r *azureRouter) setRoute(ctx context.Context, destination string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"properties": map[string]string{
+			"addressPrefix":    destination,
+			"nextHopType":      "VirtualAppliance",
+			"nextHopIpAddress": r.privateIP,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	// PUT creates the route or updates one of a previous run
+	return r.call(ctx, http.MethodPut, destination, body)
+}
+
+func (r *azureRouter) deleteRoute(ctx context.Context, destination string) error {
+	err := r.call(ctx, http.MethodDelete, destination, nil)
+	if hasStatus(err, http.StatusNotFound) {
+		return nil
+	}
+	return err
+}
+
+// call sends a request for the route of the gateway to destination
+func (r *azureRouter) call(ctx context.Context, method, destination string, body []byte) error {
+	token, err := r.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("https://management.azure.com%s/routes/%s?api-version=%s",
+		r.routeTable, cloudRouteName(r.agentID, destination), azureNetworkAPI)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	_, err = cloudDo(cloudClient, req)
+	return err
+}
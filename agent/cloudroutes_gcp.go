@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+)
+
+// gcpMetadata is the base URL of the GCE metadata server
+const gcpMetadata = "http://metadata.google.internal/computeMetadata/v1/"
+
+// gcpRoutePriority is the priority of the routes, the default of gcloud
+const gcpRoutePriority = 1000
+
+// gcpRouter adds routes of a VPC network with the next hop instance being
+// the gateway, using the token of the instance's service account. GCE
+// routes cannot be changed, a route of the gateway is named after it and
+// the destination.
+type gcpRouter struct {
+	network  string
+	project  string
+	instance string // partial URL of the instance
+	agentID  string
+
+	mu    sync.Mutex
+	token cloudToken
+}
+
+var gcpMetadataHeader = http.Header{"Metadata-Flavor": []string{"Google"}}
+
+func newGCPRouter(ctx context.Context, network, agentID string) (*gcpRouter, error) {
+	project, err := metadataGet(ctx, gcpMetadata+"project/project-id", gcpMetadataHeader)
+	if err != nil {
+		return nil, err
+	}
+	zone, err := metadataGet(ctx, gcpMetadata+"instance/zone", gcpMetadataHeader)
+	if err != nil {
+		return nil, err
+	}
+	name, err := metadataGet(ctx, gcpMetadata+"instance/name", gcpMetadataHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &gcpRouter{
+		network:  network,
+		project:  project,
+		instance: fmt.Sprintf("projects/%s/zones/%s/instances/%s", project, path.Base(zone), name),
+		agentID:  agentID,
+	}, nil
+}
+
+// accessToken returns the token of the instance's service account,
+// refreshed before it expires
+func (r *gcpRouter) accessToken(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token.valid() {
+		return r.token.value, nil
+	}
+	data, err := metadataGet(ctx, gcpMetadata+"instance/service-accounts/default/token", gcpMetadataHeader)
+	if err != nil {
+		return "", err
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		return "", fmt.Errorf("failed to parse the service account token: %w", err)
+	}
+	r.token = cloudToken{value: token.AccessToken, expires: time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)}
+	return r.token.value, nil
+}
+
+func (r *gcpRouter) setRoute(ctx context.Context, destination string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"name":            cloudRouteName(r.agentID, destination),
+		"network":         fmt.Sprintf("projects/%s/global/networks/%s", r.project, r.network),
+		"destRange":       destination,
+		"priority":        gcpRoutePriority,
+		"nextHopInstance": r.instance,
+		"description":     "EasyAnyLink gateway " + r.agentID,
+	})
+	if err != nil {
+		return err
+	}
+	err = r.call(ctx, http.MethodPost, "", body)
+	if hasStatus(err, http.StatusConflict) {
+		return nil // added by a previous run of the agent
+	}
+	return err
+}
+
+func (r *gcpRouter) deleteRoute(ctx context.Context, destination string) error {
+	err := r.call(ctx, http.MethodDelete, "/"+cloudRouteName(r.agentID, destination), nil)
+	if hasStatus(err, http.StatusNotFound) {
+		return nil
+	}
+	return err
+}
+
+// call sends a request to the routes collection of the project
+func (r *gcpRouter) call(ctx context.Context, method, suffix string, body []byte) error {
+	token, err := r.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("https://compute.googleapis.com/compute/v1/projects/%s/global/routes%s", r.project, suffix)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	_, err = cloudDo(cloudClient, req)
+	return err
+}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+	"net"
+)
+
+// Route conflict policies
+const (
+	ConflictRefuse   = "refuse"   // fail with an error
+	ConflictSkip     = "skip"     // log and do not install the route
+	ConflictOverride = "override" // log and install the route anyway
+)
+
+// findRouteConflict returns an existing system route overlapping
+// destination that was not installed by the agent. Default routes, host
+// routes and routes through the tunnel itself do not count as conflicts.
+func (a *Agent) findRouteConflict(destination string) (*systemRoute, error) {
+	_, dst, err := net.ParseCIDR(destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination %s: %w", destination, err)
+	}
+
+	routes, err := a.routeManager.SystemRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range routes {
+		route := &routes[i]
+		ones, _ := route.destination.Mask.Size()
+		if ones == 0 || ones == 32 || route.iface == a.tun.Name() {
+			continue
+		}
+		if ip := route.destination.IP; ip.IsLoopback() || ip.IsMulticast() || ip.IsLinkLocalUnicast() {
+			continue
+		}
+		if route.destination.Contains(dst.IP) || dst.Contains(route.destination.IP) {
+			return route, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// checkRouteConflict applies the configured conflict policy to a forward
+// route. It reports whether the route should be installed.
+func (a *Agent) checkRouteConflict(destination string) (bool, error) {
+	conflict, err := a.findRouteConflict(destination)
+	if err != nil {
+		// Conflict detection is best effort, do not block routing on it
+		log.Printf("Warning: failed to check route conflicts for %s: %v", destination, err)
+		return true, nil
+	}
+	if conflict == nil {
+		return true, nil
+	}
+
+	switch a.config.RouteConflicts {
+	case ConflictRefuse:
+		return false, fmt.Errorf("route %s conflicts with existing route %s via %s",
+			destination, conflict.destination, conflict.iface)
+	case ConflictOverride:
+		log.Printf("Warning: route %s overrides existing route %s via %s",
+			destination, conflict.destination, conflict.iface)
+		return true, nil
+	default:
+		log.Printf("Skipping route %s: conflicts with existing route %s via %s",
+			destination, conflict.destination, conflict.iface)
+		return false, nil
+	}
+}
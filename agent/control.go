@@ -0,0 +1,404 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+)
+
+// defaultPingTimeout bounds a ping request on the control API
+const defaultPingTimeout = 5 * time.Second
+
+// dialProtocol is the Upgrade protocol of dial requests, whose connection
+// carries the dialed stream once the agent answers 101
+const dialProtocol = "easyanylink-stream"
+
+// controlServer serves the local control API over a unix socket. The
+// socket is only accessible to the agent's user.
+type controlServer struct {
+	agent  *Agent
+	path   string
+	server *http.Server
+	done   chan struct{} // closed on shutdown to end event streams
+}
+
+// PingResult is the control API response to a ping request
+type PingResult struct {
+	Target string  `json:"target"`
+	RTTMs  float64 `json:"rtt_ms,omitempty"`
+	Error  string  `json:"error,omitempty"`
+	// Stable EALxxxx code of the error, see common/errcode
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+// startControl starts the control API on the configured socket
+func (a *Agent) startControl() error {
+	path := a.config.ControlSocket
+	if path == "" {
+		path = DefaultControlSocket
+	}
+
+	// Remove a stale socket left by a previous run
+	os.Remove(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create control socket directory: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on control socket: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to set control socket permissions: %w", err)
+	}
+
+	cs := &controlServer{agent: a, path: path, done: make(chan struct{})}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", cs.handlePing)
+	mux.HandleFunc("/dial", cs.handleDial)
+	mux.HandleFunc("/profiles", cs.handleProfiles)
+	mux.HandleFunc("/events", cs.handleEvents)
+	mux.HandleFunc("/health", cs.handleHealth)
+	mux.HandleFunc("/status", cs.handleStatus)
+	mux.HandleFunc("/stats", cs.handleStats)
+	mux.HandleFunc("/routes", cs.handleRoutes)
+	mux.HandleFunc("/connect", cs.handleConnect)
+	mux.HandleFunc("/disconnect", cs.handleDisconnect)
+	mux.HandleFunc("/access", cs.handleAccess)
+	cs.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := cs.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Control API stopped: %v", err)
+		}
+	}()
+
+	a.control = cs
+	log.Printf("Control API listening on %s", path)
+	return nil
+}
+
+// close stops the control API and removes the socket
+func (cs *controlServer) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	close(cs.done)
+	cs.server.Shutdown(ctx)
+	os.Remove(cs.path)
+}
+
+// handlePing handles GET /ping?target=<overlay-ip|name>[&timeout=5s]
+func (cs *controlServer) handlePing(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		writeJSON(w, http.StatusBadRequest, PingResult{Error: "target is required", ErrorCode: string(errcode.InvalidArgument)})
+		return
+	}
+
+	timeout := defaultPingTimeout
+	if t := r.URL.Query().Get("timeout"); t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, PingResult{Target: target, Error: "invalid timeout", ErrorCode: string(errcode.InvalidArgument)})
+			return
+		}
+		timeout = d
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	rtt, err := cs.agent.Ping(ctx, target)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = errors.New("timeout")
+		}
+		writeJSON(w, http.StatusBadGateway, PingResult{Target: target, Error: err.Error(), ErrorCode: errorCode(err, errcode.TargetUnreachable)})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PingResult{Target: target, RTTMs: float64(rtt.Microseconds()) / 1000})
+}
+
+// handleDial handles POST /dial?target=<overlay-ip|name>&port=<port>
+// [&timeout=5s], an upgrade request: the agent connects to the port over
+// the overlay and bridges the control connection to it
+func (cs *controlServer) handleDial(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	target := r.URL.Query().Get("target")
+	port, err := strconv.Atoi(r.URL.Query().Get("port"))
+	if target == "" || err != nil || port < 1 || port > 65535 {
+		writeJSON(w, http.StatusBadRequest, DialResult{Target: target, Error: "target and a port are required", ErrorCode: string(errcode.InvalidArgument)})
+		return
+	}
+
+	timeout := defaultPingTimeout
+	if t := r.URL.Query().Get("timeout"); t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, DialResult{Target: target, Error: "invalid timeout", ErrorCode: string(errcode.InvalidArgument)})
+			return
+		}
+		timeout = d
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	upstream, err := cs.agent.DialOverlay(ctx, target, port)
+	cancel()
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = errors.New("timeout")
+		}
+		writeJSON(w, http.StatusBadGateway, DialResult{Target: target, Error: err.Error(), ErrorCode: errorCode(err, errcode.TargetUnreachable)})
+		return
+	}
+	defer upstream.Close()
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	client, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer client.Close()
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: " + dialProtocol + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	// Hijacked connections outlive the control server's shutdown
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-cs.done:
+			client.Close()
+			upstream.Close()
+		case <-done:
+		}
+	}()
+
+	splice(client, upstream, rw.Reader)
+}
+
+// handleProfiles handles GET /profiles and POST /profiles?name=<profile>,
+// which switches to the named profile
+func (cs *controlServer) handleProfiles(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			writeJSON(w, http.StatusBadRequest, ProfileStatus{Error: "name is required", ErrorCode: string(errcode.InvalidArgument)})
+			return
+		}
+		if err := cs.agent.SwitchProfile(name); err != nil {
+			status := cs.agent.Profiles()
+			status.Error, status.ErrorCode = err.Error(), errorCode(err, errcode.InvalidArgument)
+			writeJSON(w, http.StatusBadRequest, status)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cs.agent.Profiles())
+}
+
+// handleHealth handles GET /health, reporting failed and degraded
+// subsystems
+func (cs *controlServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, cs.agent.Health())
+}
+
+// handleStatus handles GET /status, returning the current session and why
+// the last one ended
+func (cs *controlServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, cs.agent.Status())
+}
+
+// handleRoutes handles GET /routes, listing the routes the agent
+// installed through the tunnel
+func (cs *controlServer) handleRoutes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, cs.agent.Routes())
+}
+
+// handleConnect handles POST /connect, reestablishing the session the
+// user disconnected
+func (cs *controlServer) handleConnect(w http.ResponseWriter, r *http.Request) {
+	cs.toggle(w, r, cs.agent.Connect)
+}
+
+// handleDisconnect handles POST /disconnect, ending the session until the
+// user connects again
+func (cs *controlServer) handleDisconnect(w http.ResponseWriter, r *http.Request) {
+	cs.toggle(w, r, cs.agent.Disconnect)
+}
+
+// toggle runs a connect or disconnect request and returns the status
+func (cs *controlServer) toggle(w http.ResponseWriter, r *http.Request, fn func() error) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := fn(); err != nil {
+		status := cs.agent.Status()
+		status.Error, status.ErrorCode = err.Error(), errorCode(err, errcode.Conflict)
+		writeJSON(w, http.StatusConflict, status)
+		return
+	}
+	writeJSON(w, http.StatusOK, cs.agent.Status())
+}
+
+// handleAccess handles GET /access, listing the access requests of the
+// agent, and POST /access?dest=<cidr|ip>&for=<duration>[&proto=tcp]
+// [&ports=22|8000-8080][&reason=...], asking the server for temporary
+// access an admin has to approve
+func (cs *controlServer) handleAccess(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		requests, err := cs.agent.AccessRequests(ctx)
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, AccessRequests{Error: err.Error(), ErrorCode: errorCode(err, errcode.Internal)})
+			return
+		}
+		writeJSON(w, http.StatusOK, AccessRequests{Requests: requests})
+	case http.MethodPost:
+		query := r.URL.Query()
+		spec := AccessSpec{Destination: query.Get("dest"), Protocol: query.Get("proto"), Reason: query.Get("reason")}
+		d, err := time.ParseDuration(query.Get("for"))
+		if spec.Destination == "" || err != nil || d < time.Second {
+			writeJSON(w, http.StatusBadRequest, AccessRequests{Error: "dest and a duration of at least 1s are required", ErrorCode: string(errcode.InvalidArgument)})
+			return
+		}
+		spec.Duration = d
+		if ports := query.Get("ports"); ports != "" {
+			from, to, isRange := strings.Cut(ports, "-")
+			spec.PortFrom, err = strconv.Atoi(from)
+			if err == nil && isRange {
+				spec.PortTo, err = strconv.Atoi(to)
+			}
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, AccessRequests{Error: "invalid ports", ErrorCode: string(errcode.InvalidArgument)})
+				return
+			}
+		}
+
+		request, err := cs.agent.RequestAccess(ctx, spec)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, AccessRequests{Error: err.Error(), ErrorCode: errorCode(err, errcode.InvalidArgument)})
+			return
+		}
+		writeJSON(w, http.StatusOK, AccessRequests{Requests: []AccessRequestInfo{*request}})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleStats handles GET /stats[?last=1h], returning the per-minute
+// traffic history, by default of the last hour
+func (cs *controlServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	last := time.Hour
+	if l := r.URL.Query().Get("last"); l != "" {
+		d, err := time.ParseDuration(l)
+		if err != nil || d <= 0 {
+			writeJSON(w, http.StatusBadRequest, TrafficHistory{Error: "invalid duration", ErrorCode: string(errcode.InvalidArgument)})
+			return
+		}
+		last = d
+	}
+	writeJSON(w, http.StatusOK, cs.agent.TrafficHistory(last))
+}
+
+// handleEvents handles GET /events, streaming agent events as
+// newline-delimited JSON until the client disconnects or the agent stops
+func (cs *controlServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := cs.agent.Events()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-cs.done:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,309 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+)
+
+// ControlClient talks to the control API of a running agent
+type ControlClient struct {
+	path string
+	http *http.Client
+}
+
+// NewControlClient creates a client for the control socket at path, or
+// DefaultControlSocket if path is empty
+func NewControlClient(path string) *ControlClient {
+	if path == "" {
+		path = DefaultControlSocket
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}
+
+	return &ControlClient{path: path, http: &http.Client{Transport: transport}}
+}
+
+// Ping asks the agent to send an overlay echo probe to target
+func (c *ControlClient) Ping(ctx context.Context, target string, timeout time.Duration) (time.Duration, error) {
+	query := url.Values{"target": {target}, "timeout": {timeout.String()}}
+
+	var result PingResult
+	if err := c.get(ctx, "/ping?"+query.Encode(), &result); err != nil {
+		return 0, err
+	}
+	if result.Error != "" {
+		return 0, controlError(result.Error, result.ErrorCode)
+	}
+
+	return time.Duration(result.RTTMs * float64(time.Millisecond)), nil
+}
+
+// Dial asks the agent to connect to a port of target over the overlay and
+// returns the connection, carried by the control socket
+func (c *ControlClient) Dial(ctx context.Context, target string, port int, timeout time.Duration) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach agent control socket: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	query := url.Values{"target": {target}, "port": {strconv.Itoa(port)}, "timeout": {timeout.String()}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://agent/dial?"+query.Encode(), nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", dialProtocol)
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send dial request: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read dial response: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer conn.Close()
+		var result DialResult
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.Error == "" {
+			return nil, fmt.Errorf("dial failed: %s", resp.Status)
+		}
+		return nil, controlError(result.Error, result.ErrorCode)
+	}
+
+	conn.SetDeadline(time.Time{})
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// bufferedConn is a connection whose first bytes were read into a buffer
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Read reads the buffered bytes before those of the connection
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// CloseWrite half-closes the connection
+func (c *bufferedConn) CloseWrite() error {
+	closeWrite(c.Conn)
+	return nil
+}
+
+// Profiles returns the configured profiles of the agent
+func (c *ControlClient) Profiles(ctx context.Context) (*ProfileStatus, error) {
+	var status ProfileStatus
+	if err := c.get(ctx, "/profiles", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// UseProfile switches the agent to the named profile
+func (c *ControlClient) UseProfile(ctx context.Context, name string) (*ProfileStatus, error) {
+	var status ProfileStatus
+	query := url.Values{"name": {name}}
+	if err := c.do(ctx, http.MethodPost, "/profiles?"+query.Encode(), &status); err != nil {
+		return nil, err
+	}
+	if status.Error != "" {
+		return nil, controlError(status.Error, status.ErrorCode)
+	}
+	return &status, nil
+}
+
+// Health returns the state of the agent's subsystems
+func (c *ControlClient) Health(ctx context.Context) (*HealthStatus, error) {
+	var status HealthStatus
+	if err := c.get(ctx, "/health", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Status returns the current session and the last disconnect and error
+func (c *ControlClient) Status(ctx context.Context) (*ConnectionStatus, error) {
+	var status ConnectionStatus
+	if err := c.get(ctx, "/status", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Routes returns the routes the agent installed through the tunnel
+func (c *ControlClient) Routes(ctx context.Context) ([]RouteInfo, error) {
+	var routes []RouteInfo
+	if err := c.get(ctx, "/routes", &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// AccessRequests returns the access requests of the agent, newest first
+func (c *ControlClient) AccessRequests(ctx context.Context) ([]AccessRequestInfo, error) {
+	var result AccessRequests
+	if err := c.get(ctx, "/access", &result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, controlError(result.Error, result.ErrorCode)
+	}
+	return result.Requests, nil
+}
+
+// RequestAccess asks the agent to request temporary access from the
+// server
+func (c *ControlClient) RequestAccess(ctx context.Context, spec AccessSpec) (*AccessRequestInfo, error) {
+	query := url.Values{"dest": {spec.Destination}, "for": {spec.Duration.String()}}
+	if spec.Protocol != "" {
+		query.Set("proto", spec.Protocol)
+	}
+	if spec.PortFrom != 0 {
+		ports := strconv.Itoa(spec.PortFrom)
+		if spec.PortTo != 0 {
+			ports += "-" + strconv.Itoa(spec.PortTo)
+		}
+		query.Set("ports", ports)
+	}
+	if spec.Reason != "" {
+		query.Set("reason", spec.Reason)
+	}
+
+	var result AccessRequests
+	if err := c.do(ctx, http.MethodPost, "/access?"+query.Encode(), &result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, controlError(result.Error, result.ErrorCode)
+	}
+	if len(result.Requests) == 0 {
+		return nil, errors.New("agent returned no access request")
+	}
+	return &result.Requests[0], nil
+}
+
+// Connect asks the agent to reestablish the session the user disconnected
+func (c *ControlClient) Connect(ctx context.Context) (*ConnectionStatus, error) {
+	return c.toggle(ctx, "/connect")
+}
+
+// Disconnect asks the agent to end its session until Connect is called
+func (c *ControlClient) Disconnect(ctx context.Context) (*ConnectionStatus, error) {
+	return c.toggle(ctx, "/disconnect")
+}
+
+// toggle sends a connect or disconnect request
+func (c *ControlClient) toggle(ctx context.Context, path string) (*ConnectionStatus, error) {
+	var status ConnectionStatus
+	if err := c.do(ctx, http.MethodPost, path, &status); err != nil {
+		return nil, err
+	}
+	if status.Error != "" {
+		return nil, controlError(status.Error, status.ErrorCode)
+	}
+	return &status, nil
+}
+
+// TrafficHistory returns the per-minute traffic of the last d
+func (c *ControlClient) TrafficHistory(ctx context.Context, d time.Duration) (*TrafficHistory, error) {
+	var history TrafficHistory
+	query := url.Values{"last": {d.String()}}
+	if err := c.get(ctx, "/stats?"+query.Encode(), &history); err != nil {
+		return nil, err
+	}
+	if history.Error != "" {
+		return nil, controlError(history.Error, history.ErrorCode)
+	}
+	return &history, nil
+}
+
+// Events streams the events of the agent to fn until ctx is cancelled,
+// fn returns false or the agent stops
+func (c *ControlClient) Events(ctx context.Context, fn func(Event) bool) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://agent/events", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach agent control socket: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to subscribe to events: %s", resp.Status)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var event Event
+		if err := decoder.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		if !fn(event) {
+			return nil
+		}
+	}
+}
+
+// get performs a GET request and decodes the JSON response into v
+func (c *ControlClient) get(ctx context.Context, path string, v interface{}) error {
+	return c.do(ctx, http.MethodGet, path, v)
+}
+
+// do performs a request and decodes the JSON response into v
+func (c *ControlClient) do(ctx context.Context, method, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, "http://agent"+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach agent control socket: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// controlError returns the error of a control API response with its code
+func controlError(message, code string) error {
+	err := errors.New(message)
+	if code == "" {
+		return err
+	}
+	return &errcode.Error{Code: errcode.Code(code), Err: err}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package agent
+
+// DefaultControlSocket is the default path of the agent control socket
+const DefaultControlSocket = `C:\ProgramData\EasyAnyLink\agent.sock`
+
+// DefaultStatsFile is the default path of the agent traffic history
+const DefaultStatsFile = `C:\ProgramData\EasyAnyLink\traffic.json`
@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// DialResult is the control API response to a dial request that failed
+type DialResult struct {
+	Target    string `json:"target"`
+	Error     string `json:"error,omitempty"`
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+// DialOverlay opens a TCP connection to a port of target, an overlay IP or
+// agent name. The socket is bound to the TUN interface, so it reaches the
+// overlay even when the system routes to it are missing or shadowed, e.g.
+// by a conflicting LAN route.
+func (a *Agent) DialOverlay(ctx context.Context, target string, port int) (net.Conn, error) {
+	if a.tun == nil {
+		return nil, errors.New("overlay interface is not up")
+	}
+	iface, err := net.InterfaceByName(a.tun.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find overlay interface: %w", err)
+	}
+
+	ip, err := a.Resolve(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Captive portal probes bind the same way, and their mark lets the
+	// connection pass the kill switch
+	dialer := net.Dialer{Control: probeControl(iface)}
+	return dialer.DialContext(ctx, "tcp4", net.JoinHostPort(ip.String(), strconv.Itoa(port)))
+}
+
+// splice copies between two connections until both directions ended,
+// passing on half-closes so protocols like SSH see the end of input
+func splice(a, b net.Conn, aReader io.Reader) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		io.Copy(b, aReader)
+		closeWrite(b)
+	}()
+	io.Copy(a, b)
+	closeWrite(a)
+	wg.Wait()
+}
+
+// closeWrite half-closes a connection, or closes it if it cannot
+func closeWrite(c net.Conn) {
+	if cw, ok := c.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite()
+		return
+	}
+	c.Close()
+}
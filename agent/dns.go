@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// applyDNS points the system resolver at the tunnel DNS servers of the
+// agent's group, or else the configured ones. It is safe to call again
+// after the network changed under the agent.
+func (a *Agent) applyDNS() error {
+	if a.dns == nil {
+		return nil
+	}
+	a.dnsMu.Lock()
+	defer a.dnsMu.Unlock()
+	if a.dnsSuspended {
+		return nil
+	}
+
+	servers, search := a.dnsSettings()
+	if len(servers) == 0 {
+		// Resolvers of a group the agent left go back to the system
+		if err := a.dns.Restore(); err != nil {
+			return fmt.Errorf("failed to restore DNS: %w", err)
+		}
+		return nil
+	}
+	if err := a.dns.Apply(a.tun.Name(), servers, search); err != nil {
+		return fmt.Errorf("failed to apply DNS: %w", err)
+	}
+	log.Printf("DNS configured: %v", servers)
+	return nil
+}
+
+// dnsSettings returns the tunnel resolvers in effect, none if neither the
+// group nor the configuration sets any. The caller holds dnsMu.
+func (a *Agent) dnsSettings() (servers, search []string) {
+	if a.managed != nil && len(a.managed.DnsServers) > 0 {
+		return a.managed.DnsServers, a.managed.DnsSearch
+	}
+	if a.config.DNS != nil {
+		return a.config.DNS.Servers, a.config.DNS.Search
+	}
+	return nil, nil
+}
+
+// restoreDNS puts back the DNS configuration found before the tunnel
+func (a *Agent) restoreDNS() {
+	if a.dns == nil {
+		return
+	}
+	a.dnsMu.Lock()
+	defer a.dnsMu.Unlock()
+	if err := a.dns.Restore(); err != nil {
+		log.Printf("Warning: failed to restore DNS configuration: %v", err)
+	}
+}
+
+// suspendDNS restores the original resolvers while the session is down:
+// the tunnel resolvers are unreachable and server names must resolve
+func (a *Agent) suspendDNS() {
+	if a.dns == nil {
+		return
+	}
+	a.dnsMu.Lock()
+	defer a.dnsMu.Unlock()
+	a.dnsSuspended = true
+	if err := a.dns.Restore(); err != nil {
+		log.Printf("Warning: failed to restore DNS configuration: %v", err)
+	}
+}
+
+// resumeDNS reapplies the tunnel resolvers once a session is up again
+func (a *Agent) resumeDNS() error {
+	a.dnsMu.Lock()
+	a.dnsSuspended = false
+	a.dnsMu.Unlock()
+	return a.applyDNS()
+}
+
+// saveDNSBackup records the original DNS configuration before it is
+// replaced, so that a later run can restore it after a crash
+func saveDNSBackup(backup interface{}) error {
+	data, err := json.Marshal(backup)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dnsBackupFile), 0700); err != nil {
+		return fmt.Errorf("failed to create DNS backup directory: %w", err)
+	}
+	tmp := dnsBackupFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write DNS backup: %w", err)
+	}
+	if err := os.Rename(tmp, dnsBackupFile); err != nil {
+		return fmt.Errorf("failed to write DNS backup: %w", err)
+	}
+	return nil
+}
+
+// loadDNSBackup reads the DNS backup, reporting false if there is none
+func loadDNSBackup(backup interface{}) (bool, error) {
+	data, err := os.ReadFile(dnsBackupFile)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read DNS backup: %w", err)
+	}
+	if err := json.Unmarshal(data, backup); err != nil {
+		return false, fmt.Errorf("failed to parse DNS backup %s: %w", dnsBackupFile, err)
+	}
+	return true, nil
+}
+
+// removeDNSBackup deletes the DNS backup once the original is restored
+func removeDNSBackup() error {
+	if err := os.Remove(dnsBackupFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove DNS backup: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+//go:build darwin
+
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// dnsBackupFile holds the original DNS configuration while the agent runs
+const dnsBackupFile = "/var/db/easyanylink/dns-backup.json"
+
+// dnsBackup is the state needed to undo the tunnel DNS configuration
+type dnsBackup struct {
+	Services map[string]dnsServiceSettings `json:"services"`
+}
+
+// dnsServiceSettings are the manual DNS settings of a network service,
+// nil when they come from DHCP
+type dnsServiceSettings struct {
+	Servers []string `json:"servers"`
+	Search  []string `json:"search"`
+}
+
+// DNSConfigurator sets the tunnel resolvers on every network service with
+// networksetup, as the utun interface has no service of its own
+type DNSConfigurator struct{}
+
+// NewDNSConfigurator creates a new DNS configurator
+func NewDNSConfigurator() *DNSConfigurator {
+	return &DNSConfigurator{}
+}
+
+// Apply sends all DNS queries to servers
+func (d *DNSConfigurator) Apply(iface string, servers, search []string) error {
+	services, err := networkServices()
+	if err != nil {
+		return err
+	}
+
+	var backup dnsBackup
+	if _, err := loadDNSBackup(&backup); err != nil {
+		return err
+	}
+	if backup.Services == nil {
+		backup.Services = make(map[string]dnsServiceSettings)
+	}
+
+	// Services already in the backup carry our settings, only services
+	// that appeared since still have their original ones
+	changed := false
+	for _, service := range services {
+		if _, ok := backup.Services[service]; ok {
+			continue
+		}
+		var settings dnsServiceSettings
+		if settings.Servers, err = networksetupList("-getdnsservers", service); err != nil {
+			return err
+		}
+		if settings.Search, err = networksetupList("-getsearchdomains", service); err != nil {
+			return err
+		}
+		backup.Services[service] = settings
+		changed = true
+	}
+	if changed {
+		if err := saveDNSBackup(&backup); err != nil {
+			return err
+		}
+	}
+
+	for _, service := range services {
+		if err := setServiceDNS(service, dnsServiceSettings{Servers: servers, Search: search}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore undoes Apply, including one left behind by a crashed agent
+func (d *DNSConfigurator) Restore() error {
+	var backup dnsBackup
+	found, err := loadDNSBackup(&backup)
+	if err != nil || !found {
+		return err
+	}
+
+	services, err := networkServices()
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(services))
+	for _, service := range services {
+		present[service] = true
+	}
+
+	for service, settings := range backup.Services {
+		if !present[service] {
+			continue
+		}
+		if err := setServiceDNS(service, settings); err != nil {
+			return err
+		}
+	}
+	return removeDNSBackup()
+}
+
+// networkServices lists the enabled network services
+func networkServices() ([]string, error) {
+	output, err := exec.Command("networksetup", "-listallnetworkservices").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network services: %w", err)
+	}
+
+	var services []string
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	// The first line explains that an asterisk marks disabled services
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "*") {
+			continue
+		}
+		services = append(services, line)
+	}
+	return services, nil
+}
+
+// networksetupList reads a DNS list setting of a service, nil if unset
+func networksetupList(option, service string) ([]string, error) {
+	output, err := exec.Command("networksetup", option, service).Output()
+	if err != nil {
+		return nil, fmt.Errorf("networksetup %s %s failed: %w", option, service, err)
+	}
+	// e.g. "There aren't any DNS Servers set on Wi-Fi."
+	if strings.Contains(string(output), "aren't any") {
+		return nil, nil
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// setServiceDNS sets the DNS settings of a service, "Empty" restores DHCP
+func setServiceDNS(service string, settings dnsServiceSettings) error {
+	for _, setting := range []struct {
+		option string
+		values []string
+	}{
+		{"-setdnsservers", settings.Servers},
+		{"-setsearchdomains", settings.Search},
+	} {
+		values := setting.values
+		if len(values) == 0 {
+			values = []string{"Empty"}
+		}
+		args := append([]string{setting.option, service}, values...)
+		if output, err := exec.Command("networksetup", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("networksetup %s %s failed: %s", setting.option, service, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
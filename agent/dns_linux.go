@@ -0,0 +1,145 @@
+//go:build linux
+
+package agent
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// dnsBackupFile holds the original DNS configuration while the agent runs
+	dnsBackupFile = "/var/lib/easyanylink/dns-backup.json"
+
+	// resolvConf is rewritten when systemd-resolved is not in use
+	resolvConf = "/etc/resolv.conf"
+)
+
+// dnsBackup is the state needed to undo the tunnel DNS configuration
+type dnsBackup struct {
+	Link       string `json:"link,omitempty"`        // interface configured through systemd-resolved
+	ResolvConf []byte `json:"resolv_conf,omitempty"` // original resolv.conf
+	Applied    []byte `json:"applied,omitempty"`     // resolv.conf written by the agent
+}
+
+// DNSConfigurator sets the tunnel resolvers through systemd-resolved when
+// it manages resolv.conf, or by rewriting resolv.conf otherwise
+type DNSConfigurator struct{}
+
+// NewDNSConfigurator creates a new DNS configurator
+func NewDNSConfigurator() *DNSConfigurator {
+	return &DNSConfigurator{}
+}
+
+// Apply sends all DNS queries to servers
+func (d *DNSConfigurator) Apply(iface string, servers, search []string) error {
+	if usesResolved() {
+		if err := saveDNSBackup(&dnsBackup{Link: iface}); err != nil {
+			return err
+		}
+		if err := resolvectl(append([]string{"dns", iface}, servers...)...); err != nil {
+			return err
+		}
+		// "~." routes every domain to the tunnel link
+		return resolvectl(append([]string{"domain", iface, "~."}, search...)...)
+	}
+
+	current, err := os.ReadFile(resolvConf)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", resolvConf, err)
+	}
+	applied := generateResolvConf(servers, search)
+
+	var backup dnsBackup
+	found, err := loadDNSBackup(&backup)
+	if err != nil {
+		return err
+	}
+	if found && bytes.Equal(current, backup.Applied) {
+		return nil
+	}
+	// Without a backup the current file is the original; if the file no
+	// longer matches what we wrote, DHCP or a network manager replaced it
+	// and its version is the one to restore
+	backup = dnsBackup{ResolvConf: current, Applied: applied}
+	if err := saveDNSBackup(&backup); err != nil {
+		return err
+	}
+	if err := writeSystemFile(resolvConf, applied); err != nil {
+		return fmt.Errorf("failed to write %s: %w", resolvConf, err)
+	}
+	return nil
+}
+
+// Restore undoes Apply, including one left behind by a crashed agent
+func (d *DNSConfigurator) Restore() error {
+	var backup dnsBackup
+	found, err := loadDNSBackup(&backup)
+	if err != nil || !found {
+		return err
+	}
+
+	if backup.Link != "" {
+		// The link is gone with the TUN after a crash, nothing to revert then
+		if _, err := os.Stat(filepath.Join("/sys/class/net", backup.Link)); err == nil {
+			if err := resolvectl("revert", backup.Link); err != nil {
+				return err
+			}
+		}
+	}
+	if backup.Applied != nil {
+		current, err := os.ReadFile(resolvConf)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", resolvConf, err)
+		}
+		// Leave a file rewritten by someone else since, it is newer
+		if bytes.Equal(current, backup.Applied) {
+			if len(backup.ResolvConf) == 0 {
+				err = removeSystemFile(resolvConf)
+			} else {
+				err = writeSystemFile(resolvConf, backup.ResolvConf)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to restore %s: %w", resolvConf, err)
+			}
+		}
+	}
+
+	return removeDNSBackup()
+}
+
+// usesResolved reports whether systemd-resolved manages resolv.conf
+func usesResolved() bool {
+	target, err := os.Readlink(resolvConf)
+	if err != nil || !strings.Contains(target, "systemd/resolve") {
+		return false
+	}
+	_, err = exec.LookPath("resolvectl")
+	return err == nil
+}
+
+// resolvectl runs resolvectl with args
+func resolvectl(args ...string) error {
+	output, err := privCommand("resolvectl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("resolvectl %s failed: %s", args[0], strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// generateResolvConf renders a resolv.conf for the tunnel resolvers
+func generateResolvConf(servers, search []string) []byte {
+	var b bytes.Buffer
+	b.WriteString("# Generated by EasyAnyLink, restored on disconnect\n")
+	for _, server := range servers {
+		fmt.Fprintf(&b, "nameserver %s\n", server)
+	}
+	if len(search) > 0 {
+		fmt.Fprintf(&b, "search %s\n", strings.Join(search, " "))
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,92 @@
+//go:build windows
+
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// dnsBackupFile holds the original DNS configuration while the agent runs
+const dnsBackupFile = `C:\ProgramData\EasyAnyLink\dns-backup.json`
+
+// dnsBackup is the state needed to undo the tunnel DNS configuration
+type dnsBackup struct {
+	Iface string `json:"iface"` // adapter whose DNS settings were replaced
+}
+
+// DNSConfigurator sets the tunnel resolvers on the Wintun adapter. Windows
+// prefers the resolvers of the adapter with the lowest metric, which is the
+// tunnel while its routes are installed.
+type DNSConfigurator struct{}
+
+// NewDNSConfigurator creates a new DNS configurator
+func NewDNSConfigurator() *DNSConfigurator {
+	return &DNSConfigurator{}
+}
+
+// Apply sends DNS queries to servers
+func (d *DNSConfigurator) Apply(iface string, servers, search []string) error {
+	if err := saveDNSBackup(&dnsBackup{Iface: iface}); err != nil {
+		return err
+	}
+
+	name := "name=" + iface
+	if err := netsh("interface", "ipv4", "set", "dnsservers", name,
+		"source=static", "address="+servers[0], "register=none", "validate=no"); err != nil {
+		return err
+	}
+	for i, server := range servers[1:] {
+		if err := netsh("interface", "ipv4", "add", "dnsservers", name,
+			"address="+server, "index="+strconv.Itoa(i+2), "validate=no"); err != nil {
+			return err
+		}
+	}
+
+	// Windows keeps one connection-specific suffix per adapter
+	if len(search) > 0 {
+		cmd := exec.Command("powershell", "-NoProfile", "-Command",
+			fmt.Sprintf("Set-DnsClient -InterfaceAlias '%s' -ConnectionSpecificSuffix '%s'", iface, search[0]))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set DNS suffix: %s", strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// Restore undoes Apply, including one left behind by a crashed agent
+func (d *DNSConfigurator) Restore() error {
+	var backup dnsBackup
+	found, err := loadDNSBackup(&backup)
+	if err != nil || !found {
+		return err
+	}
+
+	// The adapter is removed with the session unless the agent crashed
+	if adapterExists(backup.Iface) {
+		if err := netsh("interface", "ipv4", "set", "dnsservers", "name="+backup.Iface, "source=dhcp"); err != nil {
+			return err
+		}
+		cmd := exec.Command("powershell", "-NoProfile", "-Command",
+			fmt.Sprintf("Set-DnsClient -InterfaceAlias '%s' -ResetConnectionSpecificSuffix", backup.Iface))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to reset DNS suffix: %s", strings.TrimSpace(string(output)))
+		}
+	}
+	return removeDNSBackup()
+}
+
+// adapterExists reports whether a network adapter is present
+func adapterExists(iface string) bool {
+	return exec.Command("netsh", "interface", "show", "interface", "name="+iface).Run() == nil
+}
+
+// netsh runs netsh with args
+func netsh(args ...string) error {
+	if output, err := exec.Command("netsh", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("netsh %s failed: %s", strings.Join(args, " "), strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of agent state change
+type EventType string
+
+const (
+	EventConnected      EventType = "connected"       // gRPC connection established
+	EventRegistered     EventType = "registered"      // registration accepted by server
+	EventRouteInstalled EventType = "route_installed" // route added to the system table
+	EventRouteRemoved   EventType = "route_removed"   // route removed from the system table
+	EventReconnecting   EventType = "reconnecting"    // connection lost, retrying
+	EventError          EventType = "error"           // a subsystem failed
+	EventDegraded       EventType = "degraded"        // a subsystem keeps failing despite restarts
+	EventCaptivePortal  EventType = "captive_portal"  // captive portal detected, kill switch paused
+	EventPortalCleared  EventType = "portal_cleared"  // server reachable again, kill switch resumed
+	EventDisconnected   EventType = "disconnected"    // session ended by the user, see Agent.Disconnect
+	EventStopped        EventType = "stopped"         // agent shut down
+)
+
+// eventBufferSize is the per-subscriber buffer; events are dropped when full
+const eventBufferSize = 64
+
+// Event describes an agent state change
+type Event struct {
+	Type       EventType `json:"type"`
+	Time       time.Time `json:"time"`
+	Message    string    `json:"message,omitempty"`
+	SessionID  string    `json:"session_id,omitempty"`
+	AssignedIP string    `json:"assigned_ip,omitempty"`
+	Route      string    `json:"route,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	ErrorCode  string    `json:"error_code,omitempty"`
+}
+
+// eventBus fans out events to subscribers without blocking the agent
+type eventBus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// subscribe registers a new subscriber channel
+func (b *eventBus) subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, eventBufferSize)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// publish delivers an event to all subscribers, dropping it for slow ones
+func (b *eventBus) publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// close closes all subscriber channels
+func (b *eventBus) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		close(ch)
+	}
+	b.subscribers = make(map[chan Event]struct{})
+	b.closed = true
+}
@@ -0,0 +1,438 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	reconnectMinBackoff = time.Second
+	reconnectMaxBackoff = 30 * time.Second
+
+	// serverCooldown is how long a server that failed is tried only after
+	// the healthy ones
+	serverCooldown = time.Minute
+
+	// failbackInterval is how often the preferred server is probed while
+	// connected to a fallback
+	failbackInterval = 5 * time.Minute
+	failbackTimeout  = 5 * time.Second
+
+	// upgradeReconnectDelay is the most a reconnect waits after the server
+	// handed over to a new process, spreading the agents over the interval
+	upgradeReconnectDelay = 2 * time.Second
+)
+
+// candidates returns the servers to try in order: servers that have not
+// failed recently in configured order, or fastest first with latency
+// selection, then those in cooldown or that just handed the session
+// over. Servers that turned the agent away are skipped until their retry
+// delay passed.
+func (a *Agent) candidates() []string {
+	a.serversMu.Lock()
+	defer a.serversMu.Unlock()
+
+	var healthy, cooling []string
+	for _, server := range a.serverList() {
+		if a.isBusy(server) {
+			continue
+		}
+		if failed, ok := a.serverFailures[server]; (ok && time.Since(failed) < serverCooldown) || a.handedOver(server) {
+			cooling = append(cooling, server)
+		} else {
+			healthy = append(healthy, server)
+		}
+	}
+	if a.latencySelection() {
+		a.sortByLatency(healthy)
+	}
+	return append(healthy, cooling...)
+}
+
+// connectAny connects and registers with the first server that accepts
+// the agent
+func (a *Agent) connectAny() error {
+	if a.conn != nil {
+		a.conn.Close()
+		a.conn = nil
+	}
+
+	a.serversMu.Lock()
+	userKey := a.config.UserKey
+	a.serversMu.Unlock()
+
+	if a.latencySelection() {
+		a.measureIfStale()
+	}
+	servers := a.candidates()
+	if len(servers) == 0 {
+		return fmt.Errorf("all servers are busy")
+	}
+
+	var lastErr error
+	for i := 0; i < len(servers); i++ {
+		server := servers[i]
+		err := a.connect(server)
+		if err == nil {
+			if err = a.register(userKey); err != nil {
+				a.conn.Close()
+				a.conn = nil
+			}
+		}
+
+		busy := serverBusy(err)
+		a.serversMu.Lock()
+		switch {
+		case busy != nil:
+			a.busyUntil[server] = time.Now().Add(time.Duration(busy.RetryAfter) * time.Second)
+		case err != nil:
+			a.serverFailures[server] = time.Now()
+		default:
+			delete(a.serverFailures, server)
+			a.server = server
+		}
+		a.serversMu.Unlock()
+
+		if err == nil {
+			return nil
+		}
+		log.Printf("Server %s unavailable: %v", server, err)
+		lastErr = err
+
+		// Try the alternates a busy server offers next
+		if busy != nil {
+			servers = insertAlternates(servers, i+1, busy.AlternateServers)
+		}
+	}
+
+	return fmt.Errorf("no server available: %w", lastErr)
+}
+
+// serverBusy returns the details of a registration turned away because the
+// server is full, nil for other errors
+func serverBusy(err error) *proto.ServerBusy {
+	if err == nil || status.Code(err) != codes.ResourceExhausted {
+		return nil
+	}
+	st, _ := status.FromError(err)
+	for _, detail := range st.Details() {
+		if busy, ok := detail.(*proto.ServerBusy); ok {
+			return busy
+		}
+	}
+	return nil
+}
+
+// insertAlternates inserts the alternate servers not yet in servers at
+// index i
+func insertAlternates(servers []string, i int, alternates []string) []string {
+	var added []string
+	for _, alternate := range alternates {
+		if !slices.Contains(servers, alternate) && !slices.Contains(added, alternate) {
+			added = append(added, alternate)
+		}
+	}
+	return slices.Insert(servers, i, added...)
+}
+
+// isBusy reports whether a server asked the agent to retry later. The
+// caller holds serversMu.
+func (a *Agent) isBusy(server string) bool {
+	return time.Now().Before(a.busyUntil[server])
+}
+
+// busyWait returns how long until the first configured server accepts
+// registrations again while all of them are busy, otherwise 0
+func (a *Agent) busyWait() time.Duration {
+	a.serversMu.Lock()
+	defer a.serversMu.Unlock()
+
+	var wait time.Duration
+	for _, server := range a.serverList() {
+		until := time.Until(a.busyUntil[server])
+		if until <= 0 {
+			return 0
+		}
+		if wait == 0 || until < wait {
+			wait = until
+		}
+	}
+	return wait
+}
+
+// startSession starts the heartbeat and one relay worker per TUN queue
+// for the current session
+func (a *Agent) startSession() {
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.sessCancel = cancel
+	a.lastStatus.sessionStarted()
+
+	// Session workers that panicked are restarted with the session
+	a.health.restarted("heartbeat")
+	a.sessWg.Add(1)
+	go a.heartbeatLoop(ctx)
+	for i := 0; i < a.tun.NumQueues(); i++ {
+		a.health.restarted(fmt.Sprintf("relay-%d", i))
+		a.health.restarted(fmt.Sprintf("relay-sender-%d", i))
+		a.sessWg.Add(1)
+		go a.relayData(ctx, i)
+	}
+
+	a.sessMu.RLock()
+	rekeyAfter, rekeyBytes := a.rekeyAfter, a.rekeyBytes
+	a.sessMu.RUnlock()
+	if rekeyAfter > 0 || rekeyBytes > 0 {
+		a.sessWg.Add(1)
+		go a.rekeyLoop(ctx, rekeyAfter, rekeyBytes)
+	}
+
+	if a.config.Mode == "gateway" && a.serverSupports(proto.CapabilityGatewayReady) {
+		a.sessWg.Add(1)
+		go a.signalReady(ctx)
+	}
+}
+
+// stopSession stops the workers of the current session
+func (a *Agent) stopSession() {
+	a.sessCancel()
+	a.sessWg.Wait()
+
+	// Discard failures reported while the workers stopped, and a rekey or
+	// handoff the new connection makes moot
+	select {
+	case <-a.lost:
+	default:
+	}
+	select {
+	case <-a.rekeyDue:
+	default:
+	}
+	select {
+	case <-a.handoff:
+	default:
+	}
+}
+
+// sessionLost records why the session ended and requests a reconnect,
+// unless the session was ended on purpose
+func (a *Agent) sessionLost(ctx context.Context, err error) {
+	if ctx.Err() != nil || a.stopping.Load() {
+		return
+	}
+	a.lastStatus.disconnected(err)
+	select {
+	case a.lost <- struct{}{}:
+	default:
+	}
+}
+
+// supervise reestablishes the session on the best available server when
+// it is lost, fails back to the preferred server once it recovers, or
+// moves to a clearly faster one with latency selection, moves it to fresh
+// keys when the rekey policy asks for it, and to the server the current
+// one hands it over to
+func (a *Agent) supervise() {
+	ticker := time.NewTicker(failbackInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-a.lost:
+		case <-a.rekeyDue:
+			a.rekey()
+			continue
+		case server := <-a.handoff:
+			a.handOff(server)
+			continue
+		case <-a.pause:
+			if !a.userDisconnect() {
+				return
+			}
+			continue
+		case <-ticker.C:
+			if !a.betterServer() {
+				continue
+			}
+		case <-a.netChanged:
+			if !a.fasterServer() {
+				continue
+			}
+		}
+
+		a.stopSession()
+		if !a.reconnect() {
+			return
+		}
+		a.startSession()
+	}
+}
+
+// reconnect connects to the best available server, retrying with backoff
+// until it succeeds or the agent stops
+func (a *Agent) reconnect() bool {
+	// Saved before connectAny stores the addresses of the new session
+	previousIP, previousGateway := a.overlayAddrs()
+	a.events.publish(Event{Type: EventReconnecting})
+	a.suspendDNS()
+
+	// The old server process drains its connections; leave it at once and
+	// give the new one a moment rather than all agents arriving together
+	if a.lastStatus.lastDisconnectReason() == DisconnectServerUpgrade {
+		if a.conn != nil {
+			a.conn.Close()
+			a.conn = nil
+		}
+		delay := 500*time.Millisecond + rand.N(upgradeReconnectDelay)
+		log.Printf("Server upgraded, reconnecting in %s", delay.Round(time.Millisecond))
+		select {
+		case <-a.ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+	}
+
+	backoff := reconnectMinBackoff
+	for {
+		err := a.connectAny()
+		if err == nil {
+			break
+		}
+
+		// Busy servers said when to come back
+		delay := backoff
+		if wait := a.busyWait(); wait > delay {
+			delay = wait
+		}
+		err = errcode.Wrap(errcode.ServerUnreachable, err)
+		log.Printf("Reconnect failed, retrying in %s: %v", delay.Round(time.Second), err)
+		a.emitError("reconnect failed", err)
+
+		select {
+		case <-a.ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		if backoff *= 2; backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+
+	a.adaptTUN(previousIP, previousGateway)
+
+	if a.managesRoutes() {
+		if err := a.syncRoutes(); err != nil {
+			log.Printf("Failed to refresh routes: %v", err)
+		}
+	}
+
+	if err := a.resumeDNS(); err != nil {
+		err = errcode.Wrap(errcode.DNSFailed, err)
+		log.Printf("Failed to reapply DNS: %v", err)
+		a.emitError("failed to reapply DNS", err)
+	}
+
+	return true
+}
+
+// adaptTUN readdresses the TUN after the session moved to another server
+// and lowers its MTU to the one the new server granted
+func (a *Agent) adaptTUN(previousIP, previousGateway string) {
+	// A server of another deployment may assign a different address
+	assignedIP, gatewayIP := a.overlayAddrs()
+	if assignedIP != previousIP || gatewayIP != previousGateway {
+		log.Printf("Overlay IP changed from %s to %s", previousIP, assignedIP)
+		if err := a.tun.ClearIP(previousIP, previousGateway, "255.255.0.0"); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		if err := a.tun.SetIP(assignedIP, gatewayIP, "255.255.0.0"); err != nil {
+			err = errcode.Wrap(errcode.TUNFailed, err)
+			log.Printf("Failed to readdress TUN: %v", err)
+			a.emitError("failed to readdress TUN", err)
+		}
+	}
+
+	// Packets larger than the new server's MTU would be dropped by it
+	a.sessMu.RLock()
+	serverMTU := a.serverMTU
+	a.sessMu.RUnlock()
+	if serverMTU > 0 && a.tun.MTU() > serverMTU {
+		log.Printf("Lowering TUN MTU to the server MTU %d", serverMTU)
+		if err := a.tun.SetMTU(serverMTU); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+}
+
+// betterServer reports whether the session should move to another
+// server: a clearly faster one with latency selection, otherwise a more
+// preferred one that recovered
+func (a *Agent) betterServer() bool {
+	if a.latencySelection() {
+		return a.fasterServer()
+	}
+	return a.preferredRecovered()
+}
+
+// preferredRecovered reports whether the agent is on a fallback server
+// while a more preferred one is reachable again
+func (a *Agent) preferredRecovered() bool {
+	a.serversMu.Lock()
+	servers := a.config.ServerList()
+	current := a.server
+	caFile, insecure := a.config.CAFile, a.config.InsecureSkipVerify
+	a.serversMu.Unlock()
+
+	for _, server := range servers {
+		if server == current {
+			return false
+		}
+		a.serversMu.Lock()
+		skip := a.isBusy(server) || a.handedOver(server)
+		a.serversMu.Unlock()
+		if skip {
+			continue
+		}
+		if probeServer(server, caFile, insecure) == nil {
+			log.Printf("Preferred server %s is reachable again, failing back", server)
+			a.serversMu.Lock()
+			delete(a.serverFailures, server)
+			a.serversMu.Unlock()
+			return true
+		}
+	}
+	return false
+}
+
+// probeServer checks that a QUIC handshake with server succeeds
+func probeServer(server, caFile string, insecure bool) error {
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := crypto.LoadClientTLSConfigWithCA(host, caFile, insecure)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), failbackTimeout)
+	defer cancel()
+
+	conn, err := crypto.NewQUICDialer(tlsConfig).DialContext(ctx, server)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"io"
+	"sync"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+)
+
+// framedQueue reads and writes raw IP packets on a TUN queue whose frames
+// carry the platform header of tunFraming, so the relay only ever sees
+// raw IP. Reading a frame that is not IP returns packet.ErrBadFrame.
+type framedQueue struct {
+	queue   io.ReadWriter
+	framing packet.Framing
+
+	writeMu  sync.Mutex
+	writeBuf []byte // frames being written, unused for raw framing
+}
+
+// packetQueue returns TUN queue i framed as raw IP packets
+func (a *Agent) packetQueue(i int) io.ReadWriter {
+	q := &framedQueue{queue: a.tun.Queue(i), framing: tunFraming}
+	if tunFraming.HeaderLen() > 0 {
+		q.writeBuf = make([]byte, tunFraming.HeaderLen()+65535)
+	}
+	return q
+}
+
+// Read reads the frame into buf and moves its packet to the front
+func (q *framedQueue) Read(buf []byte) (int, error) {
+	n, err := q.queue.Read(buf)
+	if err != nil {
+		return 0, err
+	}
+	ip, err := q.framing.Decap(buf[:n])
+	if err != nil {
+		return 0, err
+	}
+	return copy(buf, ip), nil
+}
+
+// Write writes the frame of the packet in buf
+func (q *framedQueue) Write(buf []byte) (int, error) {
+	// Raw frames are the packets themselves
+	if q.writeBuf == nil {
+		if _, err := q.framing.Decap(buf); err != nil {
+			return 0, err
+		}
+		return q.queue.Write(buf)
+	}
+
+	q.writeMu.Lock()
+	defer q.writeMu.Unlock()
+
+	n, err := q.framing.Encap(q.writeBuf, buf)
+	if err != nil {
+		return 0, err
+	}
+	written, err := q.queue.Write(q.writeBuf[:n])
+	if err != nil {
+		return 0, err
+	}
+	return written - q.framing.HeaderLen(), nil
+}
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+)
+
+// handoffHold is how long a server that handed the session over is only
+// tried after the others and not failed back to, e.g. while it is under
+// maintenance
+const handoffHold = 15 * time.Minute
+
+// requestHandoff asks the supervisor to move the session to the server
+// the current server handed it over to
+func (a *Agent) requestHandoff(server string) {
+	select {
+	case a.handoff <- server:
+	default:
+	}
+}
+
+// handOff moves the session to another server of the cluster, which
+// continues it with the overlay IP and routes. As with a rekey, the relay
+// streams of the new connection take over before the old one closes. The
+// agent only follows to servers it would connect to anyway, those the kill
+// switch lets through.
+func (a *Agent) handOff(server string) {
+	a.serversMu.Lock()
+	from, userKey := a.server, a.config.UserKey
+	known := slices.Contains(a.serverList(), server)
+	a.serversMu.Unlock()
+	if server == from {
+		return
+	}
+	if !known {
+		log.Printf("Server %s handed the session over to unknown server %s, staying", from, server)
+		return
+	}
+
+	previousIP, previousGateway := a.overlayAddrs()
+	oldConn, oldClient, oldCancel := a.conn, a.client, a.sessCancel
+	old := a.queueSenders()
+
+	err := a.connect(server)
+	if err == nil {
+		if err = a.register(userKey); err != nil {
+			a.conn.Close()
+		}
+	}
+	if err != nil {
+		a.sessMu.Lock()
+		a.conn, a.client = oldConn, oldClient
+		a.sessMu.Unlock()
+		a.serversMu.Lock()
+		a.serverFailures[server] = time.Now()
+		a.serversMu.Unlock()
+
+		err = errcode.Wrap(errcode.ServerUnreachable, err)
+		log.Printf("Handoff to %s failed, staying on %s: %v", server, from, err)
+		a.emitError("handoff failed", err)
+		return
+	}
+
+	a.serversMu.Lock()
+	a.server = server
+	delete(a.serverFailures, server)
+	a.handedOverBy, a.handedOverAt = from, time.Now()
+	a.serversMu.Unlock()
+
+	a.adaptTUN(previousIP, previousGateway)
+	a.startSession()
+	a.retireConnection(oldConn, oldCancel, old, "Handoff")
+
+	if a.managesRoutes() {
+		if err := a.syncRoutes(); err != nil {
+			log.Printf("Failed to refresh routes: %v", err)
+		}
+	}
+	log.Printf("Session moved from %s to %s", from, server)
+}
+
+// handedOver reports whether server handed the session over within
+// handoffHold. The caller holds serversMu.
+func (a *Agent) handedOver(server string) bool {
+	return server == a.handedOverBy && time.Since(a.handedOverAt) < handoffHold
+}
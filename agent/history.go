@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// historyLength is how much per-minute traffic history agents keep
+const historyLength = 24 * time.Hour
+
+// historySaveInterval is how often the traffic history is written to the
+// stats file, it is also saved when the agent stops
+const historySaveInterval = 10 * time.Minute
+
+// TrafficSample is the traffic of the agent during one minute
+type TrafficSample struct {
+	Time            time.Time `json:"time"` // start of the minute
+	BytesSent       uint64    `json:"bytes_sent"`
+	BytesReceived   uint64    `json:"bytes_received"`
+	PacketsSent     uint64    `json:"packets_sent"`
+	PacketsReceived uint64    `json:"packets_received"`
+	Drops           uint32    `json:"drops"`
+	Connected       bool      `json:"connected"` // whether a session was up at the end of the minute
+}
+
+// TrafficHistory is the control API response to a stats request
+type TrafficHistory struct {
+	Since     time.Time       `json:"since"`
+	Samples   []TrafficSample `json:"samples"` // oldest first, minutes without a sample were not recorded
+	Error     string          `json:"error,omitempty"`
+	ErrorCode string          `json:"error_code,omitempty"`
+}
+
+// trafficHistory is a ring buffer of per-minute traffic samples, loaded
+// from and saved to a local file so it survives restarts
+type trafficHistory struct {
+	path    string
+	samples []TrafficSample // oldest first, at most one per minute of historyLength
+	last    AgentStats      // counters at the previous sample
+	mu      sync.Mutex
+}
+
+// newTrafficHistory creates the history stored at path, loading samples
+// saved by a previous run
+func newTrafficHistory(path string) *trafficHistory {
+	h := &trafficHistory{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Warning: failed to read traffic history: %v", err)
+		}
+		return h
+	}
+	var saved []TrafficSample
+	if err := json.Unmarshal(data, &saved); err != nil {
+		log.Printf("Warning: ignoring corrupt traffic history %s: %v", path, err)
+		return h
+	}
+
+	cutoff := time.Now().Add(-historyLength)
+	for _, sample := range saved {
+		if sample.Time.After(cutoff) {
+			h.samples = append(h.samples, sample)
+		}
+	}
+	return h
+}
+
+// record appends the traffic since the previous sample, given the
+// cumulative counters of the agent
+func (h *trafficHistory) record(now time.Time, stats AgentStats, connected bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.samples = append(h.samples, TrafficSample{
+		Time:            now.Truncate(time.Minute).Add(-time.Minute),
+		BytesSent:       stats.BytesSent - h.last.BytesSent,
+		BytesReceived:   stats.BytesReceived - h.last.BytesReceived,
+		PacketsSent:     stats.PacketsSent - h.last.PacketsSent,
+		PacketsReceived: stats.PacketsReceived - h.last.PacketsReceived,
+		Drops:           stats.Drops - h.last.Drops,
+		Connected:       connected,
+	})
+	h.last = stats
+
+	if limit := int(historyLength / time.Minute); len(h.samples) > limit {
+		h.samples = append(h.samples[:0], h.samples[len(h.samples)-limit:]...)
+	}
+}
+
+// since returns the samples of minutes starting at or after t
+func (h *trafficHistory) since(t time.Time) []TrafficSample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := []TrafficSample{}
+	for _, sample := range h.samples {
+		if !sample.Time.Before(t) {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// save writes the history to its file, replacing it atomically
+func (h *trafficHistory) save() error {
+	h.mu.Lock()
+	data, err := json.Marshal(h.samples)
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create traffic history directory: %w", err)
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write traffic history: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace traffic history: %w", err)
+	}
+	return nil
+}
+
+// ReadTrafficHistory reads the samples of the last d from a stats file
+// written by an agent, for when the agent is not running
+func ReadTrafficHistory(path string, d time.Duration) (*TrafficHistory, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read traffic history: %w", err)
+	}
+	since := time.Now().Add(-d)
+	return &TrafficHistory{Since: since, Samples: newTrafficHistory(path).since(since)}, nil
+}
+
+// TrafficHistory returns the per-minute traffic of the last d
+func (a *Agent) TrafficHistory(d time.Duration) *TrafficHistory {
+	since := time.Now().Add(-d)
+	return &TrafficHistory{Since: since, Samples: a.history.since(since)}
+}
+
+// recordTrafficLoop samples the traffic counters every minute, whether or
+// not the server is reachable, and periodically saves the history
+func (a *Agent) recordTrafficLoop() {
+	// Align samples with wall clock minutes
+	select {
+	case <-a.ctx.Done():
+		return
+	case <-time.After(time.Until(time.Now().Truncate(time.Minute).Add(time.Minute))):
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	lastSave := time.Now()
+
+	for {
+		a.statsMu.RLock()
+		stats := a.stats
+		a.statsMu.RUnlock()
+		now := time.Now()
+		a.history.record(now, stats, a.lastStatus.isConnected())
+
+		if now.Sub(lastSave) >= historySaveInterval {
+			if err := a.history.save(); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			lastSave = now
+		}
+
+		select {
+		case <-a.ctx.Done():
+			if err := a.history.save(); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
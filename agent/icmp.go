@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"net"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+)
+
+// echoReply answers an ICMP echo request to the agent's overlay IP
+// in-process when icmp_responder is enabled. It returns nil for any
+// other packet.
+func (a *Agent) echoReply(payload []byte) []byte {
+	if !a.config.ICMPResponder {
+		return nil
+	}
+	assignedIP, _ := a.overlayAddrs()
+	if !packet.IsEchoRequestTo(payload, net.ParseIP(assignedIP)) {
+		return nil
+	}
+
+	reply, err := packet.EchoReply(payload)
+	if err != nil {
+		return nil
+	}
+	return reply
+}
+
+// validatePacket rejects payloads that are not well-formed IPv4 packets
+// fitting the TUN MTU
+func (a *Agent) validatePacket(payload []byte) error {
+	_, err := packet.Validate(payload, a.tun.MTU())
+	return err
+}
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"fmt"
+	"log"
+
+	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// hardwareKey is an ECDSA P-256 key whose private half never leaves the
+// TPM or Secure Enclave holding it
+type hardwareKey interface {
+	// Provider returns crypto.IdentityTPM2 or crypto.IdentitySecureEnclave
+	Provider() string
+	// PublicKey returns the PKIX encoded public key
+	PublicKey() []byte
+	// Sign returns the ASN.1 ECDSA signature of a SHA-256 digest
+	Sign(digest []byte) ([]byte, error)
+	Close() error
+}
+
+// openIdentity opens the hardware identity key of the agent if
+// hardware_identity is set. It runs before privileges are dropped and the
+// sandbox applied, the TPM device cannot be opened afterwards.
+func (a *Agent) openIdentity() error {
+	if !a.config.HardwareIdentity {
+		return nil
+	}
+	key, err := openHardwareKey(a.agentID)
+	if err != nil {
+		return fmt.Errorf("failed to open hardware identity key: %w", err)
+	}
+	a.identity = key
+	log.Printf("Registering with %s identity key %s", key.Provider(), crypto.IdentityFingerprint(key.PublicKey()))
+	return nil
+}
+
+// signIdentity signs a registration with the hardware identity key over
+// a fresh challenge of the server. Without a key it does nothing.
+func (a *Agent) signIdentity(ctx context.Context, req *proto.RegisterRequest) error {
+	if a.identity == nil {
+		return nil
+	}
+	challenge, err := a.client.GetIdentityChallenge(ctx, &proto.IdentityChallengeRequest{AgentId: a.agentID})
+	if err != nil {
+		return fmt.Errorf("failed to get identity challenge: %w", err)
+	}
+	req.IdentityKey = a.identity.PublicKey()
+	req.IdentityProvider = a.identity.Provider()
+	req.IdentityChallenge = challenge.Challenge
+	req.IdentitySignature, err = a.identity.Sign(crypto.IdentityDigest(req))
+	if err != nil {
+		return fmt.Errorf("failed to sign with the hardware identity key: %w", err)
+	}
+	return nil
+}
+
+// IdentityFingerprint returns the hardware holding the identity key of
+// the agent configured with cfg and the fingerprint the server shows for
+// it, to check a device before or after it registers
+func IdentityFingerprint(cfg *config.AgentConfig) (provider, fingerprint string, err error) {
+	if cfg.AgentID == "" {
+		return "", "", fmt.Errorf("the identity key is derived for the agent ID, set id in the configuration")
+	}
+	key, err := openHardwareKey(cfg.AgentID)
+	if err != nil {
+		return "", "", err
+	}
+	defer key.Close()
+	return key.Provider(), crypto.IdentityFingerprint(key.PublicKey()), nil
+}
+
+// identityPublicKey encodes an uncompressed P-256 point, as TPMs and the
+// Secure Enclave return it, as a PKIX public key
+func identityPublicKey(point []byte) ([]byte, error) {
+	pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+	if err != nil {
+		return nil, fmt.Errorf("invalid identity public key: %w", err)
+	}
+	return x509.MarshalPKIXPublicKey(pub)
+}
@@ -0,0 +1,210 @@
+//go:build darwin && cgo
+
+package agent
+
+/*
+#cgo LDFLAGS: -framework Security -framework CoreFoundation
+#include <Security/Security.h>
+#include <stdint.h>
+#include <stdio.h>
+#include <string.h>
+
+// ealDescribe writes the description of an error to msg and releases it
+static void ealDescribe(CFErrorRef err, char *msg, size_t size) {
+	CFStringRef s = err != NULL ? CFErrorCopyDescription(err) : NULL;
+	if (s == NULL || !CFStringGetCString(s, msg, (CFIndex)size, kCFStringEncodingUTF8)) {
+		strlcpy(msg, "unknown error", size);
+	}
+	if (s != NULL) {
+		CFRelease(s);
+	}
+	if (err != NULL) {
+		CFRelease(err);
+	}
+}
+
+// ealDescribeStatus writes the description of a Security framework status
+// to msg
+static void ealDescribeStatus(OSStatus status, char *msg, size_t size) {
+	CFStringRef s = SecCopyErrorMessageString(status, NULL);
+	if (s == NULL || !CFStringGetCString(s, msg, (CFIndex)size, kCFStringEncodingUTF8)) {
+		snprintf(msg, size, "OSStatus %d", (int)status);
+	}
+	if (s != NULL) {
+		CFRelease(s);
+	}
+}
+
+// ealCopyBytes copies data to out and releases it, returning its length
+// or -1 if it does not fit
+static int ealCopyBytes(CFDataRef data, UInt8 *out, size_t size) {
+	CFIndex n = CFDataGetLength(data);
+	if ((size_t)n > size) {
+		n = -1;
+	} else {
+		CFDataGetBytes(data, CFRangeMake(0, n), out);
+	}
+	CFRelease(data);
+	return (int)n;
+}
+
+// ealOpenKey returns the Secure Enclave key of the keychain tagged tag,
+// creating it the first time, or 0 with the error in msg
+static uintptr_t ealOpenKey(const UInt8 *tag, size_t tagLen, char *msg, size_t msgSize) {
+	CFDataRef tagData = CFDataCreate(NULL, tag, (CFIndex)tagLen);
+	const void *queryKeys[] = {kSecClass, kSecAttrApplicationTag, kSecAttrKeyType, kSecAttrTokenID, kSecReturnRef};
+	const void *queryValues[] = {kSecClassKey, tagData, kSecAttrKeyTypeECSECPrimeRandom, kSecAttrTokenIDSecureEnclave, kCFBooleanTrue};
+	CFDictionaryRef query = CFDictionaryCreate(NULL, queryKeys, queryValues, 5,
+		&kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+	SecKeyRef key = NULL;
+	OSStatus status = SecItemCopyMatching(query, (CFTypeRef *)&key);
+	CFRelease(query);
+	if (status == errSecSuccess) {
+		CFRelease(tagData);
+		return (uintptr_t)key;
+	}
+	if (status != errSecItemNotFound) {
+		CFRelease(tagData);
+		ealDescribeStatus(status, msg, msgSize);
+		return 0;
+	}
+
+	// Only usable on this device, without user interaction
+	CFErrorRef err = NULL;
+	SecAccessControlRef access = SecAccessControlCreateWithFlags(NULL,
+		kSecAttrAccessibleAfterFirstUnlockThisDeviceOnly, kSecAccessControlPrivateKeyUsage, &err);
+	if (access == NULL) {
+		CFRelease(tagData);
+		ealDescribe(err, msg, msgSize);
+		return 0;
+	}
+	const void *privateKeys[] = {kSecAttrIsPermanent, kSecAttrApplicationTag, kSecAttrAccessControl};
+	const void *privateValues[] = {kCFBooleanTrue, tagData, access};
+	CFDictionaryRef privateAttrs = CFDictionaryCreate(NULL, privateKeys, privateValues, 3,
+		&kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+	int bits = 256;
+	CFNumberRef size = CFNumberCreate(NULL, kCFNumberIntType, &bits);
+	const void *attrKeys[] = {kSecAttrKeyType, kSecAttrKeySizeInBits, kSecAttrTokenID, kSecPrivateKeyAttrs};
+	const void *attrValues[] = {kSecAttrKeyTypeECSECPrimeRandom, size, kSecAttrTokenIDSecureEnclave, privateAttrs};
+	CFDictionaryRef attrs = CFDictionaryCreate(NULL, attrKeys, attrValues, 4,
+		&kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+	key = SecKeyCreateRandomKey(attrs, &err);
+	CFRelease(attrs);
+	CFRelease(size);
+	CFRelease(privateAttrs);
+	CFRelease(access);
+	CFRelease(tagData);
+	if (key == NULL) {
+		ealDescribe(err, msg, msgSize);
+		return 0;
+	}
+	return (uintptr_t)key;
+}
+
+// ealPublicKey writes the X9.63 public key of key to out, returning its
+// length or -1 with the error in msg
+static int ealPublicKey(uintptr_t key, UInt8 *out, size_t size, char *msg, size_t msgSize) {
+	SecKeyRef pub = SecKeyCopyPublicKey((SecKeyRef)key);
+	if (pub == NULL) {
+		strlcpy(msg, "no public key", msgSize);
+		return -1;
+	}
+	CFErrorRef err = NULL;
+	CFDataRef data = SecKeyCopyExternalRepresentation(pub, &err);
+	CFRelease(pub);
+	if (data == NULL) {
+		ealDescribe(err, msg, msgSize);
+		return -1;
+	}
+	return ealCopyBytes(data, out, size);
+}
+
+// ealSign writes the ASN.1 ECDSA signature of a SHA-256 digest to out,
+// returning its length or -1 with the error in msg
+static int ealSign(uintptr_t key, const UInt8 *digest, size_t digestLen, UInt8 *out, size_t size, char *msg, size_t msgSize) {
+	CFDataRef data = CFDataCreate(NULL, digest, (CFIndex)digestLen);
+	CFErrorRef err = NULL;
+	CFDataRef sig = SecKeyCreateSignature((SecKeyRef)key, kSecKeyAlgorithmECDSASignatureDigestX962SHA256, data, &err);
+	CFRelease(data);
+	if (sig == NULL) {
+		ealDescribe(err, msg, msgSize);
+		return -1;
+	}
+	return ealCopyBytes(sig, out, size);
+}
+
+static void ealRelease(uintptr_t key) {
+	CFRelease((SecKeyRef)key);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+)
+
+// enclaveKey is an identity key in the Secure Enclave, kept in the
+// keychain under a tag derived from the agent ID. Storing it needs a
+// binary signed with a keychain-access-groups entitlement.
+type enclaveKey struct {
+	mu     sync.Mutex // guards ref
+	ref    C.uintptr_t
+	public []byte // PKIX
+}
+
+func openHardwareKey(agentID string) (hardwareKey, error) {
+	tag := []byte("com.easyanylink.identity." + agentID)
+	var msg [256]C.char
+	ref := C.ealOpenKey((*C.UInt8)(unsafe.Pointer(&tag[0])), C.size_t(len(tag)), &msg[0], C.size_t(len(msg)))
+	if ref == 0 {
+		return nil, fmt.Errorf("failed to open Secure Enclave key: %s", C.GoString(&msg[0]))
+	}
+
+	point := make([]byte, 128)
+	n := C.ealPublicKey(ref, (*C.UInt8)(unsafe.Pointer(&point[0])), C.size_t(len(point)), &msg[0], C.size_t(len(msg)))
+	if n < 0 {
+		C.ealRelease(ref)
+		return nil, fmt.Errorf("failed to read Secure Enclave public key: %s", C.GoString(&msg[0]))
+	}
+	public, err := identityPublicKey(point[:n])
+	if err != nil {
+		C.ealRelease(ref)
+		return nil, err
+	}
+	return &enclaveKey{ref: ref, public: public}, nil
+}
+
+func (k *enclaveKey) Provider() string  { return crypto.IdentitySecureEnclave }
+func (k *enclaveKey) PublicKey() []byte { return k.public }
+
+func (k *enclaveKey) Sign(digest []byte) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.ref == 0 {
+		return nil, errors.New("Secure Enclave key is closed")
+	}
+
+	var msg [256]C.char
+	sig := make([]byte, 128)
+	n := C.ealSign(k.ref, (*C.UInt8)(unsafe.Pointer(&digest[0])), C.size_t(len(digest)),
+		(*C.UInt8)(unsafe.Pointer(&sig[0])), C.size_t(len(sig)), &msg[0], C.size_t(len(msg)))
+	if n < 0 {
+		return nil, fmt.Errorf("Secure Enclave signing failed: %s", C.GoString(&msg[0]))
+	}
+	return sig[:n], nil
+}
+
+func (k *enclaveKey) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.ref != 0 {
+		C.ealRelease(k.ref)
+		k.ref = 0
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !windows && !(darwin && cgo)
+
+package agent
+
+import "errors"
+
+func openHardwareKey(agentID string) (hardwareKey, error) {
+	return nil, errors.New("hardware identity keys need a TPM 2.0 on Linux or Windows, or the Secure Enclave with a cgo build on macOS")
+}
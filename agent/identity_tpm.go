@@ -0,0 +1,250 @@
+//go:build linux || windows
+
+package agent
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+)
+
+// TPM 2.0 constants, see the TPM 2.0 Library specification part 2
+const (
+	tpmSTNoSessions = 0x8001
+	tpmSTSessions   = 0x8002
+	tpmSTHashcheck  = 0x8024
+
+	tpmCCCreatePrimary = 0x00000131
+	tpmCCSign          = 0x0000015d
+	tpmCCFlushContext  = 0x00000165
+
+	tpmRHOwner = 0x40000001
+	tpmRHNull  = 0x40000007
+	tpmRSPW    = 0x40000009
+
+	tpmAlgECC    = 0x0023
+	tpmAlgSHA256 = 0x000b
+	tpmAlgNull   = 0x0010
+	tpmAlgECDSA  = 0x0018
+	tpmECCP256   = 0x0003
+
+	// fixedTPM | fixedParent | sensitiveDataOrigin | userWithAuth | sign:
+	// a signing key that cannot be duplicated out of the TPM
+	tpmIdentityAttributes = 1<<1 | 1<<4 | 1<<5 | 1<<6 | 1<<18
+)
+
+// tpmTransport sends TPM 2.0 commands to the TPM
+type tpmTransport interface {
+	transmit(cmd []byte) ([]byte, error)
+	Close() error
+}
+
+// tpmKey is an identity key in a TPM 2.0. It is a primary key under the
+// owner hierarchy: the TPM derives it from the hierarchy seed and a
+// template unique to the agent ID, so nothing is stored between runs and
+// the same key comes back every time, until the TPM is cleared.
+type tpmKey struct {
+	mu     sync.Mutex // serializes commands
+	tpm    tpmTransport
+	unique []byte // of the template, from the agent ID
+	public []byte // PKIX
+}
+
+func openHardwareKey(agentID string) (hardwareKey, error) {
+	tpm, err := openTPM()
+	if err != nil {
+		return nil, err
+	}
+	unique := sha256.Sum256([]byte("easyanylink-identity/" + agentID))
+	k := &tpmKey{tpm: tpm, unique: unique[:]}
+	handle, public, err := k.createPrimary()
+	if err != nil {
+		tpm.Close()
+		return nil, err
+	}
+	k.flush(handle)
+	k.public = public
+	return k, nil
+}
+
+func (k *tpmKey) Provider() string  { return crypto.IdentityTPM2 }
+func (k *tpmKey) PublicKey() []byte { return k.public }
+func (k *tpmKey) Close() error      { return k.tpm.Close() }
+
+// Sign loads the key, signs and unloads it again, the TPM has few slots
+func (k *tpmKey) Sign(digest []byte) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	handle, public, err := k.createPrimary()
+	if err != nil {
+		return nil, err
+	}
+	defer k.flush(handle)
+	if !bytes.Equal(public, k.public) {
+		return nil, errors.New("the TPM returned a different key, it was cleared")
+	}
+
+	params := tpm2B(digest)
+	params = binary.BigEndian.AppendUint16(params, tpmAlgECDSA)
+	params = binary.BigEndian.AppendUint16(params, tpmAlgSHA256)
+	// A NULL ticket, the key is not restricted
+	params = binary.BigEndian.AppendUint16(params, tpmSTHashcheck)
+	params = binary.BigEndian.AppendUint32(params, tpmRHNull)
+	params = binary.BigEndian.AppendUint16(params, 0)
+
+	resp, err := k.run(tpmCommand(tpmCCSign, handle, params))
+	if err != nil {
+		return nil, fmt.Errorf("TPM2_Sign: %w", err)
+	}
+	r := tpmReader{b: resp}
+	r.uint32() // parameterSize
+	r.uint16() // sigAlg
+	r.uint16() // hash
+	sigR, sigS := r.sized(), r.sized()
+	if r.err != nil {
+		return nil, fmt.Errorf("TPM2_Sign: %w", r.err)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(sigR), new(big.Int).SetBytes(sigS)})
+}
+
+// createPrimary loads the key, returning its handle and PKIX public key
+func (k *tpmKey) createPrimary() (uint32, []byte, error) {
+	var template []byte
+	template = binary.BigEndian.AppendUint16(template, tpmAlgECC)
+	template = binary.BigEndian.AppendUint16(template, tpmAlgSHA256)
+	template = binary.BigEndian.AppendUint32(template, tpmIdentityAttributes)
+	template = binary.BigEndian.AppendUint16(template, 0) // authPolicy
+	template = binary.BigEndian.AppendUint16(template, tpmAlgNull)
+	template = binary.BigEndian.AppendUint16(template, tpmAlgECDSA)
+	template = binary.BigEndian.AppendUint16(template, tpmAlgSHA256)
+	template = binary.BigEndian.AppendUint16(template, tpmECCP256)
+	template = binary.BigEndian.AppendUint16(template, tpmAlgNull) // kdf
+	template = append(template, tpm2B(k.unique)...)
+	template = binary.BigEndian.AppendUint16(template, 0)
+
+	params := tpm2B([]byte{0, 0, 0, 0}) // empty userAuth and data
+	params = append(params, tpm2B(template)...)
+	params = binary.BigEndian.AppendUint16(params, 0) // outsideInfo
+	params = binary.BigEndian.AppendUint32(params, 0) // creationPCR
+
+	resp, err := k.run(tpmCommand(tpmCCCreatePrimary, tpmRHOwner, params))
+	if err != nil {
+		return 0, nil, fmt.Errorf("TPM2_CreatePrimary: %w", err)
+	}
+	r := tpmReader{b: resp}
+	handle := r.uint32()
+	r.uint32() // parameterSize
+	r.uint16() // size of outPublic
+	r.skip(8)  // type, nameAlg, objectAttributes
+	r.sized()  // authPolicy
+	r.skip(10) // the NULL symmetric, ECDSA SHA256 scheme, curve and NULL kdf of the template
+	x, y := r.sized(), r.sized()
+	if r.err == nil && (len(x) > 32 || len(y) > 32) {
+		r.err = errors.New("malformed public key")
+	}
+	if r.err != nil {
+		k.flush(handle)
+		return 0, nil, fmt.Errorf("TPM2_CreatePrimary: %w", r.err)
+	}
+
+	point := make([]byte, 65)
+	point[0] = 4
+	copy(point[33-len(x):33], x)
+	copy(point[65-len(y):], y)
+	public, err := identityPublicKey(point)
+	if err != nil {
+		k.flush(handle)
+		return 0, nil, err
+	}
+	return handle, public, nil
+}
+
+// flush unloads the key
+func (k *tpmKey) flush(handle uint32) {
+	cmd := binary.BigEndian.AppendUint16(nil, tpmSTNoSessions)
+	cmd = binary.BigEndian.AppendUint32(cmd, 14)
+	cmd = binary.BigEndian.AppendUint32(cmd, tpmCCFlushContext)
+	cmd = binary.BigEndian.AppendUint32(cmd, handle)
+	k.run(cmd)
+}
+
+// run sends a command, returning the response after its header
+func (k *tpmKey) run(cmd []byte) ([]byte, error) {
+	resp, err := k.tpm.transmit(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 10 || int(binary.BigEndian.Uint32(resp[2:6])) != len(resp) {
+		return nil, errors.New("malformed TPM response")
+	}
+	if rc := binary.BigEndian.Uint32(resp[6:10]); rc != 0 {
+		return nil, fmt.Errorf("TPM error 0x%03x", rc)
+	}
+	return resp[10:], nil
+}
+
+// tpmCommand frames a command on one handle, authorized with an empty
+// password, as the owner hierarchy and the identity key have
+func tpmCommand(code, handle uint32, params []byte) []byte {
+	cmd := binary.BigEndian.AppendUint16(nil, tpmSTSessions)
+	cmd = binary.BigEndian.AppendUint32(cmd, 0) // size, set below
+	cmd = binary.BigEndian.AppendUint32(cmd, code)
+	cmd = binary.BigEndian.AppendUint32(cmd, handle)
+	cmd = binary.BigEndian.AppendUint32(cmd, 9)       // authorizationSize
+	cmd = binary.BigEndian.AppendUint32(cmd, tpmRSPW) // password session
+	cmd = append(cmd, 0, 0, 0, 0, 0)                  // empty nonce, no attributes, empty password
+	cmd = append(cmd, params...)
+	binary.BigEndian.PutUint32(cmd[2:6], uint32(len(cmd)))
+	return cmd
+}
+
+// tpm2B encodes a sized buffer
+func tpm2B(b []byte) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(b))), b...)
+}
+
+// tpmReader reads a response, remembering the first error
+type tpmReader struct {
+	b   []byte
+	err error
+}
+
+func (r *tpmReader) skip(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.b) < n {
+		r.err = errors.New("truncated TPM response")
+		return nil
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *tpmReader) uint16() uint16 {
+	if b := r.skip(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *tpmReader) uint32() uint32 {
+	if b := r.skip(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+// sized reads a sized buffer
+func (r *tpmReader) sized() []byte {
+	return r.skip(int(r.uint16()))
+}
@@ -0,0 +1,52 @@
+//go:build linux
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// tpmDevice is the TPM character device
+type tpmDevice struct {
+	f *os.File
+}
+
+// openTPM opens the TPM through the kernel resource manager, which lets
+// the agent share it with other users, or directly on older kernels. An
+// agent running as a user needs to be in the tss group.
+func openTPM() (tpmTransport, error) {
+	var err error
+	for _, path := range []string{"/dev/tpmrm0", "/dev/tpm0"} {
+		var f *os.File
+		if f, err = os.OpenFile(path, os.O_RDWR, 0); err == nil {
+			return &tpmDevice{f: f}, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.New("no TPM 2.0 found")
+	}
+	return nil, fmt.Errorf("failed to open the TPM: %w", err)
+}
+
+func (d *tpmDevice) transmit(cmd []byte) ([]byte, error) {
+	if _, err := d.f.Write(cmd); err != nil {
+		return nil, fmt.Errorf("failed to send TPM command: %w", err)
+	}
+	// The device returns a whole response per read
+	resp := make([]byte, 4096)
+	n, err := d.f.Read(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TPM response: %w", err)
+	}
+	return resp[:n], nil
+}
+
+func (d *tpmDevice) Close() error {
+	return d.f.Close()
+}
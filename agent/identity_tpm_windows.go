@@ -0,0 +1,73 @@
+//go:build windows
+
+package agent
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modTbs                 = windows.NewLazySystemDLL("tbs.dll")
+	procTbsiContextCreate  = modTbs.NewProc("Tbsi_Context_Create")
+	procTbsipSubmitCommand = modTbs.NewProc("Tbsip_Submit_Command")
+	procTbsipContextClose  = modTbs.NewProc("Tbsip_Context_Close")
+)
+
+const (
+	tbsContextVersionTwo     = 2
+	tbsContextIncludeTPM20   = 1 << 2
+	tbsCommandLocalityZero   = 0
+	tbsCommandPriorityNormal = 200
+)
+
+// tbsContextParams2 is TBS_CONTEXT_PARAMS2
+type tbsContextParams2 struct {
+	version uint32
+	flags   uint32
+}
+
+// tbsContext is a context of the TPM Base Services, which share the TPM
+// between processes
+type tbsContext struct {
+	handle uintptr
+}
+
+// openTPM opens a TPM 2.0 context of the TPM Base Services
+func openTPM() (tpmTransport, error) {
+	if err := procTbsiContextCreate.Find(); err != nil {
+		return nil, fmt.Errorf("TPM Base Services unavailable: %w", err)
+	}
+	params := tbsContextParams2{version: tbsContextVersionTwo, flags: tbsContextIncludeTPM20}
+	var handle uintptr
+	r0, _, _ := procTbsiContextCreate.Call(uintptr(unsafe.Pointer(&params)), uintptr(unsafe.Pointer(&handle)))
+	if r0 != 0 {
+		return nil, fmt.Errorf("no TPM 2.0 found: Tbsi_Context_Create returned 0x%x", r0)
+	}
+	return &tbsContext{handle: handle}, nil
+}
+
+func (c *tbsContext) transmit(cmd []byte) ([]byte, error) {
+	resp := make([]byte, 4096)
+	size := uint32(len(resp))
+	r0, _, _ := procTbsipSubmitCommand.Call(
+		c.handle,
+		tbsCommandLocalityZero,
+		tbsCommandPriorityNormal,
+		uintptr(unsafe.Pointer(&cmd[0])),
+		uintptr(len(cmd)),
+		uintptr(unsafe.Pointer(&resp[0])),
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if r0 != 0 {
+		return nil, fmt.Errorf("failed to send TPM command: Tbsip_Submit_Command returned 0x%x", r0)
+	}
+	return resp[:size], nil
+}
+
+func (c *tbsContext) Close() error {
+	procTbsipContextClose.Call(c.handle)
+	return nil
+}
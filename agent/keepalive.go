@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"errors"
+	"log"
+	"math/rand/v2"
+	"time"
+)
+
+// Heartbeat settings used until the server sends its own
+const (
+	defaultKeepaliveInterval = 30 * time.Second
+	defaultKeepaliveTimeout  = 90 * time.Second
+)
+
+// errNoHeartbeat ends a session whose heartbeats went unanswered
+var errNoHeartbeat = errors.New("no heartbeat response")
+
+// keepalive returns the heartbeat interval and the time without a
+// heartbeat response after which the link is considered dead
+func (a *Agent) keepalive() (interval, timeout time.Duration) {
+	a.keepaliveMu.Lock()
+	defer a.keepaliveMu.Unlock()
+
+	interval, timeout = a.keepaliveInterval, a.keepaliveTimeout
+	if interval == 0 {
+		interval = defaultKeepaliveInterval
+	}
+	if timeout == 0 {
+		timeout = defaultKeepaliveTimeout
+	}
+	return interval, timeout
+}
+
+// setKeepalive applies heartbeat settings from the server in seconds, 0
+// keeps a value. It reports whether they changed. A timeout that would
+// expire before the next heartbeat is raised to three intervals.
+func (a *Agent) setKeepalive(intervalSeconds, timeoutSeconds int32) bool {
+	if intervalSeconds <= 0 && timeoutSeconds <= 0 {
+		return false
+	}
+	current, currentTimeout := a.keepalive()
+
+	interval, timeout := current, currentTimeout
+	if intervalSeconds > 0 {
+		interval = time.Duration(intervalSeconds) * time.Second
+	}
+	if timeoutSeconds > 0 {
+		timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+	if timeout <= interval {
+		timeout = 3 * interval
+	}
+	if interval == current && timeout == currentTimeout {
+		return false
+	}
+
+	a.keepaliveMu.Lock()
+	a.keepaliveInterval, a.keepaliveTimeout = interval, timeout
+	a.keepaliveMu.Unlock()
+	log.Printf("Heartbeat interval %s, dead-link timeout %s", interval, timeout)
+	return true
+}
+
+// heartbeatDelay returns the time until the next heartbeat. With jitter
+// it is random around interval, so that heartbeats do not form a regular
+// pattern, yet always comes before timeout.
+func heartbeatDelay(interval, timeout time.Duration, jitter bool) time.Duration {
+	spread := min(interval, timeout-interval)
+	if !jitter || spread <= 0 {
+		return interval
+	}
+	return interval - spread/2 + rand.N(spread)
+}
@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+)
+
+// fullTunnelCIDR marks a forward rule that sends all traffic through the overlay
+const fullTunnelCIDR = "0.0.0.0/0"
+
+// killSwitchPolicy describes which traffic the kill switch blocks outside the tunnel
+type killSwitchPolicy struct {
+	TunIface     string       // Traffic on the tunnel interface is always allowed
+	Servers      []endpoint   // Server endpoints stay reachable for reconnects
+	Destinations []string     // Blocked outside the tunnel, ignored in full-tunnel mode
+	FullTunnel   bool         // Block everything outside the tunnel
+	LocalSubnets []*net.IPNet // Always allowed (allow_lan)
+}
+
+// updateKillSwitch (re)installs the kill switch for the current forward routes
+func (a *Agent) updateKillSwitch() error {
+	if a.killSwitch == nil || a.killSwitchPaused {
+		return nil
+	}
+
+	policy, err := a.killSwitchPolicy()
+	if err != nil {
+		return err
+	}
+
+	if err := a.killSwitch.Enable(policy); err != nil {
+		return fmt.Errorf("failed to enable kill switch: %w", err)
+	}
+
+	if policy.FullTunnel {
+		log.Printf("Kill switch enabled: blocking all traffic outside %s", policy.TunIface)
+	} else {
+		log.Printf("Kill switch enabled: blocking %d destination(s) outside %s",
+			len(policy.Destinations), policy.TunIface)
+	}
+	return nil
+}
+
+// killSwitchPolicy builds the kill switch policy from forward routes
+func (a *Agent) killSwitchPolicy() (*killSwitchPolicy, error) {
+	servers, err := a.serverEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &killSwitchPolicy{
+		TunIface:     a.tun.Name(),
+		Servers:      servers,
+		LocalSubnets: a.lanSubnets(),
+	}
+
+	seen := make(map[string]bool)
+	addDestination := func(destination string) {
+		if destination == fullTunnelCIDR {
+			policy.FullTunnel = true
+			return
+		}
+		if !seen[destination] {
+			seen[destination] = true
+			policy.Destinations = append(policy.Destinations, destination)
+		}
+	}
+
+	for _, rule := range a.config.Rules {
+		if rule.Action == "forward" {
+			addDestination(rule.Destination)
+		}
+	}
+	for destination := range a.serverRoutes {
+		addDestination(destination)
+	}
+
+	return policy, nil
+}
+
+// endpoint is a resolved server address
+type endpoint struct {
+	IP   net.IP
+	Port int
+}
+
+// serverEndpoints resolves the addresses of all configured and announced
+// servers. Servers that cannot be resolved are skipped unless none can.
+func (a *Agent) serverEndpoints() ([]endpoint, error) {
+	a.serversMu.Lock()
+	servers := a.serverList()
+	a.serversMu.Unlock()
+
+	var endpoints []endpoint
+	var lastErr error
+	for _, server := range servers {
+		host, portStr, err := net.SplitHostPort(server)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server address: %w", err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server port: %w", err)
+		}
+
+		ips, err := net.LookupIP(host)
+		if err != nil {
+			log.Printf("Warning: failed to resolve server %s: %v", host, err)
+			lastErr = err
+			continue
+		}
+		for _, ip := range ips {
+			endpoints = append(endpoints, endpoint{IP: ip, Port: port})
+		}
+	}
+
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("failed to resolve server address: %w", lastErr)
+	}
+	return endpoints, nil
+}
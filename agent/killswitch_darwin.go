@@ -0,0 +1,83 @@
+//go:build darwin
+
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// killSwitchAnchor is the pf anchor holding the kill switch rules. The
+// default /etc/pf.conf evaluates "com.apple/*", so no system config change
+// is needed.
+const killSwitchAnchor = "com.apple/easyanylink"
+
+var pfTokenRegexp = regexp.MustCompile(`Token : (\d+)`)
+
+// KillSwitch blocks traffic leaking outside the tunnel using pf
+type KillSwitch struct {
+	token string // pf enable reference, released on Disable
+}
+
+// NewKillSwitch creates a new kill switch
+func NewKillSwitch() *KillSwitch {
+	return &KillSwitch{}
+}
+
+// Enable installs or replaces the kill switch rules
+func (k *KillSwitch) Enable(policy *killSwitchPolicy) error {
+	var rules strings.Builder
+	fmt.Fprintf(&rules, "pass out quick on lo0 all\n")
+	fmt.Fprintf(&rules, "pass out quick on %s all\n", policy.TunIface)
+	fmt.Fprintf(&rules, "pass out quick proto udp from any port 68 to any port 67\n")
+	for _, server := range policy.Servers {
+		fmt.Fprintf(&rules, "pass out quick proto udp to %s port %d\n", server.IP, server.Port)
+	}
+	for _, subnet := range policy.LocalSubnets {
+		fmt.Fprintf(&rules, "pass out quick to %s\n", subnet)
+	}
+	if policy.FullTunnel {
+		fmt.Fprintf(&rules, "block return out quick all\n")
+	} else {
+		for _, destination := range policy.Destinations {
+			fmt.Fprintf(&rules, "block return out quick to %s\n", destination)
+		}
+	}
+
+	cmd := exec.Command("pfctl", "-a", killSwitchAnchor, "-f", "-")
+	cmd.Stdin = strings.NewReader(rules.String())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to load pf rules: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	// Enable pf with a reference so other users of pf are not affected
+	if k.token == "" {
+		output, err := exec.Command("pfctl", "-E").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to enable pf: %w", err)
+		}
+		if m := pfTokenRegexp.FindSubmatch(output); m != nil {
+			k.token = string(m[1])
+		}
+	}
+
+	return nil
+}
+
+// Disable removes the kill switch rules
+func (k *KillSwitch) Disable() error {
+	if err := exec.Command("pfctl", "-a", killSwitchAnchor, "-F", "all").Run(); err != nil {
+		return fmt.Errorf("failed to flush pf anchor: %w", err)
+	}
+
+	if k.token != "" {
+		if err := exec.Command("pfctl", "-X", k.token).Run(); err != nil {
+			return fmt.Errorf("failed to release pf reference: %w", err)
+		}
+		k.token = ""
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+//go:build linux
+
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// killSwitchChain is the iptables and ip6tables chain holding the kill
+// switch rules
+const killSwitchChain = "EASYANYLINK-KILLSWITCH"
+
+// KillSwitch blocks traffic leaking outside the tunnel using iptables for
+// IPv4 and ip6tables for IPv6
+type KillSwitch struct{}
+
+// NewKillSwitch creates a new kill switch
+func NewKillSwitch() *KillSwitch {
+	return &KillSwitch{}
+}
+
+// Enable installs or replaces the kill switch rules
+func (k *KillSwitch) Enable(policy *killSwitchPolicy) error {
+	probe := []string{"-m", "mark", "--mark", fmt.Sprintf("0x%x", captiveProbeMark), "-j", "RETURN"}
+	v4 := [][]string{
+		{"-o", "lo", "-j", "RETURN"},
+		{"-o", policy.TunIface, "-j", "RETURN"},
+		// Captive portal probes, see probeControl
+		probe,
+		// Keep DHCP working on the physical interface
+		{"-p", "udp", "--dport", "67:68", "-j", "RETURN"},
+	}
+	v6 := [][]string{
+		{"-o", "lo", "-j", "RETURN"},
+		{"-o", policy.TunIface, "-j", "RETURN"},
+		probe,
+		// Keep DHCPv6, router solicitation and neighbor discovery working
+		{"-p", "udp", "--dport", "546:547", "-j", "RETURN"},
+		{"-p", "ipv6-icmp", "--icmpv6-type", "router-solicitation", "-j", "RETURN"},
+		{"-p", "ipv6-icmp", "--icmpv6-type", "neighbor-solicitation", "-j", "RETURN"},
+		{"-p", "ipv6-icmp", "--icmpv6-type", "neighbor-advertisement", "-j", "RETURN"},
+	}
+
+	for _, server := range policy.Servers {
+		rule := []string{"-d", server.IP.String(), "-p", "udp",
+			"--dport", strconv.Itoa(server.Port), "-j", "RETURN"}
+		if server.IP.To4() != nil {
+			v4 = append(v4, rule)
+		} else {
+			v6 = append(v6, rule)
+		}
+	}
+	for _, subnet := range policy.LocalSubnets {
+		rule := []string{"-d", subnet.String(), "-j", "RETURN"}
+		if subnet.IP.To4() != nil {
+			v4 = append(v4, rule)
+		} else {
+			v6 = append(v6, rule)
+		}
+	}
+
+	if policy.FullTunnel {
+		// The overlay carries IPv4 only, so all IPv6 would leave outside it
+		v4 = append(v4, []string{"-j", "REJECT", "--reject-with", "icmp-net-unreachable"})
+		v6 = append(v6, []string{"-j", "REJECT", "--reject-with", "icmp6-no-route"})
+	} else {
+		for _, destination := range policy.Destinations {
+			if strings.Contains(destination, ":") {
+				v6 = append(v6, []string{"-d", destination, "-j", "REJECT", "--reject-with", "icmp6-no-route"})
+			} else {
+				v4 = append(v4, []string{"-d", destination, "-j", "REJECT", "--reject-with", "icmp-net-unreachable"})
+			}
+		}
+	}
+
+	if err := installChain("iptables", v4); err != nil {
+		return err
+	}
+	return installChain("ip6tables", v6)
+}
+
+// Disable removes the kill switch rules. It does not depend on Enable
+// having been called, so it also removes chains left behind by an agent
+// that crashed.
+func (k *KillSwitch) Disable() error {
+	if err := removeChain("iptables"); err != nil {
+		return err
+	}
+	return removeChain("ip6tables")
+}
+
+// installChain replaces the rules of the kill switch chain of an
+// iptables command and hooks the chain into OUTPUT
+func installChain(command string, rules [][]string) error {
+	// -N fails if the chain already exists
+	privCommand(command, "-N", killSwitchChain).Run()
+
+	if err := ipTables(command, "-F", killSwitchChain); err != nil {
+		return err
+	}
+
+	// Jump to the chain first in OUTPUT, once
+	if privCommand(command, "-C", "OUTPUT", "-j", killSwitchChain).Run() != nil {
+		if err := ipTables(command, "-I", "OUTPUT", "1", "-j", killSwitchChain); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range rules {
+		if err := ipTables(command, append([]string{"-A", killSwitchChain}, rule...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeChain unhooks and deletes the kill switch chain of an iptables
+// command if it exists
+func removeChain(command string) error {
+	if privCommand(command, "-n", "-L", killSwitchChain).Run() != nil {
+		return nil
+	}
+
+	// Remove every jump, a crashed agent may have left several
+	for {
+		if privCommand(command, "-D", "OUTPUT", "-j", killSwitchChain).Run() != nil {
+			break
+		}
+	}
+	if err := ipTables(command, "-F", killSwitchChain); err != nil {
+		return err
+	}
+	return ipTables(command, "-X", killSwitchChain)
+}
+
+// iptables runs an iptables command
+func iptables(args ...string) error {
+	return ipTables("iptables", args...)
+}
+
+// ipTables runs an iptables or ip6tables command
+func ipTables(command string, args ...string) error {
+	output, err := privCommand(command, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", command, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
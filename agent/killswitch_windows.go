@@ -0,0 +1,135 @@
+//go:build windows
+
+package agent
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+)
+
+// killSwitchRule is the Windows Firewall rule name of the kill switch
+const killSwitchRule = "EasyAnyLink Kill Switch"
+
+// KillSwitch blocks traffic leaking outside the tunnel using Windows Firewall.
+// Block rules are limited to physical interface types so the Wintun adapter
+// keeps working.
+type KillSwitch struct{}
+
+// NewKillSwitch creates a new kill switch
+func NewKillSwitch() *KillSwitch {
+	return &KillSwitch{}
+}
+
+// Enable installs or replaces the kill switch rules
+func (k *KillSwitch) Enable(policy *killSwitchPolicy) error {
+	k.deleteRules()
+
+	remoteIP := blockedRanges(policy)
+	if remoteIP == "" {
+		return nil
+	}
+
+	for _, ifType := range []string{"lan", "wireless"} {
+		// netsh advfirewall firewall add rule name="EasyAnyLink Kill Switch" dir=out action=block ...
+		cmd := exec.Command("netsh", "advfirewall", "firewall", "add", "rule",
+			"name="+killSwitchRule, "dir=out", "action=block",
+			"interfacetype="+ifType, "remoteip="+remoteIP)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to add firewall rule: %w: %s", err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	return nil
+}
+
+// Disable removes the kill switch rules
+func (k *KillSwitch) Disable() error {
+	k.deleteRules()
+	return nil
+}
+
+// deleteRules removes all firewall rules named killSwitchRule
+func (k *KillSwitch) deleteRules() {
+	exec.Command("netsh", "advfirewall", "firewall", "delete", "rule", "name="+killSwitchRule).Run()
+}
+
+// ipRange is an inclusive IPv4 address range
+type ipRange struct {
+	start, end uint64
+}
+
+// blockedRanges returns the IPv4 ranges to block in netsh remoteip syntax.
+// Windows Firewall block rules win over allow rules, so the server endpoint
+// and local subnets are carved out of the blocked ranges instead.
+func blockedRanges(policy *killSwitchPolicy) string {
+	var blocked []ipRange
+	if policy.FullTunnel {
+		blocked = []ipRange{{0, 0xffffffff}}
+	} else {
+		for _, destination := range policy.Destinations {
+			if _, ipNet, err := net.ParseCIDR(destination); err == nil {
+				if r, ok := subnetRange(ipNet); ok {
+					blocked = append(blocked, r)
+				}
+			}
+		}
+	}
+
+	var allowed []ipRange
+	for _, server := range policy.Servers {
+		if r, ok := subnetRange(&net.IPNet{IP: server.IP, Mask: net.CIDRMask(32, 32)}); ok {
+			allowed = append(allowed, r)
+		}
+	}
+	for _, subnet := range policy.LocalSubnets {
+		if r, ok := subnetRange(subnet); ok {
+			allowed = append(allowed, r)
+		}
+	}
+
+	for _, hole := range allowed {
+		var next []ipRange
+		for _, r := range blocked {
+			if hole.end < r.start || hole.start > r.end {
+				next = append(next, r)
+				continue
+			}
+			if r.start < hole.start {
+				next = append(next, ipRange{r.start, hole.start - 1})
+			}
+			if r.end > hole.end {
+				next = append(next, ipRange{hole.end + 1, r.end})
+			}
+		}
+		blocked = next
+	}
+
+	parts := make([]string, 0, len(blocked))
+	for _, r := range blocked {
+		parts = append(parts, uint32ToIP(uint32(r.start))+"-"+uint32ToIP(uint32(r.end)))
+	}
+	return strings.Join(parts, ",")
+}
+
+// subnetRange converts an IPv4 subnet to an address range
+func subnetRange(ipNet *net.IPNet) (ipRange, bool) {
+	ip4 := ipNet.IP.To4()
+	if ip4 == nil {
+		return ipRange{}, false
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits != 32 {
+		return ipRange{}, false
+	}
+	start := uint64(binary.BigEndian.Uint32(ip4.Mask(ipNet.Mask)))
+	return ipRange{start, start + (1 << uint(32-ones)) - 1}, true
+}
+
+func uint32ToIP(v uint32) string {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, v)
+	return ip.String()
+}
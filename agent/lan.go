@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+	"net"
+)
+
+// localSubnets returns the IPv4 subnets directly attached to physical
+// interfaces, i.e. the LAN the agent is sitting on
+func localSubnets(tunName string) ([]*net.IPNet, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interfaces: %w", err)
+	}
+
+	var subnets []*net.IPNet
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 || iface.Name == tunName {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+			if !ipNet.IP.IsPrivate() && !ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			subnets = append(subnets, &net.IPNet{IP: ipNet.IP.Mask(ipNet.Mask), Mask: ipNet.Mask})
+		}
+	}
+
+	return subnets, nil
+}
+
+// lanSubnets returns the local subnets when allow_lan is enabled
+func (a *Agent) lanSubnets() []*net.IPNet {
+	if !a.config.AllowLAN || a.tun == nil {
+		return nil
+	}
+
+	subnets, err := localSubnets(a.tun.Name())
+	if err != nil {
+		log.Printf("Warning: failed to detect local subnets: %v", err)
+		return nil
+	}
+	return subnets
+}
+
+// shadowsLAN reports whether a forward route for destination would take
+// precedence over the connected route of a local subnet. Broader routes
+// such as 0.0.0.0/0 lose to the connected route and are allowed.
+func (a *Agent) shadowsLAN(destination string) bool {
+	_, dst, err := net.ParseCIDR(destination)
+	if err != nil {
+		return false
+	}
+	dstOnes, _ := dst.Mask.Size()
+
+	for _, subnet := range a.lanSubnets() {
+		ones, _ := subnet.Mask.Size()
+		if dstOnes >= ones && (subnet.Contains(dst.IP) || dst.Contains(subnet.IP)) {
+			log.Printf("Skipping route %s: overlaps local subnet %s (allow_lan)", destination, subnet)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package agent
+
+import (
+	"cmp"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyProbes is the number of handshakes timed per server. The
+	// fastest counts, so that one delayed packet does not move the agent.
+	latencyProbes = 3
+
+	// A server must answer at least latencyMinGain and latencyMinRatio of
+	// the current server's handshake time faster for the session to move
+	latencyMinGain  = 10 * time.Millisecond
+	latencyMinRatio = 5 // 20%
+)
+
+// latencySelection reports whether the agent connects to the server with
+// the fastest handshake rather than in configured order
+func (a *Agent) latencySelection() bool {
+	return a.config.ServerSelection == "latency"
+}
+
+// serverList returns the configured servers in order of preference, then
+// with latency selection the servers the last server announced. The
+// caller holds serversMu.
+func (a *Agent) serverList() []string {
+	servers := a.config.ServerList()
+	for _, server := range a.announced {
+		if !slices.Contains(servers, server) {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// setAnnouncedServers keeps the other servers of the deployment a server
+// announced at registration, for latency selection to measure, and lets
+// them through the kill switch
+func (a *Agent) setAnnouncedServers(servers []string) {
+	if !a.latencySelection() {
+		return
+	}
+	a.serversMu.Lock()
+	changed := !slices.Equal(a.announced, servers)
+	a.announced = slices.Clone(servers)
+	a.serversMu.Unlock()
+	if !changed {
+		return
+	}
+
+	a.routesMu.Lock()
+	err := a.updateKillSwitch()
+	a.routesMu.Unlock()
+	if err != nil {
+		log.Printf("Failed to update kill switch for the announced servers: %v", err)
+	}
+}
+
+// forgetLatencies drops the servers announced and the latencies measured,
+// when the configured servers change
+func (a *Agent) forgetLatencies() {
+	a.announced = nil
+	a.serverRTT = nil
+	a.measuredAt = time.Time{}
+}
+
+// measureServers times QUIC handshakes with the servers concurrently,
+// keeping the fastest of latencyProbes per server. Servers that fail a
+// handshake are left out, sorting after the measured ones.
+func (a *Agent) measureServers(servers []string) map[string]time.Duration {
+	a.serversMu.Lock()
+	caFile, insecure := a.config.CAFile, a.config.InsecureSkipVerify
+	a.serversMu.Unlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	rtts := make(map[string]time.Duration)
+	for _, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var fastest time.Duration
+			for range latencyProbes {
+				start := time.Now()
+				if err := probeServer(server, caFile, insecure); err != nil {
+					return
+				}
+				if rtt := time.Since(start); fastest == 0 || rtt < fastest {
+					fastest = rtt
+				}
+			}
+			mu.Lock()
+			rtts[server] = fastest
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	a.serversMu.Lock()
+	a.serverRTT = rtts
+	a.measuredAt = time.Now()
+	a.serversMu.Unlock()
+	return rtts
+}
+
+// measureIfStale measures the servers before a connection unless the last
+// measurement is recent
+func (a *Agent) measureIfStale() {
+	a.serversMu.Lock()
+	stale := time.Since(a.measuredAt) > failbackInterval
+	servers := a.serverList()
+	a.serversMu.Unlock()
+	if stale {
+		a.measureServers(servers)
+	}
+}
+
+// sortByLatency orders servers by their measured handshake time, those
+// not measured last in their previous order. The caller holds serversMu.
+func (a *Agent) sortByLatency(servers []string) {
+	slices.SortStableFunc(servers, func(x, y string) int {
+		rx, okx := a.serverRTT[x]
+		ry, oky := a.serverRTT[y]
+		switch {
+		case okx && oky:
+			return cmp.Compare(rx, ry)
+		case okx:
+			return -1
+		case oky:
+			return 1
+		}
+		return 0
+	})
+}
+
+// fasterServer measures the servers again and reports whether one answers
+// clearly faster than the current server, for the session to move there
+func (a *Agent) fasterServer() bool {
+	a.serversMu.Lock()
+	servers := a.serverList()
+	current := a.server
+	a.serversMu.Unlock()
+
+	rtts := a.measureServers(servers)
+	// A current server that fails the probe but keeps the session, e.g.
+	// over the TCP fallback, is not compared
+	currentRTT, ok := rtts[current]
+	if !ok {
+		return false
+	}
+
+	best := current
+	a.serversMu.Lock()
+	for _, server := range servers {
+		if rtt, ok := rtts[server]; ok && rtt < rtts[best] && !a.isBusy(server) && !a.handedOver(server) {
+			best = server
+		}
+	}
+	a.serversMu.Unlock()
+
+	gain := currentRTT - rtts[best]
+	if best == current || gain < latencyMinGain || gain*latencyMinRatio < currentRTT {
+		return false
+	}
+	log.Printf("Server %s answers in %s, %s in %s, moving the session",
+		best, rtts[best].Round(time.Millisecond), current, currentRTT.Round(time.Millisecond))
+	return true
+}
+
+// networkChanged asks latency selection to measure the servers again
+// after a network change
+func (a *Agent) networkChanged() {
+	if !a.latencySelection() {
+		return
+	}
+	select {
+	case a.netChanged <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"os/user"
+	"sort"
+	"strconv"
+	"sync"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// localUserStats counts the relayed traffic of each local user whose
+// flows the agent tagged, see socketOwners
+type localUserStats struct {
+	mu    sync.Mutex
+	users map[uint32]*proto.LocalUserStats
+}
+
+// sent counts a packet a local user sent through the tunnel
+func (l *localUserStats) sent(uid uint32, n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := l.user(uid)
+	s.BytesSent += uint64(n)
+	s.PacketsSent++
+}
+
+// received counts a packet the tunnel delivered to a local user
+func (l *localUserStats) received(uid uint32, n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := l.user(uid)
+	s.BytesReceived += uint64(n)
+	s.PacketsReceived++
+}
+
+// user returns the counters of a user, created with its name on first
+// sight. The caller holds mu.
+func (l *localUserStats) user(uid uint32) *proto.LocalUserStats {
+	if s, ok := l.users[uid]; ok {
+		return s
+	}
+	if l.users == nil {
+		l.users = make(map[uint32]*proto.LocalUserStats)
+	}
+	s := &proto.LocalUserStats{Uid: uid}
+	if u, err := user.LookupId(strconv.FormatUint(uint64(uid), 10)); err == nil {
+		s.Name = u.Username
+	}
+	l.users[uid] = s
+	return s
+}
+
+// snapshot returns a copy of the counters ordered by uid, nil if no flow
+// was tagged
+func (l *localUserStats) snapshot() []*proto.LocalUserStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var users []*proto.LocalUserStats
+	for _, s := range l.users {
+		users = append(users, &proto.LocalUserStats{
+			Uid:             s.Uid,
+			Name:            s.Name,
+			BytesSent:       s.BytesSent,
+			BytesReceived:   s.BytesReceived,
+			PacketsSent:     s.PacketsSent,
+			PacketsReceived: s.PacketsReceived,
+		})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Uid < users[j].Uid })
+	return users
+}
@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"log"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// setManagedConfig stores the settings of the agent's group sent by the
+// server, reporting whether they changed
+func (a *Agent) setManagedConfig(mc *proto.ManagedConfig) bool {
+	a.dnsMu.Lock()
+	defer a.dnsMu.Unlock()
+
+	if protobuf.Equal(a.managed, mc) {
+		return false
+	}
+	a.managed = mc
+	if mc == nil {
+		log.Printf("Agent left its group, using the local configuration")
+		return true
+	}
+
+	log.Printf("Applying settings of group %s", mc.Group)
+	if mc.BandwidthLimit > 0 {
+		log.Printf("Group %s limits relayed traffic to %d KB/s", mc.Group, mc.BandwidthLimit)
+	}
+	return true
+}
+
+// group returns the name of the agent's group, empty without one
+func (a *Agent) group() string {
+	a.dnsMu.Lock()
+	defer a.dnsMu.Unlock()
+	return a.managed.GetGroup()
+}
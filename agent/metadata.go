@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"log"
+	"net"
+	"os"
+	"runtime"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// metadataRefreshInterval is how often heartbeats collect the host metadata
+// again, it is only sent to the server when it changed
+const metadataRefreshInterval = 5 * time.Minute
+
+// collectMetadata describes the agent host: platform, physical interfaces,
+// the default route outside the tunnel and the device posture
+func (a *Agent) collectMetadata() *proto.AgentMetadata {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "agent-" + a.agentID[:8]
+	}
+
+	metadata := &proto.AgentMetadata{
+		Os:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Version:    "1.0.0",
+		Hostname:   hostname,
+		Interfaces: a.hostInterfaces(),
+		Posture:    collectPosture(),
+	}
+
+	routes, err := a.routeManager.SystemRoutes()
+	if err != nil {
+		log.Printf("Warning: failed to read the default route: %v", err)
+		return metadata
+	}
+	for _, route := range routes {
+		if ones, _ := route.destination.Mask.Size(); ones != 0 || route.iface == a.tunName() {
+			continue
+		}
+		metadata.DefaultGateway = route.gateway
+		metadata.DefaultInterface = route.iface
+		break
+	}
+	return metadata
+}
+
+// hostInterfaces lists the network interfaces of the host, leaving out
+// loopback and the tunnel
+func (a *Agent) hostInterfaces() []*proto.NetworkInterface {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		log.Printf("Warning: failed to list network interfaces: %v", err)
+		return nil
+	}
+
+	var result []*proto.NetworkInterface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 || iface.Name == a.tunName() {
+			continue
+		}
+		ni := &proto.NetworkInterface{
+			Name: iface.Name,
+			Mac:  iface.HardwareAddr.String(),
+			Mtu:  int32(iface.MTU),
+			Up:   iface.Flags&net.FlagUp != 0,
+		}
+		addrs, err := iface.Addrs()
+		if err == nil {
+			for _, addr := range addrs {
+				ni.Addresses = append(ni.Addresses, addr.String())
+			}
+		}
+		result = append(result, ni)
+	}
+	return result
+}
+
+// tunName returns the TUN interface name, empty before it is created
+func (a *Agent) tunName() string {
+	if a.tun == nil {
+		return ""
+	}
+	return a.tun.Name()
+}
@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+)
+
+// networkSettleDelay waits for a burst of network changes to settle
+// before routes are restored
+const networkSettleDelay = 2 * time.Second
+
+// networkMonitorLoop restores tunnel routes after sleep/resume, interface
+// flaps and DHCP renewals reported by the platform network monitor. It
+// fails when the platform monitor stops, so the supervisor restarts both.
+func (a *Agent) networkMonitorLoop() error {
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
+
+	changed := make(chan struct{}, 1)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- watchNetworkChanges(ctx, changed)
+	}()
+
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-a.ctx.Done():
+			return nil
+		case err := <-stopped:
+			if err == nil {
+				err = errors.New("monitor exited")
+			}
+			return fmt.Errorf("network change monitor stopped: %w", err)
+		case <-changed:
+			settle = time.After(networkSettleDelay)
+		case <-settle:
+			settle = nil
+			a.restoreRoutes()
+			a.networkChanged()
+		}
+	}
+}
+
+// restoreRoutes re-adds tunnel routes that disappeared from the system table
+func (a *Agent) restoreRoutes() {
+	// Routes stay withdrawn while the user disconnected
+	if a.paused.Load() {
+		return
+	}
+	a.routesMu.Lock()
+	restored, err := a.routeManager.RestoreRoutes()
+	// Local subnets and the server address may have changed
+	if ksErr := a.updateKillSwitch(); ksErr != nil {
+		ksErr = errcode.Wrap(errcode.KillSwitchFailed, ksErr)
+		log.Printf("Failed to update kill switch after network change: %v", ksErr)
+		a.emitError("failed to update kill switch after network change", ksErr)
+	}
+	a.routesMu.Unlock()
+	if err != nil {
+		err = errcode.Wrap(errcode.RouteFailed, err)
+		log.Printf("Failed to restore routes after network change: %v", err)
+		a.emitError("failed to restore routes after network change", err)
+	}
+	if restored > 0 {
+		log.Printf("Network change detected, restored %d route(s)", restored)
+		a.events.publish(Event{Type: EventRouteInstalled, Message: "routes restored after network change"})
+	}
+
+	// DHCP renewals rewrite the system resolvers
+	if err := a.applyDNS(); err != nil {
+		err = errcode.Wrap(errcode.DNSFailed, err)
+		log.Printf("Failed to reapply DNS after network change: %v", err)
+		a.emitError("failed to reapply DNS after network change", err)
+	}
+
+	// A newly joined network may sit behind a captive portal
+	a.triggerCaptiveCheck()
+}
+
+// notifyNetworkChange signals a change without blocking
+func notifyNetworkChange(changed chan<- struct{}) {
+	select {
+	case changed <- struct{}{}:
+	default:
+	}
+}
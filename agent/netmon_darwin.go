@@ -0,0 +1,52 @@
+//go:build darwin
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchNetworkChanges listens for interface, address and route changes on
+// a PF_ROUTE socket until ctx is cancelled. Sleep/wake shows up as
+// interface and address changes.
+func watchNetworkChanges(ctx context.Context, changed chan<- struct{}) error {
+	fd, err := unix.Socket(unix.AF_ROUTE, unix.SOCK_RAW, unix.AF_UNSPEC)
+	if err != nil {
+		return fmt.Errorf("failed to open route socket: %w", err)
+	}
+	defer unix.Close(fd)
+
+	// Wake up periodically to notice cancellation
+	timeout := unix.NsecToTimeval(int64(time.Second))
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); err != nil {
+		return fmt.Errorf("failed to set route socket timeout: %w", err)
+	}
+
+	buf := make([]byte, 8192)
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		n, err := unix.Read(fd, buf)
+		if err != nil {
+			if err == unix.EAGAIN || err == unix.EINTR {
+				continue
+			}
+			return fmt.Errorf("failed to read route socket: %w", err)
+		}
+
+		// rt_msghdr: u_short rtm_msglen; u_char rtm_version; u_char rtm_type
+		if n < 4 {
+			continue
+		}
+		switch int(buf[3]) {
+		case unix.RTM_IFINFO, unix.RTM_NEWADDR, unix.RTM_DELADDR, unix.RTM_DELETE:
+			notifyNetworkChange(changed)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+//go:build linux
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchNetworkChanges listens for link, address and route changes on a
+// rtnetlink socket until ctx is cancelled
+func watchNetworkChanges(ctx context.Context, changed chan<- struct{}) error {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return fmt.Errorf("failed to open netlink socket: %w", err)
+	}
+	defer unix.Close(fd)
+
+	addr := &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: unix.RTMGRP_LINK | unix.RTMGRP_IPV4_IFADDR | unix.RTMGRP_IPV4_ROUTE |
+			unix.RTMGRP_IPV6_IFADDR | unix.RTMGRP_IPV6_ROUTE,
+	}
+	if err := unix.Bind(fd, addr); err != nil {
+		return fmt.Errorf("failed to bind netlink socket: %w", err)
+	}
+
+	// Wake up periodically to notice cancellation
+	timeout := unix.NsecToTimeval(int64(time.Second))
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); err != nil {
+		return fmt.Errorf("failed to set netlink timeout: %w", err)
+	}
+
+	buf := make([]byte, 8192)
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if err == unix.EAGAIN || err == unix.EINTR {
+				continue
+			}
+			return fmt.Errorf("failed to read netlink socket: %w", err)
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, msg := range msgs {
+			switch msg.Header.Type {
+			case unix.RTM_NEWLINK, unix.RTM_DELLINK,
+				unix.RTM_NEWADDR, unix.RTM_DELADDR,
+				unix.RTM_DELROUTE:
+				notifyNetworkChange(changed)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+//go:build windows
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modIphlpapi              = windows.NewLazySystemDLL("iphlpapi.dll")
+	procNotifyAddrChange     = modIphlpapi.NewProc("NotifyAddrChange")
+	procCancelIPChangeNotify = modIphlpapi.NewProc("CancelIPChangeNotify")
+	procNotifyRouteChange    = modIphlpapi.NewProc("NotifyRouteChange")
+)
+
+// watchNetworkChanges waits on NotifyAddrChange and NotifyRouteChange
+// until ctx is cancelled
+func watchNetworkChanges(ctx context.Context, changed chan<- struct{}) error {
+	addrEvent, err := windows.CreateEvent(nil, 0, 0, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	defer windows.CloseHandle(addrEvent)
+
+	routeEvent, err := windows.CreateEvent(nil, 0, 0, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	defer windows.CloseHandle(routeEvent)
+
+	stopEvent, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	defer windows.CloseHandle(stopEvent)
+
+	go func() {
+		<-ctx.Done()
+		windows.SetEvent(stopEvent)
+	}()
+
+	addrOverlapped := &windows.Overlapped{HEvent: addrEvent}
+	routeOverlapped := &windows.Overlapped{HEvent: routeEvent}
+	defer procCancelIPChangeNotify.Call(uintptr(unsafe.Pointer(addrOverlapped)))
+	defer procCancelIPChangeNotify.Call(uintptr(unsafe.Pointer(routeOverlapped)))
+
+	if err := requestChangeNotify(procNotifyAddrChange, addrOverlapped); err != nil {
+		return err
+	}
+	if err := requestChangeNotify(procNotifyRouteChange, routeOverlapped); err != nil {
+		return err
+	}
+
+	handles := []windows.Handle{addrEvent, routeEvent, stopEvent}
+	for {
+		event, err := windows.WaitForMultipleObjects(handles, false, windows.INFINITE)
+		if err != nil {
+			return fmt.Errorf("failed to wait for network changes: %w", err)
+		}
+
+		switch event {
+		case windows.WAIT_OBJECT_0:
+			notifyNetworkChange(changed)
+			if err := requestChangeNotify(procNotifyAddrChange, addrOverlapped); err != nil {
+				return err
+			}
+		case windows.WAIT_OBJECT_0 + 1:
+			notifyNetworkChange(changed)
+			if err := requestChangeNotify(procNotifyRouteChange, routeOverlapped); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// requestChangeNotify arms an asynchronous IP helper change notification
+func requestChangeNotify(proc *windows.LazyProc, overlapped *windows.Overlapped) error {
+	var handle windows.Handle
+	r0, _, _ := proc.Call(uintptr(unsafe.Pointer(&handle)), uintptr(unsafe.Pointer(overlapped)))
+	if windows.Errno(r0) != windows.ERROR_IO_PENDING {
+		return fmt.Errorf("%s failed: %w", proc.Name, windows.Errno(r0))
+	}
+	return nil
+}
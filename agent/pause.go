@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"errors"
+	"log"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// Disconnect ends the session on behalf of the user. The agent keeps
+// running with its routes withdrawn, the kill switch off and the original
+// DNS until Connect is called.
+func (a *Agent) Disconnect() error {
+	if !a.paused.CompareAndSwap(false, true) {
+		return errors.New("already disconnected")
+	}
+	select {
+	case a.pause <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Connect reestablishes the session ended by Disconnect
+func (a *Agent) Connect() error {
+	if !a.paused.CompareAndSwap(true, false) {
+		return errors.New("not disconnected")
+	}
+	select {
+	case a.resume <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// userDisconnect ends the session for Disconnect and waits for Connect,
+// then connects again. It returns false if the agent stopped meanwhile.
+func (a *Agent) userDisconnect() bool {
+	a.stopSession()
+	a.reportStatus(proto.AgentStatus_OFFLINE, "disconnected by user")
+	if a.conn != nil {
+		a.conn.Close()
+		a.conn = nil
+	}
+	a.lastStatus.stopped("disconnected by user")
+
+	// Traffic leaves outside the tunnel until the user connects again
+	a.suspendDNS()
+	a.routesMu.Lock()
+	if err := a.routeManager.Withdraw(); err != nil {
+		log.Printf("Warning: failed to withdraw routes: %v", err)
+	}
+	if a.killSwitch != nil {
+		if err := a.killSwitch.Disable(); err != nil {
+			log.Printf("Warning: failed to disable kill switch: %v", err)
+		}
+		a.killSwitchPaused = true
+	}
+	a.routesMu.Unlock()
+	a.events.publish(Event{Type: EventDisconnected, Message: "disconnected by user"})
+	log.Println("Disconnected by user")
+
+	select {
+	case <-a.ctx.Done():
+		return false
+	case <-a.resume:
+	}
+
+	log.Println("Connecting on user request")
+	a.routesMu.Lock()
+	if _, err := a.routeManager.RestoreRoutes(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	a.killSwitchPaused = false
+	if err := a.updateKillSwitch(); err != nil {
+		err = errcode.Wrap(errcode.KillSwitchFailed, err)
+		log.Printf("Failed to enable kill switch: %v", err)
+		a.emitError("failed to enable kill switch", err)
+	}
+	a.routesMu.Unlock()
+
+	if !a.reconnect() {
+		return false
+	}
+	a.startSession()
+	return true
+}
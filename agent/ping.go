@@ -0,0 +1,145 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Ping sends an overlay echo probe to target (overlay IP or agent name)
+// through the relay and returns the round-trip time. It does not depend
+// on OS ICMP handling or routes.
+func (a *Agent) Ping(ctx context.Context, target string) (time.Duration, error) {
+	_, rtt, err := a.echo(ctx, target)
+	return rtt, err
+}
+
+// Resolve returns the overlay IP of target, an overlay IP or the name of
+// an agent. Names are resolved by probing the agent, so they resolve only
+// while it is connected.
+func (a *Agent) Resolve(ctx context.Context, target string) (net.IP, error) {
+	if ip := net.ParseIP(target); ip != nil {
+		return ip, nil
+	}
+	reply, _, err := a.echo(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(reply.Address)
+	if ip == nil {
+		return nil, fmt.Errorf("%s did not report its overlay address, it may need an upgrade", target)
+	}
+	return ip, nil
+}
+
+// echo sends an overlay echo probe to target and returns its reply and the
+// round-trip time
+func (a *Agent) echo(ctx context.Context, target string) (*proto.EchoProbe, time.Duration, error) {
+	sender := a.relaySender()
+	if sender == nil {
+		return nil, 0, errors.New("relay is not connected")
+	}
+	if !a.serverSupports(proto.CapabilityEcho) {
+		return nil, 0, errors.New("server does not support overlay echo")
+	}
+
+	id := a.echoSeq.Add(1)
+	replies := make(chan *proto.EchoProbe, 1)
+	a.echoWaiters.Store(id, replies)
+	defer a.echoWaiters.Delete(id)
+
+	start := time.Now()
+	if err := sender.Send(&proto.DataPacket{
+		SessionId:     sender.sessionID,
+		SourceAgentId: a.agentID,
+		Echo: &proto.EchoProbe{
+			Target: target,
+			Id:     id,
+			SentAt: timestamppb.New(start),
+		},
+	}); err != nil {
+		return nil, 0, fmt.Errorf("failed to send echo probe: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	case reply := <-replies:
+		if reply.Error != "" {
+			return nil, 0, errors.New(reply.Error)
+		}
+		return reply, time.Since(start), nil
+	}
+}
+
+// handleEcho answers echo probes from other agents and delivers replies
+// to pending Ping calls
+func (a *Agent) handleEcho(sender *relaySender, packet *proto.DataPacket) {
+	echo := packet.Echo
+	if echo.Reply {
+		if ch, ok := a.echoWaiters.Load(echo.Id); ok {
+			select {
+			case ch.(chan *proto.EchoProbe) <- echo:
+			default:
+			}
+		}
+		return
+	}
+
+	if err := sender.Send(&proto.DataPacket{
+		SessionId:          sender.sessionID,
+		SourceAgentId:      a.agentID,
+		DestinationAgentId: packet.SourceAgentId,
+		Echo: &proto.EchoProbe{
+			Id:      echo.Id,
+			Reply:   true,
+			SentAt:  echo.SentAt,
+			Address: echo.Address,
+		},
+	}); err != nil {
+		log.Printf("Failed to send echo reply: %v", err)
+	}
+}
+
+// relaySender returns the sender of any relay stream, if connected
+func (a *Agent) relaySender() *relaySender {
+	a.sendersMu.Lock()
+	defer a.sendersMu.Unlock()
+
+	for _, sender := range a.senders {
+		if sender != nil {
+			return sender
+		}
+	}
+	return nil
+}
+
+// setRelaySender makes sender the relay stream of a TUN queue and returns
+// a function unregistering it
+func (a *Agent) setRelaySender(queue int, sender *relaySender) func() {
+	a.sendersMu.Lock()
+	a.senders[queue] = sender
+	a.sendersMu.Unlock()
+
+	return func() {
+		a.sendersMu.Lock()
+		defer a.sendersMu.Unlock()
+		if a.senders[queue] == sender {
+			a.senders[queue] = nil
+		}
+	}
+}
+
+// queueSender returns the relay stream of a TUN queue, nil while
+// disconnected
+func (a *Agent) queueSender(queue int) *relaySender {
+	a.sendersMu.Lock()
+	defer a.sendersMu.Unlock()
+	return a.senders[queue]
+}
@@ -0,0 +1,19 @@
+//go:build darwin
+
+package agent
+
+import "log"
+
+// setupPlatformRoutes installs macOS-specific routes after the TUN is up
+func (a *Agent) setupPlatformRoutes() error {
+	if !a.config.ScopedDefaultRoute {
+		return nil
+	}
+
+	if err := a.routeManager.AddScopedDefaultRoute(a.gatewayIP, a.tun.Name()); err != nil {
+		return err
+	}
+	log.Printf("Added scoped default route via %s on %s", a.gatewayIP, a.tun.Name())
+
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !darwin
+
+package agent
+
+import "log"
+
+// setupPlatformRoutes installs platform-specific routes after the TUN is up
+func (a *Agent) setupPlatformRoutes() error {
+	if a.config.ScopedDefaultRoute {
+		log.Println("scoped_default_route is only supported on macOS, ignoring")
+	}
+	return nil
+}
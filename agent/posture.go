@@ -0,0 +1,13 @@
+package agent
+
+import proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+
+// postureState converts the outcome of a posture check the agent could
+// run. Checks it cannot run stay unknown, which fails policies requiring
+// them.
+func postureState(enabled bool) proto.PostureState {
+	if enabled {
+		return proto.PostureState_POSTURE_STATE_ENABLED
+	}
+	return proto.PostureState_POSTURE_STATE_DISABLED
+}
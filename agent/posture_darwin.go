@@ -0,0 +1,28 @@
+//go:build darwin
+
+package agent
+
+import (
+	"os/exec"
+	"strings"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// collectPosture reports the macOS version, FileVault and the application
+// firewall
+func collectPosture() *proto.DevicePosture {
+	posture := &proto.DevicePosture{}
+	if output, err := exec.Command("sw_vers", "-productVersion").Output(); err == nil {
+		posture.OsVersion = strings.TrimSpace(string(output))
+	}
+	// FileVault is On.
+	if output, err := exec.Command("fdesetup", "status").Output(); err == nil {
+		posture.DiskEncryption = postureState(strings.Contains(string(output), "FileVault is On"))
+	}
+	// Firewall is enabled. (State = 1)
+	if output, err := exec.Command("/usr/libexec/ApplicationFirewall/socketfilterfw", "--getglobalstate").Output(); err == nil {
+		posture.Firewall = postureState(!strings.Contains(string(output), "State = 0"))
+	}
+	return posture
+}
@@ -0,0 +1,122 @@
+//go:build linux
+
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// collectPosture reports the kernel release, whether the root file system
+// is on dm-crypt and whether nftables or iptables filter inbound traffic
+func collectPosture() *proto.DevicePosture {
+	release, _ := os.ReadFile("/proc/sys/kernel/osrelease")
+	return &proto.DevicePosture{
+		OsVersion:      strings.TrimSpace(string(release)),
+		DiskEncryption: rootEncryption(),
+		Firewall:       inboundFirewall(),
+	}
+}
+
+// rootEncryption reports whether the block device of the root file system
+// or one beneath it, such as the physical volume under LVM, is dm-crypt.
+// Roots without a block device, e.g. the overlay of a container, are
+// unknown.
+func rootEncryption() proto.PostureState {
+	data, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return proto.PostureState_POSTURE_STATE_UNKNOWN
+	}
+	// 29 1 253:1 / / rw,relatime shared:1 - ext4 /dev/mapper/vg-root rw
+	device := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) > 4 && fields[4] == "/" {
+			device = fields[2] // the last mount on / is the visible one
+		}
+	}
+	dir := "/sys/dev/block/" + device
+	if _, err := os.Stat(dir); device == "" || err != nil {
+		return proto.PostureState_POSTURE_STATE_UNKNOWN
+	}
+	return postureState(cryptDevice(dir, 0))
+}
+
+// cryptDevice reports whether the block device of a sysfs directory or a
+// device it is stacked on is dm-crypt
+func cryptDevice(dir string, depth int) bool {
+	if uuid, err := os.ReadFile(filepath.Join(dir, "dm", "uuid")); err == nil && strings.HasPrefix(string(uuid), "CRYPT-") {
+		return true
+	}
+	if depth > 8 {
+		return false
+	}
+	slaves, _ := os.ReadDir(filepath.Join(dir, "slaves"))
+	for _, slave := range slaves {
+		if cryptDevice(filepath.Join("/sys/class/block", slave.Name()), depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// inboundFirewall reports whether an input chain of nftables, or the
+// INPUT chain of iptables, drops or rejects traffic
+func inboundFirewall() proto.PostureState {
+	state := proto.PostureState_POSTURE_STATE_UNKNOWN
+	if output, err := privCommand("nft", "list", "ruleset").Output(); err == nil {
+		if nftFiltersInput(string(output)) {
+			return proto.PostureState_POSTURE_STATE_ENABLED
+		}
+		state = proto.PostureState_POSTURE_STATE_DISABLED
+	}
+	if output, err := privCommand("iptables", "-S", "INPUT").Output(); err == nil {
+		if iptablesFiltersInput(string(output)) {
+			return proto.PostureState_POSTURE_STATE_ENABLED
+		}
+		state = proto.PostureState_POSTURE_STATE_DISABLED
+	}
+	return state
+}
+
+// nftFiltersInput reports whether a base chain on the input hook has a
+// drop policy or drops or rejects packets
+func nftFiltersInput(ruleset string) bool {
+	// chain input {
+	//         type filter hook input priority filter; policy drop;
+	input := false
+	for _, line := range strings.Split(ruleset, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch {
+		case fields[0] == "chain" || fields[0] == "table":
+			input = false
+		case strings.Contains(line, "hook input"):
+			input = true
+			if strings.Contains(line, "policy drop") {
+				return true
+			}
+		case input && (slices.Contains(fields, "drop") || slices.Contains(fields, "reject")):
+			return true
+		}
+	}
+	return false
+}
+
+// iptablesFiltersInput reports whether the INPUT chain has a drop policy
+// or drops or rejects packets
+func iptablesFiltersInput(rules string) bool {
+	// -P INPUT DROP
+	// -A INPUT -j REJECT --reject-with icmp-host-prohibited
+	for _, line := range strings.Split(rules, "\n") {
+		if line == "-P INPUT DROP" || strings.Contains(line, "-j DROP") || strings.Contains(line, "-j REJECT") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"golang.org/x/sys/windows"
+)
+
+// collectPosture reports the Windows version, BitLocker on the system
+// drive and Windows Firewall. The PowerShell queries do not depend on the
+// display language, unlike the output of manage-bde and netsh.
+func collectPosture() *proto.DevicePosture {
+	v := windows.RtlGetVersion()
+	posture := &proto.DevicePosture{
+		OsVersion: fmt.Sprintf("%d.%d.%d", v.MajorVersion, v.MinorVersion, v.BuildNumber),
+	}
+
+	// 1 is protected, other values are off, encrypting or suspended
+	if protection, err := powershellOutput(`(New-Object -ComObject Shell.Application).NameSpace("$env:SystemDrive\").` +
+		`Self.ExtendedProperty('System.Volume.BitLockerProtection')`); err == nil && protection != "" {
+		posture.DiskEncryption = postureState(protection == "1")
+	}
+	// The firewall profiles that are off
+	if off, err := powershellOutput(`@(Get-NetFirewallProfile | Where-Object Enabled -eq False).Count`); err == nil && off != "" {
+		posture.Firewall = postureState(off == "0")
+	}
+	return posture
+}
+
+// powershellOutput runs a PowerShell command and returns its trimmed output
+func powershellOutput(command string) (string, error) {
+	output, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", command).Output()
+	return strings.TrimSpace(string(output)), err
+}
@@ -0,0 +1,73 @@
+//go:build linux
+
+package agent
+
+import (
+	"fmt"
+	"log"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// dropPrivileges switches the agent to the configured user once the TUN
+// is up and the routes are installed. It keeps CAP_NET_ADMIN, also for
+// the ip commands it runs, for the route changes of later updates,
+// reconnects and the cleanup on stop; every other capability is gone.
+func (a *Agent) dropPrivileges() error {
+	if !a.config.DropPrivileges || privHelper != nil {
+		return nil
+	}
+	u, err := lookupAgentUser(a.config.User)
+	if err != nil {
+		return err
+	}
+	if err := u.handOver(a.config); err != nil {
+		return err
+	}
+
+	// Capabilities are per thread, every thread of the runtime must change
+	// them. Go cannot do so in binaries using cgo, which fail here before
+	// anything changed.
+	if _, _, errno := syscall.AllThreadsSyscall(unix.SYS_PRCTL, unix.PR_SET_KEEPCAPS, 1, 0); errno != 0 {
+		if errno == syscall.ENOTSUP {
+			return fmt.Errorf("failed to keep CAP_NET_ADMIN: dropping privileges needs a build with CGO_ENABLED=0")
+		}
+		return fmt.Errorf("failed to keep CAP_NET_ADMIN: %w", errno)
+	}
+
+	groups := make([]int, len(u.groups))
+	for i, g := range u.groups {
+		groups[i] = int(g)
+	}
+	if err := syscall.Setgroups(groups); err != nil {
+		return fmt.Errorf("failed to set groups: %w", err)
+	}
+	if err := syscall.Setgid(u.gid); err != nil {
+		return fmt.Errorf("failed to set gid: %w", err)
+	}
+	if err := syscall.Setuid(u.uid); err != nil {
+		return fmt.Errorf("failed to set uid: %w", err)
+	}
+
+	// The ambient set passes CAP_NET_ADMIN on to ip, which has no file
+	// capabilities; raising it requires it to be inheritable
+	hdr := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	var data [2]unix.CapUserData
+	data[0].Effective = 1 << unix.CAP_NET_ADMIN
+	data[0].Permitted = 1 << unix.CAP_NET_ADMIN
+	data[0].Inheritable = 1 << unix.CAP_NET_ADMIN
+	if _, _, errno := syscall.AllThreadsSyscall(unix.SYS_CAPSET,
+		uintptr(unsafe.Pointer(&hdr)), uintptr(unsafe.Pointer(&data[0])), 0); errno != 0 {
+		return fmt.Errorf("failed to limit capabilities: %w", errno)
+	}
+	if _, _, errno := syscall.AllThreadsSyscall6(unix.SYS_PRCTL, unix.PR_CAP_AMBIENT,
+		unix.PR_CAP_AMBIENT_RAISE, unix.CAP_NET_ADMIN, 0, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to pass on CAP_NET_ADMIN: %w", errno)
+	}
+	syscall.AllThreadsSyscall(unix.SYS_PRCTL, unix.PR_SET_KEEPCAPS, 0, 0)
+
+	log.Printf("Dropped privileges to %s, keeping CAP_NET_ADMIN", u.name)
+	return nil
+}
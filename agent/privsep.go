@@ -0,0 +1,25 @@
+package agent
+
+import (
+	"path/filepath"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// PrivsepFDEnv names the environment variable handing the unprivileged
+// agent the descriptor of its connection to the root helper. The helper
+// started it as the configured user; see RunPrivilegeHelper.
+const PrivsepFDEnv = "EASYANYLINK_PRIVSEP_FD"
+
+// stateDirs returns the directories the agent keeps its control socket,
+// traffic history and DNS backup in
+func stateDirs(cfg *config.AgentConfig) []string {
+	socket, stats := cfg.ControlSocket, cfg.StatsFile
+	if socket == "" {
+		socket = DefaultControlSocket
+	}
+	if stats == "" {
+		stats = DefaultStatsFile
+	}
+	return []string{filepath.Dir(socket), filepath.Dir(stats), filepath.Dir(dnsBackupFile)}
+}
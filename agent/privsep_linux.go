@@ -0,0 +1,471 @@
+//go:build linux
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/songgao/water"
+	"github.com/taills/EasyAnyLink/common/config"
+	"golang.org/x/sys/unix"
+)
+
+// privMaxOutput caps the command output the helper returns, so a response
+// fits a single message on the default socket buffer
+const privMaxOutput = 128 << 10
+
+// privMaxMessage is the size of the message buffers of both ends
+const privMaxMessage = 256 << 10
+
+// privCommands are the programs the helper runs for the agent
+var privCommands = map[string]bool{
+	"ip":         true,
+	"iptables":   true,
+	"nft":        true,
+	"ip6tables":  true,
+	"resolvectl": true,
+	"vtysh":      true,
+}
+
+// privHelper is the connection to the root helper while the agent runs
+// unprivileged, nil while it runs as root and does the work itself
+var privHelper *helperClient
+
+// privRequest is a request of the agent to its helper
+type privRequest struct {
+	Op         string   `json:"op"`   // "exec", "write", "remove" or "tun"
+	Name       string   `json:"name"` // Program, file path or interface name
+	Args       []string `json:"args,omitempty"`
+	Data       []byte   `json:"data,omitempty"`        // write: file content
+	Combined   bool     `json:"combined,omitempty"`    // exec: return stderr along with stdout
+	MultiQueue bool     `json:"multi_queue,omitempty"` // tun: open with IFF_MULTI_QUEUE
+}
+
+// privResponse is the answer of the helper. The descriptor of an opened
+// TUN device travels with it as SCM_RIGHTS.
+type privResponse struct {
+	Output []byte `json:"output,omitempty"`
+	Name   string `json:"name,omitempty"` // tun: the name the kernel gave the interface
+	Error  string `json:"error,omitempty"`
+}
+
+// helperClient sends requests to the helper, one at a time
+type helperClient struct {
+	mu   sync.Mutex
+	conn *net.UnixConn
+}
+
+// call sends a request and waits for the response and the descriptor
+// passed along with it, if any
+func (h *helperClient) call(req *privRequest) (*privResponse, *os.File, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, err := h.conn.Write(data); err != nil {
+		return nil, nil, fmt.Errorf("privilege helper: %w", err)
+	}
+	buf := make([]byte, privMaxMessage)
+	oob := make([]byte, unix.CmsgSpace(4))
+	n, oobn, flags, _, err := h.conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return nil, nil, fmt.Errorf("privilege helper: %w", err)
+	}
+	if n == 0 {
+		return nil, nil, errors.New("privilege helper exited")
+	}
+
+	var file *os.File
+	if oobn > 0 {
+		file, err = receiveFile(oob[:oobn])
+		if err != nil {
+			return nil, nil, fmt.Errorf("privilege helper: %w", err)
+		}
+	}
+	var resp privResponse
+	if flags&unix.MSG_TRUNC != 0 {
+		err = errors.New("response too large")
+	} else {
+		err = json.Unmarshal(buf[:n], &resp)
+	}
+	if err != nil {
+		if file != nil {
+			file.Close()
+		}
+		return nil, nil, fmt.Errorf("privilege helper: %w", err)
+	}
+	if resp.Error != "" {
+		if file != nil {
+			file.Close()
+		}
+		return &resp, nil, errors.New(resp.Error)
+	}
+	return &resp, file, nil
+}
+
+// receiveFile turns the descriptor of an SCM_RIGHTS message into a file.
+// It is made non-blocking first, so reads go through the runtime poller
+// and Close interrupts them like it does for devices opened by water.
+func receiveFile(oob []byte) (*os.File, error) {
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil || len(msgs) != 1 {
+		return nil, fmt.Errorf("invalid control message")
+	}
+	fds, err := unix.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		return nil, fmt.Errorf("invalid control message")
+	}
+	if err := unix.SetNonblock(fds[0], true); err != nil {
+		unix.Close(fds[0])
+		return nil, err
+	}
+	return os.NewFile(uintptr(fds[0]), "tun"), nil
+}
+
+// openTUN asks the helper for a TUN device, returning it and the name of
+// its interface
+func (h *helperClient) openTUN(name string, multiQueue bool) (io.ReadWriteCloser, string, error) {
+	resp, file, err := h.call(&privRequest{Op: "tun", Name: name, MultiQueue: multiQueue})
+	if err != nil {
+		return nil, "", err
+	}
+	if file == nil {
+		return nil, "", errors.New("privilege helper sent no TUN descriptor")
+	}
+	return file, resp.Name, nil
+}
+
+// privCmd is a command changing the network configuration. The agent runs
+// it itself as root and has the helper run it otherwise.
+type privCmd struct {
+	name string
+	args []string
+}
+
+// privCommand returns a privileged command, used like exec.Command
+func privCommand(name string, args ...string) *privCmd {
+	return &privCmd{name: name, args: args}
+}
+
+// Run runs the command
+func (c *privCmd) Run() error {
+	_, err := c.run(false)
+	return err
+}
+
+// Output runs the command and returns its standard output
+func (c *privCmd) Output() ([]byte, error) {
+	return c.run(false)
+}
+
+// CombinedOutput runs the command and returns its standard output and
+// standard error
+func (c *privCmd) CombinedOutput() ([]byte, error) {
+	return c.run(true)
+}
+
+func (c *privCmd) run(combined bool) ([]byte, error) {
+	if privHelper == nil {
+		cmd := exec.Command(c.name, c.args...)
+		if combined {
+			return cmd.CombinedOutput()
+		}
+		return cmd.Output()
+	}
+
+	resp, _, err := privHelper.call(&privRequest{Op: "exec", Name: c.name, Args: c.args, Combined: combined})
+	if resp == nil {
+		return nil, err
+	}
+	return resp.Output, err
+}
+
+// writeSystemFile writes a file only root may write, a sysctl or
+// resolv.conf
+func writeSystemFile(path string, data []byte) error {
+	if privHelper == nil {
+		return os.WriteFile(path, data, 0644)
+	}
+	_, _, err := privHelper.call(&privRequest{Op: "write", Name: path, Data: data})
+	return err
+}
+
+// removeSystemFile removes a file written with writeSystemFile
+func removeSystemFile(path string) error {
+	if privHelper == nil {
+		return os.Remove(path)
+	}
+	_, _, err := privHelper.call(&privRequest{Op: "remove", Name: path})
+	return err
+}
+
+// ConnectPrivilegeHelper makes the agent send its privileged work to the
+// helper that started it. fd is the value of PrivsepFDEnv.
+func ConnectPrivilegeHelper(fd string) error {
+	n, err := strconv.Atoi(fd)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", PrivsepFDEnv, fd)
+	}
+	file := os.NewFile(uintptr(n), "privsep")
+	conn, err := net.FileConn(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the privilege helper: %w", err)
+	}
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		conn.Close()
+		return fmt.Errorf("failed to connect to the privilege helper: not a unix socket")
+	}
+	os.Unsetenv(PrivsepFDEnv)
+	privHelper = &helperClient{conn: uc}
+	return nil
+}
+
+// agentUser is the user the agent runs as instead of root
+type agentUser struct {
+	name   string
+	uid    int
+	gid    int
+	groups []uint32
+}
+
+// lookupAgentUser looks up the configured user
+func lookupAgentUser(name string) (*agentUser, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up user %s: %w", name, err)
+	}
+	au := &agentUser{name: name}
+	au.uid, _ = strconv.Atoi(u.Uid)
+	au.gid, _ = strconv.Atoi(u.Gid)
+	if ids, err := u.GroupIds(); err == nil {
+		for _, id := range ids {
+			if g, err := strconv.Atoi(id); err == nil {
+				au.groups = append(au.groups, uint32(g))
+			}
+		}
+	}
+	return au, nil
+}
+
+// handOver makes the state directories of the agent the user's, so it
+// can keep its control socket, traffic history and DNS backup there
+func (u *agentUser) handOver(cfg *config.AgentConfig) error {
+	for _, dir := range stateDirs(cfg) {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		if err := os.Chown(dir, u.uid, u.gid); err != nil {
+			return fmt.Errorf("failed to hand %s to %s: %w", dir, u.name, err)
+		}
+	}
+	return nil
+}
+
+// RunPrivilegeHelper runs the agent as the configured user and serves its
+// privileged requests until it exits: creating TUN devices, whose
+// descriptors it passes back, and running ip, iptables, resolvectl and
+// vtysh. It returns the exit code of the agent.
+func RunPrivilegeHelper(cfg *config.AgentConfig) int {
+	u, err := lookupAgentUser(cfg.User)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	if err := u.handOver(cfg); err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_SEQPACKET|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		log.Printf("Failed to create the privilege helper socket: %v", err)
+		return 1
+	}
+	local := os.NewFile(uintptr(fds[0]), "privsep")
+	remote := os.NewFile(uintptr(fds[1]), "privsep")
+	conn, err := net.FileConn(local)
+	local.Close()
+	if err != nil {
+		remote.Close()
+		log.Printf("Failed to create the privilege helper socket: %v", err)
+		return 1
+	}
+	defer conn.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		remote.Close()
+		log.Printf("Failed to find the agent executable: %v", err)
+		return 1
+	}
+	cmd := exec.Command(exe, os.Args[1:]...)
+	// The agent logs through the helper, to a log file it could not rotate
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, log.Writer()
+	cmd.ExtraFiles = []*os.File{remote} // fd 3
+	cmd.Env = append(os.Environ(), PrivsepFDEnv+"=3")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: &syscall.Credential{Uid: uint32(u.uid), Gid: uint32(u.gid), Groups: u.groups},
+		Pdeathsig:  syscall.SIGTERM,
+	}
+	if err := cmd.Start(); err != nil {
+		remote.Close()
+		log.Printf("Failed to start the agent as %s: %v", u.name, err)
+		return 1
+	}
+	remote.Close()
+	log.Printf("Privilege helper started the agent as %s (pid %d)", u.name, cmd.Process.Pid)
+
+	// The agent shuts down on the signals the helper receives
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		for sig := range sigChan {
+			cmd.Process.Signal(sig)
+		}
+	}()
+
+	go serveHelper(conn.(*net.UnixConn))
+
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+			return exitErr.ExitCode()
+		}
+		log.Printf("Agent failed: %v", err)
+		return 1
+	}
+	return 0
+}
+
+// serveHelper answers the requests of the agent until it closes the socket
+func serveHelper(conn *net.UnixConn) {
+	buf := make([]byte, privMaxMessage)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil || n == 0 {
+			return
+		}
+
+		var req privRequest
+		var resp *privResponse
+		var file *os.File
+		if err := json.Unmarshal(buf[:n], &req); err != nil {
+			resp = &privResponse{Error: "invalid request"}
+		} else {
+			resp, file = handlePrivRequest(&req)
+		}
+
+		data, _ := json.Marshal(resp)
+		var oob []byte
+		if file != nil {
+			// Fd would switch the device to blocking mode
+			if rc, err := file.SyscallConn(); err == nil {
+				rc.Control(func(fd uintptr) {
+					oob = unix.UnixRights(int(fd))
+				})
+			}
+		}
+		_, _, err = conn.WriteMsgUnix(data, oob, nil)
+		if file != nil {
+			// The agent holds its own reference to the device now
+			file.Close()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// handlePrivRequest carries out a request of the agent, refusing anything
+// but the commands and files the agent needs
+func handlePrivRequest(req *privRequest) (*privResponse, *os.File) {
+	resp := &privResponse{}
+	switch req.Op {
+	case "exec":
+		if !privCommands[req.Name] {
+			resp.Error = fmt.Sprintf("command %s is not allowed", req.Name)
+			break
+		}
+		cmd := exec.Command(req.Name, req.Args...)
+		var output []byte
+		var err error
+		if req.Combined {
+			output, err = cmd.CombinedOutput()
+		} else {
+			output, err = cmd.Output()
+		}
+		if len(output) > privMaxOutput {
+			output = output[:privMaxOutput]
+		}
+		resp.Output = output
+		if err != nil {
+			resp.Error = err.Error()
+		}
+
+	case "write", "remove":
+		if !privPathAllowed(req.Name) {
+			resp.Error = fmt.Sprintf("writing %s is not allowed", req.Name)
+			break
+		}
+		var err error
+		if req.Op == "write" {
+			err = os.WriteFile(req.Name, req.Data, 0644)
+		} else {
+			err = os.Remove(req.Name)
+		}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+
+	case "tun":
+		config := water.Config{DeviceType: water.TUN}
+		config.Name = req.Name
+		config.MultiQueue = req.MultiQueue
+		iface, err := water.New(config)
+		if err != nil {
+			resp.Error = err.Error()
+			break
+		}
+		file, ok := iface.ReadWriteCloser.(*os.File)
+		if !ok {
+			iface.Close()
+			resp.Error = "TUN device is not a file"
+			break
+		}
+		resp.Name = iface.Name()
+		return resp, file
+
+	default:
+		resp.Error = fmt.Sprintf("unknown request %q", req.Op)
+	}
+	return resp, nil
+}
+
+// privPathAllowed reports whether the helper writes path for the agent:
+// network sysctls and resolv.conf
+func privPathAllowed(path string) bool {
+	if filepath.Clean(path) != path {
+		return false
+	}
+	return path == resolvConf || strings.HasPrefix(path, "/proc/sys/net/")
+}
@@ -0,0 +1,29 @@
+//go:build !linux
+
+package agent
+
+import (
+	"errors"
+	"log"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// ConnectPrivilegeHelper is only supported on Linux
+func ConnectPrivilegeHelper(fd string) error {
+	return errors.New("privilege separation requires Linux")
+}
+
+// RunPrivilegeHelper is only supported on Linux
+func RunPrivilegeHelper(cfg *config.AgentConfig) int {
+	log.Print("Privilege separation requires Linux")
+	return 1
+}
+
+// dropPrivileges is only supported on Linux
+func (a *Agent) dropPrivileges() error {
+	if a.config.DropPrivileges {
+		return errors.New("drop_privileges requires Linux")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+//go:build darwin
+
+package agent
+
+import (
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// probeControl binds probe sockets to iface, if any
+func probeControl(iface *net.Interface) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		if iface == nil {
+			return nil
+		}
+
+		var sockErr error
+		err := c.Control(func(fd uintptr) {
+			if network == "tcp6" || network == "udp6" {
+				sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_BOUND_IF, iface.Index)
+			} else {
+				sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_BOUND_IF, iface.Index)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return sockErr
+	}
+}
@@ -0,0 +1,38 @@
+//go:build linux
+
+package agent
+
+import (
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// captiveProbeMark is the firewall mark of captive portal probes, which
+// the kill switch lets through
+const captiveProbeMark = 0x8227
+
+// probeControl binds probe sockets to iface, if any, and marks them. An
+// agent running unprivileged may not mark sockets; its probes then only
+// pass while the kill switch is off.
+func probeControl(iface *net.Interface) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		var sockErr error
+		err := c.Control(func(fd uintptr) {
+			if iface != nil {
+				if sockErr = unix.BindToDevice(int(fd), iface.Name); sockErr != nil {
+					return
+				}
+			}
+			sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_MARK, captiveProbeMark)
+			if sockErr == unix.EPERM && privHelper != nil {
+				sockErr = nil
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return sockErr
+	}
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package agent
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// Socket options selecting the outgoing interface, from ws2ipdef.h
+const (
+	ipUnicastIf   = 31
+	ipv6UnicastIf = 31
+)
+
+// probeControl binds probe sockets to iface, if any
+func probeControl(iface *net.Interface) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		if iface == nil {
+			return nil
+		}
+
+		var sockErr error
+		err := c.Control(func(fd uintptr) {
+			if network == "tcp6" || network == "udp6" {
+				sockErr = windows.SetsockoptInt(windows.Handle(fd), windows.IPPROTO_IPV6, ipv6UnicastIf, iface.Index)
+				return
+			}
+			// IP_UNICAST_IF takes the index in network byte order
+			var index [4]byte
+			binary.BigEndian.PutUint32(index[:], uint32(iface.Index))
+			sockErr = windows.SetsockoptInt(windows.Handle(fd), windows.IPPROTO_IP, ipUnicastIf,
+				int(binary.LittleEndian.Uint32(index[:])))
+		})
+		if err != nil {
+			return err
+		}
+		return sockErr
+	}
+}
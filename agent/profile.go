@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// ProfileStatus describes the configured profiles and the active one
+type ProfileStatus struct {
+	Active    string   `json:"active"`
+	Server    string   `json:"server"` // server of the current session
+	Profiles  []string `json:"profiles"`
+	Error     string   `json:"error,omitempty"`
+	ErrorCode string   `json:"error_code,omitempty"`
+}
+
+// Profiles returns the configured profiles and the active one
+func (a *Agent) Profiles() ProfileStatus {
+	a.serversMu.Lock()
+	defer a.serversMu.Unlock()
+
+	status := ProfileStatus{Active: a.config.Profile, Server: a.server}
+	for _, profile := range a.config.Profiles {
+		status.Profiles = append(status.Profiles, profile.Name)
+	}
+	return status
+}
+
+// SwitchProfile activates a profile and reconnects to its servers
+func (a *Agent) SwitchProfile(name string) error {
+	a.serversMu.Lock()
+	if name == a.config.Profile {
+		a.serversMu.Unlock()
+		return nil
+	}
+	err := a.config.ApplyProfile(name)
+	a.serverFailures = make(map[string]time.Time)
+	a.busyUntil = make(map[string]time.Time)
+	a.forgetLatencies()
+	a.serversMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Switching to profile %s", name)
+
+	// Keep the new servers reachable outside the tunnel
+	a.routesMu.Lock()
+	err = a.updateKillSwitch()
+	a.routesMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to update kill switch: %w", err)
+	}
+	if err := a.setupRouteTable(); err != nil {
+		return err
+	}
+
+	// The supervisor reconnects to the first available server
+	select {
+	case a.lost <- struct{}{}:
+	default:
+	}
+	return nil
+}
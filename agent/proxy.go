@@ -0,0 +1,433 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+)
+
+// quicFallbackTimeout is how long QUIC may take to connect before the
+// agent falls back to TCP
+const quicFallbackTimeout = 5 * time.Second
+
+// SOCKS5 protocol values (RFC 1928, RFC 1929)
+const (
+	socksVersion      = 5
+	socksAuthNone     = 0x00
+	socksAuthPassword = 0x02
+	socksNoAcceptable = 0xff
+	socksConnect      = 0x01
+	socksUDPAssociate = 0x03
+	socksAddrIPv4     = 0x01
+	socksAddrDomain   = 0x03
+	socksAddrIPv6     = 0x04
+)
+
+// transportDialer returns the dialer of gRPC connections: QUIC, through
+// the UDP relay or inside HTTP/3 CONNECT-UDP to the server's MASQUE
+// endpoint if configured, falling back to gRPC over TLS on the server's
+// TCP port when QUIC does not get through
+func (a *Agent) transportDialer(tlsConfig *tls.Config) func(ctx context.Context, addr string) (net.Conn, error) {
+	var quicDialer *crypto.QUICDialer
+	switch {
+	case a.config.UDPRelay != "":
+		relay, _ := url.Parse(a.config.UDPRelay)
+		quicDialer = crypto.NewQUICDialerVia(tlsConfig, func(ctx context.Context, addr string) (net.PacketConn, error) {
+			return socksAssociate(ctx, relay)
+		})
+	case a.config.MasquePort != 0:
+		quicDialer = crypto.NewQUICDialerVia(tlsConfig, func(ctx context.Context, addr string) (net.PacketConn, error) {
+			host, _, _ := net.SplitHostPort(addr)
+			endpoint := net.JoinHostPort(host, strconv.Itoa(a.config.MasquePort))
+			return crypto.DialMasque(ctx, endpoint, a.config.MasquePath, addr, tlsConfig)
+		})
+	default:
+		quicDialer = crypto.NewQUICDialer(tlsConfig)
+	}
+	if a.config.TCPPort == 0 {
+		return quicDialer.DialContext
+	}
+
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		quicCtx, cancel := context.WithTimeout(ctx, quicFallbackTimeout)
+		conn, err := quicDialer.DialContext(quicCtx, addr)
+		cancel()
+		if err == nil || ctx.Err() != nil {
+			return conn, err
+		}
+
+		host, _, _ := net.SplitHostPort(addr)
+		tcpAddr := net.JoinHostPort(host, strconv.Itoa(a.config.TCPPort))
+		log.Printf("QUIC to %s failed, falling back to TCP %s: %v", addr, tcpAddr, err)
+		return a.dialTLS(ctx, tcpAddr, tlsConfig)
+	}
+}
+
+// dialTLS connects to the server's TCP port, through the proxy if one
+// applies, and speaks HTTP/2 over TLS as gRPC does
+func (a *Agent) dialTLS(ctx context.Context, addr string, tlsConfig *tls.Config) (net.Conn, error) {
+	proxy, err := a.proxyFor(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy: %w", err)
+	}
+
+	var raw net.Conn
+	if proxy != nil {
+		raw, err = dialProxy(ctx, proxy, addr)
+	} else {
+		var dialer net.Dialer
+		raw, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	config := tlsConfig.Clone()
+	config.NextProtos = []string{"h2"}
+	conn := tls.Client(raw, config)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		raw.Close()
+		return nil, fmt.Errorf("TLS handshake with %s failed: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// proxyFor returns the proxy the TCP fallback to addr goes through, nil to
+// connect directly. Without a configured proxy HTTPS_PROXY and NO_PROXY
+// decide.
+func (a *Agent) proxyFor(addr string) (*url.URL, error) {
+	switch a.config.Proxy {
+	case "direct":
+		return nil, nil
+	case "":
+		return http.ProxyFromEnvironment(&http.Request{URL: &url.URL{Scheme: "https", Host: addr}})
+	}
+	return url.Parse(a.config.Proxy)
+}
+
+// dialProxy opens a tunnel to addr through an HTTP CONNECT or SOCKS5
+// proxy
+func dialProxy(ctx context.Context, proxy *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxy.Host
+	if proxy.Port() == "" {
+		port := "1080"
+		switch proxy.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxy.Hostname(), port)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy %s: %w", proxyAddr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	switch proxy.Scheme {
+	case "http", "https":
+		if proxy.Scheme == "https" {
+			conn = tls.Client(conn, &tls.Config{ServerName: proxy.Hostname(), MinVersion: tls.VersionTLS12})
+		}
+		err = httpConnect(conn, proxy, addr)
+	case "socks5", "socks5h":
+		_, err = socksRequest(conn, proxy, socksConnect, addr)
+	default:
+		err = fmt.Errorf("unsupported proxy scheme %q", proxy.Scheme)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", proxyAddr, err)
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// httpConnect asks an HTTP proxy for a tunnel to addr
+func httpConnect(conn net.Conn, proxy *url.URL, addr string) error {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := proxy.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+
+	// The server speaks only after the TLS client hello, nothing past the
+	// response is buffered
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("CONNECT %s: %s", addr, resp.Status)
+	}
+	return nil
+}
+
+// socksRequest authenticates with a SOCKS5 proxy and sends a request for
+// addr, returning the address the proxy bound for it
+func socksRequest(conn net.Conn, proxy *url.URL, command byte, addr string) (netip.AddrPort, error) {
+	methods := []byte{socksAuthNone}
+	if proxy.User != nil {
+		methods = []byte{socksAuthPassword}
+	}
+	if _, err := conn.Write(append([]byte{socksVersion, byte(len(methods))}, methods...)); err != nil {
+		return netip.AddrPort{}, err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return netip.AddrPort{}, err
+	}
+	if reply[0] != socksVersion || reply[1] == socksNoAcceptable {
+		return netip.AddrPort{}, errors.New("SOCKS5 proxy refused the authentication methods")
+	}
+
+	if reply[1] == socksAuthPassword {
+		user := proxy.User.Username()
+		password, _ := proxy.User.Password()
+		msg := []byte{1, byte(len(user))}
+		msg = append(msg, user...)
+		msg = append(msg, byte(len(password)))
+		msg = append(msg, password...)
+		if _, err := conn.Write(msg); err != nil {
+			return netip.AddrPort{}, err
+		}
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return netip.AddrPort{}, err
+		}
+		if reply[1] != 0 {
+			return netip.AddrPort{}, errors.New("SOCKS5 proxy rejected the username or password")
+		}
+	}
+
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	msg := appendSocksAddr([]byte{socksVersion, command, 0}, host, uint16(port))
+	if _, err := conn.Write(msg); err != nil {
+		return netip.AddrPort{}, err
+	}
+
+	header := make([]byte, 3)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return netip.AddrPort{}, err
+	}
+	if header[1] != 0 {
+		return netip.AddrPort{}, fmt.Errorf("SOCKS5 request failed with reply %d", header[1])
+	}
+	bound, _, err := readSocksAddr(conn)
+	return bound, err
+}
+
+// appendSocksAddr appends the SOCKS5 encoding of host and port, as a
+// domain name unless host is an IP address
+func appendSocksAddr(b []byte, host string, port uint16) []byte {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if ip.Is4() {
+			b = append(append(b, socksAddrIPv4), ip.AsSlice()...)
+		} else {
+			b = append(append(b, socksAddrIPv6), ip.AsSlice()...)
+		}
+	} else {
+		b = append(append(b, socksAddrDomain, byte(len(host))), host...)
+	}
+	return binary.BigEndian.AppendUint16(b, port)
+}
+
+// readSocksAddr reads a SOCKS5 address and port, returning its encoded
+// length. A domain name is not resolved and yields an invalid address.
+func readSocksAddr(r io.Reader) (netip.AddrPort, int, error) {
+	kind := make([]byte, 1)
+	if _, err := io.ReadFull(r, kind); err != nil {
+		return netip.AddrPort{}, 0, err
+	}
+	var n, prefix int
+	switch kind[0] {
+	case socksAddrIPv4:
+		n = 4
+	case socksAddrIPv6:
+		n = 16
+	case socksAddrDomain:
+		size := make([]byte, 1)
+		if _, err := io.ReadFull(r, size); err != nil {
+			return netip.AddrPort{}, 0, err
+		}
+		n, prefix = int(size[0]), 1
+	default:
+		return netip.AddrPort{}, 0, fmt.Errorf("invalid SOCKS5 address type %d", kind[0])
+	}
+
+	buf := make([]byte, n+2)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return netip.AddrPort{}, 0, err
+	}
+	var ip netip.Addr
+	if kind[0] != socksAddrDomain {
+		ip, _ = netip.AddrFromSlice(buf[:n])
+	}
+	return netip.AddrPortFrom(ip, binary.BigEndian.Uint16(buf[n:])), 1 + prefix + n + 2, nil
+}
+
+// socksPacketConn sends datagrams through the UDP relay of a SOCKS5
+// proxy. The relay lasts as long as the TCP connection that set it up.
+// It does not expose the UDP socket, QUIC must use ReadFrom and WriteTo.
+type socksPacketConn struct {
+	conn    *net.UDPConn
+	relay   *net.UDPAddr
+	control net.Conn
+	buf     []byte // read buffer, QUIC reads from a single goroutine
+}
+
+// socksAssociate sets up a UDP relay with a SOCKS5 proxy
+func socksAssociate(ctx context.Context, proxy *url.URL) (net.PacketConn, error) {
+	var dialer net.Dialer
+	control, err := dialer.DialContext(ctx, "tcp", proxy.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to UDP relay %s: %w", proxy.Host, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		control.SetDeadline(deadline)
+	}
+
+	// The datagrams come from an address the proxy cannot know yet
+	bound, err := socksRequest(control, proxy, socksUDPAssociate, "0.0.0.0:0")
+	if err != nil {
+		control.Close()
+		return nil, fmt.Errorf("UDP relay %s: %w", proxy.Host, err)
+	}
+	control.SetDeadline(time.Time{})
+
+	// A relay bound to the unspecified address listens on the proxy's
+	relayIP := bound.Addr()
+	if !relayIP.IsValid() || relayIP.IsUnspecified() {
+		proxyAddr, err := netip.ParseAddrPort(control.RemoteAddr().String())
+		if err != nil {
+			control.Close()
+			return nil, err
+		}
+		relayIP = proxyAddr.Addr()
+	}
+
+	conn, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		control.Close()
+		return nil, err
+	}
+	c := &socksPacketConn{
+		conn:    conn,
+		relay:   net.UDPAddrFromAddrPort(netip.AddrPortFrom(relayIP.Unmap(), bound.Port())),
+		control: control,
+		buf:     make([]byte, 65536),
+	}
+
+	// The proxy ends the relay by closing the connection
+	go func() {
+		io.Copy(io.Discard, control)
+		conn.Close()
+	}()
+	return c, nil
+}
+
+// WriteTo sends p to addr through the relay
+func (c *socksPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return 0, fmt.Errorf("not a UDP address: %v", addr)
+	}
+	ap := udpAddr.AddrPort()
+	msg := appendSocksAddr([]byte{0, 0, 0}, ap.Addr().Unmap().String(), ap.Port())
+	if _, err := c.conn.WriteTo(append(msg, p...), c.relay); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ReadFrom receives a datagram the relay forwarded, from the address in
+// its header
+func (c *socksPacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	for {
+		n, from, err := c.conn.ReadFromUDPAddrPort(c.buf)
+		if err != nil {
+			return 0, nil, err
+		}
+		// Only the relay sends here; fragments (non-zero FRAG) are not supported
+		if from.Port() != uint16(c.relay.Port) || n < 4 || c.buf[2] != 0 {
+			continue
+		}
+		addr, length, err := readSocksAddr(bytes.NewReader(c.buf[3:n]))
+		if err != nil || !addr.Addr().IsValid() {
+			continue
+		}
+		return copy(p, c.buf[3+length:n]), net.UDPAddrFromAddrPort(addr), nil
+	}
+}
+
+// Close ends the relay
+func (c *socksPacketConn) Close() error {
+	c.control.Close()
+	return c.conn.Close()
+}
+
+// LocalAddr returns the address of the local UDP socket
+func (c *socksPacketConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// SetDeadline sets the read and write deadlines of the UDP socket
+func (c *socksPacketConn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline of the UDP socket
+func (c *socksPacketConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the write deadline of the UDP socket
+func (c *socksPacketConn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// SetReadBuffer sizes the receive buffer of the UDP socket, as QUIC asks
+func (c *socksPacketConn) SetReadBuffer(bytes int) error {
+	return c.conn.SetReadBuffer(bytes)
+}
+
+// SetWriteBuffer sizes the send buffer of the UDP socket, as QUIC asks
+func (c *socksPacketConn) SetWriteBuffer(bytes int) error {
+	return c.conn.SetWriteBuffer(bytes)
+}
@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// readyRetryInterval is the delay between readiness checks of a gateway
+// that is not ready yet
+const readyRetryInterval = 10 * time.Second
+
+// signalReady reports the READY status once the gateway can take traffic,
+// rechecking until it can or the session ends. The server routes nothing
+// to the gateway before.
+func (a *Agent) signalReady(ctx context.Context) {
+	defer a.sessWg.Done()
+
+	var last string
+	for {
+		err := a.checkReady()
+		if err == nil {
+			if err = a.reportStatus(proto.AgentStatus_READY, ""); err == nil {
+				log.Println("Gateway ready, the server routes traffic here")
+				return
+			}
+		} else if err.Error() != last {
+			coded := errcode.Wrap(errcode.NotReady, err)
+			log.Printf("Gateway not ready: %v", coded)
+			a.emitError("gateway not ready", coded)
+		}
+		last = err.Error()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(readyRetryInterval):
+		}
+	}
+}
+
+// checkReady runs the readiness checks of a gateway: its TUN has the
+// overlay address and the host forwards packets between interfaces
+func (a *Agent) checkReady() error {
+	if assigned, _ := a.overlayAddrs(); assigned == "" || a.tun == nil {
+		return errors.New("the TUN has no overlay address")
+	}
+	return checkForwarding()
+}
@@ -0,0 +1,20 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// checkForwarding checks that IPv4 forwarding is enabled
+func checkForwarding() error {
+	data, err := os.ReadFile("/proc/sys/net/ipv4/ip_forward")
+	if err != nil {
+		return fmt.Errorf("failed to read net.ipv4.ip_forward: %w", err)
+	}
+	if strings.TrimSpace(string(data)) != "1" {
+		return errors.New("IPv4 forwarding is disabled, set net.ipv4.ip_forward=1")
+	}
+	return nil
+}
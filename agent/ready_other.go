@@ -0,0 +1,9 @@
+//go:build !linux
+
+package agent
+
+// checkForwarding is not checked on this platform, forwarding is left to
+// the administrator
+func checkForwarding() error {
+	return nil
+}
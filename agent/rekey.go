@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+	"google.golang.org/grpc"
+)
+
+const (
+	// rekeyCheckInterval is how often a session checks its age and traffic
+	// against the rekey policy of the server
+	rekeyCheckInterval = 10 * time.Second
+
+	// rekeyCutoverTimeout is the most the old connection is kept while
+	// the relay streams of the new one open
+	rekeyCutoverTimeout = 10 * time.Second
+
+	// rekeyOverlap is how long both connections carry traffic, letting
+	// packets in flight on the old one arrive
+	rekeyOverlap = time.Second
+)
+
+// rekeyLoop asks the supervisor to move the session to fresh keys once
+// its connection is older than the rekey interval or relayed the rekey
+// volume. A move that failed is asked for again after another period.
+func (a *Agent) rekeyLoop(ctx context.Context, after time.Duration, bytes uint64) {
+	defer a.sessWg.Done()
+
+	ticker := time.NewTicker(rekeyCheckInterval)
+	defer ticker.Stop()
+
+	since, relayed := time.Now(), a.relayedBytes()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if (after == 0 || time.Since(since) < after) &&
+			(bytes == 0 || a.relayedBytes()-relayed < bytes) {
+			continue
+		}
+		select {
+		case a.rekeyDue <- struct{}{}:
+		default:
+		}
+		since, relayed = time.Now(), a.relayedBytes()
+	}
+}
+
+// relayedBytes returns the bytes sent and received so far
+func (a *Agent) relayedBytes() uint64 {
+	a.statsMu.RLock()
+	defer a.statsMu.RUnlock()
+	return a.stats.BytesSent + a.stats.BytesReceived
+}
+
+// rekey moves the session to a new connection to the same server, whose
+// handshake derives fresh keys. The server attaches streams by session
+// ID, so the new relay streams take over before the old connection
+// closes and no packets are lost.
+func (a *Agent) rekey() {
+	a.serversMu.Lock()
+	server := a.server
+	a.serversMu.Unlock()
+
+	oldConn, oldCancel := a.conn, a.sessCancel
+	old := a.queueSenders()
+
+	if err := a.connect(server); err != nil {
+		err = errcode.Wrap(errcode.ServerUnreachable, err)
+		log.Printf("Rekey failed, keeping the current connection: %v", err)
+		a.emitError("rekey failed", err)
+		return
+	}
+	a.startSession()
+	a.retireConnection(oldConn, oldCancel, old, "Rekey")
+
+	a.sessMu.Lock()
+	a.rekeys++
+	rekeys := a.rekeys
+	a.sessMu.Unlock()
+	log.Printf("Session moved to fresh keys (rekey %d)", rekeys)
+}
+
+// retireConnection stops the workers of the previous session and closes
+// its connection once every TUN queue sends on a relay stream of the new
+// connection, or after rekeyCutoverTimeout
+func (a *Agent) retireConnection(conn *grpc.ClientConn, cancel context.CancelFunc, old []*relaySender, move string) {
+	deadline := time.Now().Add(rekeyCutoverTimeout)
+	for !a.cutOver(old) && time.Now().Before(deadline) && a.ctx.Err() == nil {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !a.cutOver(old) {
+		log.Printf("%s: relay streams of the new connection did not open within %s", move, rekeyCutoverTimeout)
+	}
+	select {
+	case <-a.ctx.Done():
+	case <-time.After(rekeyOverlap):
+	}
+
+	cancel()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// queueSenders returns the relay stream of every TUN queue
+func (a *Agent) queueSenders() []*relaySender {
+	senders := make([]*relaySender, a.tun.NumQueues())
+	for i := range senders {
+		senders[i] = a.queueSender(i)
+	}
+	return senders
+}
+
+// cutOver reports whether every TUN queue has a relay stream other than
+// the one it had in old
+func (a *Agent) cutOver(old []*relaySender) bool {
+	for i, sender := range old {
+		current := a.queueSender(i)
+		if current == nil || current == sender {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package agent
+
+import "net"
+
+// routeSpec records how a route was installed so it can be restored
+type routeSpec struct {
+	gateway string
+	iface   string
+}
+
+// systemRoute is an IPv4 route found in the system routing table
+type systemRoute struct {
+	destination *net.IPNet
+	iface       string // interface name or gateway, for diagnostics
+	gateway     string // next hop, empty for directly connected routes
+}
+
+// RouteInfo is a route the agent installed, as listed by the control API
+type RouteInfo struct {
+	Destination string `json:"destination"`
+	Gateway     string `json:"gateway,omitempty"`
+	Interface   string `json:"interface,omitempty"`
+}
+
+// Installed returns the routes the manager tracks, in installation order
+func (rm *RouteManager) Installed() []RouteInfo {
+	routes := make([]RouteInfo, 0, len(rm.routes))
+	for _, route := range rm.routes {
+		spec := rm.specs[route]
+		routes = append(routes, RouteInfo{Destination: route, Gateway: spec.gateway, Interface: spec.iface})
+	}
+	return routes
+}
+
+// Routes returns the routes the agent installed through the tunnel
+func (a *Agent) Routes() []RouteInfo {
+	a.routesMu.Lock()
+	defer a.routesMu.Unlock()
+	return a.routeManager.Installed()
+}
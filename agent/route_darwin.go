@@ -4,18 +4,24 @@ package agent
 
 import (
 	"fmt"
+	"net"
 	"os/exec"
+	"strconv"
+	"strings"
 )
 
 // RouteManager manages routing table entries
 type RouteManager struct {
-	routes []string // Keep track of installed routes for cleanup
+	routes      []string             // Keep track of installed routes for cleanup
+	specs       map[string]routeSpec // How each tracked route was added
+	scopedIface string               // Interface of the scoped default route, if any
 }
 
 // NewRouteManager creates a new route manager
 func NewRouteManager() *RouteManager {
 	return &RouteManager{
 		routes: make([]string, 0),
+		specs:  make(map[string]routeSpec),
 	}
 }
 
@@ -37,6 +43,7 @@ func (rm *RouteManager) AddRoute(destination, gateway, iface string) error {
 	}
 
 	rm.routes = append(rm.routes, destination)
+	rm.specs[destination] = routeSpec{gateway: gateway, iface: iface}
 	return nil
 }
 
@@ -48,6 +55,7 @@ func (rm *RouteManager) DeleteRoute(destination string) error {
 	}
 
 	// Remove from tracked routes
+	delete(rm.specs, destination)
 	for i, route := range rm.routes {
 		if route == destination {
 			rm.routes = append(rm.routes[:i], rm.routes[i+1:]...)
@@ -72,6 +80,7 @@ func (rm *RouteManager) AddDefaultRoute(gateway, iface string) error {
 	}
 
 	rm.routes = append(rm.routes, "default")
+	rm.specs["default"] = routeSpec{gateway: gateway, iface: iface}
 	return nil
 }
 
@@ -83,6 +92,7 @@ func (rm *RouteManager) DeleteDefaultRoute() error {
 	}
 
 	// Remove from tracked routes
+	delete(rm.specs, "default")
 	for i, route := range rm.routes {
 		if route == "default" {
 			rm.routes = append(rm.routes[:i], rm.routes[i+1:]...)
@@ -93,8 +103,43 @@ func (rm *RouteManager) DeleteDefaultRoute() error {
 	return nil
 }
 
+// AddScopedDefaultRoute adds a default route bound to an interface.
+// It only applies to traffic bound to that interface (IP_BOUND_IF), so the
+// system default route is left untouched.
+func (rm *RouteManager) AddScopedDefaultRoute(gateway, iface string) error {
+	// route add -ifscope utun3 default 10.200.0.1
+	cmd := exec.Command("route", "-q", "-n", "add", "-ifscope", iface, "default", gateway)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to add scoped default route: %w", err)
+	}
+
+	rm.scopedIface = iface
+	rm.specs["ifscope:"+iface] = routeSpec{gateway: gateway, iface: iface}
+	return nil
+}
+
 // Cleanup removes all installed routes
 func (rm *RouteManager) Cleanup() error {
+	if rm.scopedIface != "" {
+		cmd := exec.Command("route", "-q", "-n", "delete", "-ifscope", rm.scopedIface, "default")
+		if err := cmd.Run(); err != nil {
+			fmt.Printf("Warning: failed to delete scoped default route on %s: %v\n", rm.scopedIface, err)
+		}
+		delete(rm.specs, "ifscope:"+rm.scopedIface)
+		rm.scopedIface = ""
+	}
+
+	rm.Withdraw()
+
+	rm.routes = make([]string, 0)
+	rm.specs = make(map[string]routeSpec)
+	return nil
+}
+
+// Withdraw removes the installed routes from the routing table but keeps
+// tracking them, RestoreRoutes puts them back. The scoped default route
+// stays, it only serves traffic bound to the physical interface.
+func (rm *RouteManager) Withdraw() error {
 	for _, route := range rm.routes {
 		var cmd *exec.Cmd
 		if route == "default" {
@@ -108,7 +153,126 @@ func (rm *RouteManager) Cleanup() error {
 			fmt.Printf("Warning: failed to delete route %s: %v\n", route, err)
 		}
 	}
-
-	rm.routes = make([]string, 0)
 	return nil
 }
+
+// RestoreRoutes re-adds tracked routes that were removed from the routing
+// table, e.g. after sleep/wake. It returns the number of routes restored.
+func (rm *RouteManager) RestoreRoutes() (int, error) {
+	var lastErr error
+	restored := 0
+
+	for _, route := range rm.routes {
+		spec := rm.specs[route]
+		args := []string{"-n", "add"}
+		if route == "default" {
+			args = append(args, "default")
+		} else {
+			args = append(args, "-net", route)
+		}
+		if spec.iface != "" {
+			args = append(args, "-interface", spec.iface)
+		} else {
+			args = append(args, spec.gateway)
+		}
+
+		ok, err := runRouteAdd(args)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to restore route %s: %w", route, err)
+			continue
+		}
+		if ok {
+			restored++
+		}
+	}
+
+	if rm.scopedIface != "" {
+		if gateway := rm.specs["ifscope:"+rm.scopedIface].gateway; gateway != "" {
+			ok, err := runRouteAdd([]string{"-n", "add", "-ifscope", rm.scopedIface, "default", gateway})
+			if err != nil {
+				lastErr = fmt.Errorf("failed to restore scoped default route: %w", err)
+			} else if ok {
+				restored++
+			}
+		}
+	}
+
+	return restored, lastErr
+}
+
+// runRouteAdd runs "route add" and reports whether a route was added.
+// An already existing route is not an error.
+func runRouteAdd(args []string) (bool, error) {
+	output, err := exec.Command("route", args...).CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "File exists") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SystemRoutes lists the IPv4 routes of the routing table
+func (rm *RouteManager) SystemRoutes() ([]systemRoute, error) {
+	// Destination        Gateway            Flags        Netif Expire
+	// default            192.168.1.1        UGScg          en0
+	// 10.8/16            link#14            UCS          utun3
+	output, err := exec.Command("netstat", "-rn", "-f", "inet").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes: %w", err)
+	}
+
+	var routes []systemRoute
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		ipNet := parseNetstatDestination(fields[0])
+		if ipNet == nil {
+			continue
+		}
+		route := systemRoute{destination: ipNet, iface: fields[3]}
+		// Directly connected routes have link# or MAC address gateways
+		if net.ParseIP(fields[1]) != nil {
+			route.gateway = fields[1]
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+// parseNetstatDestination parses the abbreviated netstat destinations
+// ("default", "10.8/16", "192.168.1", "127.0.0.1")
+func parseNetstatDestination(destination string) *net.IPNet {
+	if destination == "default" {
+		destination = "0.0.0.0/0"
+	}
+
+	addr, bitsStr, hasBits := strings.Cut(destination, "/")
+	octets := strings.Split(addr, ".")
+	if len(octets) > 4 {
+		return nil
+	}
+
+	bits := 8 * len(octets)
+	if hasBits {
+		n, err := strconv.Atoi(bitsStr)
+		if err != nil {
+			return nil
+		}
+		bits = n
+	}
+	for len(octets) < 4 {
+		octets = append(octets, "0")
+	}
+
+	_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", strings.Join(octets, "."), bits))
+	if err != nil {
+		return nil
+	}
+	return ipNet
+}
@@ -4,21 +4,39 @@ package agent
 
 import (
 	"fmt"
-	"os/exec"
+	"net"
+	"strconv"
+	"strings"
 )
 
 // RouteManager manages routing table entries
 type RouteManager struct {
-	routes []string // Keep track of installed routes for cleanup
+	routes []string             // Keep track of installed routes for cleanup
+	specs  map[string]routeSpec // How each tracked route was added
+	table  string               // Routing table, empty for main
 }
 
 // NewRouteManager creates a new route manager
 func NewRouteManager() *RouteManager {
 	return &RouteManager{
 		routes: make([]string, 0),
+		specs:  make(map[string]routeSpec),
 	}
 }
 
+// SetTable installs subsequent routes into the given routing table
+func (rm *RouteManager) SetTable(table int) {
+	rm.table = strconv.Itoa(table)
+}
+
+// tableArgs returns the ip route arguments selecting the routing table
+func (rm *RouteManager) tableArgs() []string {
+	if rm.table == "" {
+		return nil
+	}
+	return []string{"table", rm.table}
+}
+
 // AddRoute adds a route to the routing table
 func (rm *RouteManager) AddRoute(destination, gateway, iface string) error {
 	// ip route add 10.100.0.0/16 via 10.200.0.1 dev tun0
@@ -32,24 +50,28 @@ func (rm *RouteManager) AddRoute(destination, gateway, iface string) error {
 	if iface != "" {
 		args = append(args, "dev", iface)
 	}
+	args = append(args, rm.tableArgs()...)
 
-	cmd := exec.Command("ip", args...)
+	cmd := privCommand("ip", args...)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to add route: %w", err)
 	}
 
 	rm.routes = append(rm.routes, destination)
+	rm.specs[destination] = routeSpec{gateway: gateway, iface: iface}
 	return nil
 }
 
 // DeleteRoute removes a route from the routing table
 func (rm *RouteManager) DeleteRoute(destination string) error {
-	cmd := exec.Command("ip", "route", "del", destination)
+	args := append([]string{"route", "del", destination}, rm.tableArgs()...)
+	cmd := privCommand("ip", args...)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to delete route: %w", err)
 	}
 
 	// Remove from tracked routes
+	delete(rm.specs, destination)
 	for i, route := range rm.routes {
 		if route == destination {
 			rm.routes = append(rm.routes[:i], rm.routes[i+1:]...)
@@ -71,24 +93,28 @@ func (rm *RouteManager) AddDefaultRoute(gateway, iface string) error {
 	if iface != "" {
 		args = append(args, "dev", iface)
 	}
+	args = append(args, rm.tableArgs()...)
 
-	cmd := exec.Command("ip", args...)
+	cmd := privCommand("ip", args...)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to add default route: %w", err)
 	}
 
 	rm.routes = append(rm.routes, "default")
+	rm.specs["default"] = routeSpec{gateway: gateway, iface: iface}
 	return nil
 }
 
 // DeleteDefaultRoute removes the default route
 func (rm *RouteManager) DeleteDefaultRoute() error {
-	cmd := exec.Command("ip", "route", "del", "default")
+	args := append([]string{"route", "del", "default"}, rm.tableArgs()...)
+	cmd := privCommand("ip", args...)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to delete default route: %w", err)
 	}
 
 	// Remove from tracked routes
+	delete(rm.specs, "default")
 	for i, route := range rm.routes {
 		if route == "default" {
 			rm.routes = append(rm.routes[:i], rm.routes[i+1:]...)
@@ -101,14 +127,101 @@ func (rm *RouteManager) DeleteDefaultRoute() error {
 
 // Cleanup removes all installed routes
 func (rm *RouteManager) Cleanup() error {
+	rm.Withdraw()
+
+	// A dedicated table only holds our routes, flush whatever is left
+	if rm.table != "" {
+		privCommand("ip", "route", "flush", "table", rm.table).Run()
+	}
+
+	rm.routes = make([]string, 0)
+	rm.specs = make(map[string]routeSpec)
+	return nil
+}
+
+// Withdraw removes the installed routes from the routing table but keeps
+// tracking them, RestoreRoutes puts them back
+func (rm *RouteManager) Withdraw() error {
 	for _, route := range rm.routes {
-		cmd := exec.Command("ip", "route", "del", route)
+		args := append([]string{"route", "del", route}, rm.tableArgs()...)
+		cmd := privCommand("ip", args...)
 		if err := cmd.Run(); err != nil {
 			// Log but don't fail - route might already be removed
 			fmt.Printf("Warning: failed to delete route %s: %v\n", route, err)
 		}
 	}
-
-	rm.routes = make([]string, 0)
 	return nil
 }
+
+// RestoreRoutes re-adds tracked routes that were removed from the routing
+// table, e.g. by a DHCP renewal. It returns the number of routes restored.
+func (rm *RouteManager) RestoreRoutes() (int, error) {
+	var lastErr error
+	restored := 0
+
+	for _, route := range rm.routes {
+		spec := rm.specs[route]
+		args := []string{"route", "add", route}
+		if spec.gateway != "" {
+			args = append(args, "via", spec.gateway)
+		}
+		if spec.iface != "" {
+			args = append(args, "dev", spec.iface)
+		}
+		args = append(args, rm.tableArgs()...)
+
+		output, err := privCommand("ip", args...).CombinedOutput()
+		if err != nil {
+			if strings.Contains(string(output), "File exists") {
+				continue // route is still in place
+			}
+			lastErr = fmt.Errorf("failed to restore route %s: %w", route, err)
+			continue
+		}
+		restored++
+	}
+
+	return restored, lastErr
+}
+
+// SystemRoutes lists the IPv4 routes of the main routing table
+func (rm *RouteManager) SystemRoutes() ([]systemRoute, error) {
+	// default via 192.168.1.1 dev eth0 proto dhcp metric 100
+	// 172.17.0.0/16 dev docker0 proto kernel scope link src 172.17.0.1
+	output, err := privCommand("ip", "-4", "route", "show", "table", "main").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes: %w", err)
+	}
+
+	var routes []systemRoute
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		destination := fields[0]
+		if destination == "default" {
+			destination = "0.0.0.0/0"
+		} else if !strings.Contains(destination, "/") {
+			destination += "/32"
+		}
+		_, ipNet, err := net.ParseCIDR(destination)
+		if err != nil {
+			continue // unreachable, blackhole, ... entries
+		}
+
+		route := systemRoute{destination: ipNet}
+		for i := 1; i < len(fields)-1; i++ {
+			switch fields[i] {
+			case "dev":
+				route.iface = fields[i+1]
+			case "via":
+				route.gateway = fields[i+1]
+			}
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
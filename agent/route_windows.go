@@ -4,18 +4,23 @@ package agent
 
 import (
 	"fmt"
+	"net"
 	"os/exec"
+	"strconv"
+	"strings"
 )
 
 // RouteManager manages routing table entries
 type RouteManager struct {
-	routes []string // Keep track of installed routes for cleanup
+	routes []string             // Keep track of installed routes for cleanup
+	specs  map[string]routeSpec // How each tracked route was added
 }
 
 // NewRouteManager creates a new route manager
 func NewRouteManager() *RouteManager {
 	return &RouteManager{
 		routes: make([]string, 0),
+		specs:  make(map[string]routeSpec),
 	}
 }
 
@@ -37,6 +42,7 @@ func (rm *RouteManager) AddRoute(destination, gateway, iface string) error {
 	}
 
 	rm.routes = append(rm.routes, destination)
+	rm.specs[destination] = routeSpec{gateway: gateway, iface: iface}
 	return nil
 }
 
@@ -48,6 +54,7 @@ func (rm *RouteManager) DeleteRoute(destination string) error {
 	}
 
 	// Remove from tracked routes
+	delete(rm.specs, destination)
 	for i, route := range rm.routes {
 		if route == destination {
 			rm.routes = append(rm.routes[:i], rm.routes[i+1:]...)
@@ -67,6 +74,7 @@ func (rm *RouteManager) AddDefaultRoute(gateway, iface string) error {
 	}
 
 	rm.routes = append(rm.routes, "0.0.0.0")
+	rm.specs["0.0.0.0"] = routeSpec{gateway: gateway, iface: iface}
 	return nil
 }
 
@@ -78,6 +86,7 @@ func (rm *RouteManager) DeleteDefaultRoute() error {
 	}
 
 	// Remove from tracked routes
+	delete(rm.specs, "0.0.0.0")
 	for i, route := range rm.routes {
 		if route == "0.0.0.0" {
 			rm.routes = append(rm.routes[:i], rm.routes[i+1:]...)
@@ -90,6 +99,15 @@ func (rm *RouteManager) DeleteDefaultRoute() error {
 
 // Cleanup removes all installed routes
 func (rm *RouteManager) Cleanup() error {
+	err := rm.Withdraw()
+	rm.routes = make([]string, 0)
+	rm.specs = make(map[string]routeSpec)
+	return err
+}
+
+// Withdraw removes the installed routes from the routing table but keeps
+// tracking them, RestoreRoutes puts them back
+func (rm *RouteManager) Withdraw() error {
 	var lastErr error
 
 	// Delete routes in reverse order
@@ -107,7 +125,70 @@ func (rm *RouteManager) Cleanup() error {
 			// Continue trying to delete other routes
 		}
 	}
-
-	rm.routes = make([]string, 0)
 	return lastErr
 }
+
+// RestoreRoutes re-adds tracked routes that were removed from the routing
+// table, e.g. after an adapter reset. It returns the number of routes restored.
+func (rm *RouteManager) RestoreRoutes() (int, error) {
+	var lastErr error
+	restored := 0
+
+	for _, route := range rm.routes {
+		spec := rm.specs[route]
+		args := []string{"add", route}
+		if route == "0.0.0.0" {
+			args = append(args, "mask", "0.0.0.0")
+		}
+		args = append(args, spec.gateway)
+
+		// route.exe exits 0 even when the route already exists
+		output, err := exec.Command("route", args...).CombinedOutput()
+		if strings.Contains(string(output), "already exists") {
+			continue // route is still in place
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("failed to restore route %s: %w", route, err)
+			continue
+		}
+		restored++
+	}
+
+	return restored, lastErr
+}
+
+// SystemRoutes lists the IPv4 routes of the routing table
+func (rm *RouteManager) SystemRoutes() ([]systemRoute, error) {
+	// Publish  Type      Met  Prefix                    Idx  Gateway/Interface Name
+	// No       Manual    0    0.0.0.0/0                  12  192.168.1.1
+	output, err := exec.Command("netsh", "interface", "ipv4", "show", "route").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes: %w", err)
+	}
+
+	var routes []systemRoute
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(fields[3])
+		if err != nil {
+			continue // header lines
+		}
+		route := systemRoute{destination: ipNet, iface: strings.Join(fields[5:], " ")}
+		// Routes through a gateway name it instead of the interface
+		if net.ParseIP(fields[5]) != nil {
+			route.gateway = fields[5]
+			if idx, err := strconv.Atoi(fields[4]); err == nil {
+				if iface, err := net.InterfaceByIndex(idx); err == nil {
+					route.iface = iface.Name
+				}
+			}
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,73 @@
+//go:build linux
+
+package agent
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// Priorities of the ip rules selecting the dedicated routing table. The
+// server rule comes first so tunnel traffic never loops into the overlay.
+const (
+	serverRulePriority     = "8227"
+	routeTableRulePriority = "8228"
+)
+
+// setupRouteTable installs forward routes into a dedicated routing table
+// looked up before main, keeping the main table untouched
+func (a *Agent) setupRouteTable() error {
+	if a.config.RouteTable == 0 || a.config.AppRouting != nil {
+		return nil
+	}
+
+	table := strconv.Itoa(a.config.RouteTable)
+	a.routeManager.SetTable(a.config.RouteTable)
+
+	servers, err := a.serverEndpoints()
+	if err != nil {
+		return err
+	}
+
+	// Remove rules left behind by a previous run
+	a.cleanupRouteTable()
+
+	// ip rule add to 203.0.113.10 lookup main priority 8227
+	for _, server := range servers {
+		if server.IP.To4() == nil {
+			continue
+		}
+		output, err := privCommand("ip", "rule", "add", "to", server.IP.String(),
+			"lookup", "main", "priority", serverRulePriority).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to add server ip rule: %w: %s", err, output)
+		}
+	}
+
+	// ip rule add lookup 8228 priority 8228
+	output, err := privCommand("ip", "rule", "add", "lookup", table,
+		"priority", routeTableRulePriority).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to add ip rule: %w: %s", err, output)
+	}
+
+	log.Printf("Installing overlay routes into routing table %s", table)
+	return nil
+}
+
+// cleanupRouteTable removes the ip rules of the dedicated routing table
+func (a *Agent) cleanupRouteTable() {
+	if a.config.RouteTable == 0 || a.config.AppRouting != nil {
+		return
+	}
+
+	for _, priority := range []string{serverRulePriority, routeTableRulePriority} {
+		// Several server rules may share the priority
+		for i := 0; i < 16; i++ {
+			if privCommand("ip", "rule", "del", "priority", priority).Run() != nil {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package agent
+
+import "log"
+
+// setupRouteTable is only supported on Linux
+func (a *Agent) setupRouteTable() error {
+	if a.config.RouteTable != 0 {
+		log.Println("route_table is only supported on Linux, ignoring")
+	}
+	return nil
+}
+
+// cleanupRouteTable is a no-op on this platform
+func (a *Agent) cleanupRouteTable() {}
@@ -0,0 +1,252 @@
+//go:build linux && (amd64 || arm64)
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// sandboxSyscalls are the system calls the agent and the ip, iptables and
+// resolvectl commands it runs make, on top of the legacy calls of the
+// architecture in sandboxArchSyscalls
+var sandboxSyscalls = []uintptr{
+	// Files and descriptors
+	unix.SYS_READ, unix.SYS_WRITE, unix.SYS_READV, unix.SYS_WRITEV,
+	unix.SYS_PREAD64, unix.SYS_PWRITE64, unix.SYS_CLOSE, unix.SYS_CLOSE_RANGE,
+	unix.SYS_OPENAT, unix.SYS_OPENAT2, unix.SYS_FSTAT, unix.SYS_NEWFSTATAT, unix.SYS_STATX,
+	unix.SYS_STATFS, unix.SYS_FSTATFS, unix.SYS_LSEEK, unix.SYS_IOCTL, unix.SYS_FCNTL,
+	unix.SYS_FLOCK, unix.SYS_FSYNC, unix.SYS_FDATASYNC, unix.SYS_FTRUNCATE, unix.SYS_FADVISE64,
+	unix.SYS_GETDENTS64, unix.SYS_GETCWD, unix.SYS_CHDIR, unix.SYS_FCHDIR,
+	unix.SYS_MKDIRAT, unix.SYS_UNLINKAT, unix.SYS_RENAMEAT, unix.SYS_RENAMEAT2,
+	unix.SYS_READLINKAT, unix.SYS_FACCESSAT, unix.SYS_FACCESSAT2,
+	unix.SYS_FCHMOD, unix.SYS_FCHMODAT, unix.SYS_FCHOWN, unix.SYS_FCHOWNAT,
+	unix.SYS_UTIMENSAT, unix.SYS_UMASK, unix.SYS_DUP, unix.SYS_DUP3, unix.SYS_PIPE2,
+	unix.SYS_SPLICE, unix.SYS_SENDFILE, unix.SYS_COPY_FILE_RANGE,
+	// Polling and timers
+	unix.SYS_EPOLL_CREATE1, unix.SYS_EPOLL_CTL, unix.SYS_EPOLL_PWAIT, unix.SYS_EPOLL_PWAIT2,
+	unix.SYS_PPOLL, unix.SYS_PSELECT6, unix.SYS_EVENTFD2,
+	unix.SYS_TIMERFD_CREATE, unix.SYS_TIMERFD_SETTIME, unix.SYS_TIMERFD_GETTIME,
+	unix.SYS_TIMER_CREATE, unix.SYS_TIMER_SETTIME, unix.SYS_TIMER_DELETE,
+	unix.SYS_NANOSLEEP, unix.SYS_CLOCK_NANOSLEEP, unix.SYS_CLOCK_GETTIME, unix.SYS_CLOCK_GETRES,
+	unix.SYS_GETTIMEOFDAY,
+	// Sockets
+	unix.SYS_SOCKET, unix.SYS_SOCKETPAIR, unix.SYS_CONNECT, unix.SYS_ACCEPT4, unix.SYS_BIND,
+	unix.SYS_LISTEN, unix.SYS_GETSOCKNAME, unix.SYS_GETPEERNAME, unix.SYS_SETSOCKOPT,
+	unix.SYS_GETSOCKOPT, unix.SYS_SENDTO, unix.SYS_RECVFROM, unix.SYS_SENDMSG, unix.SYS_RECVMSG,
+	unix.SYS_SENDMMSG, unix.SYS_RECVMMSG, unix.SYS_SHUTDOWN,
+	// Memory
+	unix.SYS_MMAP, unix.SYS_MPROTECT, unix.SYS_MUNMAP, unix.SYS_MREMAP, unix.SYS_MADVISE,
+	unix.SYS_BRK, unix.SYS_MEMBARRIER,
+	// Threads, signals and processes
+	unix.SYS_CLONE, unix.SYS_CLONE3, unix.SYS_EXECVE, unix.SYS_EXIT, unix.SYS_EXIT_GROUP,
+	unix.SYS_WAIT4, unix.SYS_WAITID, unix.SYS_KILL, unix.SYS_TGKILL, unix.SYS_TKILL,
+	unix.SYS_PIDFD_OPEN, unix.SYS_PIDFD_SEND_SIGNAL,
+	unix.SYS_RT_SIGACTION, unix.SYS_RT_SIGPROCMASK, unix.SYS_RT_SIGRETURN, unix.SYS_SIGALTSTACK,
+	unix.SYS_RESTART_SYSCALL, unix.SYS_FUTEX, unix.SYS_SET_ROBUST_LIST, unix.SYS_GET_ROBUST_LIST,
+	unix.SYS_SET_TID_ADDRESS, unix.SYS_RSEQ, unix.SYS_SCHED_YIELD, unix.SYS_SCHED_GETAFFINITY,
+	unix.SYS_GETPID, unix.SYS_GETPPID, unix.SYS_GETTID, unix.SYS_GETPGID, unix.SYS_SETPGID,
+	unix.SYS_GETUID, unix.SYS_GETEUID, unix.SYS_GETGID, unix.SYS_GETEGID, unix.SYS_GETGROUPS,
+	unix.SYS_GETRESUID, unix.SYS_GETRESGID, unix.SYS_CAPGET, unix.SYS_PRCTL,
+	unix.SYS_GETRLIMIT, unix.SYS_PRLIMIT64, unix.SYS_UNAME, unix.SYS_SYSINFO, unix.SYS_GETRANDOM,
+}
+
+// sandboxExec are the calls only needed to run commands, not allowed
+// while the privilege helper runs them
+var sandboxExec = map[uintptr]bool{
+	unix.SYS_CLONE3: true, unix.SYS_EXECVE: true, unix.SYS_WAIT4: true, unix.SYS_WAITID: true,
+	unix.SYS_PIDFD_OPEN: true, unix.SYS_PIDFD_SEND_SIGNAL: true,
+}
+
+// applySandbox confines the agent once it is set up. "enforce" denies
+// system calls outside sandboxSyscalls with EPERM and limits file access
+// with landlock, where the kernel supports it; "audit" only has the
+// kernel log the calls seccomp would deny. Both are inherited by the
+// commands the agent runs.
+func (a *Agent) applySandbox() error {
+	mode := a.config.Sandbox
+	if mode == "" || mode == "off" {
+		return nil
+	}
+
+	// Every thread of the runtime needs no_new_privs before the filters
+	// apply. Go cannot change all threads in binaries using cgo.
+	if _, _, errno := syscall.AllThreadsSyscall(unix.SYS_PRCTL, unix.PR_SET_NO_NEW_PRIVS, 1, 0); errno != 0 {
+		if errno == syscall.ENOTSUP {
+			return errors.New("the sandbox needs a build with CGO_ENABLED=0")
+		}
+		return fmt.Errorf("failed to set no_new_privs: %w", errno)
+	}
+
+	landlock := "no landlock"
+	if mode == "enforce" {
+		abi, err := a.applyLandlock()
+		if err != nil {
+			return err
+		}
+		if abi > 0 {
+			landlock = fmt.Sprintf("landlock ABI %d", abi)
+		}
+	}
+
+	allowed := make([]uintptr, 0, len(sandboxSyscalls)+len(sandboxArchSyscalls))
+	for _, nr := range append(sandboxSyscalls, sandboxArchSyscalls...) {
+		if privHelper != nil && sandboxExec[nr] {
+			continue
+		}
+		allowed = append(allowed, nr)
+	}
+	deny := uint32(unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM))
+	if mode == "audit" {
+		deny = unix.SECCOMP_RET_LOG
+	}
+	filter := seccompFilter(allowed, deny)
+	prog := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+	if _, _, errno := syscall.RawSyscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER,
+		unix.SECCOMP_FILTER_FLAG_TSYNC, uintptr(unsafe.Pointer(&prog))); errno != 0 {
+		return fmt.Errorf("failed to install the seccomp filter: %w", errno)
+	}
+
+	if mode == "audit" {
+		a.sandbox = fmt.Sprintf("audit (seccomp logs calls outside %d allowed)", len(allowed))
+		log.Printf("Sandbox in audit mode: calls outside the %d allowed ones are logged to the kernel audit log (type=SECCOMP), not denied", len(allowed))
+		return nil
+	}
+	a.sandbox = fmt.Sprintf("enforce (seccomp %d calls, %s)", len(allowed), landlock)
+	log.Printf("Sandbox enforced: seccomp allows %d calls, %s. Denied calls fail with %q, denied files with %q; "+
+		`set "sandbox": "audit" to find them in the kernel audit log`, len(allowed), landlock, unix.EPERM, unix.EACCES)
+	return nil
+}
+
+// seccompFilter builds a filter allowing the calls of the native
+// architecture in allowed and returning deny for all others
+func seccompFilter(allowed []uintptr, deny uint32) []unix.SockFilter {
+	stmt := func(code uint16, k uint32) unix.SockFilter {
+		return unix.SockFilter{Code: code, K: k}
+	}
+	jump := func(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+		return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+	}
+
+	// Offsets of arch and nr in struct seccomp_data
+	filter := []unix.SockFilter{
+		stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 4),
+		jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, sandboxAuditArch, 1, 0),
+		stmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_KILL_PROCESS),
+		stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 0),
+	}
+	for _, nr := range allowed {
+		filter = append(filter,
+			jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, uint32(nr), 0, 1),
+			stmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ALLOW))
+	}
+	return append(filter, stmt(unix.BPF_RET|unix.BPF_K, deny))
+}
+
+// landlockRule grants access beneath a path
+type landlockRule struct {
+	path   string
+	access uint64
+}
+
+// applyLandlock limits file access to the system directories, the state
+// directories of the agent and the files it changes. It returns the
+// landlock ABI version, 0 if the kernel has no landlock.
+func (a *Agent) applyLandlock() (int, error) {
+	abi, _, errno := syscall.RawSyscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		log.Printf("Warning: landlock unavailable (%v), the sandbox only filters system calls", errno)
+		return 0, nil
+	}
+
+	const read = unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR
+	const write = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK | unix.LANDLOCK_ACCESS_FS_MAKE_DIR | unix.LANDLOCK_ACCESS_FS_REMOVE_FILE
+	handled := uint64(0x1fff) // the file system rights of ABI 1
+	if abi >= 2 {
+		handled |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	truncate := uint64(0)
+	if abi >= 3 {
+		truncate = unix.LANDLOCK_ACCESS_FS_TRUNCATE
+		handled |= truncate
+	}
+
+	exec := uint64(0)
+	if privHelper == nil {
+		exec = unix.LANDLOCK_ACCESS_FS_EXECUTE
+	}
+	rules := []landlockRule{
+		{"/usr", read | exec}, {"/bin", read | exec}, {"/sbin", read | exec},
+		{"/lib", read | exec}, {"/lib64", read | exec},
+		{"/etc", read}, {"/proc", read}, {"/sys", read}, {"/dev", read}, {"/run", read},
+		{"/dev/null", read | unix.LANDLOCK_ACCESS_FS_WRITE_FILE},
+	}
+	if privHelper == nil {
+		rules = append(rules,
+			landlockRule{"/proc/sys/net", unix.LANDLOCK_ACCESS_FS_WRITE_FILE},
+			// The iptables lock
+			landlockRule{"/run", unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_MAKE_REG | truncate})
+		if a.config.DNS != nil {
+			rules = append(rules, landlockRule{resolvConf, unix.LANDLOCK_ACCESS_FS_WRITE_FILE | truncate})
+		}
+	}
+	for _, dir := range stateDirs(a.config) {
+		rules = append(rules, landlockRule{dir, read | write | truncate})
+	}
+	if a.config.Debug.QlogDir != "" {
+		rules = append(rules, landlockRule{a.config.Debug.QlogDir, read | write | truncate})
+	}
+	if a.config.CAFile != "" {
+		rules = append(rules, landlockRule{a.config.CAFile, read})
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	fd, _, errno := syscall.RawSyscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return 0, fmt.Errorf("failed to create the landlock ruleset: %w", errno)
+	}
+	defer unix.Close(int(fd))
+
+	for _, rule := range rules {
+		if err := addLandlockRule(int(fd), rule, handled); err != nil {
+			return 0, err
+		}
+	}
+	if _, _, errno := syscall.AllThreadsSyscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return 0, fmt.Errorf("failed to apply the landlock ruleset: %w", errno)
+	}
+	return int(abi), nil
+}
+
+// addLandlockRule adds a rule to a ruleset. Paths missing on this system
+// are skipped, rules on files keep only the rights applying to files.
+func addLandlockRule(ruleset int, rule landlockRule, handled uint64) error {
+	fd, err := unix.Open(rule.path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			return nil
+		}
+		return fmt.Errorf("failed to open %s for the landlock ruleset: %w", rule.path, err)
+	}
+	defer unix.Close(fd)
+
+	access := rule.access & handled
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err == nil && st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+			unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	attr := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	if _, _, errno := syscall.RawSyscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset),
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&attr)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to allow %s in the landlock ruleset: %w", rule.path, errno)
+	}
+	return nil
+}
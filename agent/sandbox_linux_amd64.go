@@ -0,0 +1,16 @@
+package agent
+
+import "golang.org/x/sys/unix"
+
+// sandboxAuditArch is the architecture the seccomp filter allows calls of
+const sandboxAuditArch = unix.AUDIT_ARCH_X86_64
+
+// sandboxArchSyscalls are the calls of amd64 that arm64 only has as *at
+// variants, still made by the C libraries of the commands the agent runs
+var sandboxArchSyscalls = []uintptr{
+	unix.SYS_OPEN, unix.SYS_STAT, unix.SYS_LSTAT, unix.SYS_ACCESS, unix.SYS_READLINK,
+	unix.SYS_UNLINK, unix.SYS_RENAME, unix.SYS_MKDIR, unix.SYS_CHMOD, unix.SYS_CHOWN,
+	unix.SYS_PIPE, unix.SYS_DUP2, unix.SYS_POLL, unix.SYS_SELECT, unix.SYS_EPOLL_WAIT,
+	unix.SYS_EPOLL_CREATE, unix.SYS_GETDENTS, unix.SYS_FORK, unix.SYS_VFORK,
+	unix.SYS_ARCH_PRCTL, unix.SYS_TIME, unix.SYS_GETPGRP, unix.SYS_EVENTFD,
+}
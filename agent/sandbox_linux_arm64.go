@@ -0,0 +1,10 @@
+package agent
+
+import "golang.org/x/sys/unix"
+
+// sandboxAuditArch is the architecture the seccomp filter allows calls of
+const sandboxAuditArch = unix.AUDIT_ARCH_AARCH64
+
+// sandboxArchSyscalls are the architecture-specific calls, arm64 has none
+// beyond the common ones
+var sandboxArchSyscalls []uintptr
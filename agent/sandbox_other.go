@@ -0,0 +1,16 @@
+//go:build !linux || !(amd64 || arm64)
+
+package agent
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// applySandbox is only supported on Linux on amd64 and arm64
+func (a *Agent) applySandbox() error {
+	if a.config.Sandbox == "" || a.config.Sandbox == "off" {
+		return nil
+	}
+	return fmt.Errorf("the sandbox is not supported on %s/%s", runtime.GOOS, runtime.GOARCH)
+}
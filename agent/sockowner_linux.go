@@ -0,0 +1,131 @@
+//go:build linux
+
+package agent
+
+import (
+	"bufio"
+	"encoding/binary"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+)
+
+const (
+	// socketRescanInterval bounds how often unknown ports trigger a scan
+	// of the socket tables, so that a burst of new flows costs one scan
+	socketRescanInterval = 100 * time.Millisecond
+
+	// socketRefreshInterval is how long a scan is trusted, a port a user
+	// closed may be reused by another one
+	socketRefreshInterval = 5 * time.Second
+)
+
+// socketTables are the kernel socket tables read for owners. IPv4 sockets
+// of dual-stack listeners appear in the IPv6 tables.
+var socketTables = map[uint8][]string{
+	packet.ProtocolTCP: {"/proc/net/tcp", "/proc/net/tcp6"},
+	packet.ProtocolUDP: {"/proc/net/udp", "/proc/net/udp6"},
+}
+
+// socketKey identifies a local socket by protocol and local port
+type socketKey struct {
+	protocol uint8
+	port     uint16
+}
+
+// socketOwners finds the local user owning the socket of a relayed flow.
+// Packets read from the TUN carry no socket marks, so the owner is looked
+// up by local port in the kernel socket tables, as ss does.
+type socketOwners struct {
+	mu      sync.Mutex
+	owners  map[socketKey]uint32
+	scanned time.Time
+}
+
+// newSocketOwners returns the socket owner lookup for source_uids
+func newSocketOwners() *socketOwners {
+	log.Println("Tagging relayed flows with the uid of the local user owning their socket")
+	return &socketOwners{}
+}
+
+// lookup returns the owner of the local socket of a TCP or UDP packet,
+// sent through the tunnel if outbound and delivered from it otherwise.
+// Later fragments, other protocols and sockets gone by now are untagged.
+func (o *socketOwners) lookup(b []byte, outbound bool) (uint32, bool) {
+	if o == nil {
+		return 0, false
+	}
+	h, err := packet.ParseIPv4(b)
+	if err != nil || (h.Protocol != packet.ProtocolTCP && h.Protocol != packet.ProtocolUDP) {
+		return 0, false
+	}
+	if binary.BigEndian.Uint16(b[6:8])&0x1fff != 0 || len(h.Payload) < 4 {
+		return 0, false
+	}
+	key := socketKey{protocol: h.Protocol, port: binary.BigEndian.Uint16(h.Payload[2:4])}
+	if outbound {
+		key.port = binary.BigEndian.Uint16(h.Payload[0:2])
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	uid, ok := o.owners[key]
+	since := time.Since(o.scanned)
+	if (!ok && since >= socketRescanInterval) || since >= socketRefreshInterval {
+		o.scan()
+		uid, ok = o.owners[key]
+	}
+	return uid, ok
+}
+
+// scan reads the owners of all TCP and UDP sockets. The caller holds mu.
+func (o *socketOwners) scan() {
+	owners := make(map[socketKey]uint32, len(o.owners))
+	for protocol, tables := range socketTables {
+		for _, table := range tables {
+			readSocketTable(table, protocol, owners)
+		}
+	}
+	o.owners = owners
+	o.scanned = time.Now()
+}
+
+// readSocketTable adds the sockets of a /proc/net table to owners. Lines
+// look like
+//
+//	sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid ...
+//	0: 0100007F:0277 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0 ...
+func readSocketTable(path string, protocol uint8, owners map[socketKey]uint32) {
+	f, err := os.Open(path)
+	if err != nil {
+		return // no IPv6 on this host
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		i := strings.LastIndexByte(fields[1], ':')
+		if i < 0 {
+			continue
+		}
+		port, err := strconv.ParseUint(fields[1][i+1:], 16, 16)
+		if err != nil {
+			continue
+		}
+		uid, err := strconv.ParseUint(fields[7], 10, 32)
+		if err != nil {
+			continue
+		}
+		owners[socketKey{protocol: protocol, port: uint16(port)}] = uint32(uid)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package agent
+
+import "log"
+
+// socketOwners finds the local user owning the socket of a relayed flow,
+// only supported on Linux
+type socketOwners struct{}
+
+// newSocketOwners returns nil, source_uids is only supported on Linux
+func newSocketOwners() *socketOwners {
+	log.Println("source_uids is only supported on Linux, ignoring")
+	return nil
+}
+
+// lookup never finds an owner on this platform
+func (o *socketOwners) lookup(b []byte, outbound bool) (uint32, bool) {
+	return 0, false
+}
@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/status"
+)
+
+// Disconnect reasons as reported by the control API, matching the reason
+// codes of the server's session history
+const (
+	DisconnectServerDecision = "server_decision"
+	DisconnectAuthRevoked    = "auth_revoked"
+	DisconnectIdleTimeout    = "idle_timeout"
+	DisconnectAgentShutdown  = "agent_shutdown"
+	DisconnectTransportError = "transport_error"
+	DisconnectAgentError     = "agent_error"
+	DisconnectServerUpgrade  = "server_upgrade"
+)
+
+// DisconnectInfo describes why the last session ended
+type DisconnectInfo struct {
+	Reason string    `json:"reason"`
+	Detail string    `json:"detail,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// ErrorInfo is the last error the agent reported
+type ErrorInfo struct {
+	Message string    `json:"message"`
+	Error   string    `json:"error"`
+	Code    string    `json:"code,omitempty"` // stable EALxxxx code, see common/errcode
+	Time    time.Time `json:"time"`
+}
+
+// ConnectionStatus is the control API response to a status request
+type ConnectionStatus struct {
+	Connected      bool             `json:"connected"`
+	Server         string           `json:"server,omitempty"`
+	SessionID      string           `json:"session_id,omitempty"`
+	AssignedIP     string           `json:"assigned_ip,omitempty"`
+	Group          string           `json:"group,omitempty"`
+	LastDisconnect *DisconnectInfo  `json:"last_disconnect,omitempty"`
+	LastError      *ErrorInfo       `json:"last_error,omitempty"`
+	Sandbox        string           `json:"sandbox,omitempty"`        // applied seccomp and landlock policy
+	ClockSkewMs    int64            `json:"clock_skew_ms,omitempty"`  // local clock minus server clock, as last measured
+	Paused         bool             `json:"paused,omitempty"`         // disconnected by the user until connected again
+	ServerRTTs     map[string]int64 `json:"server_rtts_ms,omitempty"` // fastest handshake per server of the last latency measurement
+	BytesSent      uint64           `json:"bytes_sent"`
+	BytesReceived  uint64           `json:"bytes_received"`
+	Error          string           `json:"error,omitempty"` // why a connect or disconnect request failed
+	ErrorCode      string           `json:"error_code,omitempty"`
+}
+
+// statusTracker keeps the last disconnect and error of the agent
+type statusTracker struct {
+	mu             sync.Mutex
+	connected      bool
+	lastDisconnect *DisconnectInfo
+	lastError      *ErrorInfo
+}
+
+// sessionStarted records that a session is established
+func (t *statusTracker) sessionStarted() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.connected = true
+}
+
+// isConnected reports whether a session is established
+func (t *statusTracker) isConnected() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.connected
+}
+
+// disconnected records why a session was lost. Every worker of the session
+// reports the loss, the first one tells the cause.
+func (t *statusTracker) disconnected(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.connected {
+		return
+	}
+	t.connected = false
+
+	t.lastDisconnect = &DisconnectInfo{Reason: DisconnectTransportError, Time: time.Now()}
+	if err != nil {
+		t.lastDisconnect.Reason, t.lastDisconnect.Detail = disconnectReason(err), err.Error()
+	}
+}
+
+// stopped records a session the agent ended on purpose
+func (t *statusTracker) stopped(detail string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.connected = false
+	t.lastDisconnect = &DisconnectInfo{Reason: DisconnectAgentShutdown, Detail: detail, Time: time.Now()}
+}
+
+// lastDisconnectReason returns why the last session ended, empty before
+// the first loss
+func (t *statusTracker) lastDisconnectReason() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.lastDisconnect == nil {
+		return ""
+	}
+	return t.lastDisconnect.Reason
+}
+
+// errorOccurred records an error reported by the agent
+func (t *statusTracker) errorOccurred(message string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastError = &ErrorInfo{Message: message, Error: err.Error(), Code: string(errcode.Of(err)), Time: time.Now()}
+}
+
+// errorCode returns the code of err, fallback if it has none
+func errorCode(err error, fallback errcode.Code) string {
+	if code := errcode.Of(err); code != "" {
+		return string(code)
+	}
+	return string(fallback)
+}
+
+// disconnectReason classifies the error that ended a session. The server
+// tells why it ended a session in the status details.
+func disconnectReason(err error) string {
+	if st, ok := status.FromError(err); ok {
+		for _, detail := range st.Details() {
+			if ended, ok := detail.(*proto.SessionEnded); ok && ended.Reason != proto.DisconnectReason_DISCONNECT_REASON_UNSPECIFIED {
+				return strings.ToLower(strings.TrimPrefix(ended.Reason.String(), "DISCONNECT_"))
+			}
+		}
+	}
+	switch {
+	case errors.Is(err, errNoHeartbeat):
+		return DisconnectIdleTimeout
+	case errors.Is(err, errPanic):
+		return DisconnectAgentError
+	}
+	return DisconnectTransportError
+}
+
+// Status returns the current session and the last disconnect and error
+func (a *Agent) Status() ConnectionStatus {
+	var s ConnectionStatus
+
+	a.sessMu.RLock()
+	s.SessionID, s.AssignedIP = a.sessionID, a.assignedIP
+	a.sessMu.RUnlock()
+
+	a.serversMu.Lock()
+	s.Server = a.server
+	if len(a.serverRTT) > 0 {
+		s.ServerRTTs = make(map[string]int64, len(a.serverRTT))
+		for server, rtt := range a.serverRTT {
+			s.ServerRTTs[server] = rtt.Milliseconds()
+		}
+	}
+	a.serversMu.Unlock()
+	s.Group = a.group()
+	s.Sandbox = a.sandbox
+	s.ClockSkewMs = time.Duration(a.clockSkew.Load()).Milliseconds()
+	s.Paused = a.paused.Load()
+	stats := a.GetStats()
+	s.BytesSent, s.BytesReceived = stats.BytesSent, stats.BytesReceived
+
+	a.lastStatus.mu.Lock()
+	s.Connected = a.lastStatus.connected
+	s.LastDisconnect, s.LastError = a.lastStatus.lastDisconnect, a.lastStatus.lastError
+	a.lastStatus.mu.Unlock()
+	return s
+}
@@ -0,0 +1,254 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Restart policy of supervised subsystems
+const (
+	restartMinBackoff = time.Second
+	restartMaxBackoff = time.Minute
+
+	// degradedFailures failures within failureWindow mark a subsystem
+	// degraded, it is healthy again once the window passes without one
+	degradedFailures = 3
+	failureWindow    = 5 * time.Minute
+)
+
+// Subsystem states
+const (
+	SubsystemRunning    = "running"
+	SubsystemRestarting = "restarting"
+	SubsystemDegraded   = "degraded"
+)
+
+// SubsystemStatus is the health of one supervised subsystem
+type SubsystemStatus struct {
+	Name        string    `json:"name"`
+	State       string    `json:"state"`
+	Restarts    uint32    `json:"restarts"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastFailure time.Time `json:"last_failure,omitempty"`
+}
+
+// HealthStatus is the control API response to a health request. Only
+// subsystems that failed since the agent started are listed.
+type HealthStatus struct {
+	Degraded   bool              `json:"degraded"`
+	Subsystems []SubsystemStatus `json:"subsystems,omitempty"`
+}
+
+// subsystemHealth tracks the failures of one subsystem
+type subsystemHealth struct {
+	restarts   uint32
+	restarting bool
+	failures   []time.Time // within failureWindow
+	lastError  string
+}
+
+// healthTracker records subsystem failures for the control API and the
+// heartbeat
+type healthTracker struct {
+	mu         sync.Mutex
+	subsystems map[string]*subsystemHealth
+}
+
+// recent drops failures that left the window and returns the rest
+func (h *subsystemHealth) recent(now time.Time) []time.Time {
+	for len(h.failures) > 0 && now.Sub(h.failures[0]) > failureWindow {
+		h.failures = h.failures[1:]
+	}
+	return h.failures
+}
+
+// state returns the state of a subsystem at now
+func (h *subsystemHealth) state(now time.Time) string {
+	switch {
+	case len(h.recent(now)) >= degradedFailures:
+		return SubsystemDegraded
+	case h.restarting:
+		return SubsystemRestarting
+	}
+	return SubsystemRunning
+}
+
+// failed records a failure and returns the backoff before the restart,
+// doubling with each recent failure. became reports whether the failure
+// made the subsystem degraded.
+func (t *healthTracker) failed(name string, err error) (backoff time.Duration, became bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.subsystems == nil {
+		t.subsystems = make(map[string]*subsystemHealth)
+	}
+	h := t.subsystems[name]
+	if h == nil {
+		h = &subsystemHealth{}
+		t.subsystems[name] = h
+	}
+
+	now := time.Now()
+	wasDegraded := h.state(now) == SubsystemDegraded
+	h.failures = append(h.recent(now), now)
+	h.lastError = err.Error()
+	h.restarting = true
+
+	backoff = restartMinBackoff << (len(h.failures) - 1)
+	if backoff > restartMaxBackoff || backoff <= 0 {
+		backoff = restartMaxBackoff
+	}
+	return backoff, !wasDegraded && h.state(now) == SubsystemDegraded
+}
+
+// restarted records that a failed subsystem runs again
+func (t *healthTracker) restarted(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if h := t.subsystems[name]; h != nil && h.restarting {
+		h.restarting = false
+		h.restarts++
+	}
+}
+
+// status returns the subsystems that failed, sorted by name
+func (t *healthTracker) status() HealthStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var status HealthStatus
+	now := time.Now()
+	for name, h := range t.subsystems {
+		ss := SubsystemStatus{
+			Name:      name,
+			State:     h.state(now),
+			Restarts:  h.restarts,
+			LastError: h.lastError,
+		}
+		if n := len(h.failures); n > 0 {
+			ss.LastFailure = h.failures[n-1]
+		}
+		if ss.State == SubsystemDegraded {
+			status.Degraded = true
+		}
+		status.Subsystems = append(status.Subsystems, ss)
+	}
+	sort.Slice(status.Subsystems, func(i, j int) bool {
+		return status.Subsystems[i].Name < status.Subsystems[j].Name
+	})
+	return status
+}
+
+// Health returns the state of the agent's subsystems
+func (a *Agent) Health() HealthStatus {
+	return a.health.status()
+}
+
+// healthProto converts the subsystem health to proto format, nil while no
+// subsystem has failed
+func (a *Agent) healthProto() *proto.AgentHealth {
+	status := a.health.status()
+	if len(status.Subsystems) == 0 {
+		return nil
+	}
+	result := &proto.AgentHealth{Degraded: status.Degraded}
+	for _, ss := range status.Subsystems {
+		sh := &proto.SubsystemHealth{
+			Name:      ss.Name,
+			State:     ss.State,
+			Restarts:  ss.Restarts,
+			LastError: ss.LastError,
+		}
+		if !ss.LastFailure.IsZero() {
+			sh.LastFailure = timestamppb.New(ss.LastFailure)
+		}
+		result.Subsystems = append(result.Subsystems, sh)
+	}
+	return result
+}
+
+// goSupervised starts a supervised subsystem tracked by wg
+func (a *Agent) goSupervised(wg *sync.WaitGroup, name string, fn func() error) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.runSupervised(name, fn)
+	}()
+}
+
+// runSupervised runs a long-lived subsystem until the agent stops,
+// restarting it with backoff when it panics or returns an error. A nil
+// return ends the subsystem.
+func (a *Agent) runSupervised(name string, fn func() error) {
+	for {
+		err := runRecovered(fn)
+		if err == nil || a.ctx.Err() != nil {
+			return
+		}
+
+		backoff := a.subsystemFailed(name, err)
+		log.Printf("Restarting %s in %s", name, backoff)
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		a.health.restarted(name)
+	}
+}
+
+// recoverSession is deferred by session workers. A panic fails the
+// subsystem and the session, which supervise then reestablishes.
+func (a *Agent) recoverSession(ctx context.Context, name string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	err := panicError(r)
+	a.subsystemFailed(name, err)
+	a.sessionLost(ctx, err)
+}
+
+// subsystemFailed records and reports a failed subsystem, returning the
+// backoff before its restart
+func (a *Agent) subsystemFailed(name string, err error) time.Duration {
+	backoff, degraded := a.health.failed(name, err)
+	err = errcode.Wrap(errcode.SubsystemFailed, err)
+	log.Printf("Subsystem %s failed: %v", name, err)
+	a.emitError(name+" failed", err)
+	if degraded {
+		log.Printf("Agent degraded: %s failed %d times within %s", name, degradedFailures, failureWindow)
+		a.events.publish(Event{Type: EventDegraded, Message: name, Error: err.Error()})
+	}
+	return backoff
+}
+
+// runRecovered calls fn, turning a panic into an error
+func runRecovered(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError(r)
+		}
+	}()
+	return fn()
+}
+
+// errPanic is wrapped by the errors of recovered panics
+var errPanic = errors.New("panic")
+
+// panicError logs the stack of a recovered panic and wraps its value
+func panicError(r interface{}) error {
+	log.Printf("Recovered panic: %v\n%s", r, debug.Stack())
+	return fmt.Errorf("%w: %v", errPanic, r)
+}
@@ -0,0 +1,302 @@
+package agent
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// JSON-RPC 2.0 error codes of the tray API
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcUnauthorized   = -32001 // auth was not called or failed
+	rpcFailed         = -32002 // the agent could not do what was asked
+)
+
+// trayMaxRequest bounds a request line of a tray client
+const trayMaxRequest = 64 * 1024
+
+// trayServer serves the tray API: JSON-RPC 2.0 over newline-delimited
+// JSON on a loopback TCP port. A client first calls auth with the token
+// from the token file, then may call
+//
+//	status                      the ConnectionStatus
+//	subscribe                   "state" and "event" notifications from now on
+//	connect, disconnect         Agent.Connect and Agent.Disconnect
+//	exitNodes                   the profiles traffic can leave through
+//	selectExitNode {"name": n}  switches to a profile
+//
+// "state" notifications carry the ConnectionStatus after every agent
+// event, "event" notifications the Event itself, for the tray app to
+// notify the user.
+type trayServer struct {
+	agent    *Agent
+	listener net.Listener
+	token    string
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// ExitNodes is the tray API response listing the exit nodes, the server
+// profiles of the agent
+type ExitNodes struct {
+	Active string   `json:"active"`
+	Server string   `json:"server"` // server of the current session
+	Nodes  []string `json:"nodes"`
+}
+
+// rpcRequest is a JSON-RPC request or notification from a tray client
+type rpcRequest struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcMessage is a JSON-RPC response or a notification to a tray client
+type rpcMessage struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  interface{}     `json:"params,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is the error of a JSON-RPC response
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// startTray writes a fresh token to the token file and starts the tray
+// API on the configured loopback address
+func (a *Agent) startTray() error {
+	cfg := a.config.Tray
+	token, err := writeTrayToken(cfg.TokenFile, cfg.User)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.Listen, err)
+	}
+
+	ts := &trayServer{agent: a, listener: listener, token: token, done: make(chan struct{})}
+	ts.wg.Add(1)
+	go ts.serve()
+
+	a.tray = ts
+	log.Printf("Tray API listening on %s", listener.Addr())
+	return nil
+}
+
+// writeTrayToken writes a random token to path, readable by owner only
+func writeTrayToken(path, owner string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate tray token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create tray token directory: %w", err)
+	}
+	// A token left by a previous run may belong to another user
+	os.Remove(path)
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write tray token: %w", err)
+	}
+	if owner != "" {
+		if err := chownToUser(path, owner); err != nil {
+			os.Remove(path)
+			return "", fmt.Errorf("failed to hand the tray token to %s: %w", owner, err)
+		}
+	}
+	return token, nil
+}
+
+// close stops the tray API, ending the connections of its clients
+func (ts *trayServer) close() {
+	close(ts.done)
+	ts.listener.Close()
+	ts.wg.Wait()
+	os.Remove(ts.agent.config.Tray.TokenFile)
+}
+
+// serve accepts tray clients until the server is closed
+func (ts *trayServer) serve() {
+	defer ts.wg.Done()
+	for {
+		conn, err := ts.listener.Accept()
+		if err != nil {
+			select {
+			case <-ts.done:
+			default:
+				log.Printf("Tray API stopped: %v", err)
+			}
+			return
+		}
+		ts.wg.Add(1)
+		go ts.handle(conn)
+	}
+}
+
+// trayConn is the connection of a tray client
+type trayConn struct {
+	conn          net.Conn
+	mu            sync.Mutex // serializes writes of responses and notifications
+	authenticated bool
+	unsubscribe   func()
+}
+
+// send writes a message to the client
+func (c *trayConn) send(msg *rpcMessage) error {
+	msg.Version = "2.0"
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	_, err = c.conn.Write(append(data, '\n'))
+	return err
+}
+
+// handle answers the requests of a client until it disconnects
+func (ts *trayServer) handle(conn net.Conn) {
+	defer ts.wg.Done()
+	c := &trayConn{conn: conn}
+	defer func() {
+		conn.Close()
+		if c.unsubscribe != nil {
+			c.unsubscribe()
+		}
+	}()
+
+	// Hanging up on shutdown ends the read below
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ts.done:
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), trayMaxRequest)
+	for scanner.Scan() {
+		var req rpcRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			c.send(&rpcMessage{ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: "parse error"}})
+			continue
+		}
+		result, rpcErr := ts.call(c, &req)
+		if len(req.ID) == 0 {
+			continue // a notification gets no response
+		}
+		if err := c.send(&rpcMessage{ID: req.ID, Result: result, Error: rpcErr}); err != nil {
+			return
+		}
+	}
+}
+
+// call runs a request of an authenticated client, or auth
+func (ts *trayServer) call(c *trayConn, req *rpcRequest) (interface{}, *rpcError) {
+	if req.Version != "2.0" || req.Method == "" {
+		return nil, &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+	}
+	if req.Method == "auth" {
+		var params struct {
+			Token string `json:"token"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "token is required"}
+		}
+		if subtle.ConstantTimeCompare([]byte(params.Token), []byte(ts.token)) != 1 {
+			return nil, &rpcError{Code: rpcUnauthorized, Message: "invalid token"}
+		}
+		c.authenticated = true
+		return struct{}{}, nil
+	}
+	if !c.authenticated {
+		return nil, &rpcError{Code: rpcUnauthorized, Message: "call auth first"}
+	}
+
+	a := ts.agent
+	switch req.Method {
+	case "status":
+		return a.Status(), nil
+	case "subscribe":
+		if c.unsubscribe == nil {
+			c.unsubscribe = ts.subscribe(c)
+		}
+		return struct{}{}, nil
+	case "connect", "disconnect":
+		toggle := a.Connect
+		if req.Method == "disconnect" {
+			toggle = a.Disconnect
+		}
+		if err := toggle(); err != nil {
+			return nil, &rpcError{Code: rpcFailed, Message: err.Error()}
+		}
+		return a.Status(), nil
+	case "exitNodes":
+		return ts.exitNodes(), nil
+	case "selectExitNode":
+		var params struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "name is required"}
+		}
+		if err := a.SwitchProfile(params.Name); err != nil {
+			return nil, &rpcError{Code: rpcFailed, Message: err.Error()}
+		}
+		return ts.exitNodes(), nil
+	}
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: "method not found"}
+}
+
+// exitNodes lists the profiles as exit nodes
+func (ts *trayServer) exitNodes() ExitNodes {
+	profiles := ts.agent.Profiles()
+	nodes := ExitNodes{Active: profiles.Active, Server: profiles.Server, Nodes: profiles.Profiles}
+	if nodes.Nodes == nil {
+		nodes.Nodes = []string{}
+	}
+	return nodes
+}
+
+// subscribe forwards agent events to the client as "event" notifications,
+// each followed by a "state" notification, until the returned function is
+// called
+func (ts *trayServer) subscribe(c *trayConn) func() {
+	events, unsubscribe := ts.agent.Events()
+	go func() {
+		for event := range events {
+			if err := c.send(&rpcMessage{Method: "event", Params: event}); err != nil {
+				return
+			}
+			if err := c.send(&rpcMessage{Method: "state", Params: ts.agent.Status()}); err != nil {
+				return
+			}
+		}
+	}()
+	return unsubscribe
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package agent
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
+
+// chownToUser gives the file at path to the named user, so the tray app
+// the user runs can read it
+func chownToUser(path, name string) error {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return fmt.Errorf("failed to look up user %s: %w", name, err)
+	}
+	uid, _ := strconv.Atoi(u.Uid)
+	gid, _ := strconv.Atoi(u.Gid)
+	return os.Chown(path, uid, gid)
+}
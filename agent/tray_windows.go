@@ -0,0 +1,11 @@
+//go:build windows
+
+package agent
+
+import "errors"
+
+// chownToUser is not supported on Windows, where the token file is
+// protected by the ACL of its directory
+func chownToUser(path, name string) error {
+	return errors.New("tray.user is not supported on Windows")
+}
@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc"
+)
+
+// FetchTrustBundle downloads the trust bundle of server for an agent that
+// does not trust the server certificate yet. The bundle is accepted only
+// if its CA certificate has the expected SHA256 fingerprint and it
+// verifies the certificate the server presented.
+func FetchTrustBundle(ctx context.Context, server, fingerprint string) ([]byte, error) {
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server address: %w", err)
+	}
+
+	// Verification is deferred until the bundle is known
+	tlsConfig, err := crypto.LoadClientTLSConfig(host, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS configuration: %w", err)
+	}
+	var mu sync.Mutex
+	var peer []*x509.Certificate
+	tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		mu.Lock()
+		peer = cs.PeerCertificates
+		mu.Unlock()
+		return nil
+	}
+
+	conn, err := grpc.Dial(
+		server,
+		crypto.GRPCDialOption(crypto.NewQUICDialer(tlsConfig)),
+		grpc.WithInsecure(), // TLS is handled by QUIC layer
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial server: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := proto.NewAgentServiceClient(conn).GetTrustBundle(ctx, &proto.TrustBundleRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trust bundle: %w", err)
+	}
+
+	block, _ := pem.Decode(resp.Bundle)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+	want := strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+	if got := fmt.Sprintf("%x", sha256.Sum256(block.Bytes)); got != want {
+		return nil, fmt.Errorf("trust bundle fingerprint %s does not match %s", got, want)
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(resp.Bundle) {
+		return nil, fmt.Errorf("no certificates found in trust bundle")
+	}
+
+	mu.Lock()
+	chain := peer
+	mu.Unlock()
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("server presented no certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := chain[0].Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         roots,
+		Intermediates: intermediates,
+	}); err != nil {
+		return nil, fmt.Errorf("trust bundle does not verify the server certificate: %w", err)
+	}
+
+	return resp.Bundle, nil
+}
@@ -4,50 +4,152 @@ package agent
 
 import (
 	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 
-	"github.com/songgao/water"
+	"github.com/taills/EasyAnyLink/common/packet"
+	"golang.org/x/sys/unix"
 )
 
-// TUNInterface represents a TUN interface
+const (
+	utunControlName = "com.apple.net.utun_control"
+	sysprotoControl = 2 // SYSPROTO_CONTROL
+	utunOptIfname   = 2 // UTUN_OPT_IFNAME
+)
+
+// tunFraming is the protocol family header utun puts on every packet
+const tunFraming = packet.FramingUtun
+
+// TUNInterface represents a TUN interface backed by a native utun device
 type TUNInterface struct {
-	iface *water.Interface
-	name  string
-	mtu   int
+	file *os.File
+	name string
+	mtu  int
 }
 
-// NewTUNInterface creates a new TUN interface
-func NewTUNInterface(name string, mtu int) (*TUNInterface, error) {
-	config := water.Config{
-		DeviceType: water.TUN,
+// NewTUNInterface creates a new utun interface through the kernel control
+// socket. A name of the form "utunN" requests unit N; any other name lets
+// the kernel pick the next free unit. Multi-queue is not available on utun.
+func NewTUNInterface(name string, mtu int, multiQueue bool) (*TUNInterface, error) {
+	unit, err := parseUtunUnit(name)
+	if err != nil {
+		log.Printf("Interface name %q is not a utun name, using next free utun unit", name)
+		unit = 0
 	}
-
-	if name != "" {
-		config.Name = name
+	if multiQueue {
+		log.Println("Multi-queue TUN is only supported on Linux, ignoring")
 	}
 
-	iface, err := water.New(config)
+	fd, err := unix.Socket(unix.AF_SYSTEM, unix.SOCK_DGRAM, sysprotoControl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create TUN interface: %w", err)
 	}
 
+	ctlInfo := &unix.CtlInfo{}
+	copy(ctlInfo.Name[:], utunControlName)
+	if err := unix.IoctlCtlInfo(fd, ctlInfo); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to create TUN interface: CTLIOCGINFO: %w", err)
+	}
+
+	// sc_unit is the utun number plus one, zero means "allocate"
+	if err := unix.Connect(fd, &unix.SockaddrCtl{ID: ctlInfo.Id, Unit: unit}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to create TUN interface: connect: %w", err)
+	}
+
+	ifName, err := unix.GetsockoptString(fd, sysprotoControl, utunOptIfname)
+	if err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to get TUN interface name: %w", err)
+	}
+	ifName = strings.TrimRight(ifName, "\x00")
+
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to set TUN interface non-blocking: %w", err)
+	}
+
 	tun := &TUNInterface{
-		iface: iface,
-		name:  iface.Name(),
-		mtu:   mtu,
+		file: os.NewFile(uintptr(fd), ifName),
+		name: ifName,
+		mtu:  mtu,
 	}
 
 	return tun, nil
 }
 
-// SetIP sets the IP address of the TUN interface
-func (t *TUNInterface) SetIP(ip, netmask string) error {
-	// ifconfig tun0 10.200.0.10 10.200.0.1 netmask 255.255.0.0
-	cmd := exec.Command("ifconfig", t.name, ip, "netmask", netmask)
+// parseUtunUnit converts "utunN" to the kernel control unit N+1
+func parseUtunUnit(name string) (uint32, error) {
+	if name == "" {
+		return 0, nil
+	}
+	if !strings.HasPrefix(name, "utun") {
+		return 0, fmt.Errorf("interface name must be utun[0-9]*")
+	}
+	n, err := strconv.ParseUint(strings.TrimPrefix(name, "utun"), 10, 31)
+	if err != nil {
+		return 0, fmt.Errorf("interface name must be utun[0-9]*")
+	}
+	return uint32(n) + 1, nil
+}
+
+// OpenQueues is not supported on utun interfaces
+func (t *TUNInterface) OpenQueues(n int) error {
+	return fmt.Errorf("multi-queue TUN is only supported on Linux")
+}
+
+// NumQueues returns the number of queues, always one
+func (t *TUNInterface) NumQueues() int {
+	return 1
+}
+
+// Queue returns the interface itself, the only queue
+func (t *TUNInterface) Queue(i int) io.ReadWriter {
+	return t
+}
+
+// SetIP configures the utun interface as a point-to-point link to peer
+// (the server's overlay gateway IP) and routes the overlay subnet through
+// it, since macOS only routes the peer address on point-to-point links.
+func (t *TUNInterface) SetIP(ip, peer, netmask string) error {
+	if peer == "" {
+		return fmt.Errorf("failed to set IP: peer address is required on utun interfaces")
+	}
+
+	// ifconfig utun3 10.200.0.10 10.200.0.1 netmask 255.255.0.0
+	cmd := exec.Command("ifconfig", t.name, "inet", ip, peer, "netmask", netmask)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to set IP: %w", err)
 	}
 
+	// route add -net 10.200.0.0/16 -interface utun3
+	mask := net.IPMask(net.ParseIP(netmask).To4())
+	subnet := &net.IPNet{IP: net.ParseIP(ip).Mask(mask), Mask: mask}
+	cmd = exec.Command("route", "-q", "-n", "add", "-net", subnet.String(), "-interface", t.name)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to add overlay route %s: %w", subnet, err)
+	}
+
+	return nil
+}
+
+// ClearIP removes the overlay route added by SetIP, the address itself is
+// replaced by the next SetIP
+func (t *TUNInterface) ClearIP(ip, peer, netmask string) error {
+	// route delete -net 10.200.0.0/16 -interface utun3
+	mask := net.IPMask(net.ParseIP(netmask).To4())
+	subnet := &net.IPNet{IP: net.ParseIP(ip).Mask(mask), Mask: mask}
+	cmd := exec.Command("route", "-q", "-n", "delete", "-net", subnet.String(), "-interface", t.name)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to delete overlay route %s: %w", subnet, err)
+	}
+
 	return nil
 }
 
@@ -62,6 +164,23 @@ func (t *TUNInterface) SetMTU(mtu int) error {
 	return nil
 }
 
+// SetTxQueueLen is not supported on utun interfaces
+func (t *TUNInterface) SetTxQueueLen(qlen int) error {
+	return fmt.Errorf("txqueuelen is not supported on macOS")
+}
+
+// DisableIPv6 removes IPv6 from the utun interface, including the
+// link-local address macOS may assign
+func (t *TUNInterface) DisableIPv6() error {
+	// ifconfig utun3 inet6 -autoconfig removes autoconfigured addresses
+	cmd := exec.Command("ifconfig", t.name, "inet6", "-autoconfig")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to disable IPv6: %w", err)
+	}
+
+	return nil
+}
+
 // Up brings the interface up
 func (t *TUNInterface) Up() error {
 	cmd := exec.Command("ifconfig", t.name, "up")
@@ -82,19 +201,19 @@ func (t *TUNInterface) Down() error {
 	return nil
 }
 
-// Read reads a packet from the TUN interface
+// Read reads a frame from the TUN interface, see tunFraming
 func (t *TUNInterface) Read(buf []byte) (int, error) {
-	return t.iface.Read(buf)
+	return t.file.Read(buf)
 }
 
-// Write writes a packet to the TUN interface
+// Write writes a frame to the TUN interface, see tunFraming
 func (t *TUNInterface) Write(buf []byte) (int, error) {
-	return t.iface.Write(buf)
+	return t.file.Write(buf)
 }
 
 // Close closes the TUN interface
 func (t *TUNInterface) Close() error {
-	return t.iface.Close()
+	return t.file.Close()
 }
 
 // Name returns the interface name
@@ -4,20 +4,29 @@ package agent
 
 import (
 	"fmt"
-	"os/exec"
+	"io"
+	"os"
 
 	"github.com/songgao/water"
+	"github.com/taills/EasyAnyLink/common/packet"
 )
 
+// tunFraming is the framing of packets on the device, water opens it with
+// IFF_NO_PI
+const tunFraming = packet.FramingRaw
+
 // TUNInterface represents a TUN interface
 type TUNInterface struct {
-	iface *water.Interface
-	name  string
-	mtu   int
+	iface  io.ReadWriteCloser
+	queues []io.ReadWriteCloser // additional IFF_MULTI_QUEUE queues
+	config water.Config
+	name   string
+	mtu    int
 }
 
-// NewTUNInterface creates a new TUN interface
-func NewTUNInterface(name string, mtu int) (*TUNInterface, error) {
+// NewTUNInterface creates a new TUN interface. An empty name lets the
+// kernel pick one.
+func NewTUNInterface(name string, mtu int, multiQueue bool) (*TUNInterface, error) {
 	config := water.Config{
 		DeviceType: water.TUN,
 	}
@@ -25,28 +34,75 @@ func NewTUNInterface(name string, mtu int) (*TUNInterface, error) {
 	if name != "" {
 		config.Name = name
 	}
+	config.MultiQueue = multiQueue
 
-	iface, err := water.New(config)
+	iface, ifName, err := openTUN(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create TUN interface: %w", err)
 	}
 
+	config.Name = ifName
 	tun := &TUNInterface{
-		iface: iface,
-		name:  iface.Name(),
-		mtu:   mtu,
+		iface:  iface,
+		config: config,
+		name:   ifName,
+		mtu:    mtu,
 	}
 
 	return tun, nil
 }
 
-// SetIP sets the IP address of the TUN interface
-func (t *TUNInterface) SetIP(ip, netmask string) error {
+// OpenQueues attaches queues to a multi-queue device until it has n queues
+func (t *TUNInterface) OpenQueues(n int) error {
+	if !t.config.MultiQueue {
+		return fmt.Errorf("failed to open TUN queues: interface is not multi-queue")
+	}
+
+	for len(t.queues)+1 < n {
+		queue, _, err := openTUN(t.config)
+		if err != nil {
+			return fmt.Errorf("failed to open TUN queue %d: %w", len(t.queues)+1, err)
+		}
+		t.queues = append(t.queues, queue)
+	}
+
+	return nil
+}
+
+// openTUN opens the device of config and returns it with the name of its
+// interface. An unprivileged agent has its helper open it.
+func openTUN(config water.Config) (io.ReadWriteCloser, string, error) {
+	if privHelper != nil {
+		return privHelper.openTUN(config.Name, config.MultiQueue)
+	}
+	iface, err := water.New(config)
+	if err != nil {
+		return nil, "", err
+	}
+	return iface, iface.Name(), nil
+}
+
+// NumQueues returns the number of open queues
+func (t *TUNInterface) NumQueues() int {
+	return len(t.queues) + 1
+}
+
+// Queue returns queue i; queue 0 is the interface itself
+func (t *TUNInterface) Queue(i int) io.ReadWriter {
+	if i == 0 {
+		return t.iface
+	}
+	return t.queues[i-1]
+}
+
+// SetIP sets the IP address of the TUN interface. The peer address is
+// only needed on point-to-point platforms and is ignored here.
+func (t *TUNInterface) SetIP(ip, peer, netmask string) error {
 	// Calculate CIDR from netmask
 	cidr := netmaskToCIDR(netmask)
 
 	// ip addr add 10.200.0.10/16 dev tun0
-	cmd := exec.Command("ip", "addr", "add", fmt.Sprintf("%s/%d", ip, cidr), "dev", t.name)
+	cmd := privCommand("ip", "addr", "add", fmt.Sprintf("%s/%d", ip, cidr), "dev", t.name)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to set IP: %w", err)
 	}
@@ -54,9 +110,22 @@ func (t *TUNInterface) SetIP(ip, netmask string) error {
 	return nil
 }
 
+// ClearIP removes an address set with SetIP
+func (t *TUNInterface) ClearIP(ip, peer, netmask string) error {
+	cidr := netmaskToCIDR(netmask)
+
+	// ip addr del 10.200.0.10/16 dev tun0
+	cmd := privCommand("ip", "addr", "del", fmt.Sprintf("%s/%d", ip, cidr), "dev", t.name)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to clear IP: %w", err)
+	}
+
+	return nil
+}
+
 // SetMTU sets the MTU of the TUN interface
 func (t *TUNInterface) SetMTU(mtu int) error {
-	cmd := exec.Command("ip", "link", "set", "dev", t.name, "mtu", fmt.Sprintf("%d", mtu))
+	cmd := privCommand("ip", "link", "set", "dev", t.name, "mtu", fmt.Sprintf("%d", mtu))
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to set MTU: %w", err)
 	}
@@ -65,9 +134,32 @@ func (t *TUNInterface) SetMTU(mtu int) error {
 	return nil
 }
 
+// SetTxQueueLen sets the transmit queue length of the TUN interface
+func (t *TUNInterface) SetTxQueueLen(qlen int) error {
+	cmd := privCommand("ip", "link", "set", "dev", t.name, "txqueuelen", fmt.Sprintf("%d", qlen))
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to set txqueuelen: %w", err)
+	}
+
+	return nil
+}
+
+// DisableIPv6 stops the kernel from configuring IPv6 on the interface
+func (t *TUNInterface) DisableIPv6() error {
+	path := fmt.Sprintf("/proc/sys/net/ipv6/conf/%s/disable_ipv6", t.name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil // kernel without IPv6
+	}
+	if err := writeSystemFile(path, []byte("1")); err != nil {
+		return fmt.Errorf("failed to disable IPv6: %w", err)
+	}
+
+	return nil
+}
+
 // Up brings the interface up
 func (t *TUNInterface) Up() error {
-	cmd := exec.Command("ip", "link", "set", "dev", t.name, "up")
+	cmd := privCommand("ip", "link", "set", "dev", t.name, "up")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to bring interface up: %w", err)
 	}
@@ -77,7 +169,7 @@ func (t *TUNInterface) Up() error {
 
 // Down brings the interface down
 func (t *TUNInterface) Down() error {
-	cmd := exec.Command("ip", "link", "set", "dev", t.name, "down")
+	cmd := privCommand("ip", "link", "set", "dev", t.name, "down")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to bring interface down: %w", err)
 	}
@@ -85,18 +177,21 @@ func (t *TUNInterface) Down() error {
 	return nil
 }
 
-// Read reads a packet from the TUN interface
+// Read reads a frame from the TUN interface, see tunFraming
 func (t *TUNInterface) Read(buf []byte) (int, error) {
 	return t.iface.Read(buf)
 }
 
-// Write writes a packet to the TUN interface
+// Write writes a frame to the TUN interface, see tunFraming
 func (t *TUNInterface) Write(buf []byte) (int, error) {
 	return t.iface.Write(buf)
 }
 
-// Close closes the TUN interface
+// Close closes the TUN interface and all of its queues
 func (t *TUNInterface) Close() error {
+	for _, queue := range t.queues {
+		queue.Close()
+	}
 	return t.iface.Close()
 }
 
@@ -3,44 +3,104 @@
 package agent
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"log"
 	"os/exec"
+	"strings"
 
-	"github.com/songgao/water"
+	"github.com/taills/EasyAnyLink/common/packet"
+	"golang.org/x/sys/windows"
+	"golang.zx2c4.com/wintun"
 )
 
-// TUNInterface represents a TUN interface
+// tunFraming is the framing of packets on the device, Wintun passes raw IP
+const tunFraming = packet.FramingRaw
+
+// TUNInterface represents a TUN interface backed by a Wintun adapter
 type TUNInterface struct {
-	iface *water.Interface
-	name  string
-	mtu   int
+	adapter *wintun.Adapter
+	session *wintunSession
+	name    string
+	mtu     int
 }
 
-// NewTUNInterface creates a new TUN interface
-func NewTUNInterface(name string, mtu int) (*TUNInterface, error) {
-	config := water.Config{
-		DeviceType: water.TUN,
+// NewTUNInterface creates a new Wintun adapter and starts a session on it.
+// An adapter left behind by a previous run under the same name is closed
+// before the new one is created. Multi-queue is not available on Wintun.
+func NewTUNInterface(name string, mtu int, multiQueue bool) (*TUNInterface, error) {
+	if multiQueue {
+		log.Println("Multi-queue TUN is only supported on Linux, ignoring")
+	}
+
+	if name == "" {
+		name = "EasyAnyLink"
+	}
+
+	// Clean up an orphaned adapter from a crashed agent
+	if orphan, err := wintun.OpenAdapter(name); err == nil {
+		log.Printf("Removing orphaned Wintun adapter %s", name)
+		orphan.Close()
 	}
 
-	// Note: Windows version of water library may not support Name field
-	// The interface name will be auto-generated
+	adapter, err := createWintunAdapter(name, adapterGUID(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TUN interface: %w", err)
+	}
 
-	iface, err := water.New(config)
+	session, err := startWintunSession(adapter)
 	if err != nil {
+		adapter.Close()
 		return nil, fmt.Errorf("failed to create TUN interface: %w", err)
 	}
 
+	if version := wintunDriverVersion(); version != 0 {
+		log.Printf("Wintun driver %d.%d loaded", version>>16&0xffff, version&0xffff)
+	}
+
 	tun := &TUNInterface{
-		iface: iface,
-		name:  iface.Name(),
-		mtu:   mtu,
+		adapter: adapter,
+		session: session,
+		name:    name,
+		mtu:     mtu,
 	}
 
 	return tun, nil
 }
 
-// SetIP sets the IP address of the TUN interface
-func (t *TUNInterface) SetIP(ip, netmask string) error {
+// adapterGUID derives a stable adapter GUID from the interface name so
+// Windows reuses the same network profile across restarts
+func adapterGUID(name string) *windows.GUID {
+	sum := sha256.Sum256([]byte("EasyAnyLink:" + name))
+	guid := &windows.GUID{
+		Data1: binary.LittleEndian.Uint32(sum[0:4]),
+		Data2: binary.LittleEndian.Uint16(sum[4:6]),
+		Data3: binary.LittleEndian.Uint16(sum[6:8]),
+	}
+	copy(guid.Data4[:], sum[8:16])
+	return guid
+}
+
+// OpenQueues is not supported on Wintun interfaces
+func (t *TUNInterface) OpenQueues(n int) error {
+	return fmt.Errorf("multi-queue TUN is only supported on Linux")
+}
+
+// NumQueues returns the number of queues, always one
+func (t *TUNInterface) NumQueues() int {
+	return 1
+}
+
+// Queue returns the interface itself, the only queue
+func (t *TUNInterface) Queue(i int) io.ReadWriter {
+	return t
+}
+
+// SetIP sets the IP address of the TUN interface. The peer address is
+// only needed on point-to-point platforms and is ignored here.
+func (t *TUNInterface) SetIP(ip, peer, netmask string) error {
 	// netsh interface ip set address name="tun0" static 10.200.0.10 255.255.0.0
 	cmd := exec.Command("netsh", "interface", "ip", "set", "address",
 		fmt.Sprintf("name=%s", t.name), "static", ip, netmask)
@@ -51,6 +111,11 @@ func (t *TUNInterface) SetIP(ip, netmask string) error {
 	return nil
 }
 
+// ClearIP is a no-op, SetIP replaces the static address
+func (t *TUNInterface) ClearIP(ip, peer, netmask string) error {
+	return nil
+}
+
 // SetMTU sets the MTU of the TUN interface
 func (t *TUNInterface) SetMTU(mtu int) error {
 	// netsh interface ipv4 set subinterface "tun0" mtu=1400
@@ -64,6 +129,22 @@ func (t *TUNInterface) SetMTU(mtu int) error {
 	return nil
 }
 
+// SetTxQueueLen is not supported on Wintun adapters
+func (t *TUNInterface) SetTxQueueLen(qlen int) error {
+	return fmt.Errorf("txqueuelen is not supported on Windows")
+}
+
+// DisableIPv6 unbinds the IPv6 stack from the Wintun adapter
+func (t *TUNInterface) DisableIPv6() error {
+	cmd := exec.Command("powershell", "-NoProfile", "-Command",
+		fmt.Sprintf("Disable-NetAdapterBinding -Name '%s' -ComponentID ms_tcpip6", t.name))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to disable IPv6: %s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
 // Up brings the interface up
 func (t *TUNInterface) Up() error {
 	// netsh interface set interface name="tun0" admin=enabled
@@ -88,19 +169,21 @@ func (t *TUNInterface) Down() error {
 	return nil
 }
 
-// Read reads a packet from the TUN interface
+// Read reads a frame from the TUN interface, see tunFraming
 func (t *TUNInterface) Read(buf []byte) (int, error) {
-	return t.iface.Read(buf)
+	return t.session.ReadPacket(buf)
 }
 
-// Write writes a packet to the TUN interface
+// Write writes a frame to the TUN interface, see tunFraming
 func (t *TUNInterface) Write(buf []byte) (int, error) {
-	return t.iface.Write(buf)
+	return t.session.WritePacket(buf)
 }
 
-// Close closes the TUN interface
+// Close ends the session and removes the adapter
 func (t *TUNInterface) Close() error {
-	return t.iface.Close()
+	t.session.End()
+	t.adapter.Close()
+	return nil
 }
 
 // Name returns the interface name
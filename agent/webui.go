@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// webUIFiles is the page of the local web UI and its assets
+//
+//go:embed webui
+var webUIFiles embed.FS
+
+// webUI serves the local web UI: a page showing the connection, routes and
+// throughput, backed by the control API under /api. It only listens on
+// loopback, but any local user can reach it, unlike the control socket.
+type webUI struct {
+	server *http.Server
+}
+
+// startWebUI starts the web UI on the configured loopback address
+func (a *Agent) startWebUI() error {
+	listener, err := net.Listen("tcp", a.config.WebUI)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", a.config.WebUI, err)
+	}
+
+	// The UI shows and toggles the connection, dialing and profile
+	// switches stay on the socket
+	cs := &controlServer{agent: a, done: make(chan struct{})}
+	api := http.NewServeMux()
+	api.HandleFunc("/status", cs.handleStatus)
+	api.HandleFunc("/stats", cs.handleStats)
+	api.HandleFunc("/health", cs.handleHealth)
+	api.HandleFunc("/routes", cs.handleRoutes)
+	api.HandleFunc("/connect", cs.handleConnect)
+	api.HandleFunc("/disconnect", cs.handleDisconnect)
+
+	files, _ := fs.Sub(webUIFiles, "webui")
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", api))
+	mux.Handle("/", http.FileServerFS(files))
+
+	ui := &webUI{server: &http.Server{Handler: localOnly(mux), ReadHeaderTimeout: 5 * time.Second}}
+	go func() {
+		if err := ui.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Web UI stopped: %v", err)
+		}
+	}()
+
+	a.webUI = ui
+	log.Printf("Web UI listening on http://%s", listener.Addr())
+	return nil
+}
+
+// close stops the web UI
+func (ui *webUI) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	ui.server.Shutdown(ctx)
+}
+
+// localOnly rejects requests that do not address the UI by a loopback
+// name, as a page that rebinds its own name to 127.0.0.1 would, and
+// changes requested by the page of another origin
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); r.Method != http.MethodGet && origin != "" && origin != "http://"+r.Host {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		next.ServeHTTP(w, r)
+	})
+}
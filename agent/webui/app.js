@@ -0,0 +1,155 @@
+// Local web UI of the agent, polling the control API under /api
+"use strict";
+
+const pollInterval = 2000;
+let last = null; // previous status, for the throughput
+
+function bytes(n) {
+  const units = ["B", "KB", "MB", "GB", "TB"];
+  let i = 0;
+  while (n >= 1024 && i < units.length - 1) {
+    n /= 1024;
+    i++;
+  }
+  return n.toFixed(i ? 1 : 0) + " " + units[i];
+}
+
+async function api(path, method) {
+  const resp = await fetch("api" + path, { method: method || "GET" });
+  const body = await resp.json();
+  if (!resp.ok && !body.error) {
+    throw new Error(resp.statusText);
+  }
+  return body;
+}
+
+function row(cells) {
+  const tr = document.createElement("tr");
+  for (const text of cells) {
+    const td = document.createElement("td");
+    td.textContent = text || "";
+    tr.appendChild(td);
+  }
+  return tr;
+}
+
+function showStatus(status) {
+  const state = document.getElementById("state");
+  const toggle = document.getElementById("toggle");
+  if (status.connected) {
+    state.textContent = "Connected to " + status.server;
+    state.className = "state up";
+  } else if (status.paused) {
+    state.textContent = "Disconnected";
+    state.className = "state down";
+  } else {
+    state.textContent = "Reconnecting…";
+    state.className = "state down";
+  }
+  toggle.textContent = status.paused ? "Connect" : "Disconnect";
+  toggle.disabled = false;
+
+  const session = document.getElementById("session");
+  session.replaceChildren();
+  const fields = [
+    ["Overlay IP", status.assigned_ip],
+    ["Session", status.session_id],
+    ["Group", status.group],
+  ];
+  if (status.last_disconnect) {
+    const d = status.last_disconnect;
+    fields.push(["Last disconnect", d.reason + (d.detail ? ": " + d.detail : "") + " (" + new Date(d.time).toLocaleString() + ")"]);
+  }
+  for (const [name, value] of fields) {
+    if (!value) continue;
+    const dt = document.createElement("dt");
+    dt.textContent = name;
+    const dd = document.createElement("dd");
+    dd.textContent = value;
+    session.append(dt, dd);
+  }
+
+  const now = Date.now();
+  if (last) {
+    const seconds = (now - last.time) / 1000;
+    document.getElementById("tx-rate").textContent = bytes(Math.max(0, status.bytes_sent - last.sent) / seconds) + "/s";
+    document.getElementById("rx-rate").textContent = bytes(Math.max(0, status.bytes_received - last.received) / seconds) + "/s";
+  }
+  last = { time: now, sent: status.bytes_sent, received: status.bytes_received };
+  document.getElementById("tx-total").textContent = bytes(status.bytes_sent);
+  document.getElementById("rx-total").textContent = bytes(status.bytes_received);
+}
+
+function showRoutes(routes) {
+  const tbody = document.getElementById("routes");
+  tbody.replaceChildren();
+  if (!routes.length) {
+    tbody.appendChild(row(["No routes installed"]));
+  }
+  for (const r of routes) {
+    tbody.appendChild(row([r.destination, r.gateway, r.interface]));
+  }
+}
+
+function showHistory(history) {
+  const svg = document.getElementById("chart");
+  const samples = history.samples || [];
+  const max = Math.max(1, ...samples.map((s) => Math.max(s.bytes_sent, s.bytes_received)));
+  const line = (key) =>
+    samples
+      .map((s, i) => (i ? "L" : "M") + ((i / Math.max(1, samples.length - 1)) * 600).toFixed(1) + " " + (120 - (s[key] / max) * 115).toFixed(1))
+      .join(" ");
+  const ns = "http://www.w3.org/2000/svg";
+  svg.replaceChildren();
+  for (const [key, color] of [["bytes_sent", "#1f6feb"], ["bytes_received", "#1a7f37"]]) {
+    if (!samples.length) break;
+    const path = document.createElementNS(ns, "path");
+    path.setAttribute("d", line(key));
+    path.setAttribute("fill", "none");
+    path.setAttribute("stroke", color);
+    path.setAttribute("stroke-width", "2");
+    path.setAttribute("vector-effect", "non-scaling-stroke");
+    svg.appendChild(path);
+  }
+}
+
+function showError(err) {
+  const el = document.getElementById("error");
+  el.textContent = err ? String(err.message || err) : "";
+  el.hidden = !err;
+}
+
+async function refresh() {
+  try {
+    showStatus(await api("/status"));
+    showRoutes(await api("/routes"));
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+}
+
+async function refreshHistory() {
+  try {
+    showHistory(await api("/stats?last=1h"));
+  } catch (err) {
+    showError(err);
+  }
+}
+
+document.getElementById("toggle").addEventListener("click", async (event) => {
+  const button = event.currentTarget;
+  button.disabled = true;
+  try {
+    const status = await api(button.textContent === "Connect" ? "/connect" : "/disconnect", "POST");
+    showError(status.error);
+    showStatus(status);
+  } catch (err) {
+    showError(err);
+  }
+});
+
+refresh();
+refreshHistory();
+setInterval(refresh, pollInterval);
+setInterval(refreshHistory, 60000);
@@ -0,0 +1,141 @@
+//go:build windows
+
+package agent
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/sys/windows"
+	"golang.zx2c4.com/wintun"
+)
+
+// Wintun adapters (https://www.wintun.net) are driven through
+// golang.zx2c4.com/wintun. wintun.dll must be shipped next to the agent
+// binary; it installs the driver on first use.
+const (
+	wintunTunnelType   = "EasyAnyLink"
+	wintunRingCapacity = 0x400000 // 4 MiB, must be a power of two
+)
+
+// createWintunAdapter creates a new adapter with a stable GUID
+func createWintunAdapter(name string, guid *windows.GUID) (*wintun.Adapter, error) {
+	adapter, err := wintun.CreateAdapter(name, wintunTunnelType, guid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Wintun adapter (is wintun.dll next to the agent binary?): %w", err)
+	}
+	return adapter, nil
+}
+
+// wintunDriverVersion returns the running driver version, or 0 if not loaded
+func wintunDriverVersion() uint32 {
+	version, err := wintun.RunningVersion()
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// wintunSession wraps a Wintun session and its ring buffers. Readers and
+// writers hold mu for reading while they use the session; End takes it
+// for writing, so the session and the stop event are only released once
+// no call is inside the Wintun API or waiting on the events.
+type wintunSession struct {
+	session   wintun.Session
+	readEvent windows.Handle
+	stopEvent windows.Handle
+	closeOnce sync.Once
+	mu        sync.RWMutex
+	ended     bool
+}
+
+// startWintunSession starts a session with ring buffers of
+// wintunRingCapacity
+func startWintunSession(adapter *wintun.Adapter) (*wintunSession, error) {
+	session, err := adapter.StartSession(wintunRingCapacity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	stopEvent, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		session.End()
+		return nil, fmt.Errorf("failed to create stop event: %w", err)
+	}
+
+	return &wintunSession{
+		session:   session,
+		readEvent: session.ReadWaitEvent(),
+		stopEvent: stopEvent,
+	}, nil
+}
+
+// ReadPacket copies the next received packet into buf, waiting if the
+// receive ring is empty
+func (s *wintunSession) ReadPacket(buf []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for {
+		if s.ended {
+			return 0, windows.ERROR_HANDLE_EOF
+		}
+
+		packet, err := s.session.ReceivePacket()
+		if err == nil {
+			n := copy(buf, packet)
+			s.session.ReleaseReceivePacket(packet)
+			return n, nil
+		}
+
+		switch err {
+		case windows.ERROR_NO_MORE_ITEMS:
+			handles := []windows.Handle{s.readEvent, s.stopEvent}
+			event, err := windows.WaitForMultipleObjects(handles, false, windows.INFINITE)
+			if err != nil {
+				return 0, fmt.Errorf("failed to wait for packets: %w", err)
+			}
+			if event == windows.WAIT_OBJECT_0+1 {
+				return 0, windows.ERROR_HANDLE_EOF
+			}
+		case windows.ERROR_HANDLE_EOF:
+			return 0, err
+		default:
+			return 0, fmt.Errorf("failed to receive packet: %w", err)
+		}
+	}
+}
+
+// WritePacket queues a packet on the send ring
+func (s *wintunSession) WritePacket(packet []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.ended {
+		return 0, windows.ERROR_HANDLE_EOF
+	}
+
+	buf, err := s.session.AllocateSendPacket(len(packet))
+	if err != nil {
+		// ERROR_BUFFER_OVERFLOW means the ring is full; the packet is dropped
+		return 0, fmt.Errorf("failed to allocate send packet: %w", err)
+	}
+
+	copy(buf, packet)
+	s.session.SendPacket(buf)
+	return len(packet), nil
+}
+
+// End stops the session. It wakes up blocked readers and waits for all
+// readers and writers to return before ending the session.
+func (s *wintunSession) End() {
+	s.closeOnce.Do(func() {
+		windows.SetEvent(s.stopEvent)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.ended = true
+		s.session.End()
+		windows.CloseHandle(s.stopEvent)
+	})
+}
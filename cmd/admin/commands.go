@@ -0,0 +1,2014 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// run dispatches a command line to its handler
+func (c *cli) run(args []string) error {
+	switch args[0] {
+	case "agents":
+		return c.runAgents(args[1:])
+	case "stats":
+		return c.runStats(args[1:])
+	case "handoff":
+		return c.runHandoff(args[1:])
+	case "users":
+		return c.runUsers(args[1:])
+	case "routes":
+		return c.runRoutes(args[1:])
+	case "groups":
+		return c.runGroups(args[1:])
+	case "acl":
+		return c.runACL(args[1:])
+	case "rollouts":
+		return c.runRollouts(args[1:])
+	case "access":
+		return c.runAccess(args[1:])
+	case "audit":
+		return c.runAudit(args[1:])
+	case "traces":
+		return c.runTraces(args[1:])
+	case "handshakes":
+		return c.runHandshakes()
+	case "crypto-policy":
+		return c.runCryptoPolicy()
+	case "relay-queues":
+		return c.runRelayQueues()
+	case "keepalive":
+		return c.runKeepalive(args[1:])
+	case "faults":
+		return c.runFaults(args[1:])
+	case "usage":
+		return c.runUsage(args[1:])
+	case "inventory":
+		return c.runInventory(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// runAgents handles the agents subcommands
+func (c *cli) runAgents(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: agents list|get|archive|restore|approve|reject|reset-identity|history|set-group")
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("agents list", flag.ExitOnError)
+		agentType := fs.String("type", "", "Filter by type (client, gateway)")
+		agentStatus := fs.String("status", "", "Filter by status (online, offline, error)")
+		userID := fs.String("user", "", "Filter by owning user ID")
+		pageSize := fs.Int("page-size", 100, "Agents fetched per request")
+		archived := fs.Bool("archived", false, "List archived agents")
+		pending := fs.Bool("pending", false, "List agents awaiting approval")
+		labels := labelFlag{}
+		fs.Var(labels, "label", "Filter by metadata label key=value (repeatable)")
+		fs.Parse(args[1:])
+
+		req := &proto.ListAgentsRequest{
+			PageSize: int32(*pageSize),
+			UserId:   *userID,
+			Labels:   labels,
+			Archived: *archived,
+			Pending:  *pending,
+		}
+		if *agentType != "" {
+			t, ok := proto.AgentType_value[strings.ToUpper(*agentType)]
+			if !ok {
+				return fmt.Errorf("invalid type %q", *agentType)
+			}
+			req.Type = proto.AgentType(t)
+		}
+		if *agentStatus != "" {
+			st, ok := proto.AgentStatus_value[strings.ToUpper(*agentStatus)]
+			if !ok {
+				return fmt.Errorf("invalid status %q", *agentStatus)
+			}
+			req.Status = proto.AgentStatus(st)
+		}
+
+		agents, err := c.listAllAgents(req)
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(&proto.ListAgentsResponse{Agents: agents})
+		}
+		printAgents(agents)
+		return nil
+
+	case "get":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: agents get <agent-id>")
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		agent, err := c.client.GetAgent(ctx, &proto.GetAgentRequest{AgentId: args[1]})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(agent)
+		}
+		printAgent(agent)
+		return nil
+
+	case "archive", "restore", "approve", "reset-identity":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: agents %s <agent-id>", args[0])
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		var agent *proto.AgentDetail
+		var err error
+		switch args[0] {
+		case "archive":
+			agent, err = c.client.ArchiveAgent(ctx, &proto.ArchiveAgentRequest{AgentId: args[1]})
+		case "restore":
+			agent, err = c.client.RestoreAgent(ctx, &proto.RestoreAgentRequest{AgentId: args[1]})
+		case "reset-identity":
+			agent, err = c.client.ResetAgentIdentity(ctx, &proto.ResetAgentIdentityRequest{AgentId: args[1]})
+		default:
+			agent, err = c.client.ApproveAgent(ctx, &proto.ApproveAgentRequest{AgentId: args[1]})
+		}
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(agent)
+		}
+		printAgent(agent)
+		return nil
+
+	case "reject":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: agents reject <agent-id>")
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.RejectAgent(ctx, &proto.RejectAgentRequest{AgentId: args[1]})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		fmt.Printf("Rejected agent %s\n", args[1])
+		return nil
+
+	case "history":
+		fs := flag.NewFlagSet("agents history", flag.ExitOnError)
+		limit := fs.Int("limit", 50, "Maximum sessions to show")
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: agents history [-limit N] <agent-id>")
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.ListSessionHistory(ctx, &proto.ListSessionHistoryRequest{
+			AgentId: fs.Arg(0),
+			Limit:   int32(*limit),
+		})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		printSessionHistory(resp.Sessions)
+		return nil
+
+	case "set-group":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: agents set-group <agent-id> <group-id>")
+		}
+		groupID, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Errorf("invalid group ID %q", args[2])
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		agent, err := c.client.SetAgentGroup(ctx, &proto.SetAgentGroupRequest{
+			AgentId: args[1],
+			GroupId: int32(groupID),
+		})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(agent)
+		}
+		printAgent(agent)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown agents command %q", args[0])
+	}
+}
+
+// listAllAgents follows page tokens until all matching agents are fetched
+func (c *cli) listAllAgents(req *proto.ListAgentsRequest) ([]*proto.AgentDetail, error) {
+	var agents []*proto.AgentDetail
+	for {
+		ctx, cancel := c.context()
+		resp, err := c.client.ListAgents(ctx, req)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		agents = append(agents, resp.Agents...)
+		if resp.NextPageToken == "" {
+			return agents, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// runStats polls connected agents and prints traffic rates until interrupted
+func (c *cli) runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	interval := fs.Duration("interval", 5*time.Second, "Polling interval")
+	agentID := fs.String("agent", "", "Only show this agent")
+	fs.Parse(args)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	previous := make(map[string]*proto.AgentStats)
+	for {
+		var agents []*proto.AgentDetail
+		if *agentID != "" {
+			ctx, cancel := c.context()
+			agent, err := c.client.GetAgent(ctx, &proto.GetAgentRequest{AgentId: *agentID})
+			cancel()
+			if err != nil {
+				return err
+			}
+			agents = []*proto.AgentDetail{agent}
+		} else {
+			var err error
+			agents, err = c.listAllAgents(&proto.ListAgentsRequest{Status: proto.AgentStatus_ONLINE})
+			if err != nil {
+				return err
+			}
+		}
+
+		if c.jsonOutput {
+			if err := printJSON(&proto.ListAgentsResponse{Agents: agents}); err != nil {
+				return err
+			}
+		} else {
+			printStats(agents, previous, *interval)
+		}
+
+		for _, agent := range agents {
+			previous[agent.AgentId] = agent.Stats
+		}
+
+		select {
+		case <-sigChan:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runUsers handles the users subcommands
+func (c *cli) runUsers(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: users list|get|create|update|delete|rotate-key")
+	}
+
+	switch args[0] {
+	case "list", "get", "update", "delete":
+		return c.runUserDetails(args)
+	}
+
+	var (
+		user *proto.UserResponse
+		err  error
+	)
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("users create", flag.ExitOnError)
+		username := fs.String("username", "", "Username (required)")
+		email := fs.String("email", "", "Email address")
+		role := fs.String("role", "user", "Role (user, owner, admin, support, auditor)")
+		password := fs.String("password", "", "Password (random if empty)")
+		maxAgents := fs.Int("max-agents", 0, "Maximum agents, 0 for the server default")
+		maxBandwidth := fs.Int("max-bandwidth", 0, "KB/s across all agents, 0 for unlimited")
+		transferCap := fs.Uint64("transfer-cap", 0, "Bytes relayed per month, 0 for unlimited")
+		sitePrefixes := fs.String("site-prefixes", "", "Comma-separated CIDRs the user's gateways may advertise as sites, empty for any")
+		fs.Parse(args[1:])
+
+		ctx, cancel := c.context()
+		defer cancel()
+		user, err = c.client.CreateUser(ctx, &proto.CreateUserRequest{
+			Username:     *username,
+			Email:        *email,
+			Role:         *role,
+			Password:     *password,
+			MaxAgents:    int32(*maxAgents),
+			MaxBandwidth: int32(*maxBandwidth),
+			TransferCap:  *transferCap,
+			SitePrefixes: splitList(*sitePrefixes),
+		})
+
+	case "rotate-key":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: users rotate-key <user-id>")
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+		user, err = c.client.RotateAPIKey(ctx, &proto.RotateAPIKeyRequest{UserId: args[1]})
+
+	default:
+		return fmt.Errorf("unknown users command %q", args[0])
+	}
+
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(user)
+	}
+
+	fmt.Printf("User ID:  %s\n", user.UserId)
+	fmt.Printf("Username: %s\n", user.Username)
+	fmt.Printf("Role:     %s\n", user.Role)
+	fmt.Printf("API Key:  %s\n", user.ApiKey)
+	return nil
+}
+
+// runUserDetails handles the users subcommands that show account details
+func (c *cli) runUserDetails(args []string) error {
+	switch args[0] {
+	case "list":
+		ctx, cancel := c.context()
+		defer cancel()
+		resp, err := c.client.ListUsers(ctx, &proto.ListUsersRequest{})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		printUsers(resp.Users)
+		return nil
+
+	case "get":
+		fs := flag.NewFlagSet("users get", flag.ExitOnError)
+		months := fs.Int("months", 12, "Months of usage history")
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: users get [-months N] <user-id>")
+		}
+
+		ctx, cancel := c.context()
+		defer cancel()
+		user, err := c.client.GetUser(ctx, &proto.GetUserRequest{
+			UserId:      fs.Arg(0),
+			UsageMonths: int32(*months),
+		})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(user)
+		}
+		printUser(user)
+		return nil
+
+	case "update":
+		fs := flag.NewFlagSet("users update", flag.ExitOnError)
+		email := fs.String("email", "", "Email address")
+		role := fs.String("role", "", "Role (user, owner, admin, support, auditor)")
+		userStatus := fs.String("status", "", "Status (active, suspended, disabled)")
+		maxAgents := fs.Int("max-agents", 0, "Maximum agents, 0 for the server default")
+		maxBandwidth := fs.Int("max-bandwidth", 0, "KB/s across all agents, 0 for unlimited")
+		transferCap := fs.Uint64("transfer-cap", 0, "Bytes relayed per month, 0 for unlimited")
+		sitePrefixes := fs.String("site-prefixes", "", "Comma-separated CIDRs the user's gateways may advertise as sites, empty for any")
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: users update [flags] <user-id>")
+		}
+
+		ctx, cancel := c.context()
+		defer cancel()
+
+		// Only change the fields given on the command line
+		current, err := c.client.GetUser(ctx, &proto.GetUserRequest{UserId: fs.Arg(0), UsageMonths: 1})
+		if err != nil {
+			return err
+		}
+		req := &proto.UpdateUserRequest{
+			UserId:       current.UserId,
+			Email:        current.Email,
+			Role:         current.Role,
+			Status:       current.Status,
+			MaxAgents:    current.MaxAgents,
+			MaxBandwidth: current.MaxBandwidth,
+			TransferCap:  current.TransferCap,
+			SitePrefixes: current.SitePrefixes,
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "email":
+				req.Email = *email
+			case "role":
+				req.Role = *role
+			case "status":
+				req.Status = *userStatus
+			case "max-agents":
+				req.MaxAgents = int32(*maxAgents)
+			case "max-bandwidth":
+				req.MaxBandwidth = int32(*maxBandwidth)
+			case "transfer-cap":
+				req.TransferCap = *transferCap
+			case "site-prefixes":
+				req.SitePrefixes = splitList(*sitePrefixes)
+			}
+		})
+
+		user, err := c.client.UpdateUser(ctx, req)
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(user)
+		}
+		printUser(user)
+		return nil
+
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: users delete <user-id>")
+		}
+
+		ctx, cancel := c.context()
+		defer cancel()
+		resp, err := c.client.DeleteUser(ctx, &proto.DeleteUserRequest{UserId: args[1]})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		fmt.Printf("User %s deleted with %d agent(s)\n", args[1], resp.AgentsDeleted)
+		return nil
+	}
+
+	return fmt.Errorf("unknown users command %q", args[0])
+}
+
+// runTraces handles the relay traces subcommands
+func (c *cli) runTraces(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: traces list|sample")
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("traces list", flag.ExitOnError)
+		agentID := fs.String("agent", "", "Only traces from or to this agent")
+		limit := fs.Int("limit", 50, "Maximum number of traces, 0 for all")
+		fs.Parse(args[1:])
+
+		ctx, cancel := c.context()
+		defer cancel()
+		resp, err := c.client.ListRelayTraces(ctx, &proto.ListRelayTracesRequest{
+			AgentId: *agentID,
+			Limit:   int32(*limit),
+		})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		if resp.SampleRate == 0 {
+			fmt.Fprintln(os.Stderr, "Relay tracing is disabled, enable it with: traces sample <N>")
+		}
+		printTraces(resp.Traces)
+		return nil
+
+	case "sample":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: traces sample <N> (trace 1 in N packets, 0 disables)")
+		}
+		rate, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid sample rate %q", args[1])
+		}
+
+		ctx, cancel := c.context()
+		defer cancel()
+		resp, err := c.client.SetRelayTracing(ctx, &proto.SetRelayTracingRequest{SampleRate: uint32(rate)})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		if resp.SampleRate == 0 {
+			fmt.Println("Relay tracing disabled")
+		} else {
+			fmt.Printf("Tracing 1 in %d relayed packets, keeping the last %d\n", resp.SampleRate, resp.BufferSize)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown traces command %q", args[0])
+	}
+}
+
+// runUsage handles the usage subcommands
+func (c *cli) runUsage(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: usage export|report [flags]")
+	}
+	switch args[0] {
+	case "export":
+		return c.runUsageExport(args[1:])
+	case "report":
+		return c.runUsageReport(args[1:])
+	default:
+		return fmt.Errorf("unknown usage command %q", args[0])
+	}
+}
+
+// runUsageReport lists the hourly or daily rollups of ended sessions
+func (c *cli) runUsageReport(args []string) error {
+	fs := flag.NewFlagSet("usage report", flag.ExitOnError)
+	daily := fs.Bool("daily", false, "Show daily instead of hourly rollups")
+	agentID := fs.String("agent", "", "Only this agent")
+	userID := fs.String("user", "", "Only agents of this user")
+	since := fs.Duration("since", 0, "Only periods of the last duration, e.g. 720h, default all kept")
+	limit := fs.Int("limit", 50, "Max rows")
+	fs.Parse(args)
+
+	req := &proto.ListTrafficRollupsRequest{
+		Period:  "hourly",
+		AgentId: *agentID,
+		UserId:  *userID,
+		Limit:   int32(*limit),
+	}
+	if *daily {
+		req.Period = "daily"
+	}
+	if *since > 0 {
+		req.Since = timestamppb.New(time.Now().Add(-*since))
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+	resp, err := c.client.ListTrafficRollups(ctx, req)
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+	printRollups(resp.Rollups, *daily)
+	return nil
+}
+
+// runUsageExport exports the monthly usage of every user
+func (c *cli) runUsageExport(args []string) error {
+	fs := flag.NewFlagSet("usage export", flag.ExitOnError)
+	month := fs.String("month", "", "Month to export as YYYY-MM (UTC), default the current month")
+	format := fs.String("format", "csv", "Export format (csv, json)")
+	output := fs.String("o", "", "Write the export to a file instead of stdout")
+	fs.Parse(args)
+
+	ctx, cancel := c.context()
+	defer cancel()
+	resp, err := c.client.ExportUsage(ctx, &proto.ExportUsageRequest{
+		Month:  *month,
+		Format: *format,
+	})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(resp.Data)
+		return err
+	}
+	if err := os.WriteFile(*output, resp.Data, 0600); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Usage for %s written to %s\n", resp.Month, *output)
+	return nil
+}
+
+// runInventory exports the agent registry for hosts files and Ansible
+func (c *cli) runInventory(args []string) error {
+	fs := flag.NewFlagSet("inventory", flag.ExitOnError)
+	format := fs.String("format", "hosts", "Export format (hosts, ansible)")
+	domain := fs.String("domain", "", "Domain appended to the host names")
+	output := fs.String("o", "", "Write the inventory to a file instead of stdout")
+	fs.Parse(args)
+
+	ctx, cancel := c.context()
+	defer cancel()
+	resp, err := c.client.ExportInventory(ctx, &proto.ExportInventoryRequest{
+		Format: *format,
+		Domain: *domain,
+	})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(resp.Data)
+		return err
+	}
+	if err := os.WriteFile(*output, resp.Data, 0644); err != nil {
+		return fmt.Errorf("failed to write inventory: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "%d agents written to %s\n", resp.AgentCount, *output)
+	return nil
+}
+
+// runHandshakes prints QUIC handshake address validation counters
+func (c *cli) runHandshakes() error {
+	ctx, cancel := c.context()
+	defer cancel()
+	resp, err := c.client.GetHandshakeStats(ctx, &proto.GetHandshakeStatsRequest{})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Validated:\t%d\n", resp.Validated)
+	fmt.Fprintf(w, "Unvalidated:\t%d\n", resp.Unvalidated)
+	fmt.Fprintf(w, "Retries sent:\t%d\n", resp.Retries)
+	fmt.Fprintf(w, "Invalid tokens:\t%d\n", resp.InvalidTokens)
+	if resp.RetryThreshold > 0 {
+		fmt.Fprintf(w, "Retry:\tabove %d handshakes/s (active: %t)\n", resp.RetryThreshold, resp.RetryActive)
+	} else {
+		fmt.Fprintf(w, "Retry:\tdisabled\n")
+	}
+	return w.Flush()
+}
+
+// runCryptoPolicy shows the crypto policy enforced by the server
+func (c *cli) runCryptoPolicy() error {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.client.GetCryptoPolicy(ctx, &proto.GetCryptoPolicyRequest{})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+
+	curves := strings.Join(resp.Curves, ", ")
+	if curves == "" {
+		curves = "Go defaults"
+	}
+	fmt.Printf("Policy:         %s\n", resp.Policy)
+	fmt.Printf("FIPS build:     %t\n", resp.FipsBuild)
+	fmt.Printf("FIPS module:    %t\n", resp.FipsModule)
+	fmt.Printf("Cipher suites:  %s\n", strings.Join(resp.CipherSuites, ", "))
+	fmt.Printf("Curves:         %s\n", curves)
+	return nil
+}
+
+// runRelayQueues shows the per traffic class counters of the server relay
+// queues
+func (c *cli) runRelayQueues() error {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.client.GetRelayQueueStats(ctx, &proto.GetRelayQueueStatsRequest{})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+	printTrafficClasses(resp.Classes)
+	if m := resp.Multicast; m != nil {
+		fmt.Printf("\nMulticast (%s): %d dropped, %d relayed, %d unicast, %d rate limited, %d copies delivered\n",
+			m.Policy, m.Dropped, m.Relayed, m.Unicast, m.RateLimited, m.Copies)
+	}
+	fmt.Printf("\nSlow consumers: %d, %d sessions ended for stalling\n", len(resp.SlowConsumers), resp.SlowConsumersTerminated)
+	for _, slow := range resp.SlowConsumers {
+		fmt.Printf("  %s  session %s  %s\n", slow.AgentId, slow.SessionId, formatFlowControl(slow.FlowControl))
+	}
+	return nil
+}
+
+// formatFlowControl describes the relay sends to an agent
+func formatFlowControl(fc *proto.FlowControl) string {
+	return fmt.Sprintf("%d pending, stalled %dms, longest stall %dms, %d slow sends",
+		fc.QueueDepth, fc.StalledMs, fc.MaxStallMs, fc.SlowSends)
+}
+
+// runKeepalive shows the heartbeat settings sent to agents, or changes
+// them when a flag is given
+func (c *cli) runKeepalive(args []string) error {
+	fs := flag.NewFlagSet("keepalive", flag.ExitOnError)
+	interval := fs.Duration("interval", 0, "Heartbeat interval, whole seconds")
+	timeout := fs.Duration("timeout", 0, "Time without a heartbeat response before agents reconnect, whole seconds")
+	fs.Parse(args)
+
+	if *interval < 0 || *interval%time.Second != 0 || *timeout < 0 || *timeout%time.Second != 0 {
+		return fmt.Errorf("interval and timeout must be whole seconds")
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	var resp *proto.KeepaliveResponse
+	var err error
+	if *interval == 0 && *timeout == 0 {
+		resp, err = c.client.GetKeepalive(ctx, &proto.GetKeepaliveRequest{})
+	} else {
+		resp, err = c.client.SetKeepalive(ctx, &proto.SetKeepaliveRequest{
+			Interval: int32(*interval / time.Second),
+			Timeout:  int32(*timeout / time.Second),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+	fmt.Printf("Heartbeat interval: %s\n", time.Duration(resp.Interval)*time.Second)
+	fmt.Printf("Dead-link timeout:  %s\n", time.Duration(resp.Timeout)*time.Second)
+	return nil
+}
+
+// runFaults shows the faults a server built with the chaos tag injects,
+// or replaces them when a flag is given
+func (c *cli) runFaults(args []string) error {
+	fs := flag.NewFlagSet("faults", flag.ExitOnError)
+	relayDrop := fs.Float64("relay-drop", 0, "Fraction of relayed packets dropped, 0 to 1")
+	heartbeatDelay := fs.Duration("heartbeat-delay", 0, "Delay of every heartbeat response")
+	dbFailure := fs.Float64("db-failure", 0, "Fraction of database calls failed, 0 to 1")
+	off := fs.Bool("off", false, "Stop injecting faults")
+	fs.Parse(args)
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	var resp *proto.FaultInjection
+	var err error
+	if fs.NFlag() == 0 {
+		resp, err = c.client.GetFaultInjection(ctx, &proto.GetFaultInjectionRequest{})
+	} else {
+		req := &proto.FaultInjection{
+			RelayDropRate:    *relayDrop,
+			HeartbeatDelayMs: int32(*heartbeatDelay / time.Millisecond),
+			DbFailureRate:    *dbFailure,
+		}
+		if *off {
+			req = &proto.FaultInjection{}
+		}
+		resp, err = c.client.SetFaultInjection(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+	fmt.Printf("Relayed packets dropped: %.1f%%\n", resp.RelayDropRate*100)
+	fmt.Printf("Heartbeat delay:         %s\n", time.Duration(resp.HeartbeatDelayMs)*time.Millisecond)
+	fmt.Printf("Database calls failed:   %.1f%%\n", resp.DbFailureRate*100)
+	return nil
+}
+
+// runRoutes handles the routes subcommands
+func (c *cli) runRoutes(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: routes list|add|update|delete")
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("routes list", flag.ExitOnError)
+		groupID := fs.Int("group", 0, "List the rules of a group instead of an agent")
+		fs.Parse(args[1:])
+		req := &proto.ListRoutingRulesRequest{GroupId: int32(*groupID)}
+		switch {
+		case *groupID == 0 && fs.NArg() == 1:
+			req.AgentId = fs.Arg(0)
+		case *groupID == 0 || fs.NArg() != 0:
+			return fmt.Errorf("usage: routes list <agent-id> | routes list -group ID")
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.ListRoutingRules(ctx, req)
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		printRules(resp.Rules)
+		return nil
+
+	case "add", "update":
+		fs := flag.NewFlagSet("routes "+args[0], flag.ExitOnError)
+		agentID := fs.String("agent", "", "Agent ID (add only)")
+		groupID := fs.Int("group", 0, "Group ID instead of an agent (add only)")
+		ruleID := fs.Int("id", 0, "Rule ID (update only)")
+		action := fs.String("action", "forward", "Action (forward, direct, deny)")
+		destination := fs.String("dest", "", "Destination CIDR")
+		gatewayID := fs.String("gateway", "", "Gateway agent ID (forward only)")
+		priority := fs.Int("priority", 100, "Priority (lower = higher priority)")
+		disabled := fs.Bool("disabled", false, "Create the rule disabled")
+		posture := fs.String("posture", "", "Posture policy the agent must meet (forward and direct only)")
+		wf := addWindowFlags(fs)
+		cf := addChangeFlags(fs)
+		fs.Parse(args[1:])
+
+		window, err := wf.window()
+		if err != nil {
+			return err
+		}
+		a, ok := proto.RouteAction_value[strings.ToUpper(*action)]
+		if !ok {
+			return fmt.Errorf("invalid action %q", *action)
+		}
+		rule := &proto.RoutingRule{
+			RuleId:      int32(*ruleID),
+			Action:      proto.RouteAction(a),
+			Destination: *destination,
+			GatewayId:   *gatewayID,
+			Priority:    int32(*priority),
+			Enabled:     !*disabled,
+			Window:      window,
+			Posture:     *posture,
+		}
+
+		if cf.staged() {
+			return c.stageChange(cf, cf.request("routing", args[0], &proto.StageRolloutRequest{
+				RoutingRule: rule,
+				AgentId:     *agentID,
+				GroupId:     int32(*groupID),
+			}))
+		}
+
+		ctx, cancel := c.context()
+		defer cancel()
+
+		var resp *proto.RoutingRuleResponse
+		if args[0] == "add" {
+			resp, err = c.client.AddRoutingRule(ctx, &proto.AddRoutingRuleRequest{
+				AgentId: *agentID,
+				GroupId: int32(*groupID),
+				Rule:    rule,
+			})
+		} else {
+			resp, err = c.client.UpdateRoutingRule(ctx, &proto.UpdateRoutingRuleRequest{Rule: rule})
+		}
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		printRules([]*proto.RoutingRule{resp.Rule})
+		return nil
+
+	case "delete":
+		fs := flag.NewFlagSet("routes delete", flag.ExitOnError)
+		cf := addChangeFlags(fs)
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: routes delete [-dry-run | -canary N | -canary-group ID] <rule-id>")
+		}
+		ruleID, err := strconv.Atoi(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("invalid rule ID %q", fs.Arg(0))
+		}
+		if cf.staged() {
+			return c.stageChange(cf, cf.request("routing", "delete", &proto.StageRolloutRequest{
+				RoutingRule: &proto.RoutingRule{RuleId: int32(ruleID)},
+			}))
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.DeleteRoutingRule(ctx, &proto.DeleteRoutingRuleRequest{RuleId: int32(ruleID)})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		if resp.GroupId != 0 {
+			fmt.Printf("Deleted rule %d of group %d\n", ruleID, resp.GroupId)
+		} else {
+			fmt.Printf("Deleted rule %d of agent %s\n", ruleID, resp.AgentId)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown routes command %q", args[0])
+	}
+}
+
+// runGroups handles the groups subcommands
+func (c *cli) runGroups(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: groups list|create|update|delete")
+	}
+
+	switch args[0] {
+	case "list":
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.ListAgentGroups(ctx, &proto.ListAgentGroupsRequest{})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		printGroups(resp.Groups)
+		return nil
+
+	case "create", "update":
+		fs := flag.NewFlagSet("groups "+args[0], flag.ExitOnError)
+		groupID := fs.Int("id", 0, "Group ID (update only)")
+		name := fs.String("name", "", "Group name")
+		description := fs.String("description", "", "Description")
+		dnsServers := fs.String("dns", "", "Comma-separated DNS servers of client agents")
+		dnsSearch := fs.String("search", "", "Comma-separated DNS search domains")
+		bandwidth := fs.Int("bandwidth", 0, "Per-agent bandwidth limit in KB/s, 0 for unlimited")
+		fs.Parse(args[1:])
+
+		group := &proto.AgentGroup{
+			GroupId:        int32(*groupID),
+			Name:           *name,
+			Description:    *description,
+			DnsServers:     splitList(*dnsServers),
+			DnsSearch:      splitList(*dnsSearch),
+			BandwidthLimit: int32(*bandwidth),
+		}
+
+		ctx, cancel := c.context()
+		defer cancel()
+
+		var err error
+		if args[0] == "create" {
+			group, err = c.client.CreateAgentGroup(ctx, group)
+		} else {
+			group, err = c.client.UpdateAgentGroup(ctx, group)
+		}
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(group)
+		}
+		printGroups([]*proto.AgentGroup{group})
+		return nil
+
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: groups delete <group-id>")
+		}
+		groupID, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid group ID %q", args[1])
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.DeleteAgentGroup(ctx, &proto.DeleteAgentGroupRequest{GroupId: int32(groupID)})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		fmt.Printf("Deleted group %d\n", groupID)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown groups command %q", args[0])
+	}
+}
+
+// splitList parses a comma-separated flag value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// runACL handles the acl subcommands
+func (c *cli) runACL(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: acl list|add|update|delete")
+	}
+
+	switch args[0] {
+	case "list":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: acl list <user-id>")
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.ListACLRules(ctx, &proto.ListACLRulesRequest{UserId: args[1]})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		printACLRules(resp.Rules)
+		return nil
+
+	case "add", "update":
+		fs := flag.NewFlagSet("acl "+args[0], flag.ExitOnError)
+		userID := fs.String("user", "", "User ID (add only)")
+		ruleID := fs.Int("id", 0, "Rule ID (update only)")
+		action := fs.String("action", "deny", "Action (allow, deny)")
+		source := fs.String("src", "", "Source CIDR, empty for any")
+		destination := fs.String("dest", "", "Destination CIDR, empty for any")
+		protocol := fs.String("proto", "any", "Protocol (any, tcp, udp, icmp)")
+		ports := fs.String("ports", "", "Destination port or range N-M (tcp and udp only)")
+		uid := fs.Int("uid", -1, "Local user on the agent host that sends the traffic, -1 for any")
+		priority := fs.Int("priority", 100, "Priority (lower = higher priority)")
+		disabled := fs.Bool("disabled", false, "Create the rule disabled")
+		posture := fs.String("posture", "", "Posture policy the sending agent must meet (allow only)")
+		wf := addWindowFlags(fs)
+		cf := addChangeFlags(fs)
+		fs.Parse(args[1:])
+
+		window, err := wf.window()
+		if err != nil {
+			return err
+		}
+		rule := &proto.ACLRule{
+			RuleId:      int32(*ruleID),
+			UserId:      *userID,
+			Source:      *source,
+			Destination: *destination,
+			Protocol:    *protocol,
+			Action:      *action,
+			Priority:    int32(*priority),
+			Enabled:     !*disabled,
+			Window:      window,
+			Posture:     *posture,
+		}
+		if *ports != "" {
+			from, to, err := parsePortRange(*ports)
+			if err != nil {
+				return err
+			}
+			rule.PortFrom, rule.PortTo = from, to
+		}
+		if *uid >= 0 {
+			sourceUID := uint32(*uid)
+			rule.SourceUid = &sourceUID
+		}
+		if cf.staged() {
+			return c.stageChange(cf, cf.request("acl", args[0], &proto.StageRolloutRequest{AclRule: rule}))
+		}
+
+		ctx, cancel := c.context()
+		defer cancel()
+
+		if args[0] == "add" {
+			rule, err = c.client.AddACLRule(ctx, &proto.AddACLRuleRequest{Rule: rule})
+		} else {
+			rule, err = c.client.UpdateACLRule(ctx, &proto.UpdateACLRuleRequest{Rule: rule})
+		}
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(rule)
+		}
+		printACLRules([]*proto.ACLRule{rule})
+		return nil
+
+	case "delete":
+		fs := flag.NewFlagSet("acl delete", flag.ExitOnError)
+		cf := addChangeFlags(fs)
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: acl delete [-dry-run | -canary N | -canary-group ID] <rule-id>")
+		}
+		ruleID, err := strconv.Atoi(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("invalid rule ID %q", fs.Arg(0))
+		}
+		if cf.staged() {
+			return c.stageChange(cf, cf.request("acl", "delete", &proto.StageRolloutRequest{
+				AclRule: &proto.ACLRule{RuleId: int32(ruleID)},
+			}))
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.DeleteACLRule(ctx, &proto.DeleteACLRuleRequest{RuleId: int32(ruleID)})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		fmt.Printf("Deleted ACL rule %d of user %s\n", ruleID, resp.UserId)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown acl command %q", args[0])
+	}
+}
+
+// runRollouts handles the rollouts subcommands
+func (c *cli) runRollouts(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: rollouts list|get|promote|rollback")
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("rollouts list", flag.ExitOnError)
+		limit := fs.Int("limit", 20, "Maximum rollouts")
+		fs.Parse(args[1:])
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.ListRollouts(ctx, &proto.ListRolloutsRequest{Limit: int32(*limit)})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		printRollouts(resp.Rollouts)
+		return nil
+
+	case "get", "promote", "rollback":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: rollouts %s <rollout-id>", args[0])
+		}
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid rollout ID %q", args[1])
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		var r *proto.Rollout
+		switch args[0] {
+		case "get":
+			r, err = c.client.GetRollout(ctx, &proto.GetRolloutRequest{RolloutId: int32(id)})
+		case "promote":
+			r, err = c.client.PromoteRollout(ctx, &proto.PromoteRolloutRequest{RolloutId: int32(id)})
+		default:
+			r, err = c.client.RollBackRollout(ctx, &proto.RollBackRolloutRequest{RolloutId: int32(id)})
+		}
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(r)
+		}
+		printRollout(r)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown rollouts command %q", args[0])
+	}
+}
+
+// runAccess handles the access subcommands
+func (c *cli) runAccess(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: access list|approve|deny")
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("access list", flag.ExitOnError)
+		state := fs.String("state", "", "Filter by state (pending, approved, denied, expired)")
+		agentID := fs.String("agent", "", "Filter by agent ID")
+		limit := fs.Int("limit", 50, "Maximum requests")
+		fs.Parse(args[1:])
+		ctx, cancel := c.context()
+		defer cancel()
+
+		resp, err := c.client.ListAccessRequests(ctx, &proto.ListAccessRequestsRequest{
+			State:   *state,
+			AgentId: *agentID,
+			Limit:   int32(*limit),
+		})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		printAccessRequests(resp.Requests)
+		return nil
+
+	case "approve", "deny":
+		fs := flag.NewFlagSet("access "+args[0], flag.ExitOnError)
+		var duration *time.Duration
+		var gateway *string
+		if args[0] == "approve" {
+			duration = fs.Duration("for", 0, "How long to grant the access, by default as long as requested")
+			gateway = fs.String("gateway", "", "Gateway to route the destination through, by default the ACL rule only")
+		}
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: access %s <request-id>", args[0])
+		}
+		id, err := strconv.Atoi(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("invalid request ID %q", fs.Arg(0))
+		}
+		ctx, cancel := c.context()
+		defer cancel()
+
+		var r *proto.AccessRequest
+		if args[0] == "approve" {
+			r, err = c.client.ApproveAccessRequest(ctx, &proto.ApproveAccessRequestRequest{
+				RequestId: int32(id),
+				Duration:  int32(duration.Round(time.Second) / time.Second),
+				GatewayId: *gateway,
+			})
+		} else {
+			r, err = c.client.DenyAccessRequest(ctx, &proto.DenyAccessRequestRequest{RequestId: int32(id)})
+		}
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(r)
+		}
+		printAccessRequests([]*proto.AccessRequest{r})
+		return nil
+
+	default:
+		return fmt.Errorf("unknown access command %q", args[0])
+	}
+}
+
+// runHandoff moves live sessions of the server to another server of the
+// cluster
+func (c *cli) runHandoff(args []string) error {
+	fs := flag.NewFlagSet("handoff", flag.ExitOnError)
+	agentID := fs.String("agent", "", "Only the session of this agent")
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		return fmt.Errorf("usage: handoff [-agent ID] [host:port]")
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+	resp, err := c.client.HandOffSessions(ctx, &proto.HandOffSessionsRequest{AgentId: *agentID, Server: fs.Arg(0)})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+	fmt.Printf("%d sessions moving to %s\n", resp.Sessions, resp.Server)
+	if resp.Skipped > 0 {
+		fmt.Printf("%d agents do not support handoff and stay until the server stops\n", resp.Skipped)
+	}
+	return nil
+}
+
+// runAudit lists the admin calls that changed something or were denied
+func (c *cli) runAudit(args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	username := fs.String("user", "", "Only calls by this username")
+	since := fs.Duration("since", 0, "Only calls of the last duration, e.g. 24h, default all")
+	limit := fs.Int("limit", 100, "Max entries")
+	fs.Parse(args)
+
+	req := &proto.ListAuditLogRequest{Username: *username, Limit: int32(*limit)}
+	if *since > 0 {
+		req.Since = timestamppb.New(time.Now().Add(-*since))
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+	resp, err := c.client.ListAuditLog(ctx, req)
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(resp)
+	}
+	printAuditLog(resp.Entries)
+	return nil
+}
+
+// changeFlags are the flags of the rule commands that simulate a change
+// or stage it as a rollout instead of applying it
+type changeFlags struct {
+	percent *int
+	groupID *int
+	dryRun  *bool
+}
+
+// addChangeFlags registers the simulation and canary flags on fs
+func addChangeFlags(fs *flag.FlagSet) *changeFlags {
+	return &changeFlags{
+		percent: fs.Int("canary", 0, "Stage the change to this percentage of agents"),
+		groupID: fs.Int("canary-group", 0, "Stage the change to the agents of this group"),
+		dryRun:  fs.Bool("dry-run", false, "Report the recently relayed flows the change would deny or reroute"),
+	}
+}
+
+// staged reports whether the change is simulated or staged
+func (f *changeFlags) staged() bool {
+	return *f.dryRun || *f.percent != 0 || *f.groupID != 0
+}
+
+// request completes the request staging a change
+func (f *changeFlags) request(kind, operation string, req *proto.StageRolloutRequest) *proto.StageRolloutRequest {
+	req.Kind = kind
+	req.Operation = operation
+	req.Percent = int32(*f.percent)
+	req.CanaryGroupId = int32(*f.groupID)
+	return req
+}
+
+// stageChange simulates a rule change and prints the affected flows, or
+// stages it and prints the rollout
+func (c *cli) stageChange(f *changeFlags, req *proto.StageRolloutRequest) error {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if *f.dryRun {
+		if req.Percent != 0 || req.CanaryGroupId != 0 {
+			return fmt.Errorf("-dry-run and -canary are mutually exclusive")
+		}
+		resp, err := c.client.SimulatePolicy(ctx, &proto.SimulatePolicyRequest{
+			Kind:        req.Kind,
+			Operation:   req.Operation,
+			RoutingRule: req.RoutingRule,
+			AgentId:     req.AgentId,
+			GroupId:     req.GroupId,
+			AclRule:     req.AclRule,
+		})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printJSON(resp)
+		}
+		printSimulation(resp)
+		return nil
+	}
+
+	r, err := c.client.StageRollout(ctx, req)
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printJSON(r)
+	}
+	printRollout(r)
+	return nil
+}
+
+// parsePortRange parses a port or an N-M port range
+func parsePortRange(value string) (uint32, uint32, error) {
+	fromStr, toStr, isRange := strings.Cut(value, "-")
+	from, err := strconv.ParseUint(fromStr, 10, 16)
+	if err != nil || from == 0 {
+		return 0, 0, fmt.Errorf("invalid port %q", fromStr)
+	}
+	if !isRange {
+		return uint32(from), uint32(from), nil
+	}
+	to, err := strconv.ParseUint(toStr, 10, 16)
+	if err != nil || to < from {
+		return 0, 0, fmt.Errorf("invalid port range %q", value)
+	}
+	return uint32(from), uint32(to), nil
+}
+
+// labelFlag collects repeated key=value flags
+type labelFlag map[string]string
+
+func (l labelFlag) String() string {
+	pairs := make([]string, 0, len(l))
+	for k, v := range l {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (l labelFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("label must be key=value")
+	}
+	l[k] = v
+	return nil
+}
+
+// printJSON writes a proto message as indented JSON
+func printJSON(m protobuf.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func printAgents(agents []*proto.AgentDetail) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tTYPE\tSTATUS\tIP\tCONNECTED\tLAST SEEN")
+	for _, a := range agents {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+			a.AgentId, a.Name, a.Type, a.Status, a.IpAddress, a.Connected, formatLastSeen(a))
+	}
+	w.Flush()
+}
+
+func printAgent(a *proto.AgentDetail) {
+	fmt.Printf("ID:         %s\n", a.AgentId)
+	fmt.Printf("Name:       %s\n", a.Name)
+	fmt.Printf("User:       %s\n", a.UserId)
+	fmt.Printf("Type:       %s\n", a.Type)
+	fmt.Printf("Status:     %s\n", a.Status)
+	if a.Group != "" {
+		fmt.Printf("Group:      %s\n", a.Group)
+	}
+	fmt.Printf("IP:         %s\n", a.IpAddress)
+	fmt.Printf("Public IP:  %s\n", a.PublicIp)
+	fmt.Printf("Connected:  %t\n", a.Connected)
+	fmt.Printf("Session:    %s\n", a.SessionId)
+	if a.Identity != nil {
+		fmt.Printf("Identity:   %s key %s\n", a.Identity.Provider, a.Identity.Fingerprint)
+	}
+	if a.NotReady {
+		fmt.Printf("Ready:      no, setting up and taking no traffic\n")
+	}
+	if a.ApiVersion != 0 {
+		fmt.Printf("API:        v%d %s\n", a.ApiVersion, strings.Join(a.Capabilities, ","))
+	}
+	if len(a.SiteSubnets) > 0 {
+		fmt.Printf("Sites:      %s\n", strings.Join(a.SiteSubnets, " "))
+	}
+	if a.ConfigGeneration != 0 {
+		drift := ""
+		if a.ConfigDrifted {
+			drift = " (drifted, fails to apply pushed config)"
+		}
+		fmt.Printf("Config:     generation %d%s\n", a.ConfigGeneration, drift)
+	}
+	if a.ClockSkewMs != 0 {
+		fmt.Printf("Clock:      %s the server clock\n", formatClockSkew(a.ClockSkewMs))
+	}
+	if fc := a.FlowControl; fc != nil {
+		slow := ""
+		if fc.SlowConsumer {
+			slow = " (slow consumer)"
+		}
+		fmt.Printf("Relay:      %s%s\n", formatFlowControl(fc), slow)
+	}
+	if r := a.Resources; r != nil {
+		limit := "unlimited"
+		if r.MemoryLimitBytes > 0 {
+			limit = fmt.Sprintf("of %d KB", r.MemoryLimitBytes>>10)
+		}
+		fmt.Printf("Resources:  %d goroutines, %d KB buffered %s, %d packets dropped over the limit\n",
+			r.Goroutines, r.BufferedBytes>>10, limit, r.MemoryDrops)
+	}
+	for _, failure := range a.PostureFailures {
+		fmt.Printf("Fails:      posture policy %s\n", failure)
+	}
+	if a.Pending {
+		fmt.Printf("Pending:    awaiting approval\n")
+	}
+	fmt.Printf("Last Seen:  %s\n", formatLastSeen(a))
+	if a.ArchivedAt != nil {
+		fmt.Printf("Archived:   %s\n", a.ArchivedAt.AsTime().Local().Format(time.RFC3339))
+	}
+	if a.Metadata != nil {
+		fmt.Printf("Platform:   %s/%s (version %s)\n", a.Metadata.Os, a.Metadata.Arch, a.Metadata.Version)
+		fmt.Printf("Hostname:   %s\n", a.Metadata.Hostname)
+		for k, v := range a.Metadata.Labels {
+			fmt.Printf("Label:      %s=%s\n", k, v)
+		}
+		if a.Metadata.DefaultGateway != "" {
+			fmt.Printf("Default:    via %s dev %s\n", a.Metadata.DefaultGateway, a.Metadata.DefaultInterface)
+		} else if a.Metadata.DefaultInterface != "" {
+			fmt.Printf("Default:    dev %s\n", a.Metadata.DefaultInterface)
+		}
+		if p := a.Metadata.Posture; p != nil {
+			fmt.Printf("Posture:    OS %s, disk encryption %s, firewall %s\n", p.OsVersion,
+				formatPostureState(p.DiskEncryption), formatPostureState(p.Firewall))
+		}
+		for _, iface := range a.Metadata.Interfaces {
+			state := "down"
+			if iface.Up {
+				state = "up"
+			}
+			fmt.Printf("Interface:  %s %s mtu %d %s %s\n", iface.Name, state, iface.Mtu,
+				iface.Mac, strings.Join(iface.Addresses, " "))
+		}
+	}
+	if a.Stats != nil {
+		fmt.Printf("Sent:       %d bytes, %d packets\n", a.Stats.BytesSent, a.Stats.PacketsSent)
+		fmt.Printf("Received:   %d bytes, %d packets\n", a.Stats.BytesReceived, a.Stats.PacketsReceived)
+		fmt.Printf("Errors:     %d, drops: %d\n", a.Stats.Errors, a.Stats.Drops)
+		fmt.Printf("Rekeys:     %d, QUIC key updates: %d\n", a.Stats.Rekeys, a.Stats.KeyUpdates)
+		for _, cs := range a.Stats.Classes {
+			fmt.Printf("Class:      %s queued %d, dropped %d\n", cs.Class, cs.Queued, cs.Dropped)
+		}
+		for _, lu := range a.Stats.LocalUsers {
+			name := strconv.Itoa(int(lu.Uid))
+			if lu.Name != "" {
+				name = lu.Name + " (" + name + ")"
+			}
+			fmt.Printf("Local user: %s sent %d bytes, received %d bytes\n", name, lu.BytesSent, lu.BytesReceived)
+		}
+	}
+	if a.Health != nil {
+		if a.Health.Degraded {
+			fmt.Printf("Health:     degraded\n")
+		} else {
+			fmt.Printf("Health:     ok, failed subsystems were restarted\n")
+		}
+		for _, sh := range a.Health.Subsystems {
+			fmt.Printf("Subsystem:  %s %s, %d restarts, last error: %s\n", sh.Name, sh.State, sh.Restarts, sh.LastError)
+		}
+	} else if a.Connected {
+		fmt.Printf("Health:     ok\n")
+	}
+}
+
+func printTrafficClasses(classes []*proto.TrafficClassStats) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CLASS\tQUEUED\tDROPPED")
+	for _, cs := range classes {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", cs.Class, cs.Queued, cs.Dropped)
+	}
+	w.Flush()
+}
+
+func printSessionHistory(sessions []*proto.SessionRecord) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SESSION\tCONNECTED\tDISCONNECTED\tSENT\tRECEIVED\tREASON")
+	for _, r := range sessions {
+		reason := r.Reason
+		if r.ReasonCode != proto.DisconnectReason_DISCONNECT_REASON_UNSPECIFIED {
+			code := strings.ToLower(strings.TrimPrefix(r.ReasonCode.String(), "DISCONNECT_"))
+			if reason == "" {
+				reason = code
+			} else {
+				reason = code + ": " + reason
+			}
+		}
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n", r.SessionId,
+			r.ConnectedAt.AsTime().Local().Format(time.RFC3339),
+			r.DisconnectedAt.AsTime().Local().Format(time.RFC3339),
+			r.BytesSent, r.BytesReceived, reason)
+	}
+	w.Flush()
+}
+
+func printRollups(rollups []*proto.TrafficRollup, daily bool) {
+	layout := "2006-01-02 15:04"
+	if daily {
+		layout = "2006-01-02"
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PERIOD	AGENT	USER	SESSIONS	CONNECTED	SENT	RECEIVED")
+	for _, r := range rollups {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d\t%d\n", r.PeriodStart.AsTime().Local().Format(layout),
+			r.AgentId, r.UserId, r.Sessions, time.Duration(r.ConnectedSeconds)*time.Second,
+			r.BytesSent, r.BytesReceived)
+	}
+	w.Flush()
+}
+
+func printStats(agents []*proto.AgentDetail, previous map[string]*proto.AgentStats, interval time.Duration) {
+	fmt.Printf("--- %s ---\n", time.Now().Format(time.RFC3339))
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSENT\tRECEIVED\tTX/s\tRX/s\tDROPS")
+	for _, a := range agents {
+		stats := a.Stats
+		if stats == nil {
+			stats = &proto.AgentStats{}
+		}
+		var txRate, rxRate uint64
+		if prev, ok := previous[a.AgentId]; ok && prev != nil {
+			seconds := uint64(interval.Seconds())
+			if seconds == 0 {
+				seconds = 1
+			}
+			if stats.BytesSent >= prev.BytesSent {
+				txRate = (stats.BytesSent - prev.BytesSent) / seconds
+			}
+			if stats.BytesReceived >= prev.BytesReceived {
+				rxRate = (stats.BytesReceived - prev.BytesReceived) / seconds
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\n",
+			a.AgentId, a.Name, stats.BytesSent, stats.BytesReceived, txRate, rxRate, stats.Drops)
+	}
+	w.Flush()
+}
+
+func printRules(rules []*proto.RoutingRule) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPRIORITY\tACTION\tDESTINATION\tGATEWAY\tENABLED\tWINDOW\tPOSTURE")
+	for _, r := range rules {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%t\t%s\t%s\n",
+			r.RuleId, r.Priority, r.Action, r.Destination, r.GatewayId, r.Enabled, formatWindow(r.Window),
+			orAny(r.Posture))
+	}
+	w.Flush()
+}
+
+func printGroups(groups []*proto.AgentGroup) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tAGENTS\tDNS\tSEARCH\tBANDWIDTH\tDESCRIPTION")
+	for _, g := range groups {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			g.GroupId, g.Name, g.AgentCount, strings.Join(g.DnsServers, ","),
+			strings.Join(g.DnsSearch, ","), formatLimit(uint64(g.BandwidthLimit), "unlimited"), g.Description)
+	}
+	w.Flush()
+}
+
+func printACLRules(rules []*proto.ACLRule) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPRIORITY\tACTION\tSOURCE\tDESTINATION\tPROTOCOL\tPORTS\tUID\tENABLED\tWINDOW\tPOSTURE")
+	for _, r := range rules {
+		ports := "any"
+		if r.PortFrom != 0 {
+			ports = strconv.Itoa(int(r.PortFrom))
+			if r.PortTo != r.PortFrom {
+				ports += "-" + strconv.Itoa(int(r.PortTo))
+			}
+		}
+		uid := "any"
+		if r.SourceUid != nil {
+			uid = strconv.Itoa(int(*r.SourceUid))
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%t\t%s\t%s\n",
+			r.RuleId, r.Priority, r.Action, orAny(r.Source), orAny(r.Destination), r.Protocol, ports,
+			uid, r.Enabled, formatWindow(r.Window), orAny(r.Posture))
+	}
+	w.Flush()
+}
+
+func printAccessRequests(requests []*proto.AccessRequest) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tAGENT\tDESTINATION\tPROTOCOL\tPORTS\tDURATION\tREQUESTED\tBY\tEXPIRES\tREASON")
+	for _, r := range requests {
+		ports := "any"
+		if r.PortFrom != 0 {
+			ports = strconv.Itoa(int(r.PortFrom))
+			if r.PortTo != r.PortFrom {
+				ports += "-" + strconv.Itoa(int(r.PortTo))
+			}
+		}
+		expires := "-"
+		if r.ExpiresAt != nil {
+			expires = r.ExpiresAt.AsTime().Local().Format(time.RFC3339)
+		}
+		by, reason := r.DecidedBy, r.Reason
+		if by == "" {
+			by = "-"
+		}
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.RequestId, r.State, r.AgentId, r.Destination, r.Protocol, ports, time.Duration(r.Duration)*time.Second,
+			r.RequestedAt.AsTime().Local().Format(time.RFC3339), by, expires, reason)
+	}
+	w.Flush()
+}
+
+func printAuditLog(entries []*proto.AuditEntry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tUSER\tROLE\tSOURCE\tRESULT\tACTION")
+	for _, e := range entries {
+		result := "allowed"
+		if !e.Allowed {
+			result = "denied"
+		}
+		source := e.SourceIp
+		if source == "" {
+			source = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Time.AsTime().Local().Format(time.RFC3339), e.Username, e.Role, source, result, e.Action)
+	}
+	w.Flush()
+}
+
+func printRollouts(rollouts []*proto.Rollout) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tCHANGE\tRULE\tCANARY\tBY\tCREATED")
+	for _, r := range rollouts {
+		fmt.Fprintf(w, "%d\t%s\t%s %s\t%s\t%s\t%s\t%s\n",
+			r.RolloutId, r.State, r.Operation, r.Kind, formatRolloutRule(r), formatCanary(r), r.CreatedBy,
+			r.CreatedAt.AsTime().Local().Format(time.RFC3339))
+	}
+	w.Flush()
+}
+
+func printRollout(r *proto.Rollout) {
+	fmt.Printf("Rollout %d: %s %s rule %s\n", r.RolloutId, r.Operation, r.Kind, formatRolloutRule(r))
+	fmt.Printf("State:   %s, staged by %s at %s\n", r.State, r.CreatedBy, r.CreatedAt.AsTime().Local().Format(time.RFC3339))
+	fmt.Printf("Canary:  %s\n", formatCanary(r))
+	if r.RoutingRule != nil {
+		printRules([]*proto.RoutingRule{r.RoutingRule.Rule})
+	} else if r.AclRule != nil {
+		printACLRules([]*proto.ACLRule{r.AclRule})
+	}
+	if r.Canary == nil {
+		return
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "COHORT\tSESSIONS\tPACKETS\tBYTES\tERRORS\tDENIED")
+	for _, cohort := range []struct {
+		name string
+		c    *proto.RolloutCohort
+	}{{"canary", r.Canary}, {"baseline", r.Baseline}} {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n",
+			cohort.name, cohort.c.Sessions, cohort.c.Packets, cohort.c.Bytes, cohort.c.Errors, cohort.c.Denied)
+	}
+	w.Flush()
+}
+
+func printSimulation(resp *proto.SimulatePolicyResponse) {
+	if resp.SampleRate == 0 {
+		fmt.Println("Relay tracing is off, enable it with traces sample <N> to record flows")
+	}
+	fmt.Printf("%d of %d recently traced flows affected\n", len(resp.Flows), resp.FlowsEvaluated)
+	if len(resp.Flows) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CHANGE\tAGENT\tSOURCE\tDESTINATION\tPROTO\tPORT\tPACKETS\tBEFORE\tAFTER")
+	for _, f := range resp.Flows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\t%s\n",
+			f.Change, f.SourceAgentId, f.SourceIp, f.DestinationIp, f.Protocol, f.DestinationPort, f.Packets,
+			f.Before, f.After)
+	}
+	w.Flush()
+}
+
+// formatRolloutRule describes the rule of a rollout and whom it applies to
+func formatRolloutRule(r *proto.Rollout) string {
+	ruleID := func(id int32) string {
+		if r.Operation == "add" {
+			return "new"
+		}
+		return strconv.Itoa(int(id))
+	}
+	switch {
+	case r.AclRule != nil:
+		return fmt.Sprintf("%s of user %s", ruleID(r.AclRule.RuleId), r.AclRule.UserId)
+	case r.RoutingRule == nil:
+		return ""
+	case r.RoutingRule.GroupId != 0:
+		return fmt.Sprintf("%s of group %d", ruleID(r.RoutingRule.Rule.RuleId), r.RoutingRule.GroupId)
+	default:
+		return fmt.Sprintf("%s of agent %s", ruleID(r.RoutingRule.Rule.RuleId), r.RoutingRule.AgentId)
+	}
+}
+
+// formatCanary describes the agents a rollout is staged to
+func formatCanary(r *proto.Rollout) string {
+	if r.CanaryGroupId != 0 {
+		return fmt.Sprintf("group %d", r.CanaryGroupId)
+	}
+	return fmt.Sprintf("%d%% of agents", r.Percent)
+}
+
+// windowFlags are the access window flags of the rule commands
+type windowFlags struct {
+	from     *string
+	until    *string
+	duration *time.Duration
+	schedule *string
+}
+
+// addWindowFlags registers the access window flags on fs
+func addWindowFlags(fs *flag.FlagSet) *windowFlags {
+	return &windowFlags{
+		from:     fs.String("from", "", "Start of validity (RFC 3339)"),
+		until:    fs.String("until", "", "End of validity (RFC 3339)"),
+		duration: fs.Duration("for", 0, "Temporary grant: validity from -from or now, e.g. 4h"),
+		schedule: fs.String("schedule", "", `Weekly schedule, e.g. "mon-fri 09:00-18:00 [Europe/Berlin]"`),
+	}
+}
+
+// window builds the access window, nil if the rule always applies
+func (f *windowFlags) window() (*proto.AccessWindow, error) {
+	if *f.until != "" && *f.duration != 0 {
+		return nil, fmt.Errorf("-until and -for are mutually exclusive")
+	}
+
+	var from, until time.Time
+	if *f.from != "" {
+		t, err := time.Parse(time.RFC3339, *f.from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -from: %w", err)
+		}
+		from = t
+	}
+	if *f.until != "" {
+		t, err := time.Parse(time.RFC3339, *f.until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -until: %w", err)
+		}
+		until = t
+	}
+	if *f.duration != 0 {
+		if *f.duration < 0 {
+			return nil, fmt.Errorf("-for must be positive")
+		}
+		if from.IsZero() {
+			from = time.Now()
+		}
+		until = from.Add(*f.duration)
+	}
+
+	if from.IsZero() && until.IsZero() && *f.schedule == "" {
+		return nil, nil
+	}
+	window := &proto.AccessWindow{Schedule: *f.schedule}
+	if !from.IsZero() {
+		window.ValidFrom = timestamppb.New(from)
+	}
+	if !until.IsZero() {
+		window.ValidUntil = timestamppb.New(until)
+	}
+	return window, nil
+}
+
+// formatWindow describes when a rule applies
+func formatWindow(w *proto.AccessWindow) string {
+	if w == nil {
+		return "always"
+	}
+	var parts []string
+	if w.ValidFrom != nil {
+		parts = append(parts, "from "+w.ValidFrom.AsTime().Local().Format(time.RFC3339))
+	}
+	if w.ValidUntil != nil {
+		parts = append(parts, "until "+w.ValidUntil.AsTime().Local().Format(time.RFC3339))
+	}
+	if w.Schedule != "" {
+		parts = append(parts, w.Schedule)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatPostureState describes the outcome of a posture check
+func formatPostureState(state proto.PostureState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "POSTURE_STATE_"))
+}
+
+// orAny shows an empty match field as any
+func orAny(value string) string {
+	if value == "" {
+		return "any"
+	}
+	return value
+}
+
+func printUsers(users []*proto.UserDetail) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tROLE\tSTATUS\tAGENTS\tMAX AGENTS\tMAX KB/s\tTRANSFER CAP\tUSED THIS MONTH")
+	for _, u := range users {
+		var used uint64
+		if len(u.Usage) > 0 && u.Usage[0].Month == time.Now().UTC().Format("2006-01") {
+			used = u.Usage[0].BytesSent + u.Usage[0].BytesReceived
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%d\n",
+			u.UserId, u.Username, u.Role, u.Status, u.AgentCount,
+			formatLimit(uint64(u.MaxAgents), "default"), formatLimit(uint64(u.MaxBandwidth), "unlimited"),
+			formatLimit(u.TransferCap, "unlimited"), used)
+	}
+	w.Flush()
+}
+
+func printUser(u *proto.UserDetail) {
+	fmt.Printf("ID:           %s\n", u.UserId)
+	fmt.Printf("Username:     %s\n", u.Username)
+	fmt.Printf("Email:        %s\n", u.Email)
+	fmt.Printf("Role:         %s\n", u.Role)
+	fmt.Printf("Status:       %s\n", u.Status)
+	fmt.Printf("Agents:       %d (max %s)\n", u.AgentCount, formatLimit(uint64(u.MaxAgents), "server default"))
+	fmt.Printf("Bandwidth:    %s KB/s\n", formatLimit(uint64(u.MaxBandwidth), "unlimited"))
+	fmt.Printf("Transfer Cap: %s bytes/month\n", formatLimit(u.TransferCap, "unlimited"))
+	if len(u.SitePrefixes) > 0 {
+		fmt.Printf("Site Prefixes: %s\n", strings.Join(u.SitePrefixes, ", "))
+	}
+	if len(u.Usage) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "MONTH\tSENT\tRECEIVED\tTOTAL")
+		for _, m := range u.Usage {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", m.Month, m.BytesSent, m.BytesReceived, m.BytesSent+m.BytesReceived)
+		}
+		w.Flush()
+	}
+}
+
+// formatLimit prints a quota, using unset for zero
+func formatLimit(limit uint64, unset string) string {
+	if limit == 0 {
+		return unset
+	}
+	return strconv.FormatUint(limit, 10)
+}
+
+func printTraces(traces []*proto.RelayTrace) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tSOURCE\tSRC IP\tDST IP\tPROTO\tLEN\tTTL\tDECISION\tDESTINATION\tDETAIL")
+	for _, t := range traces {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\t%s\t%s\n",
+			t.Time.AsTime().Local().Format("15:04:05.000"), t.SourceAgentId, t.SourceIp, t.DestinationIp,
+			t.Protocol, t.Length, t.Ttl, t.Decision, t.DestinationAgentId, t.Detail)
+	}
+	w.Flush()
+}
+
+func formatLastSeen(a *proto.AgentDetail) string {
+	if a.LastSeen == nil {
+		return "never"
+	}
+	return a.LastSeen.AsTime().Local().Format(time.RFC3339)
+}
+
+// formatClockSkew describes how far an agent clock is off the server's
+func formatClockSkew(skewMs int64) string {
+	skew := time.Duration(skewMs) * time.Millisecond
+	if skew < 0 {
+		return fmt.Sprintf("%s behind", (-skew).String())
+	}
+	return fmt.Sprintf("%s ahead of", skew.String())
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
+
+// adminKeyEnv is the environment variable holding the admin API key
+const adminKeyEnv = "EASYANYLINK_ADMIN_KEY"
+
+// cli holds the shared connection state of all commands
+type cli struct {
+	client     proto.AdminServiceClient
+	conn       *grpc.ClientConn
+	apiKey     string
+	jsonOutput bool
+	timeout    time.Duration
+}
+
+func main() {
+	// Parse global flags
+	serverAddr := flag.String("server", "localhost:8228", "Server address (host:port)")
+	apiKey := flag.String("key", os.Getenv(adminKeyEnv), "Admin API key (default $"+adminKeyEnv+")")
+	insecure := flag.Bool("insecure", false, "Skip TLS certificate verification (debugging only)")
+	caFile := flag.String("ca", "", "Trust bundle of a private CA that issued the server certificate")
+	cryptoPolicy := flag.String("crypto-policy", "", "Crypto policy of the connection (default, fips)")
+	jsonOutput := flag.Bool("json", false, "Print results as JSON")
+	timeout := flag.Duration("timeout", 10*time.Second, "Timeout of each request")
+	showVersion := flag.Bool("version", false, "Show version information")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Printf("EasyAnyLink Admin\n")
+		fmt.Printf("Version: %s\n", Version)
+		fmt.Printf("Git Commit: %s\n", GitCommit)
+		fmt.Printf("Build Time: %s\n", BuildTime)
+		fmt.Printf("Crypto Policy: %s\n", crypto.PolicySummary())
+		os.Exit(0)
+	}
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := crypto.SetPolicy(*cryptoPolicy); err != nil {
+		fatalf("%v", err)
+	}
+
+	if *apiKey == "" {
+		fatalf("admin API key is required (-key or $%s)", adminKeyEnv)
+	}
+
+	c := &cli{
+		apiKey:     *apiKey,
+		jsonOutput: *jsonOutput,
+		timeout:    *timeout,
+	}
+
+	if err := c.connect(*serverAddr, *caFile, *insecure); err != nil {
+		fatalf("%v", err)
+	}
+	defer c.conn.Close()
+
+	if err := c.run(flag.Args()); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+// connect establishes the gRPC connection over QUIC
+func (c *cli) connect(serverAddr, caFile string, insecure bool) error {
+	host, _, err := net.SplitHostPort(serverAddr)
+	if err != nil {
+		return fmt.Errorf("invalid server address: %w", err)
+	}
+
+	tlsConfig, err := crypto.LoadClientTLSConfigWithCA(host, caFile, insecure)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS configuration: %w", err)
+	}
+
+	conn, err := grpc.Dial(
+		serverAddr,
+		crypto.GRPCDialOption(crypto.NewQUICDialer(tlsConfig)),
+		grpc.WithInsecure(), // TLS is handled by QUIC layer
+	)
+	if err != nil {
+		return fmt.Errorf("failed to dial server: %w", err)
+	}
+
+	c.conn = conn
+	c.client = proto.NewAdminServiceClient(conn)
+	return nil
+}
+
+// context returns a request context carrying the admin API key
+func (c *cli) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", c.apiKey)
+	return ctx, cancel
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: easyanylink-admin [flags] <command> [arguments]
+
+Commands:
+  agents list [-type T] [-status S] [-user ID] [-label k=v] [-page-size N] [-archived] [-pending]
+  agents get <agent-id>
+  agents archive <agent-id>                Disconnect and soft-delete an agent
+  agents restore <agent-id>
+  agents approve <agent-id>                Admit an agent awaiting approval
+  agents reject <agent-id>                 Delete an agent awaiting approval
+  agents reset-identity <agent-id>         Forget the TPM or Secure Enclave key an agent
+                                           is bound to, its next registration binds a new one
+  agents history [-limit N] <agent-id>     Ended sessions of an agent
+  agents set-group <agent-id> <group-id>   Move an agent into a group, 0 to leave it
+  stats [-interval D] [-agent ID]          Tail live session statistics
+  handoff [-agent ID] [host:port]          Move live sessions to another server of the
+                                           cluster, which continues them with their overlay
+                                           IPs; default the first network.alternate_servers
+  users list
+  users get [-months N] <user-id>
+  users create -username NAME [-email E] [-role R] [-password P]
+               [-max-agents N] [-max-bandwidth KB/s] [-transfer-cap BYTES]
+               [-site-prefixes CIDR,...]
+  users update [-email E] [-role R] [-status S] [-max-agents N]
+               [-max-bandwidth KB/s] [-transfer-cap BYTES]
+               [-site-prefixes CIDR,...] <user-id>
+  users delete <user-id>
+  users rotate-key <user-id>
+  Roles: user; owner, admin, support and auditor may use this tool: auditors
+         read, support also handles agents, admins change everything but
+         the accounts of these roles, which owners manage
+  routes list <agent-id> | -group ID
+  routes add -agent ID|-group ID -action A -dest CIDR [-gateway ID] [-priority N] [-disabled]
+             [-posture POLICY]
+  routes update -id N -action A -dest CIDR [-gateway ID] [-priority N] [-disabled]
+                [-posture POLICY]
+  routes delete [-dry-run | -canary N | -canary-group ID] <rule-id>
+  groups list                              Agent groups and their config templates
+  groups create -name NAME [-description D] [-dns IPS] [-search DOMAINS]
+                [-bandwidth KB/s]
+  groups update -id N -name NAME [-description D] [-dns IPS] [-search DOMAINS]
+                [-bandwidth KB/s]
+  groups delete <group-id>                 Delete a group and its routing rules
+  acl list <user-id>                       Packet filter rules of a user's agents
+  acl add -user ID -action allow|deny [-src CIDR] [-dest CIDR] [-proto P]
+          [-ports N[-M]] [-uid UID] [-priority N] [-disabled] [-posture POLICY]
+  acl update -id N -action allow|deny [-src CIDR] [-dest CIDR] [-proto P]
+             [-ports N[-M]] [-uid UID] [-priority N] [-disabled] [-posture POLICY]
+  acl delete [-dry-run | -canary N | -canary-group ID] <rule-id>
+  Rule windows (routes and acl add/update): [-from TIME] [-until TIME | -for DURATION]
+          [-schedule "DAYS HH:MM-HH:MM [ZONE]"], e.g. -schedule "mon-fri 09:00-18:00" or -for 4h
+  Canaries (routes and acl add/update/delete): -canary PERCENT | -canary-group ID
+          stages the change to some agents as a rollout instead of applying it,
+          -dry-run reports the recently traced flows it would deny or reroute
+  rollouts list [-limit N]                 Staged, promoted and rolled back rule changes
+  rollouts get <rollout-id>                A rollout with the traffic of its canary and the
+                                           other agents while it is staged
+  rollouts promote <rollout-id>            Apply a staged change to all agents
+  rollouts rollback <rollout-id>           Withdraw a staged change from its canary
+  access list [-state S] [-agent ID] [-limit N]
+                                           Temporary access requested by agents
+  access approve [-for DURATION] [-gateway ID] <request-id>
+                                           Grant a request until it expires, with a route
+                                           through the gateway if given
+  access deny <request-id>
+  audit [-user NAME] [-since D] [-limit N] Admin calls that changed something or were
+                                           denied, and who made them
+  traces list [-agent ID] [-limit N]      Recently sampled relay decisions
+  traces sample <N>                        Trace 1 in N relayed packets, 0 disables
+  handshakes                               QUIC handshake address validation counters
+  crypto-policy                            Crypto policy enforced by the server's TLS
+  relay-queues                             Relay queue counters per traffic class and multicast policy
+  keepalive [-interval D] [-timeout D]     Show or change the agent heartbeat settings
+                                           until the server restarts
+  faults [-relay-drop F] [-heartbeat-delay D] [-db-failure F] [-off]
+                                           Show or replace the faults injected by a
+                                           server built with the chaos tag
+  usage export [-month YYYY-MM] [-format csv|json] [-o FILE]
+                                           Monthly per-user transfer for billing
+  usage report [-daily] [-agent ID] [-user ID] [-since D] [-limit N]
+                                           Hourly or daily rollups of ended sessions
+  inventory [-format hosts|ansible] [-domain D] [-o FILE]
+                                           Agent overlay addresses as a hosts file
+                                           fragment or Ansible dynamic inventory
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// runAccess implements "agent access [list]" and "agent access request".
+// It lists the temporary access requests of the running agent or asks the
+// server for access an admin has to approve.
+func runAccess(args []string) int {
+	if len(args) > 0 && args[0] == "request" {
+		return runAccessRequest(args[1:])
+	}
+	if len(args) > 0 && args[0] == "list" {
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("access", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the requests as JSON")
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent access [list] [flags]\n"+
+			"       easyanylink-agent access request [flags] <destination>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	requests, err := agent.NewControlClient(*socket).AccessRequests(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(requests)
+		return 0
+	}
+	printAccessRequests(requests)
+	return 0
+}
+
+// runAccessRequest implements "agent access request"
+func runAccessRequest(args []string) int {
+	fs := flag.NewFlagSet("access request", flag.ExitOnError)
+	protocol := fs.String("proto", "", "Protocol: any, tcp, udp or icmp (default any)")
+	ports := fs.String("ports", "", "Destination port or range, e.g. 22 or 8000-8080, with -proto tcp or udp")
+	duration := fs.Duration("for", time.Hour, "How long the access is needed")
+	reason := fs.String("reason", "", "Justification shown to the approving admin")
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent access request [flags] <destination CIDR or IP>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	spec := agent.AccessSpec{Destination: fs.Arg(0), Protocol: *protocol, Duration: *duration, Reason: *reason}
+	if *ports != "" {
+		from, to, isRange := strings.Cut(*ports, "-")
+		var err error
+		spec.PortFrom, err = strconv.Atoi(from)
+		if err == nil && isRange {
+			spec.PortTo, err = strconv.Atoi(to)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid ports %q\n", *ports)
+			return 2
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	request, err := agent.NewControlClient(*socket).RequestAccess(ctx, spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Access request %d submitted, waiting for an admin to approve it\n", request.ID)
+	fmt.Println("Check its state with: easyanylink-agent access list")
+	return 0
+}
+
+func printAccessRequests(requests []agent.AccessRequestInfo) {
+	if len(requests) == 0 {
+		fmt.Println("No access requests")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDESTINATION\tPROTOCOL\tPORTS\tDURATION\tSTATE\tEXPIRES")
+	for _, r := range requests {
+		ports := "any"
+		if r.PortFrom != 0 {
+			ports = strconv.Itoa(r.PortFrom)
+			if r.PortTo != r.PortFrom {
+				ports += "-" + strconv.Itoa(r.PortTo)
+			}
+		}
+		expires := "-"
+		if r.ExpiresAt != nil {
+			expires = r.ExpiresAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", r.ID, r.Destination, r.Protocol, ports,
+			time.Duration(r.Duration)*time.Second, r.State, expires)
+	}
+	w.Flush()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+)
+
+// checkLabels are the labels of check results, all four characters wide
+var checkLabels = map[agent.CheckStatus]string{
+	agent.CheckOK:   " OK ",
+	agent.CheckWarn: "WARN",
+	agent.CheckFail: "FAIL",
+	agent.CheckSkip: "SKIP",
+}
+
+// runCheck implements "agent check". It verifies that the agent can run
+// with its configuration and prints what to do about each failure,
+// exiting with 1 if a check failed.
+func runCheck(configFile, profile string, args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the results as JSON")
+	socket := fs.String("socket", agent.DefaultControlSocket, "Control socket of a running agent")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent [-config file] [-profile name] check [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Checks reaching the servers, certificate trust, TUN and route\n")
+		fmt.Fprintf(os.Stderr, "permissions and the overlay IP. Run it as the agent would run.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := config.LoadAgentConfig(configFile)
+	if err == nil && profile != "" {
+		err = cfg.ApplyProfile(profile)
+	}
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err == nil {
+		err = crypto.SetPolicy(cfg.CryptoPolicy)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: configuration %s: %v\n", configFile, err)
+		return 1
+	}
+
+	// The running agent's TUN has the overlay IP, that is no conflict
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	running, _ := agent.NewControlClient(*socket).Status(ctx)
+	cancel()
+
+	results := agent.SelfCheck(context.Background(), cfg, running)
+
+	failed := false
+	for _, r := range results {
+		failed = failed || r.Status == agent.CheckFail
+	}
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(results)
+	} else {
+		fmt.Printf("[ OK ] configuration %s, %s mode\n", configFile, cfg.Mode)
+		for _, r := range results {
+			fmt.Printf("[%s] %s: %s\n", checkLabels[r.Status], r.Name, r.Detail)
+			if r.Hint != "" && r.Status != agent.CheckOK {
+				fmt.Printf("       -> %s\n", r.Hint)
+			}
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// runConnect implements "agent connect" and "agent disconnect". Disconnect
+// ends the session of the running agent, which keeps running with its
+// routes withdrawn until connect.
+func runConnect(command string, args []string) int {
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent %s [flags]\n\nFlags:\n", command)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	client := agent.NewControlClient(*socket)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var err error
+	if command == "connect" {
+		_, err = client.Connect(ctx)
+	} else {
+		_, err = client.Disconnect(ctx)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if command == "connect" {
+		fmt.Println("Connecting, see \"easyanylink-agent status\"")
+	} else {
+		fmt.Println("Disconnected")
+	}
+	return 0
+}
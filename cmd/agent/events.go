@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// runEvents implements "agent events". It prints the events of the
+// running agent as they happen, until interrupted or the agent stops.
+func runEvents(args []string) int {
+	fs := flag.NewFlagSet("events", flag.ExitOnError)
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	asJSON := fs.Bool("json", false, "Print events as newline-delimited JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent events [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	encoder := json.NewEncoder(os.Stdout)
+	err := agent.NewControlClient(*socket).Events(ctx, func(event agent.Event) bool {
+		if *asJSON {
+			encoder.Encode(event)
+			return true
+		}
+
+		line := fmt.Sprintf("%s %-16s", event.Time.Format(time.RFC3339), event.Type)
+		for _, field := range []string{event.Message, event.SessionID, event.AssignedIP, event.Route, event.Error} {
+			if field != "" {
+				line += " " + field
+			}
+		}
+		fmt.Println(line)
+		return true
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
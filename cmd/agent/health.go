@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// runHealth implements "agent health". It lists subsystems of the running
+// agent that failed and exits with 1 while the agent is degraded, so it
+// can serve as a monitoring check.
+func runHealth(args []string) int {
+	fs := flag.NewFlagSet("health", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the health as JSON")
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent health [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := agent.NewControlClient(*socket).Health(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(status)
+	} else {
+		printHealth(status)
+	}
+
+	if status.Degraded {
+		return 1
+	}
+	return 0
+}
+
+func printHealth(status *agent.HealthStatus) {
+	switch {
+	case status.Degraded:
+		fmt.Println("Agent is degraded")
+	case len(status.Subsystems) > 0:
+		fmt.Println("Agent is healthy, failed subsystems were restarted")
+	default:
+		fmt.Println("Agent is healthy")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SUBSYSTEM\tSTATE\tRESTARTS\tLAST FAILURE\tERROR")
+	for _, ss := range status.Subsystems {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", ss.Name, ss.State, ss.Restarts,
+			ss.LastFailure.Local().Format(time.RFC3339), ss.LastError)
+	}
+	w.Flush()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/taills/EasyAnyLink/agent"
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// runIdentity implements "agent identity". It prints the fingerprint of
+// the hardware identity key, to compare with the one "easyanylink-admin
+// agents get" shows for the agent. The first run creates the key.
+func runIdentity(configFile string, args []string) int {
+	fs := flag.NewFlagSet("identity", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent [-config file] identity\n\n")
+		fmt.Fprintf(os.Stderr, "Prints the fingerprint of the TPM or Secure Enclave key the agent\n")
+		fmt.Fprintf(os.Stderr, "signs registrations with when hardware_identity is set.\n")
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := config.LoadAgentConfig(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: configuration %s: %v\n", configFile, err)
+		return 1
+	}
+	provider, fingerprint, err := agent.IdentityFingerprint(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Provider:    %s\n", provider)
+	fmt.Printf("Fingerprint: %s\n", fingerprint)
+	return 0
+}
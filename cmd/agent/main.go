@@ -6,10 +6,13 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/taills/EasyAnyLink/agent"
 	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"github.com/taills/EasyAnyLink/common/logging"
 )
 
 var (
@@ -21,6 +24,7 @@ var (
 func main() {
 	// Parse command-line flags
 	configFile := flag.String("config", "config/agent-client.example.json", "Path to configuration file")
+	profile := flag.String("profile", "", "Server profile to use instead of the configured one")
 	showVersion := flag.Bool("version", false, "Show version information")
 	flag.Parse()
 
@@ -29,12 +33,42 @@ func main() {
 		fmt.Printf("Version: %s\n", Version)
 		fmt.Printf("Git Commit: %s\n", GitCommit)
 		fmt.Printf("Build Time: %s\n", BuildTime)
+		fmt.Printf("Crypto Policy: %s\n", crypto.PolicySummary())
 		os.Exit(0)
 	}
 
-	// Check if running as root
-	if os.Geteuid() != 0 {
-		log.Fatal("Agent must run as root (or with sudo) to create TUN interface and modify routes")
+	// Subcommands need no TUN privileges
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "status":
+			os.Exit(runStatus(flag.Args()[1:]))
+		case "health":
+			os.Exit(runHealth(flag.Args()[1:]))
+		case "stats":
+			os.Exit(runStats(flag.Args()[1:]))
+		case "events":
+			os.Exit(runEvents(flag.Args()[1:]))
+		case "ping":
+			os.Exit(runPing(flag.Args()[1:]))
+		case "nc":
+			os.Exit(runNC(flag.Args()[1:]))
+		case "profile":
+			os.Exit(runProfile(flag.Args()[1:]))
+		case "connect", "disconnect":
+			os.Exit(runConnect(flag.Arg(0), flag.Args()[1:]))
+		case "trust":
+			os.Exit(runTrust(flag.Args()[1:]))
+		case "support-bundle":
+			os.Exit(runSupportBundle(*configFile, flag.Args()[1:]))
+		case "check":
+			os.Exit(runCheck(*configFile, *profile, flag.Args()[1:]))
+		case "identity":
+			os.Exit(runIdentity(*configFile, flag.Args()[1:]))
+		case "access":
+			os.Exit(runAccess(flag.Args()[1:]))
+		default:
+			log.Fatalf("Unknown command: %s", flag.Arg(0))
+		}
 	}
 
 	// Load configuration
@@ -43,13 +77,58 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	if *profile != "" {
+		if err := cfg.ApplyProfile(*profile); err != nil {
+			log.Fatalf("Failed to select profile: %v", err)
+		}
+	}
+
 	if err := cfg.Validate(); err != nil {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
+	if err := crypto.SetPolicy(cfg.CryptoPolicy); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	// Logs go to log.output from here on, with secrets and, per
+	// log.redact, addresses masked
+	logConfig := cfg.Log
+	if os.Getenv(agent.PrivsepFDEnv) != "" && logConfig.Output == "file" {
+		// The privilege helper writes the log file for the agent
+		logConfig.Output = "stderr"
+	}
+	if err := logging.Setup(logConfig, cfg); err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+
+	// An agent started by the privilege helper sends it the privileged work
+	if fd := os.Getenv(agent.PrivsepFDEnv); fd != "" {
+		if err := agent.ConnectPrivilegeHelper(fd); err != nil {
+			log.Fatalf("Failed to start unprivileged: %v", err)
+		}
+	} else {
+		// Check if running as root
+		if os.Geteuid() != 0 {
+			log.Fatal("Agent must run as root (or with sudo) to create TUN interface and modify routes")
+		}
+		// With a user configured, root only keeps a helper creating the
+		// TUN and changing routes, and runs the agent as that user; with
+		// drop_privileges the agent switches to the user itself
+		if cfg.User != "" && !cfg.DropPrivileges {
+			os.Exit(agent.RunPrivilegeHelper(cfg))
+		}
+	}
 
 	log.Printf("Starting EasyAnyLink Agent version %s", Version)
 	log.Printf("Mode: %s", cfg.Mode)
-	log.Printf("Server: %s", cfg.Server)
+	log.Printf("Profile: %s", cfg.Profile)
+	log.Printf("Servers: %s", strings.Join(cfg.ServerList(), ", "))
+
+	// Transport debugging is off unless configured or set in the environment
+	closeDebug, err := crypto.EnableTransportDebug(cfg.Debug.KeyLogFile, cfg.Debug.QlogDir)
+	if err != nil {
+		log.Fatalf("Failed to enable transport debugging: %v", err)
+	}
+	defer closeDebug()
 
 	// Create agent
 	ag, err := agent.NewAgent(cfg)
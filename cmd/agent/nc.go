@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// runNC implements "agent nc <overlay-ip|name> <port>". It asks the running
+// agent to connect to the port over the overlay and bridges the connection
+// to stdin and stdout, for use as an SSH ProxyCommand:
+//
+//	ssh -o ProxyCommand='easyanylink-agent nc %h %p' build-server
+func runNC(args []string) int {
+	fs := flag.NewFlagSet("nc", flag.ExitOnError)
+	timeout := fs.Duration("w", 10*time.Second, "Timeout of resolving and connecting")
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent nc [flags] <overlay-ip|name> <port>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	target := fs.Arg(0)
+	port, err := strconv.Atoi(fs.Arg(1))
+	if err != nil || port < 1 || port > 65535 {
+		fmt.Fprintf(os.Stderr, "Error: invalid port %q\n", fs.Arg(1))
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout+time.Second)
+	conn, err := agent.NewControlClient(*socket).Dial(ctx, target, port, *timeout)
+	cancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s:%d: %v\n", target, port, err)
+		return 1
+	}
+	defer conn.Close()
+
+	// The remote end closing ends the bridge, the end of stdin only
+	// half-closes it
+	go func() {
+		io.Copy(conn, os.Stdin)
+		if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+			cw.CloseWrite()
+		}
+	}()
+	if _, err := io.Copy(os.Stdout, conn); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// runPing implements "agent ping <overlay-ip|name>". It asks the running
+// agent to send overlay echo probes over the relay.
+func runPing(args []string) int {
+	fs := flag.NewFlagSet("ping", flag.ExitOnError)
+	count := fs.Int("c", 4, "Number of probes, 0 for unlimited")
+	interval := fs.Duration("i", time.Second, "Interval between probes")
+	timeout := fs.Duration("W", 5*time.Second, "Timeout of each probe")
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent ping [flags] <overlay-ip|name>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	target := fs.Arg(0)
+
+	client := agent.NewControlClient(*socket)
+	fmt.Printf("PING %s over the overlay relay\n", target)
+
+	var sent, received int
+	var min, max, total time.Duration
+	for i := 0; *count == 0 || i < *count; i++ {
+		if i > 0 {
+			time.Sleep(*interval)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout+time.Second)
+		rtt, err := client.Ping(ctx, target, *timeout)
+		cancel()
+		sent++
+
+		if err != nil {
+			fmt.Printf("probe %d: %v\n", i+1, err)
+			continue
+		}
+
+		received++
+		total += rtt
+		if min == 0 || rtt < min {
+			min = rtt
+		}
+		if rtt > max {
+			max = rtt
+		}
+		fmt.Printf("reply from %s: probe=%d time=%.3f ms\n", target, i+1, float64(rtt.Microseconds())/1000)
+	}
+
+	fmt.Printf("\n--- %s overlay ping statistics ---\n", target)
+	fmt.Printf("%d probes sent, %d received, %.0f%% loss\n", sent, received, float64(sent-received)*100/float64(sent))
+	if received > 0 {
+		avg := total / time.Duration(received)
+		fmt.Printf("rtt min/avg/max = %.3f/%.3f/%.3f ms\n",
+			float64(min.Microseconds())/1000, float64(avg.Microseconds())/1000, float64(max.Microseconds())/1000)
+	}
+
+	if received == 0 {
+		return 1
+	}
+	return 0
+}
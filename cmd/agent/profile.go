@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// runProfile implements "agent profile [list|use <name>]". It shows or
+// switches the server profile of the running agent.
+func runProfile(args []string) int {
+	fs := flag.NewFlagSet("profile", flag.ExitOnError)
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent profile [flags] [list | use <name>]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	client := agent.NewControlClient(*socket)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var status *agent.ProfileStatus
+	var err error
+	switch {
+	case fs.NArg() == 0 || (fs.NArg() == 1 && fs.Arg(0) == "list"):
+		status, err = client.Profiles(ctx)
+	case fs.NArg() == 2 && fs.Arg(0) == "use":
+		status, err = client.UseProfile(ctx, fs.Arg(1))
+	default:
+		fs.Usage()
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	for _, name := range status.Profiles {
+		marker := " "
+		if name == status.Active {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, name)
+	}
+	if status.Server != "" {
+		fmt.Printf("\nConnected to %s\n", status.Server)
+	}
+	return 0
+}
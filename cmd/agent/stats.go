@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// maxStatsRows is how many rows "agent stats" prints unless -step is given
+const maxStatsRows = 60
+
+// runStats implements "agent stats". It shows the per-minute traffic
+// history of the running agent, or the one it saved if it is not running.
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	last := fs.Duration("last", time.Hour, "How far back to show traffic, at most 24h")
+	step := fs.Duration("step", 0, "Interval of each row, by default chosen for at most 60 rows")
+	asJSON := fs.Bool("json", false, "Print the per-minute samples as JSON")
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	file := fs.String("file", agent.DefaultStatsFile, "Stats file read when the agent is not running")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent stats [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 || *last <= 0 || *step < 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	history, err := agent.NewControlClient(*socket).TrafficHistory(ctx, *last)
+	if err != nil {
+		saved, fileErr := agent.ReadTrafficHistory(*file, *last)
+		if fileErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Agent not reachable (%v), showing the history saved in %s\n", err, *file)
+		history = saved
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(history)
+		return 0
+	}
+
+	if *step == 0 {
+		*step = (*last / maxStatsRows).Truncate(time.Minute)
+	}
+	printStats(history, max(*step, time.Minute))
+	return 0
+}
+
+// printStats prints the history in rows covering step each, with totals
+func printStats(history *agent.TrafficHistory, step time.Duration) {
+	if len(history.Samples) == 0 {
+		fmt.Printf("No traffic recorded since %s\n", history.Since.Local().Format(time.RFC3339))
+		return
+	}
+
+	var rows []agent.TrafficSample
+	var total agent.TrafficSample
+	for _, s := range history.Samples {
+		start := s.Time.Truncate(step)
+		if len(rows) == 0 || !rows[len(rows)-1].Time.Equal(start) {
+			rows = append(rows, agent.TrafficSample{Time: start})
+		}
+		for _, sum := range []*agent.TrafficSample{&rows[len(rows)-1], &total} {
+			sum.BytesSent += s.BytesSent
+			sum.BytesReceived += s.BytesReceived
+			sum.PacketsSent += s.PacketsSent
+			sum.PacketsReceived += s.PacketsReceived
+			sum.Drops += s.Drops
+		}
+		// A row counts as connected if the session was up in any minute
+		rows[len(rows)-1].Connected = rows[len(rows)-1].Connected || s.Connected
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tSENT\tRECEIVED\tPACKETS OUT\tPACKETS IN\tDROPS\tCONNECTED")
+	for _, r := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n", r.Time.Local().Format("2006-01-02 15:04"),
+			formatBytes(r.BytesSent), formatBytes(r.BytesReceived), r.PacketsSent, r.PacketsReceived, r.Drops,
+			yesNo(r.Connected))
+	}
+	fmt.Fprintf(w, "TOTAL\t%s\t%s\t%d\t%d\t%d\t\n", formatBytes(total.BytesSent), formatBytes(total.BytesReceived),
+		total.PacketsSent, total.PacketsReceived, total.Drops)
+	w.Flush()
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// runStatus implements "agent status". It shows the session of the running
+// agent and why the last one ended, exiting with 1 while disconnected.
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the status as JSON")
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent status [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := agent.NewControlClient(*socket).Status(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(status)
+	} else {
+		printStatus(status)
+	}
+
+	if !status.Connected {
+		return 1
+	}
+	return 0
+}
+
+func printStatus(status *agent.ConnectionStatus) {
+	if status.Connected {
+		fmt.Printf("Connected to %s\n", status.Server)
+		fmt.Printf("Session: %s\n", status.SessionID)
+		fmt.Printf("Assigned IP: %s\n", status.AssignedIP)
+		if status.Group != "" {
+			fmt.Printf("Group: %s\n", status.Group)
+		}
+		if len(status.ServerRTTs) > 0 {
+			servers := slices.SortedFunc(maps.Keys(status.ServerRTTs), func(a, b string) int {
+				return int(status.ServerRTTs[a] - status.ServerRTTs[b])
+			})
+			rtts := make([]string, 0, len(servers))
+			for _, server := range servers {
+				rtts = append(rtts, fmt.Sprintf("%s %dms", server, status.ServerRTTs[server]))
+			}
+			fmt.Printf("Server handshakes: %s\n", strings.Join(rtts, ", "))
+		}
+	} else if status.Paused {
+		fmt.Println("Disconnected by user, run \"easyanylink-agent connect\" to connect")
+	} else {
+		fmt.Println("Disconnected")
+	}
+
+	if d := status.LastDisconnect; d != nil {
+		fmt.Printf("Last disconnect: %s (%s)", d.Reason, d.Time.Local().Format(time.RFC3339))
+		if d.Detail != "" {
+			fmt.Printf(": %s", d.Detail)
+		}
+		fmt.Println()
+	}
+	if status.Sandbox != "" {
+		fmt.Printf("Sandbox: %s\n", status.Sandbox)
+	}
+	if skew := time.Duration(status.ClockSkewMs) * time.Millisecond; skew >= time.Second || skew <= -time.Second {
+		fmt.Printf("Clock skew: %s (local clock minus server clock)\n", skew)
+	}
+	if e := status.LastError; e != nil {
+		fmt.Printf("Last error: %s: %s (%s)\n", e.Message, e.Error, e.Time.Local().Format(time.RFC3339))
+		if strings.HasPrefix(status.Sandbox, "enforce") && sandboxDenial(e.Error) {
+			fmt.Println(`Hint: the sandbox may have denied this, set "sandbox": "audit" and check the kernel audit log`)
+		}
+	}
+}
+
+// sandboxDenial reports whether an error looks like a call or file
+// access the sandbox denied
+func sandboxDenial(err string) bool {
+	return strings.Contains(err, "operation not permitted") || strings.Contains(err, "permission denied")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"github.com/taills/EasyAnyLink/common/support"
+)
+
+// runSupportBundle implements "agent support-bundle". It writes a tarball
+// with the sanitized configuration, recent logs, interfaces, routes and
+// version for an issue report. Secrets are redacted.
+func runSupportBundle(configFile string, args []string) int {
+	fs := flag.NewFlagSet("support-bundle", flag.ExitOnError)
+	stamp := time.Now().Format("20060102-150405")
+	out := fs.String("out", "easyanylink-agent-support-"+stamp+".tar.gz", "File to write the bundle to")
+	logFile := fs.String("log", "", "Log file to include, default log.file of the configuration")
+	socket := fs.String("socket", agent.DefaultControlSocket, "Agent control socket")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent [-config file] support-bundle [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	// The configuration may be invalid, that is often why a bundle is made
+	if *logFile == "" {
+		if cfg, err := config.LoadAgentConfig(configFile); err == nil {
+			*logFile = cfg.Log.File
+		}
+	}
+
+	bundle, err := support.Create(*out, "easyanylink-agent-support-"+stamp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := collectAgentBundle(bundle, configFile, *logFile, *socket); err != nil {
+		bundle.Close()
+		os.Remove(*out)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := bundle.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Support bundle written to %s\n", *out)
+	fmt.Printf("Secrets are redacted, review the contents before sharing it\n")
+	return 0
+}
+
+// collectAgentBundle adds the agent's files to a support bundle, the
+// configuration first so its secrets are scrubbed from everything else
+func collectAgentBundle(bundle *support.Bundle, configFile, logFile, socket string) error {
+	if err := bundle.AddConfig("config.json", configFile); err != nil {
+		return err
+	}
+	info := support.VersionInfo("agent", Version, GitCommit, BuildTime, crypto.PolicySummary())
+	if err := bundle.AddText("version.txt", info); err != nil {
+		return err
+	}
+	if err := bundle.AddSystemState(); err != nil {
+		return err
+	}
+	if err := bundle.AddLogs(logFile, "easyanylink-agent"); err != nil {
+		return err
+	}
+
+	// Connection state of a running agent
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := agent.NewControlClient(socket)
+	status, err := client.Profiles(ctx)
+	if err != nil {
+		return bundle.AddText("status.txt", []byte(fmt.Sprintf("agent not reachable on %s: %v\n", socket, err)))
+	}
+	if err := bundle.AddJSON("status.json", status); err != nil {
+		return err
+	}
+	connection, err := client.Status(ctx)
+	if err != nil {
+		if err := bundle.AddText("connection.txt", []byte(fmt.Sprintf("failed to get connection status: %v\n", err))); err != nil {
+			return err
+		}
+	} else if err := bundle.AddJSON("connection.json", connection); err != nil {
+		return err
+	}
+	health, err := client.Health(ctx)
+	if err != nil {
+		return bundle.AddText("health.txt", []byte(fmt.Sprintf("failed to get health: %v\n", err)))
+	}
+	return bundle.AddJSON("health.json", health)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/taills/EasyAnyLink/agent"
+)
+
+// runTrust implements "agent trust". It downloads the trust bundle of a
+// server that uses a private CA, for the ca_file setting.
+func runTrust(args []string) int {
+	fs := flag.NewFlagSet("trust", flag.ExitOnError)
+	server := fs.String("server", "", "Server address (host:port)")
+	fingerprint := fs.String("fingerprint", "", "SHA256 fingerprint of the server CA, as printed by \"server gen-ca\"")
+	out := fs.String("out", "certs/ca.crt", "File to write the trust bundle to")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-agent trust -server host:port -fingerprint sha256 [-out file]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *server == "" || *fingerprint == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	bundle, err := agent.FetchTrustBundle(ctx, *server, *fingerprint)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(*out, bundle, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Trust bundle written to %s\n", *out)
+	fmt.Printf("Set \"ca_file\": %q in the agent configuration\n", *out)
+	return 0
+}
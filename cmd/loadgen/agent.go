@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"runtime"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"github.com/taills/EasyAnyLink/common/packet"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// protocolVersion is the agent protocol the simulated agents speak
+const protocolVersion = "2.0.0"
+
+// simAgent is a simulated agent with its own QUIC connection and session
+type simAgent struct {
+	index     int // of the agent in the run, deriving its ID
+	slot      int // among the registered agents, mapping trace destinations to peers
+	id        string
+	conn      *grpc.ClientConn
+	client    proto.AgentServiceClient
+	sessionID string
+	ip        net.IP
+	mtu       int
+	keepalive time.Duration
+}
+
+// agentID derives the ID of the agent at index from the seed, so repeated
+// runs register the same agents rather than new ones
+func agentID(seed int64, index int) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, fmt.Appendf(nil, "easyanylink-loadgen/%d/%d", seed, index)).String()
+}
+
+// register connects the agent and registers it with the server, measuring
+// the registration
+func (a *simAgent) register(r *run) error {
+	conn, err := grpc.Dial(
+		r.server,
+		grpc.WithContextDialer(crypto.NewQUICDialer(r.tlsConfig).DialContext),
+		grpc.WithInsecure(), // TLS is handled by the dialer
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                30 * time.Second,
+			Timeout:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to dial server: %w", err)
+	}
+	a.conn = conn
+	a.client = proto.NewAgentServiceClient(conn)
+
+	req := &proto.RegisterRequest{
+		AgentId:         a.id,
+		UserKey:         r.userKey,
+		Type:            proto.AgentType_CLIENT,
+		ProtocolVersion: protocolVersion,
+		RequestId:       uuid.New().String(),
+		Metadata: &proto.AgentMetadata{
+			Os:       runtime.GOOS,
+			Arch:     runtime.GOARCH,
+			Version:  "loadgen",
+			Hostname: fmt.Sprintf("loadgen-%d", a.index),
+			Labels:   map[string]string{"loadgen": "true"},
+		},
+	}
+	if r.psk != "" {
+		if err := crypto.SignRegistration(r.psk, req, 0); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.ctx, 30*time.Second)
+	defer cancel()
+	start := time.Now()
+	resp, err := a.client.Register(ctx, req)
+	if err != nil {
+		return fmt.Errorf("registration failed: %w", err)
+	}
+	if !resp.Accepted {
+		return fmt.Errorf("registration rejected: %s", resp.ErrorMessage)
+	}
+	r.registrations.add(time.Since(start))
+
+	a.sessionID = resp.SessionId
+	a.ip = net.ParseIP(resp.AssignedIp).To4()
+	if a.ip == nil {
+		return fmt.Errorf("invalid assigned IP %q", resp.AssignedIp)
+	}
+	a.mtu, a.keepalive = 1400, 30*time.Second
+	if cfg := resp.ServerConfig; cfg != nil {
+		if cfg.Mtu > 0 {
+			a.mtu = int(cfg.Mtu)
+		}
+		if cfg.KeepaliveInterval > 0 {
+			a.keepalive = time.Duration(cfg.KeepaliveInterval) * time.Second
+		}
+	}
+	return nil
+}
+
+// heartbeat sends heartbeats at the interval of the server until ctx is
+// done, measuring their round trips
+func (a *simAgent) heartbeat(ctx context.Context, r *run) error {
+	stream, err := a.client.Heartbeat(ctx)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(a.keepalive)
+	defer ticker.Stop()
+	for {
+		sent := time.Now()
+		if err := stream.Send(&proto.HeartbeatRequest{SessionId: a.sessionID, Timestamp: timestamppb.New(sent)}); err != nil {
+			return ignoreDone(ctx, err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			return ignoreDone(ctx, err)
+		}
+		r.heartbeats.add(time.Since(sent))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay replays the trace to the other agents and receives what they
+// replay, until ctx is done
+func (a *simAgent) relay(ctx context.Context, r *run) error {
+	stream, err := a.client.RelayData(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&proto.DataPacket{SessionId: a.sessionID, SourceAgentId: a.id}); err != nil {
+		return err
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			dp, err := stream.Recv()
+			if err != nil {
+				errc <- ignoreDone(ctx, err)
+				return
+			}
+			if len(dp.Payload) == 0 {
+				continue
+			}
+			r.counters.received.Add(1)
+			r.counters.receivedBytes.Add(uint64(len(dp.Payload)))
+			if took, ok := r.inflight.received(dp.Payload, time.Now()); ok {
+				r.delivery.add(took)
+			}
+		}
+	}()
+
+	err = a.replay(ctx, r, stream)
+	stream.CloseSend()
+	if err != nil {
+		return err
+	}
+	return <-errc
+}
+
+// replay sends the trace packets at their recorded offsets, scaled by the
+// replay speed, from the agent to the peers their destinations map to.
+// Every run sends the same packets in the same order.
+func (a *simAgent) replay(ctx context.Context, r *run, stream proto.AgentService_RelayDataClient) error {
+	start := time.Now()
+	length := r.trace[len(r.trace)-1].at + time.Millisecond
+	for loop := 0; ; loop++ {
+		for _, tp := range r.trace {
+			due := start.Add(time.Duration(float64(time.Duration(loop)*length+tp.at) / r.speed))
+			if wait := time.Until(due); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(wait):
+				}
+			} else if ctx.Err() != nil {
+				return nil
+			}
+
+			if len(tp.data) > a.mtu {
+				r.counters.skipped.Add(1)
+				continue
+			}
+			data := append([]byte(nil), tp.data...)
+			peer := r.agents[peerIndex(a.slot, len(r.agents), tp.data[16:20])]
+			if err := packet.SetSource(data, a.ip); err != nil {
+				continue
+			}
+			packet.SetDestination(data, peer.ip)
+
+			r.inflight.sending(data, time.Now())
+			if err := stream.Send(&proto.DataPacket{SessionId: a.sessionID, SourceAgentId: a.id, Payload: data}); err != nil {
+				r.counters.sendErrors.Add(1)
+				return ignoreDone(ctx, err)
+			}
+			r.counters.sent.Add(1)
+			r.counters.sentBytes.Add(uint64(len(data)))
+		}
+		if !r.loop {
+			<-ctx.Done()
+			return nil
+		}
+	}
+}
+
+// disconnect ends the session of the agent and closes its connection
+func (a *simAgent) disconnect() {
+	if a.sessionID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		a.client.UpdateStatus(ctx, &proto.StatusUpdate{
+			SessionId: a.sessionID,
+			AgentId:   a.id,
+			Status:    proto.AgentStatus_OFFLINE,
+			Message:   "load test finished",
+		})
+		cancel()
+	}
+	if a.conn != nil {
+		a.conn.Close()
+	}
+}
+
+// peerIndex maps the original destination of a trace packet to one of the
+// other agents, the same one every run
+func peerIndex(from, agents int, dst []byte) int {
+	h := fnv.New32a()
+	h.Write(dst)
+	return (from + 1 + int(h.Sum32()%uint32(agents-1))) % agents
+}
+
+// ignoreDone drops the error of a stream ended because ctx is done
+func ignoreDone(ctx context.Context, err error) error {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// clientTLS returns the TLS configuration of the simulated agents
+func clientTLS(server, caFile string, insecure bool) (*tls.Config, error) {
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server address: %w", err)
+	}
+	return crypto.LoadClientTLSConfigWithCA(host, caFile, insecure)
+}
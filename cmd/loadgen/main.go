@@ -0,0 +1,277 @@
+// Command loadgen load tests a server. It simulates agents that register,
+// send heartbeats and replay a recorded packet trace to each other through
+// the relay, and reports the throughput and latency percentiles.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// run is the state of a load test shared by its agents
+type run struct {
+	ctx       context.Context
+	server    string
+	tlsConfig *tls.Config
+	userKey   string
+	psk       string
+	trace     []tracePacket
+	speed     float64
+	loop      bool
+
+	agents []*simAgent // registered agents, by slot
+
+	counters      counters
+	inflight      *inflight
+	registrations latencies
+	heartbeats    latencies
+	delivery      latencies
+}
+
+// report is the result of a load test
+type report struct {
+	Agents        int      `json:"agents"`
+	Registered    int      `json:"registered"`
+	Duration      float64  `json:"duration_seconds"`
+	TracePackets  int      `json:"trace_packets"`
+	Sent          uint64   `json:"packets_sent"`
+	Received      uint64   `json:"packets_received"`
+	Skipped       uint64   `json:"packets_skipped"`
+	SendErrors    uint64   `json:"send_errors"`
+	LossPercent   float64  `json:"loss_percent"`
+	SentPPS       float64  `json:"sent_pps"`
+	ReceivedPPS   float64  `json:"received_pps"`
+	SentMbps      float64  `json:"sent_mbps"`
+	ReceivedMbps  float64  `json:"received_mbps"`
+	Registration  summary  `json:"registration"`
+	Heartbeat     summary  `json:"heartbeat_rtt"`
+	Delivery      summary  `json:"delivery"`
+	FailedReasons []string `json:"failed_registrations,omitempty"`
+}
+
+func main() {
+	fs := flag.NewFlagSet("loadgen", flag.ExitOnError)
+	server := fs.String("server", "", "Server QUIC address, host:port")
+	userKey := fs.String("user-key", os.Getenv("EASYANYLINK_USER_KEY"), "User API key the agents register with, default $EASYANYLINK_USER_KEY")
+	caFile := fs.String("ca", "", "CA certificate verifying the server")
+	insecure := fs.Bool("insecure", false, "Skip verifying the server certificate")
+	psk := fs.String("psk", "", "Pre-shared key signing registrations, if the server requires one")
+	agents := fs.Int("agents", 10, "Simulated agents, at least 2")
+	duration := fs.Duration("duration", time.Minute, "How long the agents relay")
+	ramp := fs.Duration("ramp", 0, "Spread the registrations over this time")
+	tracePath := fs.String("trace", "", "pcap file of IPv4 packets to replay, e.g. from tcpdump -w on an agent TUN; empty for a synthetic trace")
+	rate := fs.Int("rate", 100, "Packets per second of each agent with the synthetic trace")
+	size := fs.Int("size", 512, "Packet size in bytes of the synthetic trace")
+	speed := fs.Float64("speed", 1, "Replay speed, 2 replays the trace twice as fast as recorded")
+	once := fs.Bool("once", false, "Replay the trace once instead of looping it")
+	seed := fs.Int64("seed", 1, "Seed of the agent IDs, the same seed registers the same agents")
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: loadgen -server host:port [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Simulates agents that register, send heartbeats and replay a packet trace to\n")
+		fmt.Fprintf(os.Stderr, "each other through the server relay, then reports throughput and latency.\n")
+		fmt.Fprintf(os.Stderr, "Every agent replays the whole trace, each packet readdressed from the agent\n")
+		fmt.Fprintf(os.Stderr, "to the peer its original destination maps to, in the same order every run.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[1:])
+
+	if *server == "" || *userKey == "" || *agents < 2 || *speed <= 0 || *rate < 1 || fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	os.Exit(runLoad(*server, *userKey, *caFile, *insecure, *psk, *agents, *duration, *ramp,
+		*tracePath, *rate, *size, *speed, !*once, *seed, *jsonOutput))
+}
+
+// runLoad runs the load test and prints its report
+func runLoad(server, userKey, caFile string, insecure bool, psk string, agents int, duration, ramp time.Duration,
+	tracePath string, rate, size int, speed float64, loop bool, seed int64, jsonOutput bool) int {
+	tlsConfig, err := clientTLS(server, caFile, insecure)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	trace := syntheticTrace(rate, size)
+	if tracePath != "" {
+		if trace, err = readTrace(tracePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", tracePath, err)
+			return 1
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	r := &run{
+		ctx:       ctx,
+		server:    server,
+		tlsConfig: tlsConfig,
+		userKey:   userKey,
+		psk:       psk,
+		trace:     trace,
+		speed:     speed,
+		loop:      loop,
+		inflight:  newInflight(),
+	}
+
+	// Register the agents, spread over the ramp
+	log.Printf("Registering %d agents with %s", agents, server)
+	var failed []string
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < agents; i++ {
+		a := &simAgent{index: i, id: agentID(seed, i)}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := a.register(r); err != nil {
+				a.disconnect()
+				mu.Lock()
+				failed = append(failed, fmt.Sprintf("agent %d: %v", a.index, err))
+				mu.Unlock()
+				return
+			}
+			mu.Lock()
+			r.agents = append(r.agents, a)
+			mu.Unlock()
+		}()
+		if ramp > 0 && i < agents-1 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(ramp / time.Duration(agents-1)):
+			}
+		}
+	}
+	wg.Wait()
+	for _, reason := range failed {
+		log.Printf("Registration failed: %s", reason)
+	}
+	if len(r.agents) < 2 {
+		fmt.Fprintf(os.Stderr, "Error: %d of %d agents registered, at least 2 are needed\n", len(r.agents), agents)
+		for _, a := range r.agents {
+			a.disconnect()
+		}
+		return 1
+	}
+	// Peers map by slot, the same for every run in which all register
+	slices.SortFunc(r.agents, func(a, b *simAgent) int { return a.index - b.index })
+	for slot, a := range r.agents {
+		a.slot = slot
+	}
+
+	// Relay for the duration
+	log.Printf("%d agents registered, replaying %d trace packets each for %s", len(r.agents), len(trace), duration)
+	runCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+	start := time.Now()
+	go func() {
+		// Packets not delivered within 10 seconds are lost
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case now := <-ticker.C:
+				r.inflight.expire(now.Add(-10 * time.Second))
+			}
+		}
+	}()
+	for _, a := range r.agents {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := a.heartbeat(runCtx, r); err != nil {
+				log.Printf("Heartbeat of agent %d failed: %v", a.index, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := a.relay(runCtx, r); err != nil {
+				log.Printf("Relay of agent %d failed: %v", a.index, err)
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	// Leave the server as found
+	for _, a := range r.agents {
+		a.disconnect()
+	}
+
+	rep := r.report(agents, elapsed, failed)
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(rep)
+	} else {
+		printReport(rep)
+	}
+	if len(failed) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// report sums up the run
+func (r *run) report(agents int, elapsed time.Duration, failed []string) report {
+	c := &r.counters
+	rep := report{
+		Agents:        agents,
+		Registered:    len(r.agents),
+		Duration:      elapsed.Seconds(),
+		TracePackets:  len(r.trace),
+		Sent:          c.sent.Load(),
+		Received:      c.received.Load(),
+		Skipped:       c.skipped.Load(),
+		SendErrors:    c.sendErrors.Load(),
+		Registration:  r.registrations.summary(),
+		Heartbeat:     r.heartbeats.summary(),
+		Delivery:      r.delivery.summary(),
+		FailedReasons: failed,
+	}
+	if rep.Sent > 0 {
+		rep.LossPercent = 100 * float64(rep.Sent-min(rep.Sent, rep.Received)) / float64(rep.Sent)
+	}
+	seconds := elapsed.Seconds()
+	rep.SentPPS = float64(rep.Sent) / seconds
+	rep.ReceivedPPS = float64(rep.Received) / seconds
+	rep.SentMbps = float64(c.sentBytes.Load()) * 8 / 1e6 / seconds
+	rep.ReceivedMbps = float64(c.receivedBytes.Load()) * 8 / 1e6 / seconds
+	return rep
+}
+
+// printReport prints the report as text
+func printReport(rep report) {
+	fmt.Printf("Agents:       %d of %d registered\n", rep.Registered, rep.Agents)
+	fmt.Printf("Duration:     %.1fs, trace of %d packets\n", rep.Duration, rep.TracePackets)
+	fmt.Printf("Sent:         %d packets, %.0f pps, %.2f Mbit/s\n", rep.Sent, rep.SentPPS, rep.SentMbps)
+	fmt.Printf("Received:     %d packets, %.0f pps, %.2f Mbit/s\n", rep.Received, rep.ReceivedPPS, rep.ReceivedMbps)
+	fmt.Printf("Loss:         %.2f%%\n", rep.LossPercent)
+	if rep.Skipped > 0 || rep.SendErrors > 0 {
+		fmt.Printf("Not sent:     %d packets over the MTU, %d send errors\n", rep.Skipped, rep.SendErrors)
+	}
+	fmt.Println()
+	fmt.Printf("%-14s %8s %10s %10s %10s %10s\n", "LATENCY", "COUNT", "P50 ms", "P90 ms", "P99 ms", "MAX ms")
+	for _, row := range []struct {
+		name string
+		s    summary
+	}{
+		{"registration", rep.Registration},
+		{"heartbeat rtt", rep.Heartbeat},
+		{"delivery", rep.Delivery},
+	} {
+		fmt.Printf("%-14s %8d %10.2f %10.2f %10.2f %10.2f\n", row.name, row.s.Count, row.s.P50, row.s.P90, row.s.P99, row.s.Max)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxSamples bounds the latencies kept per measurement, later ones replace
+// random earlier ones
+const maxSamples = 1 << 20
+
+// latencies collects durations for percentiles
+type latencies struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	seen    uint64
+	state   uint64 // of the generator picking replaced samples, deterministic per run
+}
+
+func (l *latencies) add(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seen++
+	if len(l.samples) < maxSamples {
+		l.samples = append(l.samples, d)
+		return
+	}
+	// Reservoir sampling, with a xorshift generator
+	if l.state == 0 {
+		l.state = 0x9e3779b97f4a7c15
+	}
+	l.state ^= l.state << 13
+	l.state ^= l.state >> 7
+	l.state ^= l.state << 17
+	if i := l.state % l.seen; i < maxSamples {
+		l.samples[i] = d
+	}
+}
+
+// summary holds the percentiles of a measurement in milliseconds
+type summary struct {
+	Count uint64  `json:"count"`
+	P50   float64 `json:"p50_ms"`
+	P90   float64 `json:"p90_ms"`
+	P99   float64 `json:"p99_ms"`
+	Max   float64 `json:"max_ms"`
+}
+
+func (l *latencies) summary() summary {
+	l.mu.Lock()
+	sorted := slices.Clone(l.samples)
+	seen := l.seen
+	l.mu.Unlock()
+	if len(sorted) == 0 {
+		return summary{}
+	}
+	slices.Sort(sorted)
+	at := func(p float64) float64 {
+		return float64(sorted[int(p*float64(len(sorted)-1))]) / float64(time.Millisecond)
+	}
+	return summary{Count: seen, P50: at(0.5), P90: at(0.9), P99: at(0.99), Max: at(1)}
+}
+
+// counters count the packets and bytes of the run
+type counters struct {
+	sent, sentBytes         atomic.Uint64
+	received, receivedBytes atomic.Uint64
+	skipped                 atomic.Uint64 // trace packets larger than the granted MTU
+	sendErrors              atomic.Uint64
+}
+
+// inflight matches the packets agents receive to when they were sent. All
+// agents run in one process, so one-way delivery latency needs no clock
+// synchronization.
+type inflight struct {
+	mu   sync.Mutex
+	sent map[uint64][]time.Time
+}
+
+func newInflight() *inflight {
+	return &inflight{sent: make(map[uint64][]time.Time)}
+}
+
+// packetKey hashes a packet leaving out the TTL and header checksum, which
+// routers on the way change
+func packetKey(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b[:8])
+	h.Write(b[12:])
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(b)))
+	h.Write(length[:])
+	return h.Sum64()
+}
+
+func (f *inflight) sending(b []byte, at time.Time) {
+	key := packetKey(b)
+	f.mu.Lock()
+	f.sent[key] = append(f.sent[key], at)
+	f.mu.Unlock()
+}
+
+// received returns how long ago the packet b was sent, false for a packet
+// not sent by the run or already matched
+func (f *inflight) received(b []byte, at time.Time) (time.Duration, bool) {
+	if len(b) < 12 {
+		return 0, false
+	}
+	key := packetKey(b)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	times := f.sent[key]
+	if len(times) == 0 {
+		return 0, false
+	}
+	if len(times) == 1 {
+		delete(f.sent, key)
+	} else {
+		f.sent[key] = times[1:]
+	}
+	return at.Sub(times[0]), true
+}
+
+// expire forgets packets sent before cutoff, they count as lost
+func (f *inflight) expire(cutoff time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key, times := range f.sent {
+		i := 0
+		for i < len(times) && times[i].Before(cutoff) {
+			i++
+		}
+		if i == len(times) {
+			delete(f.sent, key)
+		} else if i > 0 {
+			f.sent[key] = times[i:]
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+)
+
+// tracePacket is an IPv4 packet of a trace with its offset from the
+// first packet
+type tracePacket struct {
+	at   time.Duration
+	data []byte
+}
+
+// Link types of pcap files the trace reader takes apart
+const (
+	linkNull     = 0   // BSD loopback, 4 byte address family
+	linkEthernet = 1   // Ethernet II, optionally VLAN tagged
+	linkRaw      = 101 // raw IP, also written as 12 and 14 by some platforms
+	linkSLL      = 113 // Linux cooked capture
+	linkSLL2     = 276 // Linux cooked capture v2
+)
+
+// readTrace reads the IPv4 packets of a classic pcap file, as written by
+// tcpdump -w on the TUN interface of an agent or any other interface.
+// Other packets are skipped.
+func readTrace(path string) ([]tracePacket, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	var header [24]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("failed to read pcap header: %w", err)
+	}
+	var order binary.ByteOrder
+	var nanos bool
+	switch magic := binary.LittleEndian.Uint32(header[0:4]); magic {
+	case 0xa1b2c3d4, 0xa1b23c4d:
+		order, nanos = binary.LittleEndian, magic == 0xa1b23c4d
+	case 0xd4c3b2a1, 0x4d3cb2a1:
+		order, nanos = binary.BigEndian, magic == 0x4d3cb2a1
+	case 0x0a0d0d0a:
+		return nil, errors.New("pcapng is not supported, convert it with editcap -F pcap")
+	default:
+		return nil, errors.New("not a pcap file")
+	}
+	linkType := order.Uint32(header[20:24]) & 0xffff
+
+	var packets []tracePacket
+	var first time.Time
+	var record [16]byte
+	for {
+		if _, err := io.ReadFull(r, record[:]); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("truncated pcap record: %w", err)
+		}
+		sec, frac := order.Uint32(record[0:4]), order.Uint32(record[4:8])
+		if !nanos {
+			frac *= 1000
+		}
+		at := time.Unix(int64(sec), int64(frac))
+		data := make([]byte, order.Uint32(record[8:12]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("truncated pcap record: %w", err)
+		}
+
+		ip := linkPayload(linkType, data)
+		if ip == nil {
+			continue
+		}
+		h, err := packet.ParseIPv4(ip)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() {
+			first = at
+		}
+		packets = append(packets, tracePacket{at: at.Sub(first), data: ip[:h.TotalLen]})
+	}
+	if len(packets) == 0 {
+		return nil, errors.New("trace holds no IPv4 packets")
+	}
+	return packets, nil
+}
+
+// linkPayload returns the IPv4 packet of a frame of the link type, nil for
+// other frames
+func linkPayload(linkType uint32, frame []byte) []byte {
+	switch linkType {
+	case linkRaw, 12, 14:
+		return frame
+	case linkNull:
+		// The address family is in host order of the capturing machine
+		if len(frame) >= 4 && (frame[0] == 2 || frame[3] == 2) {
+			return frame[4:]
+		}
+	case linkEthernet:
+		if len(frame) < 14 {
+			return nil
+		}
+		etherType, offset := binary.BigEndian.Uint16(frame[12:14]), 14
+		if etherType == 0x8100 && len(frame) >= 18 {
+			etherType, offset = binary.BigEndian.Uint16(frame[16:18]), 18
+		}
+		if etherType == 0x0800 {
+			return frame[offset:]
+		}
+	case linkSLL:
+		if len(frame) >= 16 && binary.BigEndian.Uint16(frame[14:16]) == 0x0800 {
+			return frame[16:]
+		}
+	case linkSLL2:
+		if len(frame) >= 20 && binary.BigEndian.Uint16(frame[0:2]) == 0x0800 {
+			return frame[20:]
+		}
+	}
+	return nil
+}
+
+// syntheticTrace is a second of rate UDP packets of size bytes, evenly
+// spaced, for runs without a recorded trace
+func syntheticTrace(rate, size int) []tracePacket {
+	if size < packet.IPv4HeaderLen+8 {
+		size = packet.IPv4HeaderLen + 8
+	}
+	src := net.IPv4(192, 0, 2, 1)
+	packets := make([]tracePacket, rate)
+	for i := range packets {
+		// Destinations vary so the packets spread over the peers
+		dst := net.IPv4(198, 51, 100, byte(1+i%254))
+		udp := make([]byte, size-packet.IPv4HeaderLen)
+		binary.BigEndian.PutUint16(udp[0:2], uint16(40000+i%1000))
+		binary.BigEndian.PutUint16(udp[2:4], 9) // discard
+		binary.BigEndian.PutUint16(udp[4:6], uint16(len(udp)))
+		packets[i] = tracePacket{
+			at:   time.Duration(i) * time.Second / time.Duration(rate),
+			data: packet.BuildIPv4(src, dst, packet.ProtocolUDP, udp),
+		}
+	}
+	return packets
+}
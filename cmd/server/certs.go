@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+)
+
+const day = 24 * time.Hour
+
+// runGenCA implements "server gen-ca". It creates the private CA that
+// issues server and agent certificates.
+func runGenCA(args []string) int {
+	fs := flag.NewFlagSet("gen-ca", flag.ExitOnError)
+	certFile := fs.String("cert", "certs/ca.crt", "CA certificate output file")
+	keyFile := fs.String("key", "certs/ca.key", "CA private key output file, kept offline if possible")
+	name := fs.String("name", "EasyAnyLink CA", "CA common name")
+	days := fs.Int("days", 3650, "Validity in days")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	fs.Parse(args)
+
+	ca, err := crypto.NewCA(*name, time.Duration(*days)*day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	keyPEM, err := ca.KeyPEM()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := crypto.WriteKeyPair(*certFile, *keyFile, ca.CertPEM(), keyPEM, *force); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v (use -force to overwrite)\n", err)
+		return 1
+	}
+
+	fmt.Printf("CA certificate: %s\n", *certFile)
+	fmt.Printf("CA private key: %s\n", *keyFile)
+	fmt.Printf("Fingerprint:    %s\n", ca.Fingerprint())
+	fmt.Printf("\nSet \"ca_file\": %q in the server configuration to serve it as the trust bundle.\n", *certFile)
+	fmt.Printf("New agents fetch it with: easyanylink-agent trust -server <host:port> -fingerprint %s\n", ca.Fingerprint())
+	return 0
+}
+
+// runGenCert implements "server gen-cert". It issues a server or agent
+// certificate signed by the private CA.
+func runGenCert(args []string) int {
+	fs := flag.NewFlagSet("gen-cert", flag.ExitOnError)
+	caCert := fs.String("ca", "certs/ca.crt", "CA certificate")
+	caKey := fs.String("ca-key", "certs/ca.key", "CA private key")
+	certType := fs.String("type", "server", "Certificate type: server or agent")
+	name := fs.String("name", "", "Common name, default the first host for servers")
+	hosts := fs.String("hosts", "", "Comma-separated DNS names and IP addresses of the server")
+	certFile := fs.String("cert", "", "Certificate output file (default certs/<type>.crt)")
+	keyFile := fs.String("key", "", "Private key output file (default certs/<type>.key)")
+	days := fs.Int("days", 365, "Validity in days")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	fs.Parse(args)
+
+	var usage crypto.CertUsage
+	switch *certType {
+	case "server":
+		usage = crypto.ServerCert
+	case "agent":
+		usage = crypto.AgentCert
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid type %q, must be server or agent\n", *certType)
+		return 2
+	}
+
+	var hostList []string
+	for _, host := range strings.Split(*hosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hostList = append(hostList, host)
+		}
+	}
+	if *name == "" && len(hostList) > 0 {
+		*name = hostList[0]
+	}
+	if *name == "" {
+		fmt.Fprintf(os.Stderr, "Error: -name or -hosts is required\n")
+		return 2
+	}
+	if *certFile == "" {
+		*certFile = "certs/" + *certType + ".crt"
+	}
+	if *keyFile == "" {
+		*keyFile = "certs/" + *certType + ".key"
+	}
+
+	ca, err := crypto.LoadCA(*caCert, *caKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	certPEM, keyPEM, err := ca.Issue(*name, hostList, usage, time.Duration(*days)*day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := crypto.WriteKeyPair(*certFile, *keyFile, certPEM, keyPEM, *force); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v (use -force to overwrite)\n", err)
+		return 1
+	}
+
+	fmt.Printf("Certificate: %s\n", *certFile)
+	fmt.Printf("Private key: %s\n", *keyFile)
+	return 0
+}
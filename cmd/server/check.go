@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"github.com/taills/EasyAnyLink/server"
+)
+
+// checkLabels are the labels of check results, all four characters wide
+var checkLabels = map[server.CheckStatus]string{
+	server.CheckOK:   " OK ",
+	server.CheckWarn: "WARN",
+	server.CheckFail: "FAIL",
+	server.CheckSkip: "SKIP",
+}
+
+// checkReport is the JSON report of "server check"
+type checkReport struct {
+	OK     bool                 `json:"ok"`
+	Checks []server.CheckResult `json:"checks"`
+}
+
+// runCheck implements "server check". It verifies that the server can
+// start with its configuration, for deploy pipelines to run before a
+// rollout, exiting with 1 if a check failed.
+func runCheck(configFile string, args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-server [-config file] check [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Checks the configuration, the database and its schema, the certificate,\n")
+		fmt.Fprintf(os.Stderr, "the listen addresses and the overlay network. Run it with the server\n")
+		fmt.Fprintf(os.Stderr, "stopped, a running server holds the listen addresses.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	results := []server.CheckResult{{Name: "configuration " + configFile}}
+	cfg, err := config.LoadServerConfig(configFile)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err == nil {
+		err = crypto.SetPolicy(cfg.Security.CryptoPolicy)
+	}
+	if err != nil {
+		results[0].Status, results[0].Detail = server.CheckFail, err.Error()
+	} else {
+		results[0].Status, results[0].Detail = server.CheckOK, "valid"
+		results = append(results, server.Preflight(cfg)...)
+	}
+
+	report := checkReport{OK: true, Checks: results}
+	for _, r := range results {
+		report.OK = report.OK && r.Status != server.CheckFail
+	}
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		for _, r := range results {
+			fmt.Printf("[%s] %s: %s\n", checkLabels[r.Status], r.Name, r.Detail)
+			if r.Hint != "" && r.Status != server.CheckOK {
+				fmt.Printf("       -> %s\n", r.Hint)
+			}
+		}
+	}
+
+	if !report.OK {
+		return 1
+	}
+	return 0
+}
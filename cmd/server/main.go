@@ -1,21 +1,30 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/taills/EasyAnyLink/common/config"
 	"github.com/taills/EasyAnyLink/common/crypto"
-	"github.com/taills/EasyAnyLink/common/proto"
+	"github.com/taills/EasyAnyLink/common/logging"
 	"github.com/taills/EasyAnyLink/server"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 )
 
+// upgradeDrainTimeout is how long the old process of a binary upgrade
+// waits for its agents to reconnect to the new one
+const upgradeDrainTimeout = 10 * time.Second
+
 var (
 	Version   = "dev"
 	GitCommit = "unknown"
@@ -33,9 +42,27 @@ func main() {
 		fmt.Printf("Version: %s\n", Version)
 		fmt.Printf("Git Commit: %s\n", GitCommit)
 		fmt.Printf("Build Time: %s\n", BuildTime)
+		fmt.Printf("Crypto Policy: %s\n", crypto.PolicySummary())
 		os.Exit(0)
 	}
 
+	// Certificate and support commands work without a valid configuration
+	// or database
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "gen-ca":
+			os.Exit(runGenCA(flag.Args()[1:]))
+		case "gen-cert":
+			os.Exit(runGenCert(flag.Args()[1:]))
+		case "support-bundle":
+			os.Exit(runSupportBundle(*configFile, flag.Args()[1:]))
+		case "check":
+			os.Exit(runCheck(*configFile, flag.Args()[1:]))
+		default:
+			log.Fatalf("Unknown command: %s", flag.Arg(0))
+		}
+	}
+
 	// Load configuration
 	cfg, err := config.LoadServerConfig(*configFile)
 	if err != nil {
@@ -45,6 +72,14 @@ func main() {
 	if err := cfg.Validate(); err != nil {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
+	if err := crypto.SetPolicy(cfg.Security.CryptoPolicy); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	// Logs go to log.output from here on, with secrets and, per
+	// log.redact, addresses masked
+	if err := logging.Setup(cfg.Log, cfg); err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
 
 	log.Printf("Starting EasyAnyLink Server version %s", Version)
 	log.Printf("Listening on %s", cfg.Listen)
@@ -63,7 +98,18 @@ func main() {
 	}
 
 	log.Println("Using one-way TLS with QUIC transport")
-	log.Println("Agents will verify server certificate using system root CAs")
+	if cfg.CAFile != "" {
+		log.Printf("Serving trust bundle %s to new agents", cfg.CAFile)
+	} else {
+		log.Println("Agents will verify server certificate using system root CAs")
+	}
+
+	// Transport debugging is off unless configured or set in the environment
+	closeDebug, err := crypto.EnableTransportDebug(cfg.Debug.KeyLogFile, cfg.Debug.QlogDir)
+	if err != nil {
+		log.Fatalf("Failed to enable transport debugging: %v", err)
+	}
+	defer closeDebug()
 
 	// Load TLS configuration for QUIC
 	tlsConfig, err := crypto.LoadServerTLSConfig(cfg.CertFile, cfg.KeyFile)
@@ -71,47 +117,234 @@ func main() {
 		log.Fatalf("Failed to load TLS configuration: %v", err)
 	}
 
+	// Sockets passed by systemd socket activation replace the configured
+	// listen addresses
+	activated, err := server.ActivatedSockets()
+	if err != nil {
+		log.Fatalf("Failed to use sockets from systemd: %v", err)
+	}
+
 	// Create QUIC listener
-	quicListener, err := crypto.NewQUICListener(cfg.Listen, tlsConfig)
+	var quicListener *crypto.QUICListener
+	if activated.UDP != nil {
+		quicListener, err = crypto.NewQUICListenerOn(activated.UDP, tlsConfig, cfg.Security.RetryThreshold)
+	} else {
+		quicListener, err = crypto.NewQUICListener(cfg.Listen, tlsConfig, cfg.Security.RetryThreshold)
+	}
 	if err != nil {
 		log.Fatalf("Failed to create QUIC listener: %v", err)
 	}
 	defer quicListener.Close()
-	log.Printf("QUIC listener started on %s", cfg.Listen)
+	if activated.UDP != nil {
+		log.Printf("QUIC listener started on %s from systemd", quicListener.Addr())
+	} else {
+		log.Printf("QUIC listener started on %s", cfg.Listen)
+	}
 
 	// Create gRPC server
-	grpcServer := grpc.NewServer(
-		grpc.MaxConcurrentStreams(10000),
-	)
+	grpcServer := grpc.NewServer(grpcServerOptions(cfg.GRPC)...)
 
 	// Register service
 	agentServer, err := server.NewServer(cfg, db)
 	if err != nil {
 		log.Fatalf("Failed to create server: %v", err)
 	}
+	defer agentServer.Close()
 
-	proto.RegisterAgentServiceServer(grpcServer, agentServer)
+	agentServer.SetHandshakeStats(quicListener.HandshakeStats)
+
+	// Serve every API version, older agents keep working
+	server.RegisterServices(grpcServer, agentServer)
 
 	// Register reflection for grpcurl
 	reflection.Register(grpcServer)
 
+	// Serve the REST gateway over TLS next to gRPC if configured
+	var gateway *http.Server
+	var gatewayListener net.Listener
+	if cfg.Gateway.Listen != "" {
+		handler, err := agentServer.GatewayHandler(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to create REST gateway: %v", err)
+		}
+		// Agents that cannot use QUIC fall back to gRPC on this port
+		handler = server.WithGRPC(grpcServer, handler)
+		gatewayTLS := tlsConfig.Clone()
+		gatewayTLS.NextProtos = []string{"h2", "http/1.1"}
+		gateway = &http.Server{
+			Handler:           handler,
+			TLSConfig:         gatewayTLS,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		gatewayListener = activated.TCP
+		if gatewayListener == nil {
+			gatewayListener, err = net.Listen("tcp", cfg.Gateway.Listen)
+			if err != nil {
+				log.Fatalf("Failed to create REST gateway listener: %v", err)
+			}
+		}
+		go func() {
+			if err := gateway.ServeTLS(gatewayListener, "", ""); err != http.ErrServerClosed {
+				log.Printf("REST gateway stopped: %v", err)
+			}
+		}()
+		log.Printf("REST gateway listening on %s", gatewayListener.Addr())
+	} else if activated.TCP != nil {
+		activated.TCP.Close()
+		log.Println("Warning: ignoring the TCP socket from systemd, the REST gateway is not configured")
+	}
+
+	// Agents in MASQUE mode tunnel QUIC inside HTTP/3 CONNECT-UDP; their
+	// connections are served from a QUIC listener of their own
+	if cfg.Masque.Listen != "" {
+		masque, err := server.NewMasqueEndpoint(cfg.Masque, tlsConfig, quicListener.Addr())
+		if err != nil {
+			log.Fatalf("Failed to create MASQUE endpoint: %v", err)
+		}
+		defer masque.Close()
+		masqueListener, err := crypto.NewQUICListenerOn(masque.PacketConn(), tlsConfig, cfg.Security.RetryThreshold)
+		if err != nil {
+			log.Fatalf("Failed to create MASQUE QUIC listener: %v", err)
+		}
+		defer masqueListener.Close()
+		go func() {
+			if err := masque.Serve(); err != nil && err != http.ErrServerClosed {
+				log.Printf("MASQUE endpoint stopped: %v", err)
+			}
+		}()
+		go func() {
+			if err := grpcServer.Serve(server.LimitListener(masqueListener, cfg.Security.MaxConnectionsPerIP)); err != nil {
+				log.Printf("MASQUE tunnels stopped: %v", err)
+			}
+		}()
+		log.Printf("MASQUE endpoint listening on %s", masque.Addr())
+	}
+
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// SIGUSR2 hands the sockets over to a new server binary
+	upgradeChan := make(chan os.Signal, 1)
+	notifyUpgrade(upgradeChan)
+
+	// Serve returns as soon as GracefulStop starts; wait for the running
+	// handlers to finish before the deferred Close stops background work
+	stopped := make(chan struct{})
 	go func() {
-		sig := <-sigChan
-		log.Printf("Received signal %v, shutting down gracefully...", sig)
-		grpcServer.GracefulStop()
+		defer close(stopped)
+		for {
+			select {
+			case sig := <-sigChan:
+				log.Printf("Received signal %v, shutting down gracefully...", sig)
+				server.SdNotify("STOPPING=1")
+				if gateway != nil {
+					ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+					gateway.Shutdown(ctx)
+					cancel()
+				}
+				grpcServer.GracefulStop()
+				return
+
+			case <-upgradeChan:
+				// The new process could not take the HA link over
+				if cfg.HA.Listen != "" {
+					log.Println("Ignoring upgrade signal: upgrade an HA pair by restarting the standby, then the active server")
+					continue
+				}
+				// nor the MASQUE endpoint's socket
+				if cfg.Masque.Listen != "" {
+					log.Println("Ignoring upgrade signal: restart a server with a MASQUE endpoint instead")
+					continue
+				}
+				log.Println("Received upgrade signal, starting the new server binary...")
+				child, err := startUpgrade(quicListener, gatewayListener)
+				if err != nil {
+					log.Printf("Upgrade failed, still serving: %v", err)
+					continue
+				}
+				log.Printf("New server process %d accepts connections, handing over", child.Pid)
+				server.SdNotify(fmt.Sprintf("MAINPID=%d", child.Pid))
+				child.Release()
+				handOver(grpcServer, quicListener, gateway, agentServer)
+				return
+			}
+		}
 	}()
 
 	// Start server
 	log.Printf("Server listening on %s with QUIC transport", cfg.Listen)
 	log.Println("Press Ctrl+C to stop")
 
-	if err := grpcServer.Serve(quicListener); err != nil {
+	// Tell systemd (Type=notify) the server accepts connections
+	if err := server.SdNotify("READY=1"); err != nil {
+		log.Printf("Warning: failed to notify systemd: %v", err)
+	}
+	upgradeReady()
+
+	if err := grpcServer.Serve(server.LimitListener(quicListener, cfg.Security.MaxConnectionsPerIP)); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
+	<-stopped
 
 	log.Println("Server stopped")
 }
+
+// handOver drains this process once a new one took over the sockets: it
+// stops accepting, ends the sessions so the agents reconnect to the new
+// process and waits up to upgradeDrainTimeout for them to leave. Both
+// processes read the shared socket meanwhile; a packet the wrong one reads
+// is lost and retransmitted, so the drain is kept short.
+func handOver(grpcServer *grpc.Server, quicListener *crypto.QUICListener, gateway *http.Server, agentServer *server.Server) {
+	quicListener.StopAccepting()
+	if gateway != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), upgradeDrainTimeout)
+		gateway.Shutdown(ctx)
+		cancel()
+	}
+	agentServer.HandOver()
+
+	deadline := time.Now().Add(upgradeDrainTimeout)
+	for quicListener.OpenConnections() > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if n := quicListener.OpenConnections(); n > 0 {
+		log.Printf("Closing %d connections still open after the upgrade", n)
+	}
+	grpcServer.Stop()
+}
+
+// grpcServerOptions builds the gRPC server tuning from the config. Pings
+// without active calls are allowed, agents send them between sessions.
+func grpcServerOptions(cfg config.GRPCConfig) []grpc.ServerOption {
+	seconds := func(n int) time.Duration { return time.Duration(n) * time.Second }
+
+	params := keepalive.ServerParameters{
+		Time:    seconds(cfg.KeepaliveTime),
+		Timeout: seconds(cfg.KeepaliveTimeout),
+	}
+	if cfg.MaxConnectionIdle > 0 {
+		params.MaxConnectionIdle = seconds(cfg.MaxConnectionIdle)
+	}
+	if cfg.MaxConnectionAge > 0 {
+		params.MaxConnectionAge = seconds(cfg.MaxConnectionAge)
+		if cfg.MaxConnectionAgeGrace > 0 {
+			params.MaxConnectionAgeGrace = seconds(cfg.MaxConnectionAgeGrace)
+		}
+	}
+
+	return []grpc.ServerOption{
+		grpc.MaxConcurrentStreams(cfg.MaxConcurrentStreams),
+		grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.MaxSendMsgSize),
+		grpc.KeepaliveParams(params),
+		// Error statuses carry a stable code
+		grpc.ChainUnaryInterceptor(server.UnaryErrorCodes),
+		grpc.ChainStreamInterceptor(server.StreamErrorCodes),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             seconds(cfg.KeepaliveMinTime),
+			PermitWithoutStream: true,
+		}),
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"github.com/taills/EasyAnyLink/common/support"
+)
+
+// runSupportBundle implements "server support-bundle". It writes a tarball
+// with the sanitized configuration, recent logs, interfaces, routes and
+// version for an issue report. Secrets are redacted.
+func runSupportBundle(configFile string, args []string) int {
+	fs := flag.NewFlagSet("support-bundle", flag.ExitOnError)
+	stamp := time.Now().Format("20060102-150405")
+	out := fs.String("out", "easyanylink-server-support-"+stamp+".tar.gz", "File to write the bundle to")
+	logFile := fs.String("log", "", "Log file to include, default log.file of the configuration")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: easyanylink-server [-config file] support-bundle [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	// The configuration may be invalid, that is often why a bundle is made
+	if *logFile == "" {
+		if cfg, err := config.LoadServerConfig(configFile); err == nil {
+			*logFile = cfg.Log.File
+		}
+	}
+
+	bundle, err := support.Create(*out, "easyanylink-server-support-"+stamp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := collectServerBundle(bundle, configFile, *logFile); err != nil {
+		bundle.Close()
+		os.Remove(*out)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := bundle.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Support bundle written to %s\n", *out)
+	fmt.Printf("Secrets are redacted, review the contents before sharing it\n")
+	return 0
+}
+
+// collectServerBundle adds the server's files to a support bundle, the
+// configuration first so its secrets are scrubbed from everything else
+func collectServerBundle(bundle *support.Bundle, configFile, logFile string) error {
+	if err := bundle.AddConfig("config.json", configFile); err != nil {
+		return err
+	}
+	info := support.VersionInfo("server", Version, GitCommit, BuildTime, crypto.PolicySummary())
+	if err := bundle.AddText("version.txt", info); err != nil {
+		return err
+	}
+	if err := bundle.AddSystemState(); err != nil {
+		return err
+	}
+	return bundle.AddLogs(logFile, "easyanylink-server")
+}
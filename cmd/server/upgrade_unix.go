@@ -0,0 +1,115 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"github.com/taills/EasyAnyLink/server"
+)
+
+const (
+	// upgradeReadyEnv names the descriptor a server started for a binary
+	// upgrade reports on that it accepts connections
+	upgradeReadyEnv = "EASYANYLINK_UPGRADE_READY"
+
+	// upgradeStartTimeout is how long the new process may take to start
+	upgradeStartTimeout = time.Minute
+)
+
+// notifyUpgrade relays SIGUSR2, which starts a binary upgrade
+func notifyUpgrade(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR2)
+}
+
+// startUpgrade starts the server binary, replaced on disk by the new
+// version, with the listening sockets of this process and waits until it
+// accepts connections. A process that fails to start is killed.
+func startUpgrade(quicListener *crypto.QUICListener, gatewayListener net.Listener) (*os.Process, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the server binary: %w", err)
+	}
+
+	udp, err := quicListener.File()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pass the QUIC socket: %w", err)
+	}
+	defer udp.Close()
+	files := []*os.File{udp}
+
+	if gatewayListener != nil {
+		tcp, ok := gatewayListener.(interface{ File() (*os.File, error) })
+		if !ok {
+			return nil, fmt.Errorf("cannot pass the REST gateway socket")
+		}
+		file, err := tcp.File()
+		if err != nil {
+			return nil, fmt.Errorf("failed to pass the REST gateway socket: %w", err)
+		}
+		defer file.Close()
+		files = append(files, file)
+	}
+
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer ready.Close()
+
+	// The new process must not take the sockets systemd passed this one a
+	// second time, and pings the watchdog once it is the main process
+	env := []string{
+		fmt.Sprintf("%s=%d", server.UpgradeFDsEnv, len(files)),
+		fmt.Sprintf("%s=%d", upgradeReadyEnv, 3+len(files)),
+	}
+	for _, kv := range os.Environ() {
+		switch name, _, _ := strings.Cut(kv, "="); name {
+		case "LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES", "WATCHDOG_PID", server.UpgradeFDsEnv, upgradeReadyEnv:
+			continue
+		}
+		env = append(env, kv)
+	}
+
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Env = env
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	cmd.ExtraFiles = append(files, readyWriter)
+	err = cmd.Start()
+	readyWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", path, err)
+	}
+
+	// The pipe ends without a byte when the new process exits
+	ready.SetReadDeadline(time.Now().Add(upgradeStartTimeout))
+	if _, err := ready.Read(make([]byte, 1)); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, fmt.Errorf("new server process did not become ready: %w", err)
+	}
+	return cmd.Process, nil
+}
+
+// upgradeReady tells the old process of a binary upgrade that this one
+// accepts connections, nothing without an upgrade
+func upgradeReady() {
+	fd, err := strconv.Atoi(os.Getenv(upgradeReadyEnv))
+	if err != nil {
+		return
+	}
+	os.Unsetenv(upgradeReadyEnv)
+
+	file := os.NewFile(uintptr(fd), "upgrade-ready")
+	file.Write([]byte{1})
+	file.Close()
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"os"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+)
+
+// notifyUpgrade does nothing, binary upgrades are not supported on Windows
+func notifyUpgrade(c chan<- os.Signal) {}
+
+// startUpgrade is not supported on Windows
+func startUpgrade(quicListener *crypto.QUICListener, gatewayListener net.Listener) (*os.Process, error) {
+	return nil, errors.New("binary upgrades are not supported on Windows")
+}
+
+// upgradeReady does nothing on Windows
+func upgradeReady() {}
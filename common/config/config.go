@@ -3,8 +3,17 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
 	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
 )
 
 // ServerConfig represents the server configuration
@@ -14,8 +23,102 @@ type ServerConfig struct {
 	Log      LogConfig      `json:"log"`
 	CertFile string         `json:"cert_file"` // Server TLS certificate (e.g., Let's Encrypt)
 	KeyFile  string         `json:"key_file"`  // Server TLS private key
+	CAFile   string         `json:"ca_file"`   // Trust bundle served to new agents, e.g. from "server gen-ca"
 	Network  NetworkConfig  `json:"network"`
 	Security SecurityConfig `json:"security"`
+	Debug    DebugConfig    `json:"debug"`
+	Billing  BillingConfig  `json:"billing"`
+	Webhooks []Webhook      `json:"webhooks"`
+	Alerts   AlertsConfig   `json:"alerts"`
+	Gateway  GatewayConfig  `json:"gateway"`
+	GRPC     GRPCConfig     `json:"grpc"`
+	HA       HAConfig       `json:"ha"`
+	Masque   MasqueConfig   `json:"masque"`
+}
+
+// GRPCConfig represents gRPC server tuning. Durations are in seconds, a
+// zero connection idle or age limit disables it.
+type GRPCConfig struct {
+	MaxConcurrentStreams  uint32 `json:"max_concurrent_streams"`   // streams per connection, default 10000
+	MaxRecvMsgSize        int    `json:"max_recv_msg_size"`        // bytes, default 4 MiB
+	MaxSendMsgSize        int    `json:"max_send_msg_size"`        // bytes, default 16 MiB
+	KeepaliveTime         int    `json:"keepalive_time"`           // idle time before the server pings a client, default 120
+	KeepaliveTimeout      int    `json:"keepalive_timeout"`        // time to wait for a ping ack before closing, default 20
+	KeepaliveMinTime      int    `json:"keepalive_min_time"`       // shortest client ping interval tolerated, default 20
+	MaxConnectionIdle     int    `json:"max_connection_idle"`      // close connections without calls for this long
+	MaxConnectionAge      int    `json:"max_connection_age"`       // ask clients to reconnect after this long
+	MaxConnectionAgeGrace int    `json:"max_connection_age_grace"` // time calls get to finish after max_connection_age
+}
+
+// agentPingInterval is how often agents send gRPC keepalive pings, in
+// seconds. Servers enforcing a longer minimum would drop every agent.
+const agentPingInterval = 30
+
+// GatewayConfig represents the REST gateway translating HTTP/JSON to the
+// gRPC API, served over TLS with the server certificate
+type GatewayConfig struct {
+	Listen         string   `json:"listen"`          // TCP address, e.g. ":8443", empty disables the gateway
+	AllowedOrigins []string `json:"allowed_origins"` // browser origins allowed by CORS, "*" for any
+}
+
+// DefaultMasquePath is the path of CONNECT-UDP requests, the well-known
+// location of RFC 9298
+const DefaultMasquePath = "/.well-known/masque/udp/"
+
+// MasqueConfig represents the HTTP/3 endpoint carrying the tunnel inside
+// CONNECT-UDP requests (MASQUE) for agents whose network blocks or
+// fingerprints bare QUIC. Other requests get the decoy site, so that the
+// server looks like an HTTP/3 web server.
+type MasqueConfig struct {
+	Listen  string `json:"listen"`   // UDP address, e.g. ":443", empty disables MASQUE
+	Path    string `json:"path"`     // CONNECT-UDP path, default "/.well-known/masque/udp/"
+	SiteDir string `json:"site_dir"` // static files served to other requests, 404 for all if empty
+}
+
+// HAConfig pairs two servers as active and standby behind a virtual IP.
+// The active one replicates its sessions and IP allocations to the
+// standby, which takes the virtual IP over when the active one fails.
+type HAConfig struct {
+	Listen    string `json:"listen"`     // TCP address the peer replicates to, e.g. ":8229"; empty disables HA
+	Peer      string `json:"peer"`       // HA address of the other server
+	Secret    string `json:"secret"`     // shared secret the servers authenticate each other with
+	Priority  int    `json:"priority"`   // the higher one becomes active when both start or both are active, 1-254
+	Mode      string `json:"mode"`       // "arp" to move the virtual IP, "keepalived" to follow keepalived moving it; default arp
+	VirtualIP string `json:"virtual_ip"` // address with prefix length, e.g. "203.0.113.10/24"
+	Interface string `json:"interface"`  // interface the virtual IP is added to in arp mode
+	Interval  int    `json:"interval"`   // seconds between adverts carrying the replicated state, default 1
+	DeadTime  int    `json:"dead_time"`  // seconds without adverts before the standby takes over, default 3
+}
+
+// AlertsConfig represents built-in operational alert rules and where
+// alerts are sent. A rule with a zero threshold is disabled.
+type AlertsConfig struct {
+	Interval            int        `json:"interval"`              // seconds between rule evaluations, default 60
+	AgentOfflineMinutes int        `json:"agent_offline_minutes"` // alert when an agent stays offline longer
+	RelayErrorRate      float64    `json:"relay_error_rate"`      // alert when this fraction of relayed packets fail, e.g. 0.05
+	DBLatencyMs         int        `json:"db_latency_ms"`         // alert when a database ping takes longer
+	RouteLoops          bool       `json:"route_loops"`           // alert while routing rules are quarantined for forwarding loops
+	ConfigDrift         bool       `json:"config_drift"`          // alert while agents fail to apply pushed routes and group settings
+	ClockSkew           bool       `json:"clock_skew"`            // alert while agent clocks are off by more than security.clock_skew_warning
+	SlackWebhook        string     `json:"slack_webhook"`         // Slack-compatible incoming webhook URL
+	SMTP                SMTPConfig `json:"smtp"`
+}
+
+// SMTPConfig represents the mail server used for alert emails
+type SMTPConfig struct {
+	Host     string   `json:"host"` // empty disables email alerts
+	Port     int      `json:"port"` // default 587
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// Webhook represents an outbound event subscription
+type Webhook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"` // HMAC-SHA256 key signing request bodies, optional
+	Events []string `json:"events"` // Event types to deliver, empty for all
 }
 
 // DatabaseConfig represents database connection settings
@@ -30,13 +133,126 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `json:"max_open_conns"`
 	MaxIdleConns    int           `json:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `json:"conn_max_lifetime"`
+	Replicas        []DBReplica   `json:"replicas"`              // read replicas for read-heavy queries
+	HealthCheck     int           `json:"health_check_interval"` // seconds between replica health checks, default 10
+	CacheTTL        int           `json:"cache_ttl"`             // seconds users, agents and rules are cached, default 30, -1 disables
+	HistoryDays     int           `json:"history_days"`          // days session history and archived agents are kept, default 90, -1 keeps forever
+	HourlyStatsDays int           `json:"hourly_stats_days"`     // days hourly session rollups are kept, default 30, -1 keeps forever
+	DailyStatsDays  int           `json:"daily_stats_days"`      // days daily session rollups are kept, default 730, -1 keeps forever
+}
+
+// DBReplica represents a read replica of the database. User and password
+// default to those of the primary.
+type DBReplica struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	User     string `json:"user"`
+	Password string `json:"password"`
 }
 
 // LogConfig represents logging configuration
 type LogConfig struct {
-	Level  string `json:"level"`
-	File   string `json:"file"`
-	Format string `json:"format"` // json or text
+	Level  string       `json:"level"`
+	File   string       `json:"file"`
+	Format string       `json:"format"` // json or text
+	Output string       `json:"output"` // "file" (default with file set), "stderr", "syslog" or "journald"
+	Rotate LogRotation  `json:"rotate"`
+	Syslog SyslogConfig `json:"syslog"`
+	Redact RedactConfig `json:"redact"`
+}
+
+// LogRotation represents when the log file is rotated and how long
+// rotated files are kept
+type LogRotation struct {
+	MaxSizeMB     int  `json:"max_size_mb"`    // rotate when the file grows past this, default 10
+	IntervalHours int  `json:"interval_hours"` // also rotate a file this old, 0 rotates by size only
+	MaxFiles      int  `json:"max_files"`      // rotated files kept, default 5
+	MaxAgeDays    int  `json:"max_age_days"`   // delete rotated files older than this, 0 keeps them
+	Compress      bool `json:"compress"`       // gzip rotated files
+}
+
+// setDefaults picks the output and rotation of the logs
+func (l *LogConfig) setDefaults() {
+	if l.Output == "" {
+		l.Output = "stderr"
+		if l.File != "" {
+			l.Output = "file"
+		}
+	}
+	if l.Rotate.MaxSizeMB == 0 {
+		l.Rotate.MaxSizeMB = 10
+	}
+	if l.Rotate.MaxFiles == 0 {
+		l.Rotate.MaxFiles = 5
+	}
+}
+
+// SyslogConfig represents a remote syslog collector logs are sent to in
+// RFC 5424 format
+type SyslogConfig struct {
+	Network  string `json:"network"`  // "udp" (default) or "tcp"
+	Address  string `json:"address"`  // host:port of the collector, e.g. "logs.example.com:514"
+	Facility string `json:"facility"` // default "daemon"
+	Tag      string `json:"tag"`      // APP-NAME, default the name of the binary
+}
+
+// SyslogFacilities are the facilities a syslog collector may be sent
+var SyslogFacilities = map[string]int{
+	"user": 1, "daemon": 3, "auth": 4, "authpriv": 10,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// validate checks the output and redaction policy of the logs
+func (l *LogConfig) validate() error {
+	switch l.Output {
+	case "", "stderr", "journald":
+	case "file":
+		if l.File == "" {
+			return fmt.Errorf("log.file is required with log.output 'file'")
+		}
+		if l.Rotate.MaxSizeMB < 0 || l.Rotate.IntervalHours < 0 || l.Rotate.MaxFiles < 0 || l.Rotate.MaxAgeDays < 0 {
+			return fmt.Errorf("log.rotate settings must not be negative")
+		}
+	case "syslog":
+		switch l.Syslog.Network {
+		case "", "udp", "tcp":
+		default:
+			return fmt.Errorf("log.syslog.network must be 'udp' or 'tcp'")
+		}
+		if _, _, err := net.SplitHostPort(l.Syslog.Address); err != nil {
+			return fmt.Errorf("invalid log.syslog.address %q: %w", l.Syslog.Address, err)
+		}
+		if _, ok := SyslogFacilities[l.Syslog.Facility]; !ok && l.Syslog.Facility != "" {
+			return fmt.Errorf("unknown log.syslog.facility %q", l.Syslog.Facility)
+		}
+	default:
+		return fmt.Errorf("log.output must be 'file', 'stderr', 'syslog' or 'journald'")
+	}
+	return l.Redact.validate()
+}
+
+// RedactConfig selects what logs mask. API keys, passwords, tokens and
+// session IDs are masked unless show_secrets is set.
+type RedactConfig struct {
+	ShowSecrets bool     `json:"show_secrets"` // log secrets in full, for debugging only
+	Addresses   string   `json:"addresses"`    // "" keeps IP addresses, "public" masks public ones, "all" masks all
+	Patterns    []string `json:"patterns"`     // regular expressions of further values to mask
+}
+
+// validate checks the redaction policy
+func (r *RedactConfig) validate() error {
+	switch r.Addresses {
+	case "", "public", "all":
+	default:
+		return fmt.Errorf("log.redact.addresses must be 'public' or 'all'")
+	}
+	for _, pattern := range r.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid log.redact pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
 }
 
 // TLSConfig represents TLS/mTLS configuration (kept for backward compatibility)
@@ -51,27 +267,284 @@ type TLSConfig struct {
 type NetworkConfig struct {
 	OverlayCIDR       string `json:"overlay_cidr"`       // e.g., "10.200.0.0/16"
 	GatewayIP         string `json:"gateway_ip"`         // e.g., "10.200.0.1"
-	MTU               int    `json:"mtu"`                // default 1400
+	MTU               int    `json:"mtu"`                // granted to agents not asking for one, default 1400
+	MaxMTU            int    `json:"max_mtu"`            // largest MTU granted on request, e.g. 9000 for jumbo frames, default mtu
 	KeepaliveInterval int    `json:"keepalive_interval"` // seconds
-	KeepaliveTimeout  int    `json:"keepalive_timeout"`  // seconds
+	KeepaliveTimeout  int    `json:"keepalive_timeout"`  // seconds without a heartbeat response before agents reconnect
+	RelayWorkers      int    `json:"relay_workers"`      // goroutines routing relayed packets, default GOMAXPROCS
+	RelayQueueLen     int    `json:"relay_queue_len"`    // packets queued per traffic class of a relay worker, default 1024
+
+	// Agents that stop reading their relay streams block the relay workers
+	// sending to them
+	SlowConsumerStall   int `json:"slow_consumer_stall"`   // milliseconds a send may block before the agent counts as a slow consumer, default 1000
+	SlowConsumerTimeout int `json:"slow_consumer_timeout"` // seconds a send may block before the session is ended, 0 never ends it
+
+	// Packets past the ceiling are dropped rather than buffered
+	MaxSessionMemoryKB int `json:"max_session_memory_kb"` // kilobytes of packets a session may hold queued or pending, 0 for unlimited
+
+	// Registrations over max_sessions are turned away instead of degrading
+	// every connected agent
+	MaxSessions      int      `json:"max_sessions"`      // concurrent sessions, 0 for unlimited
+	BusyRetryAfter   int      `json:"busy_retry_after"`  // seconds turned away agents wait before retrying, default 30
+	AlternateServers []string `json:"alternate_servers"` // servers offered to turned away agents and announced to those selecting by latency, host:port
+
+	// Broadcast and multicast packets agents send into the overlay, e.g.
+	// mDNS and SSDP discovery
+	Multicast      string `json:"multicast"`       // "drop" (default), "relay" to the user's other agents, or "unicast" copies of discovery packets
+	MulticastRate  int    `json:"multicast_rate"`  // packets per second a session may relay, default 10
+	MulticastBurst int    `json:"multicast_burst"` // packets a session may relay at once, default the rate
+
+	// Packets a gateway sends back into the relay, e.g. through a route for
+	// the overlay CIDR that points into its TUN, quarantine the forward rule
+	LoopThreshold int `json:"loop_threshold"` // looped packets from a gateway within 2 seconds, default 8
+
+	// Forward rules of an agent for the same prefix and priority through
+	// different gateways spread flows across those connected, by a hash of
+	// addresses and ports, instead of all taking the first
+	ECMP bool `json:"ecmp"`
 }
 
 // SecurityConfig represents security-related settings
 type SecurityConfig struct {
 	SessionTimeout int `json:"session_timeout"` // minutes
 	MaxFailedAuth  int `json:"max_failed_auth"` // max failed auth attempts
+
+	RegistrationsPerSecond float64 `json:"registrations_per_second"` // server-wide registration rate, 0 for unlimited
+	RegistrationBurst      int     `json:"registration_burst"`       // registrations allowed at once, default the rate
+	MaxConnectionsPerIP    int     `json:"max_connections_per_ip"`   // concurrent QUIC connections per source IP, 0 for unlimited
+	MaxAgentsPerUser       int     `json:"max_agents_per_user"`      // registered agents per user, 0 for unlimited
+	RetryThreshold         int     `json:"retry_threshold"`          // QUIC handshakes per second before Retry is required, 0 disables
+	CertExpiryDays         int     `json:"cert_expiry_days"`         // days before certificate expiry to notify, default 30
+	RequireApproval        bool    `json:"require_approval"`         // hold newly registered agents until an admin approves them
+	PSK                    string  `json:"psk"`                      // pre-shared key agents must sign registrations with, empty disables
+	CryptoPolicy           string  `json:"crypto_policy"`            // "default" or "fips", empty for the build default
+	RekeyInterval          int     `json:"rekey_interval"`           // minutes after which agents move their session to a connection with fresh keys, 0 disables
+	RekeyBytes             uint64  `json:"rekey_bytes"`              // bytes relayed after which agents do the same, 0 disables
+	MaxClockSkew           int     `json:"max_clock_skew"`           // seconds a signed registration's timestamp may be off the server clock, default 300
+	ClockSkewWarning       int     `json:"clock_skew_warning"`       // seconds an agent clock may be off before operators are warned, default 30
+	RequireIdentity        bool    `json:"require_identity"`         // refuse agents that do not sign registrations with a hardware identity key
+	MaxAccessDuration      int     `json:"max_access_duration"`      // minutes of temporary access an agent may request, default 480
+
+	PosturePolicies []PosturePolicy `json:"posture_policies"` // device postures routing and ACL rules may require
+}
+
+// PosturePolicy is a device posture agents must report for the routing and
+// ACL rules naming the policy to apply to them. Agents failing it do not
+// get those routes, and those allow rules do not match their traffic.
+type PosturePolicy struct {
+	Name                  string            `json:"name"`
+	MinOSVersion          map[string]string `json:"min_os_version"`          // lowest OS release by OS (linux, darwin, windows), the kernel release on Linux
+	RequireDiskEncryption bool              `json:"require_disk_encryption"` // the system disk must be encrypted
+	RequireFirewall       bool              `json:"require_firewall"`        // a host firewall must filter inbound traffic
+}
+
+// BillingConfig represents usage notifications for paid deployments
+type BillingConfig struct {
+	WebhookURL        string   `json:"webhook_url"`        // POSTed when a user crosses a threshold, empty disables
+	WebhookSecret     string   `json:"webhook_secret"`     // HMAC-SHA256 key signing webhook bodies, optional
+	ThresholdPercents []int    `json:"threshold_percents"` // Percentages of the monthly transfer cap, e.g. [80, 100]
+	ThresholdBytes    []uint64 `json:"threshold_bytes"`    // Monthly transfer thresholds in bytes, for any user
+}
+
+// DebugConfig represents server debugging facilities
+type DebugConfig struct {
+	TraceSampleRate uint32 `json:"trace_sample_rate"` // Trace 1 in N relayed packets, 0 disables
+	TraceBufferSize int    `json:"trace_buffer_size"` // Number of relay traces kept, default 1000
+	TransportLog
+}
+
+// TransportLog enables transport debugging output, shared by the server and
+// agent "debug" sections. Both are off by default.
+type TransportLog struct {
+	KeyLogFile string `json:"key_log_file"` // Write TLS secrets here for Wireshark, default $SSLKEYLOGFILE
+	QlogDir    string `json:"qlog_dir"`     // Write a qlog file per QUIC connection here, default $QLOGDIR
 }
 
 // AgentConfig represents the agent configuration
 type AgentConfig struct {
 	Mode               string        `json:"mode"` // "client" or "gateway"
 	Server             string        `json:"server"`
+	Servers            []string      `json:"servers"`          // Fallback servers, tried in order when the server is unreachable
+	ServerSelection    string        `json:"server_selection"` // "order" (default) prefers server, "latency" the fastest handshake among servers and those the server announces
 	UserKey            string        `json:"user_key"`
 	AgentID            string        `json:"id"`
 	Bandwidth          int           `json:"bandwidth"`            // KB/s, 0 for unlimited
 	InsecureSkipVerify bool          `json:"insecure_skip_verify"` // Skip TLS certificate verification (for debugging only)
+	CAFile             string        `json:"ca_file"`              // Trust bundle of a private CA, in addition to the system roots
+	TCPPort            int           `json:"tcp_port"`             // Server REST gateway port, gRPC over TLS on it is the fallback when QUIC fails; 0 disables it
+	Proxy              string        `json:"proxy"`                // TCP fallback through "http://", "https://" or "socks5://[user:pass@]host:port"; empty honors HTTPS_PROXY and NO_PROXY, "direct" ignores them
+	UDPRelay           string        `json:"udp_relay"`            // QUIC through the UDP ASSOCIATE relay of "socks5://[user:pass@]host:port", empty to send it directly
+	MasquePort         int           `json:"masque_port"`          // Server MASQUE endpoint port, QUIC goes inside HTTP/3 CONNECT-UDP to it; 0 sends bare QUIC
+	MasquePath         string        `json:"masque_path"`          // CONNECT-UDP path of the MASQUE endpoint, default "/.well-known/masque/udp/"
+	Obfuscate          bool          `json:"obfuscate"`            // Pad relayed packets to bucketized sizes and randomize heartbeat timing against traffic analysis, if the server supports it
+	ScopedDefaultRoute bool          `json:"scoped_default_route"` // macOS: add an interface-scoped default route via the overlay
+	KillSwitch         bool          `json:"kill_switch"`          // Block forwarded destinations outside the tunnel (all traffic with a 0.0.0.0/0 rule)
+	AllowLAN           bool          `json:"allow_lan"`            // Keep local subnets reachable outside the tunnel
+	CaptivePortal      bool          `json:"captive_portal"`       // Pause the kill switch while behind a captive portal
+	AppRouting         *AppRouting   `json:"app_routing"`          // Linux: only route selected processes through the tunnel
+	SourceUIDs         bool          `json:"source_uids"`          // Linux: tag relayed flows with the local user owning their socket, for ACLs and per-user stats on shared hosts
+	RouteTable         int           `json:"route_table"`          // Linux: install routes into this table instead of main, 0 for main
+	RouteConflicts     string        `json:"route_conflicts"`      // Overlap with existing routes: "refuse", "skip" (default) or "override"
+	ICMPResponder      bool          `json:"icmp_responder"`       // Answer pings to the overlay IP in-process
+	ControlSocket      string        `json:"control_socket"`       // Local control API socket, empty for the platform default
+	WebUI              string        `json:"web_ui"`               // Loopback address of the local web UI, e.g. 127.0.0.1:8780, empty disables
+	Tray               *TrayConfig   `json:"tray"`                 // JSON-RPC API for a tray app, nil disables
+	StatsFile          string        `json:"stats_file"`           // Per-minute traffic history kept across restarts, empty for the platform default
+	User               string        `json:"user"`                 // Linux: run the agent as this user, a root helper creates the TUN and changes routes
+	DropPrivileges     bool          `json:"drop_privileges"`      // Linux: switch to user after setup keeping CAP_NET_ADMIN, instead of a root helper
+	Sandbox            string        `json:"sandbox"`              // Linux: "enforce" confines the agent with seccomp and landlock after setup, "audit" only logs calls it would deny
+	PSK                string        `json:"psk"`                  // Pre-shared key signing registrations, must match the server's
+	HardwareIdentity   bool          `json:"hardware_identity"`    // Sign registrations with a key kept in the TPM (Linux, Windows) or Secure Enclave (macOS), the server binds the agent to it
+	CryptoPolicy       string        `json:"crypto_policy"`        // "default" or "fips", empty for the build default
+	DNS                *DNSConfig    `json:"dns"`                  // Client mode: resolvers used while connected, nil keeps the system DNS
+	TUN                TUNConfig     `json:"tun"`
 	Log                LogConfig     `json:"log"`
+	Debug              TransportLog  `json:"debug"`
 	Rules              []RoutingRule `json:"rules,omitempty"` // Only for client mode
+	Sites              []string      `json:"sites,omitempty"` // Gateway mode: LANs routed to the user's other site gateways
+	SiteExport         *RouteFilter  `json:"site_export"`     // Gateway mode: limits the sites advertised, nil advertises all
+	BGP                *BGPConfig    `json:"bgp"`             // Gateway mode, Linux: announce routes to and learn sites from FRR's bgpd, nil disables
+	CloudRoutes        *CloudRoutes  `json:"cloud_routes"`    // Gateway mode: point a VPC route table at the instance, nil disables
+	Profiles           []Profile     `json:"profiles,omitempty"`
+	Profile            string        `json:"profile"` // Active profile, empty for the top-level settings
+}
+
+// Profile is a named set of server settings, e.g. "office" or "home",
+// that replaces the top-level ones while it is active
+type Profile struct {
+	Name    string   `json:"name"`
+	Server  string   `json:"server"`
+	Servers []string `json:"servers"`  // Fallback servers
+	UserKey string   `json:"user_key"` // Empty keeps the top-level key
+}
+
+// ServerList returns the servers to connect to, in order of preference
+func (c *AgentConfig) ServerList() []string {
+	return append([]string{c.Server}, c.Servers...)
+}
+
+// ApplyProfile makes the named profile active, replacing the server
+// settings. The top-level settings are kept as the profile "default".
+func (c *AgentConfig) ApplyProfile(name string) error {
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	var profile, defaults *Profile
+	for i := range c.Profiles {
+		switch c.Profiles[i].Name {
+		case name:
+			profile = &c.Profiles[i]
+		case DefaultProfile:
+			defaults = &c.Profiles[i]
+		}
+	}
+	if profile == nil {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+
+	c.Server = profile.Server
+	c.Servers = profile.Servers
+	c.UserKey = profile.UserKey
+	if c.UserKey == "" && defaults != nil {
+		c.UserKey = defaults.UserKey
+	}
+	c.Profile = name
+	return nil
+}
+
+// DefaultProfile names the top-level server settings of an agent config
+const DefaultProfile = "default"
+
+// TUNConfig holds TUN interface settings of the agent
+type TUNConfig struct {
+	Name       string `json:"name"`       // Interface name, empty for the platform default
+	Family     string `json:"family"`     // "ipv4" (default) disables IPv6 on the interface, "dual" keeps it
+	MTU        int    `json:"mtu"`        // 0 uses the server-provided MTU, which also caps it
+	TxQueueLen int    `json:"txqueuelen"` // Linux: transmit queue length, 0 keeps the kernel default
+	MultiQueue bool   `json:"multiqueue"` // Linux: open the device with IFF_MULTI_QUEUE
+	Queues     int    `json:"queues"`     // Linux: number of queues with multiqueue, one relay stream each
+}
+
+// DNSConfig selects the resolvers used while the tunnel is up. The original
+// system configuration is restored on disconnect, or on the next start
+// after a crash.
+type DNSConfig struct {
+	Servers []string `json:"servers"` // Resolver IPs, usually routed through the tunnel
+	Search  []string `json:"search"`  // Search domains, optional
+}
+
+// TrayConfig enables the JSON-RPC API a tray app of the desktop user
+// controls the agent with. Clients authenticate with the token the agent
+// writes to TokenFile on start.
+type TrayConfig struct {
+	Listen    string `json:"listen"`     // Loopback address, e.g. 127.0.0.1:8781
+	TokenFile string `json:"token_file"` // Written with a fresh token on start, readable by its owner only
+	User      string `json:"user"`       // Unix: owner of the token file, the user running the tray app
+}
+
+// RouteFilter limits the sites a gateway advertises to the site mesh. A
+// site is advertised if an Allow prefix contains it, or Allow is empty,
+// and it overlaps no Deny prefix.
+type RouteFilter struct {
+	Allow []string `json:"allow,omitempty"` // IPv4 CIDRs sites must lie within, empty for any
+	Deny  []string `json:"deny,omitempty"`  // IPv4 CIDRs never advertised, e.g. 192.168.0.0/16
+}
+
+// Exports reports whether a site passes the filter
+func (f *RouteFilter) Exports(site string) bool {
+	_, prefix, err := net.ParseCIDR(site)
+	if err != nil {
+		return false
+	}
+	ones, _ := prefix.Mask.Size()
+
+	allowed := len(f.Allow) == 0
+	for _, cidr := range f.Allow {
+		_, allow, _ := net.ParseCIDR(cidr)
+		if allowOnes, _ := allow.Mask.Size(); allow.Contains(prefix.IP) && allowOnes <= ones {
+			allowed = true
+			break
+		}
+	}
+	for _, cidr := range f.Deny {
+		_, deny, _ := net.ParseCIDR(cidr)
+		if deny.Contains(prefix.IP) || prefix.Contains(deny.IP) {
+			return false
+		}
+	}
+	return allowed
+}
+
+// BGPConfig has a gateway drive the bgpd of FRR on its host through
+// vtysh. The overlay and the sites of the other gateways are announced to
+// the LAN routers as networks, and LAN prefixes learned from them become
+// sites of the gateway. bgpd must run with the router bgp of ASN and its
+// neighbors configured.
+type BGPConfig struct {
+	ASN      uint32   `json:"asn"`             // AS of the router bgp the networks are added to
+	Announce bool     `json:"announce"`        // Announce the overlay and the sites of the other gateways
+	Learn    []string `json:"learn,omitempty"` // IPv4 CIDRs learned prefixes must lie within to become sites, empty learns none
+	Interval int      `json:"interval"`        // Seconds between syncs with bgpd, default 30
+}
+
+// CloudRoutes has a gateway in a cloud VPC program a route table of the
+// VPC, pointing the overlay and the sites of the other gateways at its
+// instance. Credentials come from the instance metadata service: the
+// instance needs a role, service account or managed identity allowed to
+// change routes, and IP forwarding enabled on its network interface.
+type CloudRoutes struct {
+	Provider   string `json:"provider"`    // "aws", "gcp" or "azure"
+	RouteTable string `json:"route_table"` // AWS: route table ID, GCP: VPC network name, Azure: route table resource ID
+	Interval   int    `json:"interval"`    // Seconds between syncs, default 60
+}
+
+// AppRouting selects the processes whose traffic uses the tunnel (Linux only)
+type AppRouting struct {
+	UIDs    []int    `json:"uids,omitempty"`    // Match by owner uid
+	Cgroups []string `json:"cgroups,omitempty"` // Match by cgroup v2 path, e.g. "user.slice/corp.scope"
+	Mark    uint32   `json:"mark"`              // fwmark for matched traffic
+	Table   int      `json:"table"`             // Policy routing table for forward routes
 }
 
 // RoutingRule represents a routing policy
@@ -98,21 +571,141 @@ func LoadServerConfig(path string) (*ServerConfig, error) {
 	if config.Network.MTU == 0 {
 		config.Network.MTU = 1400
 	}
+	if config.Network.MaxMTU == 0 {
+		config.Network.MaxMTU = config.Network.MTU
+	}
 	if config.Network.KeepaliveInterval == 0 {
 		config.Network.KeepaliveInterval = 30
 	}
 	if config.Network.KeepaliveTimeout == 0 {
 		config.Network.KeepaliveTimeout = 90
 	}
+	if config.Network.RelayWorkers == 0 {
+		config.Network.RelayWorkers = runtime.GOMAXPROCS(0)
+	}
+	if config.Network.RelayQueueLen == 0 {
+		config.Network.RelayQueueLen = 1024
+	}
+	if config.Network.SlowConsumerStall == 0 {
+		config.Network.SlowConsumerStall = 1000
+	}
+	if config.Network.BusyRetryAfter == 0 {
+		config.Network.BusyRetryAfter = 30
+	}
+	if config.Network.Multicast == "" {
+		config.Network.Multicast = "drop"
+	}
+	if config.Network.MulticastRate == 0 {
+		config.Network.MulticastRate = 10
+	}
+	if config.Network.LoopThreshold == 0 {
+		config.Network.LoopThreshold = 8
+	}
+	if config.GRPC.MaxConcurrentStreams == 0 {
+		config.GRPC.MaxConcurrentStreams = 10000
+	}
+	if config.GRPC.MaxRecvMsgSize == 0 {
+		config.GRPC.MaxRecvMsgSize = 4 << 20
+	}
+	if config.GRPC.MaxSendMsgSize == 0 {
+		config.GRPC.MaxSendMsgSize = 16 << 20
+	}
+	if config.GRPC.KeepaliveTime == 0 {
+		config.GRPC.KeepaliveTime = 120
+	}
+	if config.GRPC.KeepaliveTimeout == 0 {
+		config.GRPC.KeepaliveTimeout = 20
+	}
+	if config.GRPC.KeepaliveMinTime == 0 {
+		config.GRPC.KeepaliveMinTime = 20
+	}
 	if config.Security.SessionTimeout == 0 {
 		config.Security.SessionTimeout = 1440 // 24 hours
 	}
 	if config.Security.MaxFailedAuth == 0 {
 		config.Security.MaxFailedAuth = 5
 	}
+	if config.Security.CertExpiryDays == 0 {
+		config.Security.CertExpiryDays = 30
+	}
+	if config.Security.MaxClockSkew == 0 {
+		config.Security.MaxClockSkew = 300
+	}
+	if config.Security.ClockSkewWarning == 0 {
+		config.Security.ClockSkewWarning = 30
+	}
+	if config.Security.MaxAccessDuration == 0 {
+		config.Security.MaxAccessDuration = 480 // 8 hours
+	}
+	if config.Database.HistoryDays == 0 {
+		config.Database.HistoryDays = 90
+	}
+	if config.Database.HourlyStatsDays == 0 {
+		config.Database.HourlyStatsDays = 30
+	}
+	if config.Database.DailyStatsDays == 0 {
+		config.Database.DailyStatsDays = 730
+	}
+	if config.Database.CacheTTL == 0 {
+		config.Database.CacheTTL = 30
+	}
+	if config.Database.HealthCheck == 0 {
+		config.Database.HealthCheck = 10
+	}
 	if config.Log.Format == "" {
 		config.Log.Format = "json"
 	}
+	config.Log.setDefaults()
+	if config.Alerts.Interval == 0 {
+		config.Alerts.Interval = 60
+	}
+	if config.Alerts.SMTP.Port == 0 {
+		config.Alerts.SMTP.Port = 587
+	}
+	if config.HA.Mode == "" {
+		config.HA.Mode = "arp"
+	}
+	if config.HA.Interval == 0 {
+		config.HA.Interval = 1
+	}
+	if config.HA.DeadTime == 0 {
+		config.HA.DeadTime = 3 * config.HA.Interval
+	}
+	if config.Masque.Path == "" {
+		config.Masque.Path = DefaultMasquePath
+	}
+	if config.Database.HealthCheck < 0 {
+		return nil, fmt.Errorf("invalid database.health_check_interval: must be positive")
+	}
+	if config.Alerts.Interval < 0 {
+		return nil, fmt.Errorf("invalid alerts.interval: must be positive")
+	}
+	if config.Alerts.RelayErrorRate < 0 || config.Alerts.RelayErrorRate > 1 {
+		return nil, fmt.Errorf("invalid alerts.relay_error_rate: must be between 0 and 1")
+	}
+	if config.Alerts.SMTP.Host != "" && (config.Alerts.SMTP.From == "" || len(config.Alerts.SMTP.To) == 0) {
+		return nil, fmt.Errorf("invalid alerts.smtp: from and to are required")
+	}
+	for _, hook := range config.Webhooks {
+		if hook.URL == "" {
+			return nil, fmt.Errorf("invalid webhook: url is required")
+		}
+	}
+	policies := make(map[string]bool)
+	for _, policy := range config.Security.PosturePolicies {
+		if policy.Name == "" {
+			return nil, fmt.Errorf("invalid posture policy: name is required")
+		}
+		if policies[policy.Name] {
+			return nil, fmt.Errorf("invalid posture policy %s: defined twice", policy.Name)
+		}
+		policies[policy.Name] = true
+	}
+	for _, percent := range config.Billing.ThresholdPercents {
+		if percent <= 0 {
+			return nil, fmt.Errorf("invalid billing threshold %d%%: must be positive", percent)
+		}
+	}
 
 	return &config, nil
 }
@@ -138,6 +731,27 @@ func LoadAgentConfig(path string) (*AgentConfig, error) {
 		return nil, fmt.Errorf("server address is required")
 	}
 
+	// The top-level settings are always available as the default profile
+	config.Profiles = append([]Profile{{
+		Name:    DefaultProfile,
+		Server:  config.Server,
+		Servers: config.Servers,
+		UserKey: config.UserKey,
+	}}, config.Profiles...)
+	seen := make(map[string]bool)
+	for _, profile := range config.Profiles {
+		if profile.Name == "" || seen[profile.Name] {
+			return nil, fmt.Errorf("profiles must have unique, non-empty names")
+		}
+		if profile.Server == "" {
+			return nil, fmt.Errorf("profile %q requires a server address", profile.Name)
+		}
+		seen[profile.Name] = true
+	}
+	if err := config.ApplyProfile(config.Profile); err != nil {
+		return nil, err
+	}
+
 	if config.Mode == "client" && config.UserKey == "" {
 		return nil, fmt.Errorf("user_key is required for client mode")
 	}
@@ -145,6 +759,67 @@ func LoadAgentConfig(path string) (*AgentConfig, error) {
 	if config.Mode == "gateway" && config.AgentID == "" {
 		return nil, fmt.Errorf("id is required for gateway mode")
 	}
+	// The server binds the ID to the key, a new ID every start would be a
+	// new agent every start
+	if config.HardwareIdentity && config.AgentID == "" {
+		return nil, fmt.Errorf("hardware_identity requires id")
+	}
+	if len(config.Sites) > 0 && config.Mode != "gateway" {
+		return nil, fmt.Errorf("sites require gateway mode")
+	}
+	for _, site := range config.Sites {
+		if _, ipNet, err := net.ParseCIDR(site); err != nil || ipNet.IP.To4() == nil {
+			return nil, fmt.Errorf("invalid site %q: must be an IPv4 CIDR", site)
+		}
+	}
+	if config.BGP != nil {
+		if config.Mode != "gateway" {
+			return nil, fmt.Errorf("bgp requires gateway mode")
+		}
+		if config.BGP.ASN == 0 {
+			return nil, fmt.Errorf("bgp.asn is required")
+		}
+		for _, cidr := range config.BGP.Learn {
+			if _, ipNet, err := net.ParseCIDR(cidr); err != nil || ipNet.IP.To4() == nil {
+				return nil, fmt.Errorf("invalid bgp.learn prefix %q: must be an IPv4 CIDR", cidr)
+			}
+		}
+		if config.BGP.Interval < 0 {
+			return nil, fmt.Errorf("invalid bgp.interval: must not be negative")
+		}
+		if config.BGP.Interval == 0 {
+			config.BGP.Interval = 30
+		}
+	}
+	if config.CloudRoutes != nil {
+		if config.Mode != "gateway" {
+			return nil, fmt.Errorf("cloud_routes requires gateway mode")
+		}
+		switch config.CloudRoutes.Provider {
+		case "aws", "gcp", "azure":
+		default:
+			return nil, fmt.Errorf("invalid cloud_routes.provider %q: must be aws, gcp or azure", config.CloudRoutes.Provider)
+		}
+		if config.CloudRoutes.RouteTable == "" {
+			return nil, fmt.Errorf("cloud_routes.route_table is required")
+		}
+		if config.CloudRoutes.Interval < 0 {
+			return nil, fmt.Errorf("invalid cloud_routes.interval: must not be negative")
+		}
+		if config.CloudRoutes.Interval == 0 {
+			config.CloudRoutes.Interval = 60
+		}
+	}
+	if config.SiteExport != nil {
+		if config.Mode != "gateway" {
+			return nil, fmt.Errorf("site_export requires gateway mode")
+		}
+		for _, cidr := range append(slices.Clip(config.SiteExport.Allow), config.SiteExport.Deny...) {
+			if _, ipNet, err := net.ParseCIDR(cidr); err != nil || ipNet.IP.To4() == nil {
+				return nil, fmt.Errorf("invalid site_export prefix %q: must be an IPv4 CIDR", cidr)
+			}
+		}
+	}
 
 	// Set defaults
 	if config.Log.Level == "" {
@@ -153,6 +828,60 @@ func LoadAgentConfig(path string) (*AgentConfig, error) {
 	if config.Log.Format == "" {
 		config.Log.Format = "json"
 	}
+	config.Log.setDefaults()
+	if config.MasquePath == "" {
+		config.MasquePath = DefaultMasquePath
+	}
+	if config.TUN.MTU != 0 && (config.TUN.MTU < 576 || config.TUN.MTU > 65535) {
+		return nil, fmt.Errorf("invalid tun.mtu: must be between 576 and 65535")
+	}
+	if config.TUN.Queues > 1 && !config.TUN.MultiQueue {
+		return nil, fmt.Errorf("tun.queues requires tun.multiqueue")
+	}
+	switch config.TUN.Family {
+	case "":
+		config.TUN.Family = "ipv4"
+	case "ipv4", "dual":
+	case "ipv6":
+		return nil, fmt.Errorf("invalid tun.family: the overlay carries IPv4 only, use 'ipv4' or 'dual'")
+	default:
+		return nil, fmt.Errorf("invalid tun.family: must be 'ipv4' or 'dual'")
+	}
+	switch config.ServerSelection {
+	case "":
+		config.ServerSelection = "order"
+	case "order", "latency":
+	default:
+		return nil, fmt.Errorf("invalid server_selection: must be 'order' or 'latency'")
+	}
+	switch config.RouteConflicts {
+	case "":
+		config.RouteConflicts = "skip"
+	case "refuse", "skip", "override":
+	default:
+		return nil, fmt.Errorf("invalid route_conflicts: must be 'refuse', 'skip' or 'override'")
+	}
+	if config.DNS != nil {
+		if len(config.DNS.Servers) == 0 {
+			return nil, fmt.Errorf("dns requires at least one server")
+		}
+		for _, server := range config.DNS.Servers {
+			if net.ParseIP(server) == nil {
+				return nil, fmt.Errorf("invalid dns server %q: must be an IP address", server)
+			}
+		}
+	}
+	if config.AppRouting != nil {
+		if len(config.AppRouting.UIDs) == 0 && len(config.AppRouting.Cgroups) == 0 {
+			return nil, fmt.Errorf("app_routing requires at least one uid or cgroup")
+		}
+		if config.AppRouting.Mark == 0 {
+			config.AppRouting.Mark = 0x8228
+		}
+		if config.AppRouting.Table == 0 {
+			config.AppRouting.Table = 8228
+		}
+	}
 
 	return &config, nil
 }
@@ -169,9 +898,166 @@ func (c *ServerConfig) Validate() error {
 	if c.CertFile == "" || c.KeyFile == "" {
 		return fmt.Errorf("cert_file and key_file are required")
 	}
+	// Daily rollups are summed from the hourly ones of the previous day
+	if (c.Database.HourlyStatsDays < 2 && c.Database.HourlyStatsDays != -1) || (c.Database.DailyStatsDays < 1 && c.Database.DailyStatsDays != -1) {
+		return fmt.Errorf("hourly_stats_days must be at least 2 and daily_stats_days at least 1, or -1 to keep forever")
+	}
 	if c.Network.OverlayCIDR == "" {
 		return fmt.Errorf("overlay CIDR is required")
 	}
+	if c.Network.MTU < 576 || c.Network.MaxMTU < c.Network.MTU || c.Network.MaxMTU > 65535 {
+		return fmt.Errorf("mtu must be at least 576 and max_mtu between mtu and 65535")
+	}
+	if c.Network.RelayWorkers < 1 || c.Network.RelayQueueLen < 1 {
+		return fmt.Errorf("relay_workers and relay_queue_len must be positive")
+	}
+	if c.Network.MaxSessionMemoryKB < 0 {
+		return fmt.Errorf("max_session_memory_kb must not be negative")
+	}
+	if c.Network.SlowConsumerStall < 1 || c.Network.SlowConsumerTimeout < 0 {
+		return fmt.Errorf("slow_consumer_stall must be positive and slow_consumer_timeout must not be negative")
+	}
+	if c.Network.SlowConsumerTimeout > 0 && c.Network.SlowConsumerTimeout*1000 <= c.Network.SlowConsumerStall {
+		return fmt.Errorf("slow_consumer_timeout must be longer than slow_consumer_stall")
+	}
+	if c.Network.KeepaliveInterval < 1 || c.Network.KeepaliveTimeout <= c.Network.KeepaliveInterval {
+		return fmt.Errorf("keepalive_interval must be positive and keepalive_timeout longer")
+	}
+	if c.Network.MaxSessions < 0 || c.Network.BusyRetryAfter < 1 {
+		return fmt.Errorf("max_sessions must not be negative and busy_retry_after must be positive")
+	}
+	for _, server := range c.Network.AlternateServers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			return fmt.Errorf("invalid alternate server %q: %w", server, err)
+		}
+	}
+	switch c.Network.Multicast {
+	case "drop", "relay", "unicast":
+	default:
+		return fmt.Errorf("multicast must be 'drop', 'relay' or 'unicast'")
+	}
+	if c.Network.MulticastRate < 1 || c.Network.MulticastBurst < 0 {
+		return fmt.Errorf("multicast_rate must be positive and multicast_burst must not be negative")
+	}
+	if c.Network.LoopThreshold < 1 {
+		return fmt.Errorf("loop_threshold must be positive")
+	}
+	if c.Security.RekeyInterval < 0 {
+		return fmt.Errorf("rekey_interval must not be negative")
+	}
+	if c.Security.MaxClockSkew < 0 || c.Security.ClockSkewWarning < 0 {
+		return fmt.Errorf("max_clock_skew and clock_skew_warning must not be negative")
+	}
+	if c.Security.MaxAccessDuration < 0 {
+		return fmt.Errorf("max_access_duration must not be negative")
+	}
+	if c.Gateway.Listen != "" {
+		if _, _, err := net.SplitHostPort(c.Gateway.Listen); err != nil {
+			return fmt.Errorf("invalid gateway listen address %q: %w", c.Gateway.Listen, err)
+		}
+	}
+	if c.HA.Listen != "" {
+		if err := c.HA.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Masque.Listen != "" {
+		if _, _, err := net.SplitHostPort(c.Masque.Listen); err != nil {
+			return fmt.Errorf("invalid masque listen address %q: %w", c.Masque.Listen, err)
+		}
+		if !validMasquePath(c.Masque.Path) {
+			return fmt.Errorf("masque path must start and end with '/'")
+		}
+	}
+	if err := c.Log.validate(); err != nil {
+		return err
+	}
+	return c.GRPC.validate(c.Network.MaxMTU)
+}
+
+// validate checks the HA settings of a server with HA enabled
+func (h *HAConfig) validate() error {
+	if _, _, err := net.SplitHostPort(h.Listen); err != nil {
+		return fmt.Errorf("invalid ha listen address %q: %w", h.Listen, err)
+	}
+	if _, _, err := net.SplitHostPort(h.Peer); err != nil {
+		return fmt.Errorf("invalid ha peer %q: %w", h.Peer, err)
+	}
+	if len(h.Secret) < 16 {
+		return fmt.Errorf("ha secret must be at least 16 characters")
+	}
+	if h.Priority < 1 || h.Priority > 254 {
+		return fmt.Errorf("ha priority must be between 1 and 254")
+	}
+	if _, err := netip.ParsePrefix(h.VirtualIP); err != nil {
+		return fmt.Errorf("invalid ha virtual_ip %q: %w", h.VirtualIP, err)
+	}
+	switch h.Mode {
+	case "arp":
+		if h.Interface == "" {
+			return fmt.Errorf("ha mode 'arp' requires interface")
+		}
+	case "keepalived":
+	default:
+		return fmt.Errorf("ha mode must be 'arp' or 'keepalived'")
+	}
+	if h.Interval < 1 || h.DeadTime <= h.Interval {
+		return fmt.Errorf("ha interval must be positive and dead_time longer")
+	}
+	return nil
+}
+
+// validate checks the gRPC tuning after defaults were applied. Messages
+// must fit relayed packets of the largest MTU.
+func (g *GRPCConfig) validate(maxMTU int) error {
+	if minSize := maxMTU + proto.DataPacketOverhead; g.MaxRecvMsgSize < minSize || g.MaxSendMsgSize < minSize {
+		return fmt.Errorf("grpc max_recv_msg_size and max_send_msg_size must be at least %d bytes for max_mtu %d", minSize, maxMTU)
+	}
+	if g.KeepaliveTime < 10 || g.KeepaliveTimeout < 1 {
+		return fmt.Errorf("grpc keepalive_time must be at least 10 and keepalive_timeout positive")
+	}
+	if g.KeepaliveMinTime < 1 || g.KeepaliveMinTime > agentPingInterval {
+		return fmt.Errorf("grpc keepalive_min_time must be between 1 and %d, the agent ping interval", agentPingInterval)
+	}
+	if g.MaxConnectionIdle < 0 || g.MaxConnectionAgeGrace < 0 {
+		return fmt.Errorf("grpc max_connection_idle and max_connection_age_grace must not be negative")
+	}
+	if g.MaxConnectionAge != 0 && g.MaxConnectionAge < 60 {
+		return fmt.Errorf("grpc max_connection_age must be 0 or at least 60")
+	}
+	return nil
+}
+
+// validateLoopback checks that a listen address is on loopback
+func validateLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("%s is not a loopback address", addr)
+	}
+	return nil
+}
+
+// validMasquePath reports whether a CONNECT-UDP path is absolute and ends
+// in a slash, the target host and port follow it
+func validMasquePath(path string) bool {
+	return strings.HasPrefix(path, "/") && strings.HasSuffix(path, "/")
+}
+
+// validateProxyURL checks a proxy URL has one of the schemes and a port
+func validateProxyURL(raw string, schemes ...string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(schemes, u.Scheme) {
+		return fmt.Errorf("scheme must be one of %v", schemes)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return fmt.Errorf("host and port are required")
+	}
 	return nil
 }
 
@@ -187,5 +1073,57 @@ func (c *AgentConfig) Validate() error {
 	if c.Mode != "client" && c.Mode != "gateway" {
 		return fmt.Errorf("mode must be 'client' or 'gateway'")
 	}
-	return nil
+	if c.TCPPort < 0 || c.TCPPort > 65535 {
+		return fmt.Errorf("tcp_port must be between 0 and 65535")
+	}
+	if c.Proxy != "" && c.Proxy != "direct" {
+		if err := validateProxyURL(c.Proxy, "http", "https", "socks5", "socks5h"); err != nil {
+			return fmt.Errorf("invalid proxy: %w", err)
+		}
+	}
+	if c.UDPRelay != "" {
+		if err := validateProxyURL(c.UDPRelay, "socks5"); err != nil {
+			return fmt.Errorf("invalid udp_relay: %w", err)
+		}
+	}
+	if c.MasquePort < 0 || c.MasquePort > 65535 {
+		return fmt.Errorf("masque_port must be between 0 and 65535")
+	}
+	if c.MasquePort != 0 {
+		if c.UDPRelay != "" {
+			return fmt.Errorf("masque_port and udp_relay are exclusive")
+		}
+		if !validMasquePath(c.MasquePath) {
+			return fmt.Errorf("masque_path must start and end with '/'")
+		}
+	}
+	if c.WebUI != "" {
+		if err := validateLoopback(c.WebUI); err != nil {
+			return fmt.Errorf("invalid web_ui: %w", err)
+		}
+	}
+	if c.Tray != nil {
+		if err := validateLoopback(c.Tray.Listen); err != nil {
+			return fmt.Errorf("invalid tray.listen: %w", err)
+		}
+		if c.Tray.TokenFile == "" {
+			return fmt.Errorf("tray.token_file is required")
+		}
+	}
+	switch c.Sandbox {
+	case "", "off", "audit", "enforce":
+	default:
+		return fmt.Errorf("sandbox must be 'off', 'audit' or 'enforce'")
+	}
+	if c.DropPrivileges {
+		if c.User == "" {
+			return fmt.Errorf("drop_privileges requires user")
+		}
+		// resolv.conf, resolved and the iptables lock stay out of reach
+		// with CAP_NET_ADMIN alone
+		if c.DNS != nil || c.KillSwitch || c.AppRouting != nil {
+			return fmt.Errorf("dns, kill_switch and app_routing need the privilege helper, remove drop_privileges")
+		}
+	}
+	return c.Log.validate()
 }
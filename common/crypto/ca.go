@@ -0,0 +1,250 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CertUsage selects the extended key usage of an issued certificate
+type CertUsage int
+
+const (
+	// ServerCert authenticates a server to agents
+	ServerCert CertUsage = iota
+	// AgentCert authenticates an agent to a server
+	AgentCert
+)
+
+// CA is a private certificate authority issuing server and agent
+// certificates for deployments that do not use a public CA
+type CA struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+}
+
+// NewCA creates a self-signed CA valid for the given duration
+func NewCA(commonName string, validity time.Duration) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+
+	template, err := certTemplate(commonName, validity)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.MaxPathLenZero = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	return &CA{Cert: cert, Key: key}, nil
+}
+
+// LoadCA loads a CA certificate and its private key from PEM files
+func LoadCA(certFile, keyFile string) (*CA, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	cert, err := DecodePEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("%s is not a CA certificate", certFile)
+	}
+
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA key: %w", err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok || !key.PublicKey.Equal(cert.PublicKey) {
+		return nil, fmt.Errorf("CA key does not match the CA certificate")
+	}
+
+	return &CA{Cert: cert, Key: key}, nil
+}
+
+// Issue creates a certificate signed by the CA. Hosts are added as DNS
+// names or IP addresses and are required for server certificates.
+func (ca *CA) Issue(commonName string, hosts []string, usage CertUsage, validity time.Duration) (certPEM, keyPEM []byte, err error) {
+	if usage == ServerCert && len(hosts) == 0 {
+		return nil, nil, fmt.Errorf("server certificate requires at least one host")
+	}
+	if expiry := time.Now().Add(validity); expiry.After(ca.Cert.NotAfter) {
+		return nil, nil, fmt.Errorf("certificate would outlive the CA, which expires on %s", ca.Cert.NotAfter.Format("2006-01-02"))
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	template, err := certTemplate(commonName, validity)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	switch usage {
+	case ServerCert:
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	case AgentCert:
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, &key.PublicKey, ca.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	keyPEM, err = encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// CertPEM returns the CA certificate, which is the trust bundle agents
+// need to verify certificates issued by the CA
+func (ca *CA) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Cert.Raw})
+}
+
+// KeyPEM returns the CA private key
+func (ca *CA) KeyPEM() ([]byte, error) {
+	return encodeKey(ca.Key)
+}
+
+// Fingerprint returns the SHA256 fingerprint of the CA certificate
+func (ca *CA) Fingerprint() string {
+	return fmt.Sprintf("%x", sha256.Sum256(ca.Cert.Raw))
+}
+
+// WriteKeyPair stores a certificate and its private key. The key is only
+// readable by the owner, and existing files are kept unless overwrite is
+// set.
+func WriteKeyPair(certFile, keyFile string, certPEM, keyPEM []byte, overwrite bool) error {
+	// Refuse before writing anything so that no half of a pair is replaced
+	if !overwrite {
+		for _, file := range []string{certFile, keyFile} {
+			if _, err := os.Stat(file); err == nil {
+				return fmt.Errorf("%s already exists", file)
+			}
+		}
+	}
+
+	if err := writePEMFile(keyFile, keyPEM, 0600, overwrite); err != nil {
+		return err
+	}
+	return writePEMFile(certFile, certPEM, 0644, overwrite)
+}
+
+// LoadTrustBundle reads a PEM bundle of CA certificates
+func LoadTrustBundle(file string) ([]byte, *x509.CertPool, error) {
+	bundle, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read trust bundle: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, nil, fmt.Errorf("no certificates found in %s", file)
+	}
+	return bundle, pool, nil
+}
+
+// certTemplate returns a certificate template with a random serial number
+func certTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{"EasyAnyLink"},
+		},
+		NotBefore: now.Add(-time.Hour), // tolerate clock skew
+		NotAfter:  now.Add(validity),
+	}, nil
+}
+
+// encodeKey encodes a private key as PKCS#8 PEM
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// writePEMFile writes data with the given permissions, creating the
+// parent directory only accessible to the owner
+func writePEMFile(file string, data []byte, perm os.FileMode, overwrite bool) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(file, flags, perm)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("%s already exists", file)
+		}
+		return fmt.Errorf("failed to create %s: %w", file, err)
+	}
+
+	// Tighten permissions of an overwritten file created with wider ones
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to set permissions of %s: %w", file, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/logging"
+	"github.com/quic-go/quic-go/qlog"
+)
+
+// Environment variables that enable transport debugging when the
+// configuration does not
+const (
+	KeyLogFileEnv = "SSLKEYLOGFILE"
+	QlogDirEnv    = "QLOGDIR"
+)
+
+var (
+	keyLogWriter io.Writer // receives TLS secrets, nil unless key logging is enabled
+	qlogDir      string    // directory of qlog files, empty unless qlog is enabled
+)
+
+// EnableTransportDebug turns on TLS key logging in NSS key log format, for
+// decrypting captures in Wireshark, and a qlog file per QUIC connection,
+// for qvis. Empty arguments fall back to the SSLKEYLOGFILE and QLOGDIR
+// environment variables; with neither set debugging stays off. It must be
+// called before any TLS configuration is loaded, and returns a function
+// closing the key log file.
+func EnableTransportDebug(keyLogFile, qlogDirectory string) (func(), error) {
+	if keyLogFile == "" {
+		keyLogFile = os.Getenv(KeyLogFileEnv)
+	}
+	if qlogDirectory == "" {
+		qlogDirectory = os.Getenv(QlogDirEnv)
+	}
+
+	closeKeyLog := func() {}
+	if keyLogFile != "" {
+		f, err := os.OpenFile(keyLogFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open TLS key log file: %w", err)
+		}
+		keyLogWriter = f
+		closeKeyLog = func() { f.Close() }
+
+		log.Println("WARNING: ************************************************************")
+		log.Printf("WARNING: TLS key logging is enabled, writing session secrets to %s", keyLogFile)
+		log.Println("WARNING: Anyone with this file can decrypt all captured tunnel traffic.")
+		log.Println("WARNING: Use it for debugging only and delete the file afterwards!")
+		log.Println("WARNING: ************************************************************")
+	}
+
+	if qlogDirectory != "" {
+		if err := os.MkdirAll(qlogDirectory, 0700); err != nil {
+			closeKeyLog()
+			return nil, fmt.Errorf("failed to create qlog directory: %w", err)
+		}
+		qlogDir = qlogDirectory
+
+		log.Printf("WARNING: QUIC qlog output is enabled, writing a trace of every connection to %s", qlogDirectory)
+		log.Println("WARNING: qlog files grow quickly and reveal connection metadata. Use for debugging only!")
+	}
+
+	return closeKeyLog, nil
+}
+
+// connectionTracer returns the quic-go tracer counting key updates and,
+// if qlog is enabled, writing qlog files
+func connectionTracer() func(context.Context, logging.Perspective, quic.ConnectionID) *logging.ConnectionTracer {
+	dir := qlogDir
+
+	return func(_ context.Context, p logging.Perspective, connID quic.ConnectionID) *logging.ConnectionTracer {
+		if dir == "" {
+			return keyUpdateTracer
+		}
+
+		label := "client"
+		if p == logging.PerspectiveServer {
+			label = "server"
+		}
+
+		file := filepath.Join(dir, fmt.Sprintf("%s_%s.sqlog", connID, label))
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			log.Printf("Failed to create qlog file: %v", err)
+			return keyUpdateTracer
+		}
+		return logging.NewMultiplexedConnectionTracer(keyUpdateTracer,
+			qlog.NewConnectionTracer(&bufferedFile{Writer: bufio.NewWriter(f), file: f}, p, connID))
+	}
+}
+
+// bufferedFile buffers writes to a file and flushes them on close
+type bufferedFile struct {
+	*bufio.Writer
+	file *os.File
+}
+
+func (b *bufferedFile) Close() error {
+	if err := b.Flush(); err != nil {
+		b.file.Close()
+		return err
+	}
+	return b.file.Close()
+}
@@ -0,0 +1,7 @@
+//go:build fips
+
+package crypto
+
+// fipsBuild forces the fips crypto policy. Build with
+// GOFIPS140=latest go build -tags fips so the FIPS 140 module is on.
+const fipsBuild = true
@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/logging"
+)
+
+// HandshakeStats counts QUIC handshake attempts seen by a listener
+type HandshakeStats struct {
+	Validated     uint64 // Handshakes from addresses validated by a Retry or resumption token
+	Unvalidated   uint64 // Handshakes accepted without address validation
+	Retries       uint64 // Retry packets sent to force address validation
+	InvalidTokens uint64 // Handshakes rejected because of an invalid or expired Retry token
+	RetryActive   bool   // Whether Retry is currently being enforced
+}
+
+// handshakeGuard enables QUIC stateless Retry when the rate of new
+// handshake attempts exceeds a threshold, so spoofed Initial floods cost
+// the server one Retry packet each instead of handshake state
+type handshakeGuard struct {
+	threshold int // attempts per second, 0 disables Retry
+
+	mu          sync.Mutex
+	windowStart time.Time
+	attempts    int
+	active      bool
+
+	validated     atomic.Uint64
+	unvalidated   atomic.Uint64
+	retries       atomic.Uint64
+	invalidTokens atomic.Uint64
+}
+
+// verifySourceAddress is called by quic-go for each connection attempt
+// without a valid token. It returns true when the client must first go
+// through a Retry round trip.
+func (g *handshakeGuard) verifySourceAddress(net.Addr) bool {
+	if g.threshold <= 0 {
+		return false
+	}
+
+	g.mu.Lock()
+	now := time.Now()
+	if now.Sub(g.windowStart) >= time.Second {
+		// Stay in Retry mode while the previous second was over the threshold
+		g.active = g.attempts > g.threshold
+		g.windowStart = now
+		g.attempts = 0
+	}
+	g.attempts++
+	retry := g.active || g.attempts > g.threshold
+	g.mu.Unlock()
+
+	if retry {
+		g.retries.Add(1)
+	}
+	return retry
+}
+
+// configForClient records whether an accepted handshake had a validated
+// source address
+func (g *handshakeGuard) configForClient(config *quic.Config) func(*quic.ClientHelloInfo) (*quic.Config, error) {
+	return func(info *quic.ClientHelloInfo) (*quic.Config, error) {
+		if info.AddrVerified {
+			g.validated.Add(1)
+		} else {
+			g.unvalidated.Add(1)
+		}
+		return config, nil
+	}
+}
+
+// tracer counts INVALID_TOKEN rejections sent by the transport
+func (g *handshakeGuard) tracer() *logging.Tracer {
+	return &logging.Tracer{
+		SentPacket: func(_ net.Addr, _ *logging.Header, _ logging.ByteCount, frames []logging.Frame) {
+			for _, frame := range frames {
+				ccf, ok := frame.(*logging.ConnectionCloseFrame)
+				if ok && !ccf.IsApplicationError && ccf.ErrorCode == uint64(quic.InvalidToken) {
+					g.invalidTokens.Add(1)
+				}
+			}
+		},
+	}
+}
+
+// stats returns a snapshot of the handshake counters
+func (g *handshakeGuard) stats() HandshakeStats {
+	g.mu.Lock()
+	var active bool
+	switch elapsed := time.Since(g.windowStart); {
+	case g.threshold <= 0 || elapsed >= 2*time.Second:
+		active = false
+	case elapsed >= time.Second:
+		active = g.attempts > g.threshold
+	default:
+		active = g.active || g.attempts > g.threshold
+	}
+	g.mu.Unlock()
+
+	return HandshakeStats{
+		Validated:     g.validated.Load(),
+		Unvalidated:   g.unvalidated.Load(),
+		Retries:       g.retries.Load(),
+		InvalidTokens: g.invalidTokens.Load(),
+		RetryActive:   active,
+	}
+}
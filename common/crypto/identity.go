@@ -0,0 +1,76 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// Hardware that holds the identity key of an agent
+const (
+	IdentityTPM2          = "tpm2"
+	IdentitySecureEnclave = "secure-enclave"
+)
+
+// IdentityDigest returns the SHA-256 digest an agent signs with its
+// hardware identity key. It covers the server's challenge, so a signature
+// cannot be replayed, and the public key, so it cannot be moved to another
+// key. Each field is length-prefixed like the pre-shared-key MAC.
+func IdentityDigest(req *proto.RegisterRequest) []byte {
+	h := sha256.New()
+	var buf [8]byte
+	for _, field := range [][]byte{
+		[]byte("easyanylink-identity-v1"),
+		[]byte(req.AgentId),
+		[]byte(req.Type.String()),
+		[]byte(req.RequestId),
+		req.IdentityChallenge,
+		req.IdentityKey,
+	} {
+		binary.BigEndian.PutUint64(buf[:], uint64(len(field)))
+		h.Write(buf[:])
+		h.Write(field)
+	}
+	return h.Sum(nil)
+}
+
+// ParseIdentityKey parses the PKIX public key of a hardware identity,
+// which must be ECDSA on P-256, the curve TPMs and the Secure Enclave share
+func ParseIdentityKey(der []byte) (*ecdsa.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid identity key: %w", err)
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != elliptic.P256() {
+		return nil, errors.New("identity key is not an ECDSA P-256 key")
+	}
+	return pub, nil
+}
+
+// VerifyIdentity checks the identity signature of a registration. The
+// caller must also check that it issued the challenge and accept it once.
+func VerifyIdentity(req *proto.RegisterRequest) error {
+	if len(req.IdentityChallenge) == 0 || len(req.IdentitySignature) == 0 {
+		return errors.New("registration is not signed with the identity key")
+	}
+	pub, err := ParseIdentityKey(req.IdentityKey)
+	if err != nil {
+		return err
+	}
+	if !ecdsa.VerifyASN1(pub, IdentityDigest(req), req.IdentitySignature) {
+		return errors.New("invalid identity signature")
+	}
+	return nil
+}
+
+// IdentityFingerprint returns the hex SHA256 of a PKIX identity key
+func IdentityFingerprint(der []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(der))
+}
@@ -0,0 +1,25 @@
+package crypto
+
+import (
+	"sync/atomic"
+
+	"github.com/quic-go/quic-go/logging"
+)
+
+// keyUpdates counts the QUIC key updates of all connections of the process
+var keyUpdates atomic.Uint64
+
+// keyUpdateTracer counts key updates, whichever end started them
+var keyUpdateTracer = &logging.ConnectionTracer{
+	UpdatedKey: func(logging.KeyPhase, bool) {
+		keyUpdates.Add(1)
+	},
+}
+
+// KeyUpdates returns how many QUIC key updates the connections of the
+// process went through. quic-go updates the keys of a connection every
+// 100,000 packets and does not make the interval configurable; a new
+// connection is the way to fresh keys on a schedule.
+func KeyUpdates() uint64 {
+	return keyUpdates.Load()
+}
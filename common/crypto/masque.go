@@ -0,0 +1,329 @@
+package crypto
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// MasqueProtocol is the :protocol of the extended CONNECT requests opening
+// UDP tunnels (RFC 9298)
+const MasqueProtocol = "connect-udp"
+
+// masqueContextID is the context ID of HTTP datagrams carrying UDP
+// payloads; other contexts are not used and dropped
+const masqueContextID = 0
+
+// masqueQueueLen is how many received packets wait for the QUIC stack
+// before more are dropped
+const masqueQueueLen = 1024
+
+// DialMasque opens a CONNECT-UDP tunnel through the HTTP/3 endpoint at
+// endpoint (host:port) and path to target (host:port). QUIC to target runs
+// over the returned conn, which closes the HTTP/3 connection once closed.
+func DialMasque(ctx context.Context, endpoint, path, target string, tlsConfig *tls.Config) (net.PacketConn, error) {
+	targetAddr, err := net.ResolveUDPAddr("udp", target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve UDP address: %w", err)
+	}
+	host, port, _ := net.SplitHostPort(target)
+	// The target is a URI template variable, colons of IPv6 addresses
+	// are escaped too
+	u, err := url.Parse("https://" + endpoint + path +
+		strings.ReplaceAll(url.PathEscape(host), ":", "%3A") + "/" + port + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid MASQUE endpoint: %w", err)
+	}
+
+	config := tlsConfig.Clone()
+	config.NextProtos = []string{http3.NextProtoH3}
+	conn, err := quic.DialAddr(ctx, endpoint, config, &quic.Config{
+		MaxIdleTimeout:  300 * time.Second,
+		KeepAlivePeriod: 30 * time.Second,
+		EnableDatagrams: true,
+		Tracer:          connectionTracer(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MASQUE endpoint %s: %w", endpoint, err)
+	}
+	// Nothing below takes ctx, closing the connection interrupts it
+	stop := context.AfterFunc(ctx, func() {
+		conn.CloseWithError(0, "")
+	})
+	defer stop()
+	fail := func(err error) (net.PacketConn, error) {
+		conn.CloseWithError(0, "")
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, fmt.Errorf("MASQUE endpoint %s: %w", endpoint, err)
+	}
+
+	client := (&http3.Transport{EnableDatagrams: true}).NewClientConn(conn)
+	select {
+	case <-client.ReceivedSettings():
+	case <-conn.Context().Done():
+		return fail(context.Cause(conn.Context()))
+	}
+	if settings := client.Settings(); !settings.EnableDatagrams || !settings.EnableExtendedConnect {
+		return fail(errors.New("no support for CONNECT-UDP"))
+	}
+
+	str, err := client.OpenRequestStream(ctx)
+	if err != nil {
+		return fail(err)
+	}
+	req := &http.Request{
+		Method: http.MethodConnect,
+		Proto:  MasqueProtocol,
+		Host:   endpoint,
+		URL:    u,
+		Header: http.Header{"Capsule-Protocol": {"?1"}},
+	}
+	if err := str.SendRequestHeader(req.WithContext(ctx)); err != nil {
+		return fail(err)
+	}
+	rsp, err := str.ReadResponse()
+	if err != nil {
+		return fail(err)
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fail(fmt.Errorf("tunnel refused: %s", rsp.Status))
+	}
+
+	c := NewMasqueConn(conn.LocalAddr())
+	c.peer = &masqueAddr{addr: targetAddr, str: str, done: make(chan struct{})}
+	c.close = func() {
+		str.Close()
+		conn.CloseWithError(0, "")
+	}
+	go func() {
+		// The tunnel ends with the request stream
+		io.Copy(io.Discard, str)
+		conn.CloseWithError(0, "")
+	}()
+	go func() {
+		c.receive(conn.Context(), c.peer)
+		c.Close()
+	}()
+	return c, nil
+}
+
+// MasqueConn is a net.PacketConn over the HTTP datagrams of CONNECT-UDP
+// tunnels. On the client it is the one tunnel DialMasque opened; on the
+// server it gathers the packets of all the tunnels Serve was given, their
+// peers told apart by the request stream. Like UDP it drops packets it
+// cannot queue or send.
+type MasqueConn struct {
+	local     net.Addr
+	packets   chan masquePacket
+	deadline  pipeDeadline
+	done      chan struct{}
+	closeOnce sync.Once
+	peer      *masqueAddr // the tunnel of a client, nil on the server
+	close     func()      // frees the connection of a client
+}
+
+// masquePacket is a packet received from a tunnel
+type masquePacket struct {
+	data []byte
+	from *masqueAddr
+}
+
+// masqueAddr is the address of a tunnel peer. It reads like the address
+// the peer's HTTP/3 connection comes from, for per-IP limits to apply.
+type masqueAddr struct {
+	addr net.Addr
+	str  http3.Stream
+	done chan struct{} // closed once no more packets come from the tunnel
+}
+
+func (a *masqueAddr) Network() string { return "masque" }
+func (a *masqueAddr) String() string  { return a.addr.String() }
+
+// NewMasqueConn creates the conn the tunnels of a server deliver their
+// packets to; local is the address of the HTTP/3 endpoint
+func NewMasqueConn(local net.Addr) *MasqueConn {
+	return &MasqueConn{
+		local:    &masqueAddr{addr: local},
+		packets:  make(chan masquePacket, masqueQueueLen),
+		deadline: makePipeDeadline(),
+		done:     make(chan struct{}),
+	}
+}
+
+// Serve delivers the packets of a CONNECT-UDP request stream the server
+// accepted, from a peer at remote, until the stream or ctx ends
+func (c *MasqueConn) Serve(ctx context.Context, str http3.Stream, remote net.Addr) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		// No capsules are used, only the end of the stream matters
+		io.Copy(io.Discard, str)
+		cancel()
+	}()
+	c.receive(ctx, &masqueAddr{addr: remote, str: str, done: make(chan struct{})})
+}
+
+// receive queues the UDP payloads of the HTTP datagrams from peer
+func (c *MasqueConn) receive(ctx context.Context, peer *masqueAddr) {
+	defer close(peer.done)
+	for {
+		data, err := peer.str.ReceiveDatagram(ctx)
+		if err != nil {
+			return
+		}
+		contextID, n, err := quicvarint.Parse(data)
+		if err != nil || contextID != masqueContextID {
+			continue
+		}
+		select {
+		case c.packets <- masquePacket{data: data[n:], from: peer}:
+		case <-c.done:
+			return
+		default:
+		}
+	}
+}
+
+func (c *MasqueConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	select {
+	case p := <-c.packets:
+		return copy(b, p.data), p.from, nil
+	case <-c.deadline.wait():
+		return 0, nil, os.ErrDeadlineExceeded
+	case <-c.done:
+		return 0, nil, net.ErrClosed
+	}
+}
+
+func (c *MasqueConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	peer, ok := addr.(*masqueAddr)
+	if !ok {
+		peer = c.peer
+	}
+	if peer == nil || peer.str == nil {
+		return 0, fmt.Errorf("no MASQUE tunnel to %s", addr)
+	}
+	select {
+	case <-peer.done:
+		// Ends the QUIC connection of a tunnel that is gone
+		return 0, net.ErrClosed
+	default:
+	}
+
+	datagram := make([]byte, 0, quicvarint.Len(masqueContextID)+len(b))
+	datagram = quicvarint.Append(datagram, masqueContextID)
+	datagram = append(datagram, b...)
+	// A datagram too large or a full queue loses the packet
+	peer.str.SendDatagram(datagram)
+	return len(b), nil
+}
+
+func (c *MasqueConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		if c.close != nil {
+			c.close()
+		}
+	})
+	return nil
+}
+
+func (c *MasqueConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *MasqueConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+func (c *MasqueConn) SetReadDeadline(t time.Time) error {
+	c.deadline.set(t)
+	return nil
+}
+
+// SetWriteDeadline does nothing, writes never block
+func (c *MasqueConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// SetReadBuffer does nothing, there is no socket buffer to size. Having it
+// keeps QUIC from warning about small buffers.
+func (c *MasqueConn) SetReadBuffer(bytes int) error {
+	return nil
+}
+
+// SetWriteBuffer does nothing, see SetReadBuffer
+func (c *MasqueConn) SetWriteBuffer(bytes int) error {
+	return nil
+}
+
+// pipeDeadline is a read deadline that may change while a read waits on
+// it, as net.Pipe has
+type pipeDeadline struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	cancel chan struct{} // closed once the deadline passed
+}
+
+func makePipeDeadline() pipeDeadline {
+	return pipeDeadline{cancel: make(chan struct{})}
+}
+
+// set sets the deadline, the zero time clears it
+func (d *pipeDeadline) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil && !d.timer.Stop() {
+		<-d.cancel // the timer fired, wait for it to close cancel
+	}
+	d.timer = nil
+
+	closed := false
+	select {
+	case <-d.cancel:
+		closed = true
+	default:
+	}
+	if t.IsZero() {
+		if closed {
+			d.cancel = make(chan struct{})
+		}
+		return
+	}
+	if dur := time.Until(t); dur > 0 {
+		if closed {
+			d.cancel = make(chan struct{})
+		}
+		cancel := d.cancel
+		d.timer = time.AfterFunc(dur, func() {
+			close(cancel)
+		})
+		return
+	}
+	if !closed {
+		close(d.cancel)
+	}
+}
+
+// wait returns a channel closed once the deadline passes
+func (d *pipeDeadline) wait() chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cancel
+}
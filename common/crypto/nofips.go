@@ -0,0 +1,6 @@
+//go:build !fips
+
+package crypto
+
+// fipsBuild is set in binaries built with the fips tag
+const fipsBuild = false
@@ -0,0 +1,151 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/fips140"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync/atomic"
+)
+
+const (
+	// PolicyDefault allows every TLS 1.3 suite and curve Go supports
+	PolicyDefault = "default"
+
+	// PolicyFIPS restricts TLS to FIPS 140 approved algorithms: AES-GCM,
+	// the P-256 and P-384 curves, and RSA keys of at least 2048 bits
+	PolicyFIPS = "fips"
+)
+
+// fipsMinRSABits is the smallest RSA key the fips policy accepts
+const fipsMinRSABits = 2048
+
+// activePolicy is the policy applied to TLS configurations built by this
+// package, set once at startup
+var activePolicy atomic.Value
+
+func init() {
+	if fipsBuild {
+		activePolicy.Store(PolicyFIPS)
+	} else {
+		activePolicy.Store(PolicyDefault)
+	}
+}
+
+// SetPolicy selects the crypto policy, an empty name keeps the build
+// default. Binaries built with the fips tag only accept the fips policy,
+// which needs the Go FIPS 140 module: TLS 1.3 cipher suites can only be
+// restricted by it.
+func SetPolicy(name string) error {
+	if name == "" {
+		name = ActivePolicy()
+	}
+	switch name {
+	case PolicyDefault:
+		if fipsBuild {
+			return fmt.Errorf("crypto policy %q is not available in a fips build", name)
+		}
+	case PolicyFIPS:
+		if !fips140.Enabled() {
+			return fmt.Errorf("crypto policy %q requires the FIPS 140 module, build with GOFIPS140 or run with GODEBUG=fips140=on", name)
+		}
+	default:
+		return fmt.Errorf("unknown crypto policy %q: must be %q or %q", name, PolicyDefault, PolicyFIPS)
+	}
+	activePolicy.Store(name)
+	return nil
+}
+
+// ActivePolicy returns the name of the active crypto policy
+func ActivePolicy() string {
+	return activePolicy.Load().(string)
+}
+
+// PolicySummary describes the active policy for version output
+func PolicySummary() string {
+	module := "disabled"
+	if fips140.Enabled() {
+		module = "enabled"
+	}
+	return fmt.Sprintf("%s (FIPS 140 module %s)", ActivePolicy(), module)
+}
+
+// FIPSBuild reports whether the binary was built with the fips tag
+func FIPSBuild() bool {
+	return fipsBuild
+}
+
+// FIPSModuleEnabled reports whether the Go FIPS 140 module is active
+func FIPSModuleEnabled() bool {
+	return fips140.Enabled()
+}
+
+// PolicyCipherSuites returns the names of the TLS 1.3 suites the active
+// policy allows
+func PolicyCipherSuites() []string {
+	var names []string
+	for _, id := range policyCipherSuites() {
+		names = append(names, tls.CipherSuiteName(id))
+	}
+	return names
+}
+
+// PolicyCurves returns the names of the key exchange groups the active
+// policy allows, empty for the Go defaults
+func PolicyCurves() []string {
+	var names []string
+	for _, curve := range policyCurves() {
+		names = append(names, curve.String())
+	}
+	return names
+}
+
+// applyPolicy restricts a TLS configuration to the active policy
+func applyPolicy(cfg *tls.Config) {
+	cfg.CipherSuites = policyCipherSuites()
+	cfg.CurvePreferences = policyCurves()
+}
+
+// policyCipherSuites returns the TLS 1.3 suites of the active policy
+func policyCipherSuites() []uint16 {
+	if ActivePolicy() == PolicyFIPS {
+		return []uint16{
+			tls.TLS_AES_128_GCM_SHA256,
+			tls.TLS_AES_256_GCM_SHA384,
+		}
+	}
+	return getSecureCipherSuites()
+}
+
+// policyCurves returns the curve preferences of the active policy, nil
+// for the Go defaults
+func policyCurves() []tls.CurveID {
+	if ActivePolicy() == PolicyFIPS {
+		return []tls.CurveID{tls.CurveP256, tls.CurveP384}
+	}
+	return nil
+}
+
+// checkKeyPolicy rejects certificate keys the active policy does not allow
+func checkKeyPolicy(cert *x509.Certificate) error {
+	if ActivePolicy() != PolicyFIPS {
+		return nil
+	}
+
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < fipsMinRSABits {
+			return fmt.Errorf("%d-bit RSA key is below the fips policy minimum of %d bits", key.N.BitLen(), fipsMinRSABits)
+		}
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() && key.Curve != elliptic.P384() {
+			return fmt.Errorf("ECDSA curve %s is not allowed by the fips policy", key.Curve.Params().Name)
+		}
+	default:
+		return fmt.Errorf("%T keys are not allowed by the fips policy", key)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// RegistrationMaxSkew is how far the timestamp of a registration signed
+// with a pre-shared key may be from the server clock by default
+const RegistrationMaxSkew = 5 * time.Minute
+
+// SignRegistration adds a fresh nonce, the current time and the
+// pre-shared-key MAC to a registration. A TLS interception middlebox sees
+// the request but cannot alter it or replay it later. skew is how far the
+// local clock was measured ahead of the server's, the timestamp is
+// corrected by it.
+func SignRegistration(psk string, req *proto.RegisterRequest, skew time.Duration) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	req.Nonce = nonce
+	req.Timestamp = time.Now().Add(-skew).Unix()
+	req.Mac = registrationMAC(psk, req)
+	return nil
+}
+
+// VerifyRegistration checks the MAC and timestamp of a registration, which
+// may be up to maxSkew off the server clock. The caller must also reject
+// nonces it has seen within maxSkew.
+func VerifyRegistration(psk string, req *proto.RegisterRequest, maxSkew time.Duration) error {
+	if len(req.Mac) == 0 || len(req.Nonce) == 0 {
+		return fmt.Errorf("registration is not signed with the pre-shared key")
+	}
+	if !hmac.Equal(req.Mac, registrationMAC(psk, req)) {
+		return fmt.Errorf("invalid registration MAC")
+	}
+	skew := time.Since(time.Unix(req.Timestamp, 0))
+	if skew > maxSkew || skew < -maxSkew {
+		return &ClockSkewError{Skew: skew, Max: maxSkew}
+	}
+	return nil
+}
+
+// ClockSkewError rejects a registration whose MAC is valid but whose
+// timestamp is too far off the server clock
+type ClockSkewError struct {
+	Skew time.Duration // server clock minus the timestamp
+	Max  time.Duration
+}
+
+func (e *ClockSkewError) Error() string {
+	return fmt.Sprintf("registration timestamp is %s off the server clock, more than the %s allowed",
+		e.Skew.Round(time.Second), e.Max)
+}
+
+// registrationMAC authenticates the identity, credential and anti-replay
+// fields of a registration. Each field is length-prefixed so that values
+// cannot be shifted between fields.
+func registrationMAC(psk string, req *proto.RegisterRequest) []byte {
+	mac := hmac.New(sha256.New, []byte(psk))
+	var buf [8]byte
+	for _, field := range [][]byte{
+		[]byte(req.AgentId),
+		[]byte(req.UserKey),
+		[]byte(req.Type.String()),
+		[]byte(req.ProtocolVersion),
+		[]byte(req.RequestId),
+		binary.BigEndian.AppendUint32(nil, uint32(req.Bandwidth)),
+		req.Nonce,
+		binary.BigEndian.AppendUint64(nil, uint64(req.Timestamp)),
+	} {
+		binary.BigEndian.PutUint64(buf[:], uint64(len(field)))
+		mac.Write(buf[:])
+		mac.Write(field)
+	}
+	return mac.Sum(nil)
+}
@@ -5,7 +5,9 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/quic-go/quic-go"
@@ -14,13 +16,20 @@ import (
 
 // QUICListener implements net.Listener for QUIC connections
 type QUICListener struct {
-	listener *quic.Listener
-	ctx      context.Context
-	cancel   context.CancelFunc
+	listener  *quic.Listener
+	transport *quic.Transport
+	conn      net.PacketConn
+	guard     *handshakeGuard
+	ctx       context.Context
+	cancel    context.CancelFunc
+	stopped   atomic.Bool  // no longer accepting, see StopAccepting
+	open      atomic.Int64 // accepted connections not yet closed
 }
 
-// NewQUICListener creates a new QUIC listener
-func NewQUICListener(addr string, tlsConfig *tls.Config) (*QUICListener, error) {
+// NewQUICListener creates a new QUIC listener. When more than
+// retryThreshold handshakes per second arrive, clients must validate their
+// source address with a stateless Retry first; 0 disables Retry.
+func NewQUICListener(addr string, tlsConfig *tls.Config, retryThreshold int) (*QUICListener, error) {
 	udpAddr, err := net.ResolveUDPAddr("udp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve UDP address: %w", err)
@@ -30,14 +39,34 @@ func NewQUICListener(addr string, tlsConfig *tls.Config) (*QUICListener, error)
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen UDP: %w", err)
 	}
+	return NewQUICListenerOn(udpConn, tlsConfig, retryThreshold)
+}
 
+// NewQUICListenerOn creates a QUIC listener on a UDP socket that is
+// already bound, e.g. one passed by systemd socket activation, or on a
+// conn relaying packets inside another protocol such as MASQUE. The
+// listener takes over the conn.
+func NewQUICListenerOn(udpConn net.PacketConn, tlsConfig *tls.Config, retryThreshold int) (*QUICListener, error) {
 	quicConfig := &quic.Config{
 		MaxIdleTimeout:  300 * 1e9, // 300 seconds
 		KeepAlivePeriod: 30 * 1e9,  // 30 seconds
 		EnableDatagrams: false,
+		Tracer:          connectionTracer(),
+	}
+	if _, ok := udpConn.(*net.UDPConn); !ok {
+		relayedPacketSize(quicConfig)
+	}
+
+	guard := &handshakeGuard{threshold: retryThreshold}
+	quicConfig.GetConfigForClient = guard.configForClient(quicConfig.Clone())
+
+	transport := &quic.Transport{
+		Conn:                udpConn,
+		VerifySourceAddress: guard.verifySourceAddress,
+		Tracer:              guard.tracer(),
 	}
 
-	listener, err := quic.Listen(udpConn, tlsConfig, quicConfig)
+	listener, err := transport.Listen(tlsConfig, quicConfig)
 	if err != nil {
 		udpConn.Close()
 		return nil, fmt.Errorf("failed to create QUIC listener: %w", err)
@@ -46,18 +75,37 @@ func NewQUICListener(addr string, tlsConfig *tls.Config) (*QUICListener, error)
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &QUICListener{
-		listener: listener,
-		ctx:      ctx,
-		cancel:   cancel,
+		listener:  listener,
+		transport: transport,
+		conn:      udpConn,
+		guard:     guard,
+		ctx:       ctx,
+		cancel:    cancel,
 	}, nil
 }
 
+// HandshakeStats returns the handshake address validation counters
+func (l *QUICListener) HandshakeStats() HandshakeStats {
+	return l.guard.stats()
+}
+
 // Accept waits for and returns the next connection to the listener
 func (l *QUICListener) Accept() (net.Conn, error) {
 	conn, err := l.listener.Accept(l.ctx)
 	if err != nil {
+		// A gRPC server closes the listener once Accept fails; keep it
+		// serving the established connections until Close
+		if l.stopped.Load() {
+			<-l.ctx.Done()
+			return nil, net.ErrClosed
+		}
 		return nil, err
 	}
+	l.open.Add(1)
+	go func() {
+		<-conn.Context().Done()
+		l.open.Add(-1)
+	}()
 
 	stream, err := conn.AcceptStream(l.ctx)
 	if err != nil {
@@ -71,12 +119,38 @@ func (l *QUICListener) Accept() (net.Conn, error) {
 	}, nil
 }
 
-// Close closes the listener
+// Close closes the listener, its connections and the UDP socket
 func (l *QUICListener) Close() error {
 	l.cancel()
+	err := l.listener.Close()
+	l.transport.Close()
+	l.conn.Close()
+	return err
+}
+
+// StopAccepting refuses new connections while the established ones keep
+// working until Close. Accept blocks until Close from then on.
+func (l *QUICListener) StopAccepting() error {
+	l.stopped.Store(true)
 	return l.listener.Close()
 }
 
+// OpenConnections returns the number of accepted connections not closed
+// yet, to wait for them to drain after StopAccepting
+func (l *QUICListener) OpenConnections() int {
+	return int(l.open.Load())
+}
+
+// File returns a duplicate of the UDP socket, to hand it to another
+// process
+func (l *QUICListener) File() (*os.File, error) {
+	udpConn, ok := l.conn.(*net.UDPConn)
+	if !ok {
+		return nil, fmt.Errorf("QUIC listener on %s has no socket of its own", l.conn.LocalAddr())
+	}
+	return udpConn.File()
+}
+
 // Addr returns the listener's network address
 func (l *QUICListener) Addr() net.Addr {
 	return l.listener.Addr()
@@ -84,9 +158,10 @@ func (l *QUICListener) Addr() net.Addr {
 
 // quicStreamConn wraps a QUIC stream to implement net.Conn
 type quicStreamConn struct {
-	stream quic.Stream
-	conn   quic.Connection
-	mu     sync.Mutex
+	stream  quic.Stream
+	conn    quic.Connection
+	release func() // nil unless the connection has a transport of its own
+	mu      sync.Mutex
 }
 
 func (c *quicStreamConn) Read(b []byte) (n int, err error) {
@@ -99,7 +174,11 @@ func (c *quicStreamConn) Write(b []byte) (n int, err error) {
 
 func (c *quicStreamConn) Close() error {
 	c.stream.Close()
-	return c.conn.CloseWithError(0, "connection closed")
+	err := c.conn.CloseWithError(0, "connection closed")
+	if c.release != nil {
+		c.release()
+	}
+	return err
 }
 
 func (c *quicStreamConn) LocalAddr() net.Addr {
@@ -124,7 +203,8 @@ func (c *quicStreamConn) SetWriteDeadline(t time.Time) error {
 
 // QUICDialer implements gRPC dialer for QUIC
 type QUICDialer struct {
-	tlsConfig *tls.Config
+	tlsConfig  *tls.Config
+	packetConn func(ctx context.Context, addr string) (net.PacketConn, error) // nil to send from a UDP socket of its own
 }
 
 // NewQUICDialer creates a new QUIC dialer
@@ -134,6 +214,16 @@ func NewQUICDialer(tlsConfig *tls.Config) *QUICDialer {
 	}
 }
 
+// NewQUICDialerVia creates a QUIC dialer sending its packets over the
+// conns packetConn returns for the address dialed, e.g. through a UDP
+// relay; each connection gets its own and closes it
+func NewQUICDialerVia(tlsConfig *tls.Config, packetConn func(ctx context.Context, addr string) (net.PacketConn, error)) *QUICDialer {
+	return &QUICDialer{
+		tlsConfig:  tlsConfig,
+		packetConn: packetConn,
+	}
+}
+
 // DialContext dials a QUIC connection
 func (d *QUICDialer) DialContext(ctx context.Context, addr string) (net.Conn, error) {
 	udpAddr, err := net.ResolveUDPAddr("udp", addr)
@@ -145,22 +235,58 @@ func (d *QUICDialer) DialContext(ctx context.Context, addr string) (net.Conn, er
 		MaxIdleTimeout:  300 * 1e9, // 300 seconds
 		KeepAlivePeriod: 30 * 1e9,  // 30 seconds
 		EnableDatagrams: false,
+		Tracer:          connectionTracer(),
 	}
 
-	conn, err := quic.DialAddr(ctx, udpAddr.String(), d.tlsConfig, quicConfig)
+	if d.packetConn == nil {
+		conn, err := quic.DialAddr(ctx, udpAddr.String(), d.tlsConfig, quicConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial QUIC: %w", err)
+		}
+		return openStreamConn(ctx, conn, nil)
+	}
+
+	packetConn, err := d.packetConn(ctx, addr)
 	if err != nil {
+		return nil, err
+	}
+	relayedPacketSize(quicConfig)
+	transport := &quic.Transport{Conn: packetConn}
+	release := sync.OnceFunc(func() {
+		transport.Close()
+		packetConn.Close()
+	})
+	conn, err := transport.Dial(ctx, udpAddr, d.tlsConfig, quicConfig)
+	if err != nil {
+		release()
 		return nil, fmt.Errorf("failed to dial QUIC: %w", err)
 	}
+	return openStreamConn(ctx, conn, release)
+}
 
+// relayedPacketSize keeps the packets of a QUIC connection over a relay at
+// the minimum size, so that they fit whatever the relay wraps them in
+func relayedPacketSize(quicConfig *quic.Config) {
+	quicConfig.InitialPacketSize = 1200
+	quicConfig.DisablePathMTUDiscovery = true
+}
+
+// openStreamConn opens the stream gRPC runs over, release frees what the
+// connection was dialed over once it closes
+func openStreamConn(ctx context.Context, conn quic.Connection, release func()) (net.Conn, error) {
 	stream, err := conn.OpenStreamSync(ctx)
 	if err != nil {
 		conn.CloseWithError(0, "failed to open stream")
+		if release != nil {
+			release()
+		}
 		return nil, fmt.Errorf("failed to open stream: %w", err)
 	}
 
 	return &quicStreamConn{
-		stream: stream,
-		conn:   conn,
+		stream:  stream,
+		conn:    conn,
+		release: release,
 	}, nil
 }
 
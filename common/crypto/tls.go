@@ -21,15 +21,19 @@ func LoadServerTLSConfig(certFile, keyFile string) (*tls.Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load server certificate: %w", err)
 	}
+	if err := checkKeyPolicy(serverCert.Leaf); err != nil {
+		return nil, fmt.Errorf("server certificate: %w", err)
+	}
 
 	// Configure TLS for one-way authentication (server provides cert, client verifies)
 	tlsConfig := &tls.Config{
 		Certificates: []tls.Certificate{serverCert},
 		ClientAuth:   tls.NoClientCert, // No client certificate required
 		MinVersion:   tls.VersionTLS13, // Enforce TLS 1.3+
-		CipherSuites: getSecureCipherSuites(),
-		NextProtos:   []string{"h3"}, // HTTP/3 for QUIC
+		NextProtos:   []string{"h3"},   // HTTP/3 for QUIC
+		KeyLogWriter: keyLogWriter,
 	}
+	applyPolicy(tlsConfig)
 
 	return tlsConfig, nil
 }
@@ -38,13 +42,33 @@ func LoadServerTLSConfig(certFile, keyFile string) (*tls.Config, error) {
 // Uses system root CAs to verify server certificate (e.g., Let's Encrypt)
 // If insecureSkipVerify is true, skips certificate verification (for debugging only)
 func LoadClientTLSConfig(serverName string, insecureSkipVerify bool) (*tls.Config, error) {
+	return LoadClientTLSConfigWithCA(serverName, "", insecureSkipVerify)
+}
+
+// LoadClientTLSConfigWithCA is like LoadClientTLSConfig but also trusts the
+// CA certificates in caFile, e.g. the trust bundle of a private CA
+func LoadClientTLSConfigWithCA(serverName, caFile string, insecureSkipVerify bool) (*tls.Config, error) {
 	// Configure TLS for one-way authentication (client verifies server)
 	tlsConfig := &tls.Config{
 		ServerName:         serverName,
 		MinVersion:         tls.VersionTLS13, // Enforce TLS 1.3+
-		CipherSuites:       getSecureCipherSuites(),
-		NextProtos:         []string{"h3"}, // HTTP/3 for QUIC
+		NextProtos:         []string{"h3"},   // HTTP/3 for QUIC
 		InsecureSkipVerify: insecureSkipVerify,
+		KeyLogWriter:       keyLogWriter,
+	}
+	applyPolicy(tlsConfig)
+	if ActivePolicy() == PolicyFIPS && !insecureSkipVerify {
+		// The verified server chain must use allowed keys as well
+		tlsConfig.VerifyPeerCertificate = func(_ [][]byte, chains [][]*x509.Certificate) error {
+			for _, chain := range chains {
+				for _, cert := range chain {
+					if err := checkKeyPolicy(cert); err != nil {
+						return fmt.Errorf("server certificate %s: %w", cert.Subject.CommonName, err)
+					}
+				}
+			}
+			return nil
+		}
 	}
 
 	if !insecureSkipVerify {
@@ -53,6 +77,13 @@ func LoadClientTLSConfig(serverName string, insecureSkipVerify bool) (*tls.Confi
 		if err != nil {
 			return nil, fmt.Errorf("failed to load system cert pool: %w", err)
 		}
+		if caFile != "" {
+			bundle, _, err := LoadTrustBundle(caFile)
+			if err != nil {
+				return nil, err
+			}
+			rootCAs.AppendCertsFromPEM(bundle)
+		}
 		tlsConfig.RootCAs = rootCAs
 	}
 
@@ -206,6 +237,26 @@ func ValidateCertificate(certFile string) error {
 	return nil
 }
 
+// CertificateExpiry returns the expiry time of a PEM certificate file
+func CertificateExpiry(certFile string) (time.Time, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("failed to decode PEM block")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return cert.NotAfter, nil
+}
+
 // VerifyCertificateChain is kept for backward compatibility but not used in one-way TLS
 // In one-way TLS, client uses system root CAs to verify server certificate
 func VerifyCertificateChain(certFile, caFile string) error {
@@ -0,0 +1,221 @@
+// Package errcode defines the stable EALxxxx error codes the server
+// attaches to gRPC statuses and the agent shows in logs, events and
+// control socket responses, for tooling and support to key off rather
+// than English messages. Codes are never reused or renumbered.
+package errcode
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// Code is a stable error code, "EAL" and four digits whose first tells
+// the area
+type Code string
+
+// EAL1xxx: authentication and enrollment
+const (
+	AuthFailed       Code = "EAL1001"
+	PendingApproval  Code = "EAL1002"
+	AgentArchived    Code = "EAL1003"
+	ClockSkew        Code = "EAL1004"
+	ReplayedRequest  Code = "EAL1005"
+	PermissionDenied Code = "EAL1006"
+	IdentityRejected Code = "EAL1007"
+)
+
+// EAL2xxx: sessions
+const (
+	ServerBusy      Code = "EAL2001"
+	SessionEnded    Code = "EAL2002"
+	SessionNotFound Code = "EAL2003"
+	QuotaExceeded   Code = "EAL2004"
+	Unsupported     Code = "EAL2005"
+	ServerStandby   Code = "EAL2006"
+	Incompatible    Code = "EAL2007"
+)
+
+// EAL3xxx: transport
+const (
+	ServerUnreachable    Code = "EAL3001"
+	CertificateUntrusted Code = "EAL3002"
+	StreamFailed         Code = "EAL3003"
+	TargetUnreachable    Code = "EAL3004"
+)
+
+// EAL4xxx: the system the agent runs on
+const (
+	TUNFailed        Code = "EAL4001"
+	RouteFailed      Code = "EAL4002"
+	DNSFailed        Code = "EAL4003"
+	KillSwitchFailed Code = "EAL4004"
+	SubsystemFailed  Code = "EAL4005"
+	NotReady         Code = "EAL4006"
+)
+
+// EAL5xxx: requests
+const (
+	InvalidArgument Code = "EAL5001"
+	NotFound        Code = "EAL5002"
+	Conflict        Code = "EAL5003"
+)
+
+// EAL9xxx: server faults
+const (
+	Internal Code = "EAL9001"
+)
+
+// descriptions are the English descriptions of the codes
+var descriptions = map[Code]string{
+	AuthFailed:           "authentication failed, check the user key",
+	PendingApproval:      "the agent awaits approval by an admin",
+	AgentArchived:        "the agent was archived by an admin",
+	ClockSkew:            "the clock of the agent is off the server clock",
+	ReplayedRequest:      "the registration was altered or replayed on the way",
+	PermissionDenied:     "not permitted for this user or agent",
+	IdentityRejected:     "the hardware identity key of the agent is missing or not the one it is bound to",
+	ServerBusy:           "the server reached its session limit",
+	SessionEnded:         "the server ended the session",
+	SessionNotFound:      "the session is unknown or expired, register again",
+	QuotaExceeded:        "a quota of the user is used up",
+	Unsupported:          "the server does not support the request",
+	ServerStandby:        "the server is on HA standby",
+	Incompatible:         "the protocol versions of agent and server are incompatible",
+	ServerUnreachable:    "the server cannot be reached",
+	CertificateUntrusted: "the server certificate is not trusted",
+	StreamFailed:         "a stream to the server failed",
+	TargetUnreachable:    "the target cannot be reached",
+	TUNFailed:            "the TUN interface could not be set up",
+	RouteFailed:          "a route could not be changed",
+	DNSFailed:            "the DNS settings could not be changed",
+	KillSwitchFailed:     "the kill switch could not be changed",
+	SubsystemFailed:      "a subsystem of the agent failed",
+	NotReady:             "the gateway is not ready",
+	InvalidArgument:      "the request is invalid",
+	NotFound:             "not found",
+	Conflict:             "conflicts with the current state",
+	Internal:             "internal error of the server",
+}
+
+// Description returns the English description of c, for clients without
+// a translation of their own
+func (c Code) Description() string {
+	return descriptions[c]
+}
+
+// Codes returns all codes with their descriptions
+func Codes() map[Code]string {
+	all := make(map[Code]string, len(descriptions))
+	for code, description := range descriptions {
+		all[code] = description
+	}
+	return all
+}
+
+// Error is an error with a code. It reads "EALxxxx: " and the error, so
+// log lines carry the code.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string { return string(e.Code) + ": " + e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// Wrap gives err a code, the one err already has, such as from the status
+// of the server, or code. A nil err stays nil.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return err
+	}
+	if own := Of(err); own != "" {
+		code = own
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Status returns a gRPC status error with an ErrorDetail of code
+func Status(c codes.Code, code Code, format string, args ...interface{}) error {
+	return WithCode(status.New(c, fmt.Sprintf(format, args...)), code).Err()
+}
+
+// WithCode returns st with an ErrorDetail of code, st if it has one
+func WithCode(st *status.Status, code Code) *status.Status {
+	for _, detail := range st.Details() {
+		if _, ok := detail.(*proto.ErrorDetail); ok {
+			return st
+		}
+	}
+	if withDetail, err := st.WithDetails(&proto.ErrorDetail{Code: string(code)}); err == nil {
+		return withDetail
+	}
+	return st
+}
+
+// Of returns the code of err: its own, the ErrorDetail of a gRPC status,
+// or the code that fits its gRPC code or certificate error. It is empty
+// for errors without one.
+func Of(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+
+	st, ok := status.FromError(err)
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *proto.ErrorDetail:
+			return Code(detail.Code)
+		case *proto.ServerBusy:
+			return ServerBusy
+		case *proto.SessionEnded:
+			return SessionEnded
+		}
+	}
+
+	// Dialers pass on certificate errors as text
+	var verify *tls.CertificateVerificationError
+	if errors.As(err, &verify) || strings.Contains(err.Error(), "x509: ") {
+		return CertificateUntrusted
+	}
+	if !ok {
+		return ""
+	}
+	switch st.Code() {
+	case codes.Unauthenticated:
+		return AuthFailed
+	case codes.PermissionDenied:
+		return PermissionDenied
+	case codes.ResourceExhausted:
+		return QuotaExceeded
+	case codes.Aborted:
+		return SessionEnded
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return ServerUnreachable
+	case codes.Unimplemented:
+		return Unsupported
+	case codes.InvalidArgument, codes.OutOfRange:
+		return InvalidArgument
+	case codes.NotFound:
+		return NotFound
+	case codes.AlreadyExists, codes.FailedPrecondition:
+		return Conflict
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		return Internal
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+func TestOf(t *testing.T) {
+	busy, _ := status.New(codes.ResourceExhausted, "busy").WithDetails(&proto.ServerBusy{})
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, ""},
+		{"plain", errors.New("boom"), ""},
+		{"coded", fmt.Errorf("connect: %w", &Error{Code: TUNFailed, Err: errors.New("boom")}), TUNFailed},
+		{"detail", Status(codes.Unauthenticated, ClockSkew, "clock skew"), ClockSkew},
+		{"busy", busy.Err(), ServerBusy},
+		{"grpc code", status.Error(codes.Unauthenticated, "invalid user key"), AuthFailed},
+		{"certificate", errors.New("tls: x509: certificate signed by unknown authority"), CertificateUntrusted},
+	}
+	for _, tt := range tests {
+		if got := Of(tt.err); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	err := Wrap(StreamFailed, status.Error(codes.Unavailable, "connection refused"))
+	if err.Error() != "EAL3001: rpc error: code = Unavailable desc = connection refused" {
+		t.Errorf("got %q, want the code of the status", err)
+	}
+	if again := Wrap(RouteFailed, err); again != err {
+		t.Errorf("wrapping a coded error again gave %q", again)
+	}
+	if err := Wrap(DNSFailed, errors.New("resolvectl failed")); Of(err) != DNSFailed {
+		t.Errorf("got %q, want %s", err, DNSFailed)
+	}
+	if Wrap(DNSFailed, nil) != nil {
+		t.Error("wrapping nil gave an error")
+	}
+}
+
+func TestDescriptions(t *testing.T) {
+	for code, description := range Codes() {
+		if len(code) != 7 || code[:3] != "EAL" || description == "" {
+			t.Errorf("malformed code %q: %q", code, description)
+		}
+	}
+}
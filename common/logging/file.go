@@ -0,0 +1,191 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// rotatedStamp suffixes rotated files, sorting by the time they were
+// rotated
+const rotatedStamp = "20060102-150405.000"
+
+// rotatingFile is a log file moved aside once it grows past a size or gets
+// too old. Rotated files are compressed and pruned in the background. If
+// moving the file fails, as for an agent that dropped the privileges to
+// its directory, it is appended to until the next rotation is due.
+type rotatingFile struct {
+	path     string
+	maxSize  int64
+	interval time.Duration // 0 rotates by size only
+	maxFiles int
+	maxAge   time.Duration // 0 keeps rotated files of any age
+	compress bool
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+
+	cleanMu sync.Mutex // one cleanup at a time
+}
+
+func newRotatingFile(path string, cfg config.LogRotation) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:     path,
+		maxSize:  int64(cfg.MaxSizeMB) << 20,
+		interval: time.Duration(cfg.IntervalHours) * time.Hour,
+		maxFiles: cfg.MaxFiles,
+		maxAge:   time.Duration(cfg.MaxAgeDays) * 24 * time.Hour,
+		compress: cfg.Compress,
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	// Compress and prune what earlier runs left
+	go f.cleanup()
+	return f, nil
+}
+
+// open opens the log file for appending
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	f.file, f.size, f.opened = file, info.Size(), time.Now()
+	return nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Reopen the file after a rotation failed to, e.g. once its directory
+	// is back; until then lines go to stderr
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return os.Stderr.Write(p)
+		}
+	}
+
+	due := f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize
+	due = due || f.interval > 0 && time.Since(f.opened) >= f.interval
+	if due {
+		if err := f.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to rotate %s: %v\n", f.path, err)
+			// Try again once another rotation is due
+			f.size, f.opened = 0, time.Now()
+		}
+		if f.file == nil {
+			return os.Stderr.Write(p)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate moves the log file aside and opens a new one. If that fails the
+// file is left closed.
+func (f *rotatingFile) rotate() error {
+	f.file.Close()
+	f.file = nil
+	rotated := f.path + "." + time.Now().Format(rotatedStamp)
+	renameErr := os.Rename(f.path, rotated)
+	// Keep logging to the old file if it could not be moved
+	if err := f.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return renameErr
+	}
+	go f.cleanup()
+	return nil
+}
+
+// cleanup compresses rotated files and deletes those beyond the retention
+// count or age
+func (f *rotatingFile) cleanup() {
+	f.cleanMu.Lock()
+	defer f.cleanMu.Unlock()
+
+	dir, base := filepath.Split(f.path)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var rotated []string
+	for _, entry := range entries {
+		if name := entry.Name(); strings.HasPrefix(name, base+".") && !entry.IsDir() {
+			stamp := strings.TrimSuffix(strings.TrimPrefix(name, base+"."), ".gz")
+			if _, err := time.ParseInLocation(rotatedStamp, stamp, time.Local); err == nil {
+				rotated = append(rotated, name)
+			}
+		}
+	}
+	// Newest first, the stamps sort by time
+	sort.Sort(sort.Reverse(sort.StringSlice(rotated)))
+
+	for i, name := range rotated {
+		path := filepath.Join(dir, name)
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, base+"."), ".gz")
+		at, _ := time.ParseInLocation(rotatedStamp, stamp, time.Local)
+		if (f.maxFiles > 0 && i >= f.maxFiles) || (f.maxAge > 0 && time.Since(at) > f.maxAge) {
+			os.Remove(path)
+			continue
+		}
+		if f.compress && !strings.HasSuffix(name, ".gz") {
+			if err := compressFile(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to compress %s: %v\n", path, err)
+			}
+		}
+	}
+}
+
+// compressFile replaces a file with its gzipped copy
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	in.Close()
+	return os.Remove(path)
+}
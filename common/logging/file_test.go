@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "agent.log")
+	f, err := newRotatingFile(path, config.LogRotation{MaxSizeMB: 1, MaxFiles: 2, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1 KiB lines, four rotations
+	line := []byte(strings.Repeat("x", 1023) + "\n")
+	for i := 0; i < 4*1024+10; i++ {
+		if _, err := f.Write(line); err != nil {
+			t.Fatal(err)
+		}
+		if i%1024 == 0 {
+			time.Sleep(2 * time.Millisecond) // rotated files are named by the millisecond
+		}
+	}
+	f.cleanup() // after those of the rotations
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if len(names) != 3 || names[0] != "agent.log" {
+		t.Fatalf("got files %v, want agent.log and two rotated", names)
+	}
+	for _, name := range names[1:] {
+		if !strings.HasPrefix(name, "agent.log.") || !strings.HasSuffix(name, ".gz") {
+			t.Errorf("rotated file %s not compressed", name)
+		}
+	}
+	if info, _ := os.Stat(path); info.Size() != 10*1024 {
+		t.Errorf("agent.log has %d bytes, want the 10 lines after the last rotation", info.Size())
+	}
+}
+
+func TestRotatingFileReopens(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	path := filepath.Join(dir, "agent.log")
+	f, err := newRotatingFile(path, config.LogRotation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.maxSize = 100
+
+	line := []byte(strings.Repeat("x", 63) + "\n")
+	if _, err := f.Write(line); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rotation fails while the directory cannot be written, which a
+	// missing directory also does for root
+	if err := os.Rename(dir, dir+".away"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(line); err != nil {
+		t.Fatalf("write while rotation fails: %v", err)
+	}
+	if f.file != nil {
+		t.Fatal("log file still open after the rotation failed")
+	}
+	if err := os.Rename(dir+".away", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	after := []byte("after\n")
+	if _, err := f.Write(after); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "after\n") {
+		t.Errorf("agent.log has %q, want the line written once the directory is back", data)
+	}
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// journaldSocket is where journald takes entries in its native protocol
+const journaldSocket = "/run/systemd/journal/socket"
+
+// journaldWriter sends each log line to journald as an entry with its
+// priority and identifier. A line journald cannot take goes to stderr,
+// which systemd also hands to journald for a service.
+type journaldWriter struct {
+	conn       *net.UnixConn
+	identifier string
+}
+
+func newJournaldWriter(identifier string) (*journaldWriter, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journaldSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to journald: %w", err)
+	}
+	return &journaldWriter{conn: conn, identifier: identifier}, nil
+}
+
+func (w *journaldWriter) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), "\n")
+	var entry bytes.Buffer
+	journaldField(&entry, "MESSAGE", line)
+	journaldField(&entry, "PRIORITY", strconv.Itoa(severity(line)))
+	journaldField(&entry, "SYSLOG_IDENTIFIER", w.identifier)
+	journaldField(&entry, "SYSLOG_PID", strconv.Itoa(os.Getpid()))
+	if _, err := w.conn.Write(entry.Bytes()); err != nil {
+		return os.Stderr.Write(p)
+	}
+	return len(p), nil
+}
+
+// journaldField appends a field to an entry, length-prefixed if the value
+// spans lines
+func journaldField(entry *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(entry, "%s=%s\n", name, value)
+		return
+	}
+	entry.WriteString(name + "\n")
+	binary.Write(entry, binary.LittleEndian, uint64(len(value)))
+	entry.WriteString(value + "\n")
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package logging
+
+import (
+	"fmt"
+	"io"
+)
+
+func newJournaldWriter(identifier string) (io.Writer, error) {
+	return nil, fmt.Errorf("journald is only available on Linux")
+}
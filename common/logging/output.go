@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// Syslog severities of log lines
+const (
+	severityError   = 3
+	severityWarning = 4
+	severityInfo    = 6
+)
+
+// Setup sends the standard logger to the output of cfg, redacted per its
+// policy with the secret values of the configuration conf masked wherever
+// they appear. Syslog and journald timestamp lines themselves, a log file
+// is rotated per cfg.Rotate.
+func Setup(cfg config.LogConfig, conf interface{}) error {
+	r, err := NewRedactor(cfg.Redact, Secrets(conf)...)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stderr
+	switch cfg.Output {
+	case "file":
+		file, err := newRotatingFile(cfg.File, cfg.Rotate)
+		if err != nil {
+			return err
+		}
+		out = file
+		// Run in a terminal, lines show there too
+		if info, err := os.Stderr.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			out = io.MultiWriter(file, os.Stderr)
+		}
+	case "syslog":
+		out = newSyslogWriter(cfg.Syslog, appName(cfg.Syslog.Tag))
+		log.SetFlags(0)
+	case "journald":
+		if out, err = newJournaldWriter(appName("")); err != nil {
+			return err
+		}
+		log.SetFlags(0)
+	}
+	log.SetOutput(r.Writer(out))
+	return nil
+}
+
+// appName is tag, or the name of the binary without one
+func appName(tag string) string {
+	if tag != "" {
+		return tag
+	}
+	return strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+}
+
+// severity guesses the severity of a log line from how the code base
+// words them: "Warning: ..." and "Failed to ..."
+func severity(line string) int {
+	switch {
+	case strings.HasPrefix(line, "Warning"):
+		return severityWarning
+	case strings.HasPrefix(line, "Failed"), strings.HasPrefix(line, "Error"):
+		return severityError
+	}
+	return severityInfo
+}
@@ -0,0 +1,191 @@
+// Package logging masks secrets and IP addresses in log output per the
+// redaction policy of the configuration
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// redacted replaces secret values
+const redacted = "REDACTED"
+
+// minSecretLen is the shortest configured secret masked wherever it
+// appears, shorter ones would mangle unrelated text
+const minSecretLen = 6
+
+// secretKeys are substrings of configuration keys whose values are secret
+var secretKeys = []string{"password", "secret", "psk", "user_key", "api_key", "token"}
+
+// secretRules mask secrets by their form, keeping enough to tell them
+// apart where that is harmless
+var secretRules = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// password=..., "token": "...", api_key=... in URLs, JSON and errors
+	{regexp.MustCompile(`(?i)\b(password|passwd|secret|token|api[_-]?key|user[_-]?key|credential)(\s*=\s*"?|"\s*:\s*")[^\s"&,;}]+`), "${1}${2}" + redacted},
+	{regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9._~+/=-]+`), "${1}" + redacted},
+	// API keys are 64 hex digits
+	{regexp.MustCompile(`\b([0-9a-fA-F]{4})[0-9a-fA-F]{60}\b`), "${1}***"},
+	// A session ID authenticates the data streams of its session
+	{regexp.MustCompile(`(?i)\b(session:?\s+)([0-9a-f]{8})(-[0-9a-f]{4}){3}-[0-9a-f]{12}\b`), "${1}${2}-***"},
+}
+
+var (
+	ipv4Candidate = regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`)
+	// Dots let IPv4-mapped addresses parse whole
+	ipv6Candidate = regexp.MustCompile(`[0-9a-fA-F:]*:[0-9a-fA-F:.]+`)
+	cgnat         = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// Redactor masks secrets and addresses in log lines
+type Redactor struct {
+	showSecrets bool
+	secrets     []string // configured secret values
+	addresses   string   // "", "public" or "all"
+	patterns    []*regexp.Regexp
+}
+
+// NewRedactor returns a redactor applying policy, masking the given secret
+// values wherever they appear unless the policy shows secrets
+func NewRedactor(policy config.RedactConfig, secrets ...string) (*Redactor, error) {
+	r := &Redactor{showSecrets: policy.ShowSecrets, addresses: policy.Addresses}
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLen {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+	for _, pattern := range policy.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Redact returns s with secrets, addresses and matches of the configured
+// patterns masked
+func (r *Redactor) Redact(s string) string {
+	if !r.showSecrets {
+		for _, secret := range r.secrets {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+		for _, rule := range secretRules {
+			s = rule.re.ReplaceAllString(s, rule.repl)
+		}
+	}
+	if r.addresses != "" {
+		s = ipv6Candidate.ReplaceAllStringFunc(s, r.maskAddress)
+		s = ipv4Candidate.ReplaceAllStringFunc(s, r.maskAddress)
+	}
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, redacted)
+	}
+	return s
+}
+
+// maskAddress masks the host part of an address the policy covers,
+// keeping its /24 or /48 network. Anything that does not parse as an
+// address, such as a time of day, is kept.
+func (r *Redactor) maskAddress(candidate string) string {
+	trimmed := strings.TrimRight(candidate, ".:")
+	addr, err := netip.ParseAddr(trimmed)
+	if err != nil {
+		return candidate
+	}
+	if r.addresses == "public" && !isPublic(addr.Unmap()) {
+		return candidate
+	}
+	suffix := candidate[len(trimmed):]
+	if addr.Is4In6() {
+		return "::ffff:" + maskIPv4(addr.Unmap()) + suffix
+	}
+	if addr.Is4() {
+		return maskIPv4(addr) + suffix
+	}
+	network, _ := addr.Prefix(48)
+	return strings.TrimSuffix(network.Addr().String(), "::") + "::x" + suffix
+}
+
+// maskIPv4 keeps the /24 of an IPv4 address
+func maskIPv4(addr netip.Addr) string {
+	return strings.Join(strings.Split(addr.String(), ".")[:3], ".") + ".x"
+}
+
+// isPublic reports whether addr is routed on the Internet, and so may tell
+// where a user is
+func isPublic(addr netip.Addr) bool {
+	return !(addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() ||
+		addr.IsUnspecified() || addr.IsMulticast() || cgnat.Contains(addr))
+}
+
+// Writer returns a writer redacting what it writes to w. The standard
+// logger writes a line at a time.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &redactWriter{r: r, w: w}
+}
+
+type redactWriter struct {
+	r *Redactor
+	w io.Writer
+}
+
+func (w *redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, w.r.Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Secrets returns the values of the secret keys of a configuration,
+// anything encoding to a JSON document
+func Secrets(conf interface{}) []string {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return nil
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	var secrets []string
+	var walk func(v interface{}, key string)
+	walk = func(v interface{}, key string) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				walk(child, k)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child, key)
+			}
+		case string:
+			// token_file and the like name where a secret is kept
+			if v != "" && IsSecretKey(key) && !strings.HasSuffix(key, "_file") {
+				secrets = append(secrets, v)
+			}
+		}
+	}
+	walk(doc, "")
+	return secrets
+}
+
+// IsSecretKey reports whether a configuration key holds a secret
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
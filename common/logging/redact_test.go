@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+const testAPIKey = "3f2a91c07d5e4b8a9c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b"
+
+func TestRedactSecrets(t *testing.T) {
+	r, err := NewRedactor(config.RedactConfig{}, "hunter2-db", "short")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in, want string
+	}{
+		{"Authentication failed for user key " + testAPIKey, "Authentication failed for user key 3f2a***"},
+		{"dial: password=hunter2 refused", "dial: password=REDACTED refused"},
+		{`{"user_key": "abc123", "mode": "client"}`, `{"user_key": "REDACTED", "mode": "client"}`},
+		{"GET /v1?api_key=abc&x=1", "GET /v1?api_key=REDACTED&x=1"},
+		{"Authorization: Bearer eyJhbGciOi.x-y_z", "Authorization: Bearer REDACTED"},
+		{"Registration successful, session: 1b2c3d4e-5f60-4172-8394-a5b6c7d8e9f0, IP: 10.200.0.5",
+			"Registration successful, session: 1b2c3d4e-***, IP: 10.200.0.5"},
+		{"Agent 1b2c3d4e-5f60-4172-8394-a5b6c7d8e9f0 connected", "Agent 1b2c3d4e-5f60-4172-8394-a5b6c7d8e9f0 connected"},
+		{"connecting to db with hunter2-db", "connecting to db with REDACTED"},
+		{"a short story", "a short story"},
+		{"Failed to write tray token: permission denied", "Failed to write tray token: permission denied"},
+	}
+	for _, tt := range tests {
+		if got := r.Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	shown, _ := NewRedactor(config.RedactConfig{ShowSecrets: true}, "hunter2-db")
+	if in := "password=hunter2-db " + testAPIKey; shown.Redact(in) != in {
+		t.Errorf("show_secrets masked %q", shown.Redact(in))
+	}
+}
+
+func TestRedactAddresses(t *testing.T) {
+	const line = "2026/10/16 12:34:56.123456 Agent connected from 203.0.113.7:40000, " +
+		"overlay 10.200.0.5, v6 [2001:db8:1:2::7]:443, mapped ::ffff:198.51.100.9, mac 02:42:ac:11:00:02."
+	tests := []struct {
+		addresses, want string
+	}{
+		{"", line},
+		{"public", "2026/10/16 12:34:56.123456 Agent connected from 203.0.113.x:40000, " +
+			"overlay 10.200.0.5, v6 [2001:db8:1::x]:443, mapped ::ffff:198.51.100.x, mac 02:42:ac:11:00:02."},
+		{"all", "2026/10/16 12:34:56.123456 Agent connected from 203.0.113.x:40000, " +
+			"overlay 10.200.0.x, v6 [2001:db8:1::x]:443, mapped ::ffff:198.51.100.x, mac 02:42:ac:11:00:02."},
+	}
+	for _, tt := range tests {
+		r, err := NewRedactor(config.RedactConfig{Addresses: tt.addresses})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Redact(line); got != tt.want {
+			t.Errorf("addresses %q:\n got %q\nwant %q", tt.addresses, got, tt.want)
+		}
+	}
+}
+
+func TestRedactPatterns(t *testing.T) {
+	r, err := NewRedactor(config.RedactConfig{Patterns: []string{`[a-z.]+@example\.com`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Redact("User alice.b@example.com created"); got != "User REDACTED created" {
+		t.Errorf("pattern not masked: %q", got)
+	}
+	if _, err := NewRedactor(config.RedactConfig{Patterns: []string{"("}}); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+}
+
+func TestSecrets(t *testing.T) {
+	cfg := &config.AgentConfig{UserKey: testAPIKey, Server: "vpn.example.com:8228"}
+	cfg.Tray = &config.TrayConfig{TokenFile: "/run/easyanylink/tray-token"}
+	secrets := Secrets(cfg)
+	if len(secrets) != 1 || secrets[0] != testAPIKey {
+		t.Errorf("Secrets = %v, want only the user key", secrets)
+	}
+	if strings.Contains(writeThrough(t, secrets), testAPIKey) {
+		t.Error("user key written unmasked")
+	}
+}
+
+// writeThrough writes the secrets through a redacting writer and returns
+// what it wrote
+func writeThrough(t *testing.T, secrets []string) string {
+	t.Helper()
+	r, err := NewRedactor(config.RedactConfig{}, secrets...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if _, err := r.Writer(&out).Write([]byte(strings.Join(secrets, " ") + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// syslogDialTimeout bounds connecting and writing to a syslog collector
+const syslogDialTimeout = 5 * time.Second
+
+// syslogQueueLen is the number of lines waiting for the collector; lines
+// beyond go to stderr
+const syslogQueueLen = 1024
+
+// A collector that cannot be reached is dialed again after a backoff that
+// doubles from syslogMinBackoff up to syslogMaxBackoff
+const (
+	syslogMinBackoff = time.Second
+	syslogMaxBackoff = time.Minute
+)
+
+// syslogWriter sends each log line to a remote collector as an RFC 5424
+// message, framed by octet counting over TCP (RFC 6587). Lines are queued
+// and sent in the background, so a slow or unreachable collector never
+// holds up the standard logger. A line the collector cannot take goes to
+// stderr instead, so nothing is lost while it is down.
+type syslogWriter struct {
+	network  string
+	address  string
+	facility int
+	hostname string
+	appName  string
+	queue    chan syslogLine
+
+	// Used by the sending goroutine only
+	conn    net.Conn // nil until connected, and after a failed write
+	backoff time.Duration
+	retryAt time.Time // no dial before
+}
+
+// syslogLine is a queued message and the log line it was made of
+type syslogLine struct {
+	msg  []byte
+	line []byte
+}
+
+func newSyslogWriter(cfg config.SyslogConfig, appName string) *syslogWriter {
+	w := &syslogWriter{
+		network:  cfg.Network,
+		address:  cfg.Address,
+		facility: config.SyslogFacilities["daemon"],
+		appName:  appName,
+		queue:    make(chan syslogLine, syslogQueueLen),
+	}
+	if w.network == "" {
+		w.network = "udp"
+	}
+	if facility, ok := config.SyslogFacilities[cfg.Facility]; ok {
+		w.facility = facility
+	}
+	if w.hostname, _ = os.Hostname(); w.hostname == "" {
+		w.hostname = "-"
+	}
+	go w.send()
+	return w
+}
+
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), "\n")
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d - - %s", w.facility*8+severity(line),
+		time.Now().Format("2006-01-02T15:04:05.000000Z07:00"), w.hostname, w.appName, os.Getpid(), line)
+	if w.network == "tcp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	// The standard logger reuses p
+	select {
+	case w.queue <- syslogLine{msg: []byte(msg), line: bytes.Clone(p)}:
+		return len(p), nil
+	default:
+		return os.Stderr.Write(p)
+	}
+}
+
+// send delivers the queued lines
+func (w *syslogWriter) send() {
+	for l := range w.queue {
+		if !w.deliver(l.msg) {
+			os.Stderr.Write(l.line)
+		}
+	}
+}
+
+// deliver writes a message to the collector, dialing again once if the
+// write fails on a connection the collector closed
+func (w *syslogWriter) deliver(msg []byte) bool {
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil && !w.dial() {
+			return false
+		}
+		w.conn.SetWriteDeadline(time.Now().Add(syslogDialTimeout))
+		if _, err := w.conn.Write(msg); err == nil {
+			return true
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	return false
+}
+
+// dial connects to the collector unless the backoff after a failed dial
+// is still running
+func (w *syslogWriter) dial() bool {
+	if time.Now().Before(w.retryAt) {
+		return false
+	}
+	conn, err := net.DialTimeout(w.network, w.address, syslogDialTimeout)
+	if err != nil {
+		w.backoff = min(max(2*w.backoff, syslogMinBackoff), syslogMaxBackoff)
+		w.retryAt = time.Now().Add(w.backoff)
+		return false
+	}
+	w.conn, w.backoff = conn, 0
+	if w.network == "tcp" {
+		go closeOnEOF(conn)
+	}
+	return true
+}
+
+// closeOnEOF closes a TCP connection once the collector closes it.
+// Collectors never send, so the read only returns then, and the next
+// write fails and dials again instead of vanishing into the connection.
+func closeOnEOF(conn net.Conn) {
+	var b [1]byte
+	conn.Read(b[:])
+	conn.Close()
+}
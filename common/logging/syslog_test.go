@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// rfc5424 matches a message of facility local3 from the test writer
+var rfc5424 = regexp.MustCompile(`^<(\d+)>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d) \S+ easyanylink-test \d+ - - (.*)$`)
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	w := newSyslogWriter(config.SyslogConfig{Address: conn.LocalAddr().String(), Facility: "local3"}, "easyanylink-test")
+
+	for _, tt := range []struct {
+		line, pri string
+	}{
+		{"Agent connected\n", "158"},                // local3.info
+		{"Warning: clock skew of 40s\n", "156"},     // local3.warning
+		{"Failed to write the stats file\n", "155"}, // local3.err
+	} {
+		if _, err := w.Write([]byte(tt.line)); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 2048)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := rfc5424.FindStringSubmatch(string(buf[:n]))
+		if m == nil || m[1] != tt.pri || m[3] != strings.TrimSuffix(tt.line, "\n") {
+			t.Errorf("got %q, want priority %s and message %q", buf[:n], tt.pri, tt.line)
+		}
+	}
+}
+
+func TestSyslogTCPReconnects(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	w := newSyslogWriter(config.SyslogConfig{Network: "tcp", Address: ln.Addr().String()}, "easyanylink-test")
+
+	// The collector restarts between the two lines
+	for _, line := range []string{"first", "second"} {
+		if _, err := w.Write([]byte(line + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		conn, err := ln.Accept()
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var length int
+		reader := bufio.NewReader(conn)
+		if _, err := fmt.Fscanf(reader, "%d ", &length); err != nil {
+			t.Fatal(err)
+		}
+		msg := make([]byte, length)
+		_, err = io.ReadFull(reader, msg)
+		conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m := rfc5424.FindStringSubmatch(string(msg)); m == nil || m[1] != "30" || m[3] != line {
+			t.Errorf("got %q, want daemon.info %q", msg, line)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestSyslogBacksOff(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := ln.Addr().String()
+	ln.Close()
+	w := newSyslogWriter(config.SyslogConfig{Network: "tcp", Address: address}, "easyanylink-test")
+
+	steps := []struct {
+		name    string
+		elapsed bool // the backoff has run out
+		want    time.Duration
+	}{
+		{"first failure", false, syslogMinBackoff},
+		{"within backoff", false, syslogMinBackoff},
+		{"second failure", true, 2 * syslogMinBackoff},
+		{"third failure", true, 4 * syslogMinBackoff},
+	}
+	for _, step := range steps {
+		if step.elapsed {
+			w.retryAt = time.Now()
+		}
+		if w.dial() {
+			t.Fatalf("%s: dialed a closed port", step.name)
+		}
+		if w.backoff != step.want {
+			t.Errorf("%s: got backoff %v, want %v", step.name, w.backoff, step.want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package packet
+
+import "hash/fnv"
+
+// Fingerprint identifies an IPv4 packet across routing hops: it hashes
+// the header fields a router keeps, the addresses, protocol, length and
+// identification, and the first 8 bytes of the payload, e.g. ports and
+// TCP sequence. The TTL, TOS and header checksum are left out, so a
+// packet that loops back into the relay has the fingerprint it had on
+// its way out.
+func Fingerprint(b []byte, h *IPv4) uint64 {
+	f := fnv.New64a()
+	f.Write(b[2:8]) // total length, identification, flags and fragment offset
+	f.Write([]byte{h.Protocol})
+	f.Write(b[12:20]) // source and destination addresses
+	payload := h.Payload
+	if len(payload) > 8 {
+		payload = payload[:8]
+	}
+	f.Write(payload)
+	return f.Sum64()
+}
@@ -0,0 +1,25 @@
+package packet
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	b := echoRequest([]byte("ping"))
+	h, _ := ParseIPv4(b)
+	fp := Fingerprint(b, h)
+
+	// A routed copy keeps its fingerprint
+	DecrementTTL(b)
+	DecrementTTL(b)
+	b[1] = 0xb8 // DSCP EF
+	if got := Fingerprint(b, h); got != fp {
+		t.Errorf("fingerprint changed by TTL and TOS: %x, want %x", got, fp)
+	}
+
+	// The next packet of the flow gets a new one
+	next := echoRequest([]byte("ping"))
+	next[5] = 1 // identification
+	h, _ = ParseIPv4(next)
+	if Fingerprint(next, h) == fp {
+		t.Error("packets with different identifications share a fingerprint")
+	}
+}
@@ -0,0 +1,120 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrBadFrame is returned for TUN frames that do not carry an IP packet
+var ErrBadFrame = errors.New("invalid TUN frame")
+
+// Framing is the header TUN devices of a platform put in front of IP
+// packets. The relay carries raw IP packets: Decap strips the header of
+// frames read from the device and Encap restores it for writes. A TUN
+// device is a layer 3 link, so frames of anything but IP, e.g. ARP, are
+// rejected rather than forwarded.
+type Framing uint8
+
+// TUN framings
+const (
+	FramingRaw  Framing = iota // no header: Linux with IFF_NO_PI, Wintun
+	FramingUtun                // 4-byte big-endian address family: darwin utun
+	FramingPI                  // struct tun_pi, 2-byte flags and EtherType: Linux without IFF_NO_PI
+)
+
+// Address families of the utun header and EtherTypes of the tun_pi header
+const (
+	utunAFInet  = 2  // AF_INET
+	utunAFInet6 = 30 // AF_INET6 on darwin
+
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+)
+
+// String returns the framing name
+func (f Framing) String() string {
+	switch f {
+	case FramingRaw:
+		return "raw"
+	case FramingUtun:
+		return "utun"
+	case FramingPI:
+		return "pi"
+	}
+	return "unknown"
+}
+
+// HeaderLen returns the length of the header in front of each packet
+func (f Framing) HeaderLen() int {
+	if f == FramingRaw {
+		return 0
+	}
+	return 4
+}
+
+// Decap returns the IP packet of a frame read from a TUN device, aliasing
+// frame. The header must match the IP version of the packet.
+func (f Framing) Decap(frame []byte) ([]byte, error) {
+	headerLen := f.HeaderLen()
+	if len(frame) <= headerLen {
+		return nil, fmt.Errorf("%w: %d byte %s frame", ErrBadFrame, len(frame), f)
+	}
+	packet := frame[headerLen:]
+	version := packet[0] >> 4
+	if version != 4 && version != 6 {
+		return nil, fmt.Errorf("%w: IP version %d", ErrBadFrame, version)
+	}
+
+	var want, got uint32
+	switch f {
+	case FramingUtun:
+		want, got = f.proto(version), binary.BigEndian.Uint32(frame)
+	case FramingPI:
+		want, got = f.proto(version), uint32(binary.BigEndian.Uint16(frame[2:4]))
+	}
+	if want != got {
+		return nil, fmt.Errorf("%w: %s header protocol %#x for IPv%d", ErrBadFrame, f, got, version)
+	}
+	return packet, nil
+}
+
+// Encap writes the frame of the IP packet to dst and returns its length.
+// dst must have room for the packet and the header.
+func (f Framing) Encap(dst, packet []byte) (int, error) {
+	if len(packet) == 0 {
+		return 0, fmt.Errorf("%w: empty packet", ErrBadFrame)
+	}
+	version := packet[0] >> 4
+	if version != 4 && version != 6 {
+		return 0, fmt.Errorf("%w: IP version %d", ErrBadFrame, version)
+	}
+	headerLen := f.HeaderLen()
+	if len(dst) < headerLen+len(packet) {
+		return 0, fmt.Errorf("packet too large: %d bytes", len(packet))
+	}
+
+	switch f {
+	case FramingUtun:
+		binary.BigEndian.PutUint32(dst, f.proto(version))
+	case FramingPI:
+		binary.BigEndian.PutUint16(dst[0:2], 0)
+		binary.BigEndian.PutUint16(dst[2:4], uint16(f.proto(version)))
+	}
+	return headerLen + copy(dst[headerLen:], packet), nil
+}
+
+// proto returns the header protocol value of an IP version
+func (f Framing) proto(version byte) uint32 {
+	switch {
+	case f == FramingUtun && version == 4:
+		return utunAFInet
+	case f == FramingUtun:
+		return utunAFInet6
+	case f == FramingPI && version == 4:
+		return etherTypeIPv4
+	case f == FramingPI:
+		return etherTypeIPv6
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package packet
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var framings = []Framing{FramingRaw, FramingUtun, FramingPI}
+
+func TestFramingRoundTrip(t *testing.T) {
+	ipv4 := echoRequest([]byte("ping"))
+	ipv6 := append([]byte{0x60, 0, 0, 0, 0, 0, 58, 255}, make([]byte, 32)...) // NDP would be ICMPv6 (58)
+
+	for _, f := range framings {
+		for _, p := range [][]byte{ipv4, ipv6} {
+			frame := make([]byte, f.HeaderLen()+len(p))
+			n, err := f.Encap(frame, p)
+			if err != nil {
+				t.Fatalf("%s: Encap: %v", f, err)
+			}
+			if n != len(frame) {
+				t.Fatalf("%s: Encap wrote %d bytes, want %d", f, n, len(frame))
+			}
+			got, err := f.Decap(frame[:n])
+			if err != nil {
+				t.Fatalf("%s: Decap: %v", f, err)
+			}
+			if !bytes.Equal(got, p) {
+				t.Fatalf("%s: Decap returned a different IPv%d packet", f, p[0]>>4)
+			}
+		}
+	}
+}
+
+func TestFramingHeaders(t *testing.T) {
+	p := echoRequest(nil)
+	for _, tc := range []struct {
+		f      Framing
+		header []byte
+	}{
+		{FramingUtun, []byte{0, 0, 0, 2}},
+		{FramingPI, []byte{0, 0, 0x08, 0x00}},
+	} {
+		frame := make([]byte, 4+len(p))
+		tc.f.Encap(frame, p)
+		if !bytes.Equal(frame[:4], tc.header) {
+			t.Errorf("%s: header %x, want %x", tc.f, frame[:4], tc.header)
+		}
+	}
+}
+
+func TestFramingRejects(t *testing.T) {
+	p := echoRequest(nil)
+	for _, tc := range []struct {
+		name  string
+		f     Framing
+		frame []byte
+	}{
+		{"empty raw", FramingRaw, nil},
+		{"raw non-IP", FramingRaw, []byte{0x00, 0x01, 0x08, 0x00}},
+		{"utun header only", FramingUtun, []byte{0, 0, 0, 2}},
+		{"utun IPv6 family for IPv4", FramingUtun, append([]byte{0, 0, 0, 30}, p...)},
+		{"utun unknown family", FramingUtun, append([]byte{0, 0, 0, 17}, p...)},
+		{"pi ARP", FramingPI, append([]byte{0, 0, 0x08, 0x06}, p...)},
+		{"pi short", FramingPI, []byte{0, 0}},
+	} {
+		if _, err := tc.f.Decap(tc.frame); !errors.Is(err, ErrBadFrame) {
+			t.Errorf("%s: Decap error %v, want ErrBadFrame", tc.name, err)
+		}
+	}
+
+	for _, f := range framings {
+		if _, err := f.Encap(make([]byte, 64), []byte{0x00, 0x01}); !errors.Is(err, ErrBadFrame) {
+			t.Errorf("%s: Encap accepted a non-IP packet", f)
+		}
+		if _, err := f.Encap(make([]byte, len(p)), p); err == nil && f != FramingRaw {
+			t.Errorf("%s: Encap overflowed the destination", f)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package packet
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// ICMP types and codes
+const (
+	ICMPEchoReply       = 0
+	ICMPUnreachable     = 3
+	ICMPEchoRequest     = 8
+	ICMPTimeExceeded    = 11
+	ICMPNetUnreachable  = 0  // code of ICMPUnreachable
+	ICMPHostUnreachable = 1  // code of ICMPUnreachable
+	ICMPAdminProhibited = 13 // code of ICMPUnreachable
+	ICMPTTLExceeded     = 0  // code of ICMPTimeExceeded
+)
+
+// IsEchoRequestTo reports whether b is an ICMP echo request addressed to ip
+func IsEchoRequestTo(b []byte, ip net.IP) bool {
+	h, err := ParseIPv4(b)
+	if err != nil || h.Protocol != ProtocolICMP || !h.Dst.Equal(ip) {
+		return false
+	}
+	return len(h.Payload) >= 8 && h.Payload[0] == ICMPEchoRequest
+}
+
+// EchoReply builds the echo reply to an ICMP echo request
+func EchoReply(request []byte) ([]byte, error) {
+	h, err := ParseIPv4(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(h.Payload) < 8 {
+		return nil, ErrTooShort
+	}
+
+	icmp := append([]byte(nil), h.Payload...)
+	icmp[0] = ICMPEchoReply
+	icmp[2], icmp[3] = 0, 0
+	binary.BigEndian.PutUint16(icmp[2:4], Checksum(icmp))
+
+	return BuildIPv4(h.Dst, h.Src, ProtocolICMP, icmp), nil
+}
+
+// ICMPError builds an ICMP error message from src about the original
+// packet, quoting its IP header and the first 8 bytes of its payload
+// (RFC 792). No error is generated about ICMP errors.
+func ICMPError(src net.IP, original []byte, icmpType, code uint8) ([]byte, error) {
+	h, err := ParseIPv4(original)
+	if err != nil {
+		return nil, err
+	}
+	if h.Protocol == ProtocolICMP && len(h.Payload) > 0 {
+		switch h.Payload[0] {
+		case ICMPEchoRequest, ICMPEchoReply:
+		default:
+			return nil, nil
+		}
+	}
+
+	quoted := h.HeaderLen + 8
+	if quoted > h.TotalLen {
+		quoted = h.TotalLen
+	}
+
+	icmp := make([]byte, 8+quoted)
+	icmp[0] = icmpType
+	icmp[1] = code
+	copy(icmp[8:], original[:quoted])
+	binary.BigEndian.PutUint16(icmp[2:4], Checksum(icmp))
+
+	return BuildIPv4(src, h.Src, ProtocolICMP, icmp), nil
+}
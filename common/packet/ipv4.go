@@ -0,0 +1,116 @@
+// Package packet provides minimal IPv4 and ICMP parsing and construction
+// for the relay data path
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+// IP protocol numbers
+const (
+	ProtocolICMP = 1
+	ProtocolTCP  = 6
+	ProtocolUDP  = 17
+)
+
+// IPv4HeaderLen is the length of an IPv4 header without options
+const IPv4HeaderLen = 20
+
+var (
+	ErrTooShort  = errors.New("packet too short")
+	ErrNotIPv4   = errors.New("not an IPv4 packet")
+	ErrBadHeader = errors.New("invalid IPv4 header length")
+	ErrBadTotLen = errors.New("invalid IPv4 total length")
+	ErrTooLarge  = errors.New("packet exceeds maximum size")
+)
+
+// IPv4 is a parsed view of an IPv4 header. Payload aliases the packet.
+type IPv4 struct {
+	HeaderLen int
+	TotalLen  int
+	TTL       uint8
+	Protocol  uint8
+	Src       net.IP
+	Dst       net.IP
+	Payload   []byte
+}
+
+// ParseIPv4 parses the IPv4 header of b
+func ParseIPv4(b []byte) (*IPv4, error) {
+	if len(b) < IPv4HeaderLen {
+		return nil, ErrTooShort
+	}
+	if b[0]>>4 != 4 {
+		return nil, ErrNotIPv4
+	}
+
+	headerLen := int(b[0]&0x0f) * 4
+	if headerLen < IPv4HeaderLen || headerLen > len(b) {
+		return nil, ErrBadHeader
+	}
+
+	totalLen := int(binary.BigEndian.Uint16(b[2:4]))
+	if totalLen < headerLen || totalLen > len(b) {
+		return nil, ErrBadTotLen
+	}
+
+	return &IPv4{
+		HeaderLen: headerLen,
+		TotalLen:  totalLen,
+		TTL:       b[8],
+		Protocol:  b[9],
+		Src:       net.IP(b[12:16]),
+		Dst:       net.IP(b[16:20]),
+		Payload:   b[headerLen:totalLen],
+	}, nil
+}
+
+// Validate checks that b is a well-formed IPv4 packet no larger than
+// maxLen bytes. A maxLen of zero disables the size check.
+func Validate(b []byte, maxLen int) (*IPv4, error) {
+	if maxLen > 0 && len(b) > maxLen {
+		return nil, ErrTooLarge
+	}
+	return ParseIPv4(b)
+}
+
+// DecrementTTL decrements the TTL of an IPv4 packet in place, updating the
+// header checksum incrementally (RFC 1624)
+func DecrementTTL(b []byte) {
+	b[8]--
+	sum := uint32(^binary.BigEndian.Uint16(b[10:12])) + uint32(^uint16(0x0100))
+	sum = (sum & 0xffff) + (sum >> 16)
+	sum = (sum & 0xffff) + (sum >> 16)
+	binary.BigEndian.PutUint16(b[10:12], ^uint16(sum))
+}
+
+// Checksum computes the Internet checksum of b
+func Checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i:]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
+
+// BuildIPv4 builds an IPv4 packet with a default TTL of 64
+func BuildIPv4(src, dst net.IP, protocol uint8, payload []byte) []byte {
+	b := make([]byte, IPv4HeaderLen+len(payload))
+	b[0] = 0x45
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+	b[8] = 64
+	b[9] = protocol
+	copy(b[12:16], src.To4())
+	copy(b[16:20], dst.To4())
+	binary.BigEndian.PutUint16(b[10:12], Checksum(b[:IPv4HeaderLen]))
+	copy(b[IPv4HeaderLen:], payload)
+	return b
+}
@@ -0,0 +1,160 @@
+package packet
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+var (
+	testSrc = net.IPv4(10, 200, 0, 2).To4()
+	testDst = net.IPv4(10, 200, 0, 3).To4()
+)
+
+// echoRequest builds an ICMP echo request with the given data
+func echoRequest(data []byte) []byte {
+	icmp := append([]byte{ICMPEchoRequest, 0, 0, 0, 0x12, 0x34, 0, 1}, data...)
+	sum := Checksum(icmp)
+	icmp[2], icmp[3] = byte(sum>>8), byte(sum)
+	return BuildIPv4(testSrc, testDst, ProtocolICMP, icmp)
+}
+
+// addSeeds adds well-formed and malformed packets to the fuzz corpus
+func addSeeds(f *testing.F) {
+	request := echoRequest([]byte("ping"))
+	f.Add(request)
+	f.Add(request[:IPv4HeaderLen])
+	f.Add(request[:IPv4HeaderLen-1])
+	f.Add(BuildIPv4(testSrc, testDst, ProtocolUDP, []byte{0, 53, 0, 53, 0, 8, 0, 0}))
+	f.Add(BuildIPv4(testSrc, testDst, ProtocolICMP, []byte{ICMPUnreachable, 0, 0, 0}))
+
+	// Header with options, and one claiming more options than bytes
+	options := append([]byte{0x46}, request[1:IPv4HeaderLen]...)
+	options = append(options, 1, 1, 1, 0)
+	f.Add(append(options, request[IPv4HeaderLen:]...))
+	f.Add(append([]byte{0x4f}, request[1:]...))
+	f.Add([]byte{0x60, 0, 0, 0})
+	f.Add([]byte{})
+}
+
+func FuzzParseIPv4(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		h, err := ParseIPv4(b)
+		if err != nil {
+			return
+		}
+		if h.HeaderLen < IPv4HeaderLen || h.HeaderLen > len(b) {
+			t.Fatalf("header length %d out of range for %d bytes", h.HeaderLen, len(b))
+		}
+		if h.TotalLen < h.HeaderLen || h.TotalLen > len(b) {
+			t.Fatalf("total length %d out of range for %d bytes", h.TotalLen, len(b))
+		}
+		if len(h.Payload) != h.TotalLen-h.HeaderLen {
+			t.Fatalf("payload length %d, want %d", len(h.Payload), h.TotalLen-h.HeaderLen)
+		}
+
+		if _, err := Validate(b, len(b)-1); len(b) > 0 && err != ErrTooLarge {
+			t.Fatalf("Validate accepted %d bytes over a limit of %d: %v", len(b), len(b)-1, err)
+		}
+		if _, err := Validate(b, len(b)); err != nil {
+			t.Fatalf("Validate rejected a parseable packet: %v", err)
+		}
+	})
+}
+
+func FuzzDecrementTTL(f *testing.F) {
+	f.Add(uint8(64), []byte("data"))
+	f.Add(uint8(1), []byte{})
+	f.Add(uint8(255), bytes.Repeat([]byte{0xff}, 64))
+	f.Fuzz(func(t *testing.T, ttl uint8, payload []byte) {
+		if ttl == 0 || len(payload) > 1500 {
+			return
+		}
+		b := BuildIPv4(testSrc, testDst, ProtocolUDP, payload)
+		b[8] = ttl
+		b[10], b[11] = 0, 0
+		sum := Checksum(b[:IPv4HeaderLen])
+		b[10], b[11] = byte(sum>>8), byte(sum)
+
+		DecrementTTL(b)
+		if b[8] != ttl-1 {
+			t.Fatalf("TTL %d, want %d", b[8], ttl-1)
+		}
+		if Checksum(b[:IPv4HeaderLen]) != 0 {
+			t.Fatalf("invalid header checksum after decrementing TTL %d", ttl)
+		}
+	})
+}
+
+func FuzzEchoReply(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		reply, err := EchoReply(b)
+		if err != nil {
+			return
+		}
+		request, _ := ParseIPv4(b)
+
+		h, err := ParseIPv4(reply)
+		if err != nil {
+			t.Fatalf("reply does not parse: %v", err)
+		}
+		if Checksum(reply[:h.HeaderLen]) != 0 {
+			t.Fatal("invalid reply header checksum")
+		}
+		if h.Protocol != ProtocolICMP || h.Payload[0] != ICMPEchoReply {
+			t.Fatalf("reply is protocol %d type %d, want an ICMP echo reply", h.Protocol, h.Payload[0])
+		}
+		if Checksum(h.Payload) != 0 {
+			t.Fatal("invalid ICMP checksum")
+		}
+		if !h.Src.Equal(request.Dst) || !h.Dst.Equal(request.Src) {
+			t.Fatalf("reply %s -> %s does not answer %s -> %s", h.Src, h.Dst, request.Src, request.Dst)
+		}
+		if !bytes.Equal(h.Payload[4:], request.Payload[4:]) {
+			t.Fatal("reply does not echo the identifier, sequence and data")
+		}
+	})
+}
+
+func FuzzICMPError(f *testing.F) {
+	addSeeds(f)
+	gateway := net.IPv4(10, 200, 0, 1).To4()
+	f.Fuzz(func(t *testing.T, b []byte) {
+		original := append([]byte(nil), b...)
+		msg, err := ICMPError(gateway, b, ICMPTimeExceeded, ICMPTTLExceeded)
+		if !bytes.Equal(b, original) {
+			t.Fatal("ICMPError modified the original packet")
+		}
+		if err != nil || msg == nil {
+			return
+		}
+		orig, _ := ParseIPv4(b)
+
+		h, err := ParseIPv4(msg)
+		if err != nil {
+			t.Fatalf("error message does not parse: %v", err)
+		}
+		if Checksum(msg[:h.HeaderLen]) != 0 {
+			t.Fatal("invalid header checksum")
+		}
+		if Checksum(h.Payload) != 0 {
+			t.Fatal("invalid ICMP checksum")
+		}
+		if !h.Src.Equal(gateway) || !h.Dst.Equal(orig.Src) {
+			t.Fatalf("error %s -> %s, want %s -> %s", h.Src, h.Dst, gateway, orig.Src)
+		}
+
+		quoted := h.Payload[8:]
+		if len(quoted) > orig.HeaderLen+8 || !bytes.Equal(quoted, b[:len(quoted)]) {
+			t.Fatalf("quoted %d bytes do not match the original header and 8 payload bytes", len(quoted))
+		}
+
+		// Never answer an ICMP error with another one
+		if orig.Protocol == ProtocolICMP && len(orig.Payload) > 0 &&
+			orig.Payload[0] != ICMPEchoRequest && orig.Payload[0] != ICMPEchoReply {
+			t.Fatalf("generated an error about ICMP type %d", orig.Payload[0])
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package packet
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// discoveryPorts are UDP ports of service discovery protocols sent to
+// multicast or broadcast addresses
+var discoveryPorts = map[uint16]bool{
+	137:  true, // NetBIOS name service
+	138:  true, // NetBIOS datagram service
+	1900: true, // SSDP
+	3702: true, // WS-Discovery
+	5353: true, // mDNS
+	5355: true, // LLMNR
+}
+
+// IsMulticast reports whether dst is an IPv4 multicast address or the
+// limited broadcast address
+func IsMulticast(dst net.IP) bool {
+	ip := dst.To4()
+	return ip != nil && (ip.IsMulticast() || ip.Equal(net.IPv4bcast))
+}
+
+// IsDiscovery reports whether h is a UDP packet to the port of a service
+// discovery protocol
+func IsDiscovery(h *IPv4) bool {
+	if h.Protocol != ProtocolUDP || len(h.Payload) < 8 {
+		return false
+	}
+	return discoveryPorts[binary.BigEndian.Uint16(h.Payload[2:4])]
+}
+
+// SetDestination rewrites the destination address of the IPv4 packet b in
+// place, updating the header checksum and the checksum of an unfragmented
+// UDP or TCP payload, which covers the address
+func SetDestination(b []byte, dst net.IP) error {
+	return setAddress(b, 16, dst)
+}
+
+// SetSource rewrites the source address of the IPv4 packet b in place like
+// SetDestination
+func SetSource(b []byte, src net.IP) error {
+	return setAddress(b, 12, src)
+}
+
+// setAddress rewrites the address at offset of the IPv4 header of b
+func setAddress(b []byte, offset int, addr net.IP) error {
+	h, err := ParseIPv4(b)
+	if err != nil {
+		return err
+	}
+	field := b[offset : offset+4]
+	old := [4]byte(field)
+	copy(field, addr.To4())
+
+	b[10], b[11] = 0, 0
+	binary.BigEndian.PutUint16(b[10:12], Checksum(b[:h.HeaderLen]))
+
+	// Later fragments carry no transport header
+	if binary.BigEndian.Uint16(b[6:8])&0x1fff != 0 {
+		return nil
+	}
+	var sumAt int
+	switch h.Protocol {
+	case ProtocolUDP:
+		sumAt = 6
+	case ProtocolTCP:
+		sumAt = 16
+	default:
+		return nil
+	}
+	if len(h.Payload) < sumAt+2 {
+		return nil
+	}
+
+	sumField := h.Payload[sumAt : sumAt+2]
+	sum := binary.BigEndian.Uint16(sumField)
+	if h.Protocol == ProtocolUDP && sum == 0 {
+		return nil // checksum not used
+	}
+	sum = updateChecksum(sum, old[:], field)
+	if h.Protocol == ProtocolUDP && sum == 0 {
+		sum = 0xffff
+	}
+	binary.BigEndian.PutUint16(sumField, sum)
+	return nil
+}
+
+// updateChecksum updates an Internet checksum for 16-bit aligned data
+// changed from old to new (RFC 1624)
+func updateChecksum(sum uint16, old, new []byte) uint16 {
+	acc := uint32(^sum)
+	for i := 0; i+1 < len(old); i += 2 {
+		acc += uint32(^binary.BigEndian.Uint16(old[i:]))
+		acc += uint32(binary.BigEndian.Uint16(new[i:]))
+	}
+	for acc>>16 != 0 {
+		acc = (acc & 0xffff) + (acc >> 16)
+	}
+	return ^uint16(acc)
+}
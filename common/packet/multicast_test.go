@@ -0,0 +1,109 @@
+package packet
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+)
+
+// udpPacket builds a UDP packet to port with a valid checksum
+func udpPacket(dst net.IP, port uint16, data []byte) []byte {
+	udp := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint16(udp[0:2], port)
+	binary.BigEndian.PutUint16(udp[2:4], port)
+	binary.BigEndian.PutUint16(udp[4:6], uint16(len(udp)))
+	copy(udp[8:], data)
+	b := BuildIPv4(testSrc, dst, ProtocolUDP, udp)
+	sum := Checksum(pseudoHeader(b, udp))
+	if sum == 0 {
+		sum = 0xffff
+	}
+	binary.BigEndian.PutUint16(b[IPv4HeaderLen+6:], sum)
+	return b
+}
+
+// pseudoHeader returns the UDP pseudo header of b followed by udp
+func pseudoHeader(b, udp []byte) []byte {
+	ph := make([]byte, 12, 12+len(udp))
+	copy(ph[0:8], b[12:20])
+	ph[9] = ProtocolUDP
+	binary.BigEndian.PutUint16(ph[10:12], uint16(len(udp)))
+	return append(ph, udp...)
+}
+
+func TestIsMulticast(t *testing.T) {
+	for _, tc := range []struct {
+		ip   net.IP
+		want bool
+	}{
+		{net.IPv4(224, 0, 0, 251), true},
+		{net.IPv4(239, 255, 255, 250), true},
+		{net.IPv4bcast, true},
+		{testDst, false},
+		{net.ParseIP("ff02::fb"), false},
+	} {
+		if got := IsMulticast(tc.ip); got != tc.want {
+			t.Errorf("IsMulticast(%s) = %t, want %t", tc.ip, got, tc.want)
+		}
+	}
+}
+
+func TestIsDiscovery(t *testing.T) {
+	mdns, _ := ParseIPv4(udpPacket(net.IPv4(224, 0, 0, 251), 5353, []byte("query")))
+	if !IsDiscovery(mdns) {
+		t.Error("mDNS query not recognized as discovery")
+	}
+	other, _ := ParseIPv4(udpPacket(net.IPv4(224, 0, 0, 251), 4000, []byte("data")))
+	if IsDiscovery(other) {
+		t.Error("UDP port 4000 recognized as discovery")
+	}
+}
+
+func FuzzSetDestination(f *testing.F) {
+	f.Add([]byte("M-SEARCH * HTTP/1.1"), uint16(1900))
+	f.Add([]byte{}, uint16(5353))
+	f.Add([]byte{0xff, 0xff, 0xff}, uint16(0))
+	f.Fuzz(func(t *testing.T, data []byte, port uint16) {
+		if len(data) > 1400 {
+			return
+		}
+		b := udpPacket(net.IPv4(239, 255, 255, 250).To4(), port, data)
+		if err := SetDestination(b, testDst); err != nil {
+			t.Fatalf("SetDestination: %v", err)
+		}
+		h, err := ParseIPv4(b)
+		if err != nil {
+			t.Fatalf("rewritten packet does not parse: %v", err)
+		}
+		if !h.Dst.Equal(testDst) {
+			t.Fatalf("destination %s, want %s", h.Dst, testDst)
+		}
+		if Checksum(b[:h.HeaderLen]) != 0 {
+			t.Fatal("invalid header checksum")
+		}
+		if Checksum(pseudoHeader(b, h.Payload)) != 0 {
+			t.Fatal("invalid UDP checksum")
+		}
+	})
+}
+
+func TestSetSource(t *testing.T) {
+	b := udpPacket(testDst, 5353, []byte("query"))
+	src := net.IPv4(10, 200, 0, 9).To4()
+	if err := SetSource(b, src); err != nil {
+		t.Fatal(err)
+	}
+	h, err := ParseIPv4(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.Src.Equal(src) || !h.Dst.Equal(testDst) {
+		t.Fatalf("addresses %s -> %s, want %s -> %s", h.Src, h.Dst, src, testDst)
+	}
+	if Checksum(b[:h.HeaderLen]) != 0 {
+		t.Error("invalid header checksum")
+	}
+	if Checksum(pseudoHeader(b, h.Payload)) != 0 {
+		t.Error("invalid UDP checksum")
+	}
+}
@@ -0,0 +1,188 @@
+package packet
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+)
+
+// Class is the traffic class of a packet, lower classes are sent first
+type Class uint8
+
+// Traffic classes
+const (
+	ClassInteractive Class = iota // DNS, SSH, remote desktop, VoIP and ICMP
+	ClassDefault
+	ClassBulk // marked lower effort or CS1
+
+	NumClasses = 3
+)
+
+// String returns the class name
+func (c Class) String() string {
+	switch c {
+	case ClassInteractive:
+		return "interactive"
+	case ClassDefault:
+		return "default"
+	case ClassBulk:
+		return "bulk"
+	}
+	return "unknown"
+}
+
+// DSCP code points used for classification
+const (
+	dscpLE   = 1  // lower effort, RFC 8622
+	dscpCS1  = 8  // low priority data
+	dscpAF41 = 34 // lowest code point of multimedia, real-time and control traffic
+)
+
+// interactivePorts are TCP and UDP ports of latency sensitive services
+var interactivePorts = map[uint16]bool{
+	22:   true, // SSH
+	53:   true, // DNS
+	123:  true, // NTP
+	3389: true, // RDP
+	5060: true, // SIP
+	5061: true, // SIP over TLS
+}
+
+// Classify returns the traffic class of an IPv4 packet. The DSCP marking
+// decides for marked packets, unmarked ones are classified by protocol and
+// port. Anything unparseable is default traffic.
+func Classify(b []byte) Class {
+	h, err := ParseIPv4(b)
+	if err != nil {
+		return ClassDefault
+	}
+
+	switch dscp := b[1] >> 2; {
+	case dscp == dscpLE || dscp == dscpCS1:
+		return ClassBulk
+	case dscp >= dscpAF41:
+		return ClassInteractive
+	case dscp != 0:
+		return ClassDefault
+	}
+
+	switch h.Protocol {
+	case ProtocolICMP:
+		return ClassInteractive
+	case ProtocolTCP, ProtocolUDP:
+		// Only the first fragment carries the ports
+		if binary.BigEndian.Uint16(b[6:8])&0x1fff != 0 || len(h.Payload) < 4 {
+			return ClassDefault
+		}
+		if interactivePorts[binary.BigEndian.Uint16(h.Payload[0:2])] ||
+			interactivePorts[binary.BigEndian.Uint16(h.Payload[2:4])] {
+			return ClassInteractive
+		}
+	}
+	return ClassDefault
+}
+
+// ClassStats are the counters of one traffic class
+type ClassStats struct {
+	Class   Class
+	Queued  uint64 // packets queued for sending
+	Dropped uint64 // packets dropped because the class queue was full
+}
+
+// ClassCounters counts queued and dropped packets per traffic class, it
+// can be shared by several queues
+type ClassCounters struct {
+	queued  [NumClasses]atomic.Uint64
+	dropped [NumClasses]atomic.Uint64
+}
+
+// Stats returns the counters of every class
+func (c *ClassCounters) Stats() []ClassStats {
+	stats := make([]ClassStats, NumClasses)
+	for i := range stats {
+		stats[i] = ClassStats{
+			Class:   Class(i),
+			Queued:  c.queued[i].Load(),
+			Dropped: c.dropped[i].Load(),
+		}
+	}
+	return stats
+}
+
+// ClassQueue holds a bounded FIFO queue per traffic class and serves them
+// in strict priority order, so interactive traffic overtakes bulk
+// transfers. Push and Pop may be called from different goroutines.
+type ClassQueue[T any] struct {
+	queues   [NumClasses]chan T
+	counters *ClassCounters
+	popped   atomic.Uint64
+}
+
+// NewClassQueue creates a queue of length items per class counting into
+// counters
+func NewClassQueue[T any](length int, counters *ClassCounters) *ClassQueue[T] {
+	q := &ClassQueue[T]{counters: counters}
+	for i := range q.queues {
+		q.queues[i] = make(chan T, length)
+	}
+	return q
+}
+
+// Push queues v in class c without blocking, reporting false when the
+// queue of the class is full
+func (q *ClassQueue[T]) Push(c Class, v T) bool {
+	select {
+	case q.queues[c] <- v:
+		q.counters.queued[c].Add(1)
+		return true
+	default:
+		q.counters.dropped[c].Add(1)
+		return false
+	}
+}
+
+// Pop returns the oldest item of the highest non-empty class, waiting
+// for one until done is closed
+func (q *ClassQueue[T]) Pop(done <-chan struct{}) (T, bool) {
+	v, ok := q.pop(done)
+	if ok {
+		q.popped.Add(1)
+	}
+	return v, ok
+}
+
+func (q *ClassQueue[T]) pop(done <-chan struct{}) (T, bool) {
+	for i := range q.queues {
+		select {
+		case v := <-q.queues[i]:
+			return v, true
+		default:
+		}
+	}
+
+	select {
+	case v := <-q.queues[ClassInteractive]:
+		return v, true
+	case v := <-q.queues[ClassDefault]:
+		return v, true
+	case v := <-q.queues[ClassBulk]:
+		return v, true
+	case <-done:
+		var zero T
+		return zero, false
+	}
+}
+
+// Len returns the number of queued items of all classes
+func (q *ClassQueue[T]) Len() int {
+	n := 0
+	for i := range q.queues {
+		n += len(q.queues[i])
+	}
+	return n
+}
+
+// Popped returns the number of items taken from the queue, telling
+// whether its consumer makes progress
+func (q *ClassQueue[T]) Popped() uint64 {
+	return q.popped.Load()
+}
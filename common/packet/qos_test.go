@@ -0,0 +1,77 @@
+package packet
+
+import "testing"
+
+// withDSCP returns a copy of b marked with dscp
+func withDSCP(b []byte, dscp uint8) []byte {
+	b = append([]byte(nil), b...)
+	b[1] = dscp << 2
+	return b
+}
+
+func TestClassify(t *testing.T) {
+	udp := func(src, dst uint16) []byte {
+		return BuildIPv4(testSrc, testDst, ProtocolUDP,
+			[]byte{byte(src >> 8), byte(src), byte(dst >> 8), byte(dst), 0, 8, 0, 0})
+	}
+	tcp := BuildIPv4(testSrc, testDst, ProtocolTCP, make([]byte, 20))
+
+	tests := []struct {
+		name string
+		b    []byte
+		want Class
+	}{
+		{"dns query", udp(40000, 53), ClassInteractive},
+		{"dns answer", udp(53, 40000), ClassInteractive},
+		{"unmarked udp", udp(40000, 8080), ClassDefault},
+		{"unmarked tcp", tcp, ClassDefault},
+		{"ping", echoRequest([]byte("ping")), ClassInteractive},
+		{"expedited forwarding", withDSCP(tcp, 46), ClassInteractive},
+		{"cs1", withDSCP(udp(40000, 53), 8), ClassBulk},
+		{"lower effort", withDSCP(tcp, 1), ClassBulk},
+		{"af11", withDSCP(udp(40000, 53), 10), ClassDefault},
+		{"truncated", []byte{0x45, 0}, ClassDefault},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.b); got != tt.want {
+			t.Errorf("%s: class %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClassQueue(t *testing.T) {
+	var counters ClassCounters
+	q := NewClassQueue[int](2, &counters)
+
+	q.Push(ClassBulk, 1)
+	q.Push(ClassDefault, 2)
+	q.Push(ClassInteractive, 3)
+	q.Push(ClassInteractive, 4)
+	if q.Push(ClassInteractive, 5) {
+		t.Fatal("pushed into a full class queue")
+	}
+
+	if q.Len() != 4 {
+		t.Fatalf("queue length %d, want 4", q.Len())
+	}
+
+	done := make(chan struct{})
+	for _, want := range []int{3, 4, 2, 1} {
+		if got, ok := q.Pop(done); !ok || got != want {
+			t.Fatalf("popped %d, want %d", got, want)
+		}
+	}
+
+	close(done)
+	if _, ok := q.Pop(done); ok {
+		t.Fatal("popped from an empty queue after done")
+	}
+	if q.Len() != 0 || q.Popped() != 4 {
+		t.Fatalf("queue length %d and %d popped, want 0 and 4", q.Len(), q.Popped())
+	}
+
+	stats := counters.Stats()
+	if stats[ClassInteractive].Queued != 2 || stats[ClassInteractive].Dropped != 1 {
+		t.Fatalf("interactive counters %+v, want 2 queued and 1 dropped", stats[ClassInteractive])
+	}
+}
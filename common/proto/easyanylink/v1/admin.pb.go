@@ -0,0 +1,4202 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: common/proto/easyanylink/v1/admin.proto
+
+// Version 1 of the API, frozen. It is the API of the releases before API
+// versioning, which the server also serves under the unversioned "proto"
+// package name. Changes go to easyanylink.v2.
+
+package easyanylinkv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AddRoutingRuleRequest creates a new routing rule
+type AddRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent the rule applies to, empty for a group rule
+	Rule          *RoutingRule           `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`                       // Rule definition (rule_id is ignored)
+	GroupId       int32                  `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group the rule applies to, 0 for an agent rule
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRoutingRuleRequest) Reset() {
+	*x = AddRoutingRuleRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRoutingRuleRequest) ProtoMessage() {}
+
+func (x *AddRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddRoutingRuleRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AddRoutingRuleRequest) GetRule() *RoutingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *AddRoutingRuleRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// UpdateRoutingRuleRequest replaces an existing routing rule
+type UpdateRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *RoutingRule           `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"` // Rule definition, identified by rule_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoutingRuleRequest) Reset() {
+	*x = UpdateRoutingRuleRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoutingRuleRequest) ProtoMessage() {}
+
+func (x *UpdateRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdateRoutingRuleRequest) GetRule() *RoutingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// RoutingRuleResponse returns the stored routing rule
+type RoutingRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent the rule applies to, empty for a group rule
+	Rule          *RoutingRule           `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`                       // Stored rule
+	GroupId       int32                  `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group the rule applies to, 0 for an agent rule
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoutingRuleResponse) Reset() {
+	*x = RoutingRuleResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoutingRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingRuleResponse) ProtoMessage() {}
+
+func (x *RoutingRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingRuleResponse.ProtoReflect.Descriptor instead.
+func (*RoutingRuleResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RoutingRuleResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RoutingRuleResponse) GetRule() *RoutingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *RoutingRuleResponse) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// DeleteRoutingRuleRequest deletes a routing rule
+type DeleteRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        int32                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"` // Rule identifier
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoutingRuleRequest) Reset() {
+	*x = DeleteRoutingRuleRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoutingRuleRequest) ProtoMessage() {}
+
+func (x *DeleteRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DeleteRoutingRuleRequest) GetRuleId() int32 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+// DeleteRoutingRuleResponse acknowledges rule deletion
+type DeleteRoutingRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`                // Rule was removed
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent the rule applied to, empty for a group rule
+	GroupId       int32                  `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group the rule applied to, 0 for an agent rule
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoutingRuleResponse) Reset() {
+	*x = DeleteRoutingRuleResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoutingRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoutingRuleResponse) ProtoMessage() {}
+
+func (x *DeleteRoutingRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoutingRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRoutingRuleResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteRoutingRuleResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *DeleteRoutingRuleResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *DeleteRoutingRuleResponse) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// ListAgentsRequest filters and paginates the agent registry
+type ListAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`                                                      // Max agents per page (default 50, max 500)
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                                                    // Token from a previous response
+	Type          AgentType              `protobuf:"varint,3,opt,name=type,proto3,enum=easyanylink.v1.AgentType" json:"type,omitempty"`                                                // Filter by type, unspecified for all
+	Status        AgentStatus            `protobuf:"varint,4,opt,name=status,proto3,enum=easyanylink.v1.AgentStatus" json:"status,omitempty"`                                          // Filter by status, unspecified for all
+	UserId        string                 `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                                             // Filter by owning user
+	Labels        map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Filter by metadata labels (all must match)
+	Archived      bool                   `protobuf:"varint,7,opt,name=archived,proto3" json:"archived,omitempty"`                                                                      // List archived agents instead of active ones
+	Pending       bool                   `protobuf:"varint,8,opt,name=pending,proto3" json:"pending,omitempty"`                                                                        // List agents awaiting approval instead of approved ones
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsRequest) Reset() {
+	*x = ListAgentsRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsRequest) ProtoMessage() {}
+
+func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListAgentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAgentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListAgentsRequest) GetType() AgentType {
+	if x != nil {
+		return x.Type
+	}
+	return AgentType_AGENT_TYPE_UNSPECIFIED
+}
+
+func (x *ListAgentsRequest) GetStatus() AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
+func (x *ListAgentsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListAgentsRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ListAgentsRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *ListAgentsRequest) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+// ListAgentsResponse returns a page of agents
+type ListAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*AgentDetail         `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`                                      // Agents in this page
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty when there are no more pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsResponse) Reset() {
+	*x = ListAgentsResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsResponse) ProtoMessage() {}
+
+func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListAgentsResponse) GetAgents() []*AgentDetail {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *ListAgentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// GetAgentRequest identifies a single agent
+type GetAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentRequest) Reset() {
+	*x = GetAgentRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentRequest) ProtoMessage() {}
+
+func (x *GetAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// AgentDetail describes an agent in the server registry
+type AgentDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                 // Agent UUID
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                    // Owning user
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                                      // Human-readable name
+	Type          AgentType              `protobuf:"varint,4,opt,name=type,proto3,enum=easyanylink.v1.AgentType" json:"type,omitempty"`       // Client or Gateway
+	Status        AgentStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=easyanylink.v1.AgentStatus" json:"status,omitempty"` // Last reported status
+	IpAddress     string                 `protobuf:"bytes,6,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`           // Assigned overlay IP
+	PublicIp      string                 `protobuf:"bytes,7,opt,name=public_ip,json=publicIp,proto3" json:"public_ip,omitempty"`              // Public IP address
+	Metadata      *AgentMetadata         `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`                              // Platform and label information
+	Stats         *AgentStats            `protobuf:"bytes,9,opt,name=stats,proto3" json:"stats,omitempty"`                                    // Latest stats of the live session
+	LastSeen      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`             // Last heartbeat or activity
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`          // Registration time
+	SessionId     string                 `protobuf:"bytes,12,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`          // Live session, empty if disconnected
+	Connected     bool                   `protobuf:"varint,13,opt,name=connected,proto3" json:"connected,omitempty"`                          // Agent has a live session
+	ArchivedAt    *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`       // Archive time, unset if active
+	Pending       bool                   `protobuf:"varint,15,opt,name=pending,proto3" json:"pending,omitempty"`                              // Agent awaits approval and cannot connect
+	Health        *AgentHealth           `protobuf:"bytes,16,opt,name=health,proto3" json:"health,omitempty"`                                 // Failing subsystems reported by the live session
+	Group         string                 `protobuf:"bytes,17,opt,name=group,proto3" json:"group,omitempty"`                                   // Name of the agent's group, empty for none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentDetail) Reset() {
+	*x = AgentDetail{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentDetail) ProtoMessage() {}
+
+func (x *AgentDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentDetail.ProtoReflect.Descriptor instead.
+func (*AgentDetail) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AgentDetail) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetType() AgentType {
+	if x != nil {
+		return x.Type
+	}
+	return AgentType_AGENT_TYPE_UNSPECIFIED
+}
+
+func (x *AgentDetail) GetStatus() AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
+func (x *AgentDetail) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetPublicIp() string {
+	if x != nil {
+		return x.PublicIp
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetMetadata() *AgentMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetStats() *AgentStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *AgentDetail) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+func (x *AgentDetail) GetHealth() *AgentHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+// ListRoutingRulesRequest selects the rules of an agent
+type ListRoutingRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent UUID, empty to list the rules of a group
+	GroupId       int32                  `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group whose rules to list
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutingRulesRequest) Reset() {
+	*x = ListRoutingRulesRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutingRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutingRulesRequest) ProtoMessage() {}
+
+func (x *ListRoutingRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutingRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListRoutingRulesRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListRoutingRulesRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListRoutingRulesRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// ListRoutingRulesResponse returns the rules of an agent
+type ListRoutingRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*RoutingRule         `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"` // Rules ordered by priority
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutingRulesResponse) Reset() {
+	*x = ListRoutingRulesResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutingRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutingRulesResponse) ProtoMessage() {}
+
+func (x *ListRoutingRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutingRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListRoutingRulesResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListRoutingRulesResponse) GetRules() []*RoutingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// CreateUserRequest creates a user account
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`                              // Unique username
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`                                    // Optional email address
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`                              // Optional password, random if empty
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`                                      // "user" (default) or "admin"
+	MaxAgents     int32                  `protobuf:"varint,5,opt,name=max_agents,json=maxAgents,proto3" json:"max_agents,omitempty"`          // Maximum agents, 0 for the server default
+	MaxBandwidth  int32                  `protobuf:"varint,6,opt,name=max_bandwidth,json=maxBandwidth,proto3" json:"max_bandwidth,omitempty"` // KB/s across all agents, 0 for unlimited
+	TransferCap   uint64                 `protobuf:"varint,7,opt,name=transfer_cap,json=transferCap,proto3" json:"transfer_cap,omitempty"`    // Bytes relayed per calendar month, 0 for unlimited
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetMaxAgents() int32 {
+	if x != nil {
+		return x.MaxAgents
+	}
+	return 0
+}
+
+func (x *CreateUserRequest) GetMaxBandwidth() int32 {
+	if x != nil {
+		return x.MaxBandwidth
+	}
+	return 0
+}
+
+func (x *CreateUserRequest) GetTransferCap() uint64 {
+	if x != nil {
+		return x.TransferCap
+	}
+	return 0
+}
+
+// RotateAPIKeyRequest replaces the API key of a user
+type RotateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyRequest) Reset() {
+	*x = RotateAPIKeyRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyRequest) ProtoMessage() {}
+
+func (x *RotateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RotateAPIKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// UserResponse returns a user account and its API key
+type UserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User UUID
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`           // Username
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`                 // Email address
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`                   // User role
+	ApiKey        string                 `protobuf:"bytes,5,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"` // Current API key
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserResponse) Reset() {
+	*x = UserResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserResponse) ProtoMessage() {}
+
+func (x *UserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserResponse.ProtoReflect.Descriptor instead.
+func (*UserResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UserResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserResponse) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UserResponse) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+// ListUsersRequest lists user accounts
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{14}
+}
+
+// ListUsersResponse returns user accounts
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UserDetail          `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"` // Ordered by username
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListUsersResponse) GetUsers() []*UserDetail {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+// GetUserRequest selects a user account
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                 // User UUID
+	UsageMonths   int32                  `protobuf:"varint,2,opt,name=usage_months,json=usageMonths,proto3" json:"usage_months,omitempty"` // Months of usage history, default 12
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserRequest) GetUsageMonths() int32 {
+	if x != nil {
+		return x.UsageMonths
+	}
+	return 0
+}
+
+// UpdateUserRequest replaces the mutable fields of a user
+type UpdateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                    // User UUID
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`                                    // Email address, empty to clear
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`                                      // "user" or "admin"
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                  // "active", "suspended" or "disabled"
+	MaxAgents     int32                  `protobuf:"varint,5,opt,name=max_agents,json=maxAgents,proto3" json:"max_agents,omitempty"`          // Maximum agents, 0 for the server default
+	MaxBandwidth  int32                  `protobuf:"varint,6,opt,name=max_bandwidth,json=maxBandwidth,proto3" json:"max_bandwidth,omitempty"` // KB/s across all agents, 0 for unlimited
+	TransferCap   uint64                 `protobuf:"varint,7,opt,name=transfer_cap,json=transferCap,proto3" json:"transfer_cap,omitempty"`    // Bytes relayed per calendar month, 0 for unlimited
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetMaxAgents() int32 {
+	if x != nil {
+		return x.MaxAgents
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetMaxBandwidth() int32 {
+	if x != nil {
+		return x.MaxBandwidth
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetTransferCap() uint64 {
+	if x != nil {
+		return x.TransferCap
+	}
+	return 0
+}
+
+// DeleteUserRequest deletes a user account
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// DeleteUserResponse acknowledges user deletion
+type DeleteUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`                                  // User was removed
+	AgentsDeleted int32                  `protobuf:"varint,2,opt,name=agents_deleted,json=agentsDeleted,proto3" json:"agents_deleted,omitempty"` // Number of agents removed with the user
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserResponse) Reset() {
+	*x = DeleteUserResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserResponse) ProtoMessage() {}
+
+func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteUserResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *DeleteUserResponse) GetAgentsDeleted() int32 {
+	if x != nil {
+		return x.AgentsDeleted
+	}
+	return 0
+}
+
+// UserDetail describes a user account, its quotas and usage. The API key
+// is never included.
+type UserDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                    // User UUID
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`                              // Username
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`                                    // Email address
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`                                      // User role
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                                  // Account status
+	MaxAgents     int32                  `protobuf:"varint,6,opt,name=max_agents,json=maxAgents,proto3" json:"max_agents,omitempty"`          // Maximum agents, 0 for the server default
+	MaxBandwidth  int32                  `protobuf:"varint,7,opt,name=max_bandwidth,json=maxBandwidth,proto3" json:"max_bandwidth,omitempty"` // KB/s across all agents, 0 for unlimited
+	TransferCap   uint64                 `protobuf:"varint,8,opt,name=transfer_cap,json=transferCap,proto3" json:"transfer_cap,omitempty"`    // Bytes relayed per calendar month, 0 for unlimited
+	AgentCount    int32                  `protobuf:"varint,9,opt,name=agent_count,json=agentCount,proto3" json:"agent_count,omitempty"`       // Registered agents
+	Usage         []*UserUsage           `protobuf:"bytes,10,rep,name=usage,proto3" json:"usage,omitempty"`                                   // Monthly usage, newest first
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`          // Account creation time
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDetail) Reset() {
+	*x = UserDetail{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDetail) ProtoMessage() {}
+
+func (x *UserDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDetail.ProtoReflect.Descriptor instead.
+func (*UserDetail) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UserDetail) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserDetail) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserDetail) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserDetail) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UserDetail) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UserDetail) GetMaxAgents() int32 {
+	if x != nil {
+		return x.MaxAgents
+	}
+	return 0
+}
+
+func (x *UserDetail) GetMaxBandwidth() int32 {
+	if x != nil {
+		return x.MaxBandwidth
+	}
+	return 0
+}
+
+func (x *UserDetail) GetTransferCap() uint64 {
+	if x != nil {
+		return x.TransferCap
+	}
+	return 0
+}
+
+func (x *UserDetail) GetAgentCount() int32 {
+	if x != nil {
+		return x.AgentCount
+	}
+	return 0
+}
+
+func (x *UserDetail) GetUsage() []*UserUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *UserDetail) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// UserUsage is the relayed traffic of a user in one calendar month
+type UserUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         string                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`                                       // YYYY-MM in UTC
+	BytesSent     uint64                 `protobuf:"varint,2,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`             // Bytes relayed from the user's agents
+	BytesReceived uint64                 `protobuf:"varint,3,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"` // Bytes relayed to the user's agents
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserUsage) Reset() {
+	*x = UserUsage{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserUsage) ProtoMessage() {}
+
+func (x *UserUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserUsage.ProtoReflect.Descriptor instead.
+func (*UserUsage) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UserUsage) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *UserUsage) GetBytesSent() uint64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *UserUsage) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+// ExportUsageRequest selects the month and format of a usage export
+type ExportUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         string                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`   // YYYY-MM in UTC, empty for the current month
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"` // "csv" (default) or "json"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsageRequest) Reset() {
+	*x = ExportUsageRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsageRequest) ProtoMessage() {}
+
+func (x *ExportUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsageRequest.ProtoReflect.Descriptor instead.
+func (*ExportUsageRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExportUsageRequest) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *ExportUsageRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// ExportUsageResponse returns a rendered usage export
+type ExportUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         string                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`                                // Exported month
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // "text/csv" or "application/json"
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`                                  // One record per user
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsageResponse) Reset() {
+	*x = ExportUsageResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsageResponse) ProtoMessage() {}
+
+func (x *ExportUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsageResponse.ProtoReflect.Descriptor instead.
+func (*ExportUsageResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ExportUsageResponse) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *ExportUsageResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ExportUsageResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// ExportInventoryRequest selects the format of an inventory export
+type ExportInventoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"` // "hosts" (default) or "ansible"
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"` // Domain appended to host names as an alias, optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportInventoryRequest) Reset() {
+	*x = ExportInventoryRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportInventoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportInventoryRequest) ProtoMessage() {}
+
+func (x *ExportInventoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportInventoryRequest.ProtoReflect.Descriptor instead.
+func (*ExportInventoryRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ExportInventoryRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportInventoryRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+// ExportInventoryResponse returns a rendered inventory of the approved,
+// active agents with an overlay address
+type ExportInventoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContentType   string                 `protobuf:"bytes,1,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // "text/plain" or "application/json"
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	AgentCount    int32                  `protobuf:"varint,3,opt,name=agent_count,json=agentCount,proto3" json:"agent_count,omitempty"` // Agents in the inventory
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportInventoryResponse) Reset() {
+	*x = ExportInventoryResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportInventoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportInventoryResponse) ProtoMessage() {}
+
+func (x *ExportInventoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportInventoryResponse.ProtoReflect.Descriptor instead.
+func (*ExportInventoryResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ExportInventoryResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ExportInventoryResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportInventoryResponse) GetAgentCount() int32 {
+	if x != nil {
+		return x.AgentCount
+	}
+	return 0
+}
+
+// SetRelayTracingRequest changes the relay trace sampling
+type SetRelayTracingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SampleRate    uint32                 `protobuf:"varint,1,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"` // Trace 1 in N relayed packets, 0 disables tracing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRelayTracingRequest) Reset() {
+	*x = SetRelayTracingRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRelayTracingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRelayTracingRequest) ProtoMessage() {}
+
+func (x *SetRelayTracingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRelayTracingRequest.ProtoReflect.Descriptor instead.
+func (*SetRelayTracingRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SetRelayTracingRequest) GetSampleRate() uint32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+// RelayTracingResponse returns the relay tracing settings
+type RelayTracingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SampleRate    uint32                 `protobuf:"varint,1,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"` // Current sampling rate, 0 if disabled
+	BufferSize    uint32                 `protobuf:"varint,2,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"` // Number of traces kept
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RelayTracingResponse) Reset() {
+	*x = RelayTracingResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelayTracingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayTracingResponse) ProtoMessage() {}
+
+func (x *RelayTracingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayTracingResponse.ProtoReflect.Descriptor instead.
+func (*RelayTracingResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RelayTracingResponse) GetSampleRate() uint32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *RelayTracingResponse) GetBufferSize() uint32 {
+	if x != nil {
+		return x.BufferSize
+	}
+	return 0
+}
+
+// ListRelayTracesRequest selects recent relay traces
+type ListRelayTracesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Only traces from or to this agent, empty for all
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                   // Maximum number of traces, 0 for all buffered
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRelayTracesRequest) Reset() {
+	*x = ListRelayTracesRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRelayTracesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRelayTracesRequest) ProtoMessage() {}
+
+func (x *ListRelayTracesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRelayTracesRequest.ProtoReflect.Descriptor instead.
+func (*ListRelayTracesRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListRelayTracesRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListRelayTracesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListRelayTracesResponse returns recent relay traces
+type ListRelayTracesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Traces        []*RelayTrace          `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`                            // Newest first
+	SampleRate    uint32                 `protobuf:"varint,2,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"` // Current sampling rate, 0 if disabled
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRelayTracesResponse) Reset() {
+	*x = ListRelayTracesResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRelayTracesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRelayTracesResponse) ProtoMessage() {}
+
+func (x *ListRelayTracesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRelayTracesResponse.ProtoReflect.Descriptor instead.
+func (*ListRelayTracesResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListRelayTracesResponse) GetTraces() []*RelayTrace {
+	if x != nil {
+		return x.Traces
+	}
+	return nil
+}
+
+func (x *ListRelayTracesResponse) GetSampleRate() uint32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+// RelayTrace records the routing decision for one sampled packet. Only
+// headers are recorded, never payload data.
+type RelayTrace struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Time               *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`                                                         // When the packet was relayed
+	SessionId          string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                              // Source session
+	SourceAgentId      string                 `protobuf:"bytes,3,opt,name=source_agent_id,json=sourceAgentId,proto3" json:"source_agent_id,omitempty"`                // Source agent UUID
+	DestinationAgentId string                 `protobuf:"bytes,4,opt,name=destination_agent_id,json=destinationAgentId,proto3" json:"destination_agent_id,omitempty"` // Chosen destination agent, empty if none
+	SourceIp           string                 `protobuf:"bytes,5,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`                                 // IPv4 source address, if parseable
+	DestinationIp      string                 `protobuf:"bytes,6,opt,name=destination_ip,json=destinationIp,proto3" json:"destination_ip,omitempty"`                  // IPv4 destination address, if parseable
+	Protocol           uint32                 `protobuf:"varint,7,opt,name=protocol,proto3" json:"protocol,omitempty"`                                                // IP protocol number
+	Length             uint32                 `protobuf:"varint,8,opt,name=length,proto3" json:"length,omitempty"`                                                    // Payload length in bytes
+	Ttl                uint32                 `protobuf:"varint,9,opt,name=ttl,proto3" json:"ttl,omitempty"`                                                          // IPv4 TTL as received
+	Decision           string                 `protobuf:"bytes,10,opt,name=decision,proto3" json:"decision,omitempty"`                                                // forwarded, no_route, ttl_exceeded, send_failed, echo_answered, malformed
+	Detail             string                 `protobuf:"bytes,11,opt,name=detail,proto3" json:"detail,omitempty"`                                                    // Error detail, if any
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *RelayTrace) Reset() {
+	*x = RelayTrace{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelayTrace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayTrace) ProtoMessage() {}
+
+func (x *RelayTrace) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayTrace.ProtoReflect.Descriptor instead.
+func (*RelayTrace) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RelayTrace) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *RelayTrace) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetSourceAgentId() string {
+	if x != nil {
+		return x.SourceAgentId
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetDestinationAgentId() string {
+	if x != nil {
+		return x.DestinationAgentId
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetDestinationIp() string {
+	if x != nil {
+		return x.DestinationIp
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetProtocol() uint32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *RelayTrace) GetLength() uint32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *RelayTrace) GetTtl() uint32 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *RelayTrace) GetDecision() string {
+	if x != nil {
+		return x.Decision
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+// GetHandshakeStatsRequest requests QUIC handshake counters
+type GetHandshakeStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHandshakeStatsRequest) Reset() {
+	*x = GetHandshakeStatsRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHandshakeStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHandshakeStatsRequest) ProtoMessage() {}
+
+func (x *GetHandshakeStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHandshakeStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetHandshakeStatsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{31}
+}
+
+// HandshakeStatsResponse reports QUIC handshake address validation
+type HandshakeStatsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Validated      uint64                 `protobuf:"varint,1,opt,name=validated,proto3" json:"validated,omitempty"`                                 // Handshakes from validated source addresses
+	Unvalidated    uint64                 `protobuf:"varint,2,opt,name=unvalidated,proto3" json:"unvalidated,omitempty"`                             // Handshakes accepted without validation
+	Retries        uint64                 `protobuf:"varint,3,opt,name=retries,proto3" json:"retries,omitempty"`                                     // Retry packets sent
+	InvalidTokens  uint64                 `protobuf:"varint,4,opt,name=invalid_tokens,json=invalidTokens,proto3" json:"invalid_tokens,omitempty"`    // Handshakes rejected for an invalid Retry token
+	RetryThreshold int32                  `protobuf:"varint,5,opt,name=retry_threshold,json=retryThreshold,proto3" json:"retry_threshold,omitempty"` // Handshakes per second before Retry is required, 0 if disabled
+	RetryActive    bool                   `protobuf:"varint,6,opt,name=retry_active,json=retryActive,proto3" json:"retry_active,omitempty"`          // Whether Retry is currently enforced
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HandshakeStatsResponse) Reset() {
+	*x = HandshakeStatsResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandshakeStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandshakeStatsResponse) ProtoMessage() {}
+
+func (x *HandshakeStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandshakeStatsResponse.ProtoReflect.Descriptor instead.
+func (*HandshakeStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *HandshakeStatsResponse) GetValidated() uint64 {
+	if x != nil {
+		return x.Validated
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetUnvalidated() uint64 {
+	if x != nil {
+		return x.Unvalidated
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetRetries() uint64 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetInvalidTokens() uint64 {
+	if x != nil {
+		return x.InvalidTokens
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetRetryThreshold() int32 {
+	if x != nil {
+		return x.RetryThreshold
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetRetryActive() bool {
+	if x != nil {
+		return x.RetryActive
+	}
+	return false
+}
+
+// GetCryptoPolicyRequest requests the server's crypto policy
+type GetCryptoPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCryptoPolicyRequest) Reset() {
+	*x = GetCryptoPolicyRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCryptoPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCryptoPolicyRequest) ProtoMessage() {}
+
+func (x *GetCryptoPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCryptoPolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetCryptoPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{33}
+}
+
+// CryptoPolicyResponse describes the algorithms the server's TLS allows
+type CryptoPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        string                 `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`                                 // "default" or "fips"
+	FipsModule    bool                   `protobuf:"varint,2,opt,name=fips_module,json=fipsModule,proto3" json:"fips_module,omitempty"`      // Whether the Go FIPS 140 module is active
+	FipsBuild     bool                   `protobuf:"varint,3,opt,name=fips_build,json=fipsBuild,proto3" json:"fips_build,omitempty"`         // Whether the server was built with the fips tag
+	CipherSuites  []string               `protobuf:"bytes,4,rep,name=cipher_suites,json=cipherSuites,proto3" json:"cipher_suites,omitempty"` // Allowed TLS 1.3 cipher suites
+	Curves        []string               `protobuf:"bytes,5,rep,name=curves,proto3" json:"curves,omitempty"`                                 // Allowed key exchange groups, empty for the Go defaults
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CryptoPolicyResponse) Reset() {
+	*x = CryptoPolicyResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CryptoPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CryptoPolicyResponse) ProtoMessage() {}
+
+func (x *CryptoPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CryptoPolicyResponse.ProtoReflect.Descriptor instead.
+func (*CryptoPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CryptoPolicyResponse) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *CryptoPolicyResponse) GetFipsModule() bool {
+	if x != nil {
+		return x.FipsModule
+	}
+	return false
+}
+
+func (x *CryptoPolicyResponse) GetFipsBuild() bool {
+	if x != nil {
+		return x.FipsBuild
+	}
+	return false
+}
+
+func (x *CryptoPolicyResponse) GetCipherSuites() []string {
+	if x != nil {
+		return x.CipherSuites
+	}
+	return nil
+}
+
+func (x *CryptoPolicyResponse) GetCurves() []string {
+	if x != nil {
+		return x.Curves
+	}
+	return nil
+}
+
+// GetRelayQueueStatsRequest takes no parameters
+type GetRelayQueueStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRelayQueueStatsRequest) Reset() {
+	*x = GetRelayQueueStatsRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRelayQueueStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRelayQueueStatsRequest) ProtoMessage() {}
+
+func (x *GetRelayQueueStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRelayQueueStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetRelayQueueStatsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{35}
+}
+
+// RelayQueueStatsResponse holds the counters of the server relay queues
+type RelayQueueStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Classes       []*TrafficClassStats   `protobuf:"bytes,1,rep,name=classes,proto3" json:"classes,omitempty"` // One entry per traffic class
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RelayQueueStatsResponse) Reset() {
+	*x = RelayQueueStatsResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelayQueueStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayQueueStatsResponse) ProtoMessage() {}
+
+func (x *RelayQueueStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayQueueStatsResponse.ProtoReflect.Descriptor instead.
+func (*RelayQueueStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RelayQueueStatsResponse) GetClasses() []*TrafficClassStats {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+// ArchiveAgentRequest identifies the agent to archive
+type ArchiveAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveAgentRequest) Reset() {
+	*x = ArchiveAgentRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveAgentRequest) ProtoMessage() {}
+
+func (x *ArchiveAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveAgentRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ArchiveAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// RestoreAgentRequest identifies the archived agent to restore
+type RestoreAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreAgentRequest) Reset() {
+	*x = RestoreAgentRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreAgentRequest) ProtoMessage() {}
+
+func (x *RestoreAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreAgentRequest.ProtoReflect.Descriptor instead.
+func (*RestoreAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RestoreAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// ListSessionHistoryRequest selects ended sessions of an agent
+type ListSessionHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                   // Max sessions (default 50, max 500)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionHistoryRequest) Reset() {
+	*x = ListSessionHistoryRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionHistoryRequest) ProtoMessage() {}
+
+func (x *ListSessionHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListSessionHistoryRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListSessionHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListSessionHistoryResponse returns ended sessions, newest first
+type ListSessionHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*SessionRecord       `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionHistoryResponse) Reset() {
+	*x = ListSessionHistoryResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionHistoryResponse) ProtoMessage() {}
+
+func (x *ListSessionHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListSessionHistoryResponse) GetSessions() []*SessionRecord {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// SessionRecord describes an ended session
+type SessionRecord struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SessionId      string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Session UUID
+	AgentId        string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`       // Agent UUID
+	ConnectedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	DisconnectedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=disconnected_at,json=disconnectedAt,proto3" json:"disconnected_at,omitempty"`
+	BytesSent      uint64                 `protobuf:"varint,5,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`                                         // Bytes relayed to the agent
+	BytesReceived  uint64                 `protobuf:"varint,6,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`                             // Bytes relayed from the agent
+	Reason         string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`                                                                 // Why the session ended, e.g. agent shutdown: agent stopped
+	ReasonCode     DisconnectReason       `protobuf:"varint,8,opt,name=reason_code,json=reasonCode,proto3,enum=easyanylink.v1.DisconnectReason" json:"reason_code,omitempty"` // Reason classification, unspecified for sessions ended before it was recorded
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionRecord) Reset() {
+	*x = SessionRecord{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRecord) ProtoMessage() {}
+
+func (x *SessionRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRecord.ProtoReflect.Descriptor instead.
+func (*SessionRecord) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *SessionRecord) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionRecord) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SessionRecord) GetConnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return nil
+}
+
+func (x *SessionRecord) GetDisconnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisconnectedAt
+	}
+	return nil
+}
+
+func (x *SessionRecord) GetBytesSent() uint64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *SessionRecord) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *SessionRecord) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SessionRecord) GetReasonCode() DisconnectReason {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return DisconnectReason_DISCONNECT_REASON_UNSPECIFIED
+}
+
+// ApproveAgentRequest identifies the pending agent to approve
+type ApproveAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveAgentRequest) Reset() {
+	*x = ApproveAgentRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveAgentRequest) ProtoMessage() {}
+
+func (x *ApproveAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveAgentRequest.ProtoReflect.Descriptor instead.
+func (*ApproveAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ApproveAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// RejectAgentRequest identifies the pending agent to reject
+type RejectAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectAgentRequest) Reset() {
+	*x = RejectAgentRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectAgentRequest) ProtoMessage() {}
+
+func (x *RejectAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectAgentRequest.ProtoReflect.Descriptor instead.
+func (*RejectAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RejectAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// RejectAgentResponse confirms a rejection
+type RejectAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rejected      bool                   `protobuf:"varint,1,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectAgentResponse) Reset() {
+	*x = RejectAgentResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectAgentResponse) ProtoMessage() {}
+
+func (x *RejectAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectAgentResponse.ProtoReflect.Descriptor instead.
+func (*RejectAgentResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *RejectAgentResponse) GetRejected() bool {
+	if x != nil {
+		return x.Rejected
+	}
+	return false
+}
+
+// ACLRule allows or denies traffic relayed from the agents of a user.
+// Enabled rules are evaluated by priority and the first match decides;
+// traffic matching no rule is allowed.
+type ACLRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        int32                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`       // Rule identifier
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`        // User whose agents send the traffic
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`                      // Source CIDR, empty for any
+	Destination   string                 `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`            // Destination CIDR, empty for any
+	Protocol      string                 `protobuf:"bytes,5,opt,name=protocol,proto3" json:"protocol,omitempty"`                  // "any" (default), "tcp", "udp" or "icmp"
+	PortFrom      uint32                 `protobuf:"varint,6,opt,name=port_from,json=portFrom,proto3" json:"port_from,omitempty"` // First destination port (tcp/udp), 0 for any
+	PortTo        uint32                 `protobuf:"varint,7,opt,name=port_to,json=portTo,proto3" json:"port_to,omitempty"`       // Last destination port, 0 for port_from
+	Action        string                 `protobuf:"bytes,8,opt,name=action,proto3" json:"action,omitempty"`                      // "allow" or "deny"
+	Priority      int32                  `protobuf:"varint,9,opt,name=priority,proto3" json:"priority,omitempty"`                 // Rule priority (lower = higher priority)
+	Enabled       bool                   `protobuf:"varint,10,opt,name=enabled,proto3" json:"enabled,omitempty"`                  // Whether rule is active
+	Window        *AccessWindow          `protobuf:"bytes,11,opt,name=window,proto3" json:"window,omitempty"`                     // When the rule applies, unset for always
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ACLRule) Reset() {
+	*x = ACLRule{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ACLRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ACLRule) ProtoMessage() {}
+
+func (x *ACLRule) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ACLRule.ProtoReflect.Descriptor instead.
+func (*ACLRule) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ACLRule) GetRuleId() int32 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+func (x *ACLRule) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ACLRule) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ACLRule) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *ACLRule) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *ACLRule) GetPortFrom() uint32 {
+	if x != nil {
+		return x.PortFrom
+	}
+	return 0
+}
+
+func (x *ACLRule) GetPortTo() uint32 {
+	if x != nil {
+		return x.PortTo
+	}
+	return 0
+}
+
+func (x *ACLRule) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ACLRule) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *ACLRule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ACLRule) GetWindow() *AccessWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+// ListACLRulesRequest selects the ACL rules of a user
+type ListACLRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListACLRulesRequest) Reset() {
+	*x = ListACLRulesRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListACLRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListACLRulesRequest) ProtoMessage() {}
+
+func (x *ListACLRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListACLRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListACLRulesRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListACLRulesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// ListACLRulesResponse returns the ACL rules of a user
+type ListACLRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*ACLRule             `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"` // Rules ordered by priority
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListACLRulesResponse) Reset() {
+	*x = ListACLRulesResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListACLRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListACLRulesResponse) ProtoMessage() {}
+
+func (x *ListACLRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListACLRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListACLRulesResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListACLRulesResponse) GetRules() []*ACLRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// AddACLRuleRequest creates an ACL rule
+type AddACLRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *ACLRule               `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"` // Rule definition (rule_id is ignored)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddACLRuleRequest) Reset() {
+	*x = AddACLRuleRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddACLRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddACLRuleRequest) ProtoMessage() {}
+
+func (x *AddACLRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddACLRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddACLRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *AddACLRuleRequest) GetRule() *ACLRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// UpdateACLRuleRequest replaces an existing ACL rule
+type UpdateACLRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *ACLRule               `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"` // Rule definition, identified by rule_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateACLRuleRequest) Reset() {
+	*x = UpdateACLRuleRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateACLRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateACLRuleRequest) ProtoMessage() {}
+
+func (x *UpdateACLRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateACLRuleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateACLRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *UpdateACLRuleRequest) GetRule() *ACLRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// DeleteACLRuleRequest identifies the ACL rule to delete
+type DeleteACLRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        int32                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"` // Rule identifier
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteACLRuleRequest) Reset() {
+	*x = DeleteACLRuleRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteACLRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteACLRuleRequest) ProtoMessage() {}
+
+func (x *DeleteACLRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteACLRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteACLRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DeleteACLRuleRequest) GetRuleId() int32 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+// DeleteACLRuleResponse confirms a deletion
+type DeleteACLRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User the deleted rule belonged to
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteACLRuleResponse) Reset() {
+	*x = DeleteACLRuleResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteACLRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteACLRuleResponse) ProtoMessage() {}
+
+func (x *DeleteACLRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteACLRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteACLRuleResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *DeleteACLRuleResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *DeleteACLRuleResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// GetKeepaliveRequest takes no parameters
+type GetKeepaliveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeepaliveRequest) Reset() {
+	*x = GetKeepaliveRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeepaliveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeepaliveRequest) ProtoMessage() {}
+
+func (x *GetKeepaliveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeepaliveRequest.ProtoReflect.Descriptor instead.
+func (*GetKeepaliveRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{52}
+}
+
+// SetKeepaliveRequest changes the heartbeat settings
+type SetKeepaliveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interval      int32                  `protobuf:"varint,1,opt,name=interval,proto3" json:"interval,omitempty"` // Heartbeat interval in seconds, 0 keeps the current one
+	Timeout       int32                  `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`   // Seconds without a heartbeat response before agents reconnect, 0 keeps the current one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetKeepaliveRequest) Reset() {
+	*x = SetKeepaliveRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetKeepaliveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetKeepaliveRequest) ProtoMessage() {}
+
+func (x *SetKeepaliveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetKeepaliveRequest.ProtoReflect.Descriptor instead.
+func (*SetKeepaliveRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *SetKeepaliveRequest) GetInterval() int32 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *SetKeepaliveRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+// KeepaliveResponse returns the heartbeat settings
+type KeepaliveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interval      int32                  `protobuf:"varint,1,opt,name=interval,proto3" json:"interval,omitempty"` // Heartbeat interval in seconds
+	Timeout       int32                  `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`   // Dead-link timeout in seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeepaliveResponse) Reset() {
+	*x = KeepaliveResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeepaliveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeepaliveResponse) ProtoMessage() {}
+
+func (x *KeepaliveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeepaliveResponse.ProtoReflect.Descriptor instead.
+func (*KeepaliveResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *KeepaliveResponse) GetInterval() int32 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *KeepaliveResponse) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+// AgentGroup is a config template shared by the agents of a group. The
+// group's routing rules apply to its agents next to their own.
+type AgentGroup struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	GroupId        int32                  `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group identifier, ignored on creation
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                       // Unique name
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	DnsServers     []string               `protobuf:"bytes,4,rep,name=dns_servers,json=dnsServers,proto3" json:"dns_servers,omitempty"`              // Resolvers of client agents, empty keeps the agent setting
+	DnsSearch      []string               `protobuf:"bytes,5,rep,name=dns_search,json=dnsSearch,proto3" json:"dns_search,omitempty"`                 // Search domains used with dns_servers
+	BandwidthLimit int32                  `protobuf:"varint,6,opt,name=bandwidth_limit,json=bandwidthLimit,proto3" json:"bandwidth_limit,omitempty"` // KB/s per agent, 0 for unlimited
+	AgentCount     int32                  `protobuf:"varint,7,opt,name=agent_count,json=agentCount,proto3" json:"agent_count,omitempty"`             // Agents in the group, set in responses
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AgentGroup) Reset() {
+	*x = AgentGroup{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentGroup) ProtoMessage() {}
+
+func (x *AgentGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentGroup.ProtoReflect.Descriptor instead.
+func (*AgentGroup) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *AgentGroup) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *AgentGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AgentGroup) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AgentGroup) GetDnsServers() []string {
+	if x != nil {
+		return x.DnsServers
+	}
+	return nil
+}
+
+func (x *AgentGroup) GetDnsSearch() []string {
+	if x != nil {
+		return x.DnsSearch
+	}
+	return nil
+}
+
+func (x *AgentGroup) GetBandwidthLimit() int32 {
+	if x != nil {
+		return x.BandwidthLimit
+	}
+	return 0
+}
+
+func (x *AgentGroup) GetAgentCount() int32 {
+	if x != nil {
+		return x.AgentCount
+	}
+	return 0
+}
+
+// ListAgentGroupsRequest takes no parameters
+type ListAgentGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentGroupsRequest) Reset() {
+	*x = ListAgentGroupsRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentGroupsRequest) ProtoMessage() {}
+
+func (x *ListAgentGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{56}
+}
+
+// ListAgentGroupsResponse returns the agent groups ordered by name
+type ListAgentGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*AgentGroup          `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentGroupsResponse) Reset() {
+	*x = ListAgentGroupsResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentGroupsResponse) ProtoMessage() {}
+
+func (x *ListAgentGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListAgentGroupsResponse) GetGroups() []*AgentGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+// DeleteAgentGroupRequest identifies the group to delete
+type DeleteAgentGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       int32                  `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAgentGroupRequest) Reset() {
+	*x = DeleteAgentGroupRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAgentGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAgentGroupRequest) ProtoMessage() {}
+
+func (x *DeleteAgentGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAgentGroupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAgentGroupRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *DeleteAgentGroupRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// DeleteAgentGroupResponse confirms a deletion
+type DeleteAgentGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAgentGroupResponse) Reset() {
+	*x = DeleteAgentGroupResponse{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAgentGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAgentGroupResponse) ProtoMessage() {}
+
+func (x *DeleteAgentGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAgentGroupResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAgentGroupResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *DeleteAgentGroupResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+// SetAgentGroupRequest moves an agent into a group
+type SetAgentGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent UUID
+	GroupId       int32                  `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group to join, 0 to leave the current one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAgentGroupRequest) Reset() {
+	*x = SetAgentGroupRequest{}
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAgentGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAgentGroupRequest) ProtoMessage() {}
+
+func (x *SetAgentGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_admin_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAgentGroupRequest.ProtoReflect.Descriptor instead.
+func (*SetAgentGroupRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *SetAgentGroupRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SetAgentGroupRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+var File_common_proto_easyanylink_v1_admin_proto protoreflect.FileDescriptor
+
+const file_common_proto_easyanylink_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"'common/proto/easyanylink/v1/admin.proto\x12\x0eeasyanylink.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a'common/proto/easyanylink/v1/agent.proto\"~\n" +
+	"\x15AddRoutingRuleRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12/\n" +
+	"\x04rule\x18\x02 \x01(\v2\x1b.easyanylink.v1.RoutingRuleR\x04rule\x12\x19\n" +
+	"\bgroup_id\x18\x03 \x01(\x05R\agroupId\"K\n" +
+	"\x18UpdateRoutingRuleRequest\x12/\n" +
+	"\x04rule\x18\x01 \x01(\v2\x1b.easyanylink.v1.RoutingRuleR\x04rule\"|\n" +
+	"\x13RoutingRuleResponse\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12/\n" +
+	"\x04rule\x18\x02 \x01(\v2\x1b.easyanylink.v1.RoutingRuleR\x04rule\x12\x19\n" +
+	"\bgroup_id\x18\x03 \x01(\x05R\agroupId\"3\n" +
+	"\x18DeleteRoutingRuleRequest\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x05R\x06ruleId\"k\n" +
+	"\x19DeleteRoutingRuleResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x19\n" +
+	"\bgroup_id\x18\x03 \x01(\x05R\agroupId\"\x84\x03\n" +
+	"\x11ListAgentsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12-\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x19.easyanylink.v1.AgentTypeR\x04type\x123\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1b.easyanylink.v1.AgentStatusR\x06status\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\x12E\n" +
+	"\x06labels\x18\x06 \x03(\v2-.easyanylink.v1.ListAgentsRequest.LabelsEntryR\x06labels\x12\x1a\n" +
+	"\barchived\x18\a \x01(\bR\barchived\x12\x18\n" +
+	"\apending\x18\b \x01(\bR\apending\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"q\n" +
+	"\x12ListAgentsResponse\x123\n" +
+	"\x06agents\x18\x01 \x03(\v2\x1b.easyanylink.v1.AgentDetailR\x06agents\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\",\n" +
+	"\x0fGetAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"\xb5\x05\n" +
+	"\vAgentDetail\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12-\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x19.easyanylink.v1.AgentTypeR\x04type\x123\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1b.easyanylink.v1.AgentStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x06 \x01(\tR\tipAddress\x12\x1b\n" +
+	"\tpublic_ip\x18\a \x01(\tR\bpublicIp\x129\n" +
+	"\bmetadata\x18\b \x01(\v2\x1d.easyanylink.v1.AgentMetadataR\bmetadata\x120\n" +
+	"\x05stats\x18\t \x01(\v2\x1a.easyanylink.v1.AgentStatsR\x05stats\x127\n" +
+	"\tlast_seen\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\f \x01(\tR\tsessionId\x12\x1c\n" +
+	"\tconnected\x18\r \x01(\bR\tconnected\x12;\n" +
+	"\varchived_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\x12\x18\n" +
+	"\apending\x18\x0f \x01(\bR\apending\x123\n" +
+	"\x06health\x18\x10 \x01(\v2\x1b.easyanylink.v1.AgentHealthR\x06health\x12\x14\n" +
+	"\x05group\x18\x11 \x01(\tR\x05group\"O\n" +
+	"\x17ListRoutingRulesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\x05R\agroupId\"M\n" +
+	"\x18ListRoutingRulesResponse\x121\n" +
+	"\x05rules\x18\x01 \x03(\v2\x1b.easyanylink.v1.RoutingRuleR\x05rules\"\xdc\x01\n" +
+	"\x11CreateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x1d\n" +
+	"\n" +
+	"max_agents\x18\x05 \x01(\x05R\tmaxAgents\x12#\n" +
+	"\rmax_bandwidth\x18\x06 \x01(\x05R\fmaxBandwidth\x12!\n" +
+	"\ftransfer_cap\x18\a \x01(\x04R\vtransferCap\".\n" +
+	"\x13RotateAPIKeyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x86\x01\n" +
+	"\fUserResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x17\n" +
+	"\aapi_key\x18\x05 \x01(\tR\x06apiKey\"\x12\n" +
+	"\x10ListUsersRequest\"E\n" +
+	"\x11ListUsersResponse\x120\n" +
+	"\x05users\x18\x01 \x03(\v2\x1a.easyanylink.v1.UserDetailR\x05users\"L\n" +
+	"\x0eGetUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fusage_months\x18\x02 \x01(\x05R\vusageMonths\"\xd5\x01\n" +
+	"\x11UpdateUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"max_agents\x18\x05 \x01(\x05R\tmaxAgents\x12#\n" +
+	"\rmax_bandwidth\x18\x06 \x01(\x05R\fmaxBandwidth\x12!\n" +
+	"\ftransfer_cap\x18\a \x01(\x04R\vtransferCap\",\n" +
+	"\x11DeleteUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"U\n" +
+	"\x12DeleteUserResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\x12%\n" +
+	"\x0eagents_deleted\x18\x02 \x01(\x05R\ragentsDeleted\"\xf7\x02\n" +
+	"\n" +
+	"UserDetail\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"max_agents\x18\x06 \x01(\x05R\tmaxAgents\x12#\n" +
+	"\rmax_bandwidth\x18\a \x01(\x05R\fmaxBandwidth\x12!\n" +
+	"\ftransfer_cap\x18\b \x01(\x04R\vtransferCap\x12\x1f\n" +
+	"\vagent_count\x18\t \x01(\x05R\n" +
+	"agentCount\x12/\n" +
+	"\x05usage\x18\n" +
+	" \x03(\v2\x19.easyanylink.v1.UserUsageR\x05usage\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"g\n" +
+	"\tUserUsage\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\tR\x05month\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x02 \x01(\x04R\tbytesSent\x12%\n" +
+	"\x0ebytes_received\x18\x03 \x01(\x04R\rbytesReceived\"B\n" +
+	"\x12ExportUsageRequest\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\tR\x05month\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\"b\n" +
+	"\x13ExportUsageResponse\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\tR\x05month\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"H\n" +
+	"\x16ExportInventoryRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"q\n" +
+	"\x17ExportInventoryResponse\x12!\n" +
+	"\fcontent_type\x18\x01 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x1f\n" +
+	"\vagent_count\x18\x03 \x01(\x05R\n" +
+	"agentCount\"9\n" +
+	"\x16SetRelayTracingRequest\x12\x1f\n" +
+	"\vsample_rate\x18\x01 \x01(\rR\n" +
+	"sampleRate\"X\n" +
+	"\x14RelayTracingResponse\x12\x1f\n" +
+	"\vsample_rate\x18\x01 \x01(\rR\n" +
+	"sampleRate\x12\x1f\n" +
+	"\vbuffer_size\x18\x02 \x01(\rR\n" +
+	"bufferSize\"I\n" +
+	"\x16ListRelayTracesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"n\n" +
+	"\x17ListRelayTracesResponse\x122\n" +
+	"\x06traces\x18\x01 \x03(\v2\x1a.easyanylink.v1.RelayTraceR\x06traces\x12\x1f\n" +
+	"\vsample_rate\x18\x02 \x01(\rR\n" +
+	"sampleRate\"\xf3\x02\n" +
+	"\n" +
+	"RelayTrace\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12&\n" +
+	"\x0fsource_agent_id\x18\x03 \x01(\tR\rsourceAgentId\x120\n" +
+	"\x14destination_agent_id\x18\x04 \x01(\tR\x12destinationAgentId\x12\x1b\n" +
+	"\tsource_ip\x18\x05 \x01(\tR\bsourceIp\x12%\n" +
+	"\x0edestination_ip\x18\x06 \x01(\tR\rdestinationIp\x12\x1a\n" +
+	"\bprotocol\x18\a \x01(\rR\bprotocol\x12\x16\n" +
+	"\x06length\x18\b \x01(\rR\x06length\x12\x10\n" +
+	"\x03ttl\x18\t \x01(\rR\x03ttl\x12\x1a\n" +
+	"\bdecision\x18\n" +
+	" \x01(\tR\bdecision\x12\x16\n" +
+	"\x06detail\x18\v \x01(\tR\x06detail\"\x1a\n" +
+	"\x18GetHandshakeStatsRequest\"\xe5\x01\n" +
+	"\x16HandshakeStatsResponse\x12\x1c\n" +
+	"\tvalidated\x18\x01 \x01(\x04R\tvalidated\x12 \n" +
+	"\vunvalidated\x18\x02 \x01(\x04R\vunvalidated\x12\x18\n" +
+	"\aretries\x18\x03 \x01(\x04R\aretries\x12%\n" +
+	"\x0einvalid_tokens\x18\x04 \x01(\x04R\rinvalidTokens\x12'\n" +
+	"\x0fretry_threshold\x18\x05 \x01(\x05R\x0eretryThreshold\x12!\n" +
+	"\fretry_active\x18\x06 \x01(\bR\vretryActive\"\x18\n" +
+	"\x16GetCryptoPolicyRequest\"\xab\x01\n" +
+	"\x14CryptoPolicyResponse\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x1f\n" +
+	"\vfips_module\x18\x02 \x01(\bR\n" +
+	"fipsModule\x12\x1d\n" +
+	"\n" +
+	"fips_build\x18\x03 \x01(\bR\tfipsBuild\x12#\n" +
+	"\rcipher_suites\x18\x04 \x03(\tR\fcipherSuites\x12\x16\n" +
+	"\x06curves\x18\x05 \x03(\tR\x06curves\"\x1b\n" +
+	"\x19GetRelayQueueStatsRequest\"V\n" +
+	"\x17RelayQueueStatsResponse\x12;\n" +
+	"\aclasses\x18\x01 \x03(\v2!.easyanylink.v1.TrafficClassStatsR\aclasses\"0\n" +
+	"\x13ArchiveAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"0\n" +
+	"\x13RestoreAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"L\n" +
+	"\x19ListSessionHistoryRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"W\n" +
+	"\x1aListSessionHistoryResponse\x129\n" +
+	"\bsessions\x18\x01 \x03(\v2\x1d.easyanylink.v1.SessionRecordR\bsessions\"\xee\x02\n" +
+	"\rSessionRecord\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12=\n" +
+	"\fconnected_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vconnectedAt\x12C\n" +
+	"\x0fdisconnected_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0edisconnectedAt\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x05 \x01(\x04R\tbytesSent\x12%\n" +
+	"\x0ebytes_received\x18\x06 \x01(\x04R\rbytesReceived\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12A\n" +
+	"\vreason_code\x18\b \x01(\x0e2 .easyanylink.v1.DisconnectReasonR\n" +
+	"reasonCode\"0\n" +
+	"\x13ApproveAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"/\n" +
+	"\x12RejectAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"1\n" +
+	"\x13RejectAgentResponse\x12\x1a\n" +
+	"\brejected\x18\x01 \x01(\bR\brejected\"\xcb\x02\n" +
+	"\aACLRule\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x05R\x06ruleId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x04 \x01(\tR\vdestination\x12\x1a\n" +
+	"\bprotocol\x18\x05 \x01(\tR\bprotocol\x12\x1b\n" +
+	"\tport_from\x18\x06 \x01(\rR\bportFrom\x12\x17\n" +
+	"\aport_to\x18\a \x01(\rR\x06portTo\x12\x16\n" +
+	"\x06action\x18\b \x01(\tR\x06action\x12\x1a\n" +
+	"\bpriority\x18\t \x01(\x05R\bpriority\x12\x18\n" +
+	"\aenabled\x18\n" +
+	" \x01(\bR\aenabled\x124\n" +
+	"\x06window\x18\v \x01(\v2\x1c.easyanylink.v1.AccessWindowR\x06window\".\n" +
+	"\x13ListACLRulesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"E\n" +
+	"\x14ListACLRulesResponse\x12-\n" +
+	"\x05rules\x18\x01 \x03(\v2\x17.easyanylink.v1.ACLRuleR\x05rules\"@\n" +
+	"\x11AddACLRuleRequest\x12+\n" +
+	"\x04rule\x18\x01 \x01(\v2\x17.easyanylink.v1.ACLRuleR\x04rule\"C\n" +
+	"\x14UpdateACLRuleRequest\x12+\n" +
+	"\x04rule\x18\x01 \x01(\v2\x17.easyanylink.v1.ACLRuleR\x04rule\"/\n" +
+	"\x14DeleteACLRuleRequest\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x05R\x06ruleId\"J\n" +
+	"\x15DeleteACLRuleResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x15\n" +
+	"\x13GetKeepaliveRequest\"K\n" +
+	"\x13SetKeepaliveRequest\x12\x1a\n" +
+	"\binterval\x18\x01 \x01(\x05R\binterval\x12\x18\n" +
+	"\atimeout\x18\x02 \x01(\x05R\atimeout\"I\n" +
+	"\x11KeepaliveResponse\x12\x1a\n" +
+	"\binterval\x18\x01 \x01(\x05R\binterval\x12\x18\n" +
+	"\atimeout\x18\x02 \x01(\x05R\atimeout\"\xe7\x01\n" +
+	"\n" +
+	"AgentGroup\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\x05R\agroupId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vdns_servers\x18\x04 \x03(\tR\n" +
+	"dnsServers\x12\x1d\n" +
+	"\n" +
+	"dns_search\x18\x05 \x03(\tR\tdnsSearch\x12'\n" +
+	"\x0fbandwidth_limit\x18\x06 \x01(\x05R\x0ebandwidthLimit\x12\x1f\n" +
+	"\vagent_count\x18\a \x01(\x05R\n" +
+	"agentCount\"\x18\n" +
+	"\x16ListAgentGroupsRequest\"M\n" +
+	"\x17ListAgentGroupsResponse\x122\n" +
+	"\x06groups\x18\x01 \x03(\v2\x1a.easyanylink.v1.AgentGroupR\x06groups\"4\n" +
+	"\x17DeleteAgentGroupRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\x05R\agroupId\"4\n" +
+	"\x18DeleteAgentGroupResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"L\n" +
+	"\x14SetAgentGroupRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\x05R\agroupId2\xc5\x18\n" +
+	"\fAdminService\x12\\\n" +
+	"\x0eAddRoutingRule\x12%.easyanylink.v1.AddRoutingRuleRequest\x1a#.easyanylink.v1.RoutingRuleResponse\x12b\n" +
+	"\x11UpdateRoutingRule\x12(.easyanylink.v1.UpdateRoutingRuleRequest\x1a#.easyanylink.v1.RoutingRuleResponse\x12h\n" +
+	"\x11DeleteRoutingRule\x12(.easyanylink.v1.DeleteRoutingRuleRequest\x1a).easyanylink.v1.DeleteRoutingRuleResponse\x12S\n" +
+	"\n" +
+	"ListAgents\x12!.easyanylink.v1.ListAgentsRequest\x1a\".easyanylink.v1.ListAgentsResponse\x12H\n" +
+	"\bGetAgent\x12\x1f.easyanylink.v1.GetAgentRequest\x1a\x1b.easyanylink.v1.AgentDetail\x12e\n" +
+	"\x10ListRoutingRules\x12'.easyanylink.v1.ListRoutingRulesRequest\x1a(.easyanylink.v1.ListRoutingRulesResponse\x12M\n" +
+	"\n" +
+	"CreateUser\x12!.easyanylink.v1.CreateUserRequest\x1a\x1c.easyanylink.v1.UserResponse\x12Q\n" +
+	"\fRotateAPIKey\x12#.easyanylink.v1.RotateAPIKeyRequest\x1a\x1c.easyanylink.v1.UserResponse\x12P\n" +
+	"\tListUsers\x12 .easyanylink.v1.ListUsersRequest\x1a!.easyanylink.v1.ListUsersResponse\x12E\n" +
+	"\aGetUser\x12\x1e.easyanylink.v1.GetUserRequest\x1a\x1a.easyanylink.v1.UserDetail\x12K\n" +
+	"\n" +
+	"UpdateUser\x12!.easyanylink.v1.UpdateUserRequest\x1a\x1a.easyanylink.v1.UserDetail\x12S\n" +
+	"\n" +
+	"DeleteUser\x12!.easyanylink.v1.DeleteUserRequest\x1a\".easyanylink.v1.DeleteUserResponse\x12V\n" +
+	"\vExportUsage\x12\".easyanylink.v1.ExportUsageRequest\x1a#.easyanylink.v1.ExportUsageResponse\x12b\n" +
+	"\x0fExportInventory\x12&.easyanylink.v1.ExportInventoryRequest\x1a'.easyanylink.v1.ExportInventoryResponse\x12_\n" +
+	"\x0fSetRelayTracing\x12&.easyanylink.v1.SetRelayTracingRequest\x1a$.easyanylink.v1.RelayTracingResponse\x12b\n" +
+	"\x0fListRelayTraces\x12&.easyanylink.v1.ListRelayTracesRequest\x1a'.easyanylink.v1.ListRelayTracesResponse\x12e\n" +
+	"\x11GetHandshakeStats\x12(.easyanylink.v1.GetHandshakeStatsRequest\x1a&.easyanylink.v1.HandshakeStatsResponse\x12P\n" +
+	"\fArchiveAgent\x12#.easyanylink.v1.ArchiveAgentRequest\x1a\x1b.easyanylink.v1.AgentDetail\x12P\n" +
+	"\fRestoreAgent\x12#.easyanylink.v1.RestoreAgentRequest\x1a\x1b.easyanylink.v1.AgentDetail\x12k\n" +
+	"\x12ListSessionHistory\x12).easyanylink.v1.ListSessionHistoryRequest\x1a*.easyanylink.v1.ListSessionHistoryResponse\x12P\n" +
+	"\fApproveAgent\x12#.easyanylink.v1.ApproveAgentRequest\x1a\x1b.easyanylink.v1.AgentDetail\x12V\n" +
+	"\vRejectAgent\x12\".easyanylink.v1.RejectAgentRequest\x1a#.easyanylink.v1.RejectAgentResponse\x12Y\n" +
+	"\fListACLRules\x12#.easyanylink.v1.ListACLRulesRequest\x1a$.easyanylink.v1.ListACLRulesResponse\x12H\n" +
+	"\n" +
+	"AddACLRule\x12!.easyanylink.v1.AddACLRuleRequest\x1a\x17.easyanylink.v1.ACLRule\x12N\n" +
+	"\rUpdateACLRule\x12$.easyanylink.v1.UpdateACLRuleRequest\x1a\x17.easyanylink.v1.ACLRule\x12\\\n" +
+	"\rDeleteACLRule\x12$.easyanylink.v1.DeleteACLRuleRequest\x1a%.easyanylink.v1.DeleteACLRuleResponse\x12_\n" +
+	"\x0fGetCryptoPolicy\x12&.easyanylink.v1.GetCryptoPolicyRequest\x1a$.easyanylink.v1.CryptoPolicyResponse\x12h\n" +
+	"\x12GetRelayQueueStats\x12).easyanylink.v1.GetRelayQueueStatsRequest\x1a'.easyanylink.v1.RelayQueueStatsResponse\x12V\n" +
+	"\fGetKeepalive\x12#.easyanylink.v1.GetKeepaliveRequest\x1a!.easyanylink.v1.KeepaliveResponse\x12V\n" +
+	"\fSetKeepalive\x12#.easyanylink.v1.SetKeepaliveRequest\x1a!.easyanylink.v1.KeepaliveResponse\x12b\n" +
+	"\x0fListAgentGroups\x12&.easyanylink.v1.ListAgentGroupsRequest\x1a'.easyanylink.v1.ListAgentGroupsResponse\x12J\n" +
+	"\x10CreateAgentGroup\x12\x1a.easyanylink.v1.AgentGroup\x1a\x1a.easyanylink.v1.AgentGroup\x12J\n" +
+	"\x10UpdateAgentGroup\x12\x1a.easyanylink.v1.AgentGroup\x1a\x1a.easyanylink.v1.AgentGroup\x12e\n" +
+	"\x10DeleteAgentGroup\x12'.easyanylink.v1.DeleteAgentGroupRequest\x1a(.easyanylink.v1.DeleteAgentGroupResponse\x12R\n" +
+	"\rSetAgentGroup\x12$.easyanylink.v1.SetAgentGroupRequest\x1a\x1b.easyanylink.v1.AgentDetailBIZGgithub.com/taills/EasyAnyLink/common/proto/easyanylink/v1;easyanylinkv1b\x06proto3"
+
+var (
+	file_common_proto_easyanylink_v1_admin_proto_rawDescOnce sync.Once
+	file_common_proto_easyanylink_v1_admin_proto_rawDescData []byte
+)
+
+func file_common_proto_easyanylink_v1_admin_proto_rawDescGZIP() []byte {
+	file_common_proto_easyanylink_v1_admin_proto_rawDescOnce.Do(func() {
+		file_common_proto_easyanylink_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_common_proto_easyanylink_v1_admin_proto_rawDesc), len(file_common_proto_easyanylink_v1_admin_proto_rawDesc)))
+	})
+	return file_common_proto_easyanylink_v1_admin_proto_rawDescData
+}
+
+var file_common_proto_easyanylink_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 62)
+var file_common_proto_easyanylink_v1_admin_proto_goTypes = []any{
+	(*AddRoutingRuleRequest)(nil),      // 0: easyanylink.v1.AddRoutingRuleRequest
+	(*UpdateRoutingRuleRequest)(nil),   // 1: easyanylink.v1.UpdateRoutingRuleRequest
+	(*RoutingRuleResponse)(nil),        // 2: easyanylink.v1.RoutingRuleResponse
+	(*DeleteRoutingRuleRequest)(nil),   // 3: easyanylink.v1.DeleteRoutingRuleRequest
+	(*DeleteRoutingRuleResponse)(nil),  // 4: easyanylink.v1.DeleteRoutingRuleResponse
+	(*ListAgentsRequest)(nil),          // 5: easyanylink.v1.ListAgentsRequest
+	(*ListAgentsResponse)(nil),         // 6: easyanylink.v1.ListAgentsResponse
+	(*GetAgentRequest)(nil),            // 7: easyanylink.v1.GetAgentRequest
+	(*AgentDetail)(nil),                // 8: easyanylink.v1.AgentDetail
+	(*ListRoutingRulesRequest)(nil),    // 9: easyanylink.v1.ListRoutingRulesRequest
+	(*ListRoutingRulesResponse)(nil),   // 10: easyanylink.v1.ListRoutingRulesResponse
+	(*CreateUserRequest)(nil),          // 11: easyanylink.v1.CreateUserRequest
+	(*RotateAPIKeyRequest)(nil),        // 12: easyanylink.v1.RotateAPIKeyRequest
+	(*UserResponse)(nil),               // 13: easyanylink.v1.UserResponse
+	(*ListUsersRequest)(nil),           // 14: easyanylink.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),          // 15: easyanylink.v1.ListUsersResponse
+	(*GetUserRequest)(nil),             // 16: easyanylink.v1.GetUserRequest
+	(*UpdateUserRequest)(nil),          // 17: easyanylink.v1.UpdateUserRequest
+	(*DeleteUserRequest)(nil),          // 18: easyanylink.v1.DeleteUserRequest
+	(*DeleteUserResponse)(nil),         // 19: easyanylink.v1.DeleteUserResponse
+	(*UserDetail)(nil),                 // 20: easyanylink.v1.UserDetail
+	(*UserUsage)(nil),                  // 21: easyanylink.v1.UserUsage
+	(*ExportUsageRequest)(nil),         // 22: easyanylink.v1.ExportUsageRequest
+	(*ExportUsageResponse)(nil),        // 23: easyanylink.v1.ExportUsageResponse
+	(*ExportInventoryRequest)(nil),     // 24: easyanylink.v1.ExportInventoryRequest
+	(*ExportInventoryResponse)(nil),    // 25: easyanylink.v1.ExportInventoryResponse
+	(*SetRelayTracingRequest)(nil),     // 26: easyanylink.v1.SetRelayTracingRequest
+	(*RelayTracingResponse)(nil),       // 27: easyanylink.v1.RelayTracingResponse
+	(*ListRelayTracesRequest)(nil),     // 28: easyanylink.v1.ListRelayTracesRequest
+	(*ListRelayTracesResponse)(nil),    // 29: easyanylink.v1.ListRelayTracesResponse
+	(*RelayTrace)(nil),                 // 30: easyanylink.v1.RelayTrace
+	(*GetHandshakeStatsRequest)(nil),   // 31: easyanylink.v1.GetHandshakeStatsRequest
+	(*HandshakeStatsResponse)(nil),     // 32: easyanylink.v1.HandshakeStatsResponse
+	(*GetCryptoPolicyRequest)(nil),     // 33: easyanylink.v1.GetCryptoPolicyRequest
+	(*CryptoPolicyResponse)(nil),       // 34: easyanylink.v1.CryptoPolicyResponse
+	(*GetRelayQueueStatsRequest)(nil),  // 35: easyanylink.v1.GetRelayQueueStatsRequest
+	(*RelayQueueStatsResponse)(nil),    // 36: easyanylink.v1.RelayQueueStatsResponse
+	(*ArchiveAgentRequest)(nil),        // 37: easyanylink.v1.ArchiveAgentRequest
+	(*RestoreAgentRequest)(nil),        // 38: easyanylink.v1.RestoreAgentRequest
+	(*ListSessionHistoryRequest)(nil),  // 39: easyanylink.v1.ListSessionHistoryRequest
+	(*ListSessionHistoryResponse)(nil), // 40: easyanylink.v1.ListSessionHistoryResponse
+	(*SessionRecord)(nil),              // 41: easyanylink.v1.SessionRecord
+	(*ApproveAgentRequest)(nil),        // 42: easyanylink.v1.ApproveAgentRequest
+	(*RejectAgentRequest)(nil),         // 43: easyanylink.v1.RejectAgentRequest
+	(*RejectAgentResponse)(nil),        // 44: easyanylink.v1.RejectAgentResponse
+	(*ACLRule)(nil),                    // 45: easyanylink.v1.ACLRule
+	(*ListACLRulesRequest)(nil),        // 46: easyanylink.v1.ListACLRulesRequest
+	(*ListACLRulesResponse)(nil),       // 47: easyanylink.v1.ListACLRulesResponse
+	(*AddACLRuleRequest)(nil),          // 48: easyanylink.v1.AddACLRuleRequest
+	(*UpdateACLRuleRequest)(nil),       // 49: easyanylink.v1.UpdateACLRuleRequest
+	(*DeleteACLRuleRequest)(nil),       // 50: easyanylink.v1.DeleteACLRuleRequest
+	(*DeleteACLRuleResponse)(nil),      // 51: easyanylink.v1.DeleteACLRuleResponse
+	(*GetKeepaliveRequest)(nil),        // 52: easyanylink.v1.GetKeepaliveRequest
+	(*SetKeepaliveRequest)(nil),        // 53: easyanylink.v1.SetKeepaliveRequest
+	(*KeepaliveResponse)(nil),          // 54: easyanylink.v1.KeepaliveResponse
+	(*AgentGroup)(nil),                 // 55: easyanylink.v1.AgentGroup
+	(*ListAgentGroupsRequest)(nil),     // 56: easyanylink.v1.ListAgentGroupsRequest
+	(*ListAgentGroupsResponse)(nil),    // 57: easyanylink.v1.ListAgentGroupsResponse
+	(*DeleteAgentGroupRequest)(nil),    // 58: easyanylink.v1.DeleteAgentGroupRequest
+	(*DeleteAgentGroupResponse)(nil),   // 59: easyanylink.v1.DeleteAgentGroupResponse
+	(*SetAgentGroupRequest)(nil),       // 60: easyanylink.v1.SetAgentGroupRequest
+	nil,                                // 61: easyanylink.v1.ListAgentsRequest.LabelsEntry
+	(*RoutingRule)(nil),                // 62: easyanylink.v1.RoutingRule
+	(AgentType)(0),                     // 63: easyanylink.v1.AgentType
+	(AgentStatus)(0),                   // 64: easyanylink.v1.AgentStatus
+	(*AgentMetadata)(nil),              // 65: easyanylink.v1.AgentMetadata
+	(*AgentStats)(nil),                 // 66: easyanylink.v1.AgentStats
+	(*timestamppb.Timestamp)(nil),      // 67: google.protobuf.Timestamp
+	(*AgentHealth)(nil),                // 68: easyanylink.v1.AgentHealth
+	(*TrafficClassStats)(nil),          // 69: easyanylink.v1.TrafficClassStats
+	(DisconnectReason)(0),              // 70: easyanylink.v1.DisconnectReason
+	(*AccessWindow)(nil),               // 71: easyanylink.v1.AccessWindow
+}
+var file_common_proto_easyanylink_v1_admin_proto_depIdxs = []int32{
+	62, // 0: easyanylink.v1.AddRoutingRuleRequest.rule:type_name -> easyanylink.v1.RoutingRule
+	62, // 1: easyanylink.v1.UpdateRoutingRuleRequest.rule:type_name -> easyanylink.v1.RoutingRule
+	62, // 2: easyanylink.v1.RoutingRuleResponse.rule:type_name -> easyanylink.v1.RoutingRule
+	63, // 3: easyanylink.v1.ListAgentsRequest.type:type_name -> easyanylink.v1.AgentType
+	64, // 4: easyanylink.v1.ListAgentsRequest.status:type_name -> easyanylink.v1.AgentStatus
+	61, // 5: easyanylink.v1.ListAgentsRequest.labels:type_name -> easyanylink.v1.ListAgentsRequest.LabelsEntry
+	8,  // 6: easyanylink.v1.ListAgentsResponse.agents:type_name -> easyanylink.v1.AgentDetail
+	63, // 7: easyanylink.v1.AgentDetail.type:type_name -> easyanylink.v1.AgentType
+	64, // 8: easyanylink.v1.AgentDetail.status:type_name -> easyanylink.v1.AgentStatus
+	65, // 9: easyanylink.v1.AgentDetail.metadata:type_name -> easyanylink.v1.AgentMetadata
+	66, // 10: easyanylink.v1.AgentDetail.stats:type_name -> easyanylink.v1.AgentStats
+	67, // 11: easyanylink.v1.AgentDetail.last_seen:type_name -> google.protobuf.Timestamp
+	67, // 12: easyanylink.v1.AgentDetail.created_at:type_name -> google.protobuf.Timestamp
+	67, // 13: easyanylink.v1.AgentDetail.archived_at:type_name -> google.protobuf.Timestamp
+	68, // 14: easyanylink.v1.AgentDetail.health:type_name -> easyanylink.v1.AgentHealth
+	62, // 15: easyanylink.v1.ListRoutingRulesResponse.rules:type_name -> easyanylink.v1.RoutingRule
+	20, // 16: easyanylink.v1.ListUsersResponse.users:type_name -> easyanylink.v1.UserDetail
+	21, // 17: easyanylink.v1.UserDetail.usage:type_name -> easyanylink.v1.UserUsage
+	67, // 18: easyanylink.v1.UserDetail.created_at:type_name -> google.protobuf.Timestamp
+	30, // 19: easyanylink.v1.ListRelayTracesResponse.traces:type_name -> easyanylink.v1.RelayTrace
+	67, // 20: easyanylink.v1.RelayTrace.time:type_name -> google.protobuf.Timestamp
+	69, // 21: easyanylink.v1.RelayQueueStatsResponse.classes:type_name -> easyanylink.v1.TrafficClassStats
+	41, // 22: easyanylink.v1.ListSessionHistoryResponse.sessions:type_name -> easyanylink.v1.SessionRecord
+	67, // 23: easyanylink.v1.SessionRecord.connected_at:type_name -> google.protobuf.Timestamp
+	67, // 24: easyanylink.v1.SessionRecord.disconnected_at:type_name -> google.protobuf.Timestamp
+	70, // 25: easyanylink.v1.SessionRecord.reason_code:type_name -> easyanylink.v1.DisconnectReason
+	71, // 26: easyanylink.v1.ACLRule.window:type_name -> easyanylink.v1.AccessWindow
+	45, // 27: easyanylink.v1.ListACLRulesResponse.rules:type_name -> easyanylink.v1.ACLRule
+	45, // 28: easyanylink.v1.AddACLRuleRequest.rule:type_name -> easyanylink.v1.ACLRule
+	45, // 29: easyanylink.v1.UpdateACLRuleRequest.rule:type_name -> easyanylink.v1.ACLRule
+	55, // 30: easyanylink.v1.ListAgentGroupsResponse.groups:type_name -> easyanylink.v1.AgentGroup
+	0,  // 31: easyanylink.v1.AdminService.AddRoutingRule:input_type -> easyanylink.v1.AddRoutingRuleRequest
+	1,  // 32: easyanylink.v1.AdminService.UpdateRoutingRule:input_type -> easyanylink.v1.UpdateRoutingRuleRequest
+	3,  // 33: easyanylink.v1.AdminService.DeleteRoutingRule:input_type -> easyanylink.v1.DeleteRoutingRuleRequest
+	5,  // 34: easyanylink.v1.AdminService.ListAgents:input_type -> easyanylink.v1.ListAgentsRequest
+	7,  // 35: easyanylink.v1.AdminService.GetAgent:input_type -> easyanylink.v1.GetAgentRequest
+	9,  // 36: easyanylink.v1.AdminService.ListRoutingRules:input_type -> easyanylink.v1.ListRoutingRulesRequest
+	11, // 37: easyanylink.v1.AdminService.CreateUser:input_type -> easyanylink.v1.CreateUserRequest
+	12, // 38: easyanylink.v1.AdminService.RotateAPIKey:input_type -> easyanylink.v1.RotateAPIKeyRequest
+	14, // 39: easyanylink.v1.AdminService.ListUsers:input_type -> easyanylink.v1.ListUsersRequest
+	16, // 40: easyanylink.v1.AdminService.GetUser:input_type -> easyanylink.v1.GetUserRequest
+	17, // 41: easyanylink.v1.AdminService.UpdateUser:input_type -> easyanylink.v1.UpdateUserRequest
+	18, // 42: easyanylink.v1.AdminService.DeleteUser:input_type -> easyanylink.v1.DeleteUserRequest
+	22, // 43: easyanylink.v1.AdminService.ExportUsage:input_type -> easyanylink.v1.ExportUsageRequest
+	24, // 44: easyanylink.v1.AdminService.ExportInventory:input_type -> easyanylink.v1.ExportInventoryRequest
+	26, // 45: easyanylink.v1.AdminService.SetRelayTracing:input_type -> easyanylink.v1.SetRelayTracingRequest
+	28, // 46: easyanylink.v1.AdminService.ListRelayTraces:input_type -> easyanylink.v1.ListRelayTracesRequest
+	31, // 47: easyanylink.v1.AdminService.GetHandshakeStats:input_type -> easyanylink.v1.GetHandshakeStatsRequest
+	37, // 48: easyanylink.v1.AdminService.ArchiveAgent:input_type -> easyanylink.v1.ArchiveAgentRequest
+	38, // 49: easyanylink.v1.AdminService.RestoreAgent:input_type -> easyanylink.v1.RestoreAgentRequest
+	39, // 50: easyanylink.v1.AdminService.ListSessionHistory:input_type -> easyanylink.v1.ListSessionHistoryRequest
+	42, // 51: easyanylink.v1.AdminService.ApproveAgent:input_type -> easyanylink.v1.ApproveAgentRequest
+	43, // 52: easyanylink.v1.AdminService.RejectAgent:input_type -> easyanylink.v1.RejectAgentRequest
+	46, // 53: easyanylink.v1.AdminService.ListACLRules:input_type -> easyanylink.v1.ListACLRulesRequest
+	48, // 54: easyanylink.v1.AdminService.AddACLRule:input_type -> easyanylink.v1.AddACLRuleRequest
+	49, // 55: easyanylink.v1.AdminService.UpdateACLRule:input_type -> easyanylink.v1.UpdateACLRuleRequest
+	50, // 56: easyanylink.v1.AdminService.DeleteACLRule:input_type -> easyanylink.v1.DeleteACLRuleRequest
+	33, // 57: easyanylink.v1.AdminService.GetCryptoPolicy:input_type -> easyanylink.v1.GetCryptoPolicyRequest
+	35, // 58: easyanylink.v1.AdminService.GetRelayQueueStats:input_type -> easyanylink.v1.GetRelayQueueStatsRequest
+	52, // 59: easyanylink.v1.AdminService.GetKeepalive:input_type -> easyanylink.v1.GetKeepaliveRequest
+	53, // 60: easyanylink.v1.AdminService.SetKeepalive:input_type -> easyanylink.v1.SetKeepaliveRequest
+	56, // 61: easyanylink.v1.AdminService.ListAgentGroups:input_type -> easyanylink.v1.ListAgentGroupsRequest
+	55, // 62: easyanylink.v1.AdminService.CreateAgentGroup:input_type -> easyanylink.v1.AgentGroup
+	55, // 63: easyanylink.v1.AdminService.UpdateAgentGroup:input_type -> easyanylink.v1.AgentGroup
+	58, // 64: easyanylink.v1.AdminService.DeleteAgentGroup:input_type -> easyanylink.v1.DeleteAgentGroupRequest
+	60, // 65: easyanylink.v1.AdminService.SetAgentGroup:input_type -> easyanylink.v1.SetAgentGroupRequest
+	2,  // 66: easyanylink.v1.AdminService.AddRoutingRule:output_type -> easyanylink.v1.RoutingRuleResponse
+	2,  // 67: easyanylink.v1.AdminService.UpdateRoutingRule:output_type -> easyanylink.v1.RoutingRuleResponse
+	4,  // 68: easyanylink.v1.AdminService.DeleteRoutingRule:output_type -> easyanylink.v1.DeleteRoutingRuleResponse
+	6,  // 69: easyanylink.v1.AdminService.ListAgents:output_type -> easyanylink.v1.ListAgentsResponse
+	8,  // 70: easyanylink.v1.AdminService.GetAgent:output_type -> easyanylink.v1.AgentDetail
+	10, // 71: easyanylink.v1.AdminService.ListRoutingRules:output_type -> easyanylink.v1.ListRoutingRulesResponse
+	13, // 72: easyanylink.v1.AdminService.CreateUser:output_type -> easyanylink.v1.UserResponse
+	13, // 73: easyanylink.v1.AdminService.RotateAPIKey:output_type -> easyanylink.v1.UserResponse
+	15, // 74: easyanylink.v1.AdminService.ListUsers:output_type -> easyanylink.v1.ListUsersResponse
+	20, // 75: easyanylink.v1.AdminService.GetUser:output_type -> easyanylink.v1.UserDetail
+	20, // 76: easyanylink.v1.AdminService.UpdateUser:output_type -> easyanylink.v1.UserDetail
+	19, // 77: easyanylink.v1.AdminService.DeleteUser:output_type -> easyanylink.v1.DeleteUserResponse
+	23, // 78: easyanylink.v1.AdminService.ExportUsage:output_type -> easyanylink.v1.ExportUsageResponse
+	25, // 79: easyanylink.v1.AdminService.ExportInventory:output_type -> easyanylink.v1.ExportInventoryResponse
+	27, // 80: easyanylink.v1.AdminService.SetRelayTracing:output_type -> easyanylink.v1.RelayTracingResponse
+	29, // 81: easyanylink.v1.AdminService.ListRelayTraces:output_type -> easyanylink.v1.ListRelayTracesResponse
+	32, // 82: easyanylink.v1.AdminService.GetHandshakeStats:output_type -> easyanylink.v1.HandshakeStatsResponse
+	8,  // 83: easyanylink.v1.AdminService.ArchiveAgent:output_type -> easyanylink.v1.AgentDetail
+	8,  // 84: easyanylink.v1.AdminService.RestoreAgent:output_type -> easyanylink.v1.AgentDetail
+	40, // 85: easyanylink.v1.AdminService.ListSessionHistory:output_type -> easyanylink.v1.ListSessionHistoryResponse
+	8,  // 86: easyanylink.v1.AdminService.ApproveAgent:output_type -> easyanylink.v1.AgentDetail
+	44, // 87: easyanylink.v1.AdminService.RejectAgent:output_type -> easyanylink.v1.RejectAgentResponse
+	47, // 88: easyanylink.v1.AdminService.ListACLRules:output_type -> easyanylink.v1.ListACLRulesResponse
+	45, // 89: easyanylink.v1.AdminService.AddACLRule:output_type -> easyanylink.v1.ACLRule
+	45, // 90: easyanylink.v1.AdminService.UpdateACLRule:output_type -> easyanylink.v1.ACLRule
+	51, // 91: easyanylink.v1.AdminService.DeleteACLRule:output_type -> easyanylink.v1.DeleteACLRuleResponse
+	34, // 92: easyanylink.v1.AdminService.GetCryptoPolicy:output_type -> easyanylink.v1.CryptoPolicyResponse
+	36, // 93: easyanylink.v1.AdminService.GetRelayQueueStats:output_type -> easyanylink.v1.RelayQueueStatsResponse
+	54, // 94: easyanylink.v1.AdminService.GetKeepalive:output_type -> easyanylink.v1.KeepaliveResponse
+	54, // 95: easyanylink.v1.AdminService.SetKeepalive:output_type -> easyanylink.v1.KeepaliveResponse
+	57, // 96: easyanylink.v1.AdminService.ListAgentGroups:output_type -> easyanylink.v1.ListAgentGroupsResponse
+	55, // 97: easyanylink.v1.AdminService.CreateAgentGroup:output_type -> easyanylink.v1.AgentGroup
+	55, // 98: easyanylink.v1.AdminService.UpdateAgentGroup:output_type -> easyanylink.v1.AgentGroup
+	59, // 99: easyanylink.v1.AdminService.DeleteAgentGroup:output_type -> easyanylink.v1.DeleteAgentGroupResponse
+	8,  // 100: easyanylink.v1.AdminService.SetAgentGroup:output_type -> easyanylink.v1.AgentDetail
+	66, // [66:101] is the sub-list for method output_type
+	31, // [31:66] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_common_proto_easyanylink_v1_admin_proto_init() }
+func file_common_proto_easyanylink_v1_admin_proto_init() {
+	if File_common_proto_easyanylink_v1_admin_proto != nil {
+		return
+	}
+	file_common_proto_easyanylink_v1_agent_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_proto_easyanylink_v1_admin_proto_rawDesc), len(file_common_proto_easyanylink_v1_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   62,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_common_proto_easyanylink_v1_admin_proto_goTypes,
+		DependencyIndexes: file_common_proto_easyanylink_v1_admin_proto_depIdxs,
+		MessageInfos:      file_common_proto_easyanylink_v1_admin_proto_msgTypes,
+	}.Build()
+	File_common_proto_easyanylink_v1_admin_proto = out.File
+	file_common_proto_easyanylink_v1_admin_proto_goTypes = nil
+	file_common_proto_easyanylink_v1_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,1501 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: common/proto/easyanylink/v1/admin.proto
+
+// Version 1 of the API, frozen. It is the API of the releases before API
+// versioning, which the server also serves under the unversioned "proto"
+// package name. Changes go to easyanylink.v2.
+
+package easyanylinkv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_AddRoutingRule_FullMethodName     = "/easyanylink.v1.AdminService/AddRoutingRule"
+	AdminService_UpdateRoutingRule_FullMethodName  = "/easyanylink.v1.AdminService/UpdateRoutingRule"
+	AdminService_DeleteRoutingRule_FullMethodName  = "/easyanylink.v1.AdminService/DeleteRoutingRule"
+	AdminService_ListAgents_FullMethodName         = "/easyanylink.v1.AdminService/ListAgents"
+	AdminService_GetAgent_FullMethodName           = "/easyanylink.v1.AdminService/GetAgent"
+	AdminService_ListRoutingRules_FullMethodName   = "/easyanylink.v1.AdminService/ListRoutingRules"
+	AdminService_CreateUser_FullMethodName         = "/easyanylink.v1.AdminService/CreateUser"
+	AdminService_RotateAPIKey_FullMethodName       = "/easyanylink.v1.AdminService/RotateAPIKey"
+	AdminService_ListUsers_FullMethodName          = "/easyanylink.v1.AdminService/ListUsers"
+	AdminService_GetUser_FullMethodName            = "/easyanylink.v1.AdminService/GetUser"
+	AdminService_UpdateUser_FullMethodName         = "/easyanylink.v1.AdminService/UpdateUser"
+	AdminService_DeleteUser_FullMethodName         = "/easyanylink.v1.AdminService/DeleteUser"
+	AdminService_ExportUsage_FullMethodName        = "/easyanylink.v1.AdminService/ExportUsage"
+	AdminService_ExportInventory_FullMethodName    = "/easyanylink.v1.AdminService/ExportInventory"
+	AdminService_SetRelayTracing_FullMethodName    = "/easyanylink.v1.AdminService/SetRelayTracing"
+	AdminService_ListRelayTraces_FullMethodName    = "/easyanylink.v1.AdminService/ListRelayTraces"
+	AdminService_GetHandshakeStats_FullMethodName  = "/easyanylink.v1.AdminService/GetHandshakeStats"
+	AdminService_ArchiveAgent_FullMethodName       = "/easyanylink.v1.AdminService/ArchiveAgent"
+	AdminService_RestoreAgent_FullMethodName       = "/easyanylink.v1.AdminService/RestoreAgent"
+	AdminService_ListSessionHistory_FullMethodName = "/easyanylink.v1.AdminService/ListSessionHistory"
+	AdminService_ApproveAgent_FullMethodName       = "/easyanylink.v1.AdminService/ApproveAgent"
+	AdminService_RejectAgent_FullMethodName        = "/easyanylink.v1.AdminService/RejectAgent"
+	AdminService_ListACLRules_FullMethodName       = "/easyanylink.v1.AdminService/ListACLRules"
+	AdminService_AddACLRule_FullMethodName         = "/easyanylink.v1.AdminService/AddACLRule"
+	AdminService_UpdateACLRule_FullMethodName      = "/easyanylink.v1.AdminService/UpdateACLRule"
+	AdminService_DeleteACLRule_FullMethodName      = "/easyanylink.v1.AdminService/DeleteACLRule"
+	AdminService_GetCryptoPolicy_FullMethodName    = "/easyanylink.v1.AdminService/GetCryptoPolicy"
+	AdminService_GetRelayQueueStats_FullMethodName = "/easyanylink.v1.AdminService/GetRelayQueueStats"
+	AdminService_GetKeepalive_FullMethodName       = "/easyanylink.v1.AdminService/GetKeepalive"
+	AdminService_SetKeepalive_FullMethodName       = "/easyanylink.v1.AdminService/SetKeepalive"
+	AdminService_ListAgentGroups_FullMethodName    = "/easyanylink.v1.AdminService/ListAgentGroups"
+	AdminService_CreateAgentGroup_FullMethodName   = "/easyanylink.v1.AdminService/CreateAgentGroup"
+	AdminService_UpdateAgentGroup_FullMethodName   = "/easyanylink.v1.AdminService/UpdateAgentGroup"
+	AdminService_DeleteAgentGroup_FullMethodName   = "/easyanylink.v1.AdminService/DeleteAgentGroup"
+	AdminService_SetAgentGroup_FullMethodName      = "/easyanylink.v1.AdminService/SetAgentGroup"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService defines the gRPC service for administrative tooling.
+// All calls must carry the API key of an admin user in the "x-api-key"
+// request metadata.
+type AdminServiceClient interface {
+	// Add a routing rule for an agent
+	AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*RoutingRuleResponse, error)
+	// Replace an existing routing rule
+	UpdateRoutingRule(ctx context.Context, in *UpdateRoutingRuleRequest, opts ...grpc.CallOption) (*RoutingRuleResponse, error)
+	// Delete a routing rule
+	DeleteRoutingRule(ctx context.Context, in *DeleteRoutingRuleRequest, opts ...grpc.CallOption) (*DeleteRoutingRuleResponse, error)
+	// List registered agents with optional filters
+	ListAgents(ctx context.Context, in *ListAgentsRequest, opts ...grpc.CallOption) (*ListAgentsResponse, error)
+	// Get a single agent
+	GetAgent(ctx context.Context, in *GetAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// List all routing rules of an agent, including disabled ones
+	ListRoutingRules(ctx context.Context, in *ListRoutingRulesRequest, opts ...grpc.CallOption) (*ListRoutingRulesResponse, error)
+	// Create a user account with a new API key
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	// Replace the API key of a user
+	RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	// List all user accounts with their quotas and current usage
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	// Get a user account with its quotas and monthly usage
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*UserDetail, error)
+	// Update the email, role, status and quotas of a user
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UserDetail, error)
+	// Delete a user together with its agents
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
+	// Export the relayed traffic of every user in a month for billing
+	ExportUsage(ctx context.Context, in *ExportUsageRequest, opts ...grpc.CallOption) (*ExportUsageResponse, error)
+	// Export the overlay addresses of the agents as a hosts file fragment
+	// or an Ansible inventory
+	ExportInventory(ctx context.Context, in *ExportInventoryRequest, opts ...grpc.CallOption) (*ExportInventoryResponse, error)
+	// Change the relay trace sampling rate at runtime
+	SetRelayTracing(ctx context.Context, in *SetRelayTracingRequest, opts ...grpc.CallOption) (*RelayTracingResponse, error)
+	// List recently sampled relay decisions, newest first
+	ListRelayTraces(ctx context.Context, in *ListRelayTracesRequest, opts ...grpc.CallOption) (*ListRelayTracesResponse, error)
+	// Get QUIC handshake address validation counters
+	GetHandshakeStats(ctx context.Context, in *GetHandshakeStatsRequest, opts ...grpc.CallOption) (*HandshakeStatsResponse, error)
+	// Archive an agent, disconnecting it but keeping its history
+	ArchiveAgent(ctx context.Context, in *ArchiveAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// Restore an archived agent
+	RestoreAgent(ctx context.Context, in *RestoreAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// List ended sessions of an agent, newest first
+	ListSessionHistory(ctx context.Context, in *ListSessionHistoryRequest, opts ...grpc.CallOption) (*ListSessionHistoryResponse, error)
+	// Approve an agent awaiting approval, allowing it to connect
+	ApproveAgent(ctx context.Context, in *ApproveAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// Reject an agent awaiting approval, deleting it
+	RejectAgent(ctx context.Context, in *RejectAgentRequest, opts ...grpc.CallOption) (*RejectAgentResponse, error)
+	// List the ACL rules of a user, including disabled ones
+	ListACLRules(ctx context.Context, in *ListACLRulesRequest, opts ...grpc.CallOption) (*ListACLRulesResponse, error)
+	// Add an ACL rule filtering the traffic relayed from a user's agents
+	AddACLRule(ctx context.Context, in *AddACLRuleRequest, opts ...grpc.CallOption) (*ACLRule, error)
+	// Replace an existing ACL rule
+	UpdateACLRule(ctx context.Context, in *UpdateACLRuleRequest, opts ...grpc.CallOption) (*ACLRule, error)
+	// Delete an ACL rule
+	DeleteACLRule(ctx context.Context, in *DeleteACLRuleRequest, opts ...grpc.CallOption) (*DeleteACLRuleResponse, error)
+	// Get the crypto policy enforced by the server's TLS
+	GetCryptoPolicy(ctx context.Context, in *GetCryptoPolicyRequest, opts ...grpc.CallOption) (*CryptoPolicyResponse, error)
+	// Get the per traffic class counters of the server relay queues
+	GetRelayQueueStats(ctx context.Context, in *GetRelayQueueStatsRequest, opts ...grpc.CallOption) (*RelayQueueStatsResponse, error)
+	// Get the heartbeat settings sent to agents
+	GetKeepalive(ctx context.Context, in *GetKeepaliveRequest, opts ...grpc.CallOption) (*KeepaliveResponse, error)
+	// Change the heartbeat settings until the server restarts, connected
+	// agents apply them with their next heartbeat
+	SetKeepalive(ctx context.Context, in *SetKeepaliveRequest, opts ...grpc.CallOption) (*KeepaliveResponse, error)
+	// List agent groups
+	ListAgentGroups(ctx context.Context, in *ListAgentGroupsRequest, opts ...grpc.CallOption) (*ListAgentGroupsResponse, error)
+	// Create an agent group
+	CreateAgentGroup(ctx context.Context, in *AgentGroup, opts ...grpc.CallOption) (*AgentGroup, error)
+	// Replace the settings of an agent group, its connected agents apply
+	// them at once
+	UpdateAgentGroup(ctx context.Context, in *AgentGroup, opts ...grpc.CallOption) (*AgentGroup, error)
+	// Delete an agent group together with its routing rules
+	DeleteAgentGroup(ctx context.Context, in *DeleteAgentGroupRequest, opts ...grpc.CallOption) (*DeleteAgentGroupResponse, error)
+	// Move an agent into a group or out of its group
+	SetAgentGroup(ctx context.Context, in *SetAgentGroupRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*RoutingRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoutingRuleResponse)
+	err := c.cc.Invoke(ctx, AdminService_AddRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateRoutingRule(ctx context.Context, in *UpdateRoutingRuleRequest, opts ...grpc.CallOption) (*RoutingRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoutingRuleResponse)
+	err := c.cc.Invoke(ctx, AdminService_UpdateRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteRoutingRule(ctx context.Context, in *DeleteRoutingRuleRequest, opts ...grpc.CallOption) (*DeleteRoutingRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRoutingRuleResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAgents(ctx context.Context, in *ListAgentsRequest, opts ...grpc.CallOption) (*ListAgentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAgentsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAgents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetAgent(ctx context.Context, in *GetAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_GetAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListRoutingRules(ctx context.Context, in *ListRoutingRulesRequest, opts ...grpc.CallOption) (*ListRoutingRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRoutingRulesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListRoutingRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, AdminService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, AdminService_RotateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*UserDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserDetail)
+	err := c.cc.Invoke(ctx, AdminService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UserDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserDetail)
+	err := c.cc.Invoke(ctx, AdminService_UpdateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUserResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExportUsage(ctx context.Context, in *ExportUsageRequest, opts ...grpc.CallOption) (*ExportUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportUsageResponse)
+	err := c.cc.Invoke(ctx, AdminService_ExportUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExportInventory(ctx context.Context, in *ExportInventoryRequest, opts ...grpc.CallOption) (*ExportInventoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportInventoryResponse)
+	err := c.cc.Invoke(ctx, AdminService_ExportInventory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetRelayTracing(ctx context.Context, in *SetRelayTracingRequest, opts ...grpc.CallOption) (*RelayTracingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RelayTracingResponse)
+	err := c.cc.Invoke(ctx, AdminService_SetRelayTracing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListRelayTraces(ctx context.Context, in *ListRelayTracesRequest, opts ...grpc.CallOption) (*ListRelayTracesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRelayTracesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListRelayTraces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetHandshakeStats(ctx context.Context, in *GetHandshakeStatsRequest, opts ...grpc.CallOption) (*HandshakeStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandshakeStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetHandshakeStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ArchiveAgent(ctx context.Context, in *ArchiveAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_ArchiveAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RestoreAgent(ctx context.Context, in *RestoreAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_RestoreAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListSessionHistory(ctx context.Context, in *ListSessionHistoryRequest, opts ...grpc.CallOption) (*ListSessionHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionHistoryResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListSessionHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ApproveAgent(ctx context.Context, in *ApproveAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_ApproveAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RejectAgent(ctx context.Context, in *RejectAgentRequest, opts ...grpc.CallOption) (*RejectAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectAgentResponse)
+	err := c.cc.Invoke(ctx, AdminService_RejectAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListACLRules(ctx context.Context, in *ListACLRulesRequest, opts ...grpc.CallOption) (*ListACLRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListACLRulesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListACLRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) AddACLRule(ctx context.Context, in *AddACLRuleRequest, opts ...grpc.CallOption) (*ACLRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ACLRule)
+	err := c.cc.Invoke(ctx, AdminService_AddACLRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateACLRule(ctx context.Context, in *UpdateACLRuleRequest, opts ...grpc.CallOption) (*ACLRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ACLRule)
+	err := c.cc.Invoke(ctx, AdminService_UpdateACLRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteACLRule(ctx context.Context, in *DeleteACLRuleRequest, opts ...grpc.CallOption) (*DeleteACLRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteACLRuleResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteACLRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetCryptoPolicy(ctx context.Context, in *GetCryptoPolicyRequest, opts ...grpc.CallOption) (*CryptoPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CryptoPolicyResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetCryptoPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetRelayQueueStats(ctx context.Context, in *GetRelayQueueStatsRequest, opts ...grpc.CallOption) (*RelayQueueStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RelayQueueStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetRelayQueueStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetKeepalive(ctx context.Context, in *GetKeepaliveRequest, opts ...grpc.CallOption) (*KeepaliveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeepaliveResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetKeepalive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetKeepalive(ctx context.Context, in *SetKeepaliveRequest, opts ...grpc.CallOption) (*KeepaliveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeepaliveResponse)
+	err := c.cc.Invoke(ctx, AdminService_SetKeepalive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAgentGroups(ctx context.Context, in *ListAgentGroupsRequest, opts ...grpc.CallOption) (*ListAgentGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAgentGroupsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAgentGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateAgentGroup(ctx context.Context, in *AgentGroup, opts ...grpc.CallOption) (*AgentGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentGroup)
+	err := c.cc.Invoke(ctx, AdminService_CreateAgentGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateAgentGroup(ctx context.Context, in *AgentGroup, opts ...grpc.CallOption) (*AgentGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentGroup)
+	err := c.cc.Invoke(ctx, AdminService_UpdateAgentGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteAgentGroup(ctx context.Context, in *DeleteAgentGroupRequest, opts ...grpc.CallOption) (*DeleteAgentGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAgentGroupResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteAgentGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetAgentGroup(ctx context.Context, in *SetAgentGroupRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_SetAgentGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService defines the gRPC service for administrative tooling.
+// All calls must carry the API key of an admin user in the "x-api-key"
+// request metadata.
+type AdminServiceServer interface {
+	// Add a routing rule for an agent
+	AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*RoutingRuleResponse, error)
+	// Replace an existing routing rule
+	UpdateRoutingRule(context.Context, *UpdateRoutingRuleRequest) (*RoutingRuleResponse, error)
+	// Delete a routing rule
+	DeleteRoutingRule(context.Context, *DeleteRoutingRuleRequest) (*DeleteRoutingRuleResponse, error)
+	// List registered agents with optional filters
+	ListAgents(context.Context, *ListAgentsRequest) (*ListAgentsResponse, error)
+	// Get a single agent
+	GetAgent(context.Context, *GetAgentRequest) (*AgentDetail, error)
+	// List all routing rules of an agent, including disabled ones
+	ListRoutingRules(context.Context, *ListRoutingRulesRequest) (*ListRoutingRulesResponse, error)
+	// Create a user account with a new API key
+	CreateUser(context.Context, *CreateUserRequest) (*UserResponse, error)
+	// Replace the API key of a user
+	RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*UserResponse, error)
+	// List all user accounts with their quotas and current usage
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	// Get a user account with its quotas and monthly usage
+	GetUser(context.Context, *GetUserRequest) (*UserDetail, error)
+	// Update the email, role, status and quotas of a user
+	UpdateUser(context.Context, *UpdateUserRequest) (*UserDetail, error)
+	// Delete a user together with its agents
+	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
+	// Export the relayed traffic of every user in a month for billing
+	ExportUsage(context.Context, *ExportUsageRequest) (*ExportUsageResponse, error)
+	// Export the overlay addresses of the agents as a hosts file fragment
+	// or an Ansible inventory
+	ExportInventory(context.Context, *ExportInventoryRequest) (*ExportInventoryResponse, error)
+	// Change the relay trace sampling rate at runtime
+	SetRelayTracing(context.Context, *SetRelayTracingRequest) (*RelayTracingResponse, error)
+	// List recently sampled relay decisions, newest first
+	ListRelayTraces(context.Context, *ListRelayTracesRequest) (*ListRelayTracesResponse, error)
+	// Get QUIC handshake address validation counters
+	GetHandshakeStats(context.Context, *GetHandshakeStatsRequest) (*HandshakeStatsResponse, error)
+	// Archive an agent, disconnecting it but keeping its history
+	ArchiveAgent(context.Context, *ArchiveAgentRequest) (*AgentDetail, error)
+	// Restore an archived agent
+	RestoreAgent(context.Context, *RestoreAgentRequest) (*AgentDetail, error)
+	// List ended sessions of an agent, newest first
+	ListSessionHistory(context.Context, *ListSessionHistoryRequest) (*ListSessionHistoryResponse, error)
+	// Approve an agent awaiting approval, allowing it to connect
+	ApproveAgent(context.Context, *ApproveAgentRequest) (*AgentDetail, error)
+	// Reject an agent awaiting approval, deleting it
+	RejectAgent(context.Context, *RejectAgentRequest) (*RejectAgentResponse, error)
+	// List the ACL rules of a user, including disabled ones
+	ListACLRules(context.Context, *ListACLRulesRequest) (*ListACLRulesResponse, error)
+	// Add an ACL rule filtering the traffic relayed from a user's agents
+	AddACLRule(context.Context, *AddACLRuleRequest) (*ACLRule, error)
+	// Replace an existing ACL rule
+	UpdateACLRule(context.Context, *UpdateACLRuleRequest) (*ACLRule, error)
+	// Delete an ACL rule
+	DeleteACLRule(context.Context, *DeleteACLRuleRequest) (*DeleteACLRuleResponse, error)
+	// Get the crypto policy enforced by the server's TLS
+	GetCryptoPolicy(context.Context, *GetCryptoPolicyRequest) (*CryptoPolicyResponse, error)
+	// Get the per traffic class counters of the server relay queues
+	GetRelayQueueStats(context.Context, *GetRelayQueueStatsRequest) (*RelayQueueStatsResponse, error)
+	// Get the heartbeat settings sent to agents
+	GetKeepalive(context.Context, *GetKeepaliveRequest) (*KeepaliveResponse, error)
+	// Change the heartbeat settings until the server restarts, connected
+	// agents apply them with their next heartbeat
+	SetKeepalive(context.Context, *SetKeepaliveRequest) (*KeepaliveResponse, error)
+	// List agent groups
+	ListAgentGroups(context.Context, *ListAgentGroupsRequest) (*ListAgentGroupsResponse, error)
+	// Create an agent group
+	CreateAgentGroup(context.Context, *AgentGroup) (*AgentGroup, error)
+	// Replace the settings of an agent group, its connected agents apply
+	// them at once
+	UpdateAgentGroup(context.Context, *AgentGroup) (*AgentGroup, error)
+	// Delete an agent group together with its routing rules
+	DeleteAgentGroup(context.Context, *DeleteAgentGroupRequest) (*DeleteAgentGroupResponse, error)
+	// Move an agent into a group or out of its group
+	SetAgentGroup(context.Context, *SetAgentGroupRequest) (*AgentDetail, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*RoutingRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRoutingRule not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateRoutingRule(context.Context, *UpdateRoutingRuleRequest) (*RoutingRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRoutingRule not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteRoutingRule(context.Context, *DeleteRoutingRuleRequest) (*DeleteRoutingRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRoutingRule not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAgents(context.Context, *ListAgentsRequest) (*ListAgentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAgents not implemented")
+}
+func (UnimplementedAdminServiceServer) GetAgent(context.Context, *GetAgentRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) ListRoutingRules(context.Context, *ListRoutingRulesRequest) (*ListRoutingRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoutingRules not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateUser(context.Context, *CreateUserRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedAdminServiceServer) RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateAPIKey not implemented")
+}
+func (UnimplementedAdminServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedAdminServiceServer) GetUser(context.Context, *GetUserRequest) (*UserDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateUser(context.Context, *UpdateUserRequest) (*UserDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedAdminServiceServer) ExportUsage(context.Context, *ExportUsageRequest) (*ExportUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportUsage not implemented")
+}
+func (UnimplementedAdminServiceServer) ExportInventory(context.Context, *ExportInventoryRequest) (*ExportInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportInventory not implemented")
+}
+func (UnimplementedAdminServiceServer) SetRelayTracing(context.Context, *SetRelayTracingRequest) (*RelayTracingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRelayTracing not implemented")
+}
+func (UnimplementedAdminServiceServer) ListRelayTraces(context.Context, *ListRelayTracesRequest) (*ListRelayTracesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRelayTraces not implemented")
+}
+func (UnimplementedAdminServiceServer) GetHandshakeStats(context.Context, *GetHandshakeStatsRequest) (*HandshakeStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHandshakeStats not implemented")
+}
+func (UnimplementedAdminServiceServer) ArchiveAgent(context.Context, *ArchiveAgentRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) RestoreAgent(context.Context, *RestoreAgentRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) ListSessionHistory(context.Context, *ListSessionHistoryRequest) (*ListSessionHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessionHistory not implemented")
+}
+func (UnimplementedAdminServiceServer) ApproveAgent(context.Context, *ApproveAgentRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) RejectAgent(context.Context, *RejectAgentRequest) (*RejectAgentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) ListACLRules(context.Context, *ListACLRulesRequest) (*ListACLRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListACLRules not implemented")
+}
+func (UnimplementedAdminServiceServer) AddACLRule(context.Context, *AddACLRuleRequest) (*ACLRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddACLRule not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateACLRule(context.Context, *UpdateACLRuleRequest) (*ACLRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateACLRule not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteACLRule(context.Context, *DeleteACLRuleRequest) (*DeleteACLRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteACLRule not implemented")
+}
+func (UnimplementedAdminServiceServer) GetCryptoPolicy(context.Context, *GetCryptoPolicyRequest) (*CryptoPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCryptoPolicy not implemented")
+}
+func (UnimplementedAdminServiceServer) GetRelayQueueStats(context.Context, *GetRelayQueueStatsRequest) (*RelayQueueStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelayQueueStats not implemented")
+}
+func (UnimplementedAdminServiceServer) GetKeepalive(context.Context, *GetKeepaliveRequest) (*KeepaliveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeepalive not implemented")
+}
+func (UnimplementedAdminServiceServer) SetKeepalive(context.Context, *SetKeepaliveRequest) (*KeepaliveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetKeepalive not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAgentGroups(context.Context, *ListAgentGroupsRequest) (*ListAgentGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAgentGroups not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateAgentGroup(context.Context, *AgentGroup) (*AgentGroup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAgentGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateAgentGroup(context.Context, *AgentGroup) (*AgentGroup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAgentGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteAgentGroup(context.Context, *DeleteAgentGroupRequest) (*DeleteAgentGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAgentGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) SetAgentGroup(context.Context, *SetAgentGroupRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAgentGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_AddRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).AddRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_AddRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).AddRoutingRule(ctx, req.(*AddRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateRoutingRule(ctx, req.(*UpdateRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteRoutingRule(ctx, req.(*DeleteRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAgents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAgents(ctx, req.(*ListAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetAgent(ctx, req.(*GetAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListRoutingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoutingRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListRoutingRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListRoutingRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListRoutingRules(ctx, req.(*ListRoutingRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RotateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RotateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RotateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RotateAPIKey(ctx, req.(*RotateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExportUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ExportUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ExportUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ExportUsage(ctx, req.(*ExportUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExportInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ExportInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ExportInventory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ExportInventory(ctx, req.(*ExportInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetRelayTracing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRelayTracingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetRelayTracing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetRelayTracing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetRelayTracing(ctx, req.(*SetRelayTracingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListRelayTraces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRelayTracesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListRelayTraces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListRelayTraces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListRelayTraces(ctx, req.(*ListRelayTracesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetHandshakeStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHandshakeStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetHandshakeStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetHandshakeStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetHandshakeStats(ctx, req.(*GetHandshakeStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ArchiveAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ArchiveAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ArchiveAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ArchiveAgent(ctx, req.(*ArchiveAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RestoreAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RestoreAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RestoreAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RestoreAgent(ctx, req.(*RestoreAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListSessionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListSessionHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListSessionHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListSessionHistory(ctx, req.(*ListSessionHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ApproveAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ApproveAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ApproveAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ApproveAgent(ctx, req.(*ApproveAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RejectAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RejectAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RejectAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RejectAgent(ctx, req.(*RejectAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListACLRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListACLRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListACLRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListACLRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListACLRules(ctx, req.(*ListACLRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_AddACLRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddACLRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).AddACLRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_AddACLRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).AddACLRule(ctx, req.(*AddACLRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateACLRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateACLRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateACLRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateACLRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateACLRule(ctx, req.(*UpdateACLRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteACLRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteACLRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteACLRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteACLRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteACLRule(ctx, req.(*DeleteACLRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetCryptoPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCryptoPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetCryptoPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetCryptoPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetCryptoPolicy(ctx, req.(*GetCryptoPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetRelayQueueStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelayQueueStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetRelayQueueStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetRelayQueueStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetRelayQueueStats(ctx, req.(*GetRelayQueueStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetKeepalive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeepaliveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetKeepalive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetKeepalive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetKeepalive(ctx, req.(*GetKeepaliveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetKeepalive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeepaliveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetKeepalive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetKeepalive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetKeepalive(ctx, req.(*SetKeepaliveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAgentGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAgentGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAgentGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAgentGroups(ctx, req.(*ListAgentGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateAgentGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateAgentGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateAgentGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateAgentGroup(ctx, req.(*AgentGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateAgentGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateAgentGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateAgentGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateAgentGroup(ctx, req.(*AgentGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteAgentGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAgentGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteAgentGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteAgentGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteAgentGroup(ctx, req.(*DeleteAgentGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetAgentGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAgentGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetAgentGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetAgentGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetAgentGroup(ctx, req.(*SetAgentGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "easyanylink.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddRoutingRule",
+			Handler:    _AdminService_AddRoutingRule_Handler,
+		},
+		{
+			MethodName: "UpdateRoutingRule",
+			Handler:    _AdminService_UpdateRoutingRule_Handler,
+		},
+		{
+			MethodName: "DeleteRoutingRule",
+			Handler:    _AdminService_DeleteRoutingRule_Handler,
+		},
+		{
+			MethodName: "ListAgents",
+			Handler:    _AdminService_ListAgents_Handler,
+		},
+		{
+			MethodName: "GetAgent",
+			Handler:    _AdminService_GetAgent_Handler,
+		},
+		{
+			MethodName: "ListRoutingRules",
+			Handler:    _AdminService_ListRoutingRules_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _AdminService_CreateUser_Handler,
+		},
+		{
+			MethodName: "RotateAPIKey",
+			Handler:    _AdminService_RotateAPIKey_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _AdminService_ListUsers_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _AdminService_GetUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _AdminService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _AdminService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "ExportUsage",
+			Handler:    _AdminService_ExportUsage_Handler,
+		},
+		{
+			MethodName: "ExportInventory",
+			Handler:    _AdminService_ExportInventory_Handler,
+		},
+		{
+			MethodName: "SetRelayTracing",
+			Handler:    _AdminService_SetRelayTracing_Handler,
+		},
+		{
+			MethodName: "ListRelayTraces",
+			Handler:    _AdminService_ListRelayTraces_Handler,
+		},
+		{
+			MethodName: "GetHandshakeStats",
+			Handler:    _AdminService_GetHandshakeStats_Handler,
+		},
+		{
+			MethodName: "ArchiveAgent",
+			Handler:    _AdminService_ArchiveAgent_Handler,
+		},
+		{
+			MethodName: "RestoreAgent",
+			Handler:    _AdminService_RestoreAgent_Handler,
+		},
+		{
+			MethodName: "ListSessionHistory",
+			Handler:    _AdminService_ListSessionHistory_Handler,
+		},
+		{
+			MethodName: "ApproveAgent",
+			Handler:    _AdminService_ApproveAgent_Handler,
+		},
+		{
+			MethodName: "RejectAgent",
+			Handler:    _AdminService_RejectAgent_Handler,
+		},
+		{
+			MethodName: "ListACLRules",
+			Handler:    _AdminService_ListACLRules_Handler,
+		},
+		{
+			MethodName: "AddACLRule",
+			Handler:    _AdminService_AddACLRule_Handler,
+		},
+		{
+			MethodName: "UpdateACLRule",
+			Handler:    _AdminService_UpdateACLRule_Handler,
+		},
+		{
+			MethodName: "DeleteACLRule",
+			Handler:    _AdminService_DeleteACLRule_Handler,
+		},
+		{
+			MethodName: "GetCryptoPolicy",
+			Handler:    _AdminService_GetCryptoPolicy_Handler,
+		},
+		{
+			MethodName: "GetRelayQueueStats",
+			Handler:    _AdminService_GetRelayQueueStats_Handler,
+		},
+		{
+			MethodName: "GetKeepalive",
+			Handler:    _AdminService_GetKeepalive_Handler,
+		},
+		{
+			MethodName: "SetKeepalive",
+			Handler:    _AdminService_SetKeepalive_Handler,
+		},
+		{
+			MethodName: "ListAgentGroups",
+			Handler:    _AdminService_ListAgentGroups_Handler,
+		},
+		{
+			MethodName: "CreateAgentGroup",
+			Handler:    _AdminService_CreateAgentGroup_Handler,
+		},
+		{
+			MethodName: "UpdateAgentGroup",
+			Handler:    _AdminService_UpdateAgentGroup_Handler,
+		},
+		{
+			MethodName: "DeleteAgentGroup",
+			Handler:    _AdminService_DeleteAgentGroup_Handler,
+		},
+		{
+			MethodName: "SetAgentGroup",
+			Handler:    _AdminService_SetAgentGroup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "common/proto/easyanylink/v1/admin.proto",
+}
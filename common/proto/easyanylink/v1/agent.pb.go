@@ -0,0 +1,2326 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: common/proto/easyanylink/v1/agent.proto
+
+// Version 1 of the API, frozen. It is the API of the releases before API
+// versioning, which the server also serves under the unversioned "proto"
+// package name. Changes go to easyanylink.v2.
+
+package easyanylinkv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AgentType defines the role of the agent
+type AgentType int32
+
+const (
+	AgentType_AGENT_TYPE_UNSPECIFIED AgentType = 0
+	AgentType_CLIENT                 AgentType = 1
+	AgentType_GATEWAY                AgentType = 2
+)
+
+// Enum value maps for AgentType.
+var (
+	AgentType_name = map[int32]string{
+		0: "AGENT_TYPE_UNSPECIFIED",
+		1: "CLIENT",
+		2: "GATEWAY",
+	}
+	AgentType_value = map[string]int32{
+		"AGENT_TYPE_UNSPECIFIED": 0,
+		"CLIENT":                 1,
+		"GATEWAY":                2,
+	}
+)
+
+func (x AgentType) Enum() *AgentType {
+	p := new(AgentType)
+	*p = x
+	return p
+}
+
+func (x AgentType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AgentType) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_proto_easyanylink_v1_agent_proto_enumTypes[0].Descriptor()
+}
+
+func (AgentType) Type() protoreflect.EnumType {
+	return &file_common_proto_easyanylink_v1_agent_proto_enumTypes[0]
+}
+
+func (x AgentType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AgentType.Descriptor instead.
+func (AgentType) EnumDescriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{0}
+}
+
+// RouteAction defines what to do with matching packets
+type RouteAction int32
+
+const (
+	RouteAction_ROUTE_ACTION_UNSPECIFIED RouteAction = 0
+	RouteAction_FORWARD                  RouteAction = 1 // Forward through gateway
+	RouteAction_DIRECT                   RouteAction = 2 // Send directly (local routing)
+	RouteAction_DENY                     RouteAction = 3 // Drop the packet
+)
+
+// Enum value maps for RouteAction.
+var (
+	RouteAction_name = map[int32]string{
+		0: "ROUTE_ACTION_UNSPECIFIED",
+		1: "FORWARD",
+		2: "DIRECT",
+		3: "DENY",
+	}
+	RouteAction_value = map[string]int32{
+		"ROUTE_ACTION_UNSPECIFIED": 0,
+		"FORWARD":                  1,
+		"DIRECT":                   2,
+		"DENY":                     3,
+	}
+)
+
+func (x RouteAction) Enum() *RouteAction {
+	p := new(RouteAction)
+	*p = x
+	return p
+}
+
+func (x RouteAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RouteAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_proto_easyanylink_v1_agent_proto_enumTypes[1].Descriptor()
+}
+
+func (RouteAction) Type() protoreflect.EnumType {
+	return &file_common_proto_easyanylink_v1_agent_proto_enumTypes[1]
+}
+
+func (x RouteAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RouteAction.Descriptor instead.
+func (RouteAction) EnumDescriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{1}
+}
+
+// AgentStatus represents the operational state
+type AgentStatus int32
+
+const (
+	AgentStatus_AGENT_STATUS_UNSPECIFIED AgentStatus = 0
+	AgentStatus_ONLINE                   AgentStatus = 1
+	AgentStatus_OFFLINE                  AgentStatus = 2
+	AgentStatus_ERROR                    AgentStatus = 3
+	AgentStatus_MAINTENANCE              AgentStatus = 4
+)
+
+// Enum value maps for AgentStatus.
+var (
+	AgentStatus_name = map[int32]string{
+		0: "AGENT_STATUS_UNSPECIFIED",
+		1: "ONLINE",
+		2: "OFFLINE",
+		3: "ERROR",
+		4: "MAINTENANCE",
+	}
+	AgentStatus_value = map[string]int32{
+		"AGENT_STATUS_UNSPECIFIED": 0,
+		"ONLINE":                   1,
+		"OFFLINE":                  2,
+		"ERROR":                    3,
+		"MAINTENANCE":              4,
+	}
+)
+
+func (x AgentStatus) Enum() *AgentStatus {
+	p := new(AgentStatus)
+	*p = x
+	return p
+}
+
+func (x AgentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AgentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_proto_easyanylink_v1_agent_proto_enumTypes[2].Descriptor()
+}
+
+func (AgentStatus) Type() protoreflect.EnumType {
+	return &file_common_proto_easyanylink_v1_agent_proto_enumTypes[2]
+}
+
+func (x AgentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AgentStatus.Descriptor instead.
+func (AgentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{2}
+}
+
+// DisconnectReason classifies why a session ended
+type DisconnectReason int32
+
+const (
+	DisconnectReason_DISCONNECT_REASON_UNSPECIFIED DisconnectReason = 0
+	DisconnectReason_DISCONNECT_SERVER_DECISION    DisconnectReason = 1 // An admin or the server ended the session
+	DisconnectReason_DISCONNECT_AUTH_REVOKED       DisconnectReason = 2 // The user was deleted or deactivated
+	DisconnectReason_DISCONNECT_IDLE_TIMEOUT       DisconnectReason = 3 // No heartbeat within the keepalive timeout
+	DisconnectReason_DISCONNECT_AGENT_SHUTDOWN     DisconnectReason = 4 // The agent stopped
+	DisconnectReason_DISCONNECT_TRANSPORT_ERROR    DisconnectReason = 5 // The relay or heartbeat streams failed
+	DisconnectReason_DISCONNECT_AGENT_ERROR        DisconnectReason = 6 // The agent reported a fatal error
+)
+
+// Enum value maps for DisconnectReason.
+var (
+	DisconnectReason_name = map[int32]string{
+		0: "DISCONNECT_REASON_UNSPECIFIED",
+		1: "DISCONNECT_SERVER_DECISION",
+		2: "DISCONNECT_AUTH_REVOKED",
+		3: "DISCONNECT_IDLE_TIMEOUT",
+		4: "DISCONNECT_AGENT_SHUTDOWN",
+		5: "DISCONNECT_TRANSPORT_ERROR",
+		6: "DISCONNECT_AGENT_ERROR",
+	}
+	DisconnectReason_value = map[string]int32{
+		"DISCONNECT_REASON_UNSPECIFIED": 0,
+		"DISCONNECT_SERVER_DECISION":    1,
+		"DISCONNECT_AUTH_REVOKED":       2,
+		"DISCONNECT_IDLE_TIMEOUT":       3,
+		"DISCONNECT_AGENT_SHUTDOWN":     4,
+		"DISCONNECT_TRANSPORT_ERROR":    5,
+		"DISCONNECT_AGENT_ERROR":        6,
+	}
+)
+
+func (x DisconnectReason) Enum() *DisconnectReason {
+	p := new(DisconnectReason)
+	*p = x
+	return p
+}
+
+func (x DisconnectReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisconnectReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_proto_easyanylink_v1_agent_proto_enumTypes[3].Descriptor()
+}
+
+func (DisconnectReason) Type() protoreflect.EnumType {
+	return &file_common_proto_easyanylink_v1_agent_proto_enumTypes[3]
+}
+
+func (x DisconnectReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisconnectReason.Descriptor instead.
+func (DisconnectReason) EnumDescriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{3}
+}
+
+// RegisterRequest is sent by agents during initial connection
+type RegisterRequest struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	AgentId                string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                                              // UUID of the agent
+	UserKey                string                 `protobuf:"bytes,2,opt,name=user_key,json=userKey,proto3" json:"user_key,omitempty"`                                              // User API key for authentication
+	Type                   AgentType              `protobuf:"varint,3,opt,name=type,proto3,enum=easyanylink.v1.AgentType" json:"type,omitempty"`                                    // Client or Gateway
+	ProtocolVersion        string                 `protobuf:"bytes,4,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`                      // Protocol version (e.g., "1.0.0")
+	CertificateFingerprint string                 `protobuf:"bytes,5,opt,name=certificate_fingerprint,json=certificateFingerprint,proto3" json:"certificate_fingerprint,omitempty"` // SHA256 fingerprint of client cert
+	Metadata               *AgentMetadata         `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`                                                           // Additional agent information
+	Bandwidth              int32                  `protobuf:"varint,7,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`                                                        // Bandwidth in KB/s, 0 for unlimited
+	RequestId              string                 `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`                                        // Idempotency key, reused when a registration is retried
+	Nonce                  []byte                 `protobuf:"bytes,9,opt,name=nonce,proto3" json:"nonce,omitempty"`                                                                 // Random per attempt, rejected if seen before (pre-shared key only)
+	Timestamp              int64                  `protobuf:"varint,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                                       // Unix seconds of the attempt (pre-shared key only)
+	Mac                    []byte                 `protobuf:"bytes,11,opt,name=mac,proto3" json:"mac,omitempty"`                                                                    // HMAC-SHA256 of the request under the pre-shared key, empty without one
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetUserKey() string {
+	if x != nil {
+		return x.UserKey
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetType() AgentType {
+	if x != nil {
+		return x.Type
+	}
+	return AgentType_AGENT_TYPE_UNSPECIFIED
+}
+
+func (x *RegisterRequest) GetProtocolVersion() string {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetCertificateFingerprint() string {
+	if x != nil {
+		return x.CertificateFingerprint
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetMetadata() *AgentMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetBandwidth() int32 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetMac() []byte {
+	if x != nil {
+		return x.Mac
+	}
+	return nil
+}
+
+// AgentMetadata contains platform and version information
+type AgentMetadata struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Os               string                 `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`                                                                                   // Operating system (linux, darwin, windows)
+	Arch             string                 `protobuf:"bytes,2,opt,name=arch,proto3" json:"arch,omitempty"`                                                                               // Architecture (amd64, arm64)
+	Version          string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`                                                                         // Agent version
+	Hostname         string                 `protobuf:"bytes,4,opt,name=hostname,proto3" json:"hostname,omitempty"`                                                                       // Hostname of the machine
+	Labels           map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Custom labels for filtering/grouping
+	Interfaces       []*NetworkInterface    `protobuf:"bytes,6,rep,name=interfaces,proto3" json:"interfaces,omitempty"`                                                                   // Physical network interfaces
+	DefaultGateway   string                 `protobuf:"bytes,7,opt,name=default_gateway,json=defaultGateway,proto3" json:"default_gateway,omitempty"`                                     // Next hop of the physical default route
+	DefaultInterface string                 `protobuf:"bytes,8,opt,name=default_interface,json=defaultInterface,proto3" json:"default_interface,omitempty"`                               // Interface of the physical default route
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AgentMetadata) Reset() {
+	*x = AgentMetadata{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentMetadata) ProtoMessage() {}
+
+func (x *AgentMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentMetadata.ProtoReflect.Descriptor instead.
+func (*AgentMetadata) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AgentMetadata) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *AgentMetadata) GetInterfaces() []*NetworkInterface {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *AgentMetadata) GetDefaultGateway() string {
+	if x != nil {
+		return x.DefaultGateway
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetDefaultInterface() string {
+	if x != nil {
+		return x.DefaultInterface
+	}
+	return ""
+}
+
+// NetworkInterface describes a network interface of the agent host
+type NetworkInterface struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`           // Interface name
+	Addresses     []string               `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty"` // Addresses in CIDR notation
+	Mac           string                 `protobuf:"bytes,3,opt,name=mac,proto3" json:"mac,omitempty"`             // Hardware address
+	Mtu           int32                  `protobuf:"varint,4,opt,name=mtu,proto3" json:"mtu,omitempty"`            // Interface MTU
+	Up            bool                   `protobuf:"varint,5,opt,name=up,proto3" json:"up,omitempty"`              // Whether the interface is up
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkInterface) Reset() {
+	*x = NetworkInterface{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkInterface) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkInterface) ProtoMessage() {}
+
+func (x *NetworkInterface) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkInterface.ProtoReflect.Descriptor instead.
+func (*NetworkInterface) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NetworkInterface) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *NetworkInterface) GetMac() string {
+	if x != nil {
+		return x.Mac
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetMtu() int32 {
+	if x != nil {
+		return x.Mtu
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetUp() bool {
+	if x != nil {
+		return x.Up
+	}
+	return false
+}
+
+// RegisterResponse is returned after successful registration
+type RegisterResponse struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Accepted                bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`                                                               // Whether registration was accepted
+	SessionId               string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                                             // Unique session identifier
+	AssignedIp              string                 `protobuf:"bytes,3,opt,name=assigned_ip,json=assignedIp,proto3" json:"assigned_ip,omitempty"`                                          // Assigned overlay IP address
+	ServerVersion           string                 `protobuf:"bytes,4,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`                                 // Server protocol version
+	MinimumSupportedVersion string                 `protobuf:"bytes,5,opt,name=minimum_supported_version,json=minimumSupportedVersion,proto3" json:"minimum_supported_version,omitempty"` // Minimum compatible version
+	ErrorMessage            string                 `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`                                    // Error description if not accepted
+	ServerConfig            *ServerConfig          `protobuf:"bytes,7,opt,name=server_config,json=serverConfig,proto3" json:"server_config,omitempty"`                                    // Server configuration parameters
+	ManagedConfig           *ManagedConfig         `protobuf:"bytes,8,opt,name=managed_config,json=managedConfig,proto3" json:"managed_config,omitempty"`                                 // Settings of the agent's group, unset without a group
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResponse) ProtoMessage() {}
+
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RegisterResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *RegisterResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetAssignedIp() string {
+	if x != nil {
+		return x.AssignedIp
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetServerVersion() string {
+	if x != nil {
+		return x.ServerVersion
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetMinimumSupportedVersion() string {
+	if x != nil {
+		return x.MinimumSupportedVersion
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetServerConfig() *ServerConfig {
+	if x != nil {
+		return x.ServerConfig
+	}
+	return nil
+}
+
+func (x *RegisterResponse) GetManagedConfig() *ManagedConfig {
+	if x != nil {
+		return x.ManagedConfig
+	}
+	return nil
+}
+
+// ServerConfig contains server-side configuration
+type ServerConfig struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	GatewayIp         string                 `protobuf:"bytes,1,opt,name=gateway_ip,json=gatewayIp,proto3" json:"gateway_ip,omitempty"`                          // Server's overlay IP (usually .0.1)
+	Mtu               int32                  `protobuf:"varint,2,opt,name=mtu,proto3" json:"mtu,omitempty"`                                                      // Maximum transmission unit
+	KeepaliveInterval int32                  `protobuf:"varint,3,opt,name=keepalive_interval,json=keepaliveInterval,proto3" json:"keepalive_interval,omitempty"` // Heartbeat interval in seconds
+	KeepaliveTimeout  int32                  `protobuf:"varint,4,opt,name=keepalive_timeout,json=keepaliveTimeout,proto3" json:"keepalive_timeout,omitempty"`    // Connection timeout in seconds
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ServerConfig) Reset() {
+	*x = ServerConfig{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerConfig) ProtoMessage() {}
+
+func (x *ServerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerConfig.ProtoReflect.Descriptor instead.
+func (*ServerConfig) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ServerConfig) GetGatewayIp() string {
+	if x != nil {
+		return x.GatewayIp
+	}
+	return ""
+}
+
+func (x *ServerConfig) GetMtu() int32 {
+	if x != nil {
+		return x.Mtu
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetKeepaliveInterval() int32 {
+	if x != nil {
+		return x.KeepaliveInterval
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetKeepaliveTimeout() int32 {
+	if x != nil {
+		return x.KeepaliveTimeout
+	}
+	return 0
+}
+
+// ManagedConfig carries the settings of the agent's group, computed by the
+// server. Set settings take precedence over the agent's configuration file.
+type ManagedConfig struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Group          string                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`                                          // Group name
+	DnsServers     []string               `protobuf:"bytes,2,rep,name=dns_servers,json=dnsServers,proto3" json:"dns_servers,omitempty"`              // Resolvers used while connected, empty keeps the agent setting
+	DnsSearch      []string               `protobuf:"bytes,3,rep,name=dns_search,json=dnsSearch,proto3" json:"dns_search,omitempty"`                 // Search domains used with dns_servers
+	BandwidthLimit int32                  `protobuf:"varint,4,opt,name=bandwidth_limit,json=bandwidthLimit,proto3" json:"bandwidth_limit,omitempty"` // KB/s the server relays for the agent, 0 for unlimited
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ManagedConfig) Reset() {
+	*x = ManagedConfig{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManagedConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManagedConfig) ProtoMessage() {}
+
+func (x *ManagedConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManagedConfig.ProtoReflect.Descriptor instead.
+func (*ManagedConfig) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ManagedConfig) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ManagedConfig) GetDnsServers() []string {
+	if x != nil {
+		return x.DnsServers
+	}
+	return nil
+}
+
+func (x *ManagedConfig) GetDnsSearch() []string {
+	if x != nil {
+		return x.DnsSearch
+	}
+	return nil
+}
+
+func (x *ManagedConfig) GetBandwidthLimit() int32 {
+	if x != nil {
+		return x.BandwidthLimit
+	}
+	return 0
+}
+
+// HeartbeatRequest is sent periodically to maintain connection
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Session identifier
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                  // Current timestamp
+	Stats         *AgentStats            `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`                          // Agent statistics
+	Metadata      *AgentMetadata         `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`                    // Refreshed metadata, set only when it changed
+	Health        *AgentHealth           `protobuf:"bytes,5,opt,name=health,proto3" json:"health,omitempty"`                        // Failing subsystems, unset while all are healthy
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HeartbeatRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetStats() *AgentStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetMetadata() *AgentMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetHealth() *AgentHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+// AgentHealth reports agent subsystems that failed and were restarted
+type AgentHealth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Degraded      bool                   `protobuf:"varint,1,opt,name=degraded,proto3" json:"degraded,omitempty"`    // A subsystem keeps failing despite restarts
+	Subsystems    []*SubsystemHealth     `protobuf:"bytes,2,rep,name=subsystems,proto3" json:"subsystems,omitempty"` // Subsystems that failed since the agent started
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentHealth) Reset() {
+	*x = AgentHealth{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentHealth) ProtoMessage() {}
+
+func (x *AgentHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentHealth.ProtoReflect.Descriptor instead.
+func (*AgentHealth) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AgentHealth) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+func (x *AgentHealth) GetSubsystems() []*SubsystemHealth {
+	if x != nil {
+		return x.Subsystems
+	}
+	return nil
+}
+
+// SubsystemHealth is the state of one supervised agent subsystem
+type SubsystemHealth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                  // e.g. tun-reader-0 or network-monitor
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`                                // running, restarting or degraded
+	Restarts      uint32                 `protobuf:"varint,3,opt,name=restarts,proto3" json:"restarts,omitempty"`                         // Restarts since the agent started
+	LastError     string                 `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`       // Error or panic of the last failure
+	LastFailure   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_failure,json=lastFailure,proto3" json:"last_failure,omitempty"` // Time of the last failure
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubsystemHealth) Reset() {
+	*x = SubsystemHealth{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubsystemHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubsystemHealth) ProtoMessage() {}
+
+func (x *SubsystemHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubsystemHealth.ProtoReflect.Descriptor instead.
+func (*SubsystemHealth) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SubsystemHealth) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SubsystemHealth) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *SubsystemHealth) GetRestarts() uint32 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
+}
+
+func (x *SubsystemHealth) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *SubsystemHealth) GetLastFailure() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastFailure
+	}
+	return nil
+}
+
+// AgentStats contains performance and traffic metrics
+type AgentStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	BytesSent       uint64                 `protobuf:"varint,1,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`                   // Total bytes sent
+	BytesReceived   uint64                 `protobuf:"varint,2,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`       // Total bytes received
+	PacketsSent     uint64                 `protobuf:"varint,3,opt,name=packets_sent,json=packetsSent,proto3" json:"packets_sent,omitempty"`             // Total packets sent
+	PacketsReceived uint64                 `protobuf:"varint,4,opt,name=packets_received,json=packetsReceived,proto3" json:"packets_received,omitempty"` // Total packets received
+	Errors          uint32                 `protobuf:"varint,5,opt,name=errors,proto3" json:"errors,omitempty"`                                          // Error count
+	Drops           uint32                 `protobuf:"varint,6,opt,name=drops,proto3" json:"drops,omitempty"`                                            // Dropped packet count
+	CpuUsage        float32                `protobuf:"fixed32,7,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`                     // CPU usage percentage
+	MemoryUsage     uint64                 `protobuf:"varint,8,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"`             // Memory usage in bytes
+	Classes         []*TrafficClassStats   `protobuf:"bytes,9,rep,name=classes,proto3" json:"classes,omitempty"`                                         // Relay send queue counters per traffic class
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AgentStats) Reset() {
+	*x = AgentStats{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentStats) ProtoMessage() {}
+
+func (x *AgentStats) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentStats.ProtoReflect.Descriptor instead.
+func (*AgentStats) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AgentStats) GetBytesSent() uint64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *AgentStats) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *AgentStats) GetPacketsSent() uint64 {
+	if x != nil {
+		return x.PacketsSent
+	}
+	return 0
+}
+
+func (x *AgentStats) GetPacketsReceived() uint64 {
+	if x != nil {
+		return x.PacketsReceived
+	}
+	return 0
+}
+
+func (x *AgentStats) GetErrors() uint32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *AgentStats) GetDrops() uint32 {
+	if x != nil {
+		return x.Drops
+	}
+	return 0
+}
+
+func (x *AgentStats) GetCpuUsage() float32 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *AgentStats) GetMemoryUsage() uint64 {
+	if x != nil {
+		return x.MemoryUsage
+	}
+	return 0
+}
+
+func (x *AgentStats) GetClasses() []*TrafficClassStats {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+// TrafficClassStats counts the relayed packets of one traffic class
+type TrafficClassStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Class         string                 `protobuf:"bytes,1,opt,name=class,proto3" json:"class,omitempty"`      // interactive, default or bulk
+	Queued        uint64                 `protobuf:"varint,2,opt,name=queued,proto3" json:"queued,omitempty"`   // Packets queued for sending
+	Dropped       uint64                 `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"` // Packets dropped because the class queue was full
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrafficClassStats) Reset() {
+	*x = TrafficClassStats{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrafficClassStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrafficClassStats) ProtoMessage() {}
+
+func (x *TrafficClassStats) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrafficClassStats.ProtoReflect.Descriptor instead.
+func (*TrafficClassStats) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TrafficClassStats) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *TrafficClassStats) GetQueued() uint64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *TrafficClassStats) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+// HeartbeatResponse acknowledges heartbeat
+type HeartbeatResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Alive               bool                   `protobuf:"varint,1,opt,name=alive,proto3" json:"alive,omitempty"`                                                          // Server is alive
+	Timestamp           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                                   // Server timestamp
+	ShouldRefreshRoutes bool                   `protobuf:"varint,3,opt,name=should_refresh_routes,json=shouldRefreshRoutes,proto3" json:"should_refresh_routes,omitempty"` // Client should re-fetch routes
+	Message             string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`                                                       // Optional message from server
+	KeepaliveInterval   int32                  `protobuf:"varint,5,opt,name=keepalive_interval,json=keepaliveInterval,proto3" json:"keepalive_interval,omitempty"`         // Current heartbeat interval in seconds, 0 keeps the negotiated one
+	KeepaliveTimeout    int32                  `protobuf:"varint,6,opt,name=keepalive_timeout,json=keepaliveTimeout,proto3" json:"keepalive_timeout,omitempty"`            // Current dead-link timeout in seconds, 0 keeps the negotiated one
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HeartbeatResponse) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+func (x *HeartbeatResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *HeartbeatResponse) GetShouldRefreshRoutes() bool {
+	if x != nil {
+		return x.ShouldRefreshRoutes
+	}
+	return false
+}
+
+func (x *HeartbeatResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *HeartbeatResponse) GetKeepaliveInterval() int32 {
+	if x != nil {
+		return x.KeepaliveInterval
+	}
+	return 0
+}
+
+func (x *HeartbeatResponse) GetKeepaliveTimeout() int32 {
+	if x != nil {
+		return x.KeepaliveTimeout
+	}
+	return 0
+}
+
+// DataPacket represents an IP packet being relayed
+type DataPacket struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	SessionId          string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                              // Session identifier
+	SourceAgentId      string                 `protobuf:"bytes,2,opt,name=source_agent_id,json=sourceAgentId,proto3" json:"source_agent_id,omitempty"`                // Source agent UUID
+	DestinationAgentId string                 `protobuf:"bytes,3,opt,name=destination_agent_id,json=destinationAgentId,proto3" json:"destination_agent_id,omitempty"` // Destination agent UUID (empty for gateway)
+	Payload            []byte                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`                                                   // IP packet data
+	Sequence           uint32                 `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`                                                // Sequence number for ordering
+	Timestamp          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                               // Packet timestamp
+	Echo               *EchoProbe             `protobuf:"bytes,7,opt,name=echo,proto3" json:"echo,omitempty"`                                                         // Overlay echo probe, carried instead of a payload
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DataPacket) Reset() {
+	*x = DataPacket{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataPacket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataPacket) ProtoMessage() {}
+
+func (x *DataPacket) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataPacket.ProtoReflect.Descriptor instead.
+func (*DataPacket) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DataPacket) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *DataPacket) GetSourceAgentId() string {
+	if x != nil {
+		return x.SourceAgentId
+	}
+	return ""
+}
+
+func (x *DataPacket) GetDestinationAgentId() string {
+	if x != nil {
+		return x.DestinationAgentId
+	}
+	return ""
+}
+
+func (x *DataPacket) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DataPacket) GetSequence() uint32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *DataPacket) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *DataPacket) GetEcho() *EchoProbe {
+	if x != nil {
+		return x.Echo
+	}
+	return nil
+}
+
+// EchoProbe is an overlay-level ping that does not depend on OS ICMP or routes
+type EchoProbe struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        string                 `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`               // Overlay IP or agent name (requests only)
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`                      // Probe identifier chosen by the sender
+	Reply         bool                   `protobuf:"varint,3,opt,name=reply,proto3" json:"reply,omitempty"`                // Set on responses
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"` // Echoed back unchanged
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`                 // Set by the server when the target is unreachable
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EchoProbe) Reset() {
+	*x = EchoProbe{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EchoProbe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EchoProbe) ProtoMessage() {}
+
+func (x *EchoProbe) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EchoProbe.ProtoReflect.Descriptor instead.
+func (*EchoProbe) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EchoProbe) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *EchoProbe) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EchoProbe) GetReply() bool {
+	if x != nil {
+		return x.Reply
+	}
+	return false
+}
+
+func (x *EchoProbe) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *EchoProbe) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// TrustBundleRequest asks for the trust bundle of the server
+type TrustBundleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrustBundleRequest) Reset() {
+	*x = TrustBundleRequest{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustBundleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustBundleRequest) ProtoMessage() {}
+
+func (x *TrustBundleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustBundleRequest.ProtoReflect.Descriptor instead.
+func (*TrustBundleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{14}
+}
+
+// TrustBundleResponse contains the PEM encoded CA certificates
+type TrustBundleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bundle        []byte                 `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`           // PEM encoded CA certificates
+	Fingerprint   string                 `protobuf:"bytes,2,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"` // SHA256 fingerprint of the first certificate
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrustBundleResponse) Reset() {
+	*x = TrustBundleResponse{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustBundleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustBundleResponse) ProtoMessage() {}
+
+func (x *TrustBundleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustBundleResponse.ProtoReflect.Descriptor instead.
+func (*TrustBundleResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TrustBundleResponse) GetBundle() []byte {
+	if x != nil {
+		return x.Bundle
+	}
+	return nil
+}
+
+func (x *TrustBundleResponse) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+// RouteRequest asks for routing configuration
+type RouteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Session identifier
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`       // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteRequest) Reset() {
+	*x = RouteRequest{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteRequest) ProtoMessage() {}
+
+func (x *RouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteRequest.ProtoReflect.Descriptor instead.
+func (*RouteRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RouteRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RouteRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// RouteResponse provides routing rules
+type RouteResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Rules            []*RoutingRule         `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`                                                 // List of routing rules
+	DefaultGatewayId string                 `protobuf:"bytes,2,opt,name=default_gateway_id,json=defaultGatewayId,proto3" json:"default_gateway_id,omitempty"` // Default gateway agent ID
+	ManagedConfig    *ManagedConfig         `protobuf:"bytes,3,opt,name=managed_config,json=managedConfig,proto3" json:"managed_config,omitempty"`            // Settings of the agent's group, unset without a group
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RouteResponse) Reset() {
+	*x = RouteResponse{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteResponse) ProtoMessage() {}
+
+func (x *RouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteResponse.ProtoReflect.Descriptor instead.
+func (*RouteResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RouteResponse) GetRules() []*RoutingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *RouteResponse) GetDefaultGatewayId() string {
+	if x != nil {
+		return x.DefaultGatewayId
+	}
+	return ""
+}
+
+func (x *RouteResponse) GetManagedConfig() *ManagedConfig {
+	if x != nil {
+		return x.ManagedConfig
+	}
+	return nil
+}
+
+// RoutingRule defines a routing policy
+type RoutingRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        int32                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`                   // Rule identifier
+	Action        RouteAction            `protobuf:"varint,2,opt,name=action,proto3,enum=easyanylink.v1.RouteAction" json:"action,omitempty"` // Action to take
+	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`                        // Destination CIDR
+	GatewayId     string                 `protobuf:"bytes,4,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"`           // Gateway agent ID (for forward action)
+	Priority      int32                  `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`                             // Rule priority (lower = higher priority)
+	Enabled       bool                   `protobuf:"varint,6,opt,name=enabled,proto3" json:"enabled,omitempty"`                               // Whether rule is active
+	Window        *AccessWindow          `protobuf:"bytes,7,opt,name=window,proto3" json:"window,omitempty"`                                  // When the rule applies, unset for always
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoutingRule) Reset() {
+	*x = RoutingRule{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoutingRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingRule) ProtoMessage() {}
+
+func (x *RoutingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingRule.ProtoReflect.Descriptor instead.
+func (*RoutingRule) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RoutingRule) GetRuleId() int32 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+func (x *RoutingRule) GetAction() RouteAction {
+	if x != nil {
+		return x.Action
+	}
+	return RouteAction_ROUTE_ACTION_UNSPECIFIED
+}
+
+func (x *RoutingRule) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetGatewayId() string {
+	if x != nil {
+		return x.GatewayId
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *RoutingRule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RoutingRule) GetWindow() *AccessWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+// AccessWindow limits when a rule applies
+type AccessWindow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ValidFrom     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`    // Start of validity, unset for no start
+	ValidUntil    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"` // End of validity, unset for no end
+	Schedule      string                 `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"`                       // Weekly schedule, e.g. "mon-fri 09:00-18:00 Europe/Berlin"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessWindow) Reset() {
+	*x = AccessWindow{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessWindow) ProtoMessage() {}
+
+func (x *AccessWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessWindow.ProtoReflect.Descriptor instead.
+func (*AccessWindow) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AccessWindow) GetValidFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return nil
+}
+
+func (x *AccessWindow) GetValidUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return nil
+}
+
+func (x *AccessWindow) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+// StatusUpdate allows agents to report status changes
+type StatusUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`           // Session identifier
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                 // Agent UUID
+	Status        AgentStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=easyanylink.v1.AgentStatus" json:"status,omitempty"` // New status
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`                                // Optional status message
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusUpdate) Reset() {
+	*x = StatusUpdate{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusUpdate) ProtoMessage() {}
+
+func (x *StatusUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusUpdate.ProtoReflect.Descriptor instead.
+func (*StatusUpdate) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StatusUpdate) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *StatusUpdate) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *StatusUpdate) GetStatus() AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
+func (x *StatusUpdate) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// SessionEnded is attached to the error status of calls on a session the
+// server ended recently, telling the agent why
+type SessionEnded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        DisconnectReason       `protobuf:"varint,1,opt,name=reason,proto3,enum=easyanylink.v1.DisconnectReason" json:"reason,omitempty"` // Reason code
+	Detail        string                 `protobuf:"bytes,2,opt,name=detail,proto3" json:"detail,omitempty"`                                       // Human-readable detail
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionEnded) Reset() {
+	*x = SessionEnded{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionEnded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionEnded) ProtoMessage() {}
+
+func (x *SessionEnded) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionEnded.ProtoReflect.Descriptor instead.
+func (*SessionEnded) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SessionEnded) GetReason() DisconnectReason {
+	if x != nil {
+		return x.Reason
+	}
+	return DisconnectReason_DISCONNECT_REASON_UNSPECIFIED
+}
+
+func (x *SessionEnded) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+// ServerBusy is attached to the RESOURCE_EXHAUSTED status of a registration
+// turned away because the server reached its session limit
+type ServerBusy struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RetryAfter       int32                  `protobuf:"varint,1,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`                  // Seconds before retrying this server
+	AlternateServers []string               `protobuf:"bytes,2,rep,name=alternate_servers,json=alternateServers,proto3" json:"alternate_servers,omitempty"` // Servers to try meanwhile, host:port
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ServerBusy) Reset() {
+	*x = ServerBusy{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerBusy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerBusy) ProtoMessage() {}
+
+func (x *ServerBusy) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerBusy.ProtoReflect.Descriptor instead.
+func (*ServerBusy) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ServerBusy) GetRetryAfter() int32 {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return 0
+}
+
+func (x *ServerBusy) GetAlternateServers() []string {
+	if x != nil {
+		return x.AlternateServers
+	}
+	return nil
+}
+
+// StatusResponse acknowledges status update
+type StatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Acknowledged  bool                   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"` // Update was received
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`            // Optional response message
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v1_agent_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StatusResponse) GetAcknowledged() bool {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return false
+}
+
+func (x *StatusResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_common_proto_easyanylink_v1_agent_proto protoreflect.FileDescriptor
+
+const file_common_proto_easyanylink_v1_agent_proto_rawDesc = "" +
+	"\n" +
+	"'common/proto/easyanylink/v1/agent.proto\x12\x0eeasyanylink.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x98\x03\n" +
+	"\x0fRegisterRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x19\n" +
+	"\buser_key\x18\x02 \x01(\tR\auserKey\x12-\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x19.easyanylink.v1.AgentTypeR\x04type\x12)\n" +
+	"\x10protocol_version\x18\x04 \x01(\tR\x0fprotocolVersion\x127\n" +
+	"\x17certificate_fingerprint\x18\x05 \x01(\tR\x16certificateFingerprint\x129\n" +
+	"\bmetadata\x18\x06 \x01(\v2\x1d.easyanylink.v1.AgentMetadataR\bmetadata\x12\x1c\n" +
+	"\tbandwidth\x18\a \x01(\x05R\tbandwidth\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tR\trequestId\x12\x14\n" +
+	"\x05nonce\x18\t \x01(\fR\x05nonce\x12\x1c\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\x03R\ttimestamp\x12\x10\n" +
+	"\x03mac\x18\v \x01(\fR\x03mac\"\xff\x02\n" +
+	"\rAgentMetadata\x12\x0e\n" +
+	"\x02os\x18\x01 \x01(\tR\x02os\x12\x12\n" +
+	"\x04arch\x18\x02 \x01(\tR\x04arch\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x1a\n" +
+	"\bhostname\x18\x04 \x01(\tR\bhostname\x12A\n" +
+	"\x06labels\x18\x05 \x03(\v2).easyanylink.v1.AgentMetadata.LabelsEntryR\x06labels\x12@\n" +
+	"\n" +
+	"interfaces\x18\x06 \x03(\v2 .easyanylink.v1.NetworkInterfaceR\n" +
+	"interfaces\x12'\n" +
+	"\x0fdefault_gateway\x18\a \x01(\tR\x0edefaultGateway\x12+\n" +
+	"\x11default_interface\x18\b \x01(\tR\x10defaultInterface\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"x\n" +
+	"\x10NetworkInterface\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\taddresses\x18\x02 \x03(\tR\taddresses\x12\x10\n" +
+	"\x03mac\x18\x03 \x01(\tR\x03mac\x12\x10\n" +
+	"\x03mtu\x18\x04 \x01(\x05R\x03mtu\x12\x0e\n" +
+	"\x02up\x18\x05 \x01(\bR\x02up\"\xff\x02\n" +
+	"\x10RegisterResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1f\n" +
+	"\vassigned_ip\x18\x03 \x01(\tR\n" +
+	"assignedIp\x12%\n" +
+	"\x0eserver_version\x18\x04 \x01(\tR\rserverVersion\x12:\n" +
+	"\x19minimum_supported_version\x18\x05 \x01(\tR\x17minimumSupportedVersion\x12#\n" +
+	"\rerror_message\x18\x06 \x01(\tR\ferrorMessage\x12A\n" +
+	"\rserver_config\x18\a \x01(\v2\x1c.easyanylink.v1.ServerConfigR\fserverConfig\x12D\n" +
+	"\x0emanaged_config\x18\b \x01(\v2\x1d.easyanylink.v1.ManagedConfigR\rmanagedConfig\"\x9b\x01\n" +
+	"\fServerConfig\x12\x1d\n" +
+	"\n" +
+	"gateway_ip\x18\x01 \x01(\tR\tgatewayIp\x12\x10\n" +
+	"\x03mtu\x18\x02 \x01(\x05R\x03mtu\x12-\n" +
+	"\x12keepalive_interval\x18\x03 \x01(\x05R\x11keepaliveInterval\x12+\n" +
+	"\x11keepalive_timeout\x18\x04 \x01(\x05R\x10keepaliveTimeout\"\x8e\x01\n" +
+	"\rManagedConfig\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x1f\n" +
+	"\vdns_servers\x18\x02 \x03(\tR\n" +
+	"dnsServers\x12\x1d\n" +
+	"\n" +
+	"dns_search\x18\x03 \x03(\tR\tdnsSearch\x12'\n" +
+	"\x0fbandwidth_limit\x18\x04 \x01(\x05R\x0ebandwidthLimit\"\x8d\x02\n" +
+	"\x10HeartbeatRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x120\n" +
+	"\x05stats\x18\x03 \x01(\v2\x1a.easyanylink.v1.AgentStatsR\x05stats\x129\n" +
+	"\bmetadata\x18\x04 \x01(\v2\x1d.easyanylink.v1.AgentMetadataR\bmetadata\x123\n" +
+	"\x06health\x18\x05 \x01(\v2\x1b.easyanylink.v1.AgentHealthR\x06health\"j\n" +
+	"\vAgentHealth\x12\x1a\n" +
+	"\bdegraded\x18\x01 \x01(\bR\bdegraded\x12?\n" +
+	"\n" +
+	"subsystems\x18\x02 \x03(\v2\x1f.easyanylink.v1.SubsystemHealthR\n" +
+	"subsystems\"\xb5\x01\n" +
+	"\x0fSubsystemHealth\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x1a\n" +
+	"\brestarts\x18\x03 \x01(\rR\brestarts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x04 \x01(\tR\tlastError\x12=\n" +
+	"\flast_failure\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vlastFailure\"\xcb\x02\n" +
+	"\n" +
+	"AgentStats\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x01 \x01(\x04R\tbytesSent\x12%\n" +
+	"\x0ebytes_received\x18\x02 \x01(\x04R\rbytesReceived\x12!\n" +
+	"\fpackets_sent\x18\x03 \x01(\x04R\vpacketsSent\x12)\n" +
+	"\x10packets_received\x18\x04 \x01(\x04R\x0fpacketsReceived\x12\x16\n" +
+	"\x06errors\x18\x05 \x01(\rR\x06errors\x12\x14\n" +
+	"\x05drops\x18\x06 \x01(\rR\x05drops\x12\x1b\n" +
+	"\tcpu_usage\x18\a \x01(\x02R\bcpuUsage\x12!\n" +
+	"\fmemory_usage\x18\b \x01(\x04R\vmemoryUsage\x12;\n" +
+	"\aclasses\x18\t \x03(\v2!.easyanylink.v1.TrafficClassStatsR\aclasses\"[\n" +
+	"\x11TrafficClassStats\x12\x14\n" +
+	"\x05class\x18\x01 \x01(\tR\x05class\x12\x16\n" +
+	"\x06queued\x18\x02 \x01(\x04R\x06queued\x12\x18\n" +
+	"\adropped\x18\x03 \x01(\x04R\adropped\"\x8d\x02\n" +
+	"\x11HeartbeatResponse\x12\x14\n" +
+	"\x05alive\x18\x01 \x01(\bR\x05alive\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x122\n" +
+	"\x15should_refresh_routes\x18\x03 \x01(\bR\x13shouldRefreshRoutes\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12-\n" +
+	"\x12keepalive_interval\x18\x05 \x01(\x05R\x11keepaliveInterval\x12+\n" +
+	"\x11keepalive_timeout\x18\x06 \x01(\x05R\x10keepaliveTimeout\"\xa4\x02\n" +
+	"\n" +
+	"DataPacket\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12&\n" +
+	"\x0fsource_agent_id\x18\x02 \x01(\tR\rsourceAgentId\x120\n" +
+	"\x14destination_agent_id\x18\x03 \x01(\tR\x12destinationAgentId\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\fR\apayload\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\rR\bsequence\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12-\n" +
+	"\x04echo\x18\a \x01(\v2\x19.easyanylink.v1.EchoProbeR\x04echo\"\x94\x01\n" +
+	"\tEchoProbe\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\x12\x14\n" +
+	"\x05reply\x18\x03 \x01(\bR\x05reply\x123\n" +
+	"\asent_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\x14\n" +
+	"\x12TrustBundleRequest\"O\n" +
+	"\x13TrustBundleResponse\x12\x16\n" +
+	"\x06bundle\x18\x01 \x01(\fR\x06bundle\x12 \n" +
+	"\vfingerprint\x18\x02 \x01(\tR\vfingerprint\"H\n" +
+	"\fRouteRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"\xb6\x01\n" +
+	"\rRouteResponse\x121\n" +
+	"\x05rules\x18\x01 \x03(\v2\x1b.easyanylink.v1.RoutingRuleR\x05rules\x12,\n" +
+	"\x12default_gateway_id\x18\x02 \x01(\tR\x10defaultGatewayId\x12D\n" +
+	"\x0emanaged_config\x18\x03 \x01(\v2\x1d.easyanylink.v1.ManagedConfigR\rmanagedConfig\"\x88\x02\n" +
+	"\vRoutingRule\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x05R\x06ruleId\x123\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x1b.easyanylink.v1.RouteActionR\x06action\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\x12\x1d\n" +
+	"\n" +
+	"gateway_id\x18\x04 \x01(\tR\tgatewayId\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\x05R\bpriority\x12\x18\n" +
+	"\aenabled\x18\x06 \x01(\bR\aenabled\x124\n" +
+	"\x06window\x18\a \x01(\v2\x1c.easyanylink.v1.AccessWindowR\x06window\"\xa2\x01\n" +
+	"\fAccessWindow\x129\n" +
+	"\n" +
+	"valid_from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tvalidFrom\x12;\n" +
+	"\vvalid_until\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"validUntil\x12\x1a\n" +
+	"\bschedule\x18\x03 \x01(\tR\bschedule\"\x97\x01\n" +
+	"\fStatusUpdate\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x123\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1b.easyanylink.v1.AgentStatusR\x06status\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"`\n" +
+	"\fSessionEnded\x128\n" +
+	"\x06reason\x18\x01 \x01(\x0e2 .easyanylink.v1.DisconnectReasonR\x06reason\x12\x16\n" +
+	"\x06detail\x18\x02 \x01(\tR\x06detail\"Z\n" +
+	"\n" +
+	"ServerBusy\x12\x1f\n" +
+	"\vretry_after\x18\x01 \x01(\x05R\n" +
+	"retryAfter\x12+\n" +
+	"\x11alternate_servers\x18\x02 \x03(\tR\x10alternateServers\"N\n" +
+	"\x0eStatusResponse\x12\"\n" +
+	"\facknowledged\x18\x01 \x01(\bR\facknowledged\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage*@\n" +
+	"\tAgentType\x12\x1a\n" +
+	"\x16AGENT_TYPE_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06CLIENT\x10\x01\x12\v\n" +
+	"\aGATEWAY\x10\x02*N\n" +
+	"\vRouteAction\x12\x1c\n" +
+	"\x18ROUTE_ACTION_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aFORWARD\x10\x01\x12\n" +
+	"\n" +
+	"\x06DIRECT\x10\x02\x12\b\n" +
+	"\x04DENY\x10\x03*`\n" +
+	"\vAgentStatus\x12\x1c\n" +
+	"\x18AGENT_STATUS_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06ONLINE\x10\x01\x12\v\n" +
+	"\aOFFLINE\x10\x02\x12\t\n" +
+	"\x05ERROR\x10\x03\x12\x0f\n" +
+	"\vMAINTENANCE\x10\x04*\xea\x01\n" +
+	"\x10DisconnectReason\x12!\n" +
+	"\x1dDISCONNECT_REASON_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aDISCONNECT_SERVER_DECISION\x10\x01\x12\x1b\n" +
+	"\x17DISCONNECT_AUTH_REVOKED\x10\x02\x12\x1b\n" +
+	"\x17DISCONNECT_IDLE_TIMEOUT\x10\x03\x12\x1d\n" +
+	"\x19DISCONNECT_AGENT_SHUTDOWN\x10\x04\x12\x1e\n" +
+	"\x1aDISCONNECT_TRANSPORT_ERROR\x10\x05\x12\x1a\n" +
+	"\x16DISCONNECT_AGENT_ERROR\x10\x062\xef\x03\n" +
+	"\fAgentService\x12M\n" +
+	"\bRegister\x12\x1f.easyanylink.v1.RegisterRequest\x1a .easyanylink.v1.RegisterResponse\x12T\n" +
+	"\tHeartbeat\x12 .easyanylink.v1.HeartbeatRequest\x1a!.easyanylink.v1.HeartbeatResponse(\x010\x01\x12G\n" +
+	"\tRelayData\x12\x1a.easyanylink.v1.DataPacket\x1a\x1a.easyanylink.v1.DataPacket(\x010\x01\x12H\n" +
+	"\tGetRoutes\x12\x1c.easyanylink.v1.RouteRequest\x1a\x1d.easyanylink.v1.RouteResponse\x12L\n" +
+	"\fUpdateStatus\x12\x1c.easyanylink.v1.StatusUpdate\x1a\x1e.easyanylink.v1.StatusResponse\x12Y\n" +
+	"\x0eGetTrustBundle\x12\".easyanylink.v1.TrustBundleRequest\x1a#.easyanylink.v1.TrustBundleResponseBIZGgithub.com/taills/EasyAnyLink/common/proto/easyanylink/v1;easyanylinkv1b\x06proto3"
+
+var (
+	file_common_proto_easyanylink_v1_agent_proto_rawDescOnce sync.Once
+	file_common_proto_easyanylink_v1_agent_proto_rawDescData []byte
+)
+
+func file_common_proto_easyanylink_v1_agent_proto_rawDescGZIP() []byte {
+	file_common_proto_easyanylink_v1_agent_proto_rawDescOnce.Do(func() {
+		file_common_proto_easyanylink_v1_agent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_common_proto_easyanylink_v1_agent_proto_rawDesc), len(file_common_proto_easyanylink_v1_agent_proto_rawDesc)))
+	})
+	return file_common_proto_easyanylink_v1_agent_proto_rawDescData
+}
+
+var file_common_proto_easyanylink_v1_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_common_proto_easyanylink_v1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_common_proto_easyanylink_v1_agent_proto_goTypes = []any{
+	(AgentType)(0),                // 0: easyanylink.v1.AgentType
+	(RouteAction)(0),              // 1: easyanylink.v1.RouteAction
+	(AgentStatus)(0),              // 2: easyanylink.v1.AgentStatus
+	(DisconnectReason)(0),         // 3: easyanylink.v1.DisconnectReason
+	(*RegisterRequest)(nil),       // 4: easyanylink.v1.RegisterRequest
+	(*AgentMetadata)(nil),         // 5: easyanylink.v1.AgentMetadata
+	(*NetworkInterface)(nil),      // 6: easyanylink.v1.NetworkInterface
+	(*RegisterResponse)(nil),      // 7: easyanylink.v1.RegisterResponse
+	(*ServerConfig)(nil),          // 8: easyanylink.v1.ServerConfig
+	(*ManagedConfig)(nil),         // 9: easyanylink.v1.ManagedConfig
+	(*HeartbeatRequest)(nil),      // 10: easyanylink.v1.HeartbeatRequest
+	(*AgentHealth)(nil),           // 11: easyanylink.v1.AgentHealth
+	(*SubsystemHealth)(nil),       // 12: easyanylink.v1.SubsystemHealth
+	(*AgentStats)(nil),            // 13: easyanylink.v1.AgentStats
+	(*TrafficClassStats)(nil),     // 14: easyanylink.v1.TrafficClassStats
+	(*HeartbeatResponse)(nil),     // 15: easyanylink.v1.HeartbeatResponse
+	(*DataPacket)(nil),            // 16: easyanylink.v1.DataPacket
+	(*EchoProbe)(nil),             // 17: easyanylink.v1.EchoProbe
+	(*TrustBundleRequest)(nil),    // 18: easyanylink.v1.TrustBundleRequest
+	(*TrustBundleResponse)(nil),   // 19: easyanylink.v1.TrustBundleResponse
+	(*RouteRequest)(nil),          // 20: easyanylink.v1.RouteRequest
+	(*RouteResponse)(nil),         // 21: easyanylink.v1.RouteResponse
+	(*RoutingRule)(nil),           // 22: easyanylink.v1.RoutingRule
+	(*AccessWindow)(nil),          // 23: easyanylink.v1.AccessWindow
+	(*StatusUpdate)(nil),          // 24: easyanylink.v1.StatusUpdate
+	(*SessionEnded)(nil),          // 25: easyanylink.v1.SessionEnded
+	(*ServerBusy)(nil),            // 26: easyanylink.v1.ServerBusy
+	(*StatusResponse)(nil),        // 27: easyanylink.v1.StatusResponse
+	nil,                           // 28: easyanylink.v1.AgentMetadata.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 29: google.protobuf.Timestamp
+}
+var file_common_proto_easyanylink_v1_agent_proto_depIdxs = []int32{
+	0,  // 0: easyanylink.v1.RegisterRequest.type:type_name -> easyanylink.v1.AgentType
+	5,  // 1: easyanylink.v1.RegisterRequest.metadata:type_name -> easyanylink.v1.AgentMetadata
+	28, // 2: easyanylink.v1.AgentMetadata.labels:type_name -> easyanylink.v1.AgentMetadata.LabelsEntry
+	6,  // 3: easyanylink.v1.AgentMetadata.interfaces:type_name -> easyanylink.v1.NetworkInterface
+	8,  // 4: easyanylink.v1.RegisterResponse.server_config:type_name -> easyanylink.v1.ServerConfig
+	9,  // 5: easyanylink.v1.RegisterResponse.managed_config:type_name -> easyanylink.v1.ManagedConfig
+	29, // 6: easyanylink.v1.HeartbeatRequest.timestamp:type_name -> google.protobuf.Timestamp
+	13, // 7: easyanylink.v1.HeartbeatRequest.stats:type_name -> easyanylink.v1.AgentStats
+	5,  // 8: easyanylink.v1.HeartbeatRequest.metadata:type_name -> easyanylink.v1.AgentMetadata
+	11, // 9: easyanylink.v1.HeartbeatRequest.health:type_name -> easyanylink.v1.AgentHealth
+	12, // 10: easyanylink.v1.AgentHealth.subsystems:type_name -> easyanylink.v1.SubsystemHealth
+	29, // 11: easyanylink.v1.SubsystemHealth.last_failure:type_name -> google.protobuf.Timestamp
+	14, // 12: easyanylink.v1.AgentStats.classes:type_name -> easyanylink.v1.TrafficClassStats
+	29, // 13: easyanylink.v1.HeartbeatResponse.timestamp:type_name -> google.protobuf.Timestamp
+	29, // 14: easyanylink.v1.DataPacket.timestamp:type_name -> google.protobuf.Timestamp
+	17, // 15: easyanylink.v1.DataPacket.echo:type_name -> easyanylink.v1.EchoProbe
+	29, // 16: easyanylink.v1.EchoProbe.sent_at:type_name -> google.protobuf.Timestamp
+	22, // 17: easyanylink.v1.RouteResponse.rules:type_name -> easyanylink.v1.RoutingRule
+	9,  // 18: easyanylink.v1.RouteResponse.managed_config:type_name -> easyanylink.v1.ManagedConfig
+	1,  // 19: easyanylink.v1.RoutingRule.action:type_name -> easyanylink.v1.RouteAction
+	23, // 20: easyanylink.v1.RoutingRule.window:type_name -> easyanylink.v1.AccessWindow
+	29, // 21: easyanylink.v1.AccessWindow.valid_from:type_name -> google.protobuf.Timestamp
+	29, // 22: easyanylink.v1.AccessWindow.valid_until:type_name -> google.protobuf.Timestamp
+	2,  // 23: easyanylink.v1.StatusUpdate.status:type_name -> easyanylink.v1.AgentStatus
+	3,  // 24: easyanylink.v1.SessionEnded.reason:type_name -> easyanylink.v1.DisconnectReason
+	4,  // 25: easyanylink.v1.AgentService.Register:input_type -> easyanylink.v1.RegisterRequest
+	10, // 26: easyanylink.v1.AgentService.Heartbeat:input_type -> easyanylink.v1.HeartbeatRequest
+	16, // 27: easyanylink.v1.AgentService.RelayData:input_type -> easyanylink.v1.DataPacket
+	20, // 28: easyanylink.v1.AgentService.GetRoutes:input_type -> easyanylink.v1.RouteRequest
+	24, // 29: easyanylink.v1.AgentService.UpdateStatus:input_type -> easyanylink.v1.StatusUpdate
+	18, // 30: easyanylink.v1.AgentService.GetTrustBundle:input_type -> easyanylink.v1.TrustBundleRequest
+	7,  // 31: easyanylink.v1.AgentService.Register:output_type -> easyanylink.v1.RegisterResponse
+	15, // 32: easyanylink.v1.AgentService.Heartbeat:output_type -> easyanylink.v1.HeartbeatResponse
+	16, // 33: easyanylink.v1.AgentService.RelayData:output_type -> easyanylink.v1.DataPacket
+	21, // 34: easyanylink.v1.AgentService.GetRoutes:output_type -> easyanylink.v1.RouteResponse
+	27, // 35: easyanylink.v1.AgentService.UpdateStatus:output_type -> easyanylink.v1.StatusResponse
+	19, // 36: easyanylink.v1.AgentService.GetTrustBundle:output_type -> easyanylink.v1.TrustBundleResponse
+	31, // [31:37] is the sub-list for method output_type
+	25, // [25:31] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_common_proto_easyanylink_v1_agent_proto_init() }
+func file_common_proto_easyanylink_v1_agent_proto_init() {
+	if File_common_proto_easyanylink_v1_agent_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_proto_easyanylink_v1_agent_proto_rawDesc), len(file_common_proto_easyanylink_v1_agent_proto_rawDesc)),
+			NumEnums:      4,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_common_proto_easyanylink_v1_agent_proto_goTypes,
+		DependencyIndexes: file_common_proto_easyanylink_v1_agent_proto_depIdxs,
+		EnumInfos:         file_common_proto_easyanylink_v1_agent_proto_enumTypes,
+		MessageInfos:      file_common_proto_easyanylink_v1_agent_proto_msgTypes,
+	}.Build()
+	File_common_proto_easyanylink_v1_agent_proto = out.File
+	file_common_proto_easyanylink_v1_agent_proto_goTypes = nil
+	file_common_proto_easyanylink_v1_agent_proto_depIdxs = nil
+}
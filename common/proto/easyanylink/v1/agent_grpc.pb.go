@@ -2,9 +2,13 @@
 // versions:
 // - protoc-gen-go-grpc v1.5.1
 // - protoc             v6.32.1
-// source: common/proto/agent.proto
+// source: common/proto/easyanylink/v1/agent.proto
 
-package proto
+// Version 1 of the API, frozen. It is the API of the releases before API
+// versioning, which the server also serves under the unversioned "proto"
+// package name. Changes go to easyanylink.v2.
+
+package easyanylinkv1
 
 import (
 	context "context"
@@ -19,11 +23,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AgentService_Register_FullMethodName     = "/proto.AgentService/Register"
-	AgentService_Heartbeat_FullMethodName    = "/proto.AgentService/Heartbeat"
-	AgentService_RelayData_FullMethodName    = "/proto.AgentService/RelayData"
-	AgentService_GetRoutes_FullMethodName    = "/proto.AgentService/GetRoutes"
-	AgentService_UpdateStatus_FullMethodName = "/proto.AgentService/UpdateStatus"
+	AgentService_Register_FullMethodName       = "/easyanylink.v1.AgentService/Register"
+	AgentService_Heartbeat_FullMethodName      = "/easyanylink.v1.AgentService/Heartbeat"
+	AgentService_RelayData_FullMethodName      = "/easyanylink.v1.AgentService/RelayData"
+	AgentService_GetRoutes_FullMethodName      = "/easyanylink.v1.AgentService/GetRoutes"
+	AgentService_UpdateStatus_FullMethodName   = "/easyanylink.v1.AgentService/UpdateStatus"
+	AgentService_GetTrustBundle_FullMethodName = "/easyanylink.v1.AgentService/GetTrustBundle"
 )
 
 // AgentServiceClient is the client API for AgentService service.
@@ -42,6 +47,9 @@ type AgentServiceClient interface {
 	GetRoutes(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*RouteResponse, error)
 	// Update agent status
 	UpdateStatus(ctx context.Context, in *StatusUpdate, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Download the CA certificates that issued the server certificate,
+	// used by new agents before they trust the server
+	GetTrustBundle(ctx context.Context, in *TrustBundleRequest, opts ...grpc.CallOption) (*TrustBundleResponse, error)
 }
 
 type agentServiceClient struct {
@@ -108,6 +116,16 @@ func (c *agentServiceClient) UpdateStatus(ctx context.Context, in *StatusUpdate,
 	return out, nil
 }
 
+func (c *agentServiceClient) GetTrustBundle(ctx context.Context, in *TrustBundleRequest, opts ...grpc.CallOption) (*TrustBundleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrustBundleResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetTrustBundle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServiceServer is the server API for AgentService service.
 // All implementations must embed UnimplementedAgentServiceServer
 // for forward compatibility.
@@ -124,6 +142,9 @@ type AgentServiceServer interface {
 	GetRoutes(context.Context, *RouteRequest) (*RouteResponse, error)
 	// Update agent status
 	UpdateStatus(context.Context, *StatusUpdate) (*StatusResponse, error)
+	// Download the CA certificates that issued the server certificate,
+	// used by new agents before they trust the server
+	GetTrustBundle(context.Context, *TrustBundleRequest) (*TrustBundleResponse, error)
 	mustEmbedUnimplementedAgentServiceServer()
 }
 
@@ -149,6 +170,9 @@ func (UnimplementedAgentServiceServer) GetRoutes(context.Context, *RouteRequest)
 func (UnimplementedAgentServiceServer) UpdateStatus(context.Context, *StatusUpdate) (*StatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateStatus not implemented")
 }
+func (UnimplementedAgentServiceServer) GetTrustBundle(context.Context, *TrustBundleRequest) (*TrustBundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrustBundle not implemented")
+}
 func (UnimplementedAgentServiceServer) mustEmbedUnimplementedAgentServiceServer() {}
 func (UnimplementedAgentServiceServer) testEmbeddedByValue()                      {}
 
@@ -238,11 +262,29 @@ func _AgentService_UpdateStatus_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentService_GetTrustBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrustBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetTrustBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetTrustBundle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetTrustBundle(ctx, req.(*TrustBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AgentService_ServiceDesc is the grpc.ServiceDesc for AgentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var AgentService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.AgentService",
+	ServiceName: "easyanylink.v1.AgentService",
 	HandlerType: (*AgentServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
@@ -257,6 +299,10 @@ var AgentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateStatus",
 			Handler:    _AgentService_UpdateStatus_Handler,
 		},
+		{
+			MethodName: "GetTrustBundle",
+			Handler:    _AgentService_GetTrustBundle_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -272,5 +318,5 @@ var AgentService_ServiceDesc = grpc.ServiceDesc{
 			ClientStreams: true,
 		},
 	},
-	Metadata: "common/proto/agent.proto",
+	Metadata: "common/proto/easyanylink/v1/agent.proto",
 }
@@ -0,0 +1,6718 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: common/proto/easyanylink/v2/admin.proto
+
+// Version 2 of the API. It must stay wire compatible with v1, which the
+// server serves with the same handlers: add fields, never renumber them,
+// and reserve the numbers and names of removed ones.
+
+package easyanylinkv2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AddRoutingRuleRequest creates a new routing rule
+type AddRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent the rule applies to, empty for a group rule
+	Rule          *RoutingRule           `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`                       // Rule definition (rule_id is ignored)
+	GroupId       int32                  `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group the rule applies to, 0 for an agent rule
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRoutingRuleRequest) Reset() {
+	*x = AddRoutingRuleRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRoutingRuleRequest) ProtoMessage() {}
+
+func (x *AddRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddRoutingRuleRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AddRoutingRuleRequest) GetRule() *RoutingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *AddRoutingRuleRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// UpdateRoutingRuleRequest replaces an existing routing rule
+type UpdateRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *RoutingRule           `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"` // Rule definition, identified by rule_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoutingRuleRequest) Reset() {
+	*x = UpdateRoutingRuleRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoutingRuleRequest) ProtoMessage() {}
+
+func (x *UpdateRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdateRoutingRuleRequest) GetRule() *RoutingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// RoutingRuleResponse returns the stored routing rule
+type RoutingRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent the rule applies to, empty for a group rule
+	Rule          *RoutingRule           `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`                       // Stored rule
+	GroupId       int32                  `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group the rule applies to, 0 for an agent rule
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoutingRuleResponse) Reset() {
+	*x = RoutingRuleResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoutingRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingRuleResponse) ProtoMessage() {}
+
+func (x *RoutingRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingRuleResponse.ProtoReflect.Descriptor instead.
+func (*RoutingRuleResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RoutingRuleResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RoutingRuleResponse) GetRule() *RoutingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *RoutingRuleResponse) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// DeleteRoutingRuleRequest deletes a routing rule
+type DeleteRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        int32                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"` // Rule identifier
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoutingRuleRequest) Reset() {
+	*x = DeleteRoutingRuleRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoutingRuleRequest) ProtoMessage() {}
+
+func (x *DeleteRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DeleteRoutingRuleRequest) GetRuleId() int32 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+// DeleteRoutingRuleResponse acknowledges rule deletion
+type DeleteRoutingRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`                // Rule was removed
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent the rule applied to, empty for a group rule
+	GroupId       int32                  `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group the rule applied to, 0 for an agent rule
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoutingRuleResponse) Reset() {
+	*x = DeleteRoutingRuleResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoutingRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoutingRuleResponse) ProtoMessage() {}
+
+func (x *DeleteRoutingRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoutingRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRoutingRuleResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteRoutingRuleResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *DeleteRoutingRuleResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *DeleteRoutingRuleResponse) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// ListAgentsRequest filters and paginates the agent registry
+type ListAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`                                                      // Max agents per page (default 50, max 500)
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                                                    // Token from a previous response
+	Type          AgentType              `protobuf:"varint,3,opt,name=type,proto3,enum=easyanylink.v2.AgentType" json:"type,omitempty"`                                                // Filter by type, unspecified for all
+	Status        AgentStatus            `protobuf:"varint,4,opt,name=status,proto3,enum=easyanylink.v2.AgentStatus" json:"status,omitempty"`                                          // Filter by status, unspecified for all
+	UserId        string                 `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                                             // Filter by owning user
+	Labels        map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Filter by metadata labels (all must match)
+	Archived      bool                   `protobuf:"varint,7,opt,name=archived,proto3" json:"archived,omitempty"`                                                                      // List archived agents instead of active ones
+	Pending       bool                   `protobuf:"varint,8,opt,name=pending,proto3" json:"pending,omitempty"`                                                                        // List agents awaiting approval instead of approved ones
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsRequest) Reset() {
+	*x = ListAgentsRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsRequest) ProtoMessage() {}
+
+func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListAgentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAgentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListAgentsRequest) GetType() AgentType {
+	if x != nil {
+		return x.Type
+	}
+	return AgentType_AGENT_TYPE_UNSPECIFIED
+}
+
+func (x *ListAgentsRequest) GetStatus() AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
+func (x *ListAgentsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListAgentsRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ListAgentsRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *ListAgentsRequest) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+// ListAgentsResponse returns a page of agents
+type ListAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*AgentDetail         `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`                                      // Agents in this page
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty when there are no more pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsResponse) Reset() {
+	*x = ListAgentsResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsResponse) ProtoMessage() {}
+
+func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListAgentsResponse) GetAgents() []*AgentDetail {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *ListAgentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// GetAgentRequest identifies a single agent
+type GetAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentRequest) Reset() {
+	*x = GetAgentRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentRequest) ProtoMessage() {}
+
+func (x *GetAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// AgentDetail describes an agent in the server registry
+type AgentDetail struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AgentId          string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                              // Agent UUID
+	UserId           string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                 // Owning user
+	Name             string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                                                   // Human-readable name
+	Type             AgentType              `protobuf:"varint,4,opt,name=type,proto3,enum=easyanylink.v2.AgentType" json:"type,omitempty"`                    // Client or Gateway
+	Status           AgentStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=easyanylink.v2.AgentStatus" json:"status,omitempty"`              // Last reported status
+	IpAddress        string                 `protobuf:"bytes,6,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`                        // Assigned overlay IP
+	PublicIp         string                 `protobuf:"bytes,7,opt,name=public_ip,json=publicIp,proto3" json:"public_ip,omitempty"`                           // Public IP address
+	Metadata         *AgentMetadata         `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`                                           // Platform and label information
+	Stats            *AgentStats            `protobuf:"bytes,9,opt,name=stats,proto3" json:"stats,omitempty"`                                                 // Latest stats of the live session
+	LastSeen         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`                          // Last heartbeat or activity
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                       // Registration time
+	SessionId        string                 `protobuf:"bytes,12,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                       // Live session, empty if disconnected
+	Connected        bool                   `protobuf:"varint,13,opt,name=connected,proto3" json:"connected,omitempty"`                                       // Agent has a live session
+	ArchivedAt       *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`                    // Archive time, unset if active
+	Pending          bool                   `protobuf:"varint,15,opt,name=pending,proto3" json:"pending,omitempty"`                                           // Agent awaits approval and cannot connect
+	Health           *AgentHealth           `protobuf:"bytes,16,opt,name=health,proto3" json:"health,omitempty"`                                              // Failing subsystems reported by the live session
+	Group            string                 `protobuf:"bytes,17,opt,name=group,proto3" json:"group,omitempty"`                                                // Name of the agent's group, empty for none
+	ApiVersion       int32                  `protobuf:"varint,18,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`                   // API version the connected agent uses, 0 while disconnected
+	Capabilities     []string               `protobuf:"bytes,19,rep,name=capabilities,proto3" json:"capabilities,omitempty"`                                  // Optional features of the connected agent
+	SiteSubnets      []string               `protobuf:"bytes,20,rep,name=site_subnets,json=siteSubnets,proto3" json:"site_subnets,omitempty"`                 // LANs the connected gateway routes for the site mesh
+	ConfigGeneration uint64                 `protobuf:"varint,21,opt,name=config_generation,json=configGeneration,proto3" json:"config_generation,omitempty"` // Config generation the connected agent applied, 0 if it does not report one
+	ConfigDrifted    bool                   `protobuf:"varint,22,opt,name=config_drifted,json=configDrifted,proto3" json:"config_drifted,omitempty"`          // The connected agent keeps failing to apply pushed config
+	ClockSkewMs      int64                  `protobuf:"varint,23,opt,name=clock_skew_ms,json=clockSkewMs,proto3" json:"clock_skew_ms,omitempty"`              // Clock of the connected agent minus the server clock in milliseconds
+	NotReady         bool                   `protobuf:"varint,24,opt,name=not_ready,json=notReady,proto3" json:"not_ready,omitempty"`                         // The connected gateway is still setting up and takes no traffic
+	FlowControl      *FlowControl           `protobuf:"bytes,25,opt,name=flow_control,json=flowControl,proto3" json:"flow_control,omitempty"`                 // Relay sends to the connected agent, unset while disconnected
+	Resources        *SessionResources      `protobuf:"bytes,26,opt,name=resources,proto3" json:"resources,omitempty"`                                        // Held by the live session, unset while disconnected
+	Identity         *AgentIdentity         `protobuf:"bytes,27,opt,name=identity,proto3" json:"identity,omitempty"`                                          // Hardware identity key the agent is bound to, unset for none
+	PostureFailures  []string               `protobuf:"bytes,28,rep,name=posture_failures,json=postureFailures,proto3" json:"posture_failures,omitempty"`     // Posture policies the connected agent fails and why, e.g. "finance: firewall disabled"
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AgentDetail) Reset() {
+	*x = AgentDetail{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentDetail) ProtoMessage() {}
+
+func (x *AgentDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentDetail.ProtoReflect.Descriptor instead.
+func (*AgentDetail) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AgentDetail) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetType() AgentType {
+	if x != nil {
+		return x.Type
+	}
+	return AgentType_AGENT_TYPE_UNSPECIFIED
+}
+
+func (x *AgentDetail) GetStatus() AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
+func (x *AgentDetail) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetPublicIp() string {
+	if x != nil {
+		return x.PublicIp
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetMetadata() *AgentMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetStats() *AgentStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *AgentDetail) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+func (x *AgentDetail) GetHealth() *AgentHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *AgentDetail) GetApiVersion() int32 {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return 0
+}
+
+func (x *AgentDetail) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetSiteSubnets() []string {
+	if x != nil {
+		return x.SiteSubnets
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetConfigGeneration() uint64 {
+	if x != nil {
+		return x.ConfigGeneration
+	}
+	return 0
+}
+
+func (x *AgentDetail) GetConfigDrifted() bool {
+	if x != nil {
+		return x.ConfigDrifted
+	}
+	return false
+}
+
+func (x *AgentDetail) GetClockSkewMs() int64 {
+	if x != nil {
+		return x.ClockSkewMs
+	}
+	return 0
+}
+
+func (x *AgentDetail) GetNotReady() bool {
+	if x != nil {
+		return x.NotReady
+	}
+	return false
+}
+
+func (x *AgentDetail) GetFlowControl() *FlowControl {
+	if x != nil {
+		return x.FlowControl
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetResources() *SessionResources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetIdentity() *AgentIdentity {
+	if x != nil {
+		return x.Identity
+	}
+	return nil
+}
+
+func (x *AgentDetail) GetPostureFailures() []string {
+	if x != nil {
+		return x.PostureFailures
+	}
+	return nil
+}
+
+// AgentIdentity is the hardware-backed key an agent signs its
+// registrations with. The server only stores the public key.
+type AgentIdentity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`       // "tpm2" or "secure-enclave"
+	Fingerprint   string                 `protobuf:"bytes,2,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"` // SHA256 of the PKIX public key, hex encoded
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentIdentity) Reset() {
+	*x = AgentIdentity{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentIdentity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentIdentity) ProtoMessage() {}
+
+func (x *AgentIdentity) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentIdentity.ProtoReflect.Descriptor instead.
+func (*AgentIdentity) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AgentIdentity) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *AgentIdentity) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+// SessionResources are the goroutines and packet buffers a session holds
+// on the server
+type SessionResources struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Goroutines       int32                  `protobuf:"varint,1,opt,name=goroutines,proto3" json:"goroutines,omitempty"`                                       // Stream handlers serving the session
+	BufferedBytes    int64                  `protobuf:"varint,2,opt,name=buffered_bytes,json=bufferedBytes,proto3" json:"buffered_bytes,omitempty"`            // Packets of the session queued for relay or pending sends to it
+	MemoryLimitBytes int64                  `protobuf:"varint,3,opt,name=memory_limit_bytes,json=memoryLimitBytes,proto3" json:"memory_limit_bytes,omitempty"` // network.max_session_memory_kb in bytes, 0 for unlimited
+	MemoryDrops      uint64                 `protobuf:"varint,4,opt,name=memory_drops,json=memoryDrops,proto3" json:"memory_drops,omitempty"`                  // Packets dropped because the session held its limit
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SessionResources) Reset() {
+	*x = SessionResources{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionResources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionResources) ProtoMessage() {}
+
+func (x *SessionResources) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionResources.ProtoReflect.Descriptor instead.
+func (*SessionResources) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SessionResources) GetGoroutines() int32 {
+	if x != nil {
+		return x.Goroutines
+	}
+	return 0
+}
+
+func (x *SessionResources) GetBufferedBytes() int64 {
+	if x != nil {
+		return x.BufferedBytes
+	}
+	return 0
+}
+
+func (x *SessionResources) GetMemoryLimitBytes() int64 {
+	if x != nil {
+		return x.MemoryLimitBytes
+	}
+	return 0
+}
+
+func (x *SessionResources) GetMemoryDrops() uint64 {
+	if x != nil {
+		return x.MemoryDrops
+	}
+	return 0
+}
+
+// FlowControl describes the relay sends to an agent, which block while it
+// does not read its relay streams
+type FlowControl struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueueDepth    int32                  `protobuf:"varint,1,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`       // Packets waiting to be sent or being sent
+	StalledMs     int64                  `protobuf:"varint,2,opt,name=stalled_ms,json=stalledMs,proto3" json:"stalled_ms,omitempty"`          // How long the oldest pending send has blocked
+	MaxStallMs    int64                  `protobuf:"varint,3,opt,name=max_stall_ms,json=maxStallMs,proto3" json:"max_stall_ms,omitempty"`     // Longest a send blocked during the session
+	SlowSends     uint64                 `protobuf:"varint,4,opt,name=slow_sends,json=slowSends,proto3" json:"slow_sends,omitempty"`          // Sends that blocked longer than network.slow_consumer_stall
+	SlowConsumer  bool                   `protobuf:"varint,5,opt,name=slow_consumer,json=slowConsumer,proto3" json:"slow_consumer,omitempty"` // A send is blocked longer than network.slow_consumer_stall
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlowControl) Reset() {
+	*x = FlowControl{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlowControl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlowControl) ProtoMessage() {}
+
+func (x *FlowControl) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlowControl.ProtoReflect.Descriptor instead.
+func (*FlowControl) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FlowControl) GetQueueDepth() int32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *FlowControl) GetStalledMs() int64 {
+	if x != nil {
+		return x.StalledMs
+	}
+	return 0
+}
+
+func (x *FlowControl) GetMaxStallMs() int64 {
+	if x != nil {
+		return x.MaxStallMs
+	}
+	return 0
+}
+
+func (x *FlowControl) GetSlowSends() uint64 {
+	if x != nil {
+		return x.SlowSends
+	}
+	return 0
+}
+
+func (x *FlowControl) GetSlowConsumer() bool {
+	if x != nil {
+		return x.SlowConsumer
+	}
+	return false
+}
+
+// ListRoutingRulesRequest selects the rules of an agent
+type ListRoutingRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent UUID, empty to list the rules of a group
+	GroupId       int32                  `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group whose rules to list
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutingRulesRequest) Reset() {
+	*x = ListRoutingRulesRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutingRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutingRulesRequest) ProtoMessage() {}
+
+func (x *ListRoutingRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutingRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListRoutingRulesRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListRoutingRulesRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListRoutingRulesRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// ListRoutingRulesResponse returns the rules of an agent
+type ListRoutingRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*RoutingRule         `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"` // Rules ordered by priority
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutingRulesResponse) Reset() {
+	*x = ListRoutingRulesResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutingRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutingRulesResponse) ProtoMessage() {}
+
+func (x *ListRoutingRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutingRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListRoutingRulesResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListRoutingRulesResponse) GetRules() []*RoutingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// CreateUserRequest creates a user account
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`                              // Unique username
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`                                    // Optional email address
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`                              // Optional password, random if empty
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`                                      // "user" (default), "owner", "admin", "support" or "auditor"
+	MaxAgents     int32                  `protobuf:"varint,5,opt,name=max_agents,json=maxAgents,proto3" json:"max_agents,omitempty"`          // Maximum agents, 0 for the server default
+	MaxBandwidth  int32                  `protobuf:"varint,6,opt,name=max_bandwidth,json=maxBandwidth,proto3" json:"max_bandwidth,omitempty"` // KB/s across all agents, 0 for unlimited
+	TransferCap   uint64                 `protobuf:"varint,7,opt,name=transfer_cap,json=transferCap,proto3" json:"transfer_cap,omitempty"`    // Bytes relayed per calendar month, 0 for unlimited
+	SitePrefixes  []string               `protobuf:"bytes,8,rep,name=site_prefixes,json=sitePrefixes,proto3" json:"site_prefixes,omitempty"`  // IPv4 CIDRs the user's gateways may advertise as sites, empty for any
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetMaxAgents() int32 {
+	if x != nil {
+		return x.MaxAgents
+	}
+	return 0
+}
+
+func (x *CreateUserRequest) GetMaxBandwidth() int32 {
+	if x != nil {
+		return x.MaxBandwidth
+	}
+	return 0
+}
+
+func (x *CreateUserRequest) GetTransferCap() uint64 {
+	if x != nil {
+		return x.TransferCap
+	}
+	return 0
+}
+
+func (x *CreateUserRequest) GetSitePrefixes() []string {
+	if x != nil {
+		return x.SitePrefixes
+	}
+	return nil
+}
+
+// RotateAPIKeyRequest replaces the API key of a user
+type RotateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyRequest) Reset() {
+	*x = RotateAPIKeyRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyRequest) ProtoMessage() {}
+
+func (x *RotateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RotateAPIKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// UserResponse returns a user account and its API key
+type UserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User UUID
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`           // Username
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`                 // Email address
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`                   // User role
+	ApiKey        string                 `protobuf:"bytes,5,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"` // Current API key
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserResponse) Reset() {
+	*x = UserResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserResponse) ProtoMessage() {}
+
+func (x *UserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserResponse.ProtoReflect.Descriptor instead.
+func (*UserResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UserResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserResponse) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UserResponse) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+// ListUsersRequest lists user accounts
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{17}
+}
+
+// ListUsersResponse returns user accounts
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UserDetail          `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"` // Ordered by username
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListUsersResponse) GetUsers() []*UserDetail {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+// GetUserRequest selects a user account
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                 // User UUID
+	UsageMonths   int32                  `protobuf:"varint,2,opt,name=usage_months,json=usageMonths,proto3" json:"usage_months,omitempty"` // Months of usage history, default 12
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserRequest) GetUsageMonths() int32 {
+	if x != nil {
+		return x.UsageMonths
+	}
+	return 0
+}
+
+// UpdateUserRequest replaces the mutable fields of a user
+type UpdateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                    // User UUID
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`                                    // Email address, empty to clear
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`                                      // "user", "owner", "admin", "support" or "auditor"
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                  // "active", "suspended" or "disabled"
+	MaxAgents     int32                  `protobuf:"varint,5,opt,name=max_agents,json=maxAgents,proto3" json:"max_agents,omitempty"`          // Maximum agents, 0 for the server default
+	MaxBandwidth  int32                  `protobuf:"varint,6,opt,name=max_bandwidth,json=maxBandwidth,proto3" json:"max_bandwidth,omitempty"` // KB/s across all agents, 0 for unlimited
+	TransferCap   uint64                 `protobuf:"varint,7,opt,name=transfer_cap,json=transferCap,proto3" json:"transfer_cap,omitempty"`    // Bytes relayed per calendar month, 0 for unlimited
+	SitePrefixes  []string               `protobuf:"bytes,8,rep,name=site_prefixes,json=sitePrefixes,proto3" json:"site_prefixes,omitempty"`  // IPv4 CIDRs the user's gateways may advertise as sites, empty for any
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdateUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetMaxAgents() int32 {
+	if x != nil {
+		return x.MaxAgents
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetMaxBandwidth() int32 {
+	if x != nil {
+		return x.MaxBandwidth
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetTransferCap() uint64 {
+	if x != nil {
+		return x.TransferCap
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetSitePrefixes() []string {
+	if x != nil {
+		return x.SitePrefixes
+	}
+	return nil
+}
+
+// DeleteUserRequest deletes a user account
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeleteUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// DeleteUserResponse acknowledges user deletion
+type DeleteUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`                                  // User was removed
+	AgentsDeleted int32                  `protobuf:"varint,2,opt,name=agents_deleted,json=agentsDeleted,proto3" json:"agents_deleted,omitempty"` // Number of agents removed with the user
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserResponse) Reset() {
+	*x = DeleteUserResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserResponse) ProtoMessage() {}
+
+func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteUserResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *DeleteUserResponse) GetAgentsDeleted() int32 {
+	if x != nil {
+		return x.AgentsDeleted
+	}
+	return 0
+}
+
+// UserDetail describes a user account, its quotas and usage. The API key
+// is never included.
+type UserDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                    // User UUID
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`                              // Username
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`                                    // Email address
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`                                      // User role
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                                  // Account status
+	MaxAgents     int32                  `protobuf:"varint,6,opt,name=max_agents,json=maxAgents,proto3" json:"max_agents,omitempty"`          // Maximum agents, 0 for the server default
+	MaxBandwidth  int32                  `protobuf:"varint,7,opt,name=max_bandwidth,json=maxBandwidth,proto3" json:"max_bandwidth,omitempty"` // KB/s across all agents, 0 for unlimited
+	TransferCap   uint64                 `protobuf:"varint,8,opt,name=transfer_cap,json=transferCap,proto3" json:"transfer_cap,omitempty"`    // Bytes relayed per calendar month, 0 for unlimited
+	AgentCount    int32                  `protobuf:"varint,9,opt,name=agent_count,json=agentCount,proto3" json:"agent_count,omitempty"`       // Registered agents
+	Usage         []*UserUsage           `protobuf:"bytes,10,rep,name=usage,proto3" json:"usage,omitempty"`                                   // Monthly usage, newest first
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`          // Account creation time
+	SitePrefixes  []string               `protobuf:"bytes,12,rep,name=site_prefixes,json=sitePrefixes,proto3" json:"site_prefixes,omitempty"` // IPv4 CIDRs the user's gateways may advertise as sites, empty for any
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDetail) Reset() {
+	*x = UserDetail{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDetail) ProtoMessage() {}
+
+func (x *UserDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDetail.ProtoReflect.Descriptor instead.
+func (*UserDetail) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *UserDetail) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserDetail) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserDetail) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserDetail) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UserDetail) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UserDetail) GetMaxAgents() int32 {
+	if x != nil {
+		return x.MaxAgents
+	}
+	return 0
+}
+
+func (x *UserDetail) GetMaxBandwidth() int32 {
+	if x != nil {
+		return x.MaxBandwidth
+	}
+	return 0
+}
+
+func (x *UserDetail) GetTransferCap() uint64 {
+	if x != nil {
+		return x.TransferCap
+	}
+	return 0
+}
+
+func (x *UserDetail) GetAgentCount() int32 {
+	if x != nil {
+		return x.AgentCount
+	}
+	return 0
+}
+
+func (x *UserDetail) GetUsage() []*UserUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *UserDetail) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *UserDetail) GetSitePrefixes() []string {
+	if x != nil {
+		return x.SitePrefixes
+	}
+	return nil
+}
+
+// UserUsage is the relayed traffic of a user in one calendar month
+type UserUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         string                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`                                       // YYYY-MM in UTC
+	BytesSent     uint64                 `protobuf:"varint,2,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`             // Bytes relayed from the user's agents
+	BytesReceived uint64                 `protobuf:"varint,3,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"` // Bytes relayed to the user's agents
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserUsage) Reset() {
+	*x = UserUsage{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserUsage) ProtoMessage() {}
+
+func (x *UserUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserUsage.ProtoReflect.Descriptor instead.
+func (*UserUsage) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UserUsage) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *UserUsage) GetBytesSent() uint64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *UserUsage) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+// ExportUsageRequest selects the month and format of a usage export
+type ExportUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         string                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`   // YYYY-MM in UTC, empty for the current month
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"` // "csv" (default) or "json"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsageRequest) Reset() {
+	*x = ExportUsageRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsageRequest) ProtoMessage() {}
+
+func (x *ExportUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsageRequest.ProtoReflect.Descriptor instead.
+func (*ExportUsageRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ExportUsageRequest) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *ExportUsageRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// ExportUsageResponse returns a rendered usage export
+type ExportUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         string                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`                                // Exported month
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // "text/csv" or "application/json"
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`                                  // One record per user
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsageResponse) Reset() {
+	*x = ExportUsageResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsageResponse) ProtoMessage() {}
+
+func (x *ExportUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsageResponse.ProtoReflect.Descriptor instead.
+func (*ExportUsageResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ExportUsageResponse) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *ExportUsageResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ExportUsageResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// ListTrafficRollupsRequest selects rolled up session statistics
+type ListTrafficRollupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Period        string                 `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"`                  // "hourly" (default) or "daily"
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Only this agent, empty for all
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`    // Only agents of this user, empty for all
+	Since         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`                    // Only periods starting at or after this time, unset for all kept
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                   // Max rollups (default 50, max 500)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrafficRollupsRequest) Reset() {
+	*x = ListTrafficRollupsRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrafficRollupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrafficRollupsRequest) ProtoMessage() {}
+
+func (x *ListTrafficRollupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrafficRollupsRequest.ProtoReflect.Descriptor instead.
+func (*ListTrafficRollupsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListTrafficRollupsRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *ListTrafficRollupsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListTrafficRollupsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListTrafficRollupsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListTrafficRollupsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListTrafficRollupsResponse returns rollups, newest first
+type ListTrafficRollupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rollups       []*TrafficRollup       `protobuf:"bytes,1,rep,name=rollups,proto3" json:"rollups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrafficRollupsResponse) Reset() {
+	*x = ListTrafficRollupsResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrafficRollupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrafficRollupsResponse) ProtoMessage() {}
+
+func (x *ListTrafficRollupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrafficRollupsResponse.ProtoReflect.Descriptor instead.
+func (*ListTrafficRollupsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListTrafficRollupsResponse) GetRollups() []*TrafficRollup {
+	if x != nil {
+		return x.Rollups
+	}
+	return nil
+}
+
+// TrafficRollup sums the sessions of an agent that ended in one hour or day
+type TrafficRollup struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PeriodStart      *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=period_start,json=periodStart,proto3" json:"period_start,omitempty"`                 // Start of the hour or day
+	AgentId          string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                             // Agent UUID, the agent may since have been purged
+	UserId           string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                // Owner of the agent
+	Sessions         uint32                 `protobuf:"varint,4,opt,name=sessions,proto3" json:"sessions,omitempty"`                                         // Ended sessions
+	ConnectedSeconds uint64                 `protobuf:"varint,5,opt,name=connected_seconds,json=connectedSeconds,proto3" json:"connected_seconds,omitempty"` // Summed duration of the sessions
+	BytesSent        uint64                 `protobuf:"varint,6,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`                      // Bytes relayed to the agent
+	BytesReceived    uint64                 `protobuf:"varint,7,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`          // Bytes relayed from the agent
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TrafficRollup) Reset() {
+	*x = TrafficRollup{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrafficRollup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrafficRollup) ProtoMessage() {}
+
+func (x *TrafficRollup) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrafficRollup.ProtoReflect.Descriptor instead.
+func (*TrafficRollup) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TrafficRollup) GetPeriodStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeriodStart
+	}
+	return nil
+}
+
+func (x *TrafficRollup) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *TrafficRollup) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TrafficRollup) GetSessions() uint32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *TrafficRollup) GetConnectedSeconds() uint64 {
+	if x != nil {
+		return x.ConnectedSeconds
+	}
+	return 0
+}
+
+func (x *TrafficRollup) GetBytesSent() uint64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *TrafficRollup) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+// ExportInventoryRequest selects the format of an inventory export
+type ExportInventoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"` // "hosts" (default) or "ansible"
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"` // Domain appended to host names as an alias, optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportInventoryRequest) Reset() {
+	*x = ExportInventoryRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportInventoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportInventoryRequest) ProtoMessage() {}
+
+func (x *ExportInventoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportInventoryRequest.ProtoReflect.Descriptor instead.
+func (*ExportInventoryRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ExportInventoryRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportInventoryRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+// ExportInventoryResponse returns a rendered inventory of the approved,
+// active agents with an overlay address
+type ExportInventoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContentType   string                 `protobuf:"bytes,1,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // "text/plain" or "application/json"
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	AgentCount    int32                  `protobuf:"varint,3,opt,name=agent_count,json=agentCount,proto3" json:"agent_count,omitempty"` // Agents in the inventory
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportInventoryResponse) Reset() {
+	*x = ExportInventoryResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportInventoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportInventoryResponse) ProtoMessage() {}
+
+func (x *ExportInventoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportInventoryResponse.ProtoReflect.Descriptor instead.
+func (*ExportInventoryResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ExportInventoryResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ExportInventoryResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportInventoryResponse) GetAgentCount() int32 {
+	if x != nil {
+		return x.AgentCount
+	}
+	return 0
+}
+
+// SetRelayTracingRequest changes the relay trace sampling
+type SetRelayTracingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SampleRate    uint32                 `protobuf:"varint,1,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"` // Trace 1 in N relayed packets, 0 disables tracing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRelayTracingRequest) Reset() {
+	*x = SetRelayTracingRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRelayTracingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRelayTracingRequest) ProtoMessage() {}
+
+func (x *SetRelayTracingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRelayTracingRequest.ProtoReflect.Descriptor instead.
+func (*SetRelayTracingRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SetRelayTracingRequest) GetSampleRate() uint32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+// RelayTracingResponse returns the relay tracing settings
+type RelayTracingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SampleRate    uint32                 `protobuf:"varint,1,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"` // Current sampling rate, 0 if disabled
+	BufferSize    uint32                 `protobuf:"varint,2,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"` // Number of traces kept
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RelayTracingResponse) Reset() {
+	*x = RelayTracingResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelayTracingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayTracingResponse) ProtoMessage() {}
+
+func (x *RelayTracingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayTracingResponse.ProtoReflect.Descriptor instead.
+func (*RelayTracingResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RelayTracingResponse) GetSampleRate() uint32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *RelayTracingResponse) GetBufferSize() uint32 {
+	if x != nil {
+		return x.BufferSize
+	}
+	return 0
+}
+
+// ListRelayTracesRequest selects recent relay traces
+type ListRelayTracesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Only traces from or to this agent, empty for all
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                   // Maximum number of traces, 0 for all buffered
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRelayTracesRequest) Reset() {
+	*x = ListRelayTracesRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRelayTracesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRelayTracesRequest) ProtoMessage() {}
+
+func (x *ListRelayTracesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRelayTracesRequest.ProtoReflect.Descriptor instead.
+func (*ListRelayTracesRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListRelayTracesRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListRelayTracesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListRelayTracesResponse returns recent relay traces
+type ListRelayTracesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Traces        []*RelayTrace          `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`                            // Newest first
+	SampleRate    uint32                 `protobuf:"varint,2,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"` // Current sampling rate, 0 if disabled
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRelayTracesResponse) Reset() {
+	*x = ListRelayTracesResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRelayTracesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRelayTracesResponse) ProtoMessage() {}
+
+func (x *ListRelayTracesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRelayTracesResponse.ProtoReflect.Descriptor instead.
+func (*ListRelayTracesResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListRelayTracesResponse) GetTraces() []*RelayTrace {
+	if x != nil {
+		return x.Traces
+	}
+	return nil
+}
+
+func (x *ListRelayTracesResponse) GetSampleRate() uint32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+// RelayTrace records the routing decision for one sampled packet. Only
+// headers are recorded, never payload data.
+type RelayTrace struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Time               *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`                                                         // When the packet was relayed
+	SessionId          string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                              // Source session
+	SourceAgentId      string                 `protobuf:"bytes,3,opt,name=source_agent_id,json=sourceAgentId,proto3" json:"source_agent_id,omitempty"`                // Source agent UUID
+	DestinationAgentId string                 `protobuf:"bytes,4,opt,name=destination_agent_id,json=destinationAgentId,proto3" json:"destination_agent_id,omitempty"` // Chosen destination agent, empty if none
+	SourceIp           string                 `protobuf:"bytes,5,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`                                 // IPv4 source address, if parseable
+	DestinationIp      string                 `protobuf:"bytes,6,opt,name=destination_ip,json=destinationIp,proto3" json:"destination_ip,omitempty"`                  // IPv4 destination address, if parseable
+	Protocol           uint32                 `protobuf:"varint,7,opt,name=protocol,proto3" json:"protocol,omitempty"`                                                // IP protocol number
+	Length             uint32                 `protobuf:"varint,8,opt,name=length,proto3" json:"length,omitempty"`                                                    // Payload length in bytes
+	Ttl                uint32                 `protobuf:"varint,9,opt,name=ttl,proto3" json:"ttl,omitempty"`                                                          // IPv4 TTL as received
+	Decision           string                 `protobuf:"bytes,10,opt,name=decision,proto3" json:"decision,omitempty"`                                                // forwarded, no_route, ttl_exceeded, send_failed, echo_answered, malformed, acl_denied, rate_limited, multicast_dropped, multicast_relayed, multicast_unicast, loop_detected, memory_dropped
+	Detail             string                 `protobuf:"bytes,11,opt,name=detail,proto3" json:"detail,omitempty"`                                                    // Error detail, if any
+	DestinationPort    uint32                 `protobuf:"varint,12,opt,name=destination_port,json=destinationPort,proto3" json:"destination_port,omitempty"`          // TCP or UDP destination port, 0 if none
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *RelayTrace) Reset() {
+	*x = RelayTrace{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelayTrace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayTrace) ProtoMessage() {}
+
+func (x *RelayTrace) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayTrace.ProtoReflect.Descriptor instead.
+func (*RelayTrace) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RelayTrace) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *RelayTrace) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetSourceAgentId() string {
+	if x != nil {
+		return x.SourceAgentId
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetDestinationAgentId() string {
+	if x != nil {
+		return x.DestinationAgentId
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetDestinationIp() string {
+	if x != nil {
+		return x.DestinationIp
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetProtocol() uint32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *RelayTrace) GetLength() uint32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *RelayTrace) GetTtl() uint32 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *RelayTrace) GetDecision() string {
+	if x != nil {
+		return x.Decision
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *RelayTrace) GetDestinationPort() uint32 {
+	if x != nil {
+		return x.DestinationPort
+	}
+	return 0
+}
+
+// GetHandshakeStatsRequest requests QUIC handshake counters
+type GetHandshakeStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHandshakeStatsRequest) Reset() {
+	*x = GetHandshakeStatsRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHandshakeStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHandshakeStatsRequest) ProtoMessage() {}
+
+func (x *GetHandshakeStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHandshakeStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetHandshakeStatsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{37}
+}
+
+// HandshakeStatsResponse reports QUIC handshake address validation
+type HandshakeStatsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Validated      uint64                 `protobuf:"varint,1,opt,name=validated,proto3" json:"validated,omitempty"`                                 // Handshakes from validated source addresses
+	Unvalidated    uint64                 `protobuf:"varint,2,opt,name=unvalidated,proto3" json:"unvalidated,omitempty"`                             // Handshakes accepted without validation
+	Retries        uint64                 `protobuf:"varint,3,opt,name=retries,proto3" json:"retries,omitempty"`                                     // Retry packets sent
+	InvalidTokens  uint64                 `protobuf:"varint,4,opt,name=invalid_tokens,json=invalidTokens,proto3" json:"invalid_tokens,omitempty"`    // Handshakes rejected for an invalid Retry token
+	RetryThreshold int32                  `protobuf:"varint,5,opt,name=retry_threshold,json=retryThreshold,proto3" json:"retry_threshold,omitempty"` // Handshakes per second before Retry is required, 0 if disabled
+	RetryActive    bool                   `protobuf:"varint,6,opt,name=retry_active,json=retryActive,proto3" json:"retry_active,omitempty"`          // Whether Retry is currently enforced
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HandshakeStatsResponse) Reset() {
+	*x = HandshakeStatsResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandshakeStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandshakeStatsResponse) ProtoMessage() {}
+
+func (x *HandshakeStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandshakeStatsResponse.ProtoReflect.Descriptor instead.
+func (*HandshakeStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *HandshakeStatsResponse) GetValidated() uint64 {
+	if x != nil {
+		return x.Validated
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetUnvalidated() uint64 {
+	if x != nil {
+		return x.Unvalidated
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetRetries() uint64 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetInvalidTokens() uint64 {
+	if x != nil {
+		return x.InvalidTokens
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetRetryThreshold() int32 {
+	if x != nil {
+		return x.RetryThreshold
+	}
+	return 0
+}
+
+func (x *HandshakeStatsResponse) GetRetryActive() bool {
+	if x != nil {
+		return x.RetryActive
+	}
+	return false
+}
+
+// GetCryptoPolicyRequest requests the server's crypto policy
+type GetCryptoPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCryptoPolicyRequest) Reset() {
+	*x = GetCryptoPolicyRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCryptoPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCryptoPolicyRequest) ProtoMessage() {}
+
+func (x *GetCryptoPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCryptoPolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetCryptoPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{39}
+}
+
+// CryptoPolicyResponse describes the algorithms the server's TLS allows
+type CryptoPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        string                 `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`                                 // "default" or "fips"
+	FipsModule    bool                   `protobuf:"varint,2,opt,name=fips_module,json=fipsModule,proto3" json:"fips_module,omitempty"`      // Whether the Go FIPS 140 module is active
+	FipsBuild     bool                   `protobuf:"varint,3,opt,name=fips_build,json=fipsBuild,proto3" json:"fips_build,omitempty"`         // Whether the server was built with the fips tag
+	CipherSuites  []string               `protobuf:"bytes,4,rep,name=cipher_suites,json=cipherSuites,proto3" json:"cipher_suites,omitempty"` // Allowed TLS 1.3 cipher suites
+	Curves        []string               `protobuf:"bytes,5,rep,name=curves,proto3" json:"curves,omitempty"`                                 // Allowed key exchange groups, empty for the Go defaults
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CryptoPolicyResponse) Reset() {
+	*x = CryptoPolicyResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CryptoPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CryptoPolicyResponse) ProtoMessage() {}
+
+func (x *CryptoPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CryptoPolicyResponse.ProtoReflect.Descriptor instead.
+func (*CryptoPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CryptoPolicyResponse) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *CryptoPolicyResponse) GetFipsModule() bool {
+	if x != nil {
+		return x.FipsModule
+	}
+	return false
+}
+
+func (x *CryptoPolicyResponse) GetFipsBuild() bool {
+	if x != nil {
+		return x.FipsBuild
+	}
+	return false
+}
+
+func (x *CryptoPolicyResponse) GetCipherSuites() []string {
+	if x != nil {
+		return x.CipherSuites
+	}
+	return nil
+}
+
+func (x *CryptoPolicyResponse) GetCurves() []string {
+	if x != nil {
+		return x.Curves
+	}
+	return nil
+}
+
+// GetRelayQueueStatsRequest takes no parameters
+type GetRelayQueueStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRelayQueueStatsRequest) Reset() {
+	*x = GetRelayQueueStatsRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRelayQueueStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRelayQueueStatsRequest) ProtoMessage() {}
+
+func (x *GetRelayQueueStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRelayQueueStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetRelayQueueStatsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{41}
+}
+
+// RelayQueueStatsResponse holds the counters of the server relay queues
+type RelayQueueStatsResponse struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Classes                 []*TrafficClassStats   `protobuf:"bytes,1,rep,name=classes,proto3" json:"classes,omitempty"`                                                                   // One entry per traffic class
+	Multicast               *MulticastStats        `protobuf:"bytes,2,opt,name=multicast,proto3" json:"multicast,omitempty"`                                                               // Handling of broadcast and multicast packets
+	SlowConsumers           []*SlowConsumer        `protobuf:"bytes,3,rep,name=slow_consumers,json=slowConsumers,proto3" json:"slow_consumers,omitempty"`                                  // Sessions whose agents do not keep up with their relay streams
+	SlowConsumersTerminated uint64                 `protobuf:"varint,4,opt,name=slow_consumers_terminated,json=slowConsumersTerminated,proto3" json:"slow_consumers_terminated,omitempty"` // Sessions ended for stalling past network.slow_consumer_timeout
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *RelayQueueStatsResponse) Reset() {
+	*x = RelayQueueStatsResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelayQueueStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayQueueStatsResponse) ProtoMessage() {}
+
+func (x *RelayQueueStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayQueueStatsResponse.ProtoReflect.Descriptor instead.
+func (*RelayQueueStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *RelayQueueStatsResponse) GetClasses() []*TrafficClassStats {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+func (x *RelayQueueStatsResponse) GetMulticast() *MulticastStats {
+	if x != nil {
+		return x.Multicast
+	}
+	return nil
+}
+
+func (x *RelayQueueStatsResponse) GetSlowConsumers() []*SlowConsumer {
+	if x != nil {
+		return x.SlowConsumers
+	}
+	return nil
+}
+
+func (x *RelayQueueStatsResponse) GetSlowConsumersTerminated() uint64 {
+	if x != nil {
+		return x.SlowConsumersTerminated
+	}
+	return 0
+}
+
+// SlowConsumer is a session whose relay sends block
+type SlowConsumer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`             // Agent UUID
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`       // Live session
+	FlowControl   *FlowControl           `protobuf:"bytes,3,opt,name=flow_control,json=flowControl,proto3" json:"flow_control,omitempty"` // Relay sends to the agent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlowConsumer) Reset() {
+	*x = SlowConsumer{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlowConsumer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlowConsumer) ProtoMessage() {}
+
+func (x *SlowConsumer) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlowConsumer.ProtoReflect.Descriptor instead.
+func (*SlowConsumer) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *SlowConsumer) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SlowConsumer) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SlowConsumer) GetFlowControl() *FlowControl {
+	if x != nil {
+		return x.FlowControl
+	}
+	return nil
+}
+
+// MulticastStats counts the decisions of the overlay multicast policy
+type MulticastStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        string                 `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`                               // drop, relay or unicast
+	Dropped       uint64                 `protobuf:"varint,2,opt,name=dropped,proto3" json:"dropped,omitempty"`                            // Packets dropped by the policy
+	Relayed       uint64                 `protobuf:"varint,3,opt,name=relayed,proto3" json:"relayed,omitempty"`                            // Packets relayed to the user's other agents
+	Unicast       uint64                 `protobuf:"varint,4,opt,name=unicast,proto3" json:"unicast,omitempty"`                            // Discovery packets converted to unicast copies
+	RateLimited   uint64                 `protobuf:"varint,5,opt,name=rate_limited,json=rateLimited,proto3" json:"rate_limited,omitempty"` // Packets dropped over the per-session rate
+	Copies        uint64                 `protobuf:"varint,6,opt,name=copies,proto3" json:"copies,omitempty"`                              // Copies delivered to agents
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MulticastStats) Reset() {
+	*x = MulticastStats{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MulticastStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MulticastStats) ProtoMessage() {}
+
+func (x *MulticastStats) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MulticastStats.ProtoReflect.Descriptor instead.
+func (*MulticastStats) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *MulticastStats) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *MulticastStats) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *MulticastStats) GetRelayed() uint64 {
+	if x != nil {
+		return x.Relayed
+	}
+	return 0
+}
+
+func (x *MulticastStats) GetUnicast() uint64 {
+	if x != nil {
+		return x.Unicast
+	}
+	return 0
+}
+
+func (x *MulticastStats) GetRateLimited() uint64 {
+	if x != nil {
+		return x.RateLimited
+	}
+	return 0
+}
+
+func (x *MulticastStats) GetCopies() uint64 {
+	if x != nil {
+		return x.Copies
+	}
+	return 0
+}
+
+// ArchiveAgentRequest identifies the agent to archive
+type ArchiveAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveAgentRequest) Reset() {
+	*x = ArchiveAgentRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveAgentRequest) ProtoMessage() {}
+
+func (x *ArchiveAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveAgentRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ArchiveAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// RestoreAgentRequest identifies the archived agent to restore
+type RestoreAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreAgentRequest) Reset() {
+	*x = RestoreAgentRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreAgentRequest) ProtoMessage() {}
+
+func (x *RestoreAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreAgentRequest.ProtoReflect.Descriptor instead.
+func (*RestoreAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RestoreAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// ListSessionHistoryRequest selects ended sessions of an agent
+type ListSessionHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                   // Max sessions (default 50, max 500)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionHistoryRequest) Reset() {
+	*x = ListSessionHistoryRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionHistoryRequest) ProtoMessage() {}
+
+func (x *ListSessionHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListSessionHistoryRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListSessionHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListSessionHistoryResponse returns ended sessions, newest first
+type ListSessionHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*SessionRecord       `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionHistoryResponse) Reset() {
+	*x = ListSessionHistoryResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionHistoryResponse) ProtoMessage() {}
+
+func (x *ListSessionHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListSessionHistoryResponse) GetSessions() []*SessionRecord {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// SessionRecord describes an ended session
+type SessionRecord struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SessionId      string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Session UUID
+	AgentId        string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`       // Agent UUID
+	ConnectedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	DisconnectedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=disconnected_at,json=disconnectedAt,proto3" json:"disconnected_at,omitempty"`
+	BytesSent      uint64                 `protobuf:"varint,5,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`                                         // Bytes relayed to the agent
+	BytesReceived  uint64                 `protobuf:"varint,6,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`                             // Bytes relayed from the agent
+	Reason         string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`                                                                 // Why the session ended, e.g. agent shutdown: agent stopped
+	ReasonCode     DisconnectReason       `protobuf:"varint,8,opt,name=reason_code,json=reasonCode,proto3,enum=easyanylink.v2.DisconnectReason" json:"reason_code,omitempty"` // Reason classification, unspecified for sessions ended before it was recorded
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionRecord) Reset() {
+	*x = SessionRecord{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRecord) ProtoMessage() {}
+
+func (x *SessionRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRecord.ProtoReflect.Descriptor instead.
+func (*SessionRecord) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *SessionRecord) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionRecord) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SessionRecord) GetConnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return nil
+}
+
+func (x *SessionRecord) GetDisconnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisconnectedAt
+	}
+	return nil
+}
+
+func (x *SessionRecord) GetBytesSent() uint64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *SessionRecord) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *SessionRecord) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SessionRecord) GetReasonCode() DisconnectReason {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return DisconnectReason_DISCONNECT_REASON_UNSPECIFIED
+}
+
+// ApproveAgentRequest identifies the pending agent to approve
+type ApproveAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveAgentRequest) Reset() {
+	*x = ApproveAgentRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveAgentRequest) ProtoMessage() {}
+
+func (x *ApproveAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveAgentRequest.ProtoReflect.Descriptor instead.
+func (*ApproveAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ApproveAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// RejectAgentRequest identifies the pending agent to reject
+type RejectAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectAgentRequest) Reset() {
+	*x = RejectAgentRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectAgentRequest) ProtoMessage() {}
+
+func (x *RejectAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectAgentRequest.ProtoReflect.Descriptor instead.
+func (*RejectAgentRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *RejectAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// ResetAgentIdentityRequest identifies the agent whose identity key to
+// forget
+type ResetAgentIdentityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetAgentIdentityRequest) Reset() {
+	*x = ResetAgentIdentityRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetAgentIdentityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetAgentIdentityRequest) ProtoMessage() {}
+
+func (x *ResetAgentIdentityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetAgentIdentityRequest.ProtoReflect.Descriptor instead.
+func (*ResetAgentIdentityRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ResetAgentIdentityRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// RejectAgentResponse confirms a rejection
+type RejectAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rejected      bool                   `protobuf:"varint,1,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectAgentResponse) Reset() {
+	*x = RejectAgentResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectAgentResponse) ProtoMessage() {}
+
+func (x *RejectAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectAgentResponse.ProtoReflect.Descriptor instead.
+func (*RejectAgentResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *RejectAgentResponse) GetRejected() bool {
+	if x != nil {
+		return x.Rejected
+	}
+	return false
+}
+
+// ACLRule allows or denies traffic relayed from the agents of a user.
+// Enabled rules are evaluated by priority and the first match decides;
+// traffic matching no rule is allowed.
+type ACLRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        int32                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`                 // Rule identifier
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                  // User whose agents send the traffic
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`                                // Source CIDR, empty for any
+	Destination   string                 `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`                      // Destination CIDR, empty for any
+	Protocol      string                 `protobuf:"bytes,5,opt,name=protocol,proto3" json:"protocol,omitempty"`                            // "any" (default), "tcp", "udp" or "icmp"
+	PortFrom      uint32                 `protobuf:"varint,6,opt,name=port_from,json=portFrom,proto3" json:"port_from,omitempty"`           // First destination port (tcp/udp), 0 for any
+	PortTo        uint32                 `protobuf:"varint,7,opt,name=port_to,json=portTo,proto3" json:"port_to,omitempty"`                 // Last destination port, 0 for port_from
+	Action        string                 `protobuf:"bytes,8,opt,name=action,proto3" json:"action,omitempty"`                                // "allow" or "deny"
+	Priority      int32                  `protobuf:"varint,9,opt,name=priority,proto3" json:"priority,omitempty"`                           // Rule priority (lower = higher priority)
+	Enabled       bool                   `protobuf:"varint,10,opt,name=enabled,proto3" json:"enabled,omitempty"`                            // Whether rule is active
+	Window        *AccessWindow          `protobuf:"bytes,11,opt,name=window,proto3" json:"window,omitempty"`                               // When the rule applies, unset for always
+	SourceUid     *uint32                `protobuf:"varint,12,opt,name=source_uid,json=sourceUid,proto3,oneof" json:"source_uid,omitempty"` // Local user on the agent host that sent the traffic, unset for any
+	Posture       string                 `protobuf:"bytes,13,opt,name=posture,proto3" json:"posture,omitempty"`                             // Posture policy the sending agent must meet for an allow rule to apply, empty for none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ACLRule) Reset() {
+	*x = ACLRule{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ACLRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ACLRule) ProtoMessage() {}
+
+func (x *ACLRule) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ACLRule.ProtoReflect.Descriptor instead.
+func (*ACLRule) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ACLRule) GetRuleId() int32 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+func (x *ACLRule) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ACLRule) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ACLRule) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *ACLRule) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *ACLRule) GetPortFrom() uint32 {
+	if x != nil {
+		return x.PortFrom
+	}
+	return 0
+}
+
+func (x *ACLRule) GetPortTo() uint32 {
+	if x != nil {
+		return x.PortTo
+	}
+	return 0
+}
+
+func (x *ACLRule) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ACLRule) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *ACLRule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ACLRule) GetWindow() *AccessWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *ACLRule) GetSourceUid() uint32 {
+	if x != nil && x.SourceUid != nil {
+		return *x.SourceUid
+	}
+	return 0
+}
+
+func (x *ACLRule) GetPosture() string {
+	if x != nil {
+		return x.Posture
+	}
+	return ""
+}
+
+// ListACLRulesRequest selects the ACL rules of a user
+type ListACLRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListACLRulesRequest) Reset() {
+	*x = ListACLRulesRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListACLRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListACLRulesRequest) ProtoMessage() {}
+
+func (x *ListACLRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListACLRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListACLRulesRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListACLRulesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// ListACLRulesResponse returns the ACL rules of a user
+type ListACLRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*ACLRule             `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"` // Rules ordered by priority
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListACLRulesResponse) Reset() {
+	*x = ListACLRulesResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListACLRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListACLRulesResponse) ProtoMessage() {}
+
+func (x *ListACLRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListACLRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListACLRulesResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ListACLRulesResponse) GetRules() []*ACLRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// AddACLRuleRequest creates an ACL rule
+type AddACLRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *ACLRule               `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"` // Rule definition (rule_id is ignored)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddACLRuleRequest) Reset() {
+	*x = AddACLRuleRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddACLRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddACLRuleRequest) ProtoMessage() {}
+
+func (x *AddACLRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddACLRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddACLRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *AddACLRuleRequest) GetRule() *ACLRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// UpdateACLRuleRequest replaces an existing ACL rule
+type UpdateACLRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *ACLRule               `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"` // Rule definition, identified by rule_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateACLRuleRequest) Reset() {
+	*x = UpdateACLRuleRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateACLRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateACLRuleRequest) ProtoMessage() {}
+
+func (x *UpdateACLRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateACLRuleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateACLRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *UpdateACLRuleRequest) GetRule() *ACLRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// DeleteACLRuleRequest identifies the ACL rule to delete
+type DeleteACLRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        int32                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"` // Rule identifier
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteACLRuleRequest) Reset() {
+	*x = DeleteACLRuleRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteACLRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteACLRuleRequest) ProtoMessage() {}
+
+func (x *DeleteACLRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteACLRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteACLRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *DeleteACLRuleRequest) GetRuleId() int32 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+// DeleteACLRuleResponse confirms a deletion
+type DeleteACLRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User the deleted rule belonged to
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteACLRuleResponse) Reset() {
+	*x = DeleteACLRuleResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteACLRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteACLRuleResponse) ProtoMessage() {}
+
+func (x *DeleteACLRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteACLRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteACLRuleResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *DeleteACLRuleResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *DeleteACLRuleResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// GetKeepaliveRequest takes no parameters
+type GetKeepaliveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeepaliveRequest) Reset() {
+	*x = GetKeepaliveRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeepaliveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeepaliveRequest) ProtoMessage() {}
+
+func (x *GetKeepaliveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeepaliveRequest.ProtoReflect.Descriptor instead.
+func (*GetKeepaliveRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{61}
+}
+
+// SetKeepaliveRequest changes the heartbeat settings
+type SetKeepaliveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interval      int32                  `protobuf:"varint,1,opt,name=interval,proto3" json:"interval,omitempty"` // Heartbeat interval in seconds, 0 keeps the current one
+	Timeout       int32                  `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`   // Seconds without a heartbeat response before agents reconnect, 0 keeps the current one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetKeepaliveRequest) Reset() {
+	*x = SetKeepaliveRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetKeepaliveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetKeepaliveRequest) ProtoMessage() {}
+
+func (x *SetKeepaliveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetKeepaliveRequest.ProtoReflect.Descriptor instead.
+func (*SetKeepaliveRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *SetKeepaliveRequest) GetInterval() int32 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *SetKeepaliveRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+// KeepaliveResponse returns the heartbeat settings
+type KeepaliveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interval      int32                  `protobuf:"varint,1,opt,name=interval,proto3" json:"interval,omitempty"` // Heartbeat interval in seconds
+	Timeout       int32                  `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`   // Dead-link timeout in seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeepaliveResponse) Reset() {
+	*x = KeepaliveResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeepaliveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeepaliveResponse) ProtoMessage() {}
+
+func (x *KeepaliveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeepaliveResponse.ProtoReflect.Descriptor instead.
+func (*KeepaliveResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *KeepaliveResponse) GetInterval() int32 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *KeepaliveResponse) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+// AgentGroup is a config template shared by the agents of a group. The
+// group's routing rules apply to its agents next to their own.
+type AgentGroup struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	GroupId        int32                  `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group identifier, ignored on creation
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                       // Unique name
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	DnsServers     []string               `protobuf:"bytes,4,rep,name=dns_servers,json=dnsServers,proto3" json:"dns_servers,omitempty"`              // Resolvers of client agents, empty keeps the agent setting
+	DnsSearch      []string               `protobuf:"bytes,5,rep,name=dns_search,json=dnsSearch,proto3" json:"dns_search,omitempty"`                 // Search domains used with dns_servers
+	BandwidthLimit int32                  `protobuf:"varint,6,opt,name=bandwidth_limit,json=bandwidthLimit,proto3" json:"bandwidth_limit,omitempty"` // KB/s per agent, 0 for unlimited
+	AgentCount     int32                  `protobuf:"varint,7,opt,name=agent_count,json=agentCount,proto3" json:"agent_count,omitempty"`             // Agents in the group, set in responses
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AgentGroup) Reset() {
+	*x = AgentGroup{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentGroup) ProtoMessage() {}
+
+func (x *AgentGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentGroup.ProtoReflect.Descriptor instead.
+func (*AgentGroup) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *AgentGroup) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *AgentGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AgentGroup) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AgentGroup) GetDnsServers() []string {
+	if x != nil {
+		return x.DnsServers
+	}
+	return nil
+}
+
+func (x *AgentGroup) GetDnsSearch() []string {
+	if x != nil {
+		return x.DnsSearch
+	}
+	return nil
+}
+
+func (x *AgentGroup) GetBandwidthLimit() int32 {
+	if x != nil {
+		return x.BandwidthLimit
+	}
+	return 0
+}
+
+func (x *AgentGroup) GetAgentCount() int32 {
+	if x != nil {
+		return x.AgentCount
+	}
+	return 0
+}
+
+// ListAgentGroupsRequest takes no parameters
+type ListAgentGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentGroupsRequest) Reset() {
+	*x = ListAgentGroupsRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentGroupsRequest) ProtoMessage() {}
+
+func (x *ListAgentGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{65}
+}
+
+// ListAgentGroupsResponse returns the agent groups ordered by name
+type ListAgentGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*AgentGroup          `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentGroupsResponse) Reset() {
+	*x = ListAgentGroupsResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentGroupsResponse) ProtoMessage() {}
+
+func (x *ListAgentGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ListAgentGroupsResponse) GetGroups() []*AgentGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+// DeleteAgentGroupRequest identifies the group to delete
+type DeleteAgentGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       int32                  `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAgentGroupRequest) Reset() {
+	*x = DeleteAgentGroupRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAgentGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAgentGroupRequest) ProtoMessage() {}
+
+func (x *DeleteAgentGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAgentGroupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAgentGroupRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *DeleteAgentGroupRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// DeleteAgentGroupResponse confirms a deletion
+type DeleteAgentGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAgentGroupResponse) Reset() {
+	*x = DeleteAgentGroupResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAgentGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAgentGroupResponse) ProtoMessage() {}
+
+func (x *DeleteAgentGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAgentGroupResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAgentGroupResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *DeleteAgentGroupResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+// SetAgentGroupRequest moves an agent into a group
+type SetAgentGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`  // Agent UUID
+	GroupId       int32                  `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"` // Group to join, 0 to leave the current one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAgentGroupRequest) Reset() {
+	*x = SetAgentGroupRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAgentGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAgentGroupRequest) ProtoMessage() {}
+
+func (x *SetAgentGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAgentGroupRequest.ProtoReflect.Descriptor instead.
+func (*SetAgentGroupRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *SetAgentGroupRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SetAgentGroupRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// StageRolloutRequest stages a rule change. Updated and deleted rules are
+// identified by rule_id, a deletion needs nothing else.
+type StageRolloutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`                                           // "routing" or "acl"
+	Operation     string                 `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`                                 // "add", "update" or "delete"
+	RoutingRule   *RoutingRule           `protobuf:"bytes,3,opt,name=routing_rule,json=routingRule,proto3" json:"routing_rule,omitempty"`          // Proposed routing rule
+	AgentId       string                 `protobuf:"bytes,4,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                      // Agent an added routing rule applies to, empty for a group rule
+	GroupId       int32                  `protobuf:"varint,5,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`                     // Group an added routing rule applies to, 0 for an agent rule
+	AclRule       *ACLRule               `protobuf:"bytes,6,opt,name=acl_rule,json=aclRule,proto3" json:"acl_rule,omitempty"`                      // Proposed ACL rule
+	Percent       int32                  `protobuf:"varint,7,opt,name=percent,proto3" json:"percent,omitempty"`                                    // Share of agents in the canary, 1-100
+	CanaryGroupId int32                  `protobuf:"varint,8,opt,name=canary_group_id,json=canaryGroupId,proto3" json:"canary_group_id,omitempty"` // Agent group forming the canary instead of a share
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StageRolloutRequest) Reset() {
+	*x = StageRolloutRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StageRolloutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StageRolloutRequest) ProtoMessage() {}
+
+func (x *StageRolloutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StageRolloutRequest.ProtoReflect.Descriptor instead.
+func (*StageRolloutRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *StageRolloutRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *StageRolloutRequest) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *StageRolloutRequest) GetRoutingRule() *RoutingRule {
+	if x != nil {
+		return x.RoutingRule
+	}
+	return nil
+}
+
+func (x *StageRolloutRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *StageRolloutRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *StageRolloutRequest) GetAclRule() *ACLRule {
+	if x != nil {
+		return x.AclRule
+	}
+	return nil
+}
+
+func (x *StageRolloutRequest) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *StageRolloutRequest) GetCanaryGroupId() int32 {
+	if x != nil {
+		return x.CanaryGroupId
+	}
+	return 0
+}
+
+// Rollout is a rule change staged to a canary of agents
+type Rollout struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RolloutId     int32                  `protobuf:"varint,1,opt,name=rollout_id,json=rolloutId,proto3" json:"rollout_id,omitempty"`               // Rollout identifier
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`                                           // "routing" or "acl"
+	Operation     string                 `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`                                 // "add", "update" or "delete"
+	State         string                 `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`                                         // "staged", "promoted" or "rolled_back"
+	RoutingRule   *RoutingRuleResponse   `protobuf:"bytes,5,opt,name=routing_rule,json=routingRule,proto3" json:"routing_rule,omitempty"`          // Proposed routing rule with its agent or group, the deleted one for deletions
+	AclRule       *ACLRule               `protobuf:"bytes,6,opt,name=acl_rule,json=aclRule,proto3" json:"acl_rule,omitempty"`                      // Proposed ACL rule, the deleted one for deletions
+	Percent       int32                  `protobuf:"varint,7,opt,name=percent,proto3" json:"percent,omitempty"`                                    // Share of agents in the canary, 0 with a canary group
+	CanaryGroupId int32                  `protobuf:"varint,8,opt,name=canary_group_id,json=canaryGroupId,proto3" json:"canary_group_id,omitempty"` // Agent group forming the canary, 0 for a share of agents
+	CreatedBy     string                 `protobuf:"bytes,9,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`                // Admin who staged the change
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`               // Staging time
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`               // Promotion or rollback time
+	Canary        *RolloutCohort         `protobuf:"bytes,12,opt,name=canary,proto3" json:"canary,omitempty"`                                      // Traffic of the agents in the canary, while staged
+	Baseline      *RolloutCohort         `protobuf:"bytes,13,opt,name=baseline,proto3" json:"baseline,omitempty"`                                  // Traffic of the other agents, while staged
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rollout) Reset() {
+	*x = Rollout{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rollout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rollout) ProtoMessage() {}
+
+func (x *Rollout) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rollout.ProtoReflect.Descriptor instead.
+func (*Rollout) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *Rollout) GetRolloutId() int32 {
+	if x != nil {
+		return x.RolloutId
+	}
+	return 0
+}
+
+func (x *Rollout) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Rollout) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *Rollout) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Rollout) GetRoutingRule() *RoutingRuleResponse {
+	if x != nil {
+		return x.RoutingRule
+	}
+	return nil
+}
+
+func (x *Rollout) GetAclRule() *ACLRule {
+	if x != nil {
+		return x.AclRule
+	}
+	return nil
+}
+
+func (x *Rollout) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *Rollout) GetCanaryGroupId() int32 {
+	if x != nil {
+		return x.CanaryGroupId
+	}
+	return 0
+}
+
+func (x *Rollout) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Rollout) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Rollout) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Rollout) GetCanary() *RolloutCohort {
+	if x != nil {
+		return x.Canary
+	}
+	return nil
+}
+
+func (x *Rollout) GetBaseline() *RolloutCohort {
+	if x != nil {
+		return x.Baseline
+	}
+	return nil
+}
+
+// RolloutCohort counts the traffic this server relayed from the agents of
+// one side of a staged rollout
+type RolloutCohort struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      int32                  `protobuf:"varint,1,opt,name=sessions,proto3" json:"sessions,omitempty"` // Connected agents
+	Packets       uint64                 `protobuf:"varint,2,opt,name=packets,proto3" json:"packets,omitempty"`   // Packets relayed from the agents since the rollout was staged
+	Bytes         uint64                 `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`       // Bytes of these packets
+	Errors        uint64                 `protobuf:"varint,4,opt,name=errors,proto3" json:"errors,omitempty"`     // Packets that could not be delivered, e.g. without a route
+	Denied        uint64                 `protobuf:"varint,5,opt,name=denied,proto3" json:"denied,omitempty"`     // Packets denied by ACL rules
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RolloutCohort) Reset() {
+	*x = RolloutCohort{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RolloutCohort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RolloutCohort) ProtoMessage() {}
+
+func (x *RolloutCohort) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RolloutCohort.ProtoReflect.Descriptor instead.
+func (*RolloutCohort) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *RolloutCohort) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *RolloutCohort) GetPackets() uint64 {
+	if x != nil {
+		return x.Packets
+	}
+	return 0
+}
+
+func (x *RolloutCohort) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *RolloutCohort) GetErrors() uint64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *RolloutCohort) GetDenied() uint64 {
+	if x != nil {
+		return x.Denied
+	}
+	return 0
+}
+
+// ListRolloutsRequest limits the rollouts listed
+type ListRolloutsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // Maximum rollouts, default 20
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolloutsRequest) Reset() {
+	*x = ListRolloutsRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolloutsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolloutsRequest) ProtoMessage() {}
+
+func (x *ListRolloutsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolloutsRequest.ProtoReflect.Descriptor instead.
+func (*ListRolloutsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *ListRolloutsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListRolloutsResponse returns rollouts, newest first
+type ListRolloutsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rollouts      []*Rollout             `protobuf:"bytes,1,rep,name=rollouts,proto3" json:"rollouts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolloutsResponse) Reset() {
+	*x = ListRolloutsResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolloutsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolloutsResponse) ProtoMessage() {}
+
+func (x *ListRolloutsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolloutsResponse.ProtoReflect.Descriptor instead.
+func (*ListRolloutsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ListRolloutsResponse) GetRollouts() []*Rollout {
+	if x != nil {
+		return x.Rollouts
+	}
+	return nil
+}
+
+// GetRolloutRequest identifies a rollout
+type GetRolloutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RolloutId     int32                  `protobuf:"varint,1,opt,name=rollout_id,json=rolloutId,proto3" json:"rollout_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRolloutRequest) Reset() {
+	*x = GetRolloutRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRolloutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRolloutRequest) ProtoMessage() {}
+
+func (x *GetRolloutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRolloutRequest.ProtoReflect.Descriptor instead.
+func (*GetRolloutRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *GetRolloutRequest) GetRolloutId() int32 {
+	if x != nil {
+		return x.RolloutId
+	}
+	return 0
+}
+
+// PromoteRolloutRequest identifies the staged rollout to promote
+type PromoteRolloutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RolloutId     int32                  `protobuf:"varint,1,opt,name=rollout_id,json=rolloutId,proto3" json:"rollout_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PromoteRolloutRequest) Reset() {
+	*x = PromoteRolloutRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromoteRolloutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromoteRolloutRequest) ProtoMessage() {}
+
+func (x *PromoteRolloutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromoteRolloutRequest.ProtoReflect.Descriptor instead.
+func (*PromoteRolloutRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *PromoteRolloutRequest) GetRolloutId() int32 {
+	if x != nil {
+		return x.RolloutId
+	}
+	return 0
+}
+
+// RollBackRolloutRequest identifies the staged rollout to roll back
+type RollBackRolloutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RolloutId     int32                  `protobuf:"varint,1,opt,name=rollout_id,json=rolloutId,proto3" json:"rollout_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollBackRolloutRequest) Reset() {
+	*x = RollBackRolloutRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollBackRolloutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollBackRolloutRequest) ProtoMessage() {}
+
+func (x *RollBackRolloutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollBackRolloutRequest.ProtoReflect.Descriptor instead.
+func (*RollBackRolloutRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *RollBackRolloutRequest) GetRolloutId() int32 {
+	if x != nil {
+		return x.RolloutId
+	}
+	return 0
+}
+
+// SimulatePolicyRequest describes a rule change like StageRolloutRequest,
+// without a canary
+type SimulatePolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`                                  // "routing" or "acl"
+	Operation     string                 `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`                        // "add", "update" or "delete"
+	RoutingRule   *RoutingRule           `protobuf:"bytes,3,opt,name=routing_rule,json=routingRule,proto3" json:"routing_rule,omitempty"` // Proposed routing rule, or the rule_id of the one to delete
+	AgentId       string                 `protobuf:"bytes,4,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`             // Agent an added routing rule applies to, empty for a group rule
+	GroupId       int32                  `protobuf:"varint,5,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`            // Group an added routing rule applies to, 0 for an agent rule
+	AclRule       *ACLRule               `protobuf:"bytes,6,opt,name=acl_rule,json=aclRule,proto3" json:"acl_rule,omitempty"`             // Proposed ACL rule, or the rule_id of the one to delete
+	Limit         int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`                               // Maximum flows returned, default 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimulatePolicyRequest) Reset() {
+	*x = SimulatePolicyRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulatePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulatePolicyRequest) ProtoMessage() {}
+
+func (x *SimulatePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulatePolicyRequest.ProtoReflect.Descriptor instead.
+func (*SimulatePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *SimulatePolicyRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SimulatePolicyRequest) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *SimulatePolicyRequest) GetRoutingRule() *RoutingRule {
+	if x != nil {
+		return x.RoutingRule
+	}
+	return nil
+}
+
+func (x *SimulatePolicyRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SimulatePolicyRequest) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *SimulatePolicyRequest) GetAclRule() *ACLRule {
+	if x != nil {
+		return x.AclRule
+	}
+	return nil
+}
+
+func (x *SimulatePolicyRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// SimulatePolicyResponse lists the flows of the relay traces whose
+// outcome the change would alter
+type SimulatePolicyResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FlowsEvaluated int32                  `protobuf:"varint,1,opt,name=flows_evaluated,json=flowsEvaluated,proto3" json:"flows_evaluated,omitempty"` // Distinct flows in the relay traces
+	Flows          []*SimulatedFlow       `protobuf:"bytes,2,rep,name=flows,proto3" json:"flows,omitempty"`                                          // Flows the change affects, most sampled first
+	SampleRate     uint32                 `protobuf:"varint,3,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`             // Relay trace sampling rate, 0 if tracing is off
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SimulatePolicyResponse) Reset() {
+	*x = SimulatePolicyResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulatePolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulatePolicyResponse) ProtoMessage() {}
+
+func (x *SimulatePolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulatePolicyResponse.ProtoReflect.Descriptor instead.
+func (*SimulatePolicyResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *SimulatePolicyResponse) GetFlowsEvaluated() int32 {
+	if x != nil {
+		return x.FlowsEvaluated
+	}
+	return 0
+}
+
+func (x *SimulatePolicyResponse) GetFlows() []*SimulatedFlow {
+	if x != nil {
+		return x.Flows
+	}
+	return nil
+}
+
+func (x *SimulatePolicyResponse) GetSampleRate() uint32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+// SimulatedFlow is a flow of the relay traces with its outcome under the
+// current and the proposed rules
+type SimulatedFlow struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SourceAgentId   string                 `protobuf:"bytes,1,opt,name=source_agent_id,json=sourceAgentId,proto3" json:"source_agent_id,omitempty"`
+	SourceIp        string                 `protobuf:"bytes,2,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`
+	DestinationIp   string                 `protobuf:"bytes,3,opt,name=destination_ip,json=destinationIp,proto3" json:"destination_ip,omitempty"`
+	Protocol        uint32                 `protobuf:"varint,4,opt,name=protocol,proto3" json:"protocol,omitempty"`                                      // IP protocol number
+	DestinationPort uint32                 `protobuf:"varint,5,opt,name=destination_port,json=destinationPort,proto3" json:"destination_port,omitempty"` // TCP or UDP destination port, 0 if none
+	Packets         uint32                 `protobuf:"varint,6,opt,name=packets,proto3" json:"packets,omitempty"`                                        // Sampled packets of the flow
+	LastSeen        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Change          string                 `protobuf:"bytes,8,opt,name=change,proto3" json:"change,omitempty"` // "denied", "allowed" or "rerouted"
+	Before          string                 `protobuf:"bytes,9,opt,name=before,proto3" json:"before,omitempty"` // Outcome under the current rules, e.g. "allow", "deny by rule 4", "forward via <gateway>"
+	After           string                 `protobuf:"bytes,10,opt,name=after,proto3" json:"after,omitempty"`  // Outcome under the proposed rules
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SimulatedFlow) Reset() {
+	*x = SimulatedFlow{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulatedFlow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulatedFlow) ProtoMessage() {}
+
+func (x *SimulatedFlow) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulatedFlow.ProtoReflect.Descriptor instead.
+func (*SimulatedFlow) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *SimulatedFlow) GetSourceAgentId() string {
+	if x != nil {
+		return x.SourceAgentId
+	}
+	return ""
+}
+
+func (x *SimulatedFlow) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
+}
+
+func (x *SimulatedFlow) GetDestinationIp() string {
+	if x != nil {
+		return x.DestinationIp
+	}
+	return ""
+}
+
+func (x *SimulatedFlow) GetProtocol() uint32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *SimulatedFlow) GetDestinationPort() uint32 {
+	if x != nil {
+		return x.DestinationPort
+	}
+	return 0
+}
+
+func (x *SimulatedFlow) GetPackets() uint32 {
+	if x != nil {
+		return x.Packets
+	}
+	return 0
+}
+
+func (x *SimulatedFlow) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *SimulatedFlow) GetChange() string {
+	if x != nil {
+		return x.Change
+	}
+	return ""
+}
+
+func (x *SimulatedFlow) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *SimulatedFlow) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+// GetFaultInjectionRequest takes no parameters
+type GetFaultInjectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFaultInjectionRequest) Reset() {
+	*x = GetFaultInjectionRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFaultInjectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFaultInjectionRequest) ProtoMessage() {}
+
+func (x *GetFaultInjectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFaultInjectionRequest.ProtoReflect.Descriptor instead.
+func (*GetFaultInjectionRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{81}
+}
+
+// FaultInjection holds the faults a server built with the chaos tag
+// injects, all zero for none
+type FaultInjection struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RelayDropRate    float64                `protobuf:"fixed64,1,opt,name=relay_drop_rate,json=relayDropRate,proto3" json:"relay_drop_rate,omitempty"`         // Fraction of relayed packets dropped, 0 to 1
+	HeartbeatDelayMs int32                  `protobuf:"varint,2,opt,name=heartbeat_delay_ms,json=heartbeatDelayMs,proto3" json:"heartbeat_delay_ms,omitempty"` // Delay of every heartbeat response
+	DbFailureRate    float64                `protobuf:"fixed64,3,opt,name=db_failure_rate,json=dbFailureRate,proto3" json:"db_failure_rate,omitempty"`         // Fraction of database calls failed, 0 to 1
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *FaultInjection) Reset() {
+	*x = FaultInjection{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FaultInjection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaultInjection) ProtoMessage() {}
+
+func (x *FaultInjection) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaultInjection.ProtoReflect.Descriptor instead.
+func (*FaultInjection) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *FaultInjection) GetRelayDropRate() float64 {
+	if x != nil {
+		return x.RelayDropRate
+	}
+	return 0
+}
+
+func (x *FaultInjection) GetHeartbeatDelayMs() int32 {
+	if x != nil {
+		return x.HeartbeatDelayMs
+	}
+	return 0
+}
+
+func (x *FaultInjection) GetDbFailureRate() float64 {
+	if x != nil {
+		return x.DbFailureRate
+	}
+	return 0
+}
+
+// ListAccessRequestsRequest filters access requests
+type ListAccessRequestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`                    // "pending", "approved", "denied" or "expired", empty for all
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Empty for all agents
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                   // Maximum requests, default 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccessRequestsRequest) Reset() {
+	*x = ListAccessRequestsRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccessRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccessRequestsRequest) ProtoMessage() {}
+
+func (x *ListAccessRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccessRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListAccessRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *ListAccessRequestsRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ListAccessRequestsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListAccessRequestsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListAccessRequestsResponse returns access requests, newest first
+type ListAccessRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*AccessRequest       `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccessRequestsResponse) Reset() {
+	*x = ListAccessRequestsResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccessRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccessRequestsResponse) ProtoMessage() {}
+
+func (x *ListAccessRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccessRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListAccessRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *ListAccessRequestsResponse) GetRequests() []*AccessRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// ApproveAccessRequestRequest identifies the access request to approve
+// and how to grant it
+type ApproveAccessRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     int32                  `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Duration      int32                  `protobuf:"varint,2,opt,name=duration,proto3" json:"duration,omitempty"`                   // Seconds granted, 0 for those asked for
+	GatewayId     string                 `protobuf:"bytes,3,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"` // Gateway to route the destination through, empty to grant the ACL rule only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveAccessRequestRequest) Reset() {
+	*x = ApproveAccessRequestRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveAccessRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveAccessRequestRequest) ProtoMessage() {}
+
+func (x *ApproveAccessRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveAccessRequestRequest.ProtoReflect.Descriptor instead.
+func (*ApproveAccessRequestRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *ApproveAccessRequestRequest) GetRequestId() int32 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *ApproveAccessRequestRequest) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *ApproveAccessRequestRequest) GetGatewayId() string {
+	if x != nil {
+		return x.GatewayId
+	}
+	return ""
+}
+
+// DenyAccessRequestRequest identifies the access request to deny
+type DenyAccessRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     int32                  `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DenyAccessRequestRequest) Reset() {
+	*x = DenyAccessRequestRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DenyAccessRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DenyAccessRequestRequest) ProtoMessage() {}
+
+func (x *DenyAccessRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DenyAccessRequestRequest.ProtoReflect.Descriptor instead.
+func (*DenyAccessRequestRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *DenyAccessRequestRequest) GetRequestId() int32 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+// ListAuditLogRequest filters the audit log
+type ListAuditLogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"` // Empty for all principals
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`       // Unset for no lower bound
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`      // Maximum entries, default 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditLogRequest) Reset() {
+	*x = ListAuditLogRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogRequest) ProtoMessage() {}
+
+func (x *ListAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *ListAuditLogRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ListAuditLogRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListAuditLogRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// AuditEntry records an admin API call by a principal
+type AuditEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntryId       int64                  `protobuf:"varint,1,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Principal, kept after the user is deleted
+	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`        // Role of the principal at the time of the call
+	Action        string                 `protobuf:"bytes,6,opt,name=action,proto3" json:"action,omitempty"`    // Method called, e.g. "/easyanylink.v2.AdminService/DeleteUser"
+	Allowed       bool                   `protobuf:"varint,7,opt,name=allowed,proto3" json:"allowed,omitempty"` // False if the role did not permit the call
+	SourceIp      string                 `protobuf:"bytes,8,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *AuditEntry) GetEntryId() int64 {
+	if x != nil {
+		return x.EntryId
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *AuditEntry) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
+}
+
+// ListAuditLogResponse returns audit log entries, newest first
+type ListAuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*AuditEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditLogResponse) Reset() {
+	*x = ListAuditLogResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogResponse) ProtoMessage() {}
+
+func (x *ListAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *ListAuditLogResponse) GetEntries() []*AuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// HandOffSessionsRequest selects the sessions to move and their new server
+type HandOffSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Only the session of this agent, empty for all
+	Server        string                 `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`                  // host:port agents re-dial, empty for the first of network.alternate_servers
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandOffSessionsRequest) Reset() {
+	*x = HandOffSessionsRequest{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandOffSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandOffSessionsRequest) ProtoMessage() {}
+
+func (x *HandOffSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandOffSessionsRequest.ProtoReflect.Descriptor instead.
+func (*HandOffSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *HandOffSessionsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *HandOffSessionsRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+// HandOffSessionsResponse counts the sessions signaled to move
+type HandOffSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      int32                  `protobuf:"varint,1,opt,name=sessions,proto3" json:"sessions,omitempty"` // Sessions whose agents were told to move
+	Skipped       int32                  `protobuf:"varint,2,opt,name=skipped,proto3" json:"skipped,omitempty"`   // Sessions of agents without CapabilitySessionHandoff, left in place
+	Server        string                 `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`      // Server the sessions move to
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandOffSessionsResponse) Reset() {
+	*x = HandOffSessionsResponse{}
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandOffSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandOffSessionsResponse) ProtoMessage() {}
+
+func (x *HandOffSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_admin_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandOffSessionsResponse.ProtoReflect.Descriptor instead.
+func (*HandOffSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *HandOffSessionsResponse) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *HandOffSessionsResponse) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *HandOffSessionsResponse) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+var File_common_proto_easyanylink_v2_admin_proto protoreflect.FileDescriptor
+
+const file_common_proto_easyanylink_v2_admin_proto_rawDesc = "" +
+	"\n" +
+	"'common/proto/easyanylink/v2/admin.proto\x12\x0eeasyanylink.v2\x1a\x1fgoogle/protobuf/timestamp.proto\x1a'common/proto/easyanylink/v2/agent.proto\"~\n" +
+	"\x15AddRoutingRuleRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12/\n" +
+	"\x04rule\x18\x02 \x01(\v2\x1b.easyanylink.v2.RoutingRuleR\x04rule\x12\x19\n" +
+	"\bgroup_id\x18\x03 \x01(\x05R\agroupId\"K\n" +
+	"\x18UpdateRoutingRuleRequest\x12/\n" +
+	"\x04rule\x18\x01 \x01(\v2\x1b.easyanylink.v2.RoutingRuleR\x04rule\"|\n" +
+	"\x13RoutingRuleResponse\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12/\n" +
+	"\x04rule\x18\x02 \x01(\v2\x1b.easyanylink.v2.RoutingRuleR\x04rule\x12\x19\n" +
+	"\bgroup_id\x18\x03 \x01(\x05R\agroupId\"3\n" +
+	"\x18DeleteRoutingRuleRequest\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x05R\x06ruleId\"k\n" +
+	"\x19DeleteRoutingRuleResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x19\n" +
+	"\bgroup_id\x18\x03 \x01(\x05R\agroupId\"\x84\x03\n" +
+	"\x11ListAgentsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12-\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x19.easyanylink.v2.AgentTypeR\x04type\x123\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1b.easyanylink.v2.AgentStatusR\x06status\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\x12E\n" +
+	"\x06labels\x18\x06 \x03(\v2-.easyanylink.v2.ListAgentsRequest.LabelsEntryR\x06labels\x12\x1a\n" +
+	"\barchived\x18\a \x01(\bR\barchived\x12\x18\n" +
+	"\apending\x18\b \x01(\bR\apending\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"q\n" +
+	"\x12ListAgentsResponse\x123\n" +
+	"\x06agents\x18\x01 \x03(\v2\x1b.easyanylink.v2.AgentDetailR\x06agents\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\",\n" +
+	"\x0fGetAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"\x98\t\n" +
+	"\vAgentDetail\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12-\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x19.easyanylink.v2.AgentTypeR\x04type\x123\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1b.easyanylink.v2.AgentStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x06 \x01(\tR\tipAddress\x12\x1b\n" +
+	"\tpublic_ip\x18\a \x01(\tR\bpublicIp\x129\n" +
+	"\bmetadata\x18\b \x01(\v2\x1d.easyanylink.v2.AgentMetadataR\bmetadata\x120\n" +
+	"\x05stats\x18\t \x01(\v2\x1a.easyanylink.v2.AgentStatsR\x05stats\x127\n" +
+	"\tlast_seen\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\f \x01(\tR\tsessionId\x12\x1c\n" +
+	"\tconnected\x18\r \x01(\bR\tconnected\x12;\n" +
+	"\varchived_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\x12\x18\n" +
+	"\apending\x18\x0f \x01(\bR\apending\x123\n" +
+	"\x06health\x18\x10 \x01(\v2\x1b.easyanylink.v2.AgentHealthR\x06health\x12\x14\n" +
+	"\x05group\x18\x11 \x01(\tR\x05group\x12\x1f\n" +
+	"\vapi_version\x18\x12 \x01(\x05R\n" +
+	"apiVersion\x12\"\n" +
+	"\fcapabilities\x18\x13 \x03(\tR\fcapabilities\x12!\n" +
+	"\fsite_subnets\x18\x14 \x03(\tR\vsiteSubnets\x12+\n" +
+	"\x11config_generation\x18\x15 \x01(\x04R\x10configGeneration\x12%\n" +
+	"\x0econfig_drifted\x18\x16 \x01(\bR\rconfigDrifted\x12\"\n" +
+	"\rclock_skew_ms\x18\x17 \x01(\x03R\vclockSkewMs\x12\x1b\n" +
+	"\tnot_ready\x18\x18 \x01(\bR\bnotReady\x12>\n" +
+	"\fflow_control\x18\x19 \x01(\v2\x1b.easyanylink.v2.FlowControlR\vflowControl\x12>\n" +
+	"\tresources\x18\x1a \x01(\v2 .easyanylink.v2.SessionResourcesR\tresources\x129\n" +
+	"\bidentity\x18\x1b \x01(\v2\x1d.easyanylink.v2.AgentIdentityR\bidentity\x12)\n" +
+	"\x10posture_failures\x18\x1c \x03(\tR\x0fpostureFailures\"M\n" +
+	"\rAgentIdentity\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12 \n" +
+	"\vfingerprint\x18\x02 \x01(\tR\vfingerprint\"\xaa\x01\n" +
+	"\x10SessionResources\x12\x1e\n" +
+	"\n" +
+	"goroutines\x18\x01 \x01(\x05R\n" +
+	"goroutines\x12%\n" +
+	"\x0ebuffered_bytes\x18\x02 \x01(\x03R\rbufferedBytes\x12,\n" +
+	"\x12memory_limit_bytes\x18\x03 \x01(\x03R\x10memoryLimitBytes\x12!\n" +
+	"\fmemory_drops\x18\x04 \x01(\x04R\vmemoryDrops\"\xb3\x01\n" +
+	"\vFlowControl\x12\x1f\n" +
+	"\vqueue_depth\x18\x01 \x01(\x05R\n" +
+	"queueDepth\x12\x1d\n" +
+	"\n" +
+	"stalled_ms\x18\x02 \x01(\x03R\tstalledMs\x12 \n" +
+	"\fmax_stall_ms\x18\x03 \x01(\x03R\n" +
+	"maxStallMs\x12\x1d\n" +
+	"\n" +
+	"slow_sends\x18\x04 \x01(\x04R\tslowSends\x12#\n" +
+	"\rslow_consumer\x18\x05 \x01(\bR\fslowConsumer\"O\n" +
+	"\x17ListRoutingRulesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\x05R\agroupId\"M\n" +
+	"\x18ListRoutingRulesResponse\x121\n" +
+	"\x05rules\x18\x01 \x03(\v2\x1b.easyanylink.v2.RoutingRuleR\x05rules\"\x81\x02\n" +
+	"\x11CreateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x1d\n" +
+	"\n" +
+	"max_agents\x18\x05 \x01(\x05R\tmaxAgents\x12#\n" +
+	"\rmax_bandwidth\x18\x06 \x01(\x05R\fmaxBandwidth\x12!\n" +
+	"\ftransfer_cap\x18\a \x01(\x04R\vtransferCap\x12#\n" +
+	"\rsite_prefixes\x18\b \x03(\tR\fsitePrefixes\".\n" +
+	"\x13RotateAPIKeyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x86\x01\n" +
+	"\fUserResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x17\n" +
+	"\aapi_key\x18\x05 \x01(\tR\x06apiKey\"\x12\n" +
+	"\x10ListUsersRequest\"E\n" +
+	"\x11ListUsersResponse\x120\n" +
+	"\x05users\x18\x01 \x03(\v2\x1a.easyanylink.v2.UserDetailR\x05users\"L\n" +
+	"\x0eGetUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fusage_months\x18\x02 \x01(\x05R\vusageMonths\"\xfa\x01\n" +
+	"\x11UpdateUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"max_agents\x18\x05 \x01(\x05R\tmaxAgents\x12#\n" +
+	"\rmax_bandwidth\x18\x06 \x01(\x05R\fmaxBandwidth\x12!\n" +
+	"\ftransfer_cap\x18\a \x01(\x04R\vtransferCap\x12#\n" +
+	"\rsite_prefixes\x18\b \x03(\tR\fsitePrefixes\",\n" +
+	"\x11DeleteUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"U\n" +
+	"\x12DeleteUserResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\x12%\n" +
+	"\x0eagents_deleted\x18\x02 \x01(\x05R\ragentsDeleted\"\x9c\x03\n" +
+	"\n" +
+	"UserDetail\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"max_agents\x18\x06 \x01(\x05R\tmaxAgents\x12#\n" +
+	"\rmax_bandwidth\x18\a \x01(\x05R\fmaxBandwidth\x12!\n" +
+	"\ftransfer_cap\x18\b \x01(\x04R\vtransferCap\x12\x1f\n" +
+	"\vagent_count\x18\t \x01(\x05R\n" +
+	"agentCount\x12/\n" +
+	"\x05usage\x18\n" +
+	" \x03(\v2\x19.easyanylink.v2.UserUsageR\x05usage\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12#\n" +
+	"\rsite_prefixes\x18\f \x03(\tR\fsitePrefixes\"g\n" +
+	"\tUserUsage\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\tR\x05month\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x02 \x01(\x04R\tbytesSent\x12%\n" +
+	"\x0ebytes_received\x18\x03 \x01(\x04R\rbytesReceived\"B\n" +
+	"\x12ExportUsageRequest\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\tR\x05month\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\"b\n" +
+	"\x13ExportUsageResponse\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\tR\x05month\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"\xaf\x01\n" +
+	"\x19ListTrafficRollupsRequest\x12\x16\n" +
+	"\x06period\x18\x01 \x01(\tR\x06period\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x120\n" +
+	"\x05since\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"U\n" +
+	"\x1aListTrafficRollupsResponse\x127\n" +
+	"\arollups\x18\x01 \x03(\v2\x1d.easyanylink.v2.TrafficRollupR\arollups\"\x91\x02\n" +
+	"\rTrafficRollup\x12=\n" +
+	"\fperiod_start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\vperiodStart\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bsessions\x18\x04 \x01(\rR\bsessions\x12+\n" +
+	"\x11connected_seconds\x18\x05 \x01(\x04R\x10connectedSeconds\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x06 \x01(\x04R\tbytesSent\x12%\n" +
+	"\x0ebytes_received\x18\a \x01(\x04R\rbytesReceived\"H\n" +
+	"\x16ExportInventoryRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"q\n" +
+	"\x17ExportInventoryResponse\x12!\n" +
+	"\fcontent_type\x18\x01 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x1f\n" +
+	"\vagent_count\x18\x03 \x01(\x05R\n" +
+	"agentCount\"9\n" +
+	"\x16SetRelayTracingRequest\x12\x1f\n" +
+	"\vsample_rate\x18\x01 \x01(\rR\n" +
+	"sampleRate\"X\n" +
+	"\x14RelayTracingResponse\x12\x1f\n" +
+	"\vsample_rate\x18\x01 \x01(\rR\n" +
+	"sampleRate\x12\x1f\n" +
+	"\vbuffer_size\x18\x02 \x01(\rR\n" +
+	"bufferSize\"I\n" +
+	"\x16ListRelayTracesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"n\n" +
+	"\x17ListRelayTracesResponse\x122\n" +
+	"\x06traces\x18\x01 \x03(\v2\x1a.easyanylink.v2.RelayTraceR\x06traces\x12\x1f\n" +
+	"\vsample_rate\x18\x02 \x01(\rR\n" +
+	"sampleRate\"\x9e\x03\n" +
+	"\n" +
+	"RelayTrace\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12&\n" +
+	"\x0fsource_agent_id\x18\x03 \x01(\tR\rsourceAgentId\x120\n" +
+	"\x14destination_agent_id\x18\x04 \x01(\tR\x12destinationAgentId\x12\x1b\n" +
+	"\tsource_ip\x18\x05 \x01(\tR\bsourceIp\x12%\n" +
+	"\x0edestination_ip\x18\x06 \x01(\tR\rdestinationIp\x12\x1a\n" +
+	"\bprotocol\x18\a \x01(\rR\bprotocol\x12\x16\n" +
+	"\x06length\x18\b \x01(\rR\x06length\x12\x10\n" +
+	"\x03ttl\x18\t \x01(\rR\x03ttl\x12\x1a\n" +
+	"\bdecision\x18\n" +
+	" \x01(\tR\bdecision\x12\x16\n" +
+	"\x06detail\x18\v \x01(\tR\x06detail\x12)\n" +
+	"\x10destination_port\x18\f \x01(\rR\x0fdestinationPort\"\x1a\n" +
+	"\x18GetHandshakeStatsRequest\"\xe5\x01\n" +
+	"\x16HandshakeStatsResponse\x12\x1c\n" +
+	"\tvalidated\x18\x01 \x01(\x04R\tvalidated\x12 \n" +
+	"\vunvalidated\x18\x02 \x01(\x04R\vunvalidated\x12\x18\n" +
+	"\aretries\x18\x03 \x01(\x04R\aretries\x12%\n" +
+	"\x0einvalid_tokens\x18\x04 \x01(\x04R\rinvalidTokens\x12'\n" +
+	"\x0fretry_threshold\x18\x05 \x01(\x05R\x0eretryThreshold\x12!\n" +
+	"\fretry_active\x18\x06 \x01(\bR\vretryActive\"\x18\n" +
+	"\x16GetCryptoPolicyRequest\"\xab\x01\n" +
+	"\x14CryptoPolicyResponse\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x1f\n" +
+	"\vfips_module\x18\x02 \x01(\bR\n" +
+	"fipsModule\x12\x1d\n" +
+	"\n" +
+	"fips_build\x18\x03 \x01(\bR\tfipsBuild\x12#\n" +
+	"\rcipher_suites\x18\x04 \x03(\tR\fcipherSuites\x12\x16\n" +
+	"\x06curves\x18\x05 \x03(\tR\x06curves\"\x1b\n" +
+	"\x19GetRelayQueueStatsRequest\"\x95\x02\n" +
+	"\x17RelayQueueStatsResponse\x12;\n" +
+	"\aclasses\x18\x01 \x03(\v2!.easyanylink.v2.TrafficClassStatsR\aclasses\x12<\n" +
+	"\tmulticast\x18\x02 \x01(\v2\x1e.easyanylink.v2.MulticastStatsR\tmulticast\x12C\n" +
+	"\x0eslow_consumers\x18\x03 \x03(\v2\x1c.easyanylink.v2.SlowConsumerR\rslowConsumers\x12:\n" +
+	"\x19slow_consumers_terminated\x18\x04 \x01(\x04R\x17slowConsumersTerminated\"\x88\x01\n" +
+	"\fSlowConsumer\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12>\n" +
+	"\fflow_control\x18\x03 \x01(\v2\x1b.easyanylink.v2.FlowControlR\vflowControl\"\xb1\x01\n" +
+	"\x0eMulticastStats\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x18\n" +
+	"\adropped\x18\x02 \x01(\x04R\adropped\x12\x18\n" +
+	"\arelayed\x18\x03 \x01(\x04R\arelayed\x12\x18\n" +
+	"\aunicast\x18\x04 \x01(\x04R\aunicast\x12!\n" +
+	"\frate_limited\x18\x05 \x01(\x04R\vrateLimited\x12\x16\n" +
+	"\x06copies\x18\x06 \x01(\x04R\x06copies\"0\n" +
+	"\x13ArchiveAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"0\n" +
+	"\x13RestoreAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"L\n" +
+	"\x19ListSessionHistoryRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"W\n" +
+	"\x1aListSessionHistoryResponse\x129\n" +
+	"\bsessions\x18\x01 \x03(\v2\x1d.easyanylink.v2.SessionRecordR\bsessions\"\xee\x02\n" +
+	"\rSessionRecord\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12=\n" +
+	"\fconnected_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vconnectedAt\x12C\n" +
+	"\x0fdisconnected_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0edisconnectedAt\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x05 \x01(\x04R\tbytesSent\x12%\n" +
+	"\x0ebytes_received\x18\x06 \x01(\x04R\rbytesReceived\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12A\n" +
+	"\vreason_code\x18\b \x01(\x0e2 .easyanylink.v2.DisconnectReasonR\n" +
+	"reasonCode\"0\n" +
+	"\x13ApproveAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"/\n" +
+	"\x12RejectAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"6\n" +
+	"\x19ResetAgentIdentityRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"1\n" +
+	"\x13RejectAgentResponse\x12\x1a\n" +
+	"\brejected\x18\x01 \x01(\bR\brejected\"\x98\x03\n" +
+	"\aACLRule\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x05R\x06ruleId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x04 \x01(\tR\vdestination\x12\x1a\n" +
+	"\bprotocol\x18\x05 \x01(\tR\bprotocol\x12\x1b\n" +
+	"\tport_from\x18\x06 \x01(\rR\bportFrom\x12\x17\n" +
+	"\aport_to\x18\a \x01(\rR\x06portTo\x12\x16\n" +
+	"\x06action\x18\b \x01(\tR\x06action\x12\x1a\n" +
+	"\bpriority\x18\t \x01(\x05R\bpriority\x12\x18\n" +
+	"\aenabled\x18\n" +
+	" \x01(\bR\aenabled\x124\n" +
+	"\x06window\x18\v \x01(\v2\x1c.easyanylink.v2.AccessWindowR\x06window\x12\"\n" +
+	"\n" +
+	"source_uid\x18\f \x01(\rH\x00R\tsourceUid\x88\x01\x01\x12\x18\n" +
+	"\aposture\x18\r \x01(\tR\apostureB\r\n" +
+	"\v_source_uid\".\n" +
+	"\x13ListACLRulesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"E\n" +
+	"\x14ListACLRulesResponse\x12-\n" +
+	"\x05rules\x18\x01 \x03(\v2\x17.easyanylink.v2.ACLRuleR\x05rules\"@\n" +
+	"\x11AddACLRuleRequest\x12+\n" +
+	"\x04rule\x18\x01 \x01(\v2\x17.easyanylink.v2.ACLRuleR\x04rule\"C\n" +
+	"\x14UpdateACLRuleRequest\x12+\n" +
+	"\x04rule\x18\x01 \x01(\v2\x17.easyanylink.v2.ACLRuleR\x04rule\"/\n" +
+	"\x14DeleteACLRuleRequest\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x05R\x06ruleId\"J\n" +
+	"\x15DeleteACLRuleResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x15\n" +
+	"\x13GetKeepaliveRequest\"K\n" +
+	"\x13SetKeepaliveRequest\x12\x1a\n" +
+	"\binterval\x18\x01 \x01(\x05R\binterval\x12\x18\n" +
+	"\atimeout\x18\x02 \x01(\x05R\atimeout\"I\n" +
+	"\x11KeepaliveResponse\x12\x1a\n" +
+	"\binterval\x18\x01 \x01(\x05R\binterval\x12\x18\n" +
+	"\atimeout\x18\x02 \x01(\x05R\atimeout\"\xe7\x01\n" +
+	"\n" +
+	"AgentGroup\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\x05R\agroupId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vdns_servers\x18\x04 \x03(\tR\n" +
+	"dnsServers\x12\x1d\n" +
+	"\n" +
+	"dns_search\x18\x05 \x03(\tR\tdnsSearch\x12'\n" +
+	"\x0fbandwidth_limit\x18\x06 \x01(\x05R\x0ebandwidthLimit\x12\x1f\n" +
+	"\vagent_count\x18\a \x01(\x05R\n" +
+	"agentCount\"\x18\n" +
+	"\x16ListAgentGroupsRequest\"M\n" +
+	"\x17ListAgentGroupsResponse\x122\n" +
+	"\x06groups\x18\x01 \x03(\v2\x1a.easyanylink.v2.AgentGroupR\x06groups\"4\n" +
+	"\x17DeleteAgentGroupRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\x05R\agroupId\"4\n" +
+	"\x18DeleteAgentGroupResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"L\n" +
+	"\x14SetAgentGroupRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\x05R\agroupId\"\xb3\x02\n" +
+	"\x13StageRolloutRequest\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x1c\n" +
+	"\toperation\x18\x02 \x01(\tR\toperation\x12>\n" +
+	"\frouting_rule\x18\x03 \x01(\v2\x1b.easyanylink.v2.RoutingRuleR\vroutingRule\x12\x19\n" +
+	"\bagent_id\x18\x04 \x01(\tR\aagentId\x12\x19\n" +
+	"\bgroup_id\x18\x05 \x01(\x05R\agroupId\x122\n" +
+	"\bacl_rule\x18\x06 \x01(\v2\x17.easyanylink.v2.ACLRuleR\aaclRule\x12\x18\n" +
+	"\apercent\x18\a \x01(\x05R\apercent\x12&\n" +
+	"\x0fcanary_group_id\x18\b \x01(\x05R\rcanaryGroupId\"\xb5\x04\n" +
+	"\aRollout\x12\x1d\n" +
+	"\n" +
+	"rollout_id\x18\x01 \x01(\x05R\trolloutId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x1c\n" +
+	"\toperation\x18\x03 \x01(\tR\toperation\x12\x14\n" +
+	"\x05state\x18\x04 \x01(\tR\x05state\x12F\n" +
+	"\frouting_rule\x18\x05 \x01(\v2#.easyanylink.v2.RoutingRuleResponseR\vroutingRule\x122\n" +
+	"\bacl_rule\x18\x06 \x01(\v2\x17.easyanylink.v2.ACLRuleR\aaclRule\x12\x18\n" +
+	"\apercent\x18\a \x01(\x05R\apercent\x12&\n" +
+	"\x0fcanary_group_id\x18\b \x01(\x05R\rcanaryGroupId\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\t \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x125\n" +
+	"\x06canary\x18\f \x01(\v2\x1d.easyanylink.v2.RolloutCohortR\x06canary\x129\n" +
+	"\bbaseline\x18\r \x01(\v2\x1d.easyanylink.v2.RolloutCohortR\bbaseline\"\x8b\x01\n" +
+	"\rRolloutCohort\x12\x1a\n" +
+	"\bsessions\x18\x01 \x01(\x05R\bsessions\x12\x18\n" +
+	"\apackets\x18\x02 \x01(\x04R\apackets\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x04R\x05bytes\x12\x16\n" +
+	"\x06errors\x18\x04 \x01(\x04R\x06errors\x12\x16\n" +
+	"\x06denied\x18\x05 \x01(\x04R\x06denied\"+\n" +
+	"\x13ListRolloutsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\"K\n" +
+	"\x14ListRolloutsResponse\x123\n" +
+	"\brollouts\x18\x01 \x03(\v2\x17.easyanylink.v2.RolloutR\brollouts\"2\n" +
+	"\x11GetRolloutRequest\x12\x1d\n" +
+	"\n" +
+	"rollout_id\x18\x01 \x01(\x05R\trolloutId\"6\n" +
+	"\x15PromoteRolloutRequest\x12\x1d\n" +
+	"\n" +
+	"rollout_id\x18\x01 \x01(\x05R\trolloutId\"7\n" +
+	"\x16RollBackRolloutRequest\x12\x1d\n" +
+	"\n" +
+	"rollout_id\x18\x01 \x01(\x05R\trolloutId\"\x89\x02\n" +
+	"\x15SimulatePolicyRequest\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x1c\n" +
+	"\toperation\x18\x02 \x01(\tR\toperation\x12>\n" +
+	"\frouting_rule\x18\x03 \x01(\v2\x1b.easyanylink.v2.RoutingRuleR\vroutingRule\x12\x19\n" +
+	"\bagent_id\x18\x04 \x01(\tR\aagentId\x12\x19\n" +
+	"\bgroup_id\x18\x05 \x01(\x05R\agroupId\x122\n" +
+	"\bacl_rule\x18\x06 \x01(\v2\x17.easyanylink.v2.ACLRuleR\aaclRule\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\"\x97\x01\n" +
+	"\x16SimulatePolicyResponse\x12'\n" +
+	"\x0fflows_evaluated\x18\x01 \x01(\x05R\x0eflowsEvaluated\x123\n" +
+	"\x05flows\x18\x02 \x03(\v2\x1d.easyanylink.v2.SimulatedFlowR\x05flows\x12\x1f\n" +
+	"\vsample_rate\x18\x03 \x01(\rR\n" +
+	"sampleRate\"\xdb\x02\n" +
+	"\rSimulatedFlow\x12&\n" +
+	"\x0fsource_agent_id\x18\x01 \x01(\tR\rsourceAgentId\x12\x1b\n" +
+	"\tsource_ip\x18\x02 \x01(\tR\bsourceIp\x12%\n" +
+	"\x0edestination_ip\x18\x03 \x01(\tR\rdestinationIp\x12\x1a\n" +
+	"\bprotocol\x18\x04 \x01(\rR\bprotocol\x12)\n" +
+	"\x10destination_port\x18\x05 \x01(\rR\x0fdestinationPort\x12\x18\n" +
+	"\apackets\x18\x06 \x01(\rR\apackets\x127\n" +
+	"\tlast_seen\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12\x16\n" +
+	"\x06change\x18\b \x01(\tR\x06change\x12\x16\n" +
+	"\x06before\x18\t \x01(\tR\x06before\x12\x14\n" +
+	"\x05after\x18\n" +
+	" \x01(\tR\x05after\"\x1a\n" +
+	"\x18GetFaultInjectionRequest\"\x8e\x01\n" +
+	"\x0eFaultInjection\x12&\n" +
+	"\x0frelay_drop_rate\x18\x01 \x01(\x01R\rrelayDropRate\x12,\n" +
+	"\x12heartbeat_delay_ms\x18\x02 \x01(\x05R\x10heartbeatDelayMs\x12&\n" +
+	"\x0fdb_failure_rate\x18\x03 \x01(\x01R\rdbFailureRate\"b\n" +
+	"\x19ListAccessRequestsRequest\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"W\n" +
+	"\x1aListAccessRequestsResponse\x129\n" +
+	"\brequests\x18\x01 \x03(\v2\x1d.easyanylink.v2.AccessRequestR\brequests\"w\n" +
+	"\x1bApproveAccessRequestRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\x05R\trequestId\x12\x1a\n" +
+	"\bduration\x18\x02 \x01(\x05R\bduration\x12\x1d\n" +
+	"\n" +
+	"gateway_id\x18\x03 \x01(\tR\tgatewayId\"9\n" +
+	"\x18DenyAccessRequestRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\x05R\trequestId\"y\n" +
+	"\x13ListAuditLogRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\xef\x01\n" +
+	"\n" +
+	"AuditEntry\x12\x19\n" +
+	"\bentry_id\x18\x01 \x01(\x03R\aentryId\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x04 \x01(\tR\busername\x12\x12\n" +
+	"\x04role\x18\x05 \x01(\tR\x04role\x12\x16\n" +
+	"\x06action\x18\x06 \x01(\tR\x06action\x12\x18\n" +
+	"\aallowed\x18\a \x01(\bR\aallowed\x12\x1b\n" +
+	"\tsource_ip\x18\b \x01(\tR\bsourceIp\"L\n" +
+	"\x14ListAuditLogResponse\x124\n" +
+	"\aentries\x18\x01 \x03(\v2\x1a.easyanylink.v2.AuditEntryR\aentries\"K\n" +
+	"\x16HandOffSessionsRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06server\x18\x02 \x01(\tR\x06server\"g\n" +
+	"\x17HandOffSessionsResponse\x12\x1a\n" +
+	"\bsessions\x18\x01 \x01(\x05R\bsessions\x12\x18\n" +
+	"\askipped\x18\x02 \x01(\x05R\askipped\x12\x16\n" +
+	"\x06server\x18\x03 \x01(\tR\x06server2\xac#\n" +
+	"\fAdminService\x12\\\n" +
+	"\x0eAddRoutingRule\x12%.easyanylink.v2.AddRoutingRuleRequest\x1a#.easyanylink.v2.RoutingRuleResponse\x12b\n" +
+	"\x11UpdateRoutingRule\x12(.easyanylink.v2.UpdateRoutingRuleRequest\x1a#.easyanylink.v2.RoutingRuleResponse\x12h\n" +
+	"\x11DeleteRoutingRule\x12(.easyanylink.v2.DeleteRoutingRuleRequest\x1a).easyanylink.v2.DeleteRoutingRuleResponse\x12S\n" +
+	"\n" +
+	"ListAgents\x12!.easyanylink.v2.ListAgentsRequest\x1a\".easyanylink.v2.ListAgentsResponse\x12H\n" +
+	"\bGetAgent\x12\x1f.easyanylink.v2.GetAgentRequest\x1a\x1b.easyanylink.v2.AgentDetail\x12e\n" +
+	"\x10ListRoutingRules\x12'.easyanylink.v2.ListRoutingRulesRequest\x1a(.easyanylink.v2.ListRoutingRulesResponse\x12M\n" +
+	"\n" +
+	"CreateUser\x12!.easyanylink.v2.CreateUserRequest\x1a\x1c.easyanylink.v2.UserResponse\x12Q\n" +
+	"\fRotateAPIKey\x12#.easyanylink.v2.RotateAPIKeyRequest\x1a\x1c.easyanylink.v2.UserResponse\x12P\n" +
+	"\tListUsers\x12 .easyanylink.v2.ListUsersRequest\x1a!.easyanylink.v2.ListUsersResponse\x12E\n" +
+	"\aGetUser\x12\x1e.easyanylink.v2.GetUserRequest\x1a\x1a.easyanylink.v2.UserDetail\x12K\n" +
+	"\n" +
+	"UpdateUser\x12!.easyanylink.v2.UpdateUserRequest\x1a\x1a.easyanylink.v2.UserDetail\x12S\n" +
+	"\n" +
+	"DeleteUser\x12!.easyanylink.v2.DeleteUserRequest\x1a\".easyanylink.v2.DeleteUserResponse\x12V\n" +
+	"\vExportUsage\x12\".easyanylink.v2.ExportUsageRequest\x1a#.easyanylink.v2.ExportUsageResponse\x12k\n" +
+	"\x12ListTrafficRollups\x12).easyanylink.v2.ListTrafficRollupsRequest\x1a*.easyanylink.v2.ListTrafficRollupsResponse\x12b\n" +
+	"\x0fExportInventory\x12&.easyanylink.v2.ExportInventoryRequest\x1a'.easyanylink.v2.ExportInventoryResponse\x12_\n" +
+	"\x0fSetRelayTracing\x12&.easyanylink.v2.SetRelayTracingRequest\x1a$.easyanylink.v2.RelayTracingResponse\x12b\n" +
+	"\x0fListRelayTraces\x12&.easyanylink.v2.ListRelayTracesRequest\x1a'.easyanylink.v2.ListRelayTracesResponse\x12e\n" +
+	"\x11GetHandshakeStats\x12(.easyanylink.v2.GetHandshakeStatsRequest\x1a&.easyanylink.v2.HandshakeStatsResponse\x12P\n" +
+	"\fArchiveAgent\x12#.easyanylink.v2.ArchiveAgentRequest\x1a\x1b.easyanylink.v2.AgentDetail\x12P\n" +
+	"\fRestoreAgent\x12#.easyanylink.v2.RestoreAgentRequest\x1a\x1b.easyanylink.v2.AgentDetail\x12k\n" +
+	"\x12ListSessionHistory\x12).easyanylink.v2.ListSessionHistoryRequest\x1a*.easyanylink.v2.ListSessionHistoryResponse\x12P\n" +
+	"\fApproveAgent\x12#.easyanylink.v2.ApproveAgentRequest\x1a\x1b.easyanylink.v2.AgentDetail\x12V\n" +
+	"\vRejectAgent\x12\".easyanylink.v2.RejectAgentRequest\x1a#.easyanylink.v2.RejectAgentResponse\x12\\\n" +
+	"\x12ResetAgentIdentity\x12).easyanylink.v2.ResetAgentIdentityRequest\x1a\x1b.easyanylink.v2.AgentDetail\x12Y\n" +
+	"\fListACLRules\x12#.easyanylink.v2.ListACLRulesRequest\x1a$.easyanylink.v2.ListACLRulesResponse\x12H\n" +
+	"\n" +
+	"AddACLRule\x12!.easyanylink.v2.AddACLRuleRequest\x1a\x17.easyanylink.v2.ACLRule\x12N\n" +
+	"\rUpdateACLRule\x12$.easyanylink.v2.UpdateACLRuleRequest\x1a\x17.easyanylink.v2.ACLRule\x12\\\n" +
+	"\rDeleteACLRule\x12$.easyanylink.v2.DeleteACLRuleRequest\x1a%.easyanylink.v2.DeleteACLRuleResponse\x12_\n" +
+	"\x0fGetCryptoPolicy\x12&.easyanylink.v2.GetCryptoPolicyRequest\x1a$.easyanylink.v2.CryptoPolicyResponse\x12h\n" +
+	"\x12GetRelayQueueStats\x12).easyanylink.v2.GetRelayQueueStatsRequest\x1a'.easyanylink.v2.RelayQueueStatsResponse\x12V\n" +
+	"\fGetKeepalive\x12#.easyanylink.v2.GetKeepaliveRequest\x1a!.easyanylink.v2.KeepaliveResponse\x12V\n" +
+	"\fSetKeepalive\x12#.easyanylink.v2.SetKeepaliveRequest\x1a!.easyanylink.v2.KeepaliveResponse\x12b\n" +
+	"\x0fListAgentGroups\x12&.easyanylink.v2.ListAgentGroupsRequest\x1a'.easyanylink.v2.ListAgentGroupsResponse\x12J\n" +
+	"\x10CreateAgentGroup\x12\x1a.easyanylink.v2.AgentGroup\x1a\x1a.easyanylink.v2.AgentGroup\x12J\n" +
+	"\x10UpdateAgentGroup\x12\x1a.easyanylink.v2.AgentGroup\x1a\x1a.easyanylink.v2.AgentGroup\x12e\n" +
+	"\x10DeleteAgentGroup\x12'.easyanylink.v2.DeleteAgentGroupRequest\x1a(.easyanylink.v2.DeleteAgentGroupResponse\x12R\n" +
+	"\rSetAgentGroup\x12$.easyanylink.v2.SetAgentGroupRequest\x1a\x1b.easyanylink.v2.AgentDetail\x12L\n" +
+	"\fStageRollout\x12#.easyanylink.v2.StageRolloutRequest\x1a\x17.easyanylink.v2.Rollout\x12Y\n" +
+	"\fListRollouts\x12#.easyanylink.v2.ListRolloutsRequest\x1a$.easyanylink.v2.ListRolloutsResponse\x12H\n" +
+	"\n" +
+	"GetRollout\x12!.easyanylink.v2.GetRolloutRequest\x1a\x17.easyanylink.v2.Rollout\x12P\n" +
+	"\x0ePromoteRollout\x12%.easyanylink.v2.PromoteRolloutRequest\x1a\x17.easyanylink.v2.Rollout\x12R\n" +
+	"\x0fRollBackRollout\x12&.easyanylink.v2.RollBackRolloutRequest\x1a\x17.easyanylink.v2.Rollout\x12_\n" +
+	"\x0eSimulatePolicy\x12%.easyanylink.v2.SimulatePolicyRequest\x1a&.easyanylink.v2.SimulatePolicyResponse\x12]\n" +
+	"\x11GetFaultInjection\x12(.easyanylink.v2.GetFaultInjectionRequest\x1a\x1e.easyanylink.v2.FaultInjection\x12S\n" +
+	"\x11SetFaultInjection\x12\x1e.easyanylink.v2.FaultInjection\x1a\x1e.easyanylink.v2.FaultInjection\x12k\n" +
+	"\x12ListAccessRequests\x12).easyanylink.v2.ListAccessRequestsRequest\x1a*.easyanylink.v2.ListAccessRequestsResponse\x12b\n" +
+	"\x14ApproveAccessRequest\x12+.easyanylink.v2.ApproveAccessRequestRequest\x1a\x1d.easyanylink.v2.AccessRequest\x12\\\n" +
+	"\x11DenyAccessRequest\x12(.easyanylink.v2.DenyAccessRequestRequest\x1a\x1d.easyanylink.v2.AccessRequest\x12Y\n" +
+	"\fListAuditLog\x12#.easyanylink.v2.ListAuditLogRequest\x1a$.easyanylink.v2.ListAuditLogResponse\x12b\n" +
+	"\x0fHandOffSessions\x12&.easyanylink.v2.HandOffSessionsRequest\x1a'.easyanylink.v2.HandOffSessionsResponseBIZGgithub.com/taills/EasyAnyLink/common/proto/easyanylink/v2;easyanylinkv2b\x06proto3"
+
+var (
+	file_common_proto_easyanylink_v2_admin_proto_rawDescOnce sync.Once
+	file_common_proto_easyanylink_v2_admin_proto_rawDescData []byte
+)
+
+func file_common_proto_easyanylink_v2_admin_proto_rawDescGZIP() []byte {
+	file_common_proto_easyanylink_v2_admin_proto_rawDescOnce.Do(func() {
+		file_common_proto_easyanylink_v2_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_common_proto_easyanylink_v2_admin_proto_rawDesc), len(file_common_proto_easyanylink_v2_admin_proto_rawDesc)))
+	})
+	return file_common_proto_easyanylink_v2_admin_proto_rawDescData
+}
+
+var file_common_proto_easyanylink_v2_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 93)
+var file_common_proto_easyanylink_v2_admin_proto_goTypes = []any{
+	(*AddRoutingRuleRequest)(nil),       // 0: easyanylink.v2.AddRoutingRuleRequest
+	(*UpdateRoutingRuleRequest)(nil),    // 1: easyanylink.v2.UpdateRoutingRuleRequest
+	(*RoutingRuleResponse)(nil),         // 2: easyanylink.v2.RoutingRuleResponse
+	(*DeleteRoutingRuleRequest)(nil),    // 3: easyanylink.v2.DeleteRoutingRuleRequest
+	(*DeleteRoutingRuleResponse)(nil),   // 4: easyanylink.v2.DeleteRoutingRuleResponse
+	(*ListAgentsRequest)(nil),           // 5: easyanylink.v2.ListAgentsRequest
+	(*ListAgentsResponse)(nil),          // 6: easyanylink.v2.ListAgentsResponse
+	(*GetAgentRequest)(nil),             // 7: easyanylink.v2.GetAgentRequest
+	(*AgentDetail)(nil),                 // 8: easyanylink.v2.AgentDetail
+	(*AgentIdentity)(nil),               // 9: easyanylink.v2.AgentIdentity
+	(*SessionResources)(nil),            // 10: easyanylink.v2.SessionResources
+	(*FlowControl)(nil),                 // 11: easyanylink.v2.FlowControl
+	(*ListRoutingRulesRequest)(nil),     // 12: easyanylink.v2.ListRoutingRulesRequest
+	(*ListRoutingRulesResponse)(nil),    // 13: easyanylink.v2.ListRoutingRulesResponse
+	(*CreateUserRequest)(nil),           // 14: easyanylink.v2.CreateUserRequest
+	(*RotateAPIKeyRequest)(nil),         // 15: easyanylink.v2.RotateAPIKeyRequest
+	(*UserResponse)(nil),                // 16: easyanylink.v2.UserResponse
+	(*ListUsersRequest)(nil),            // 17: easyanylink.v2.ListUsersRequest
+	(*ListUsersResponse)(nil),           // 18: easyanylink.v2.ListUsersResponse
+	(*GetUserRequest)(nil),              // 19: easyanylink.v2.GetUserRequest
+	(*UpdateUserRequest)(nil),           // 20: easyanylink.v2.UpdateUserRequest
+	(*DeleteUserRequest)(nil),           // 21: easyanylink.v2.DeleteUserRequest
+	(*DeleteUserResponse)(nil),          // 22: easyanylink.v2.DeleteUserResponse
+	(*UserDetail)(nil),                  // 23: easyanylink.v2.UserDetail
+	(*UserUsage)(nil),                   // 24: easyanylink.v2.UserUsage
+	(*ExportUsageRequest)(nil),          // 25: easyanylink.v2.ExportUsageRequest
+	(*ExportUsageResponse)(nil),         // 26: easyanylink.v2.ExportUsageResponse
+	(*ListTrafficRollupsRequest)(nil),   // 27: easyanylink.v2.ListTrafficRollupsRequest
+	(*ListTrafficRollupsResponse)(nil),  // 28: easyanylink.v2.ListTrafficRollupsResponse
+	(*TrafficRollup)(nil),               // 29: easyanylink.v2.TrafficRollup
+	(*ExportInventoryRequest)(nil),      // 30: easyanylink.v2.ExportInventoryRequest
+	(*ExportInventoryResponse)(nil),     // 31: easyanylink.v2.ExportInventoryResponse
+	(*SetRelayTracingRequest)(nil),      // 32: easyanylink.v2.SetRelayTracingRequest
+	(*RelayTracingResponse)(nil),        // 33: easyanylink.v2.RelayTracingResponse
+	(*ListRelayTracesRequest)(nil),      // 34: easyanylink.v2.ListRelayTracesRequest
+	(*ListRelayTracesResponse)(nil),     // 35: easyanylink.v2.ListRelayTracesResponse
+	(*RelayTrace)(nil),                  // 36: easyanylink.v2.RelayTrace
+	(*GetHandshakeStatsRequest)(nil),    // 37: easyanylink.v2.GetHandshakeStatsRequest
+	(*HandshakeStatsResponse)(nil),      // 38: easyanylink.v2.HandshakeStatsResponse
+	(*GetCryptoPolicyRequest)(nil),      // 39: easyanylink.v2.GetCryptoPolicyRequest
+	(*CryptoPolicyResponse)(nil),        // 40: easyanylink.v2.CryptoPolicyResponse
+	(*GetRelayQueueStatsRequest)(nil),   // 41: easyanylink.v2.GetRelayQueueStatsRequest
+	(*RelayQueueStatsResponse)(nil),     // 42: easyanylink.v2.RelayQueueStatsResponse
+	(*SlowConsumer)(nil),                // 43: easyanylink.v2.SlowConsumer
+	(*MulticastStats)(nil),              // 44: easyanylink.v2.MulticastStats
+	(*ArchiveAgentRequest)(nil),         // 45: easyanylink.v2.ArchiveAgentRequest
+	(*RestoreAgentRequest)(nil),         // 46: easyanylink.v2.RestoreAgentRequest
+	(*ListSessionHistoryRequest)(nil),   // 47: easyanylink.v2.ListSessionHistoryRequest
+	(*ListSessionHistoryResponse)(nil),  // 48: easyanylink.v2.ListSessionHistoryResponse
+	(*SessionRecord)(nil),               // 49: easyanylink.v2.SessionRecord
+	(*ApproveAgentRequest)(nil),         // 50: easyanylink.v2.ApproveAgentRequest
+	(*RejectAgentRequest)(nil),          // 51: easyanylink.v2.RejectAgentRequest
+	(*ResetAgentIdentityRequest)(nil),   // 52: easyanylink.v2.ResetAgentIdentityRequest
+	(*RejectAgentResponse)(nil),         // 53: easyanylink.v2.RejectAgentResponse
+	(*ACLRule)(nil),                     // 54: easyanylink.v2.ACLRule
+	(*ListACLRulesRequest)(nil),         // 55: easyanylink.v2.ListACLRulesRequest
+	(*ListACLRulesResponse)(nil),        // 56: easyanylink.v2.ListACLRulesResponse
+	(*AddACLRuleRequest)(nil),           // 57: easyanylink.v2.AddACLRuleRequest
+	(*UpdateACLRuleRequest)(nil),        // 58: easyanylink.v2.UpdateACLRuleRequest
+	(*DeleteACLRuleRequest)(nil),        // 59: easyanylink.v2.DeleteACLRuleRequest
+	(*DeleteACLRuleResponse)(nil),       // 60: easyanylink.v2.DeleteACLRuleResponse
+	(*GetKeepaliveRequest)(nil),         // 61: easyanylink.v2.GetKeepaliveRequest
+	(*SetKeepaliveRequest)(nil),         // 62: easyanylink.v2.SetKeepaliveRequest
+	(*KeepaliveResponse)(nil),           // 63: easyanylink.v2.KeepaliveResponse
+	(*AgentGroup)(nil),                  // 64: easyanylink.v2.AgentGroup
+	(*ListAgentGroupsRequest)(nil),      // 65: easyanylink.v2.ListAgentGroupsRequest
+	(*ListAgentGroupsResponse)(nil),     // 66: easyanylink.v2.ListAgentGroupsResponse
+	(*DeleteAgentGroupRequest)(nil),     // 67: easyanylink.v2.DeleteAgentGroupRequest
+	(*DeleteAgentGroupResponse)(nil),    // 68: easyanylink.v2.DeleteAgentGroupResponse
+	(*SetAgentGroupRequest)(nil),        // 69: easyanylink.v2.SetAgentGroupRequest
+	(*StageRolloutRequest)(nil),         // 70: easyanylink.v2.StageRolloutRequest
+	(*Rollout)(nil),                     // 71: easyanylink.v2.Rollout
+	(*RolloutCohort)(nil),               // 72: easyanylink.v2.RolloutCohort
+	(*ListRolloutsRequest)(nil),         // 73: easyanylink.v2.ListRolloutsRequest
+	(*ListRolloutsResponse)(nil),        // 74: easyanylink.v2.ListRolloutsResponse
+	(*GetRolloutRequest)(nil),           // 75: easyanylink.v2.GetRolloutRequest
+	(*PromoteRolloutRequest)(nil),       // 76: easyanylink.v2.PromoteRolloutRequest
+	(*RollBackRolloutRequest)(nil),      // 77: easyanylink.v2.RollBackRolloutRequest
+	(*SimulatePolicyRequest)(nil),       // 78: easyanylink.v2.SimulatePolicyRequest
+	(*SimulatePolicyResponse)(nil),      // 79: easyanylink.v2.SimulatePolicyResponse
+	(*SimulatedFlow)(nil),               // 80: easyanylink.v2.SimulatedFlow
+	(*GetFaultInjectionRequest)(nil),    // 81: easyanylink.v2.GetFaultInjectionRequest
+	(*FaultInjection)(nil),              // 82: easyanylink.v2.FaultInjection
+	(*ListAccessRequestsRequest)(nil),   // 83: easyanylink.v2.ListAccessRequestsRequest
+	(*ListAccessRequestsResponse)(nil),  // 84: easyanylink.v2.ListAccessRequestsResponse
+	(*ApproveAccessRequestRequest)(nil), // 85: easyanylink.v2.ApproveAccessRequestRequest
+	(*DenyAccessRequestRequest)(nil),    // 86: easyanylink.v2.DenyAccessRequestRequest
+	(*ListAuditLogRequest)(nil),         // 87: easyanylink.v2.ListAuditLogRequest
+	(*AuditEntry)(nil),                  // 88: easyanylink.v2.AuditEntry
+	(*ListAuditLogResponse)(nil),        // 89: easyanylink.v2.ListAuditLogResponse
+	(*HandOffSessionsRequest)(nil),      // 90: easyanylink.v2.HandOffSessionsRequest
+	(*HandOffSessionsResponse)(nil),     // 91: easyanylink.v2.HandOffSessionsResponse
+	nil,                                 // 92: easyanylink.v2.ListAgentsRequest.LabelsEntry
+	(*RoutingRule)(nil),                 // 93: easyanylink.v2.RoutingRule
+	(AgentType)(0),                      // 94: easyanylink.v2.AgentType
+	(AgentStatus)(0),                    // 95: easyanylink.v2.AgentStatus
+	(*AgentMetadata)(nil),               // 96: easyanylink.v2.AgentMetadata
+	(*AgentStats)(nil),                  // 97: easyanylink.v2.AgentStats
+	(*timestamppb.Timestamp)(nil),       // 98: google.protobuf.Timestamp
+	(*AgentHealth)(nil),                 // 99: easyanylink.v2.AgentHealth
+	(*TrafficClassStats)(nil),           // 100: easyanylink.v2.TrafficClassStats
+	(DisconnectReason)(0),               // 101: easyanylink.v2.DisconnectReason
+	(*AccessWindow)(nil),                // 102: easyanylink.v2.AccessWindow
+	(*AccessRequest)(nil),               // 103: easyanylink.v2.AccessRequest
+}
+var file_common_proto_easyanylink_v2_admin_proto_depIdxs = []int32{
+	93,  // 0: easyanylink.v2.AddRoutingRuleRequest.rule:type_name -> easyanylink.v2.RoutingRule
+	93,  // 1: easyanylink.v2.UpdateRoutingRuleRequest.rule:type_name -> easyanylink.v2.RoutingRule
+	93,  // 2: easyanylink.v2.RoutingRuleResponse.rule:type_name -> easyanylink.v2.RoutingRule
+	94,  // 3: easyanylink.v2.ListAgentsRequest.type:type_name -> easyanylink.v2.AgentType
+	95,  // 4: easyanylink.v2.ListAgentsRequest.status:type_name -> easyanylink.v2.AgentStatus
+	92,  // 5: easyanylink.v2.ListAgentsRequest.labels:type_name -> easyanylink.v2.ListAgentsRequest.LabelsEntry
+	8,   // 6: easyanylink.v2.ListAgentsResponse.agents:type_name -> easyanylink.v2.AgentDetail
+	94,  // 7: easyanylink.v2.AgentDetail.type:type_name -> easyanylink.v2.AgentType
+	95,  // 8: easyanylink.v2.AgentDetail.status:type_name -> easyanylink.v2.AgentStatus
+	96,  // 9: easyanylink.v2.AgentDetail.metadata:type_name -> easyanylink.v2.AgentMetadata
+	97,  // 10: easyanylink.v2.AgentDetail.stats:type_name -> easyanylink.v2.AgentStats
+	98,  // 11: easyanylink.v2.AgentDetail.last_seen:type_name -> google.protobuf.Timestamp
+	98,  // 12: easyanylink.v2.AgentDetail.created_at:type_name -> google.protobuf.Timestamp
+	98,  // 13: easyanylink.v2.AgentDetail.archived_at:type_name -> google.protobuf.Timestamp
+	99,  // 14: easyanylink.v2.AgentDetail.health:type_name -> easyanylink.v2.AgentHealth
+	11,  // 15: easyanylink.v2.AgentDetail.flow_control:type_name -> easyanylink.v2.FlowControl
+	10,  // 16: easyanylink.v2.AgentDetail.resources:type_name -> easyanylink.v2.SessionResources
+	9,   // 17: easyanylink.v2.AgentDetail.identity:type_name -> easyanylink.v2.AgentIdentity
+	93,  // 18: easyanylink.v2.ListRoutingRulesResponse.rules:type_name -> easyanylink.v2.RoutingRule
+	23,  // 19: easyanylink.v2.ListUsersResponse.users:type_name -> easyanylink.v2.UserDetail
+	24,  // 20: easyanylink.v2.UserDetail.usage:type_name -> easyanylink.v2.UserUsage
+	98,  // 21: easyanylink.v2.UserDetail.created_at:type_name -> google.protobuf.Timestamp
+	98,  // 22: easyanylink.v2.ListTrafficRollupsRequest.since:type_name -> google.protobuf.Timestamp
+	29,  // 23: easyanylink.v2.ListTrafficRollupsResponse.rollups:type_name -> easyanylink.v2.TrafficRollup
+	98,  // 24: easyanylink.v2.TrafficRollup.period_start:type_name -> google.protobuf.Timestamp
+	36,  // 25: easyanylink.v2.ListRelayTracesResponse.traces:type_name -> easyanylink.v2.RelayTrace
+	98,  // 26: easyanylink.v2.RelayTrace.time:type_name -> google.protobuf.Timestamp
+	100, // 27: easyanylink.v2.RelayQueueStatsResponse.classes:type_name -> easyanylink.v2.TrafficClassStats
+	44,  // 28: easyanylink.v2.RelayQueueStatsResponse.multicast:type_name -> easyanylink.v2.MulticastStats
+	43,  // 29: easyanylink.v2.RelayQueueStatsResponse.slow_consumers:type_name -> easyanylink.v2.SlowConsumer
+	11,  // 30: easyanylink.v2.SlowConsumer.flow_control:type_name -> easyanylink.v2.FlowControl
+	49,  // 31: easyanylink.v2.ListSessionHistoryResponse.sessions:type_name -> easyanylink.v2.SessionRecord
+	98,  // 32: easyanylink.v2.SessionRecord.connected_at:type_name -> google.protobuf.Timestamp
+	98,  // 33: easyanylink.v2.SessionRecord.disconnected_at:type_name -> google.protobuf.Timestamp
+	101, // 34: easyanylink.v2.SessionRecord.reason_code:type_name -> easyanylink.v2.DisconnectReason
+	102, // 35: easyanylink.v2.ACLRule.window:type_name -> easyanylink.v2.AccessWindow
+	54,  // 36: easyanylink.v2.ListACLRulesResponse.rules:type_name -> easyanylink.v2.ACLRule
+	54,  // 37: easyanylink.v2.AddACLRuleRequest.rule:type_name -> easyanylink.v2.ACLRule
+	54,  // 38: easyanylink.v2.UpdateACLRuleRequest.rule:type_name -> easyanylink.v2.ACLRule
+	64,  // 39: easyanylink.v2.ListAgentGroupsResponse.groups:type_name -> easyanylink.v2.AgentGroup
+	93,  // 40: easyanylink.v2.StageRolloutRequest.routing_rule:type_name -> easyanylink.v2.RoutingRule
+	54,  // 41: easyanylink.v2.StageRolloutRequest.acl_rule:type_name -> easyanylink.v2.ACLRule
+	2,   // 42: easyanylink.v2.Rollout.routing_rule:type_name -> easyanylink.v2.RoutingRuleResponse
+	54,  // 43: easyanylink.v2.Rollout.acl_rule:type_name -> easyanylink.v2.ACLRule
+	98,  // 44: easyanylink.v2.Rollout.created_at:type_name -> google.protobuf.Timestamp
+	98,  // 45: easyanylink.v2.Rollout.updated_at:type_name -> google.protobuf.Timestamp
+	72,  // 46: easyanylink.v2.Rollout.canary:type_name -> easyanylink.v2.RolloutCohort
+	72,  // 47: easyanylink.v2.Rollout.baseline:type_name -> easyanylink.v2.RolloutCohort
+	71,  // 48: easyanylink.v2.ListRolloutsResponse.rollouts:type_name -> easyanylink.v2.Rollout
+	93,  // 49: easyanylink.v2.SimulatePolicyRequest.routing_rule:type_name -> easyanylink.v2.RoutingRule
+	54,  // 50: easyanylink.v2.SimulatePolicyRequest.acl_rule:type_name -> easyanylink.v2.ACLRule
+	80,  // 51: easyanylink.v2.SimulatePolicyResponse.flows:type_name -> easyanylink.v2.SimulatedFlow
+	98,  // 52: easyanylink.v2.SimulatedFlow.last_seen:type_name -> google.protobuf.Timestamp
+	103, // 53: easyanylink.v2.ListAccessRequestsResponse.requests:type_name -> easyanylink.v2.AccessRequest
+	98,  // 54: easyanylink.v2.ListAuditLogRequest.since:type_name -> google.protobuf.Timestamp
+	98,  // 55: easyanylink.v2.AuditEntry.time:type_name -> google.protobuf.Timestamp
+	88,  // 56: easyanylink.v2.ListAuditLogResponse.entries:type_name -> easyanylink.v2.AuditEntry
+	0,   // 57: easyanylink.v2.AdminService.AddRoutingRule:input_type -> easyanylink.v2.AddRoutingRuleRequest
+	1,   // 58: easyanylink.v2.AdminService.UpdateRoutingRule:input_type -> easyanylink.v2.UpdateRoutingRuleRequest
+	3,   // 59: easyanylink.v2.AdminService.DeleteRoutingRule:input_type -> easyanylink.v2.DeleteRoutingRuleRequest
+	5,   // 60: easyanylink.v2.AdminService.ListAgents:input_type -> easyanylink.v2.ListAgentsRequest
+	7,   // 61: easyanylink.v2.AdminService.GetAgent:input_type -> easyanylink.v2.GetAgentRequest
+	12,  // 62: easyanylink.v2.AdminService.ListRoutingRules:input_type -> easyanylink.v2.ListRoutingRulesRequest
+	14,  // 63: easyanylink.v2.AdminService.CreateUser:input_type -> easyanylink.v2.CreateUserRequest
+	15,  // 64: easyanylink.v2.AdminService.RotateAPIKey:input_type -> easyanylink.v2.RotateAPIKeyRequest
+	17,  // 65: easyanylink.v2.AdminService.ListUsers:input_type -> easyanylink.v2.ListUsersRequest
+	19,  // 66: easyanylink.v2.AdminService.GetUser:input_type -> easyanylink.v2.GetUserRequest
+	20,  // 67: easyanylink.v2.AdminService.UpdateUser:input_type -> easyanylink.v2.UpdateUserRequest
+	21,  // 68: easyanylink.v2.AdminService.DeleteUser:input_type -> easyanylink.v2.DeleteUserRequest
+	25,  // 69: easyanylink.v2.AdminService.ExportUsage:input_type -> easyanylink.v2.ExportUsageRequest
+	27,  // 70: easyanylink.v2.AdminService.ListTrafficRollups:input_type -> easyanylink.v2.ListTrafficRollupsRequest
+	30,  // 71: easyanylink.v2.AdminService.ExportInventory:input_type -> easyanylink.v2.ExportInventoryRequest
+	32,  // 72: easyanylink.v2.AdminService.SetRelayTracing:input_type -> easyanylink.v2.SetRelayTracingRequest
+	34,  // 73: easyanylink.v2.AdminService.ListRelayTraces:input_type -> easyanylink.v2.ListRelayTracesRequest
+	37,  // 74: easyanylink.v2.AdminService.GetHandshakeStats:input_type -> easyanylink.v2.GetHandshakeStatsRequest
+	45,  // 75: easyanylink.v2.AdminService.ArchiveAgent:input_type -> easyanylink.v2.ArchiveAgentRequest
+	46,  // 76: easyanylink.v2.AdminService.RestoreAgent:input_type -> easyanylink.v2.RestoreAgentRequest
+	47,  // 77: easyanylink.v2.AdminService.ListSessionHistory:input_type -> easyanylink.v2.ListSessionHistoryRequest
+	50,  // 78: easyanylink.v2.AdminService.ApproveAgent:input_type -> easyanylink.v2.ApproveAgentRequest
+	51,  // 79: easyanylink.v2.AdminService.RejectAgent:input_type -> easyanylink.v2.RejectAgentRequest
+	52,  // 80: easyanylink.v2.AdminService.ResetAgentIdentity:input_type -> easyanylink.v2.ResetAgentIdentityRequest
+	55,  // 81: easyanylink.v2.AdminService.ListACLRules:input_type -> easyanylink.v2.ListACLRulesRequest
+	57,  // 82: easyanylink.v2.AdminService.AddACLRule:input_type -> easyanylink.v2.AddACLRuleRequest
+	58,  // 83: easyanylink.v2.AdminService.UpdateACLRule:input_type -> easyanylink.v2.UpdateACLRuleRequest
+	59,  // 84: easyanylink.v2.AdminService.DeleteACLRule:input_type -> easyanylink.v2.DeleteACLRuleRequest
+	39,  // 85: easyanylink.v2.AdminService.GetCryptoPolicy:input_type -> easyanylink.v2.GetCryptoPolicyRequest
+	41,  // 86: easyanylink.v2.AdminService.GetRelayQueueStats:input_type -> easyanylink.v2.GetRelayQueueStatsRequest
+	61,  // 87: easyanylink.v2.AdminService.GetKeepalive:input_type -> easyanylink.v2.GetKeepaliveRequest
+	62,  // 88: easyanylink.v2.AdminService.SetKeepalive:input_type -> easyanylink.v2.SetKeepaliveRequest
+	65,  // 89: easyanylink.v2.AdminService.ListAgentGroups:input_type -> easyanylink.v2.ListAgentGroupsRequest
+	64,  // 90: easyanylink.v2.AdminService.CreateAgentGroup:input_type -> easyanylink.v2.AgentGroup
+	64,  // 91: easyanylink.v2.AdminService.UpdateAgentGroup:input_type -> easyanylink.v2.AgentGroup
+	67,  // 92: easyanylink.v2.AdminService.DeleteAgentGroup:input_type -> easyanylink.v2.DeleteAgentGroupRequest
+	69,  // 93: easyanylink.v2.AdminService.SetAgentGroup:input_type -> easyanylink.v2.SetAgentGroupRequest
+	70,  // 94: easyanylink.v2.AdminService.StageRollout:input_type -> easyanylink.v2.StageRolloutRequest
+	73,  // 95: easyanylink.v2.AdminService.ListRollouts:input_type -> easyanylink.v2.ListRolloutsRequest
+	75,  // 96: easyanylink.v2.AdminService.GetRollout:input_type -> easyanylink.v2.GetRolloutRequest
+	76,  // 97: easyanylink.v2.AdminService.PromoteRollout:input_type -> easyanylink.v2.PromoteRolloutRequest
+	77,  // 98: easyanylink.v2.AdminService.RollBackRollout:input_type -> easyanylink.v2.RollBackRolloutRequest
+	78,  // 99: easyanylink.v2.AdminService.SimulatePolicy:input_type -> easyanylink.v2.SimulatePolicyRequest
+	81,  // 100: easyanylink.v2.AdminService.GetFaultInjection:input_type -> easyanylink.v2.GetFaultInjectionRequest
+	82,  // 101: easyanylink.v2.AdminService.SetFaultInjection:input_type -> easyanylink.v2.FaultInjection
+	83,  // 102: easyanylink.v2.AdminService.ListAccessRequests:input_type -> easyanylink.v2.ListAccessRequestsRequest
+	85,  // 103: easyanylink.v2.AdminService.ApproveAccessRequest:input_type -> easyanylink.v2.ApproveAccessRequestRequest
+	86,  // 104: easyanylink.v2.AdminService.DenyAccessRequest:input_type -> easyanylink.v2.DenyAccessRequestRequest
+	87,  // 105: easyanylink.v2.AdminService.ListAuditLog:input_type -> easyanylink.v2.ListAuditLogRequest
+	90,  // 106: easyanylink.v2.AdminService.HandOffSessions:input_type -> easyanylink.v2.HandOffSessionsRequest
+	2,   // 107: easyanylink.v2.AdminService.AddRoutingRule:output_type -> easyanylink.v2.RoutingRuleResponse
+	2,   // 108: easyanylink.v2.AdminService.UpdateRoutingRule:output_type -> easyanylink.v2.RoutingRuleResponse
+	4,   // 109: easyanylink.v2.AdminService.DeleteRoutingRule:output_type -> easyanylink.v2.DeleteRoutingRuleResponse
+	6,   // 110: easyanylink.v2.AdminService.ListAgents:output_type -> easyanylink.v2.ListAgentsResponse
+	8,   // 111: easyanylink.v2.AdminService.GetAgent:output_type -> easyanylink.v2.AgentDetail
+	13,  // 112: easyanylink.v2.AdminService.ListRoutingRules:output_type -> easyanylink.v2.ListRoutingRulesResponse
+	16,  // 113: easyanylink.v2.AdminService.CreateUser:output_type -> easyanylink.v2.UserResponse
+	16,  // 114: easyanylink.v2.AdminService.RotateAPIKey:output_type -> easyanylink.v2.UserResponse
+	18,  // 115: easyanylink.v2.AdminService.ListUsers:output_type -> easyanylink.v2.ListUsersResponse
+	23,  // 116: easyanylink.v2.AdminService.GetUser:output_type -> easyanylink.v2.UserDetail
+	23,  // 117: easyanylink.v2.AdminService.UpdateUser:output_type -> easyanylink.v2.UserDetail
+	22,  // 118: easyanylink.v2.AdminService.DeleteUser:output_type -> easyanylink.v2.DeleteUserResponse
+	26,  // 119: easyanylink.v2.AdminService.ExportUsage:output_type -> easyanylink.v2.ExportUsageResponse
+	28,  // 120: easyanylink.v2.AdminService.ListTrafficRollups:output_type -> easyanylink.v2.ListTrafficRollupsResponse
+	31,  // 121: easyanylink.v2.AdminService.ExportInventory:output_type -> easyanylink.v2.ExportInventoryResponse
+	33,  // 122: easyanylink.v2.AdminService.SetRelayTracing:output_type -> easyanylink.v2.RelayTracingResponse
+	35,  // 123: easyanylink.v2.AdminService.ListRelayTraces:output_type -> easyanylink.v2.ListRelayTracesResponse
+	38,  // 124: easyanylink.v2.AdminService.GetHandshakeStats:output_type -> easyanylink.v2.HandshakeStatsResponse
+	8,   // 125: easyanylink.v2.AdminService.ArchiveAgent:output_type -> easyanylink.v2.AgentDetail
+	8,   // 126: easyanylink.v2.AdminService.RestoreAgent:output_type -> easyanylink.v2.AgentDetail
+	48,  // 127: easyanylink.v2.AdminService.ListSessionHistory:output_type -> easyanylink.v2.ListSessionHistoryResponse
+	8,   // 128: easyanylink.v2.AdminService.ApproveAgent:output_type -> easyanylink.v2.AgentDetail
+	53,  // 129: easyanylink.v2.AdminService.RejectAgent:output_type -> easyanylink.v2.RejectAgentResponse
+	8,   // 130: easyanylink.v2.AdminService.ResetAgentIdentity:output_type -> easyanylink.v2.AgentDetail
+	56,  // 131: easyanylink.v2.AdminService.ListACLRules:output_type -> easyanylink.v2.ListACLRulesResponse
+	54,  // 132: easyanylink.v2.AdminService.AddACLRule:output_type -> easyanylink.v2.ACLRule
+	54,  // 133: easyanylink.v2.AdminService.UpdateACLRule:output_type -> easyanylink.v2.ACLRule
+	60,  // 134: easyanylink.v2.AdminService.DeleteACLRule:output_type -> easyanylink.v2.DeleteACLRuleResponse
+	40,  // 135: easyanylink.v2.AdminService.GetCryptoPolicy:output_type -> easyanylink.v2.CryptoPolicyResponse
+	42,  // 136: easyanylink.v2.AdminService.GetRelayQueueStats:output_type -> easyanylink.v2.RelayQueueStatsResponse
+	63,  // 137: easyanylink.v2.AdminService.GetKeepalive:output_type -> easyanylink.v2.KeepaliveResponse
+	63,  // 138: easyanylink.v2.AdminService.SetKeepalive:output_type -> easyanylink.v2.KeepaliveResponse
+	66,  // 139: easyanylink.v2.AdminService.ListAgentGroups:output_type -> easyanylink.v2.ListAgentGroupsResponse
+	64,  // 140: easyanylink.v2.AdminService.CreateAgentGroup:output_type -> easyanylink.v2.AgentGroup
+	64,  // 141: easyanylink.v2.AdminService.UpdateAgentGroup:output_type -> easyanylink.v2.AgentGroup
+	68,  // 142: easyanylink.v2.AdminService.DeleteAgentGroup:output_type -> easyanylink.v2.DeleteAgentGroupResponse
+	8,   // 143: easyanylink.v2.AdminService.SetAgentGroup:output_type -> easyanylink.v2.AgentDetail
+	71,  // 144: easyanylink.v2.AdminService.StageRollout:output_type -> easyanylink.v2.Rollout
+	74,  // 145: easyanylink.v2.AdminService.ListRollouts:output_type -> easyanylink.v2.ListRolloutsResponse
+	71,  // 146: easyanylink.v2.AdminService.GetRollout:output_type -> easyanylink.v2.Rollout
+	71,  // 147: easyanylink.v2.AdminService.PromoteRollout:output_type -> easyanylink.v2.Rollout
+	71,  // 148: easyanylink.v2.AdminService.RollBackRollout:output_type -> easyanylink.v2.Rollout
+	79,  // 149: easyanylink.v2.AdminService.SimulatePolicy:output_type -> easyanylink.v2.SimulatePolicyResponse
+	82,  // 150: easyanylink.v2.AdminService.GetFaultInjection:output_type -> easyanylink.v2.FaultInjection
+	82,  // 151: easyanylink.v2.AdminService.SetFaultInjection:output_type -> easyanylink.v2.FaultInjection
+	84,  // 152: easyanylink.v2.AdminService.ListAccessRequests:output_type -> easyanylink.v2.ListAccessRequestsResponse
+	103, // 153: easyanylink.v2.AdminService.ApproveAccessRequest:output_type -> easyanylink.v2.AccessRequest
+	103, // 154: easyanylink.v2.AdminService.DenyAccessRequest:output_type -> easyanylink.v2.AccessRequest
+	89,  // 155: easyanylink.v2.AdminService.ListAuditLog:output_type -> easyanylink.v2.ListAuditLogResponse
+	91,  // 156: easyanylink.v2.AdminService.HandOffSessions:output_type -> easyanylink.v2.HandOffSessionsResponse
+	107, // [107:157] is the sub-list for method output_type
+	57,  // [57:107] is the sub-list for method input_type
+	57,  // [57:57] is the sub-list for extension type_name
+	57,  // [57:57] is the sub-list for extension extendee
+	0,   // [0:57] is the sub-list for field type_name
+}
+
+func init() { file_common_proto_easyanylink_v2_admin_proto_init() }
+func file_common_proto_easyanylink_v2_admin_proto_init() {
+	if File_common_proto_easyanylink_v2_admin_proto != nil {
+		return
+	}
+	file_common_proto_easyanylink_v2_agent_proto_init()
+	file_common_proto_easyanylink_v2_admin_proto_msgTypes[54].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_proto_easyanylink_v2_admin_proto_rawDesc), len(file_common_proto_easyanylink_v2_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   93,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_common_proto_easyanylink_v2_admin_proto_goTypes,
+		DependencyIndexes: file_common_proto_easyanylink_v2_admin_proto_depIdxs,
+		MessageInfos:      file_common_proto_easyanylink_v2_admin_proto_msgTypes,
+	}.Build()
+	File_common_proto_easyanylink_v2_admin_proto = out.File
+	file_common_proto_easyanylink_v2_admin_proto_goTypes = nil
+	file_common_proto_easyanylink_v2_admin_proto_depIdxs = nil
+}
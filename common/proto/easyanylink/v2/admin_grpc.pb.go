@@ -0,0 +1,2115 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: common/proto/easyanylink/v2/admin.proto
+
+// Version 2 of the API. It must stay wire compatible with v1, which the
+// server serves with the same handlers: add fields, never renumber them,
+// and reserve the numbers and names of removed ones.
+
+package easyanylinkv2
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_AddRoutingRule_FullMethodName       = "/easyanylink.v2.AdminService/AddRoutingRule"
+	AdminService_UpdateRoutingRule_FullMethodName    = "/easyanylink.v2.AdminService/UpdateRoutingRule"
+	AdminService_DeleteRoutingRule_FullMethodName    = "/easyanylink.v2.AdminService/DeleteRoutingRule"
+	AdminService_ListAgents_FullMethodName           = "/easyanylink.v2.AdminService/ListAgents"
+	AdminService_GetAgent_FullMethodName             = "/easyanylink.v2.AdminService/GetAgent"
+	AdminService_ListRoutingRules_FullMethodName     = "/easyanylink.v2.AdminService/ListRoutingRules"
+	AdminService_CreateUser_FullMethodName           = "/easyanylink.v2.AdminService/CreateUser"
+	AdminService_RotateAPIKey_FullMethodName         = "/easyanylink.v2.AdminService/RotateAPIKey"
+	AdminService_ListUsers_FullMethodName            = "/easyanylink.v2.AdminService/ListUsers"
+	AdminService_GetUser_FullMethodName              = "/easyanylink.v2.AdminService/GetUser"
+	AdminService_UpdateUser_FullMethodName           = "/easyanylink.v2.AdminService/UpdateUser"
+	AdminService_DeleteUser_FullMethodName           = "/easyanylink.v2.AdminService/DeleteUser"
+	AdminService_ExportUsage_FullMethodName          = "/easyanylink.v2.AdminService/ExportUsage"
+	AdminService_ListTrafficRollups_FullMethodName   = "/easyanylink.v2.AdminService/ListTrafficRollups"
+	AdminService_ExportInventory_FullMethodName      = "/easyanylink.v2.AdminService/ExportInventory"
+	AdminService_SetRelayTracing_FullMethodName      = "/easyanylink.v2.AdminService/SetRelayTracing"
+	AdminService_ListRelayTraces_FullMethodName      = "/easyanylink.v2.AdminService/ListRelayTraces"
+	AdminService_GetHandshakeStats_FullMethodName    = "/easyanylink.v2.AdminService/GetHandshakeStats"
+	AdminService_ArchiveAgent_FullMethodName         = "/easyanylink.v2.AdminService/ArchiveAgent"
+	AdminService_RestoreAgent_FullMethodName         = "/easyanylink.v2.AdminService/RestoreAgent"
+	AdminService_ListSessionHistory_FullMethodName   = "/easyanylink.v2.AdminService/ListSessionHistory"
+	AdminService_ApproveAgent_FullMethodName         = "/easyanylink.v2.AdminService/ApproveAgent"
+	AdminService_RejectAgent_FullMethodName          = "/easyanylink.v2.AdminService/RejectAgent"
+	AdminService_ResetAgentIdentity_FullMethodName   = "/easyanylink.v2.AdminService/ResetAgentIdentity"
+	AdminService_ListACLRules_FullMethodName         = "/easyanylink.v2.AdminService/ListACLRules"
+	AdminService_AddACLRule_FullMethodName           = "/easyanylink.v2.AdminService/AddACLRule"
+	AdminService_UpdateACLRule_FullMethodName        = "/easyanylink.v2.AdminService/UpdateACLRule"
+	AdminService_DeleteACLRule_FullMethodName        = "/easyanylink.v2.AdminService/DeleteACLRule"
+	AdminService_GetCryptoPolicy_FullMethodName      = "/easyanylink.v2.AdminService/GetCryptoPolicy"
+	AdminService_GetRelayQueueStats_FullMethodName   = "/easyanylink.v2.AdminService/GetRelayQueueStats"
+	AdminService_GetKeepalive_FullMethodName         = "/easyanylink.v2.AdminService/GetKeepalive"
+	AdminService_SetKeepalive_FullMethodName         = "/easyanylink.v2.AdminService/SetKeepalive"
+	AdminService_ListAgentGroups_FullMethodName      = "/easyanylink.v2.AdminService/ListAgentGroups"
+	AdminService_CreateAgentGroup_FullMethodName     = "/easyanylink.v2.AdminService/CreateAgentGroup"
+	AdminService_UpdateAgentGroup_FullMethodName     = "/easyanylink.v2.AdminService/UpdateAgentGroup"
+	AdminService_DeleteAgentGroup_FullMethodName     = "/easyanylink.v2.AdminService/DeleteAgentGroup"
+	AdminService_SetAgentGroup_FullMethodName        = "/easyanylink.v2.AdminService/SetAgentGroup"
+	AdminService_StageRollout_FullMethodName         = "/easyanylink.v2.AdminService/StageRollout"
+	AdminService_ListRollouts_FullMethodName         = "/easyanylink.v2.AdminService/ListRollouts"
+	AdminService_GetRollout_FullMethodName           = "/easyanylink.v2.AdminService/GetRollout"
+	AdminService_PromoteRollout_FullMethodName       = "/easyanylink.v2.AdminService/PromoteRollout"
+	AdminService_RollBackRollout_FullMethodName      = "/easyanylink.v2.AdminService/RollBackRollout"
+	AdminService_SimulatePolicy_FullMethodName       = "/easyanylink.v2.AdminService/SimulatePolicy"
+	AdminService_GetFaultInjection_FullMethodName    = "/easyanylink.v2.AdminService/GetFaultInjection"
+	AdminService_SetFaultInjection_FullMethodName    = "/easyanylink.v2.AdminService/SetFaultInjection"
+	AdminService_ListAccessRequests_FullMethodName   = "/easyanylink.v2.AdminService/ListAccessRequests"
+	AdminService_ApproveAccessRequest_FullMethodName = "/easyanylink.v2.AdminService/ApproveAccessRequest"
+	AdminService_DenyAccessRequest_FullMethodName    = "/easyanylink.v2.AdminService/DenyAccessRequest"
+	AdminService_ListAuditLog_FullMethodName         = "/easyanylink.v2.AdminService/ListAuditLog"
+	AdminService_HandOffSessions_FullMethodName      = "/easyanylink.v2.AdminService/HandOffSessions"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService defines the gRPC service for administrative tooling.
+// All calls must carry the API key of an admin user in the "x-api-key"
+// request metadata.
+type AdminServiceClient interface {
+	// Add a routing rule for an agent
+	AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*RoutingRuleResponse, error)
+	// Replace an existing routing rule
+	UpdateRoutingRule(ctx context.Context, in *UpdateRoutingRuleRequest, opts ...grpc.CallOption) (*RoutingRuleResponse, error)
+	// Delete a routing rule
+	DeleteRoutingRule(ctx context.Context, in *DeleteRoutingRuleRequest, opts ...grpc.CallOption) (*DeleteRoutingRuleResponse, error)
+	// List registered agents with optional filters
+	ListAgents(ctx context.Context, in *ListAgentsRequest, opts ...grpc.CallOption) (*ListAgentsResponse, error)
+	// Get a single agent
+	GetAgent(ctx context.Context, in *GetAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// List all routing rules of an agent, including disabled ones
+	ListRoutingRules(ctx context.Context, in *ListRoutingRulesRequest, opts ...grpc.CallOption) (*ListRoutingRulesResponse, error)
+	// Create a user account with a new API key
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	// Replace the API key of a user
+	RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	// List all user accounts with their quotas and current usage
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	// Get a user account with its quotas and monthly usage
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*UserDetail, error)
+	// Update the email, role, status and quotas of a user
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UserDetail, error)
+	// Delete a user together with its agents
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
+	// Export the relayed traffic of every user in a month for billing
+	ExportUsage(ctx context.Context, in *ExportUsageRequest, opts ...grpc.CallOption) (*ExportUsageResponse, error)
+	// List the hourly or daily rollups of ended sessions, newest first
+	ListTrafficRollups(ctx context.Context, in *ListTrafficRollupsRequest, opts ...grpc.CallOption) (*ListTrafficRollupsResponse, error)
+	// Export the overlay addresses of the agents as a hosts file fragment
+	// or an Ansible inventory
+	ExportInventory(ctx context.Context, in *ExportInventoryRequest, opts ...grpc.CallOption) (*ExportInventoryResponse, error)
+	// Change the relay trace sampling rate at runtime
+	SetRelayTracing(ctx context.Context, in *SetRelayTracingRequest, opts ...grpc.CallOption) (*RelayTracingResponse, error)
+	// List recently sampled relay decisions, newest first
+	ListRelayTraces(ctx context.Context, in *ListRelayTracesRequest, opts ...grpc.CallOption) (*ListRelayTracesResponse, error)
+	// Get QUIC handshake address validation counters
+	GetHandshakeStats(ctx context.Context, in *GetHandshakeStatsRequest, opts ...grpc.CallOption) (*HandshakeStatsResponse, error)
+	// Archive an agent, disconnecting it but keeping its history
+	ArchiveAgent(ctx context.Context, in *ArchiveAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// Restore an archived agent
+	RestoreAgent(ctx context.Context, in *RestoreAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// List ended sessions of an agent, newest first
+	ListSessionHistory(ctx context.Context, in *ListSessionHistoryRequest, opts ...grpc.CallOption) (*ListSessionHistoryResponse, error)
+	// Approve an agent awaiting approval, allowing it to connect
+	ApproveAgent(ctx context.Context, in *ApproveAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// Reject an agent awaiting approval, deleting it
+	RejectAgent(ctx context.Context, in *RejectAgentRequest, opts ...grpc.CallOption) (*RejectAgentResponse, error)
+	// Forget the hardware identity key an agent is bound to, its next
+	// registration binds a new one, e.g. after a TPM was cleared
+	ResetAgentIdentity(ctx context.Context, in *ResetAgentIdentityRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// List the ACL rules of a user, including disabled ones
+	ListACLRules(ctx context.Context, in *ListACLRulesRequest, opts ...grpc.CallOption) (*ListACLRulesResponse, error)
+	// Add an ACL rule filtering the traffic relayed from a user's agents
+	AddACLRule(ctx context.Context, in *AddACLRuleRequest, opts ...grpc.CallOption) (*ACLRule, error)
+	// Replace an existing ACL rule
+	UpdateACLRule(ctx context.Context, in *UpdateACLRuleRequest, opts ...grpc.CallOption) (*ACLRule, error)
+	// Delete an ACL rule
+	DeleteACLRule(ctx context.Context, in *DeleteACLRuleRequest, opts ...grpc.CallOption) (*DeleteACLRuleResponse, error)
+	// Get the crypto policy enforced by the server's TLS
+	GetCryptoPolicy(ctx context.Context, in *GetCryptoPolicyRequest, opts ...grpc.CallOption) (*CryptoPolicyResponse, error)
+	// Get the per traffic class counters of the server relay queues
+	GetRelayQueueStats(ctx context.Context, in *GetRelayQueueStatsRequest, opts ...grpc.CallOption) (*RelayQueueStatsResponse, error)
+	// Get the heartbeat settings sent to agents
+	GetKeepalive(ctx context.Context, in *GetKeepaliveRequest, opts ...grpc.CallOption) (*KeepaliveResponse, error)
+	// Change the heartbeat settings until the server restarts, connected
+	// agents apply them with their next heartbeat
+	SetKeepalive(ctx context.Context, in *SetKeepaliveRequest, opts ...grpc.CallOption) (*KeepaliveResponse, error)
+	// List agent groups
+	ListAgentGroups(ctx context.Context, in *ListAgentGroupsRequest, opts ...grpc.CallOption) (*ListAgentGroupsResponse, error)
+	// Create an agent group
+	CreateAgentGroup(ctx context.Context, in *AgentGroup, opts ...grpc.CallOption) (*AgentGroup, error)
+	// Replace the settings of an agent group, its connected agents apply
+	// them at once
+	UpdateAgentGroup(ctx context.Context, in *AgentGroup, opts ...grpc.CallOption) (*AgentGroup, error)
+	// Delete an agent group together with its routing rules
+	DeleteAgentGroup(ctx context.Context, in *DeleteAgentGroupRequest, opts ...grpc.CallOption) (*DeleteAgentGroupResponse, error)
+	// Move an agent into a group or out of its group
+	SetAgentGroup(ctx context.Context, in *SetAgentGroupRequest, opts ...grpc.CallOption) (*AgentDetail, error)
+	// Stage a routing or ACL rule change to a canary of agents. One change
+	// can be staged at a time.
+	StageRollout(ctx context.Context, in *StageRolloutRequest, opts ...grpc.CallOption) (*Rollout, error)
+	// List rollouts, newest first
+	ListRollouts(ctx context.Context, in *ListRolloutsRequest, opts ...grpc.CallOption) (*ListRolloutsResponse, error)
+	// Get a rollout with the traffic of its canary and of the other agents
+	GetRollout(ctx context.Context, in *GetRolloutRequest, opts ...grpc.CallOption) (*Rollout, error)
+	// Apply a staged change to all agents
+	PromoteRollout(ctx context.Context, in *PromoteRolloutRequest, opts ...grpc.CallOption) (*Rollout, error)
+	// Withdraw a staged change from its canary
+	RollBackRollout(ctx context.Context, in *RollBackRolloutRequest, opts ...grpc.CallOption) (*Rollout, error)
+	// Report the recently relayed flows a routing or ACL rule change would
+	// deny or reroute, without applying it
+	SimulatePolicy(ctx context.Context, in *SimulatePolicyRequest, opts ...grpc.CallOption) (*SimulatePolicyResponse, error)
+	// Get the faults injected by a server built with the chaos tag
+	GetFaultInjection(ctx context.Context, in *GetFaultInjectionRequest, opts ...grpc.CallOption) (*FaultInjection, error)
+	// Replace the faults injected by a server built with the chaos tag
+	// until it restarts, for resilience tests in staging
+	SetFaultInjection(ctx context.Context, in *FaultInjection, opts ...grpc.CallOption) (*FaultInjection, error)
+	// List the access requests of agents, newest first
+	ListAccessRequests(ctx context.Context, in *ListAccessRequestsRequest, opts ...grpc.CallOption) (*ListAccessRequestsResponse, error)
+	// Approve a pending access request, granting its agent the access until
+	// the grant expires
+	ApproveAccessRequest(ctx context.Context, in *ApproveAccessRequestRequest, opts ...grpc.CallOption) (*AccessRequest, error)
+	// Deny a pending access request
+	DenyAccessRequest(ctx context.Context, in *DenyAccessRequestRequest, opts ...grpc.CallOption) (*AccessRequest, error)
+	// List the audit log of admin API calls that changed something or were
+	// denied, newest first
+	ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error)
+	// Move live sessions to another server of the cluster, which continues
+	// them with their overlay IPs and routes, e.g. before maintenance
+	HandOffSessions(ctx context.Context, in *HandOffSessionsRequest, opts ...grpc.CallOption) (*HandOffSessionsResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*RoutingRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoutingRuleResponse)
+	err := c.cc.Invoke(ctx, AdminService_AddRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateRoutingRule(ctx context.Context, in *UpdateRoutingRuleRequest, opts ...grpc.CallOption) (*RoutingRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoutingRuleResponse)
+	err := c.cc.Invoke(ctx, AdminService_UpdateRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteRoutingRule(ctx context.Context, in *DeleteRoutingRuleRequest, opts ...grpc.CallOption) (*DeleteRoutingRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRoutingRuleResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAgents(ctx context.Context, in *ListAgentsRequest, opts ...grpc.CallOption) (*ListAgentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAgentsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAgents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetAgent(ctx context.Context, in *GetAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_GetAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListRoutingRules(ctx context.Context, in *ListRoutingRulesRequest, opts ...grpc.CallOption) (*ListRoutingRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRoutingRulesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListRoutingRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, AdminService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, AdminService_RotateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*UserDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserDetail)
+	err := c.cc.Invoke(ctx, AdminService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UserDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserDetail)
+	err := c.cc.Invoke(ctx, AdminService_UpdateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUserResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExportUsage(ctx context.Context, in *ExportUsageRequest, opts ...grpc.CallOption) (*ExportUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportUsageResponse)
+	err := c.cc.Invoke(ctx, AdminService_ExportUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListTrafficRollups(ctx context.Context, in *ListTrafficRollupsRequest, opts ...grpc.CallOption) (*ListTrafficRollupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrafficRollupsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListTrafficRollups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExportInventory(ctx context.Context, in *ExportInventoryRequest, opts ...grpc.CallOption) (*ExportInventoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportInventoryResponse)
+	err := c.cc.Invoke(ctx, AdminService_ExportInventory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetRelayTracing(ctx context.Context, in *SetRelayTracingRequest, opts ...grpc.CallOption) (*RelayTracingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RelayTracingResponse)
+	err := c.cc.Invoke(ctx, AdminService_SetRelayTracing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListRelayTraces(ctx context.Context, in *ListRelayTracesRequest, opts ...grpc.CallOption) (*ListRelayTracesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRelayTracesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListRelayTraces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetHandshakeStats(ctx context.Context, in *GetHandshakeStatsRequest, opts ...grpc.CallOption) (*HandshakeStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandshakeStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetHandshakeStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ArchiveAgent(ctx context.Context, in *ArchiveAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_ArchiveAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RestoreAgent(ctx context.Context, in *RestoreAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_RestoreAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListSessionHistory(ctx context.Context, in *ListSessionHistoryRequest, opts ...grpc.CallOption) (*ListSessionHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionHistoryResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListSessionHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ApproveAgent(ctx context.Context, in *ApproveAgentRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_ApproveAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RejectAgent(ctx context.Context, in *RejectAgentRequest, opts ...grpc.CallOption) (*RejectAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectAgentResponse)
+	err := c.cc.Invoke(ctx, AdminService_RejectAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ResetAgentIdentity(ctx context.Context, in *ResetAgentIdentityRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_ResetAgentIdentity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListACLRules(ctx context.Context, in *ListACLRulesRequest, opts ...grpc.CallOption) (*ListACLRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListACLRulesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListACLRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) AddACLRule(ctx context.Context, in *AddACLRuleRequest, opts ...grpc.CallOption) (*ACLRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ACLRule)
+	err := c.cc.Invoke(ctx, AdminService_AddACLRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateACLRule(ctx context.Context, in *UpdateACLRuleRequest, opts ...grpc.CallOption) (*ACLRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ACLRule)
+	err := c.cc.Invoke(ctx, AdminService_UpdateACLRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteACLRule(ctx context.Context, in *DeleteACLRuleRequest, opts ...grpc.CallOption) (*DeleteACLRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteACLRuleResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteACLRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetCryptoPolicy(ctx context.Context, in *GetCryptoPolicyRequest, opts ...grpc.CallOption) (*CryptoPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CryptoPolicyResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetCryptoPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetRelayQueueStats(ctx context.Context, in *GetRelayQueueStatsRequest, opts ...grpc.CallOption) (*RelayQueueStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RelayQueueStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetRelayQueueStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetKeepalive(ctx context.Context, in *GetKeepaliveRequest, opts ...grpc.CallOption) (*KeepaliveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeepaliveResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetKeepalive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetKeepalive(ctx context.Context, in *SetKeepaliveRequest, opts ...grpc.CallOption) (*KeepaliveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeepaliveResponse)
+	err := c.cc.Invoke(ctx, AdminService_SetKeepalive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAgentGroups(ctx context.Context, in *ListAgentGroupsRequest, opts ...grpc.CallOption) (*ListAgentGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAgentGroupsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAgentGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateAgentGroup(ctx context.Context, in *AgentGroup, opts ...grpc.CallOption) (*AgentGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentGroup)
+	err := c.cc.Invoke(ctx, AdminService_CreateAgentGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateAgentGroup(ctx context.Context, in *AgentGroup, opts ...grpc.CallOption) (*AgentGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentGroup)
+	err := c.cc.Invoke(ctx, AdminService_UpdateAgentGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteAgentGroup(ctx context.Context, in *DeleteAgentGroupRequest, opts ...grpc.CallOption) (*DeleteAgentGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAgentGroupResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteAgentGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetAgentGroup(ctx context.Context, in *SetAgentGroupRequest, opts ...grpc.CallOption) (*AgentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentDetail)
+	err := c.cc.Invoke(ctx, AdminService_SetAgentGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) StageRollout(ctx context.Context, in *StageRolloutRequest, opts ...grpc.CallOption) (*Rollout, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Rollout)
+	err := c.cc.Invoke(ctx, AdminService_StageRollout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListRollouts(ctx context.Context, in *ListRolloutsRequest, opts ...grpc.CallOption) (*ListRolloutsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRolloutsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListRollouts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetRollout(ctx context.Context, in *GetRolloutRequest, opts ...grpc.CallOption) (*Rollout, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Rollout)
+	err := c.cc.Invoke(ctx, AdminService_GetRollout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) PromoteRollout(ctx context.Context, in *PromoteRolloutRequest, opts ...grpc.CallOption) (*Rollout, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Rollout)
+	err := c.cc.Invoke(ctx, AdminService_PromoteRollout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RollBackRollout(ctx context.Context, in *RollBackRolloutRequest, opts ...grpc.CallOption) (*Rollout, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Rollout)
+	err := c.cc.Invoke(ctx, AdminService_RollBackRollout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SimulatePolicy(ctx context.Context, in *SimulatePolicyRequest, opts ...grpc.CallOption) (*SimulatePolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimulatePolicyResponse)
+	err := c.cc.Invoke(ctx, AdminService_SimulatePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetFaultInjection(ctx context.Context, in *GetFaultInjectionRequest, opts ...grpc.CallOption) (*FaultInjection, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FaultInjection)
+	err := c.cc.Invoke(ctx, AdminService_GetFaultInjection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetFaultInjection(ctx context.Context, in *FaultInjection, opts ...grpc.CallOption) (*FaultInjection, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FaultInjection)
+	err := c.cc.Invoke(ctx, AdminService_SetFaultInjection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAccessRequests(ctx context.Context, in *ListAccessRequestsRequest, opts ...grpc.CallOption) (*ListAccessRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAccessRequestsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAccessRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ApproveAccessRequest(ctx context.Context, in *ApproveAccessRequestRequest, opts ...grpc.CallOption) (*AccessRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccessRequest)
+	err := c.cc.Invoke(ctx, AdminService_ApproveAccessRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DenyAccessRequest(ctx context.Context, in *DenyAccessRequestRequest, opts ...grpc.CallOption) (*AccessRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccessRequest)
+	err := c.cc.Invoke(ctx, AdminService_DenyAccessRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditLogResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) HandOffSessions(ctx context.Context, in *HandOffSessionsRequest, opts ...grpc.CallOption) (*HandOffSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandOffSessionsResponse)
+	err := c.cc.Invoke(ctx, AdminService_HandOffSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService defines the gRPC service for administrative tooling.
+// All calls must carry the API key of an admin user in the "x-api-key"
+// request metadata.
+type AdminServiceServer interface {
+	// Add a routing rule for an agent
+	AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*RoutingRuleResponse, error)
+	// Replace an existing routing rule
+	UpdateRoutingRule(context.Context, *UpdateRoutingRuleRequest) (*RoutingRuleResponse, error)
+	// Delete a routing rule
+	DeleteRoutingRule(context.Context, *DeleteRoutingRuleRequest) (*DeleteRoutingRuleResponse, error)
+	// List registered agents with optional filters
+	ListAgents(context.Context, *ListAgentsRequest) (*ListAgentsResponse, error)
+	// Get a single agent
+	GetAgent(context.Context, *GetAgentRequest) (*AgentDetail, error)
+	// List all routing rules of an agent, including disabled ones
+	ListRoutingRules(context.Context, *ListRoutingRulesRequest) (*ListRoutingRulesResponse, error)
+	// Create a user account with a new API key
+	CreateUser(context.Context, *CreateUserRequest) (*UserResponse, error)
+	// Replace the API key of a user
+	RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*UserResponse, error)
+	// List all user accounts with their quotas and current usage
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	// Get a user account with its quotas and monthly usage
+	GetUser(context.Context, *GetUserRequest) (*UserDetail, error)
+	// Update the email, role, status and quotas of a user
+	UpdateUser(context.Context, *UpdateUserRequest) (*UserDetail, error)
+	// Delete a user together with its agents
+	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
+	// Export the relayed traffic of every user in a month for billing
+	ExportUsage(context.Context, *ExportUsageRequest) (*ExportUsageResponse, error)
+	// List the hourly or daily rollups of ended sessions, newest first
+	ListTrafficRollups(context.Context, *ListTrafficRollupsRequest) (*ListTrafficRollupsResponse, error)
+	// Export the overlay addresses of the agents as a hosts file fragment
+	// or an Ansible inventory
+	ExportInventory(context.Context, *ExportInventoryRequest) (*ExportInventoryResponse, error)
+	// Change the relay trace sampling rate at runtime
+	SetRelayTracing(context.Context, *SetRelayTracingRequest) (*RelayTracingResponse, error)
+	// List recently sampled relay decisions, newest first
+	ListRelayTraces(context.Context, *ListRelayTracesRequest) (*ListRelayTracesResponse, error)
+	// Get QUIC handshake address validation counters
+	GetHandshakeStats(context.Context, *GetHandshakeStatsRequest) (*HandshakeStatsResponse, error)
+	// Archive an agent, disconnecting it but keeping its history
+	ArchiveAgent(context.Context, *ArchiveAgentRequest) (*AgentDetail, error)
+	// Restore an archived agent
+	RestoreAgent(context.Context, *RestoreAgentRequest) (*AgentDetail, error)
+	// List ended sessions of an agent, newest first
+	ListSessionHistory(context.Context, *ListSessionHistoryRequest) (*ListSessionHistoryResponse, error)
+	// Approve an agent awaiting approval, allowing it to connect
+	ApproveAgent(context.Context, *ApproveAgentRequest) (*AgentDetail, error)
+	// Reject an agent awaiting approval, deleting it
+	RejectAgent(context.Context, *RejectAgentRequest) (*RejectAgentResponse, error)
+	// Forget the hardware identity key an agent is bound to, its next
+	// registration binds a new one, e.g. after a TPM was cleared
+	ResetAgentIdentity(context.Context, *ResetAgentIdentityRequest) (*AgentDetail, error)
+	// List the ACL rules of a user, including disabled ones
+	ListACLRules(context.Context, *ListACLRulesRequest) (*ListACLRulesResponse, error)
+	// Add an ACL rule filtering the traffic relayed from a user's agents
+	AddACLRule(context.Context, *AddACLRuleRequest) (*ACLRule, error)
+	// Replace an existing ACL rule
+	UpdateACLRule(context.Context, *UpdateACLRuleRequest) (*ACLRule, error)
+	// Delete an ACL rule
+	DeleteACLRule(context.Context, *DeleteACLRuleRequest) (*DeleteACLRuleResponse, error)
+	// Get the crypto policy enforced by the server's TLS
+	GetCryptoPolicy(context.Context, *GetCryptoPolicyRequest) (*CryptoPolicyResponse, error)
+	// Get the per traffic class counters of the server relay queues
+	GetRelayQueueStats(context.Context, *GetRelayQueueStatsRequest) (*RelayQueueStatsResponse, error)
+	// Get the heartbeat settings sent to agents
+	GetKeepalive(context.Context, *GetKeepaliveRequest) (*KeepaliveResponse, error)
+	// Change the heartbeat settings until the server restarts, connected
+	// agents apply them with their next heartbeat
+	SetKeepalive(context.Context, *SetKeepaliveRequest) (*KeepaliveResponse, error)
+	// List agent groups
+	ListAgentGroups(context.Context, *ListAgentGroupsRequest) (*ListAgentGroupsResponse, error)
+	// Create an agent group
+	CreateAgentGroup(context.Context, *AgentGroup) (*AgentGroup, error)
+	// Replace the settings of an agent group, its connected agents apply
+	// them at once
+	UpdateAgentGroup(context.Context, *AgentGroup) (*AgentGroup, error)
+	// Delete an agent group together with its routing rules
+	DeleteAgentGroup(context.Context, *DeleteAgentGroupRequest) (*DeleteAgentGroupResponse, error)
+	// Move an agent into a group or out of its group
+	SetAgentGroup(context.Context, *SetAgentGroupRequest) (*AgentDetail, error)
+	// Stage a routing or ACL rule change to a canary of agents. One change
+	// can be staged at a time.
+	StageRollout(context.Context, *StageRolloutRequest) (*Rollout, error)
+	// List rollouts, newest first
+	ListRollouts(context.Context, *ListRolloutsRequest) (*ListRolloutsResponse, error)
+	// Get a rollout with the traffic of its canary and of the other agents
+	GetRollout(context.Context, *GetRolloutRequest) (*Rollout, error)
+	// Apply a staged change to all agents
+	PromoteRollout(context.Context, *PromoteRolloutRequest) (*Rollout, error)
+	// Withdraw a staged change from its canary
+	RollBackRollout(context.Context, *RollBackRolloutRequest) (*Rollout, error)
+	// Report the recently relayed flows a routing or ACL rule change would
+	// deny or reroute, without applying it
+	SimulatePolicy(context.Context, *SimulatePolicyRequest) (*SimulatePolicyResponse, error)
+	// Get the faults injected by a server built with the chaos tag
+	GetFaultInjection(context.Context, *GetFaultInjectionRequest) (*FaultInjection, error)
+	// Replace the faults injected by a server built with the chaos tag
+	// until it restarts, for resilience tests in staging
+	SetFaultInjection(context.Context, *FaultInjection) (*FaultInjection, error)
+	// List the access requests of agents, newest first
+	ListAccessRequests(context.Context, *ListAccessRequestsRequest) (*ListAccessRequestsResponse, error)
+	// Approve a pending access request, granting its agent the access until
+	// the grant expires
+	ApproveAccessRequest(context.Context, *ApproveAccessRequestRequest) (*AccessRequest, error)
+	// Deny a pending access request
+	DenyAccessRequest(context.Context, *DenyAccessRequestRequest) (*AccessRequest, error)
+	// List the audit log of admin API calls that changed something or were
+	// denied, newest first
+	ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error)
+	// Move live sessions to another server of the cluster, which continues
+	// them with their overlay IPs and routes, e.g. before maintenance
+	HandOffSessions(context.Context, *HandOffSessionsRequest) (*HandOffSessionsResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*RoutingRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRoutingRule not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateRoutingRule(context.Context, *UpdateRoutingRuleRequest) (*RoutingRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRoutingRule not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteRoutingRule(context.Context, *DeleteRoutingRuleRequest) (*DeleteRoutingRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRoutingRule not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAgents(context.Context, *ListAgentsRequest) (*ListAgentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAgents not implemented")
+}
+func (UnimplementedAdminServiceServer) GetAgent(context.Context, *GetAgentRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) ListRoutingRules(context.Context, *ListRoutingRulesRequest) (*ListRoutingRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoutingRules not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateUser(context.Context, *CreateUserRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedAdminServiceServer) RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateAPIKey not implemented")
+}
+func (UnimplementedAdminServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedAdminServiceServer) GetUser(context.Context, *GetUserRequest) (*UserDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateUser(context.Context, *UpdateUserRequest) (*UserDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedAdminServiceServer) ExportUsage(context.Context, *ExportUsageRequest) (*ExportUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportUsage not implemented")
+}
+func (UnimplementedAdminServiceServer) ListTrafficRollups(context.Context, *ListTrafficRollupsRequest) (*ListTrafficRollupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrafficRollups not implemented")
+}
+func (UnimplementedAdminServiceServer) ExportInventory(context.Context, *ExportInventoryRequest) (*ExportInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportInventory not implemented")
+}
+func (UnimplementedAdminServiceServer) SetRelayTracing(context.Context, *SetRelayTracingRequest) (*RelayTracingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRelayTracing not implemented")
+}
+func (UnimplementedAdminServiceServer) ListRelayTraces(context.Context, *ListRelayTracesRequest) (*ListRelayTracesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRelayTraces not implemented")
+}
+func (UnimplementedAdminServiceServer) GetHandshakeStats(context.Context, *GetHandshakeStatsRequest) (*HandshakeStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHandshakeStats not implemented")
+}
+func (UnimplementedAdminServiceServer) ArchiveAgent(context.Context, *ArchiveAgentRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) RestoreAgent(context.Context, *RestoreAgentRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) ListSessionHistory(context.Context, *ListSessionHistoryRequest) (*ListSessionHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessionHistory not implemented")
+}
+func (UnimplementedAdminServiceServer) ApproveAgent(context.Context, *ApproveAgentRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) RejectAgent(context.Context, *RejectAgentRequest) (*RejectAgentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectAgent not implemented")
+}
+func (UnimplementedAdminServiceServer) ResetAgentIdentity(context.Context, *ResetAgentIdentityRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetAgentIdentity not implemented")
+}
+func (UnimplementedAdminServiceServer) ListACLRules(context.Context, *ListACLRulesRequest) (*ListACLRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListACLRules not implemented")
+}
+func (UnimplementedAdminServiceServer) AddACLRule(context.Context, *AddACLRuleRequest) (*ACLRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddACLRule not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateACLRule(context.Context, *UpdateACLRuleRequest) (*ACLRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateACLRule not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteACLRule(context.Context, *DeleteACLRuleRequest) (*DeleteACLRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteACLRule not implemented")
+}
+func (UnimplementedAdminServiceServer) GetCryptoPolicy(context.Context, *GetCryptoPolicyRequest) (*CryptoPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCryptoPolicy not implemented")
+}
+func (UnimplementedAdminServiceServer) GetRelayQueueStats(context.Context, *GetRelayQueueStatsRequest) (*RelayQueueStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelayQueueStats not implemented")
+}
+func (UnimplementedAdminServiceServer) GetKeepalive(context.Context, *GetKeepaliveRequest) (*KeepaliveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeepalive not implemented")
+}
+func (UnimplementedAdminServiceServer) SetKeepalive(context.Context, *SetKeepaliveRequest) (*KeepaliveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetKeepalive not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAgentGroups(context.Context, *ListAgentGroupsRequest) (*ListAgentGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAgentGroups not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateAgentGroup(context.Context, *AgentGroup) (*AgentGroup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAgentGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateAgentGroup(context.Context, *AgentGroup) (*AgentGroup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAgentGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteAgentGroup(context.Context, *DeleteAgentGroupRequest) (*DeleteAgentGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAgentGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) SetAgentGroup(context.Context, *SetAgentGroupRequest) (*AgentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAgentGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) StageRollout(context.Context, *StageRolloutRequest) (*Rollout, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StageRollout not implemented")
+}
+func (UnimplementedAdminServiceServer) ListRollouts(context.Context, *ListRolloutsRequest) (*ListRolloutsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRollouts not implemented")
+}
+func (UnimplementedAdminServiceServer) GetRollout(context.Context, *GetRolloutRequest) (*Rollout, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRollout not implemented")
+}
+func (UnimplementedAdminServiceServer) PromoteRollout(context.Context, *PromoteRolloutRequest) (*Rollout, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PromoteRollout not implemented")
+}
+func (UnimplementedAdminServiceServer) RollBackRollout(context.Context, *RollBackRolloutRequest) (*Rollout, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollBackRollout not implemented")
+}
+func (UnimplementedAdminServiceServer) SimulatePolicy(context.Context, *SimulatePolicyRequest) (*SimulatePolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulatePolicy not implemented")
+}
+func (UnimplementedAdminServiceServer) GetFaultInjection(context.Context, *GetFaultInjectionRequest) (*FaultInjection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFaultInjection not implemented")
+}
+func (UnimplementedAdminServiceServer) SetFaultInjection(context.Context, *FaultInjection) (*FaultInjection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFaultInjection not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAccessRequests(context.Context, *ListAccessRequestsRequest) (*ListAccessRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccessRequests not implemented")
+}
+func (UnimplementedAdminServiceServer) ApproveAccessRequest(context.Context, *ApproveAccessRequestRequest) (*AccessRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveAccessRequest not implemented")
+}
+func (UnimplementedAdminServiceServer) DenyAccessRequest(context.Context, *DenyAccessRequestRequest) (*AccessRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DenyAccessRequest not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLog not implemented")
+}
+func (UnimplementedAdminServiceServer) HandOffSessions(context.Context, *HandOffSessionsRequest) (*HandOffSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandOffSessions not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_AddRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).AddRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_AddRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).AddRoutingRule(ctx, req.(*AddRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateRoutingRule(ctx, req.(*UpdateRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteRoutingRule(ctx, req.(*DeleteRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAgents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAgents(ctx, req.(*ListAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetAgent(ctx, req.(*GetAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListRoutingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoutingRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListRoutingRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListRoutingRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListRoutingRules(ctx, req.(*ListRoutingRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RotateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RotateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RotateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RotateAPIKey(ctx, req.(*RotateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExportUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ExportUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ExportUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ExportUsage(ctx, req.(*ExportUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListTrafficRollups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrafficRollupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListTrafficRollups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListTrafficRollups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListTrafficRollups(ctx, req.(*ListTrafficRollupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExportInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ExportInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ExportInventory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ExportInventory(ctx, req.(*ExportInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetRelayTracing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRelayTracingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetRelayTracing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetRelayTracing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetRelayTracing(ctx, req.(*SetRelayTracingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListRelayTraces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRelayTracesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListRelayTraces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListRelayTraces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListRelayTraces(ctx, req.(*ListRelayTracesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetHandshakeStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHandshakeStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetHandshakeStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetHandshakeStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetHandshakeStats(ctx, req.(*GetHandshakeStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ArchiveAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ArchiveAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ArchiveAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ArchiveAgent(ctx, req.(*ArchiveAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RestoreAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RestoreAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RestoreAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RestoreAgent(ctx, req.(*RestoreAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListSessionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListSessionHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListSessionHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListSessionHistory(ctx, req.(*ListSessionHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ApproveAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ApproveAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ApproveAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ApproveAgent(ctx, req.(*ApproveAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RejectAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RejectAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RejectAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RejectAgent(ctx, req.(*RejectAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ResetAgentIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetAgentIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ResetAgentIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ResetAgentIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ResetAgentIdentity(ctx, req.(*ResetAgentIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListACLRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListACLRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListACLRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListACLRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListACLRules(ctx, req.(*ListACLRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_AddACLRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddACLRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).AddACLRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_AddACLRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).AddACLRule(ctx, req.(*AddACLRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateACLRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateACLRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateACLRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateACLRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateACLRule(ctx, req.(*UpdateACLRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteACLRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteACLRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteACLRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteACLRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteACLRule(ctx, req.(*DeleteACLRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetCryptoPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCryptoPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetCryptoPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetCryptoPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetCryptoPolicy(ctx, req.(*GetCryptoPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetRelayQueueStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelayQueueStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetRelayQueueStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetRelayQueueStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetRelayQueueStats(ctx, req.(*GetRelayQueueStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetKeepalive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeepaliveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetKeepalive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetKeepalive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetKeepalive(ctx, req.(*GetKeepaliveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetKeepalive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeepaliveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetKeepalive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetKeepalive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetKeepalive(ctx, req.(*SetKeepaliveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAgentGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAgentGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAgentGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAgentGroups(ctx, req.(*ListAgentGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateAgentGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateAgentGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateAgentGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateAgentGroup(ctx, req.(*AgentGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateAgentGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateAgentGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateAgentGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateAgentGroup(ctx, req.(*AgentGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteAgentGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAgentGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteAgentGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteAgentGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteAgentGroup(ctx, req.(*DeleteAgentGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetAgentGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAgentGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetAgentGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetAgentGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetAgentGroup(ctx, req.(*SetAgentGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_StageRollout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StageRolloutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).StageRollout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_StageRollout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).StageRollout(ctx, req.(*StageRolloutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListRollouts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRolloutsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListRollouts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListRollouts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListRollouts(ctx, req.(*ListRolloutsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetRollout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRolloutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetRollout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetRollout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetRollout(ctx, req.(*GetRolloutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_PromoteRollout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteRolloutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).PromoteRollout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_PromoteRollout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).PromoteRollout(ctx, req.(*PromoteRolloutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RollBackRollout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollBackRolloutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RollBackRollout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RollBackRollout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RollBackRollout(ctx, req.(*RollBackRolloutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SimulatePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulatePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SimulatePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SimulatePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SimulatePolicy(ctx, req.(*SimulatePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetFaultInjection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFaultInjectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetFaultInjection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetFaultInjection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetFaultInjection(ctx, req.(*GetFaultInjectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetFaultInjection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FaultInjection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetFaultInjection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetFaultInjection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetFaultInjection(ctx, req.(*FaultInjection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAccessRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccessRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAccessRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAccessRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAccessRequests(ctx, req.(*ListAccessRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ApproveAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveAccessRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ApproveAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ApproveAccessRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ApproveAccessRequest(ctx, req.(*ApproveAccessRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DenyAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DenyAccessRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DenyAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DenyAccessRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DenyAccessRequest(ctx, req.(*DenyAccessRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAuditLog(ctx, req.(*ListAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_HandOffSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandOffSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).HandOffSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_HandOffSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).HandOffSessions(ctx, req.(*HandOffSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "easyanylink.v2.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddRoutingRule",
+			Handler:    _AdminService_AddRoutingRule_Handler,
+		},
+		{
+			MethodName: "UpdateRoutingRule",
+			Handler:    _AdminService_UpdateRoutingRule_Handler,
+		},
+		{
+			MethodName: "DeleteRoutingRule",
+			Handler:    _AdminService_DeleteRoutingRule_Handler,
+		},
+		{
+			MethodName: "ListAgents",
+			Handler:    _AdminService_ListAgents_Handler,
+		},
+		{
+			MethodName: "GetAgent",
+			Handler:    _AdminService_GetAgent_Handler,
+		},
+		{
+			MethodName: "ListRoutingRules",
+			Handler:    _AdminService_ListRoutingRules_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _AdminService_CreateUser_Handler,
+		},
+		{
+			MethodName: "RotateAPIKey",
+			Handler:    _AdminService_RotateAPIKey_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _AdminService_ListUsers_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _AdminService_GetUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _AdminService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _AdminService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "ExportUsage",
+			Handler:    _AdminService_ExportUsage_Handler,
+		},
+		{
+			MethodName: "ListTrafficRollups",
+			Handler:    _AdminService_ListTrafficRollups_Handler,
+		},
+		{
+			MethodName: "ExportInventory",
+			Handler:    _AdminService_ExportInventory_Handler,
+		},
+		{
+			MethodName: "SetRelayTracing",
+			Handler:    _AdminService_SetRelayTracing_Handler,
+		},
+		{
+			MethodName: "ListRelayTraces",
+			Handler:    _AdminService_ListRelayTraces_Handler,
+		},
+		{
+			MethodName: "GetHandshakeStats",
+			Handler:    _AdminService_GetHandshakeStats_Handler,
+		},
+		{
+			MethodName: "ArchiveAgent",
+			Handler:    _AdminService_ArchiveAgent_Handler,
+		},
+		{
+			MethodName: "RestoreAgent",
+			Handler:    _AdminService_RestoreAgent_Handler,
+		},
+		{
+			MethodName: "ListSessionHistory",
+			Handler:    _AdminService_ListSessionHistory_Handler,
+		},
+		{
+			MethodName: "ApproveAgent",
+			Handler:    _AdminService_ApproveAgent_Handler,
+		},
+		{
+			MethodName: "RejectAgent",
+			Handler:    _AdminService_RejectAgent_Handler,
+		},
+		{
+			MethodName: "ResetAgentIdentity",
+			Handler:    _AdminService_ResetAgentIdentity_Handler,
+		},
+		{
+			MethodName: "ListACLRules",
+			Handler:    _AdminService_ListACLRules_Handler,
+		},
+		{
+			MethodName: "AddACLRule",
+			Handler:    _AdminService_AddACLRule_Handler,
+		},
+		{
+			MethodName: "UpdateACLRule",
+			Handler:    _AdminService_UpdateACLRule_Handler,
+		},
+		{
+			MethodName: "DeleteACLRule",
+			Handler:    _AdminService_DeleteACLRule_Handler,
+		},
+		{
+			MethodName: "GetCryptoPolicy",
+			Handler:    _AdminService_GetCryptoPolicy_Handler,
+		},
+		{
+			MethodName: "GetRelayQueueStats",
+			Handler:    _AdminService_GetRelayQueueStats_Handler,
+		},
+		{
+			MethodName: "GetKeepalive",
+			Handler:    _AdminService_GetKeepalive_Handler,
+		},
+		{
+			MethodName: "SetKeepalive",
+			Handler:    _AdminService_SetKeepalive_Handler,
+		},
+		{
+			MethodName: "ListAgentGroups",
+			Handler:    _AdminService_ListAgentGroups_Handler,
+		},
+		{
+			MethodName: "CreateAgentGroup",
+			Handler:    _AdminService_CreateAgentGroup_Handler,
+		},
+		{
+			MethodName: "UpdateAgentGroup",
+			Handler:    _AdminService_UpdateAgentGroup_Handler,
+		},
+		{
+			MethodName: "DeleteAgentGroup",
+			Handler:    _AdminService_DeleteAgentGroup_Handler,
+		},
+		{
+			MethodName: "SetAgentGroup",
+			Handler:    _AdminService_SetAgentGroup_Handler,
+		},
+		{
+			MethodName: "StageRollout",
+			Handler:    _AdminService_StageRollout_Handler,
+		},
+		{
+			MethodName: "ListRollouts",
+			Handler:    _AdminService_ListRollouts_Handler,
+		},
+		{
+			MethodName: "GetRollout",
+			Handler:    _AdminService_GetRollout_Handler,
+		},
+		{
+			MethodName: "PromoteRollout",
+			Handler:    _AdminService_PromoteRollout_Handler,
+		},
+		{
+			MethodName: "RollBackRollout",
+			Handler:    _AdminService_RollBackRollout_Handler,
+		},
+		{
+			MethodName: "SimulatePolicy",
+			Handler:    _AdminService_SimulatePolicy_Handler,
+		},
+		{
+			MethodName: "GetFaultInjection",
+			Handler:    _AdminService_GetFaultInjection_Handler,
+		},
+		{
+			MethodName: "SetFaultInjection",
+			Handler:    _AdminService_SetFaultInjection_Handler,
+		},
+		{
+			MethodName: "ListAccessRequests",
+			Handler:    _AdminService_ListAccessRequests_Handler,
+		},
+		{
+			MethodName: "ApproveAccessRequest",
+			Handler:    _AdminService_ApproveAccessRequest_Handler,
+		},
+		{
+			MethodName: "DenyAccessRequest",
+			Handler:    _AdminService_DenyAccessRequest_Handler,
+		},
+		{
+			MethodName: "ListAuditLog",
+			Handler:    _AdminService_ListAuditLog_Handler,
+		},
+		{
+			MethodName: "HandOffSessions",
+			Handler:    _AdminService_HandOffSessions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "common/proto/easyanylink/v2/admin.proto",
+}
@@ -0,0 +1,3422 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: common/proto/easyanylink/v2/agent.proto
+
+// Version 2 of the API. It must stay wire compatible with v1, which the
+// server serves with the same handlers: add fields, never renumber them,
+// and reserve the numbers and names of removed ones.
+
+package easyanylinkv2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AgentType defines the role of the agent
+type AgentType int32
+
+const (
+	AgentType_AGENT_TYPE_UNSPECIFIED AgentType = 0
+	AgentType_CLIENT                 AgentType = 1
+	AgentType_GATEWAY                AgentType = 2
+)
+
+// Enum value maps for AgentType.
+var (
+	AgentType_name = map[int32]string{
+		0: "AGENT_TYPE_UNSPECIFIED",
+		1: "CLIENT",
+		2: "GATEWAY",
+	}
+	AgentType_value = map[string]int32{
+		"AGENT_TYPE_UNSPECIFIED": 0,
+		"CLIENT":                 1,
+		"GATEWAY":                2,
+	}
+)
+
+func (x AgentType) Enum() *AgentType {
+	p := new(AgentType)
+	*p = x
+	return p
+}
+
+func (x AgentType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AgentType) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_proto_easyanylink_v2_agent_proto_enumTypes[0].Descriptor()
+}
+
+func (AgentType) Type() protoreflect.EnumType {
+	return &file_common_proto_easyanylink_v2_agent_proto_enumTypes[0]
+}
+
+func (x AgentType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AgentType.Descriptor instead.
+func (AgentType) EnumDescriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{0}
+}
+
+// PostureState is the outcome of one posture check
+type PostureState int32
+
+const (
+	PostureState_POSTURE_STATE_UNKNOWN  PostureState = 0 // The agent could not tell, fails policies requiring it
+	PostureState_POSTURE_STATE_ENABLED  PostureState = 1
+	PostureState_POSTURE_STATE_DISABLED PostureState = 2
+)
+
+// Enum value maps for PostureState.
+var (
+	PostureState_name = map[int32]string{
+		0: "POSTURE_STATE_UNKNOWN",
+		1: "POSTURE_STATE_ENABLED",
+		2: "POSTURE_STATE_DISABLED",
+	}
+	PostureState_value = map[string]int32{
+		"POSTURE_STATE_UNKNOWN":  0,
+		"POSTURE_STATE_ENABLED":  1,
+		"POSTURE_STATE_DISABLED": 2,
+	}
+)
+
+func (x PostureState) Enum() *PostureState {
+	p := new(PostureState)
+	*p = x
+	return p
+}
+
+func (x PostureState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PostureState) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_proto_easyanylink_v2_agent_proto_enumTypes[1].Descriptor()
+}
+
+func (PostureState) Type() protoreflect.EnumType {
+	return &file_common_proto_easyanylink_v2_agent_proto_enumTypes[1]
+}
+
+func (x PostureState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PostureState.Descriptor instead.
+func (PostureState) EnumDescriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{1}
+}
+
+// RouteAction defines what to do with matching packets
+type RouteAction int32
+
+const (
+	RouteAction_ROUTE_ACTION_UNSPECIFIED RouteAction = 0
+	RouteAction_FORWARD                  RouteAction = 1 // Forward through gateway
+	RouteAction_DIRECT                   RouteAction = 2 // Send directly (local routing)
+	RouteAction_DENY                     RouteAction = 3 // Drop the packet
+)
+
+// Enum value maps for RouteAction.
+var (
+	RouteAction_name = map[int32]string{
+		0: "ROUTE_ACTION_UNSPECIFIED",
+		1: "FORWARD",
+		2: "DIRECT",
+		3: "DENY",
+	}
+	RouteAction_value = map[string]int32{
+		"ROUTE_ACTION_UNSPECIFIED": 0,
+		"FORWARD":                  1,
+		"DIRECT":                   2,
+		"DENY":                     3,
+	}
+)
+
+func (x RouteAction) Enum() *RouteAction {
+	p := new(RouteAction)
+	*p = x
+	return p
+}
+
+func (x RouteAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RouteAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_proto_easyanylink_v2_agent_proto_enumTypes[2].Descriptor()
+}
+
+func (RouteAction) Type() protoreflect.EnumType {
+	return &file_common_proto_easyanylink_v2_agent_proto_enumTypes[2]
+}
+
+func (x RouteAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RouteAction.Descriptor instead.
+func (RouteAction) EnumDescriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{2}
+}
+
+// AgentStatus represents the operational state
+type AgentStatus int32
+
+const (
+	AgentStatus_AGENT_STATUS_UNSPECIFIED AgentStatus = 0
+	AgentStatus_ONLINE                   AgentStatus = 1
+	AgentStatus_OFFLINE                  AgentStatus = 2
+	AgentStatus_ERROR                    AgentStatus = 3
+	AgentStatus_MAINTENANCE              AgentStatus = 4
+	AgentStatus_READY                    AgentStatus = 5 // A gateway finished setting up and takes traffic, see CapabilityGatewayReady
+)
+
+// Enum value maps for AgentStatus.
+var (
+	AgentStatus_name = map[int32]string{
+		0: "AGENT_STATUS_UNSPECIFIED",
+		1: "ONLINE",
+		2: "OFFLINE",
+		3: "ERROR",
+		4: "MAINTENANCE",
+		5: "READY",
+	}
+	AgentStatus_value = map[string]int32{
+		"AGENT_STATUS_UNSPECIFIED": 0,
+		"ONLINE":                   1,
+		"OFFLINE":                  2,
+		"ERROR":                    3,
+		"MAINTENANCE":              4,
+		"READY":                    5,
+	}
+)
+
+func (x AgentStatus) Enum() *AgentStatus {
+	p := new(AgentStatus)
+	*p = x
+	return p
+}
+
+func (x AgentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AgentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_proto_easyanylink_v2_agent_proto_enumTypes[3].Descriptor()
+}
+
+func (AgentStatus) Type() protoreflect.EnumType {
+	return &file_common_proto_easyanylink_v2_agent_proto_enumTypes[3]
+}
+
+func (x AgentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AgentStatus.Descriptor instead.
+func (AgentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{3}
+}
+
+// DisconnectReason classifies why a session ended
+type DisconnectReason int32
+
+const (
+	DisconnectReason_DISCONNECT_REASON_UNSPECIFIED DisconnectReason = 0
+	DisconnectReason_DISCONNECT_SERVER_DECISION    DisconnectReason = 1 // An admin or the server ended the session
+	DisconnectReason_DISCONNECT_AUTH_REVOKED       DisconnectReason = 2 // The user was deleted or deactivated
+	DisconnectReason_DISCONNECT_IDLE_TIMEOUT       DisconnectReason = 3 // No heartbeat within the keepalive timeout
+	DisconnectReason_DISCONNECT_AGENT_SHUTDOWN     DisconnectReason = 4 // The agent stopped
+	DisconnectReason_DISCONNECT_TRANSPORT_ERROR    DisconnectReason = 5 // The relay or heartbeat streams failed
+	DisconnectReason_DISCONNECT_AGENT_ERROR        DisconnectReason = 6 // The agent reported a fatal error
+	DisconnectReason_DISCONNECT_SERVER_UPGRADE     DisconnectReason = 7 // The server handed over to a new process, reconnect
+	DisconnectReason_DISCONNECT_SLOW_CONSUMER      DisconnectReason = 8 // The agent did not read its relay streams within network.slow_consumer_timeout
+)
+
+// Enum value maps for DisconnectReason.
+var (
+	DisconnectReason_name = map[int32]string{
+		0: "DISCONNECT_REASON_UNSPECIFIED",
+		1: "DISCONNECT_SERVER_DECISION",
+		2: "DISCONNECT_AUTH_REVOKED",
+		3: "DISCONNECT_IDLE_TIMEOUT",
+		4: "DISCONNECT_AGENT_SHUTDOWN",
+		5: "DISCONNECT_TRANSPORT_ERROR",
+		6: "DISCONNECT_AGENT_ERROR",
+		7: "DISCONNECT_SERVER_UPGRADE",
+		8: "DISCONNECT_SLOW_CONSUMER",
+	}
+	DisconnectReason_value = map[string]int32{
+		"DISCONNECT_REASON_UNSPECIFIED": 0,
+		"DISCONNECT_SERVER_DECISION":    1,
+		"DISCONNECT_AUTH_REVOKED":       2,
+		"DISCONNECT_IDLE_TIMEOUT":       3,
+		"DISCONNECT_AGENT_SHUTDOWN":     4,
+		"DISCONNECT_TRANSPORT_ERROR":    5,
+		"DISCONNECT_AGENT_ERROR":        6,
+		"DISCONNECT_SERVER_UPGRADE":     7,
+		"DISCONNECT_SLOW_CONSUMER":      8,
+	}
+)
+
+func (x DisconnectReason) Enum() *DisconnectReason {
+	p := new(DisconnectReason)
+	*p = x
+	return p
+}
+
+func (x DisconnectReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisconnectReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_proto_easyanylink_v2_agent_proto_enumTypes[4].Descriptor()
+}
+
+func (DisconnectReason) Type() protoreflect.EnumType {
+	return &file_common_proto_easyanylink_v2_agent_proto_enumTypes[4]
+}
+
+func (x DisconnectReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisconnectReason.Descriptor instead.
+func (DisconnectReason) EnumDescriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{4}
+}
+
+// RegisterRequest is sent by agents during initial connection
+type RegisterRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AgentId         string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                         // UUID of the agent
+	UserKey         string                 `protobuf:"bytes,2,opt,name=user_key,json=userKey,proto3" json:"user_key,omitempty"`                         // User API key for authentication
+	Type            AgentType              `protobuf:"varint,3,opt,name=type,proto3,enum=easyanylink.v2.AgentType" json:"type,omitempty"`               // Client or Gateway
+	ProtocolVersion string                 `protobuf:"bytes,4,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"` // Protocol version (e.g., "1.0.0")
+	// Deprecated: Marked as deprecated in common/proto/easyanylink/v2/agent.proto.
+	CertificateFingerprint string         `protobuf:"bytes,5,opt,name=certificate_fingerprint,json=certificateFingerprint,proto3" json:"certificate_fingerprint,omitempty"` // Not sent, agents authenticate with user_key over one-way TLS
+	Metadata               *AgentMetadata `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`                                                           // Additional agent information
+	Bandwidth              int32          `protobuf:"varint,7,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`                                                        // Bandwidth in KB/s, 0 for unlimited
+	RequestId              string         `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`                                        // Idempotency key, reused when a registration is retried
+	Nonce                  []byte         `protobuf:"bytes,9,opt,name=nonce,proto3" json:"nonce,omitempty"`                                                                 // Random per attempt, rejected if seen before (pre-shared key only)
+	Timestamp              int64          `protobuf:"varint,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                                       // Unix seconds of the attempt (pre-shared key only)
+	Mac                    []byte         `protobuf:"bytes,11,opt,name=mac,proto3" json:"mac,omitempty"`                                                                    // HMAC-SHA256 of the request under the pre-shared key, empty without one
+	Capabilities           []string       `protobuf:"bytes,12,rep,name=capabilities,proto3" json:"capabilities,omitempty"`                                                  // Optional features the agent supports
+	Mtu                    int32          `protobuf:"varint,13,opt,name=mtu,proto3" json:"mtu,omitempty"`                                                                   // MTU the agent asks for, 0 for the server default
+	MaxMessageSize         int32          `protobuf:"varint,14,opt,name=max_message_size,json=maxMessageSize,proto3" json:"max_message_size,omitempty"`                     // Largest gRPC message in bytes the agent accepts
+	SiteSubnets            []string       `protobuf:"bytes,15,rep,name=site_subnets,json=siteSubnets,proto3" json:"site_subnets,omitempty"`                                 // Gateway LANs in CIDR notation routed to the user's other site gateways
+	ResumeSessionId        string         `protobuf:"bytes,16,opt,name=resume_session_id,json=resumeSessionId,proto3" json:"resume_session_id,omitempty"`                   // Session of the previous connection, continued by a standby server that took over
+	IdentityKey            []byte         `protobuf:"bytes,17,opt,name=identity_key,json=identityKey,proto3" json:"identity_key,omitempty"`                                 // PKIX ECDSA P-256 public key of the hardware identity, empty without one
+	IdentityProvider       string         `protobuf:"bytes,18,opt,name=identity_provider,json=identityProvider,proto3" json:"identity_provider,omitempty"`                  // Hardware holding the identity key, "tpm2" or "secure-enclave"
+	IdentityChallenge      []byte         `protobuf:"bytes,19,opt,name=identity_challenge,json=identityChallenge,proto3" json:"identity_challenge,omitempty"`               // Challenge from GetIdentityChallenge signed by identity_signature
+	IdentitySignature      []byte         `protobuf:"bytes,20,opt,name=identity_signature,json=identitySignature,proto3" json:"identity_signature,omitempty"`               // ASN.1 ECDSA signature of the registration with the identity key
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetUserKey() string {
+	if x != nil {
+		return x.UserKey
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetType() AgentType {
+	if x != nil {
+		return x.Type
+	}
+	return AgentType_AGENT_TYPE_UNSPECIFIED
+}
+
+func (x *RegisterRequest) GetProtocolVersion() string {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in common/proto/easyanylink/v2/agent.proto.
+func (x *RegisterRequest) GetCertificateFingerprint() string {
+	if x != nil {
+		return x.CertificateFingerprint
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetMetadata() *AgentMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetBandwidth() int32 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetMac() []byte {
+	if x != nil {
+		return x.Mac
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetMtu() int32 {
+	if x != nil {
+		return x.Mtu
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetMaxMessageSize() int32 {
+	if x != nil {
+		return x.MaxMessageSize
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetSiteSubnets() []string {
+	if x != nil {
+		return x.SiteSubnets
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetResumeSessionId() string {
+	if x != nil {
+		return x.ResumeSessionId
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetIdentityKey() []byte {
+	if x != nil {
+		return x.IdentityKey
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetIdentityProvider() string {
+	if x != nil {
+		return x.IdentityProvider
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetIdentityChallenge() []byte {
+	if x != nil {
+		return x.IdentityChallenge
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetIdentitySignature() []byte {
+	if x != nil {
+		return x.IdentitySignature
+	}
+	return nil
+}
+
+// AgentMetadata contains platform and version information
+type AgentMetadata struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Os               string                 `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`                                                                                   // Operating system (linux, darwin, windows)
+	Arch             string                 `protobuf:"bytes,2,opt,name=arch,proto3" json:"arch,omitempty"`                                                                               // Architecture (amd64, arm64)
+	Version          string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`                                                                         // Agent version
+	Hostname         string                 `protobuf:"bytes,4,opt,name=hostname,proto3" json:"hostname,omitempty"`                                                                       // Hostname of the machine
+	Labels           map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Custom labels for filtering/grouping
+	Interfaces       []*NetworkInterface    `protobuf:"bytes,6,rep,name=interfaces,proto3" json:"interfaces,omitempty"`                                                                   // Physical network interfaces
+	DefaultGateway   string                 `protobuf:"bytes,7,opt,name=default_gateway,json=defaultGateway,proto3" json:"default_gateway,omitempty"`                                     // Next hop of the physical default route
+	DefaultInterface string                 `protobuf:"bytes,8,opt,name=default_interface,json=defaultInterface,proto3" json:"default_interface,omitempty"`                               // Interface of the physical default route
+	Posture          *DevicePosture         `protobuf:"bytes,9,opt,name=posture,proto3" json:"posture,omitempty"`                                                                         // Security state of the host, checked against posture policies
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AgentMetadata) Reset() {
+	*x = AgentMetadata{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentMetadata) ProtoMessage() {}
+
+func (x *AgentMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentMetadata.ProtoReflect.Descriptor instead.
+func (*AgentMetadata) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AgentMetadata) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *AgentMetadata) GetInterfaces() []*NetworkInterface {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *AgentMetadata) GetDefaultGateway() string {
+	if x != nil {
+		return x.DefaultGateway
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetDefaultInterface() string {
+	if x != nil {
+		return x.DefaultInterface
+	}
+	return ""
+}
+
+func (x *AgentMetadata) GetPosture() *DevicePosture {
+	if x != nil {
+		return x.Posture
+	}
+	return nil
+}
+
+// DevicePosture is the security state of the agent host that posture
+// policies of the server require before routing and ACL rules naming them
+// apply to the agent
+type DevicePosture struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OsVersion      string                 `protobuf:"bytes,1,opt,name=os_version,json=osVersion,proto3" json:"os_version,omitempty"`                                                  // OS release, the kernel release on Linux
+	DiskEncryption PostureState           `protobuf:"varint,2,opt,name=disk_encryption,json=diskEncryption,proto3,enum=easyanylink.v2.PostureState" json:"disk_encryption,omitempty"` // Encryption of the system disk
+	Firewall       PostureState           `protobuf:"varint,3,opt,name=firewall,proto3,enum=easyanylink.v2.PostureState" json:"firewall,omitempty"`                                   // Host firewall filtering inbound traffic
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DevicePosture) Reset() {
+	*x = DevicePosture{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DevicePosture) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DevicePosture) ProtoMessage() {}
+
+func (x *DevicePosture) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DevicePosture.ProtoReflect.Descriptor instead.
+func (*DevicePosture) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DevicePosture) GetOsVersion() string {
+	if x != nil {
+		return x.OsVersion
+	}
+	return ""
+}
+
+func (x *DevicePosture) GetDiskEncryption() PostureState {
+	if x != nil {
+		return x.DiskEncryption
+	}
+	return PostureState_POSTURE_STATE_UNKNOWN
+}
+
+func (x *DevicePosture) GetFirewall() PostureState {
+	if x != nil {
+		return x.Firewall
+	}
+	return PostureState_POSTURE_STATE_UNKNOWN
+}
+
+// NetworkInterface describes a network interface of the agent host
+type NetworkInterface struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`           // Interface name
+	Addresses     []string               `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty"` // Addresses in CIDR notation
+	Mac           string                 `protobuf:"bytes,3,opt,name=mac,proto3" json:"mac,omitempty"`             // Hardware address
+	Mtu           int32                  `protobuf:"varint,4,opt,name=mtu,proto3" json:"mtu,omitempty"`            // Interface MTU
+	Up            bool                   `protobuf:"varint,5,opt,name=up,proto3" json:"up,omitempty"`              // Whether the interface is up
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkInterface) Reset() {
+	*x = NetworkInterface{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkInterface) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkInterface) ProtoMessage() {}
+
+func (x *NetworkInterface) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkInterface.ProtoReflect.Descriptor instead.
+func (*NetworkInterface) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *NetworkInterface) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *NetworkInterface) GetMac() string {
+	if x != nil {
+		return x.Mac
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetMtu() int32 {
+	if x != nil {
+		return x.Mtu
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetUp() bool {
+	if x != nil {
+		return x.Up
+	}
+	return false
+}
+
+// RegisterResponse is returned after successful registration
+type RegisterResponse struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Accepted                bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`                                                               // Whether registration was accepted
+	SessionId               string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                                             // Unique session identifier
+	AssignedIp              string                 `protobuf:"bytes,3,opt,name=assigned_ip,json=assignedIp,proto3" json:"assigned_ip,omitempty"`                                          // Assigned overlay IP address
+	ServerVersion           string                 `protobuf:"bytes,4,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`                                 // Server protocol version
+	MinimumSupportedVersion string                 `protobuf:"bytes,5,opt,name=minimum_supported_version,json=minimumSupportedVersion,proto3" json:"minimum_supported_version,omitempty"` // Minimum compatible version
+	ErrorMessage            string                 `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`                                    // Error description if not accepted
+	ServerConfig            *ServerConfig          `protobuf:"bytes,7,opt,name=server_config,json=serverConfig,proto3" json:"server_config,omitempty"`                                    // Server configuration parameters
+	ManagedConfig           *ManagedConfig         `protobuf:"bytes,8,opt,name=managed_config,json=managedConfig,proto3" json:"managed_config,omitempty"`                                 // Settings of the agent's group, unset without a group
+	Capabilities            []string               `protobuf:"bytes,9,rep,name=capabilities,proto3" json:"capabilities,omitempty"`                                                        // Optional features the server supports
+	ServerTime              *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`                                         // Server clock when the response was sent, for the agent to measure clock skew
+	ErrorCode               string                 `protobuf:"bytes,11,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`                                            // Stable EALxxxx code of error_message, see ErrorDetail
+	Servers                 []string               `protobuf:"bytes,12,rep,name=servers,proto3" json:"servers,omitempty"`                                                                 // Other servers of the deployment, host:port, for agents choosing the lowest-latency one
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResponse) ProtoMessage() {}
+
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RegisterResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *RegisterResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetAssignedIp() string {
+	if x != nil {
+		return x.AssignedIp
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetServerVersion() string {
+	if x != nil {
+		return x.ServerVersion
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetMinimumSupportedVersion() string {
+	if x != nil {
+		return x.MinimumSupportedVersion
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetServerConfig() *ServerConfig {
+	if x != nil {
+		return x.ServerConfig
+	}
+	return nil
+}
+
+func (x *RegisterResponse) GetManagedConfig() *ManagedConfig {
+	if x != nil {
+		return x.ManagedConfig
+	}
+	return nil
+}
+
+func (x *RegisterResponse) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *RegisterResponse) GetServerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ServerTime
+	}
+	return nil
+}
+
+func (x *RegisterResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetServers() []string {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+// ServerConfig contains server-side configuration
+type ServerConfig struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	GatewayIp         string                 `protobuf:"bytes,1,opt,name=gateway_ip,json=gatewayIp,proto3" json:"gateway_ip,omitempty"`                          // Server's overlay IP (usually .0.1)
+	Mtu               int32                  `protobuf:"varint,2,opt,name=mtu,proto3" json:"mtu,omitempty"`                                                      // MTU granted to the agent
+	KeepaliveInterval int32                  `protobuf:"varint,3,opt,name=keepalive_interval,json=keepaliveInterval,proto3" json:"keepalive_interval,omitempty"` // Heartbeat interval in seconds
+	KeepaliveTimeout  int32                  `protobuf:"varint,4,opt,name=keepalive_timeout,json=keepaliveTimeout,proto3" json:"keepalive_timeout,omitempty"`    // Connection timeout in seconds
+	MaxMessageSize    int32                  `protobuf:"varint,5,opt,name=max_message_size,json=maxMessageSize,proto3" json:"max_message_size,omitempty"`        // Largest gRPC message in bytes the server accepts
+	RekeyInterval     int32                  `protobuf:"varint,6,opt,name=rekey_interval,json=rekeyInterval,proto3" json:"rekey_interval,omitempty"`             // Seconds after which the agent moves the session to a connection with fresh keys, 0 disables
+	RekeyBytes        uint64                 `protobuf:"varint,7,opt,name=rekey_bytes,json=rekeyBytes,proto3" json:"rekey_bytes,omitempty"`                      // Bytes relayed after which the agent moves the session to a connection with fresh keys, 0 disables
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ServerConfig) Reset() {
+	*x = ServerConfig{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerConfig) ProtoMessage() {}
+
+func (x *ServerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerConfig.ProtoReflect.Descriptor instead.
+func (*ServerConfig) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ServerConfig) GetGatewayIp() string {
+	if x != nil {
+		return x.GatewayIp
+	}
+	return ""
+}
+
+func (x *ServerConfig) GetMtu() int32 {
+	if x != nil {
+		return x.Mtu
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetKeepaliveInterval() int32 {
+	if x != nil {
+		return x.KeepaliveInterval
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetKeepaliveTimeout() int32 {
+	if x != nil {
+		return x.KeepaliveTimeout
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetMaxMessageSize() int32 {
+	if x != nil {
+		return x.MaxMessageSize
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetRekeyInterval() int32 {
+	if x != nil {
+		return x.RekeyInterval
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetRekeyBytes() uint64 {
+	if x != nil {
+		return x.RekeyBytes
+	}
+	return 0
+}
+
+// ManagedConfig carries the settings of the agent's group, computed by the
+// server. Set settings take precedence over the agent's configuration file.
+type ManagedConfig struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Group          string                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`                                          // Group name
+	DnsServers     []string               `protobuf:"bytes,2,rep,name=dns_servers,json=dnsServers,proto3" json:"dns_servers,omitempty"`              // Resolvers used while connected, empty keeps the agent setting
+	DnsSearch      []string               `protobuf:"bytes,3,rep,name=dns_search,json=dnsSearch,proto3" json:"dns_search,omitempty"`                 // Search domains used with dns_servers
+	BandwidthLimit int32                  `protobuf:"varint,4,opt,name=bandwidth_limit,json=bandwidthLimit,proto3" json:"bandwidth_limit,omitempty"` // KB/s the server relays for the agent, 0 for unlimited
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ManagedConfig) Reset() {
+	*x = ManagedConfig{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManagedConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManagedConfig) ProtoMessage() {}
+
+func (x *ManagedConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManagedConfig.ProtoReflect.Descriptor instead.
+func (*ManagedConfig) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ManagedConfig) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ManagedConfig) GetDnsServers() []string {
+	if x != nil {
+		return x.DnsServers
+	}
+	return nil
+}
+
+func (x *ManagedConfig) GetDnsSearch() []string {
+	if x != nil {
+		return x.DnsSearch
+	}
+	return nil
+}
+
+func (x *ManagedConfig) GetBandwidthLimit() int32 {
+	if x != nil {
+		return x.BandwidthLimit
+	}
+	return 0
+}
+
+// HeartbeatRequest is sent periodically to maintain connection
+type HeartbeatRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SessionId        string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                       // Session identifier
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                        // Current timestamp
+	Stats            *AgentStats            `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`                                                // Agent statistics
+	Metadata         *AgentMetadata         `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`                                          // Refreshed metadata, set only when it changed
+	Health           *AgentHealth           `protobuf:"bytes,5,opt,name=health,proto3" json:"health,omitempty"`                                              // Failing subsystems, unset while all are healthy
+	ConfigGeneration uint64                 `protobuf:"varint,6,opt,name=config_generation,json=configGeneration,proto3" json:"config_generation,omitempty"` // Config generation of the route snapshot the agent applied, see RouteResponse
+	ClockSkewMs      int64                  `protobuf:"varint,7,opt,name=clock_skew_ms,json=clockSkewMs,proto3" json:"clock_skew_ms,omitempty"`              // Agent clock minus server clock in milliseconds, measured with the previous heartbeat
+	Sites            *SiteUpdate            `protobuf:"bytes,8,opt,name=sites,proto3" json:"sites,omitempty"`                                                // Sites of a gateway, set only when they changed since registration
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *HeartbeatRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetStats() *AgentStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetMetadata() *AgentMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetHealth() *AgentHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetConfigGeneration() uint64 {
+	if x != nil {
+		return x.ConfigGeneration
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetClockSkewMs() int64 {
+	if x != nil {
+		return x.ClockSkewMs
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetSites() *SiteUpdate {
+	if x != nil {
+		return x.Sites
+	}
+	return nil
+}
+
+// SiteUpdate replaces the sites a gateway advertises to the site mesh, e.g.
+// after it learned LAN prefixes over BGP
+type SiteUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subnets       []string               `protobuf:"bytes,1,rep,name=subnets,proto3" json:"subnets,omitempty"` // IPv4 CIDRs, empty leaves the mesh
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SiteUpdate) Reset() {
+	*x = SiteUpdate{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SiteUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SiteUpdate) ProtoMessage() {}
+
+func (x *SiteUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SiteUpdate.ProtoReflect.Descriptor instead.
+func (*SiteUpdate) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SiteUpdate) GetSubnets() []string {
+	if x != nil {
+		return x.Subnets
+	}
+	return nil
+}
+
+// AgentHealth reports agent subsystems that failed and were restarted
+type AgentHealth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Degraded      bool                   `protobuf:"varint,1,opt,name=degraded,proto3" json:"degraded,omitempty"`    // A subsystem keeps failing despite restarts
+	Subsystems    []*SubsystemHealth     `protobuf:"bytes,2,rep,name=subsystems,proto3" json:"subsystems,omitempty"` // Subsystems that failed since the agent started
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentHealth) Reset() {
+	*x = AgentHealth{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentHealth) ProtoMessage() {}
+
+func (x *AgentHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentHealth.ProtoReflect.Descriptor instead.
+func (*AgentHealth) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AgentHealth) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+func (x *AgentHealth) GetSubsystems() []*SubsystemHealth {
+	if x != nil {
+		return x.Subsystems
+	}
+	return nil
+}
+
+// SubsystemHealth is the state of one supervised agent subsystem
+type SubsystemHealth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                  // e.g. tun-reader-0 or network-monitor
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`                                // running, restarting or degraded
+	Restarts      uint32                 `protobuf:"varint,3,opt,name=restarts,proto3" json:"restarts,omitempty"`                         // Restarts since the agent started
+	LastError     string                 `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`       // Error or panic of the last failure
+	LastFailure   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_failure,json=lastFailure,proto3" json:"last_failure,omitempty"` // Time of the last failure
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubsystemHealth) Reset() {
+	*x = SubsystemHealth{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubsystemHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubsystemHealth) ProtoMessage() {}
+
+func (x *SubsystemHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubsystemHealth.ProtoReflect.Descriptor instead.
+func (*SubsystemHealth) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SubsystemHealth) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SubsystemHealth) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *SubsystemHealth) GetRestarts() uint32 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
+}
+
+func (x *SubsystemHealth) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *SubsystemHealth) GetLastFailure() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastFailure
+	}
+	return nil
+}
+
+// AgentStats contains performance and traffic metrics
+type AgentStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	BytesSent       uint64                 `protobuf:"varint,1,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`                   // Total bytes sent
+	BytesReceived   uint64                 `protobuf:"varint,2,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`       // Total bytes received
+	PacketsSent     uint64                 `protobuf:"varint,3,opt,name=packets_sent,json=packetsSent,proto3" json:"packets_sent,omitempty"`             // Total packets sent
+	PacketsReceived uint64                 `protobuf:"varint,4,opt,name=packets_received,json=packetsReceived,proto3" json:"packets_received,omitempty"` // Total packets received
+	Errors          uint32                 `protobuf:"varint,5,opt,name=errors,proto3" json:"errors,omitempty"`                                          // Error count
+	Drops           uint32                 `protobuf:"varint,6,opt,name=drops,proto3" json:"drops,omitempty"`                                            // Dropped packet count
+	Classes         []*TrafficClassStats   `protobuf:"bytes,9,rep,name=classes,proto3" json:"classes,omitempty"`                                         // Relay send queue counters per traffic class
+	Rekeys          uint32                 `protobuf:"varint,10,opt,name=rekeys,proto3" json:"rekeys,omitempty"`                                         // Moves of the session to a connection with fresh keys
+	KeyUpdates      uint64                 `protobuf:"varint,11,opt,name=key_updates,json=keyUpdates,proto3" json:"key_updates,omitempty"`               // QUIC key updates of the session's connections
+	LocalUsers      []*LocalUserStats      `protobuf:"bytes,12,rep,name=local_users,json=localUsers,proto3" json:"local_users,omitempty"`                // Relayed traffic per local user, from agents tagging flows with source uids
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AgentStats) Reset() {
+	*x = AgentStats{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentStats) ProtoMessage() {}
+
+func (x *AgentStats) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentStats.ProtoReflect.Descriptor instead.
+func (*AgentStats) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AgentStats) GetBytesSent() uint64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *AgentStats) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *AgentStats) GetPacketsSent() uint64 {
+	if x != nil {
+		return x.PacketsSent
+	}
+	return 0
+}
+
+func (x *AgentStats) GetPacketsReceived() uint64 {
+	if x != nil {
+		return x.PacketsReceived
+	}
+	return 0
+}
+
+func (x *AgentStats) GetErrors() uint32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *AgentStats) GetDrops() uint32 {
+	if x != nil {
+		return x.Drops
+	}
+	return 0
+}
+
+func (x *AgentStats) GetClasses() []*TrafficClassStats {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+func (x *AgentStats) GetRekeys() uint32 {
+	if x != nil {
+		return x.Rekeys
+	}
+	return 0
+}
+
+func (x *AgentStats) GetKeyUpdates() uint64 {
+	if x != nil {
+		return x.KeyUpdates
+	}
+	return 0
+}
+
+func (x *AgentStats) GetLocalUsers() []*LocalUserStats {
+	if x != nil {
+		return x.LocalUsers
+	}
+	return nil
+}
+
+// TrafficClassStats counts the relayed packets of one traffic class
+type TrafficClassStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Class         string                 `protobuf:"bytes,1,opt,name=class,proto3" json:"class,omitempty"`      // interactive, default or bulk
+	Queued        uint64                 `protobuf:"varint,2,opt,name=queued,proto3" json:"queued,omitempty"`   // Packets queued for sending
+	Dropped       uint64                 `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"` // Packets dropped because the class queue was full
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrafficClassStats) Reset() {
+	*x = TrafficClassStats{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrafficClassStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrafficClassStats) ProtoMessage() {}
+
+func (x *TrafficClassStats) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrafficClassStats.ProtoReflect.Descriptor instead.
+func (*TrafficClassStats) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TrafficClassStats) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *TrafficClassStats) GetQueued() uint64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *TrafficClassStats) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+// LocalUserStats counts the relayed traffic of one local user of a shared
+// agent host
+type LocalUserStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Uid             uint32                 `protobuf:"varint,1,opt,name=uid,proto3" json:"uid,omitempty"`  // Local user id
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // User name, empty if the uid has none
+	BytesSent       uint64                 `protobuf:"varint,3,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`
+	BytesReceived   uint64                 `protobuf:"varint,4,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	PacketsSent     uint64                 `protobuf:"varint,5,opt,name=packets_sent,json=packetsSent,proto3" json:"packets_sent,omitempty"`
+	PacketsReceived uint64                 `protobuf:"varint,6,opt,name=packets_received,json=packetsReceived,proto3" json:"packets_received,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LocalUserStats) Reset() {
+	*x = LocalUserStats{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocalUserStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocalUserStats) ProtoMessage() {}
+
+func (x *LocalUserStats) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocalUserStats.ProtoReflect.Descriptor instead.
+func (*LocalUserStats) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LocalUserStats) GetUid() uint32 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+func (x *LocalUserStats) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LocalUserStats) GetBytesSent() uint64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *LocalUserStats) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *LocalUserStats) GetPacketsSent() uint64 {
+	if x != nil {
+		return x.PacketsSent
+	}
+	return 0
+}
+
+func (x *LocalUserStats) GetPacketsReceived() uint64 {
+	if x != nil {
+		return x.PacketsReceived
+	}
+	return 0
+}
+
+// HeartbeatResponse acknowledges heartbeat
+type HeartbeatResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Alive               bool                   `protobuf:"varint,1,opt,name=alive,proto3" json:"alive,omitempty"`                                                          // Server is alive
+	Timestamp           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                                   // Server timestamp
+	ShouldRefreshRoutes bool                   `protobuf:"varint,3,opt,name=should_refresh_routes,json=shouldRefreshRoutes,proto3" json:"should_refresh_routes,omitempty"` // Client should re-fetch routes
+	Message             string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`                                                       // Optional message from server
+	KeepaliveInterval   int32                  `protobuf:"varint,5,opt,name=keepalive_interval,json=keepaliveInterval,proto3" json:"keepalive_interval,omitempty"`         // Current heartbeat interval in seconds, 0 keeps the negotiated one
+	KeepaliveTimeout    int32                  `protobuf:"varint,6,opt,name=keepalive_timeout,json=keepaliveTimeout,proto3" json:"keepalive_timeout,omitempty"`            // Current dead-link timeout in seconds, 0 keeps the negotiated one
+	HandoffServer       string                 `protobuf:"bytes,7,opt,name=handoff_server,json=handoffServer,proto3" json:"handoff_server,omitempty"`                      // Server to move the session to, host:port, continued there with its overlay IP, see CapabilitySessionHandoff
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HeartbeatResponse) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+func (x *HeartbeatResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *HeartbeatResponse) GetShouldRefreshRoutes() bool {
+	if x != nil {
+		return x.ShouldRefreshRoutes
+	}
+	return false
+}
+
+func (x *HeartbeatResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *HeartbeatResponse) GetKeepaliveInterval() int32 {
+	if x != nil {
+		return x.KeepaliveInterval
+	}
+	return 0
+}
+
+func (x *HeartbeatResponse) GetKeepaliveTimeout() int32 {
+	if x != nil {
+		return x.KeepaliveTimeout
+	}
+	return 0
+}
+
+func (x *HeartbeatResponse) GetHandoffServer() string {
+	if x != nil {
+		return x.HandoffServer
+	}
+	return ""
+}
+
+// DataPacket represents an IP packet being relayed
+type DataPacket struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	SessionId          string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                              // Session identifier
+	SourceAgentId      string                 `protobuf:"bytes,2,opt,name=source_agent_id,json=sourceAgentId,proto3" json:"source_agent_id,omitempty"`                // Source agent UUID
+	DestinationAgentId string                 `protobuf:"bytes,3,opt,name=destination_agent_id,json=destinationAgentId,proto3" json:"destination_agent_id,omitempty"` // Destination agent UUID (empty for gateway)
+	Payload            []byte                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`                                                   // IP packet data
+	Echo               *EchoProbe             `protobuf:"bytes,7,opt,name=echo,proto3" json:"echo,omitempty"`                                                         // Overlay echo probe, carried instead of a payload
+	Padding            []byte                 `protobuf:"bytes,8,opt,name=padding,proto3" json:"padding,omitempty"`                                                   // Filler bringing the message to a padded size, ignored by receivers
+	SourceUid          *uint32                `protobuf:"varint,9,opt,name=source_uid,json=sourceUid,proto3,oneof" json:"source_uid,omitempty"`                       // Local user whose socket sent the payload, set by agents tagging flows on shared hosts
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DataPacket) Reset() {
+	*x = DataPacket{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataPacket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataPacket) ProtoMessage() {}
+
+func (x *DataPacket) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataPacket.ProtoReflect.Descriptor instead.
+func (*DataPacket) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DataPacket) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *DataPacket) GetSourceAgentId() string {
+	if x != nil {
+		return x.SourceAgentId
+	}
+	return ""
+}
+
+func (x *DataPacket) GetDestinationAgentId() string {
+	if x != nil {
+		return x.DestinationAgentId
+	}
+	return ""
+}
+
+func (x *DataPacket) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DataPacket) GetEcho() *EchoProbe {
+	if x != nil {
+		return x.Echo
+	}
+	return nil
+}
+
+func (x *DataPacket) GetPadding() []byte {
+	if x != nil {
+		return x.Padding
+	}
+	return nil
+}
+
+func (x *DataPacket) GetSourceUid() uint32 {
+	if x != nil && x.SourceUid != nil {
+		return *x.SourceUid
+	}
+	return 0
+}
+
+// EchoProbe is an overlay-level ping that does not depend on OS ICMP or routes
+type EchoProbe struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        string                 `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`               // Overlay IP or agent name (requests only)
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`                      // Probe identifier chosen by the sender
+	Reply         bool                   `protobuf:"varint,3,opt,name=reply,proto3" json:"reply,omitempty"`                // Set on responses
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"` // Echoed back unchanged
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`                 // Set by the server when the target is unreachable
+	Address       string                 `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`             // Overlay IP of the target, set by the server on forwarded probes and echoed back in replies
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EchoProbe) Reset() {
+	*x = EchoProbe{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EchoProbe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EchoProbe) ProtoMessage() {}
+
+func (x *EchoProbe) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EchoProbe.ProtoReflect.Descriptor instead.
+func (*EchoProbe) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EchoProbe) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *EchoProbe) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EchoProbe) GetReply() bool {
+	if x != nil {
+		return x.Reply
+	}
+	return false
+}
+
+func (x *EchoProbe) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *EchoProbe) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *EchoProbe) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+// IdentityChallengeRequest asks for a challenge to sign with a hardware
+// identity key
+type IdentityChallengeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Agent UUID, the challenge is only valid for its registrations
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdentityChallengeRequest) Reset() {
+	*x = IdentityChallengeRequest{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdentityChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdentityChallengeRequest) ProtoMessage() {}
+
+func (x *IdentityChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdentityChallengeRequest.ProtoReflect.Descriptor instead.
+func (*IdentityChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *IdentityChallengeRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// IdentityChallenge is a random challenge accepted once, in a
+// registration of the agent it was issued to
+type IdentityChallenge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Challenge     []byte                 `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`                   // Random bytes to sign
+	ExpiresIn     int32                  `protobuf:"varint,2,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"` // Seconds the challenge is valid for
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdentityChallenge) Reset() {
+	*x = IdentityChallenge{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdentityChallenge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdentityChallenge) ProtoMessage() {}
+
+func (x *IdentityChallenge) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdentityChallenge.ProtoReflect.Descriptor instead.
+func (*IdentityChallenge) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *IdentityChallenge) GetChallenge() []byte {
+	if x != nil {
+		return x.Challenge
+	}
+	return nil
+}
+
+func (x *IdentityChallenge) GetExpiresIn() int32 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+// TrustBundleRequest asks for the trust bundle of the server
+type TrustBundleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrustBundleRequest) Reset() {
+	*x = TrustBundleRequest{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustBundleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustBundleRequest) ProtoMessage() {}
+
+func (x *TrustBundleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustBundleRequest.ProtoReflect.Descriptor instead.
+func (*TrustBundleRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{19}
+}
+
+// TrustBundleResponse contains the PEM encoded CA certificates
+type TrustBundleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bundle        []byte                 `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`           // PEM encoded CA certificates
+	Fingerprint   string                 `protobuf:"bytes,2,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"` // SHA256 fingerprint of the first certificate
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrustBundleResponse) Reset() {
+	*x = TrustBundleResponse{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustBundleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustBundleResponse) ProtoMessage() {}
+
+func (x *TrustBundleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustBundleResponse.ProtoReflect.Descriptor instead.
+func (*TrustBundleResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TrustBundleResponse) GetBundle() []byte {
+	if x != nil {
+		return x.Bundle
+	}
+	return nil
+}
+
+func (x *TrustBundleResponse) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+// RouteRequest asks for routing configuration
+type RouteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Session identifier
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`       // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteRequest) Reset() {
+	*x = RouteRequest{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteRequest) ProtoMessage() {}
+
+func (x *RouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteRequest.ProtoReflect.Descriptor instead.
+func (*RouteRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RouteRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RouteRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// RouteResponse provides routing rules
+type RouteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*RoutingRule         `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`                                      // List of routing rules
+	ManagedConfig *ManagedConfig         `protobuf:"bytes,3,opt,name=managed_config,json=managedConfig,proto3" json:"managed_config,omitempty"` // Settings of the agent's group, unset without a group
+	Generation    uint64                 `protobuf:"varint,4,opt,name=generation,proto3" json:"generation,omitempty"`                           // Config generation of this snapshot, grows with every change of routes or group settings
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteResponse) Reset() {
+	*x = RouteResponse{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteResponse) ProtoMessage() {}
+
+func (x *RouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteResponse.ProtoReflect.Descriptor instead.
+func (*RouteResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RouteResponse) GetRules() []*RoutingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *RouteResponse) GetManagedConfig() *ManagedConfig {
+	if x != nil {
+		return x.ManagedConfig
+	}
+	return nil
+}
+
+func (x *RouteResponse) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+// RoutingRule defines a routing policy
+type RoutingRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        int32                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`                   // Rule identifier, 0 for a route to the site of another gateway
+	Action        RouteAction            `protobuf:"varint,2,opt,name=action,proto3,enum=easyanylink.v2.RouteAction" json:"action,omitempty"` // Action to take
+	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`                        // Destination CIDR
+	GatewayId     string                 `protobuf:"bytes,4,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"`           // Gateway agent ID (for forward action)
+	Priority      int32                  `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`                             // Rule priority (lower = higher priority)
+	Enabled       bool                   `protobuf:"varint,6,opt,name=enabled,proto3" json:"enabled,omitempty"`                               // Whether rule is active
+	Window        *AccessWindow          `protobuf:"bytes,7,opt,name=window,proto3" json:"window,omitempty"`                                  // When the rule applies, unset for always
+	Posture       string                 `protobuf:"bytes,8,opt,name=posture,proto3" json:"posture,omitempty"`                                // Posture policy the agent must meet for the rule to apply, empty for none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoutingRule) Reset() {
+	*x = RoutingRule{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoutingRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingRule) ProtoMessage() {}
+
+func (x *RoutingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingRule.ProtoReflect.Descriptor instead.
+func (*RoutingRule) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RoutingRule) GetRuleId() int32 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+func (x *RoutingRule) GetAction() RouteAction {
+	if x != nil {
+		return x.Action
+	}
+	return RouteAction_ROUTE_ACTION_UNSPECIFIED
+}
+
+func (x *RoutingRule) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetGatewayId() string {
+	if x != nil {
+		return x.GatewayId
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *RoutingRule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RoutingRule) GetWindow() *AccessWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetPosture() string {
+	if x != nil {
+		return x.Posture
+	}
+	return ""
+}
+
+// AccessWindow limits when a rule applies
+type AccessWindow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ValidFrom     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`    // Start of validity, unset for no start
+	ValidUntil    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"` // End of validity, unset for no end
+	Schedule      string                 `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"`                       // Weekly schedule, e.g. "mon-fri 09:00-18:00 Europe/Berlin"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessWindow) Reset() {
+	*x = AccessWindow{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessWindow) ProtoMessage() {}
+
+func (x *AccessWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessWindow.ProtoReflect.Descriptor instead.
+func (*AccessWindow) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AccessWindow) GetValidFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return nil
+}
+
+func (x *AccessWindow) GetValidUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return nil
+}
+
+func (x *AccessWindow) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+// StatusUpdate allows agents to report status changes
+type StatusUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`           // Session identifier
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                 // Agent UUID
+	Status        AgentStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=easyanylink.v2.AgentStatus" json:"status,omitempty"` // New status
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`                                // Optional status message
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusUpdate) Reset() {
+	*x = StatusUpdate{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusUpdate) ProtoMessage() {}
+
+func (x *StatusUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusUpdate.ProtoReflect.Descriptor instead.
+func (*StatusUpdate) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *StatusUpdate) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *StatusUpdate) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *StatusUpdate) GetStatus() AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
+func (x *StatusUpdate) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// SessionEnded is attached to the error status of calls on a session the
+// server ended recently, telling the agent why
+type SessionEnded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        DisconnectReason       `protobuf:"varint,1,opt,name=reason,proto3,enum=easyanylink.v2.DisconnectReason" json:"reason,omitempty"` // Reason code
+	Detail        string                 `protobuf:"bytes,2,opt,name=detail,proto3" json:"detail,omitempty"`                                       // Human-readable detail
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionEnded) Reset() {
+	*x = SessionEnded{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionEnded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionEnded) ProtoMessage() {}
+
+func (x *SessionEnded) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionEnded.ProtoReflect.Descriptor instead.
+func (*SessionEnded) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SessionEnded) GetReason() DisconnectReason {
+	if x != nil {
+		return x.Reason
+	}
+	return DisconnectReason_DISCONNECT_REASON_UNSPECIFIED
+}
+
+func (x *SessionEnded) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+// ServerBusy is attached to the RESOURCE_EXHAUSTED status of a registration
+// turned away because the server reached its session limit
+type ServerBusy struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RetryAfter       int32                  `protobuf:"varint,1,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`                  // Seconds before retrying this server
+	AlternateServers []string               `protobuf:"bytes,2,rep,name=alternate_servers,json=alternateServers,proto3" json:"alternate_servers,omitempty"` // Servers to try meanwhile, host:port
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ServerBusy) Reset() {
+	*x = ServerBusy{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerBusy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerBusy) ProtoMessage() {}
+
+func (x *ServerBusy) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerBusy.ProtoReflect.Descriptor instead.
+func (*ServerBusy) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ServerBusy) GetRetryAfter() int32 {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return 0
+}
+
+func (x *ServerBusy) GetAlternateServers() []string {
+	if x != nil {
+		return x.AlternateServers
+	}
+	return nil
+}
+
+// ErrorDetail is attached to error statuses with a stable code of the
+// error, for tooling to key off and clients to localize the message by.
+// The codes are listed in common/errcode.
+type ErrorDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"` // e.g. "EAL1001"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorDetail) Reset() {
+	*x = ErrorDetail{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorDetail) ProtoMessage() {}
+
+func (x *ErrorDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorDetail.ProtoReflect.Descriptor instead.
+func (*ErrorDetail) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ErrorDetail) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// StatusResponse acknowledges status update
+type StatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Acknowledged  bool                   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"` // Update was received
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`            // Optional response message
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *StatusResponse) GetAcknowledged() bool {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return false
+}
+
+func (x *StatusResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// RequestAccessRequest asks for temporary access to a destination through
+// the relay
+type RequestAccessRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Session identifier
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`       // Agent UUID
+	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`              // IPv4 CIDR, or an address for a single host
+	Protocol      string                 `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`                    // "any", "tcp", "udp" or "icmp", default "any"
+	PortFrom      uint32                 `protobuf:"varint,5,opt,name=port_from,json=portFrom,proto3" json:"port_from,omitempty"`   // First destination port, 0 for any
+	PortTo        uint32                 `protobuf:"varint,6,opt,name=port_to,json=portTo,proto3" json:"port_to,omitempty"`         // Last destination port, 0 for port_from only
+	Duration      int32                  `protobuf:"varint,7,opt,name=duration,proto3" json:"duration,omitempty"`                   // Seconds of access asked for
+	Reason        string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`                        // Justification shown to the approving admin
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestAccessRequest) Reset() {
+	*x = RequestAccessRequest{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestAccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestAccessRequest) ProtoMessage() {}
+
+func (x *RequestAccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestAccessRequest.ProtoReflect.Descriptor instead.
+func (*RequestAccessRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RequestAccessRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RequestAccessRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RequestAccessRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *RequestAccessRequest) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *RequestAccessRequest) GetPortFrom() uint32 {
+	if x != nil {
+		return x.PortFrom
+	}
+	return 0
+}
+
+func (x *RequestAccessRequest) GetPortTo() uint32 {
+	if x != nil {
+		return x.PortTo
+	}
+	return 0
+}
+
+func (x *RequestAccessRequest) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RequestAccessRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// AccessRequestsRequest identifies the agent whose access requests to get
+type AccessRequestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Session identifier
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`       // Agent UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessRequestsRequest) Reset() {
+	*x = AccessRequestsRequest{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessRequestsRequest) ProtoMessage() {}
+
+func (x *AccessRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessRequestsRequest.ProtoReflect.Descriptor instead.
+func (*AccessRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *AccessRequestsRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *AccessRequestsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// AccessRequestsResponse returns access requests, newest first
+type AccessRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*AccessRequest       `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessRequestsResponse) Reset() {
+	*x = AccessRequestsResponse{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessRequestsResponse) ProtoMessage() {}
+
+func (x *AccessRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessRequestsResponse.ProtoReflect.Descriptor instead.
+func (*AccessRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *AccessRequestsResponse) GetRequests() []*AccessRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// AccessRequest is a request of an agent for temporary access. Approving
+// it grants the access through an ACL rule, and a routing rule when the
+// admin names a gateway, that are removed when the grant expires.
+type AccessRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     int32                  `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // Request identifier
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`        // Agent that asked for access
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`           // Owner of the agent, whose ACL rules get the grant
+	Destination   string                 `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`               // Destination CIDR
+	Protocol      string                 `protobuf:"bytes,5,opt,name=protocol,proto3" json:"protocol,omitempty"`                     // "any", "tcp", "udp" or "icmp"
+	PortFrom      uint32                 `protobuf:"varint,6,opt,name=port_from,json=portFrom,proto3" json:"port_from,omitempty"`    // First destination port, 0 for any
+	PortTo        uint32                 `protobuf:"varint,7,opt,name=port_to,json=portTo,proto3" json:"port_to,omitempty"`          // Last destination port
+	Duration      int32                  `protobuf:"varint,8,opt,name=duration,proto3" json:"duration,omitempty"`                    // Seconds of access asked for
+	Reason        string                 `protobuf:"bytes,9,opt,name=reason,proto3" json:"reason,omitempty"`                         // Justification of the request
+	State         string                 `protobuf:"bytes,10,opt,name=state,proto3" json:"state,omitempty"`                          // "pending", "approved", "denied" or "expired"
+	RequestedAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
+	DecidedBy     string                 `protobuf:"bytes,12,opt,name=decided_by,json=decidedBy,proto3" json:"decided_by,omitempty"` // Admin who approved or denied the request
+	DecidedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=decided_at,json=decidedAt,proto3" json:"decided_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                // End of the grant of an approved request
+	AclRuleId     int32                  `protobuf:"varint,15,opt,name=acl_rule_id,json=aclRuleId,proto3" json:"acl_rule_id,omitempty"`             // ACL rule of the grant, while approved
+	RoutingRuleId int32                  `protobuf:"varint,16,opt,name=routing_rule_id,json=routingRuleId,proto3" json:"routing_rule_id,omitempty"` // Routing rule of the grant, 0 for none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessRequest) Reset() {
+	*x = AccessRequest{}
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessRequest) ProtoMessage() {}
+
+func (x *AccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_easyanylink_v2_agent_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessRequest.ProtoReflect.Descriptor instead.
+func (*AccessRequest) Descriptor() ([]byte, []int) {
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *AccessRequest) GetRequestId() int32 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *AccessRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetPortFrom() uint32 {
+	if x != nil {
+		return x.PortFrom
+	}
+	return 0
+}
+
+func (x *AccessRequest) GetPortTo() uint32 {
+	if x != nil {
+		return x.PortTo
+	}
+	return 0
+}
+
+func (x *AccessRequest) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *AccessRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetRequestedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RequestedAt
+	}
+	return nil
+}
+
+func (x *AccessRequest) GetDecidedBy() string {
+	if x != nil {
+		return x.DecidedBy
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetDecidedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DecidedAt
+	}
+	return nil
+}
+
+func (x *AccessRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *AccessRequest) GetAclRuleId() int32 {
+	if x != nil {
+		return x.AclRuleId
+	}
+	return 0
+}
+
+func (x *AccessRequest) GetRoutingRuleId() int32 {
+	if x != nil {
+		return x.RoutingRuleId
+	}
+	return 0
+}
+
+var File_common_proto_easyanylink_v2_agent_proto protoreflect.FileDescriptor
+
+const file_common_proto_easyanylink_v2_agent_proto_rawDesc = "" +
+	"\n" +
+	"'common/proto/easyanylink/v2/agent.proto\x12\x0eeasyanylink.v2\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf9\x05\n" +
+	"\x0fRegisterRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x19\n" +
+	"\buser_key\x18\x02 \x01(\tR\auserKey\x12-\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x19.easyanylink.v2.AgentTypeR\x04type\x12)\n" +
+	"\x10protocol_version\x18\x04 \x01(\tR\x0fprotocolVersion\x12;\n" +
+	"\x17certificate_fingerprint\x18\x05 \x01(\tB\x02\x18\x01R\x16certificateFingerprint\x129\n" +
+	"\bmetadata\x18\x06 \x01(\v2\x1d.easyanylink.v2.AgentMetadataR\bmetadata\x12\x1c\n" +
+	"\tbandwidth\x18\a \x01(\x05R\tbandwidth\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tR\trequestId\x12\x14\n" +
+	"\x05nonce\x18\t \x01(\fR\x05nonce\x12\x1c\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\x03R\ttimestamp\x12\x10\n" +
+	"\x03mac\x18\v \x01(\fR\x03mac\x12\"\n" +
+	"\fcapabilities\x18\f \x03(\tR\fcapabilities\x12\x10\n" +
+	"\x03mtu\x18\r \x01(\x05R\x03mtu\x12(\n" +
+	"\x10max_message_size\x18\x0e \x01(\x05R\x0emaxMessageSize\x12!\n" +
+	"\fsite_subnets\x18\x0f \x03(\tR\vsiteSubnets\x12*\n" +
+	"\x11resume_session_id\x18\x10 \x01(\tR\x0fresumeSessionId\x12!\n" +
+	"\fidentity_key\x18\x11 \x01(\fR\videntityKey\x12+\n" +
+	"\x11identity_provider\x18\x12 \x01(\tR\x10identityProvider\x12-\n" +
+	"\x12identity_challenge\x18\x13 \x01(\fR\x11identityChallenge\x12-\n" +
+	"\x12identity_signature\x18\x14 \x01(\fR\x11identitySignature\"\xb8\x03\n" +
+	"\rAgentMetadata\x12\x0e\n" +
+	"\x02os\x18\x01 \x01(\tR\x02os\x12\x12\n" +
+	"\x04arch\x18\x02 \x01(\tR\x04arch\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x1a\n" +
+	"\bhostname\x18\x04 \x01(\tR\bhostname\x12A\n" +
+	"\x06labels\x18\x05 \x03(\v2).easyanylink.v2.AgentMetadata.LabelsEntryR\x06labels\x12@\n" +
+	"\n" +
+	"interfaces\x18\x06 \x03(\v2 .easyanylink.v2.NetworkInterfaceR\n" +
+	"interfaces\x12'\n" +
+	"\x0fdefault_gateway\x18\a \x01(\tR\x0edefaultGateway\x12+\n" +
+	"\x11default_interface\x18\b \x01(\tR\x10defaultInterface\x127\n" +
+	"\aposture\x18\t \x01(\v2\x1d.easyanylink.v2.DevicePostureR\aposture\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xaf\x01\n" +
+	"\rDevicePosture\x12\x1d\n" +
+	"\n" +
+	"os_version\x18\x01 \x01(\tR\tosVersion\x12E\n" +
+	"\x0fdisk_encryption\x18\x02 \x01(\x0e2\x1c.easyanylink.v2.PostureStateR\x0ediskEncryption\x128\n" +
+	"\bfirewall\x18\x03 \x01(\x0e2\x1c.easyanylink.v2.PostureStateR\bfirewall\"x\n" +
+	"\x10NetworkInterface\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\taddresses\x18\x02 \x03(\tR\taddresses\x12\x10\n" +
+	"\x03mac\x18\x03 \x01(\tR\x03mac\x12\x10\n" +
+	"\x03mtu\x18\x04 \x01(\x05R\x03mtu\x12\x0e\n" +
+	"\x02up\x18\x05 \x01(\bR\x02up\"\x99\x04\n" +
+	"\x10RegisterResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1f\n" +
+	"\vassigned_ip\x18\x03 \x01(\tR\n" +
+	"assignedIp\x12%\n" +
+	"\x0eserver_version\x18\x04 \x01(\tR\rserverVersion\x12:\n" +
+	"\x19minimum_supported_version\x18\x05 \x01(\tR\x17minimumSupportedVersion\x12#\n" +
+	"\rerror_message\x18\x06 \x01(\tR\ferrorMessage\x12A\n" +
+	"\rserver_config\x18\a \x01(\v2\x1c.easyanylink.v2.ServerConfigR\fserverConfig\x12D\n" +
+	"\x0emanaged_config\x18\b \x01(\v2\x1d.easyanylink.v2.ManagedConfigR\rmanagedConfig\x12\"\n" +
+	"\fcapabilities\x18\t \x03(\tR\fcapabilities\x12;\n" +
+	"\vserver_time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"serverTime\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\v \x01(\tR\terrorCode\x12\x18\n" +
+	"\aservers\x18\f \x03(\tR\aservers\"\x8d\x02\n" +
+	"\fServerConfig\x12\x1d\n" +
+	"\n" +
+	"gateway_ip\x18\x01 \x01(\tR\tgatewayIp\x12\x10\n" +
+	"\x03mtu\x18\x02 \x01(\x05R\x03mtu\x12-\n" +
+	"\x12keepalive_interval\x18\x03 \x01(\x05R\x11keepaliveInterval\x12+\n" +
+	"\x11keepalive_timeout\x18\x04 \x01(\x05R\x10keepaliveTimeout\x12(\n" +
+	"\x10max_message_size\x18\x05 \x01(\x05R\x0emaxMessageSize\x12%\n" +
+	"\x0erekey_interval\x18\x06 \x01(\x05R\rrekeyInterval\x12\x1f\n" +
+	"\vrekey_bytes\x18\a \x01(\x04R\n" +
+	"rekeyBytes\"\x8e\x01\n" +
+	"\rManagedConfig\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x1f\n" +
+	"\vdns_servers\x18\x02 \x03(\tR\n" +
+	"dnsServers\x12\x1d\n" +
+	"\n" +
+	"dns_search\x18\x03 \x03(\tR\tdnsSearch\x12'\n" +
+	"\x0fbandwidth_limit\x18\x04 \x01(\x05R\x0ebandwidthLimit\"\x90\x03\n" +
+	"\x10HeartbeatRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x120\n" +
+	"\x05stats\x18\x03 \x01(\v2\x1a.easyanylink.v2.AgentStatsR\x05stats\x129\n" +
+	"\bmetadata\x18\x04 \x01(\v2\x1d.easyanylink.v2.AgentMetadataR\bmetadata\x123\n" +
+	"\x06health\x18\x05 \x01(\v2\x1b.easyanylink.v2.AgentHealthR\x06health\x12+\n" +
+	"\x11config_generation\x18\x06 \x01(\x04R\x10configGeneration\x12\"\n" +
+	"\rclock_skew_ms\x18\a \x01(\x03R\vclockSkewMs\x120\n" +
+	"\x05sites\x18\b \x01(\v2\x1a.easyanylink.v2.SiteUpdateR\x05sites\"&\n" +
+	"\n" +
+	"SiteUpdate\x12\x18\n" +
+	"\asubnets\x18\x01 \x03(\tR\asubnets\"j\n" +
+	"\vAgentHealth\x12\x1a\n" +
+	"\bdegraded\x18\x01 \x01(\bR\bdegraded\x12?\n" +
+	"\n" +
+	"subsystems\x18\x02 \x03(\v2\x1f.easyanylink.v2.SubsystemHealthR\n" +
+	"subsystems\"\xb5\x01\n" +
+	"\x0fSubsystemHealth\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x1a\n" +
+	"\brestarts\x18\x03 \x01(\rR\brestarts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x04 \x01(\tR\tlastError\x12=\n" +
+	"\flast_failure\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vlastFailure\"\xaa\x03\n" +
+	"\n" +
+	"AgentStats\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x01 \x01(\x04R\tbytesSent\x12%\n" +
+	"\x0ebytes_received\x18\x02 \x01(\x04R\rbytesReceived\x12!\n" +
+	"\fpackets_sent\x18\x03 \x01(\x04R\vpacketsSent\x12)\n" +
+	"\x10packets_received\x18\x04 \x01(\x04R\x0fpacketsReceived\x12\x16\n" +
+	"\x06errors\x18\x05 \x01(\rR\x06errors\x12\x14\n" +
+	"\x05drops\x18\x06 \x01(\rR\x05drops\x12;\n" +
+	"\aclasses\x18\t \x03(\v2!.easyanylink.v2.TrafficClassStatsR\aclasses\x12\x16\n" +
+	"\x06rekeys\x18\n" +
+	" \x01(\rR\x06rekeys\x12\x1f\n" +
+	"\vkey_updates\x18\v \x01(\x04R\n" +
+	"keyUpdates\x12?\n" +
+	"\vlocal_users\x18\f \x03(\v2\x1e.easyanylink.v2.LocalUserStatsR\n" +
+	"localUsersJ\x04\b\a\x10\bJ\x04\b\b\x10\tR\tcpu_usageR\fmemory_usage\"[\n" +
+	"\x11TrafficClassStats\x12\x14\n" +
+	"\x05class\x18\x01 \x01(\tR\x05class\x12\x16\n" +
+	"\x06queued\x18\x02 \x01(\x04R\x06queued\x12\x18\n" +
+	"\adropped\x18\x03 \x01(\x04R\adropped\"\xca\x01\n" +
+	"\x0eLocalUserStats\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\rR\x03uid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x03 \x01(\x04R\tbytesSent\x12%\n" +
+	"\x0ebytes_received\x18\x04 \x01(\x04R\rbytesReceived\x12!\n" +
+	"\fpackets_sent\x18\x05 \x01(\x04R\vpacketsSent\x12)\n" +
+	"\x10packets_received\x18\x06 \x01(\x04R\x0fpacketsReceived\"\xb4\x02\n" +
+	"\x11HeartbeatResponse\x12\x14\n" +
+	"\x05alive\x18\x01 \x01(\bR\x05alive\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x122\n" +
+	"\x15should_refresh_routes\x18\x03 \x01(\bR\x13shouldRefreshRoutes\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12-\n" +
+	"\x12keepalive_interval\x18\x05 \x01(\x05R\x11keepaliveInterval\x12+\n" +
+	"\x11keepalive_timeout\x18\x06 \x01(\x05R\x10keepaliveTimeout\x12%\n" +
+	"\x0ehandoff_server\x18\a \x01(\tR\rhandoffServer\"\xbc\x02\n" +
+	"\n" +
+	"DataPacket\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12&\n" +
+	"\x0fsource_agent_id\x18\x02 \x01(\tR\rsourceAgentId\x120\n" +
+	"\x14destination_agent_id\x18\x03 \x01(\tR\x12destinationAgentId\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\fR\apayload\x12-\n" +
+	"\x04echo\x18\a \x01(\v2\x19.easyanylink.v2.EchoProbeR\x04echo\x12\x18\n" +
+	"\apadding\x18\b \x01(\fR\apadding\x12\"\n" +
+	"\n" +
+	"source_uid\x18\t \x01(\rH\x00R\tsourceUid\x88\x01\x01B\r\n" +
+	"\v_source_uidJ\x04\b\x05\x10\x06J\x04\b\x06\x10\aR\bsequenceR\ttimestamp\"\xae\x01\n" +
+	"\tEchoProbe\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\x12\x14\n" +
+	"\x05reply\x18\x03 \x01(\bR\x05reply\x123\n" +
+	"\asent_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x18\n" +
+	"\aaddress\x18\x06 \x01(\tR\aaddress\"5\n" +
+	"\x18IdentityChallengeRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"P\n" +
+	"\x11IdentityChallenge\x12\x1c\n" +
+	"\tchallenge\x18\x01 \x01(\fR\tchallenge\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x02 \x01(\x05R\texpiresIn\"\x14\n" +
+	"\x12TrustBundleRequest\"O\n" +
+	"\x13TrustBundleResponse\x12\x16\n" +
+	"\x06bundle\x18\x01 \x01(\fR\x06bundle\x12 \n" +
+	"\vfingerprint\x18\x02 \x01(\tR\vfingerprint\"H\n" +
+	"\fRouteRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"\xc2\x01\n" +
+	"\rRouteResponse\x121\n" +
+	"\x05rules\x18\x01 \x03(\v2\x1b.easyanylink.v2.RoutingRuleR\x05rules\x12D\n" +
+	"\x0emanaged_config\x18\x03 \x01(\v2\x1d.easyanylink.v2.ManagedConfigR\rmanagedConfig\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x04 \x01(\x04R\n" +
+	"generationJ\x04\b\x02\x10\x03R\x12default_gateway_id\"\xa2\x02\n" +
+	"\vRoutingRule\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x05R\x06ruleId\x123\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x1b.easyanylink.v2.RouteActionR\x06action\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\x12\x1d\n" +
+	"\n" +
+	"gateway_id\x18\x04 \x01(\tR\tgatewayId\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\x05R\bpriority\x12\x18\n" +
+	"\aenabled\x18\x06 \x01(\bR\aenabled\x124\n" +
+	"\x06window\x18\a \x01(\v2\x1c.easyanylink.v2.AccessWindowR\x06window\x12\x18\n" +
+	"\aposture\x18\b \x01(\tR\aposture\"\xa2\x01\n" +
+	"\fAccessWindow\x129\n" +
+	"\n" +
+	"valid_from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tvalidFrom\x12;\n" +
+	"\vvalid_until\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"validUntil\x12\x1a\n" +
+	"\bschedule\x18\x03 \x01(\tR\bschedule\"\x97\x01\n" +
+	"\fStatusUpdate\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x123\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1b.easyanylink.v2.AgentStatusR\x06status\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"`\n" +
+	"\fSessionEnded\x128\n" +
+	"\x06reason\x18\x01 \x01(\x0e2 .easyanylink.v2.DisconnectReasonR\x06reason\x12\x16\n" +
+	"\x06detail\x18\x02 \x01(\tR\x06detail\"Z\n" +
+	"\n" +
+	"ServerBusy\x12\x1f\n" +
+	"\vretry_after\x18\x01 \x01(\x05R\n" +
+	"retryAfter\x12+\n" +
+	"\x11alternate_servers\x18\x02 \x03(\tR\x10alternateServers\"!\n" +
+	"\vErrorDetail\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"N\n" +
+	"\x0eStatusResponse\x12\"\n" +
+	"\facknowledged\x18\x01 \x01(\bR\facknowledged\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xf8\x01\n" +
+	"\x14RequestAccessRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\x12\x1a\n" +
+	"\bprotocol\x18\x04 \x01(\tR\bprotocol\x12\x1b\n" +
+	"\tport_from\x18\x05 \x01(\rR\bportFrom\x12\x17\n" +
+	"\aport_to\x18\x06 \x01(\rR\x06portTo\x12\x1a\n" +
+	"\bduration\x18\a \x01(\x05R\bduration\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\"Q\n" +
+	"\x15AccessRequestsRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"S\n" +
+	"\x16AccessRequestsResponse\x129\n" +
+	"\brequests\x18\x01 \x03(\v2\x1d.easyanylink.v2.AccessRequestR\brequests\"\xbc\x04\n" +
+	"\rAccessRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\x05R\trequestId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12 \n" +
+	"\vdestination\x18\x04 \x01(\tR\vdestination\x12\x1a\n" +
+	"\bprotocol\x18\x05 \x01(\tR\bprotocol\x12\x1b\n" +
+	"\tport_from\x18\x06 \x01(\rR\bportFrom\x12\x17\n" +
+	"\aport_to\x18\a \x01(\rR\x06portTo\x12\x1a\n" +
+	"\bduration\x18\b \x01(\x05R\bduration\x12\x16\n" +
+	"\x06reason\x18\t \x01(\tR\x06reason\x12\x14\n" +
+	"\x05state\x18\n" +
+	" \x01(\tR\x05state\x12=\n" +
+	"\frequested_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vrequestedAt\x12\x1d\n" +
+	"\n" +
+	"decided_by\x18\f \x01(\tR\tdecidedBy\x129\n" +
+	"\n" +
+	"decided_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tdecidedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1e\n" +
+	"\vacl_rule_id\x18\x0f \x01(\x05R\taclRuleId\x12&\n" +
+	"\x0frouting_rule_id\x18\x10 \x01(\x05R\rroutingRuleId*@\n" +
+	"\tAgentType\x12\x1a\n" +
+	"\x16AGENT_TYPE_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06CLIENT\x10\x01\x12\v\n" +
+	"\aGATEWAY\x10\x02*`\n" +
+	"\fPostureState\x12\x19\n" +
+	"\x15POSTURE_STATE_UNKNOWN\x10\x00\x12\x19\n" +
+	"\x15POSTURE_STATE_ENABLED\x10\x01\x12\x1a\n" +
+	"\x16POSTURE_STATE_DISABLED\x10\x02*N\n" +
+	"\vRouteAction\x12\x1c\n" +
+	"\x18ROUTE_ACTION_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aFORWARD\x10\x01\x12\n" +
+	"\n" +
+	"\x06DIRECT\x10\x02\x12\b\n" +
+	"\x04DENY\x10\x03*k\n" +
+	"\vAgentStatus\x12\x1c\n" +
+	"\x18AGENT_STATUS_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06ONLINE\x10\x01\x12\v\n" +
+	"\aOFFLINE\x10\x02\x12\t\n" +
+	"\x05ERROR\x10\x03\x12\x0f\n" +
+	"\vMAINTENANCE\x10\x04\x12\t\n" +
+	"\x05READY\x10\x05*\xa7\x02\n" +
+	"\x10DisconnectReason\x12!\n" +
+	"\x1dDISCONNECT_REASON_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aDISCONNECT_SERVER_DECISION\x10\x01\x12\x1b\n" +
+	"\x17DISCONNECT_AUTH_REVOKED\x10\x02\x12\x1b\n" +
+	"\x17DISCONNECT_IDLE_TIMEOUT\x10\x03\x12\x1d\n" +
+	"\x19DISCONNECT_AGENT_SHUTDOWN\x10\x04\x12\x1e\n" +
+	"\x1aDISCONNECT_TRANSPORT_ERROR\x10\x05\x12\x1a\n" +
+	"\x16DISCONNECT_AGENT_ERROR\x10\x06\x12\x1d\n" +
+	"\x19DISCONNECT_SERVER_UPGRADE\x10\a\x12\x1c\n" +
+	"\x18DISCONNECT_SLOW_CONSUMER\x10\b2\x8e\x06\n" +
+	"\fAgentService\x12M\n" +
+	"\bRegister\x12\x1f.easyanylink.v2.RegisterRequest\x1a .easyanylink.v2.RegisterResponse\x12T\n" +
+	"\tHeartbeat\x12 .easyanylink.v2.HeartbeatRequest\x1a!.easyanylink.v2.HeartbeatResponse(\x010\x01\x12G\n" +
+	"\tRelayData\x12\x1a.easyanylink.v2.DataPacket\x1a\x1a.easyanylink.v2.DataPacket(\x010\x01\x12H\n" +
+	"\tGetRoutes\x12\x1c.easyanylink.v2.RouteRequest\x1a\x1d.easyanylink.v2.RouteResponse\x12L\n" +
+	"\fUpdateStatus\x12\x1c.easyanylink.v2.StatusUpdate\x1a\x1e.easyanylink.v2.StatusResponse\x12Y\n" +
+	"\x0eGetTrustBundle\x12\".easyanylink.v2.TrustBundleRequest\x1a#.easyanylink.v2.TrustBundleResponse\x12c\n" +
+	"\x14GetIdentityChallenge\x12(.easyanylink.v2.IdentityChallengeRequest\x1a!.easyanylink.v2.IdentityChallenge\x12T\n" +
+	"\rRequestAccess\x12$.easyanylink.v2.RequestAccessRequest\x1a\x1d.easyanylink.v2.AccessRequest\x12b\n" +
+	"\x11GetAccessRequests\x12%.easyanylink.v2.AccessRequestsRequest\x1a&.easyanylink.v2.AccessRequestsResponseBIZGgithub.com/taills/EasyAnyLink/common/proto/easyanylink/v2;easyanylinkv2b\x06proto3"
+
+var (
+	file_common_proto_easyanylink_v2_agent_proto_rawDescOnce sync.Once
+	file_common_proto_easyanylink_v2_agent_proto_rawDescData []byte
+)
+
+func file_common_proto_easyanylink_v2_agent_proto_rawDescGZIP() []byte {
+	file_common_proto_easyanylink_v2_agent_proto_rawDescOnce.Do(func() {
+		file_common_proto_easyanylink_v2_agent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_common_proto_easyanylink_v2_agent_proto_rawDesc), len(file_common_proto_easyanylink_v2_agent_proto_rawDesc)))
+	})
+	return file_common_proto_easyanylink_v2_agent_proto_rawDescData
+}
+
+var file_common_proto_easyanylink_v2_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_common_proto_easyanylink_v2_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
+var file_common_proto_easyanylink_v2_agent_proto_goTypes = []any{
+	(AgentType)(0),                   // 0: easyanylink.v2.AgentType
+	(PostureState)(0),                // 1: easyanylink.v2.PostureState
+	(RouteAction)(0),                 // 2: easyanylink.v2.RouteAction
+	(AgentStatus)(0),                 // 3: easyanylink.v2.AgentStatus
+	(DisconnectReason)(0),            // 4: easyanylink.v2.DisconnectReason
+	(*RegisterRequest)(nil),          // 5: easyanylink.v2.RegisterRequest
+	(*AgentMetadata)(nil),            // 6: easyanylink.v2.AgentMetadata
+	(*DevicePosture)(nil),            // 7: easyanylink.v2.DevicePosture
+	(*NetworkInterface)(nil),         // 8: easyanylink.v2.NetworkInterface
+	(*RegisterResponse)(nil),         // 9: easyanylink.v2.RegisterResponse
+	(*ServerConfig)(nil),             // 10: easyanylink.v2.ServerConfig
+	(*ManagedConfig)(nil),            // 11: easyanylink.v2.ManagedConfig
+	(*HeartbeatRequest)(nil),         // 12: easyanylink.v2.HeartbeatRequest
+	(*SiteUpdate)(nil),               // 13: easyanylink.v2.SiteUpdate
+	(*AgentHealth)(nil),              // 14: easyanylink.v2.AgentHealth
+	(*SubsystemHealth)(nil),          // 15: easyanylink.v2.SubsystemHealth
+	(*AgentStats)(nil),               // 16: easyanylink.v2.AgentStats
+	(*TrafficClassStats)(nil),        // 17: easyanylink.v2.TrafficClassStats
+	(*LocalUserStats)(nil),           // 18: easyanylink.v2.LocalUserStats
+	(*HeartbeatResponse)(nil),        // 19: easyanylink.v2.HeartbeatResponse
+	(*DataPacket)(nil),               // 20: easyanylink.v2.DataPacket
+	(*EchoProbe)(nil),                // 21: easyanylink.v2.EchoProbe
+	(*IdentityChallengeRequest)(nil), // 22: easyanylink.v2.IdentityChallengeRequest
+	(*IdentityChallenge)(nil),        // 23: easyanylink.v2.IdentityChallenge
+	(*TrustBundleRequest)(nil),       // 24: easyanylink.v2.TrustBundleRequest
+	(*TrustBundleResponse)(nil),      // 25: easyanylink.v2.TrustBundleResponse
+	(*RouteRequest)(nil),             // 26: easyanylink.v2.RouteRequest
+	(*RouteResponse)(nil),            // 27: easyanylink.v2.RouteResponse
+	(*RoutingRule)(nil),              // 28: easyanylink.v2.RoutingRule
+	(*AccessWindow)(nil),             // 29: easyanylink.v2.AccessWindow
+	(*StatusUpdate)(nil),             // 30: easyanylink.v2.StatusUpdate
+	(*SessionEnded)(nil),             // 31: easyanylink.v2.SessionEnded
+	(*ServerBusy)(nil),               // 32: easyanylink.v2.ServerBusy
+	(*ErrorDetail)(nil),              // 33: easyanylink.v2.ErrorDetail
+	(*StatusResponse)(nil),           // 34: easyanylink.v2.StatusResponse
+	(*RequestAccessRequest)(nil),     // 35: easyanylink.v2.RequestAccessRequest
+	(*AccessRequestsRequest)(nil),    // 36: easyanylink.v2.AccessRequestsRequest
+	(*AccessRequestsResponse)(nil),   // 37: easyanylink.v2.AccessRequestsResponse
+	(*AccessRequest)(nil),            // 38: easyanylink.v2.AccessRequest
+	nil,                              // 39: easyanylink.v2.AgentMetadata.LabelsEntry
+	(*timestamppb.Timestamp)(nil),    // 40: google.protobuf.Timestamp
+}
+var file_common_proto_easyanylink_v2_agent_proto_depIdxs = []int32{
+	0,  // 0: easyanylink.v2.RegisterRequest.type:type_name -> easyanylink.v2.AgentType
+	6,  // 1: easyanylink.v2.RegisterRequest.metadata:type_name -> easyanylink.v2.AgentMetadata
+	39, // 2: easyanylink.v2.AgentMetadata.labels:type_name -> easyanylink.v2.AgentMetadata.LabelsEntry
+	8,  // 3: easyanylink.v2.AgentMetadata.interfaces:type_name -> easyanylink.v2.NetworkInterface
+	7,  // 4: easyanylink.v2.AgentMetadata.posture:type_name -> easyanylink.v2.DevicePosture
+	1,  // 5: easyanylink.v2.DevicePosture.disk_encryption:type_name -> easyanylink.v2.PostureState
+	1,  // 6: easyanylink.v2.DevicePosture.firewall:type_name -> easyanylink.v2.PostureState
+	10, // 7: easyanylink.v2.RegisterResponse.server_config:type_name -> easyanylink.v2.ServerConfig
+	11, // 8: easyanylink.v2.RegisterResponse.managed_config:type_name -> easyanylink.v2.ManagedConfig
+	40, // 9: easyanylink.v2.RegisterResponse.server_time:type_name -> google.protobuf.Timestamp
+	40, // 10: easyanylink.v2.HeartbeatRequest.timestamp:type_name -> google.protobuf.Timestamp
+	16, // 11: easyanylink.v2.HeartbeatRequest.stats:type_name -> easyanylink.v2.AgentStats
+	6,  // 12: easyanylink.v2.HeartbeatRequest.metadata:type_name -> easyanylink.v2.AgentMetadata
+	14, // 13: easyanylink.v2.HeartbeatRequest.health:type_name -> easyanylink.v2.AgentHealth
+	13, // 14: easyanylink.v2.HeartbeatRequest.sites:type_name -> easyanylink.v2.SiteUpdate
+	15, // 15: easyanylink.v2.AgentHealth.subsystems:type_name -> easyanylink.v2.SubsystemHealth
+	40, // 16: easyanylink.v2.SubsystemHealth.last_failure:type_name -> google.protobuf.Timestamp
+	17, // 17: easyanylink.v2.AgentStats.classes:type_name -> easyanylink.v2.TrafficClassStats
+	18, // 18: easyanylink.v2.AgentStats.local_users:type_name -> easyanylink.v2.LocalUserStats
+	40, // 19: easyanylink.v2.HeartbeatResponse.timestamp:type_name -> google.protobuf.Timestamp
+	21, // 20: easyanylink.v2.DataPacket.echo:type_name -> easyanylink.v2.EchoProbe
+	40, // 21: easyanylink.v2.EchoProbe.sent_at:type_name -> google.protobuf.Timestamp
+	28, // 22: easyanylink.v2.RouteResponse.rules:type_name -> easyanylink.v2.RoutingRule
+	11, // 23: easyanylink.v2.RouteResponse.managed_config:type_name -> easyanylink.v2.ManagedConfig
+	2,  // 24: easyanylink.v2.RoutingRule.action:type_name -> easyanylink.v2.RouteAction
+	29, // 25: easyanylink.v2.RoutingRule.window:type_name -> easyanylink.v2.AccessWindow
+	40, // 26: easyanylink.v2.AccessWindow.valid_from:type_name -> google.protobuf.Timestamp
+	40, // 27: easyanylink.v2.AccessWindow.valid_until:type_name -> google.protobuf.Timestamp
+	3,  // 28: easyanylink.v2.StatusUpdate.status:type_name -> easyanylink.v2.AgentStatus
+	4,  // 29: easyanylink.v2.SessionEnded.reason:type_name -> easyanylink.v2.DisconnectReason
+	38, // 30: easyanylink.v2.AccessRequestsResponse.requests:type_name -> easyanylink.v2.AccessRequest
+	40, // 31: easyanylink.v2.AccessRequest.requested_at:type_name -> google.protobuf.Timestamp
+	40, // 32: easyanylink.v2.AccessRequest.decided_at:type_name -> google.protobuf.Timestamp
+	40, // 33: easyanylink.v2.AccessRequest.expires_at:type_name -> google.protobuf.Timestamp
+	5,  // 34: easyanylink.v2.AgentService.Register:input_type -> easyanylink.v2.RegisterRequest
+	12, // 35: easyanylink.v2.AgentService.Heartbeat:input_type -> easyanylink.v2.HeartbeatRequest
+	20, // 36: easyanylink.v2.AgentService.RelayData:input_type -> easyanylink.v2.DataPacket
+	26, // 37: easyanylink.v2.AgentService.GetRoutes:input_type -> easyanylink.v2.RouteRequest
+	30, // 38: easyanylink.v2.AgentService.UpdateStatus:input_type -> easyanylink.v2.StatusUpdate
+	24, // 39: easyanylink.v2.AgentService.GetTrustBundle:input_type -> easyanylink.v2.TrustBundleRequest
+	22, // 40: easyanylink.v2.AgentService.GetIdentityChallenge:input_type -> easyanylink.v2.IdentityChallengeRequest
+	35, // 41: easyanylink.v2.AgentService.RequestAccess:input_type -> easyanylink.v2.RequestAccessRequest
+	36, // 42: easyanylink.v2.AgentService.GetAccessRequests:input_type -> easyanylink.v2.AccessRequestsRequest
+	9,  // 43: easyanylink.v2.AgentService.Register:output_type -> easyanylink.v2.RegisterResponse
+	19, // 44: easyanylink.v2.AgentService.Heartbeat:output_type -> easyanylink.v2.HeartbeatResponse
+	20, // 45: easyanylink.v2.AgentService.RelayData:output_type -> easyanylink.v2.DataPacket
+	27, // 46: easyanylink.v2.AgentService.GetRoutes:output_type -> easyanylink.v2.RouteResponse
+	34, // 47: easyanylink.v2.AgentService.UpdateStatus:output_type -> easyanylink.v2.StatusResponse
+	25, // 48: easyanylink.v2.AgentService.GetTrustBundle:output_type -> easyanylink.v2.TrustBundleResponse
+	23, // 49: easyanylink.v2.AgentService.GetIdentityChallenge:output_type -> easyanylink.v2.IdentityChallenge
+	38, // 50: easyanylink.v2.AgentService.RequestAccess:output_type -> easyanylink.v2.AccessRequest
+	37, // 51: easyanylink.v2.AgentService.GetAccessRequests:output_type -> easyanylink.v2.AccessRequestsResponse
+	43, // [43:52] is the sub-list for method output_type
+	34, // [34:43] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
+}
+
+func init() { file_common_proto_easyanylink_v2_agent_proto_init() }
+func file_common_proto_easyanylink_v2_agent_proto_init() {
+	if File_common_proto_easyanylink_v2_agent_proto != nil {
+		return
+	}
+	file_common_proto_easyanylink_v2_agent_proto_msgTypes[15].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_proto_easyanylink_v2_agent_proto_rawDesc), len(file_common_proto_easyanylink_v2_agent_proto_rawDesc)),
+			NumEnums:      5,
+			NumMessages:   35,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_common_proto_easyanylink_v2_agent_proto_goTypes,
+		DependencyIndexes: file_common_proto_easyanylink_v2_agent_proto_depIdxs,
+		EnumInfos:         file_common_proto_easyanylink_v2_agent_proto_enumTypes,
+		MessageInfos:      file_common_proto_easyanylink_v2_agent_proto_msgTypes,
+	}.Build()
+	File_common_proto_easyanylink_v2_agent_proto = out.File
+	file_common_proto_easyanylink_v2_agent_proto_goTypes = nil
+	file_common_proto_easyanylink_v2_agent_proto_depIdxs = nil
+}
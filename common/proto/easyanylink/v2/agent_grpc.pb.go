@@ -0,0 +1,446 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: common/proto/easyanylink/v2/agent.proto
+
+// Version 2 of the API. It must stay wire compatible with v1, which the
+// server serves with the same handlers: add fields, never renumber them,
+// and reserve the numbers and names of removed ones.
+
+package easyanylinkv2
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AgentService_Register_FullMethodName             = "/easyanylink.v2.AgentService/Register"
+	AgentService_Heartbeat_FullMethodName            = "/easyanylink.v2.AgentService/Heartbeat"
+	AgentService_RelayData_FullMethodName            = "/easyanylink.v2.AgentService/RelayData"
+	AgentService_GetRoutes_FullMethodName            = "/easyanylink.v2.AgentService/GetRoutes"
+	AgentService_UpdateStatus_FullMethodName         = "/easyanylink.v2.AgentService/UpdateStatus"
+	AgentService_GetTrustBundle_FullMethodName       = "/easyanylink.v2.AgentService/GetTrustBundle"
+	AgentService_GetIdentityChallenge_FullMethodName = "/easyanylink.v2.AgentService/GetIdentityChallenge"
+	AgentService_RequestAccess_FullMethodName        = "/easyanylink.v2.AgentService/RequestAccess"
+	AgentService_GetAccessRequests_FullMethodName    = "/easyanylink.v2.AgentService/GetAccessRequests"
+)
+
+// AgentServiceClient is the client API for AgentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AgentService defines the gRPC service for agent-server communication
+type AgentServiceClient interface {
+	// Register an agent with the server
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// Maintain heartbeat to keep the connection alive
+	Heartbeat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[HeartbeatRequest, HeartbeatResponse], error)
+	// Relay data packets between agents
+	RelayData(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DataPacket, DataPacket], error)
+	// Get routing configuration for client agents
+	GetRoutes(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*RouteResponse, error)
+	// Update agent status
+	UpdateStatus(ctx context.Context, in *StatusUpdate, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Download the CA certificates that issued the server certificate,
+	// used by new agents before they trust the server
+	GetTrustBundle(ctx context.Context, in *TrustBundleRequest, opts ...grpc.CallOption) (*TrustBundleResponse, error)
+	// Get a single-use challenge for an agent to sign with its hardware
+	// identity key in its next registration
+	GetIdentityChallenge(ctx context.Context, in *IdentityChallengeRequest, opts ...grpc.CallOption) (*IdentityChallenge, error)
+	// Ask for temporary access to a destination, granted once an admin
+	// approves the request
+	RequestAccess(ctx context.Context, in *RequestAccessRequest, opts ...grpc.CallOption) (*AccessRequest, error)
+	// Get the access requests of an agent, newest first
+	GetAccessRequests(ctx context.Context, in *AccessRequestsRequest, opts ...grpc.CallOption) (*AccessRequestsResponse, error)
+}
+
+type agentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAgentServiceClient(cc grpc.ClientConnInterface) AgentServiceClient {
+	return &agentServiceClient{cc}
+}
+
+func (c *agentServiceClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, AgentService_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) Heartbeat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[HeartbeatRequest, HeartbeatResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AgentService_ServiceDesc.Streams[0], AgentService_Heartbeat_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[HeartbeatRequest, HeartbeatResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_HeartbeatClient = grpc.BidiStreamingClient[HeartbeatRequest, HeartbeatResponse]
+
+func (c *agentServiceClient) RelayData(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DataPacket, DataPacket], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AgentService_ServiceDesc.Streams[1], AgentService_RelayData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DataPacket, DataPacket]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_RelayDataClient = grpc.BidiStreamingClient[DataPacket, DataPacket]
+
+func (c *agentServiceClient) GetRoutes(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*RouteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RouteResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetRoutes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) UpdateStatus(ctx context.Context, in *StatusUpdate, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, AgentService_UpdateStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) GetTrustBundle(ctx context.Context, in *TrustBundleRequest, opts ...grpc.CallOption) (*TrustBundleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrustBundleResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetTrustBundle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) GetIdentityChallenge(ctx context.Context, in *IdentityChallengeRequest, opts ...grpc.CallOption) (*IdentityChallenge, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IdentityChallenge)
+	err := c.cc.Invoke(ctx, AgentService_GetIdentityChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) RequestAccess(ctx context.Context, in *RequestAccessRequest, opts ...grpc.CallOption) (*AccessRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccessRequest)
+	err := c.cc.Invoke(ctx, AgentService_RequestAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) GetAccessRequests(ctx context.Context, in *AccessRequestsRequest, opts ...grpc.CallOption) (*AccessRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccessRequestsResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetAccessRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AgentServiceServer is the server API for AgentService service.
+// All implementations must embed UnimplementedAgentServiceServer
+// for forward compatibility.
+//
+// AgentService defines the gRPC service for agent-server communication
+type AgentServiceServer interface {
+	// Register an agent with the server
+	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
+	// Maintain heartbeat to keep the connection alive
+	Heartbeat(grpc.BidiStreamingServer[HeartbeatRequest, HeartbeatResponse]) error
+	// Relay data packets between agents
+	RelayData(grpc.BidiStreamingServer[DataPacket, DataPacket]) error
+	// Get routing configuration for client agents
+	GetRoutes(context.Context, *RouteRequest) (*RouteResponse, error)
+	// Update agent status
+	UpdateStatus(context.Context, *StatusUpdate) (*StatusResponse, error)
+	// Download the CA certificates that issued the server certificate,
+	// used by new agents before they trust the server
+	GetTrustBundle(context.Context, *TrustBundleRequest) (*TrustBundleResponse, error)
+	// Get a single-use challenge for an agent to sign with its hardware
+	// identity key in its next registration
+	GetIdentityChallenge(context.Context, *IdentityChallengeRequest) (*IdentityChallenge, error)
+	// Ask for temporary access to a destination, granted once an admin
+	// approves the request
+	RequestAccess(context.Context, *RequestAccessRequest) (*AccessRequest, error)
+	// Get the access requests of an agent, newest first
+	GetAccessRequests(context.Context, *AccessRequestsRequest) (*AccessRequestsResponse, error)
+	mustEmbedUnimplementedAgentServiceServer()
+}
+
+// UnimplementedAgentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAgentServiceServer struct{}
+
+func (UnimplementedAgentServiceServer) Register(context.Context, *RegisterRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedAgentServiceServer) Heartbeat(grpc.BidiStreamingServer[HeartbeatRequest, HeartbeatResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedAgentServiceServer) RelayData(grpc.BidiStreamingServer[DataPacket, DataPacket]) error {
+	return status.Errorf(codes.Unimplemented, "method RelayData not implemented")
+}
+func (UnimplementedAgentServiceServer) GetRoutes(context.Context, *RouteRequest) (*RouteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoutes not implemented")
+}
+func (UnimplementedAgentServiceServer) UpdateStatus(context.Context, *StatusUpdate) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStatus not implemented")
+}
+func (UnimplementedAgentServiceServer) GetTrustBundle(context.Context, *TrustBundleRequest) (*TrustBundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrustBundle not implemented")
+}
+func (UnimplementedAgentServiceServer) GetIdentityChallenge(context.Context, *IdentityChallengeRequest) (*IdentityChallenge, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIdentityChallenge not implemented")
+}
+func (UnimplementedAgentServiceServer) RequestAccess(context.Context, *RequestAccessRequest) (*AccessRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestAccess not implemented")
+}
+func (UnimplementedAgentServiceServer) GetAccessRequests(context.Context, *AccessRequestsRequest) (*AccessRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccessRequests not implemented")
+}
+func (UnimplementedAgentServiceServer) mustEmbedUnimplementedAgentServiceServer() {}
+func (UnimplementedAgentServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAgentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AgentServiceServer will
+// result in compilation errors.
+type UnsafeAgentServiceServer interface {
+	mustEmbedUnimplementedAgentServiceServer()
+}
+
+func RegisterAgentServiceServer(s grpc.ServiceRegistrar, srv AgentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAgentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AgentService_ServiceDesc, srv)
+}
+
+func _AgentService_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_Heartbeat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentServiceServer).Heartbeat(&grpc.GenericServerStream[HeartbeatRequest, HeartbeatResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_HeartbeatServer = grpc.BidiStreamingServer[HeartbeatRequest, HeartbeatResponse]
+
+func _AgentService_RelayData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentServiceServer).RelayData(&grpc.GenericServerStream[DataPacket, DataPacket]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_RelayDataServer = grpc.BidiStreamingServer[DataPacket, DataPacket]
+
+func _AgentService_GetRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetRoutes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetRoutes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetRoutes(ctx, req.(*RouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_UpdateStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusUpdate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).UpdateStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_UpdateStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).UpdateStatus(ctx, req.(*StatusUpdate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_GetTrustBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrustBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetTrustBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetTrustBundle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetTrustBundle(ctx, req.(*TrustBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_GetIdentityChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdentityChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetIdentityChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetIdentityChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetIdentityChallenge(ctx, req.(*IdentityChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_RequestAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).RequestAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_RequestAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).RequestAccess(ctx, req.(*RequestAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_GetAccessRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetAccessRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetAccessRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetAccessRequests(ctx, req.(*AccessRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AgentService_ServiceDesc is the grpc.ServiceDesc for AgentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AgentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "easyanylink.v2.AgentService",
+	HandlerType: (*AgentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _AgentService_Register_Handler,
+		},
+		{
+			MethodName: "GetRoutes",
+			Handler:    _AgentService_GetRoutes_Handler,
+		},
+		{
+			MethodName: "UpdateStatus",
+			Handler:    _AgentService_UpdateStatus_Handler,
+		},
+		{
+			MethodName: "GetTrustBundle",
+			Handler:    _AgentService_GetTrustBundle_Handler,
+		},
+		{
+			MethodName: "GetIdentityChallenge",
+			Handler:    _AgentService_GetIdentityChallenge_Handler,
+		},
+		{
+			MethodName: "RequestAccess",
+			Handler:    _AgentService_RequestAccess_Handler,
+		},
+		{
+			MethodName: "GetAccessRequests",
+			Handler:    _AgentService_GetAccessRequests_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Heartbeat",
+			Handler:       _AgentService_Heartbeat_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "RelayData",
+			Handler:       _AgentService_RelayData_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "common/proto/easyanylink/v2/agent.proto",
+}
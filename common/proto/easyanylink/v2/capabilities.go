@@ -0,0 +1,16 @@
+package easyanylinkv2
+
+// Optional features exchanged in RegisterRequest and RegisterResponse.
+// Peers ignore names they do not know.
+const (
+	CapabilityEcho             = "echo"              // overlay echo probes in DataPacket
+	CapabilityManagedConfig    = "managed-config"    // group settings in ManagedConfig
+	CapabilityServerBusy       = "server-busy"       // ServerBusy details when the session limit is reached
+	CapabilitySessionEnded     = "session-ended"     // SessionEnded details on calls of ended sessions
+	CapabilitySiteMesh         = "site-mesh"         // routes between the sites advertised by gateways
+	CapabilityConfigGeneration = "config-generation" // applied config generations in HeartbeatRequest
+	CapabilityPadding          = "padding"           // DataPacket messages padded to bucketized sizes, see Pad
+	CapabilityGatewayReady     = "gateway-ready"     // gateways take traffic only after reporting the READY status
+	CapabilityAccessRequests   = "access-requests"   // temporary access through RequestAccess, granted by an admin
+	CapabilitySessionHandoff   = "session-handoff"   // sessions moved to another server of the cluster, see HeartbeatResponse
+)
@@ -0,0 +1,6 @@
+package easyanylinkv2
+
+// DataPacketOverhead bounds the bytes a DataPacket message adds to its
+// payload: session and agent IDs and field framing. Message size limits
+// must leave this much room above the MTU.
+const DataPacketOverhead = 256
@@ -0,0 +1,9 @@
+package easyanylinkv2
+
+import _ "embed"
+
+// OpenAPISpec is the OpenAPI v2 description of the REST gateway, generated
+// from the HTTP rules in gateway.yaml
+//
+//go:embed easyanylink.swagger.json
+var OpenAPISpec []byte
@@ -0,0 +1,31 @@
+package easyanylinkv2
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// PaddingBlock is the size DataPacket messages are padded to a multiple
+// of once padding was negotiated (CapabilityPadding). Packets of similar
+// size become indistinguishable on the wire. The padding field adds at
+// most PaddingBlock+1 bytes, within DataPacketOverhead next to the IDs.
+const PaddingBlock = 128
+
+// zeroPadding backs the padding of every message, which is only read
+var zeroPadding = make([]byte, PaddingBlock)
+
+// Pad sets the padding of a data packet so that its encoded size becomes
+// the next multiple of PaddingBlock, or a byte short of it where the
+// length of the field would need a second byte
+func Pad(dp *DataPacket) {
+	dp.Padding = nil
+	size := protobuf.Size(dp)
+	// The field takes a byte of tag and one of length next to at least a
+	// byte of padding, an empty field is not encoded
+	target := (size + 3 + PaddingBlock - 1) / PaddingBlock * PaddingBlock
+	n := target - size - 2
+	if protowire.SizeVarint(uint64(n)) > 1 {
+		n--
+	}
+	dp.Padding = zeroPadding[:n]
+}
@@ -0,0 +1,255 @@
+// Package support builds support bundles: gzipped tarballs of sanitized
+// configuration, recent logs and system state to attach to issue reports
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/logging"
+)
+
+// commandTimeout bounds each command whose output goes into a bundle
+const commandTimeout = 15 * time.Second
+
+// maxLogBytes is how much of the end of a log file a bundle includes
+const maxLogBytes = 4 << 20
+
+// redacted replaces secret values
+const redacted = "REDACTED"
+
+// minScrubLen is the shortest secret scrubbed from logs and command output
+const minScrubLen = 6
+
+// Bundle is a support bundle being written. Secrets found in added
+// configuration files are also scrubbed from everything added after them,
+// so configuration files should be added first.
+type Bundle struct {
+	file    *os.File
+	gz      *gzip.Writer
+	tw      *tar.Writer
+	dir     string   // top-level directory inside the tarball
+	secrets []string // secret values to scrub from text
+	created time.Time
+}
+
+// Create starts a bundle at path with its entries below dir
+func Create(path, dir string) (*Bundle, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create support bundle: %w", err)
+	}
+	gz := gzip.NewWriter(file)
+	return &Bundle{
+		file:    file,
+		gz:      gz,
+		tw:      tar.NewWriter(gz),
+		dir:     dir,
+		created: time.Now(),
+	}, nil
+}
+
+// Close finishes the bundle
+func (b *Bundle) Close() error {
+	err := b.tw.Close()
+	if gzErr := b.gz.Close(); err == nil {
+		err = gzErr
+	}
+	if fileErr := b.file.Close(); err == nil {
+		err = fileErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	return nil
+}
+
+// AddConfig adds a JSON configuration file with secret values redacted
+func (b *Bundle) AddConfig(name, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b.AddText(name, []byte(fmt.Sprintf("failed to read %s: %v\n", path, err)))
+	}
+
+	var config interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		// Never copy a file that could not be sanitized
+		return b.AddText(name, []byte(fmt.Sprintf("failed to parse %s, not included: %v\n", path, err)))
+	}
+	config = b.redact(config, "")
+
+	sanitized, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return b.AddText(name, append(sanitized, '\n'))
+}
+
+// redact replaces the values of secret keys in a decoded JSON document and
+// remembers them for scrubbing
+func (b *Bundle) redact(v interface{}, key string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = b.redact(child, k)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = b.redact(child, key)
+		}
+		return v
+	case string:
+		if v != "" && logging.IsSecretKey(key) {
+			// Scrubbing very short values would mangle unrelated text
+			if len(v) >= minScrubLen {
+				b.secrets = append(b.secrets, v)
+			}
+			return redacted
+		}
+	}
+	return v
+}
+
+// AddText adds a file with known secrets scrubbed
+func (b *Bundle) AddText(name string, data []byte) error {
+	for _, secret := range b.secrets {
+		data = bytes.ReplaceAll(data, []byte(secret), []byte(redacted))
+	}
+
+	if err := b.tw.WriteHeader(&tar.Header{
+		Name:    b.dir + "/" + name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: b.created,
+	}); err != nil {
+		return fmt.Errorf("failed to add %s to support bundle: %w", name, err)
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to add %s to support bundle: %w", name, err)
+	}
+	return nil
+}
+
+// AddFileTail adds at most the last maxBytes of a file, such as a log
+func (b *Bundle) AddFileTail(name, path string, maxBytes int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return b.AddText(name, []byte(fmt.Sprintf("failed to open %s: %v\n", path, err)))
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() > maxBytes {
+		if _, err := f.Seek(-maxBytes, io.SeekEnd); err != nil {
+			return b.AddText(name, []byte(fmt.Sprintf("failed to read %s: %v\n", path, err)))
+		}
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return b.AddText(name, []byte(fmt.Sprintf("failed to read %s: %v\n", path, err)))
+	}
+	return b.AddText(name, data)
+}
+
+// AddCommand adds the output of a command. A failing command is recorded
+// in the file instead of failing the bundle.
+func (b *Bundle) AddCommand(name string, command string, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, command, args...).CombinedOutput()
+	header := fmt.Sprintf("$ %s %s\n", command, strings.Join(args, " "))
+	if err != nil {
+		output = append(output, fmt.Sprintf("\ncommand failed: %v\n", err)...)
+	}
+	return b.AddText(name, append([]byte(header), output...))
+}
+
+// AddJSON adds a value encoded as indented JSON
+func (b *Bundle) AddJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return b.AddText(name, append(data, '\n'))
+}
+
+// VersionInfo describes the binary and the host for version.txt
+func VersionInfo(component, version, commit, buildTime, cryptoPolicy string) []byte {
+	hostname, _ := os.Hostname()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Component: %s\n", component)
+	fmt.Fprintf(&buf, "Version: %s\n", version)
+	fmt.Fprintf(&buf, "Git Commit: %s\n", commit)
+	fmt.Fprintf(&buf, "Build Time: %s\n", buildTime)
+	fmt.Fprintf(&buf, "Go Version: %s\n", runtime.Version())
+	fmt.Fprintf(&buf, "Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&buf, "Hostname: %s\n", hostname)
+	fmt.Fprintf(&buf, "Crypto Policy: %s\n", cryptoPolicy)
+	fmt.Fprintf(&buf, "Collected: %s\n", time.Now().Format(time.RFC3339))
+	return buf.Bytes()
+}
+
+// systemCommands are the commands collecting interface state and route
+// tables on this platform, as file name and command line
+func systemCommands() [][]string {
+	switch runtime.GOOS {
+	case "linux":
+		return [][]string{
+			{"interfaces.txt", "ip", "addr", "show"},
+			{"routes.txt", "ip", "route", "show", "table", "all"},
+			{"rules.txt", "ip", "rule", "show"},
+			{"resolv.conf.txt", "cat", "/etc/resolv.conf"},
+		}
+	case "darwin":
+		return [][]string{
+			{"interfaces.txt", "ifconfig", "-a"},
+			{"routes.txt", "netstat", "-rn"},
+			{"dns.txt", "scutil", "--dns"},
+		}
+	case "windows":
+		return [][]string{
+			{"interfaces.txt", "ipconfig", "/all"},
+			{"routes.txt", "route", "print"},
+		}
+	}
+	return nil
+}
+
+// AddSystemState adds the interface state and route tables
+func (b *Bundle) AddSystemState() error {
+	for _, c := range systemCommands() {
+		if err := b.AddCommand("system/"+c[0], c[1], c[2:]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddLogs adds the end of the log file, or without one the recent system
+// log entries of the service: journalctl on Linux, the unified log on macOS
+func (b *Bundle) AddLogs(logFile, service string) error {
+	if logFile != "" {
+		return b.AddFileTail("logs/"+filepath.Base(logFile), logFile, maxLogBytes)
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		return b.AddCommand("logs/journal.txt", "journalctl", "-u", service, "-n", "5000", "--no-pager")
+	case "darwin":
+		return b.AddCommand("logs/unified.txt", "log", "show", "--last", "1h", "--style", "compact",
+			"--predicate", fmt.Sprintf("process == %q", service))
+	}
+	return b.AddText("logs/README.txt", []byte("No log file is configured, set log.file to include logs\n"))
+}
@@ -15,15 +15,48 @@ CREATE TABLE IF NOT EXISTS users (
     email VARCHAR(255) UNIQUE,
     password_hash VARCHAR(255) NOT NULL COMMENT 'bcrypt hash',
     api_key VARCHAR(64) UNIQUE NOT NULL COMMENT 'API authentication key',
+    role ENUM('user', 'admin', 'owner', 'support', 'auditor') DEFAULT 'user' NOT NULL COMMENT 'Roles other than user may use the AdminService API',
     status ENUM('active', 'suspended', 'disabled') DEFAULT 'active' NOT NULL,
+    max_agents INT UNSIGNED COMMENT 'NULL for the server default',
+    max_bandwidth INT UNSIGNED COMMENT 'KB/s across all agents, NULL for unlimited',
+    monthly_transfer_cap BIGINT UNSIGNED COMMENT 'Bytes per calendar month, NULL for unlimited',
+    site_prefixes VARCHAR(1024) COMMENT 'Comma-separated CIDRs the user''s gateways may advertise as sites, NULL for any',
+    external_id VARCHAR(255) COMMENT 'ID in the identity provider provisioning the user over SCIM, NULL for none',
+    scim_deactivated TINYINT(1) NOT NULL DEFAULT 0 COMMENT '1=disabled by the identity provider, which may reactivate the user',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_api_key (api_key),
     INDEX idx_status (status),
-    INDEX idx_username (username)
+    INDEX idx_username (username),
+    INDEX idx_external_id (external_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
 COMMENT='User accounts and authentication';
 
+-- User usage table: Relayed traffic per user and calendar month
+CREATE TABLE IF NOT EXISTS user_usage (
+    user_id VARCHAR(36) NOT NULL,
+    month CHAR(7) NOT NULL COMMENT 'YYYY-MM in UTC',
+    bytes_sent BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Bytes relayed from the user''s agents',
+    bytes_received BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Bytes relayed to the user''s agents',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, month),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Relayed traffic per user and month';
+
+-- Agent groups table: Settings and routing rules shared by a fleet of agents
+CREATE TABLE IF NOT EXISTS agent_groups (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    dns_servers VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Comma separated resolver IPs, empty keeps the agent setting',
+    dns_search VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Comma separated search domains',
+    bandwidth_limit INT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'KB/s per agent, 0 for unlimited',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Config templates pushed to the agents of a group';
+
 -- Agents table: All registered agents (client and gateway)
 CREATE TABLE IF NOT EXISTS agents (
     id VARCHAR(36) PRIMARY KEY COMMENT 'UUID format',
@@ -39,33 +72,117 @@ CREATE TABLE IF NOT EXISTS agents (
     metadata JSON COMMENT 'Additional agent info (OS, version, etc.)',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT 'Archive time, NULL for active agents',
+    pending TINYINT(1) NOT NULL DEFAULT 0 COMMENT '1=awaiting admin approval',
+    group_id INT UNSIGNED COMMENT 'Agent group, NULL for none',
+    identity_key VARCHAR(255) COMMENT 'Base64 PKIX public key of the hardware identity, NULL for none',
+    identity_provider VARCHAR(32) COMMENT 'tpm2 or secure-enclave',
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES agent_groups(id) ON DELETE SET NULL,
     INDEX idx_user_id (user_id),
     INDEX idx_type (type),
     INDEX idx_status (status),
-    INDEX idx_last_heartbeat (last_heartbeat)
+    INDEX idx_last_heartbeat (last_heartbeat),
+    INDEX idx_deleted_at (deleted_at),
+    INDEX idx_pending (pending),
+    INDEX idx_group_id (group_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
 COMMENT='Registered agents (client and gateway)';
 
 -- Routing rules table: Client routing policies
 CREATE TABLE IF NOT EXISTS routing_rules (
     id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
-    agent_id VARCHAR(36) NOT NULL,
+    agent_id VARCHAR(36) COMMENT 'Agent the rule applies to, NULL for a group rule',
+    group_id INT UNSIGNED COMMENT 'Agent group the rule applies to, NULL for an agent rule',
     action ENUM('forward', 'direct', 'deny') NOT NULL,
     destination VARCHAR(45) NOT NULL COMMENT 'CIDR notation (e.g., 10.0.0.0/8)',
     gateway_id VARCHAR(36) COMMENT 'NULL for direct and deny actions',
     priority INT NOT NULL DEFAULT 100 COMMENT 'Lower number = higher priority',
     enabled TINYINT(1) NOT NULL DEFAULT 1 COMMENT '1=enabled, 0=disabled',
+    valid_from DATETIME COMMENT 'Start of validity, NULL for no start',
+    valid_until DATETIME COMMENT 'End of validity, NULL for no end',
+    schedule VARCHAR(100) COMMENT 'Weekly schedule, e.g. mon-fri 09:00-18:00, NULL for always',
+    posture VARCHAR(64) COMMENT 'Posture policy the agent must meet, NULL for none',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (agent_id) REFERENCES agents(id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES agent_groups(id) ON DELETE CASCADE,
     FOREIGN KEY (gateway_id) REFERENCES agents(id) ON DELETE SET NULL,
     INDEX idx_agent_id (agent_id),
+    INDEX idx_group_id (group_id),
     INDEX idx_priority (priority),
     INDEX idx_enabled (enabled)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
 COMMENT='Client routing policies';
 
+-- ACL rules table: Traffic the agents of a user may send through the relay
+CREATE TABLE IF NOT EXISTS acl_rules (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL COMMENT 'User whose agents send the traffic',
+    source VARCHAR(45) COMMENT 'Source CIDR, NULL for any',
+    destination VARCHAR(45) COMMENT 'Destination CIDR, NULL for any',
+    protocol ENUM('any', 'tcp', 'udp', 'icmp') DEFAULT 'any' NOT NULL,
+    port_from SMALLINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'First destination port, 0 for any',
+    port_to SMALLINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Last destination port',
+    action ENUM('allow', 'deny') NOT NULL,
+    priority INT NOT NULL DEFAULT 100 COMMENT 'Lower number = higher priority',
+    enabled TINYINT(1) NOT NULL DEFAULT 1 COMMENT '1=enabled, 0=disabled',
+    valid_from DATETIME COMMENT 'Start of validity, NULL for no start',
+    valid_until DATETIME COMMENT 'End of validity, NULL for no end',
+    schedule VARCHAR(100) COMMENT 'Weekly schedule, e.g. mon-fri 09:00-18:00, NULL for always',
+    source_uid INT UNSIGNED COMMENT 'Local user on the agent host that sent the traffic, NULL for any',
+    posture VARCHAR(64) COMMENT 'Posture policy the sending agent must meet for an allow rule, NULL for none',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_id (user_id),
+    INDEX idx_priority (priority)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Relay access control, first matching rule by priority decides';
+
+-- Rollouts table: Routing and ACL rule changes staged to a canary of agents
+CREATE TABLE IF NOT EXISTS rollouts (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    kind ENUM('routing', 'acl') NOT NULL COMMENT 'Rule set the change applies to',
+    operation ENUM('add', 'update', 'delete') NOT NULL,
+    rule_id INT UNSIGNED COMMENT 'Rule updated or deleted, NULL for an added rule',
+    rule TEXT NOT NULL COMMENT 'JSON of the proposed rule, of the deleted rule for deletions',
+    percent TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Share of agents in the canary, 0 with a canary group',
+    group_id INT UNSIGNED COMMENT 'Agent group forming the canary, NULL for a share of agents',
+    state ENUM('staged', 'promoted', 'rolled_back') NOT NULL DEFAULT 'staged',
+    created_by VARCHAR(255) NOT NULL COMMENT 'Admin who staged the change',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES agent_groups(id) ON DELETE SET NULL,
+    INDEX idx_state (state)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Canary rollouts of rule changes, at most one staged at a time';
+
+-- Access requests table: Temporary access agents asked for, and the grants approving them
+CREATE TABLE IF NOT EXISTS access_requests (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    agent_id VARCHAR(36) NOT NULL COMMENT 'Agent that asked for access',
+    destination VARCHAR(45) NOT NULL COMMENT 'Destination CIDR',
+    protocol ENUM('any', 'tcp', 'udp', 'icmp') DEFAULT 'any' NOT NULL,
+    port_from SMALLINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'First destination port, 0 for any',
+    port_to SMALLINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Last destination port',
+    duration INT UNSIGNED NOT NULL COMMENT 'Seconds of access asked for',
+    reason VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Justification given by the user',
+    state ENUM('pending', 'approved', 'denied', 'expired') NOT NULL DEFAULT 'pending',
+    decided_by VARCHAR(255) COMMENT 'Admin who approved or denied the request',
+    decided_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL COMMENT 'End of an approved grant',
+    routing_rule_id INT UNSIGNED COMMENT 'Routing rule of the grant, NULL without a gateway',
+    acl_rule_id INT UNSIGNED COMMENT 'ACL rule of the grant',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (agent_id) REFERENCES agents(id) ON DELETE CASCADE,
+    FOREIGN KEY (routing_rule_id) REFERENCES routing_rules(id) ON DELETE SET NULL,
+    FOREIGN KEY (acl_rule_id) REFERENCES acl_rules(id) ON DELETE SET NULL,
+    INDEX idx_agent_id (agent_id),
+    INDEX idx_state (state)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Just-in-time access requests, approved ones grant rules until expires_at';
+
 -- Sessions table: Active agent connections
 CREATE TABLE IF NOT EXISTS sessions (
     id VARCHAR(36) PRIMARY KEY COMMENT 'Session UUID',
@@ -75,6 +192,7 @@ CREATE TABLE IF NOT EXISTS sessions (
     last_activity TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     bytes_sent BIGINT UNSIGNED NOT NULL DEFAULT 0,
     bytes_received BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    handoff_until TIMESTAMP NULL COMMENT 'Until when another server of the cluster may continue the session, NULL if not handed off',
     FOREIGN KEY (agent_id) REFERENCES agents(id) ON DELETE CASCADE,
     INDEX idx_agent_id (agent_id),
     INDEX idx_connection_id (connection_id),
@@ -82,10 +200,59 @@ CREATE TABLE IF NOT EXISTS sessions (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
 COMMENT='Active agent connections';
 
+-- Session history table: Ended sessions, kept when their agent is archived
+CREATE TABLE IF NOT EXISTS session_history (
+    id VARCHAR(36) PRIMARY KEY COMMENT 'Session UUID',
+    agent_id VARCHAR(36) NOT NULL,
+    connection_id VARCHAR(64) NOT NULL COMMENT 'gRPC stream identifier',
+    connected_at TIMESTAMP NOT NULL,
+    disconnected_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    bytes_sent BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    bytes_received BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    disconnect_code VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'Reason code, e.g. agent_shutdown or idle_timeout',
+    disconnect_reason VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Detail of the reason, e.g. agent stopped',
+    FOREIGN KEY (agent_id) REFERENCES agents(id) ON DELETE CASCADE,
+    INDEX idx_agent_id (agent_id),
+    INDEX idx_disconnected_at (disconnected_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Ended agent sessions, purged after the history retention';
+
+-- Hourly stats table: Ended sessions rolled up per agent and hour
+CREATE TABLE IF NOT EXISTS stats_hourly (
+    period_start TIMESTAMP NOT NULL COMMENT 'Start of the hour the sessions ended in',
+    agent_id VARCHAR(36) NOT NULL COMMENT 'No foreign key, rollups outlive purged agents',
+    user_id VARCHAR(36) NOT NULL,
+    sessions INT UNSIGNED NOT NULL DEFAULT 0,
+    connected_seconds BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Summed duration of the sessions',
+    bytes_sent BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    bytes_received BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (period_start, agent_id),
+    INDEX idx_agent_id (agent_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Hourly session rollups, purged after the hourly stats retention';
+
+-- Daily stats table: Hourly rollups summed per agent and day
+CREATE TABLE IF NOT EXISTS stats_daily (
+    day DATE NOT NULL COMMENT 'Day in the database time zone',
+    agent_id VARCHAR(36) NOT NULL COMMENT 'No foreign key, rollups outlive purged agents',
+    user_id VARCHAR(36) NOT NULL,
+    sessions INT UNSIGNED NOT NULL DEFAULT 0,
+    connected_seconds BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Summed duration of the sessions',
+    bytes_sent BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    bytes_received BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, agent_id),
+    INDEX idx_agent_id (agent_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Daily session rollups, purged after the daily stats retention';
+
 -- Audit logs table: Security and operational audit trail
 CREATE TABLE IF NOT EXISTS audit_logs (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     user_id VARCHAR(36) COMMENT 'NULL if system action',
+    username VARCHAR(255) COMMENT 'Principal at the time of the action',
+    role VARCHAR(20) COMMENT 'Role of the principal at the time of the action',
     agent_id VARCHAR(36) COMMENT 'NULL if not agent-related',
     action VARCHAR(100) NOT NULL,
     resource_type VARCHAR(50) COMMENT 'e.g., agent, user, rule',
@@ -106,12 +273,13 @@ COMMENT='Security and operational audit trail';
 
 -- Insert default admin user (password: admin123 - CHANGE IN PRODUCTION!)
 -- Password hash generated with bcrypt cost 10
-INSERT INTO users (id, username, email, password_hash, api_key, status) VALUES
+INSERT INTO users (id, username, email, password_hash, api_key, role, status) VALUES
 ('00000000-0000-0000-0000-000000000001', 
  'admin', 
  'admin@easyanylink.local',
  '$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy',
  'dev_admin_key_change_in_production_00000000',
+ 'owner',
  'active')
 ON DUPLICATE KEY UPDATE username=username;
 
@@ -151,4 +319,5 @@ SELECT
 FROM agents a
 LEFT JOIN sessions s ON a.id = s.agent_id
 JOIN users u ON a.user_id = u.id
+WHERE a.deleted_at IS NULL
 GROUP BY a.id, a.name, a.type, a.status, a.ip_address, a.last_heartbeat, a.user_id, u.username;
@@ -0,0 +1,31 @@
+-- EasyAnyLink migration: user roles, user management and per-user quotas
+-- Upgrades databases created by init_db.sql before admin roles and user
+-- quotas were added. New installations get these changes from init_db.sql.
+-- MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/001_user_management.sql
+
+USE easy_any_link;
+
+-- Role and quota columns on users, NULL quotas keep the server default or
+-- unlimited
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role ENUM('user', 'admin') DEFAULT 'user' NOT NULL COMMENT 'admin may use the AdminService API' AFTER api_key,
+    ADD COLUMN IF NOT EXISTS max_agents INT UNSIGNED COMMENT 'NULL for the server default' AFTER status,
+    ADD COLUMN IF NOT EXISTS max_bandwidth INT UNSIGNED COMMENT 'KB/s across all agents, NULL for unlimited' AFTER max_agents,
+    ADD COLUMN IF NOT EXISTS monthly_transfer_cap BIGINT UNSIGNED COMMENT 'Bytes per calendar month, NULL for unlimited' AFTER max_bandwidth;
+
+-- The default admin user of init_db.sql may use the AdminService API
+UPDATE users SET role = 'admin' WHERE id = '00000000-0000-0000-0000-000000000001';
+
+-- User usage table: Relayed traffic per user and calendar month
+CREATE TABLE IF NOT EXISTS user_usage (
+    user_id VARCHAR(36) NOT NULL,
+    month CHAR(7) NOT NULL COMMENT 'YYYY-MM in UTC',
+    bytes_sent BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Bytes relayed from the user''s agents',
+    bytes_received BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Bytes relayed to the user''s agents',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, month),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Relayed traffic per user and month';
@@ -0,0 +1,48 @@
+-- EasyAnyLink migration: archived agents and session history
+-- Upgrades databases created by init_db.sql before agents were archived
+-- instead of deleted and ended sessions were kept. New installations get
+-- these changes from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/002_archived_agents.sql
+
+USE easy_any_link;
+
+-- Deleting an agent archives it; existing agents stay active
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT 'Archive time, NULL for active agents' AFTER updated_at,
+    ADD INDEX IF NOT EXISTS idx_deleted_at (deleted_at);
+
+-- Session history table: Ended sessions, kept when their agent is archived
+CREATE TABLE IF NOT EXISTS session_history (
+    id VARCHAR(36) PRIMARY KEY COMMENT 'Session UUID',
+    agent_id VARCHAR(36) NOT NULL,
+    connection_id VARCHAR(64) NOT NULL COMMENT 'gRPC stream identifier',
+    connected_at TIMESTAMP NOT NULL,
+    disconnected_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    bytes_sent BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    bytes_received BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    FOREIGN KEY (agent_id) REFERENCES agents(id) ON DELETE CASCADE,
+    INDEX idx_agent_id (agent_id),
+    INDEX idx_disconnected_at (disconnected_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+COMMENT='Ended agent sessions, purged after the history retention';
+
+-- Archived agents are left out of the statistics
+CREATE OR REPLACE VIEW agent_statistics AS
+SELECT
+    a.id AS agent_id,
+    a.name,
+    a.type,
+    a.status,
+    a.ip_address,
+    a.last_heartbeat,
+    COUNT(DISTINCT s.id) AS active_sessions,
+    COALESCE(SUM(s.bytes_sent), 0) AS total_bytes_sent,
+    COALESCE(SUM(s.bytes_received), 0) AS total_bytes_received,
+    a.user_id,
+    u.username
+FROM agents a
+LEFT JOIN sessions s ON a.id = s.agent_id
+JOIN users u ON a.user_id = u.id
+WHERE a.deleted_at IS NULL
+GROUP BY a.id, a.name, a.type, a.status, a.ip_address, a.last_heartbeat, a.user_id, u.username;
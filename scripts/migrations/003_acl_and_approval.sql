@@ -0,0 +1,34 @@
+-- EasyAnyLink migration: relay ACLs and device approval
+-- Upgrades databases created by init_db.sql before ACL rules and pending
+-- agents were added. New installations get these changes from init_db.sql.
+-- MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/003_acl_and_approval.sql
+
+USE easy_any_link;
+
+-- Agents registered while security.require_approval is set wait for an
+-- admin; existing agents stay approved
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS pending TINYINT(1) NOT NULL DEFAULT 0 COMMENT '1=awaiting admin approval' AFTER deleted_at,
+    ADD INDEX IF NOT EXISTS idx_pending (pending);
+
+-- ACL rules table: Traffic the agents of a user may send through the relay
+CREATE TABLE IF NOT EXISTS acl_rules (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL COMMENT 'User whose agents send the traffic',
+    source VARCHAR(45) COMMENT 'Source CIDR, NULL for any',
+    destination VARCHAR(45) COMMENT 'Destination CIDR, NULL for any',
+    protocol ENUM('any', 'tcp', 'udp', 'icmp') DEFAULT 'any' NOT NULL,
+    port_from SMALLINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'First destination port, 0 for any',
+    port_to SMALLINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Last destination port',
+    action ENUM('allow', 'deny') NOT NULL,
+    priority INT NOT NULL DEFAULT 100 COMMENT 'Lower number = higher priority',
+    enabled TINYINT(1) NOT NULL DEFAULT 1 COMMENT '1=enabled, 0=disabled',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_id (user_id),
+    INDEX idx_priority (priority)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Relay access control, first matching rule by priority decides';
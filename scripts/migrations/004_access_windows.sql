@@ -0,0 +1,19 @@
+-- EasyAnyLink migration: scheduled access windows
+-- Upgrades databases created by init_db.sql before routing and ACL rules
+-- had validity windows. New installations get these changes from
+-- init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/004_access_windows.sql
+
+USE easy_any_link;
+
+-- Existing rules keep applying at all times
+ALTER TABLE routing_rules
+    ADD COLUMN IF NOT EXISTS valid_from DATETIME COMMENT 'Start of validity, NULL for no start' AFTER enabled,
+    ADD COLUMN IF NOT EXISTS valid_until DATETIME COMMENT 'End of validity, NULL for no end' AFTER valid_from,
+    ADD COLUMN IF NOT EXISTS schedule VARCHAR(100) COMMENT 'Weekly schedule, e.g. mon-fri 09:00-18:00, NULL for always' AFTER valid_until;
+
+ALTER TABLE acl_rules
+    ADD COLUMN IF NOT EXISTS valid_from DATETIME COMMENT 'Start of validity, NULL for no start' AFTER enabled,
+    ADD COLUMN IF NOT EXISTS valid_until DATETIME COMMENT 'End of validity, NULL for no end' AFTER valid_from,
+    ADD COLUMN IF NOT EXISTS schedule VARCHAR(100) COMMENT 'Weekly schedule, e.g. mon-fri 09:00-18:00, NULL for always' AFTER valid_until;
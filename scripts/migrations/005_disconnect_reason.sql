@@ -0,0 +1,12 @@
+-- EasyAnyLink migration: session disconnect reasons
+-- Upgrades databases created by init_db.sql before the session history
+-- recorded why a session ended. New installations get these changes from
+-- init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/005_disconnect_reason.sql
+
+USE easy_any_link;
+
+-- Sessions ended before the upgrade have no reason
+ALTER TABLE session_history
+    ADD COLUMN IF NOT EXISTS disconnect_reason VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Why the session ended, e.g. agent shutdown' AFTER bytes_received;
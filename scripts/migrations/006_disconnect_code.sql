@@ -0,0 +1,12 @@
+-- EasyAnyLink migration: session disconnect reason codes
+-- Upgrades databases created by init_db.sql before the session history
+-- recorded a reason code next to the reason. New installations get these
+-- changes from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/006_disconnect_code.sql
+
+USE easy_any_link;
+
+-- Sessions ended before the upgrade have no code
+ALTER TABLE session_history
+    ADD COLUMN IF NOT EXISTS disconnect_code VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'Reason code, e.g. agent_shutdown or idle_timeout' AFTER bytes_received;
@@ -0,0 +1,34 @@
+-- EasyAnyLink migration: agent groups
+-- Upgrades databases created by init_db.sql before agents could be grouped
+-- under shared config templates. New installations get these changes from
+-- init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/007_agent_groups.sql
+
+USE easy_any_link;
+
+-- Agent groups table: Settings and routing rules shared by a fleet of agents
+CREATE TABLE IF NOT EXISTS agent_groups (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    dns_servers VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Comma separated resolver IPs, empty keeps the agent setting',
+    dns_search VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Comma separated search domains',
+    bandwidth_limit INT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'KB/s per agent, 0 for unlimited',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Config templates pushed to the agents of a group';
+
+-- Existing agents belong to no group
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS group_id INT UNSIGNED COMMENT 'Agent group, NULL for none' AFTER pending,
+    ADD INDEX IF NOT EXISTS idx_group_id (group_id),
+    ADD CONSTRAINT fk_agents_group FOREIGN KEY IF NOT EXISTS (group_id) REFERENCES agent_groups(id) ON DELETE SET NULL;
+
+-- Routing rules apply to either an agent or a group
+ALTER TABLE routing_rules
+    MODIFY agent_id VARCHAR(36) COMMENT 'Agent the rule applies to, NULL for a group rule',
+    ADD COLUMN IF NOT EXISTS group_id INT UNSIGNED COMMENT 'Agent group the rule applies to, NULL for an agent rule' AFTER agent_id,
+    ADD INDEX IF NOT EXISTS idx_group_id (group_id),
+    ADD CONSTRAINT fk_routing_rules_group FOREIGN KEY IF NOT EXISTS (group_id) REFERENCES agent_groups(id) ON DELETE CASCADE;
@@ -0,0 +1,39 @@
+-- EasyAnyLink migration: stats rollups
+-- Upgrades databases created by init_db.sql before ended sessions were
+-- rolled up into hourly and daily statistics. New installations get these
+-- tables from init_db.sql. MariaDB 10.5+; safe to run more than once. The
+-- server rolls up the session history still kept on its next start.
+--
+-- Usage: mysql -u root -p < scripts/migrations/008_stats_rollups.sql
+
+USE easy_any_link;
+
+-- Hourly stats table: Ended sessions rolled up per agent and hour
+CREATE TABLE IF NOT EXISTS stats_hourly (
+    period_start TIMESTAMP NOT NULL COMMENT 'Start of the hour the sessions ended in',
+    agent_id VARCHAR(36) NOT NULL COMMENT 'No foreign key, rollups outlive purged agents',
+    user_id VARCHAR(36) NOT NULL,
+    sessions INT UNSIGNED NOT NULL DEFAULT 0,
+    connected_seconds BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Summed duration of the sessions',
+    bytes_sent BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    bytes_received BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (period_start, agent_id),
+    INDEX idx_agent_id (agent_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Hourly session rollups, purged after the hourly stats retention';
+
+-- Daily stats table: Hourly rollups summed per agent and day
+CREATE TABLE IF NOT EXISTS stats_daily (
+    day DATE NOT NULL COMMENT 'Day in the database time zone',
+    agent_id VARCHAR(36) NOT NULL COMMENT 'No foreign key, rollups outlive purged agents',
+    user_id VARCHAR(36) NOT NULL,
+    sessions INT UNSIGNED NOT NULL DEFAULT 0,
+    connected_seconds BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Summed duration of the sessions',
+    bytes_sent BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    bytes_received BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, agent_id),
+    INDEX idx_agent_id (agent_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Daily session rollups, purged after the daily stats retention';
@@ -0,0 +1,26 @@
+-- EasyAnyLink migration: canary rollouts
+-- Upgrades databases created by init_db.sql before routing and ACL rule
+-- changes could be staged to a canary of agents. New installations get
+-- this table from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/009_rollouts.sql
+
+USE easy_any_link;
+
+-- Rollouts table: Routing and ACL rule changes staged to a canary of agents
+CREATE TABLE IF NOT EXISTS rollouts (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    kind ENUM('routing', 'acl') NOT NULL COMMENT 'Rule set the change applies to',
+    operation ENUM('add', 'update', 'delete') NOT NULL,
+    rule_id INT UNSIGNED COMMENT 'Rule updated or deleted, NULL for an added rule',
+    rule TEXT NOT NULL COMMENT 'JSON of the proposed rule, of the deleted rule for deletions',
+    percent TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Share of agents in the canary, 0 with a canary group',
+    group_id INT UNSIGNED COMMENT 'Agent group forming the canary, NULL for a share of agents',
+    state ENUM('staged', 'promoted', 'rolled_back') NOT NULL DEFAULT 'staged',
+    created_by VARCHAR(255) NOT NULL COMMENT 'Admin who staged the change',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES agent_groups(id) ON DELETE SET NULL,
+    INDEX idx_state (state)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Canary rollouts of rule changes, at most one staged at a time';
@@ -0,0 +1,12 @@
+-- EasyAnyLink migration: ACL rules for local users of shared hosts
+-- Upgrades databases created by init_db.sql before ACL rules could match
+-- the local user that sent the traffic. New installations get these
+-- changes from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/010_acl_source_uid.sql
+
+USE easy_any_link;
+
+-- Existing rules apply to all local users
+ALTER TABLE acl_rules
+    ADD COLUMN IF NOT EXISTS source_uid INT UNSIGNED COMMENT 'Local user on the agent host that sent the traffic, NULL for any' AFTER schedule;
@@ -0,0 +1,12 @@
+-- EasyAnyLink migration: allowed site prefixes per user
+-- Upgrades databases created by init_db.sql before the sites a user's
+-- gateways advertise could be limited. New installations get these
+-- changes from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/011_site_prefixes.sql
+
+USE easy_any_link;
+
+-- Existing users may keep advertising any site
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS site_prefixes VARCHAR(1024) COMMENT 'Comma-separated CIDRs the user''s gateways may advertise as sites, NULL for any' AFTER monthly_transfer_cap;
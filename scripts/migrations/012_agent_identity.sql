@@ -0,0 +1,13 @@
+-- EasyAnyLink migration: hardware identity keys of agents
+-- Upgrades databases created by init_db.sql before agents could be bound
+-- to a TPM or Secure Enclave key. New installations get these changes
+-- from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/012_agent_identity.sql
+
+USE easy_any_link;
+
+-- Existing agents are bound to a key at their next registration with one
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS identity_key VARCHAR(255) COMMENT 'Base64 PKIX public key of the hardware identity, NULL for none' AFTER group_id,
+    ADD COLUMN IF NOT EXISTS identity_provider VARCHAR(32) COMMENT 'tpm2 or secure-enclave' AFTER identity_key;
@@ -0,0 +1,15 @@
+-- EasyAnyLink migration: device posture policies on routing and ACL rules
+-- Upgrades databases created by init_db.sql before rules could require
+-- the agent to meet a posture policy. New installations get these
+-- changes from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/013_posture.sql
+
+USE easy_any_link;
+
+-- Existing rules apply to every device
+ALTER TABLE routing_rules
+    ADD COLUMN IF NOT EXISTS posture VARCHAR(64) COMMENT 'Posture policy the agent must meet, NULL for none' AFTER schedule;
+
+ALTER TABLE acl_rules
+    ADD COLUMN IF NOT EXISTS posture VARCHAR(64) COMMENT 'Posture policy the sending agent must meet for an allow rule, NULL for none' AFTER source_uid;
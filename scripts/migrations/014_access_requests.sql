@@ -0,0 +1,33 @@
+-- EasyAnyLink migration: just-in-time access requests
+-- Upgrades databases created by init_db.sql before agents could ask for
+-- temporary access for an admin to approve. New installations get this
+-- table from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/014_access_requests.sql
+
+USE easy_any_link;
+
+-- Access requests table: Temporary access agents asked for, and the grants approving them
+CREATE TABLE IF NOT EXISTS access_requests (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    agent_id VARCHAR(36) NOT NULL COMMENT 'Agent that asked for access',
+    destination VARCHAR(45) NOT NULL COMMENT 'Destination CIDR',
+    protocol ENUM('any', 'tcp', 'udp', 'icmp') DEFAULT 'any' NOT NULL,
+    port_from SMALLINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'First destination port, 0 for any',
+    port_to SMALLINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Last destination port',
+    duration INT UNSIGNED NOT NULL COMMENT 'Seconds of access asked for',
+    reason VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Justification given by the user',
+    state ENUM('pending', 'approved', 'denied', 'expired') NOT NULL DEFAULT 'pending',
+    decided_by VARCHAR(255) COMMENT 'Admin who approved or denied the request',
+    decided_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL COMMENT 'End of an approved grant',
+    routing_rule_id INT UNSIGNED COMMENT 'Routing rule of the grant, NULL without a gateway',
+    acl_rule_id INT UNSIGNED COMMENT 'ACL rule of the grant',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (agent_id) REFERENCES agents(id) ON DELETE CASCADE,
+    FOREIGN KEY (routing_rule_id) REFERENCES routing_rules(id) ON DELETE SET NULL,
+    FOREIGN KEY (acl_rule_id) REFERENCES acl_rules(id) ON DELETE SET NULL,
+    INDEX idx_agent_id (agent_id),
+    INDEX idx_state (state)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci 
+COMMENT='Just-in-time access requests, approved ones grant rules until expires_at';
@@ -0,0 +1,13 @@
+-- EasyAnyLink migration: SCIM user provisioning
+-- Upgrades databases created by init_db.sql before identity providers
+-- could provision users over SCIM. New installations get these changes
+-- from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/015_scim.sql
+
+USE easy_any_link;
+
+-- Existing users were not provisioned by an identity provider
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS external_id VARCHAR(255) COMMENT 'ID in the identity provider provisioning the user over SCIM, NULL for none' AFTER site_prefixes,
+    ADD INDEX IF NOT EXISTS idx_external_id (external_id);
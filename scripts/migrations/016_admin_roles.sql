@@ -0,0 +1,19 @@
+-- EasyAnyLink migration: admin roles and the admin API audit log
+-- Upgrades databases created by init_db.sql before the admin API had
+-- owner, admin, support and auditor roles. New installations get these
+-- changes from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/016_admin_roles.sql
+
+USE easy_any_link;
+
+-- Existing admins keep every permission they had, including managing
+-- other admins, as owners
+ALTER TABLE users
+    MODIFY COLUMN role ENUM('user', 'admin', 'owner', 'support', 'auditor') DEFAULT 'user' NOT NULL COMMENT 'Roles other than user may use the AdminService API';
+UPDATE users SET role = 'owner' WHERE role = 'admin';
+
+-- The principal of an audit log entry outlives its user
+ALTER TABLE audit_logs
+    ADD COLUMN IF NOT EXISTS username VARCHAR(255) COMMENT 'Principal at the time of the action' AFTER user_id,
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) COMMENT 'Role of the principal at the time of the action' AFTER username;
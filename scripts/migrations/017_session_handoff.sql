@@ -0,0 +1,11 @@
+-- EasyAnyLink migration: session handoff
+-- Upgrades databases created by init_db.sql before servers of a cluster
+-- could hand live sessions over to each other. New installations get
+-- these changes from init_db.sql. MariaDB 10.5+; safe to run more than once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/017_session_handoff.sql
+
+USE easy_any_link;
+
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS handoff_until TIMESTAMP NULL COMMENT 'Until when another server of the cluster may continue the session, NULL if not handed off' AFTER bytes_received;
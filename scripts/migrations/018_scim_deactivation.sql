@@ -0,0 +1,14 @@
+-- EasyAnyLink migration: SCIM deactivation
+-- Upgrades databases created by init_db.sql before the server told users
+-- an identity provider disabled from those an admin did. New installations
+-- get these changes from init_db.sql. MariaDB 10.5+; safe to run more than
+-- once.
+--
+-- Usage: mysql -u root -p < scripts/migrations/018_scim_deactivation.sql
+
+USE easy_any_link;
+
+-- Users disabled over SCIM before the upgrade count as disabled by an
+-- admin, who reactivates them
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS scim_deactivated TINYINT(1) NOT NULL DEFAULT 0 COMMENT '1=disabled by the identity provider, which may reactivate the user' AFTER external_id;
@@ -0,0 +1,410 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxPendingAccessRequests bounds the requests an agent can have
+	// waiting for an admin
+	maxPendingAccessRequests = 5
+
+	// maxAccessReason is the longest justification of a request
+	maxAccessReason = 255
+
+	defaultAccessRequestLimit = 50
+)
+
+// accessRequestEvent is the data of access.requested events
+type accessRequestEvent struct {
+	RequestID   int    `json:"request_id"`
+	AgentID     string `json:"agent_id"`
+	UserID      string `json:"user_id"`
+	Destination string `json:"destination"`
+	Protocol    string `json:"protocol"`
+	PortFrom    int    `json:"port_from,omitempty"`
+	PortTo      int    `json:"port_to,omitempty"`
+	Duration    int    `json:"duration"` // seconds
+	Reason      string `json:"reason,omitempty"`
+}
+
+// agentSession returns the live session of a call of an agent on its
+// session
+func (s *Server) agentSession(sessionID, agentID string) (*SessionInfo, error) {
+	value, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return nil, errcode.Status(codes.NotFound, errcode.SessionNotFound, "session not found")
+	}
+	si := value.(*SessionInfo)
+	if si.AgentID != agentID {
+		return nil, status.Errorf(codes.PermissionDenied, "session does not belong to agent %s", agentID)
+	}
+	return si, nil
+}
+
+// RequestAccess records a request of an agent for temporary access to a
+// destination and notifies admins through the access.requested webhook
+// event. Nothing is granted until an admin approves it.
+func (s *Server) RequestAccess(ctx context.Context, req *proto.RequestAccessRequest) (*proto.AccessRequest, error) {
+	si, err := s.agentSession(req.SessionId, req.AgentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Destination == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "destination is required")
+	}
+	// Checked as the ACL rule granting it, a bare address is one host
+	destination := req.Destination
+	if !strings.Contains(destination, "/") {
+		destination += "/32"
+	}
+	grant, err := s.validateACLRule(&proto.ACLRule{
+		UserId:      si.UserID,
+		Destination: destination,
+		Protocol:    req.Protocol,
+		PortFrom:    req.PortFrom,
+		PortTo:      req.PortTo,
+		Action:      "allow",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maxDuration := s.config.Security.MaxAccessDuration * 60
+	if req.Duration <= 0 || int(req.Duration) > maxDuration {
+		return nil, status.Errorf(codes.InvalidArgument, "duration must be between 1 and %d seconds", maxDuration)
+	}
+	if len(req.Reason) > maxAccessReason {
+		return nil, status.Errorf(codes.InvalidArgument, "reason must be at most %d bytes", maxAccessReason)
+	}
+
+	pending, err := s.db.CountPendingAccessRequests(si.AgentID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count access requests: %v", err)
+	}
+	if pending >= maxPendingAccessRequests {
+		return nil, status.Errorf(codes.ResourceExhausted, "agent %s already has %d pending access requests", si.AgentID, pending)
+	}
+
+	r := &AccessRequest{
+		AgentID:     si.AgentID,
+		UserID:      si.UserID,
+		Destination: grant.Destination,
+		Protocol:    grant.Protocol,
+		PortFrom:    grant.PortFrom,
+		PortTo:      grant.PortTo,
+		Duration:    int(req.Duration),
+		Reason:      strings.TrimSpace(req.Reason),
+	}
+	if err := s.db.CreateAccessRequest(r); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create access request: %v", err)
+	}
+
+	log.Printf("Agent %s requested access to %s for %s, request %d",
+		r.AgentID, describeAccess(r), time.Duration(r.Duration)*time.Second, r.ID)
+	s.webhooks.publish(EventAccessRequested, &accessRequestEvent{
+		RequestID:   r.ID,
+		AgentID:     r.AgentID,
+		UserID:      r.UserID,
+		Destination: r.Destination,
+		Protocol:    r.Protocol,
+		PortFrom:    r.PortFrom,
+		PortTo:      r.PortTo,
+		Duration:    r.Duration,
+		Reason:      r.Reason,
+	})
+
+	created, err := s.db.GetAccessRequest(r.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get access request: %v", err)
+	}
+	return accessRequestToProto(created), nil
+}
+
+// GetAccessRequests returns the access requests of an agent, newest first
+func (s *Server) GetAccessRequests(ctx context.Context, req *proto.AccessRequestsRequest) (*proto.AccessRequestsResponse, error) {
+	if _, err := s.agentSession(req.SessionId, req.AgentId); err != nil {
+		return nil, err
+	}
+
+	requests, err := s.db.ListAccessRequests(AccessRequestFilter{AgentID: req.AgentId, Limit: defaultAccessRequestLimit})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list access requests: %v", err)
+	}
+
+	resp := &proto.AccessRequestsResponse{Requests: make([]*proto.AccessRequest, 0, len(requests))}
+	for _, r := range requests {
+		resp.Requests = append(resp.Requests, accessRequestToProto(r))
+	}
+	return resp, nil
+}
+
+// ListAccessRequests returns the access requests of agents, newest first
+func (s *Server) ListAccessRequests(ctx context.Context, req *proto.ListAccessRequestsRequest) (*proto.ListAccessRequestsResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	switch req.State {
+	case "", "pending", "approved", "denied", "expired":
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid state %q", req.State)
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultAccessRequestLimit
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	requests, err := s.db.ListAccessRequests(AccessRequestFilter{State: req.State, AgentID: req.AgentId, Limit: limit})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list access requests: %v", err)
+	}
+
+	resp := &proto.ListAccessRequestsResponse{Requests: make([]*proto.AccessRequest, 0, len(requests))}
+	for _, r := range requests {
+		resp.Requests = append(resp.Requests, accessRequestToProto(r))
+	}
+	return resp, nil
+}
+
+// ApproveAccessRequest grants a pending access request. The grant is an
+// ACL rule of the agent's user allowing traffic from the agent's overlay
+// IP to the destination and, with a gateway, a forward rule of the agent
+// routing the destination through it. Both take precedence over the
+// existing rules, are valid until the grant expires and are deleted then.
+func (s *Server) ApproveAccessRequest(ctx context.Context, req *proto.ApproveAccessRequestRequest) (*proto.AccessRequest, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.db.GetAccessRequest(int(req.RequestId))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "access request %d not found", req.RequestId)
+	}
+	if r.State != "pending" {
+		return nil, status.Errorf(codes.FailedPrecondition, "access request %d is %s, not pending", r.ID, r.State)
+	}
+	duration := int(req.Duration)
+	if duration < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "duration must not be negative")
+	}
+	if duration == 0 {
+		duration = r.Duration
+	}
+
+	agent, err := s.db.GetAgentByID(r.AgentID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s not found", r.AgentID)
+	}
+	if !agent.DeletedAt.IsZero() || agent.IPAddress == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "agent %s is archived or has no overlay IP", r.AgentID)
+	}
+
+	now := time.Now()
+	r.DecidedBy = admin.Username
+	r.DecidedAt = now
+	r.ExpiresAt = now.Add(time.Duration(duration) * time.Second)
+	window := windowToProto(AccessWindow{ValidUntil: r.ExpiresAt})
+
+	aclRules, err := s.db.ListACLRules(r.UserID, false)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list ACL rules: %v", err)
+	}
+	aclRule, err := s.validateACLRule(&proto.ACLRule{
+		UserId:      r.UserID,
+		Source:      agent.IPAddress + "/32",
+		Destination: r.Destination,
+		Protocol:    r.Protocol,
+		PortFrom:    uint32(r.PortFrom),
+		PortTo:      uint32(r.PortTo),
+		Action:      "allow",
+		Priority:    int32(grantPriority(aclRules, func(rule *ACLRule) int { return rule.Priority })),
+		Enabled:     true,
+		Window:      window,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var routingRule *RoutingRule
+	if req.GatewayId != "" {
+		routes, err := s.db.GetRoutingRulesByAgentID(agent.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get routing rules: %v", err)
+		}
+		routingRule, err = s.validateRoutingRule(agent.ID, 0, 0, &proto.RoutingRule{
+			Action:      proto.RouteAction_FORWARD,
+			Destination: r.Destination,
+			GatewayId:   req.GatewayId,
+			Priority:    int32(grantPriority(routes, func(rule *RoutingRule) int { return rule.Priority })),
+			Enabled:     true,
+			Window:      window,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.db.CreateACLRule(aclRule); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create ACL rule: %v", err)
+	}
+	r.ACLRuleID = aclRule.ID
+	if routingRule != nil {
+		if err := s.db.CreateRoutingRule(routingRule); err != nil {
+			s.db.DeleteACLRule(aclRule.ID)
+			return nil, status.Errorf(codes.Internal, "failed to create routing rule: %v", err)
+		}
+		r.RoutingRuleID = routingRule.ID
+	}
+	if err := s.db.ApproveAccessRequest(r); err != nil {
+		s.deleteAccessGrant(r)
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	s.acls.delete(r.UserID)
+	if routingRule != nil {
+		s.notifyRuleChange(routingRule)
+	}
+
+	log.Printf("Access request %d of agent %s to %s approved by %s until %s",
+		r.ID, r.AgentID, describeAccess(r), admin.Username, r.ExpiresAt.Format(time.RFC3339))
+
+	approved, err := s.db.GetAccessRequest(r.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get access request: %v", err)
+	}
+	return accessRequestToProto(approved), nil
+}
+
+// DenyAccessRequest denies a pending access request
+func (s *Server) DenyAccessRequest(ctx context.Context, req *proto.DenyAccessRequestRequest) (*proto.AccessRequest, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.db.GetAccessRequest(int(req.RequestId))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "access request %d not found", req.RequestId)
+	}
+	if err := s.db.DenyAccessRequest(r.ID, admin.Username, time.Now()); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	log.Printf("Access request %d of agent %s to %s denied by %s", r.ID, r.AgentID, describeAccess(r), admin.Username)
+
+	denied, err := s.db.GetAccessRequest(r.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get access request: %v", err)
+	}
+	return accessRequestToProto(denied), nil
+}
+
+// expireAccessGrants deletes the rules of the access grants that ended by
+// now. Their access windows already closed, this removes them from the
+// rule lists and refreshes the routes of the agents.
+func (s *Server) expireAccessGrants(now time.Time) {
+	grants, err := s.db.ListExpiredAccessGrants(now)
+	if err != nil {
+		log.Printf("Failed to check access grants: %v", err)
+		return
+	}
+
+	for _, r := range grants {
+		// Claimed first, so that one server of a cluster deletes the rules
+		claimed, err := s.db.ExpireAccessRequest(r.ID)
+		if err != nil {
+			log.Printf("Failed to expire access request %d: %v", r.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.deleteAccessGrant(r)
+		log.Printf("Access grant %d of agent %s to %s expired", r.ID, r.AgentID, describeAccess(r))
+	}
+}
+
+// deleteAccessGrant deletes the rules granting an access request
+func (s *Server) deleteAccessGrant(r *AccessRequest) {
+	if r.ACLRuleID != 0 {
+		if err := s.db.DeleteACLRule(r.ACLRuleID); err != nil {
+			log.Printf("Failed to delete ACL rule %d of access request %d: %v", r.ACLRuleID, r.ID, err)
+		}
+		s.acls.delete(r.UserID)
+	}
+	if r.RoutingRuleID != 0 {
+		if err := s.db.DeleteRoutingRule(r.RoutingRuleID); err != nil {
+			log.Printf("Failed to delete routing rule %d of access request %d: %v", r.RoutingRuleID, r.ID, err)
+		}
+		s.notifyRouteChange(r.AgentID)
+	}
+}
+
+// grantPriority returns a priority ahead of all the rules, so that a grant
+// is not shadowed by a broader deny rule
+func grantPriority[T any](rules []T, priority func(T) int) int {
+	if len(rules) == 0 {
+		return 0
+	}
+	lowest := priority(rules[0])
+	for _, rule := range rules[1:] {
+		lowest = min(lowest, priority(rule))
+	}
+	return lowest - 1
+}
+
+// describeAccess describes the traffic an access request is for, e.g.
+// "10.1.0.0/16 tcp/22"
+func describeAccess(r *AccessRequest) string {
+	switch {
+	case r.PortFrom == 0 && r.Protocol == "any":
+		return r.Destination
+	case r.PortFrom == 0:
+		return r.Destination + " " + r.Protocol
+	case r.PortTo == r.PortFrom:
+		return fmt.Sprintf("%s %s/%d", r.Destination, r.Protocol, r.PortFrom)
+	}
+	return fmt.Sprintf("%s %s/%d-%d", r.Destination, r.Protocol, r.PortFrom, r.PortTo)
+}
+
+// accessRequestToProto converts an access request to proto format
+func accessRequestToProto(r *AccessRequest) *proto.AccessRequest {
+	pr := &proto.AccessRequest{
+		RequestId:     int32(r.ID),
+		AgentId:       r.AgentID,
+		UserId:        r.UserID,
+		Destination:   r.Destination,
+		Protocol:      r.Protocol,
+		PortFrom:      uint32(r.PortFrom),
+		PortTo:        uint32(r.PortTo),
+		Duration:      int32(r.Duration),
+		Reason:        r.Reason,
+		State:         r.State,
+		RequestedAt:   timestamppb.New(r.CreatedAt),
+		DecidedBy:     r.DecidedBy,
+		AclRuleId:     int32(r.ACLRuleID),
+		RoutingRuleId: int32(r.RoutingRuleID),
+	}
+	if !r.DecidedAt.IsZero() {
+		pr.DecidedAt = timestamppb.New(r.DecidedAt)
+	}
+	if !r.ExpiresAt.IsZero() {
+		pr.ExpiresAt = timestamppb.New(r.ExpiresAt)
+	}
+	return pr
+}
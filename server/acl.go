@@ -0,0 +1,363 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"log"
+	"net"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// aclCacheTTL bounds how long rules changed through another server
+// instance take to apply here; changes made here apply immediately
+const aclCacheTTL = 30 * time.Second
+
+// aclProtocols maps ACL protocol names to IP protocol numbers
+var aclProtocols = map[string]uint8{
+	"tcp":  packet.ProtocolTCP,
+	"udp":  packet.ProtocolUDP,
+	"icmp": packet.ProtocolICMP,
+}
+
+// aclMatcher is an enabled ACL rule prepared for matching packets
+type aclMatcher struct {
+	id          int
+	source      *net.IPNet // nil for any
+	destination *net.IPNet // nil for any
+	protocol    uint8      // 0 for any
+	portFrom    uint16     // 0 for any
+	portTo      uint16
+	sourceUID   *uint32 // nil for any local user
+	allow       bool
+	window      *accessWindow // nil if the rule always applies
+	posture     string        // policy the sending agent must meet, empty for none
+}
+
+// matches reports whether the rule applies to a packet. Ports are only
+// known for TCP and UDP packets that are not later fragments, so port
+// rules never match other packets. Likewise, rules for a local user only
+// match packets the agent tagged with that user's uid, and rules of a
+// posture policy only packets of agents meeting it.
+func (m *aclMatcher) matches(h *packet.IPv4, port uint16, hasPort bool, uid *uint32, posture *postureState) bool {
+	if m.source != nil && !m.source.Contains(h.Src) {
+		return false
+	}
+	if m.destination != nil && !m.destination.Contains(h.Dst) {
+		return false
+	}
+	if m.protocol != 0 && m.protocol != h.Protocol {
+		return false
+	}
+	if m.portFrom != 0 && (!hasPort || port < m.portFrom || port > m.portTo) {
+		return false
+	}
+	if m.sourceUID != nil && (uid == nil || *uid != *m.sourceUID) {
+		return false
+	}
+	if !posture.meets(m.posture) {
+		return false
+	}
+	return m.window == nil || m.window.active(time.Now())
+}
+
+// aclAllows evaluates the ACL rules of the user of a session against a
+// packet sent by its agent. The first matching rule decides; without a
+// match the packet is allowed. Agents in the canary of a staged ACL
+// rollout are checked against the rules with the change applied.
+func (s *Server) aclAllows(si *SessionInfo, dp *proto.DataPacket, h *packet.IPv4) (bool, int, error) {
+	matchers, err := s.sessionACLMatchers(si)
+	if err != nil {
+		return false, 0, err
+	}
+	if len(matchers) == 0 {
+		return true, 0, nil
+	}
+
+	port, hasPort := destinationPort(dp.Payload, h)
+	if m := firstMatch(matchers, h, port, hasPort, dp.SourceUid, si.posture.Load()); m != nil {
+		return m.allow, m.id, nil
+	}
+	return true, 0, nil
+}
+
+// firstMatch returns the first rule matching a packet, nil if none does
+func firstMatch(matchers []*aclMatcher, h *packet.IPv4, port uint16, hasPort bool, uid *uint32, posture *postureState) *aclMatcher {
+	for _, m := range matchers {
+		if m.matches(h, port, hasPort, uid, posture) {
+			return m
+		}
+	}
+	return nil
+}
+
+// aclMatchers returns the prepared enabled ACL rules of a user
+func (s *Server) aclMatchers(userID string) ([]*aclMatcher, error) {
+	if cached, ok := s.acls.get(userID); ok {
+		return cached.([]*aclMatcher), nil
+	}
+
+	rules, err := s.db.ListACLRules(userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	matchers := prepareACLMatchers(rules)
+	s.acls.set(userID, matchers)
+	return matchers, nil
+}
+
+// prepareACLMatchers prepares enabled ACL rules, in order, for matching
+func prepareACLMatchers(rules []*ACLRule) []*aclMatcher {
+	var err error
+	matchers := make([]*aclMatcher, 0, len(rules))
+	for _, rule := range rules {
+		m := &aclMatcher{
+			id:        rule.ID,
+			protocol:  aclProtocols[rule.Protocol],
+			portFrom:  uint16(rule.PortFrom),
+			portTo:    uint16(rule.PortTo),
+			sourceUID: rule.SourceUID,
+			allow:     rule.Action == "allow",
+			posture:   rule.Posture,
+		}
+		// Rules are validated when stored, a broken one is skipped
+		if m.window, err = rule.Window.parse(); err != nil {
+			log.Printf("Skipping ACL rule %d: %v", rule.ID, err)
+			continue
+		}
+		if rule.Source != "" {
+			if _, m.source, err = net.ParseCIDR(rule.Source); err != nil {
+				log.Printf("Skipping ACL rule %d: %v", rule.ID, err)
+				continue
+			}
+		}
+		if rule.Destination != "" {
+			if _, m.destination, err = net.ParseCIDR(rule.Destination); err != nil {
+				log.Printf("Skipping ACL rule %d: %v", rule.ID, err)
+				continue
+			}
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers
+}
+
+// destinationPort returns the TCP or UDP destination port of a packet
+func destinationPort(payload []byte, h *packet.IPv4) (uint16, bool) {
+	if h.Protocol != packet.ProtocolTCP && h.Protocol != packet.ProtocolUDP {
+		return 0, false
+	}
+	// Later fragments carry no transport header
+	if binary.BigEndian.Uint16(payload[6:8])&0x1fff != 0 || len(h.Payload) < 4 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(h.Payload[2:4]), true
+}
+
+// ListACLRules returns all ACL rules of a user
+func (s *Server) ListACLRules(ctx context.Context, req *proto.ListACLRulesRequest) (*proto.ListACLRulesResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.db.ListACLRules(req.UserId, false)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list ACL rules: %v", err)
+	}
+
+	resp := &proto.ListACLRulesResponse{Rules: make([]*proto.ACLRule, 0, len(rules))}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, aclRuleToProto(rule))
+	}
+	return resp, nil
+}
+
+// AddACLRule creates an ACL rule for a user
+func (s *Server) AddACLRule(ctx context.Context, req *proto.AddACLRuleRequest) (*proto.ACLRule, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.validateACLRule(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.db.GetUserByID(rule.UserID); err != nil {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", rule.UserID)
+	}
+
+	if err := s.db.CreateACLRule(rule); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create ACL rule: %v", err)
+	}
+	s.acls.delete(rule.UserID)
+
+	log.Printf("ACL rule %d added for user %s by %s", rule.ID, rule.UserID, admin.Username)
+	return aclRuleToProto(rule), nil
+}
+
+// UpdateACLRule replaces an existing ACL rule. The owning user cannot be
+// changed.
+func (s *Server) UpdateACLRule(ctx context.Context, req *proto.UpdateACLRuleRequest) (*proto.ACLRule, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+	if req.Rule == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "rule is required")
+	}
+
+	existing, err := s.db.GetACLRuleByID(int(req.Rule.RuleId))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "ACL rule %d not found", req.Rule.RuleId)
+	}
+	if req.Rule.UserId != "" && req.Rule.UserId != existing.UserID {
+		return nil, status.Errorf(codes.InvalidArgument, "the user of an ACL rule cannot be changed")
+	}
+
+	req.Rule.UserId = existing.UserID
+	rule, err := s.validateACLRule(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = existing.ID
+
+	if err := s.db.UpdateACLRule(rule); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update ACL rule: %v", err)
+	}
+	s.acls.delete(rule.UserID)
+
+	log.Printf("ACL rule %d updated for user %s by %s", rule.ID, rule.UserID, admin.Username)
+
+	updated, err := s.db.GetACLRuleByID(rule.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get ACL rule: %v", err)
+	}
+	return aclRuleToProto(updated), nil
+}
+
+// DeleteACLRule removes an ACL rule
+func (s *Server) DeleteACLRule(ctx context.Context, req *proto.DeleteACLRuleRequest) (*proto.DeleteACLRuleResponse, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.db.GetACLRuleByID(int(req.RuleId))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "ACL rule %d not found", req.RuleId)
+	}
+
+	if err := s.db.DeleteACLRule(existing.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete ACL rule: %v", err)
+	}
+	s.acls.delete(existing.UserID)
+
+	log.Printf("ACL rule %d deleted for user %s by %s", existing.ID, existing.UserID, admin.Username)
+	return &proto.DeleteACLRuleResponse{Deleted: true, UserId: existing.UserID}, nil
+}
+
+// validateACLRule checks a proto ACL rule and converts it to a database
+// record with normalized CIDRs
+func (s *Server) validateACLRule(pr *proto.ACLRule) (*ACLRule, error) {
+	if pr == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "rule is required")
+	}
+	if pr.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id is required")
+	}
+
+	rule := &ACLRule{
+		UserID:    pr.UserId,
+		Protocol:  pr.Protocol,
+		Action:    pr.Action,
+		Priority:  int(pr.Priority),
+		Enabled:   pr.Enabled,
+		SourceUID: pr.SourceUid,
+		Posture:   pr.Posture,
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+		dest  *string
+	}{
+		{"source", pr.Source, &rule.Source},
+		{"destination", pr.Destination, &rule.Destination},
+	} {
+		if field.value == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(field.value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s CIDR %q: %v", field.name, field.value, err)
+		}
+		if ipNet.IP.To4() == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be an IPv4 CIDR, the overlay carries IPv4 only", field.name)
+		}
+		*field.dest = ipNet.String()
+	}
+
+	if rule.Protocol == "" {
+		rule.Protocol = "any"
+	}
+	if _, ok := aclProtocols[rule.Protocol]; !ok && rule.Protocol != "any" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid protocol %q", pr.Protocol)
+	}
+
+	if pr.PortFrom > 65535 || pr.PortTo > 65535 {
+		return nil, status.Errorf(codes.InvalidArgument, "ports must be at most 65535")
+	}
+	rule.PortFrom, rule.PortTo = int(pr.PortFrom), int(pr.PortTo)
+	if rule.PortFrom == 0 && rule.PortTo != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "port_to requires port_from")
+	}
+	if rule.PortTo == 0 {
+		rule.PortTo = rule.PortFrom
+	}
+	if rule.PortTo < rule.PortFrom {
+		return nil, status.Errorf(codes.InvalidArgument, "port_to must not be below port_from")
+	}
+	if rule.PortFrom != 0 && rule.Protocol != "tcp" && rule.Protocol != "udp" {
+		return nil, status.Errorf(codes.InvalidArgument, "ports require protocol tcp or udp")
+	}
+
+	if rule.Action != "allow" && rule.Action != "deny" {
+		return nil, status.Errorf(codes.InvalidArgument, "action must be allow or deny")
+	}
+	if err := s.validatePosture(rule.Posture, rule.Action == "deny"); err != nil {
+		return nil, err
+	}
+
+	window, err := windowFromProto(pr.Window)
+	if err != nil {
+		return nil, err
+	}
+	rule.Window = window
+
+	return rule, nil
+}
+
+// aclRuleToProto converts a database ACL rule to proto format
+func aclRuleToProto(rule *ACLRule) *proto.ACLRule {
+	return &proto.ACLRule{
+		RuleId:      int32(rule.ID),
+		UserId:      rule.UserID,
+		Source:      rule.Source,
+		Destination: rule.Destination,
+		Protocol:    rule.Protocol,
+		PortFrom:    uint32(rule.PortFrom),
+		PortTo:      uint32(rule.PortTo),
+		Action:      rule.Action,
+		Priority:    int32(rule.Priority),
+		Enabled:     rule.Enabled,
+		Window:      windowToProto(rule.Window),
+		SourceUid:   rule.SourceUID,
+		Posture:     rule.Posture,
+	}
+}
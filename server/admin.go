@@ -0,0 +1,821 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AdminAPIKeyHeader is the metadata key carrying the admin API key
+const AdminAPIKeyHeader = "x-api-key"
+
+// Pagination limits for list RPCs
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// defaultUsageMonths is the usage history returned by GetUser by default
+const defaultUsageMonths = 12
+
+// authorizeAdmin authenticates the caller and requires a staff role with
+// the permission a call needs. Calls needing more than permRead and calls
+// the role does not permit are audited.
+func (s *Server) authorizeAdmin(ctx context.Context, need permission) (*User, error) {
+	apiKey := s.GetMetadata(ctx, AdminAPIKeyHeader)
+	if apiKey == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "missing %s metadata", AdminAPIKeyHeader)
+	}
+
+	user, err := s.db.GetUserByAPIKey(apiKey)
+	if err != nil {
+		s.authFailed(ctx, "AdminService")
+		return nil, status.Errorf(codes.Unauthenticated, "authentication failed")
+	}
+	s.authSucceeded(ctx)
+
+	action := adminAction(ctx)
+	if !rolePermits(user.Role, need) {
+		s.audit(ctx, user, action, false)
+		return nil, status.Error(codes.PermissionDenied, deniedMessage(user.Role, action))
+	}
+	if need > permRead {
+		s.audit(ctx, user, action, true)
+	}
+
+	return user, nil
+}
+
+// AddRoutingRule creates a routing rule for an agent or an agent group
+func (s *Server) AddRoutingRule(ctx context.Context, req *proto.AddRoutingRuleRequest) (*proto.RoutingRuleResponse, error) {
+	user, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case (req.AgentId == "") == (req.GroupId == 0):
+		return nil, status.Errorf(codes.InvalidArgument, "exactly one of agent_id and group_id is required")
+	case req.GroupId != 0:
+		if _, err := s.db.GetAgentGroup(int(req.GroupId)); err != nil {
+			return nil, status.Errorf(codes.NotFound, "agent group %d not found", req.GroupId)
+		}
+	default:
+		if _, err := s.db.GetAgentByID(req.AgentId); err != nil {
+			return nil, status.Errorf(codes.NotFound, "agent %s not found", req.AgentId)
+		}
+	}
+
+	rule, err := s.validateRoutingRule(req.AgentId, int(req.GroupId), 0, req.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.CreateRoutingRule(rule); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create routing rule: %v", err)
+	}
+
+	s.notifyRuleChange(rule)
+	log.Printf("Routing rule %d added for %s by %s", rule.ID, ruleOwner(rule), user.Username)
+
+	return &proto.RoutingRuleResponse{
+		AgentId: rule.AgentID,
+		GroupId: int32(rule.GroupID),
+		Rule:    routingRuleToProto(rule),
+	}, nil
+}
+
+// UpdateRoutingRule replaces an existing routing rule
+func (s *Server) UpdateRoutingRule(ctx context.Context, req *proto.UpdateRoutingRuleRequest) (*proto.RoutingRuleResponse, error) {
+	user, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Rule == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "rule is required")
+	}
+
+	existing, err := s.db.GetRoutingRuleByID(int(req.Rule.RuleId))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "routing rule %d not found", req.Rule.RuleId)
+	}
+
+	rule, err := s.validateRoutingRule(existing.AgentID, existing.GroupID, existing.ID, req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = existing.ID
+
+	if err := s.db.UpdateRoutingRule(rule); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update routing rule: %v", err)
+	}
+
+	s.notifyRuleChange(rule)
+	s.alerts.ruleChanged(rule.ID)
+	log.Printf("Routing rule %d updated for %s by %s", rule.ID, ruleOwner(rule), user.Username)
+
+	return &proto.RoutingRuleResponse{
+		AgentId: rule.AgentID,
+		GroupId: int32(rule.GroupID),
+		Rule:    routingRuleToProto(rule),
+	}, nil
+}
+
+// DeleteRoutingRule removes a routing rule
+func (s *Server) DeleteRoutingRule(ctx context.Context, req *proto.DeleteRoutingRuleRequest) (*proto.DeleteRoutingRuleResponse, error) {
+	user, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.db.GetRoutingRuleByID(int(req.RuleId))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "routing rule %d not found", req.RuleId)
+	}
+
+	if err := s.db.DeleteRoutingRule(existing.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete routing rule: %v", err)
+	}
+
+	s.notifyRuleChange(existing)
+	s.alerts.ruleChanged(existing.ID)
+	log.Printf("Routing rule %d deleted for %s by %s", existing.ID, ruleOwner(existing), user.Username)
+
+	return &proto.DeleteRoutingRuleResponse{
+		Deleted: true,
+		AgentId: existing.AgentID,
+		GroupId: int32(existing.GroupID),
+	}, nil
+}
+
+// notifyRuleChange has the connected agents a changed rule applies to
+// re-fetch their routes
+func (s *Server) notifyRuleChange(rule *RoutingRule) {
+	if rule.GroupID != 0 {
+		for _, agentID := range s.connectedMembers(rule.GroupID) {
+			s.notifyRouteChange(agentID)
+		}
+		return
+	}
+	s.notifyRouteChange(rule.AgentID)
+}
+
+// ruleOwner describes the agent or group a routing rule applies to
+func ruleOwner(rule *RoutingRule) string {
+	if rule.GroupID != 0 {
+		return fmt.Sprintf("group %d", rule.GroupID)
+	}
+	return "agent " + rule.AgentID
+}
+
+// validateRoutingRule checks a proto rule of an agent or a group and
+// converts it to a database record. ruleID is the ID of the rule being
+// updated, or 0 for a new rule.
+func (s *Server) validateRoutingRule(agentID string, groupID, ruleID int, pr *proto.RoutingRule) (*RoutingRule, error) {
+	if pr == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "rule is required")
+	}
+
+	// Normalize destination CIDR
+	_, ipNet, err := net.ParseCIDR(pr.Destination)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid destination CIDR %q: %v", pr.Destination, err)
+	}
+
+	rule := &RoutingRule{
+		AgentID:     agentID,
+		GroupID:     groupID,
+		Destination: ipNet.String(),
+		Priority:    int(pr.Priority),
+		Enabled:     pr.Enabled,
+	}
+	if rule.Window, err = windowFromProto(pr.Window); err != nil {
+		return nil, err
+	}
+	if err := s.validatePosture(pr.Posture, pr.Action == proto.RouteAction_DENY); err != nil {
+		return nil, err
+	}
+	rule.Posture = pr.Posture
+
+	switch pr.Action {
+	case proto.RouteAction_FORWARD:
+		rule.Action = "forward"
+		if pr.GatewayId == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "gateway_id is required for forward rules")
+		}
+		gateway, err := s.db.GetAgentByID(pr.GatewayId)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "gateway %s not found", pr.GatewayId)
+		}
+		if !gateway.DeletedAt.IsZero() {
+			return nil, status.Errorf(codes.FailedPrecondition, "gateway %s is archived", pr.GatewayId)
+		}
+		if !strings.EqualFold(gateway.Type, "gateway") {
+			return nil, status.Errorf(codes.FailedPrecondition, "agent %s is not a gateway", pr.GatewayId)
+		}
+		rule.GatewayID = gateway.ID
+	case proto.RouteAction_DIRECT:
+		rule.Action = "direct"
+	case proto.RouteAction_DENY:
+		rule.Action = "deny"
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid route action")
+	}
+
+	// Enabled rules of the same agent or group must have distinct
+	// priorities, an agent's own rule wins over a group rule of its
+	// priority. Forward rules for the same prefix through different
+	// gateways may share one, they are equal-cost paths.
+	if rule.Enabled {
+		var rules []*RoutingRule
+		if groupID != 0 {
+			rules, err = s.db.ListGroupRoutingRules(groupID)
+		} else {
+			rules, err = s.db.GetRoutingRulesByAgentID(agentID)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get routing rules: %v", err)
+		}
+		for _, other := range rules {
+			if other.Enabled && other.GroupID == groupID && other.ID != ruleID && other.Priority == rule.Priority &&
+				!equalCost(rule, other) {
+				return nil, status.Errorf(codes.AlreadyExists,
+					"priority %d already used by rule %d", rule.Priority, other.ID)
+			}
+		}
+	}
+
+	return rule, nil
+}
+
+// equalCost reports whether two rules are equal-cost paths: forward rules
+// for the same prefix through different gateways
+func equalCost(a, b *RoutingRule) bool {
+	return a.Action == "forward" && b.Action == "forward" &&
+		a.Destination == b.Destination && a.GatewayID != b.GatewayID
+}
+
+// ListAgents returns a page of agents from the registry
+func (s *Server) ListAgents(ctx context.Context, req *proto.ListAgentsRequest) (*proto.ListAgentsResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	filter := AgentFilter{
+		UserID:  req.UserId,
+		Labels:  req.Labels,
+		AfterID: req.PageToken,
+		Limit:   pageSize + 1, // fetch one extra to detect the next page
+		Deleted: req.Archived,
+		Pending: req.Pending,
+	}
+	if req.Type != proto.AgentType_AGENT_TYPE_UNSPECIFIED {
+		filter.Type = strings.ToLower(req.Type.String())
+	}
+	if req.Status != proto.AgentStatus_AGENT_STATUS_UNSPECIFIED {
+		filter.Status = strings.ToLower(req.Status.String())
+	}
+
+	agents, err := s.db.ListAgents(filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list agents: %v", err)
+	}
+
+	resp := &proto.ListAgentsResponse{}
+	if len(agents) > pageSize {
+		agents = agents[:pageSize]
+		resp.NextPageToken = agents[pageSize-1].ID
+	}
+
+	resp.Agents = make([]*proto.AgentDetail, 0, len(agents))
+	for _, agent := range agents {
+		resp.Agents = append(resp.Agents, s.agentDetail(agent))
+	}
+
+	return resp, nil
+}
+
+// GetAgent returns a single agent from the registry
+func (s *Server) GetAgent(ctx context.Context, req *proto.GetAgentRequest) (*proto.AgentDetail, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	agent, err := s.db.GetAgentByID(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s not found", req.AgentId)
+	}
+
+	return s.agentDetail(agent), nil
+}
+
+// agentDetail combines a database agent record with live session state
+func (s *Server) agentDetail(agent *Agent) *proto.AgentDetail {
+	detail := &proto.AgentDetail{
+		AgentId:   agent.ID,
+		UserId:    agent.UserID,
+		Name:      agent.Name,
+		Type:      agentTypeFromString(agent.Type),
+		Status:    agentStatusFromString(agent.Status),
+		IpAddress: agent.IPAddress,
+		PublicIp:  agent.PublicIP,
+		CreatedAt: timestamppb.New(agent.CreatedAt),
+		Pending:   agent.Pending,
+		Identity:  agentIdentity(agent),
+	}
+
+	if agent.Metadata != "" {
+		metadata := &proto.AgentMetadata{}
+		if err := json.Unmarshal([]byte(agent.Metadata), metadata); err == nil {
+			detail.Metadata = metadata
+		}
+	}
+	if !agent.LastHeartbeat.IsZero() {
+		detail.LastSeen = timestamppb.New(agent.LastHeartbeat)
+	}
+	if !agent.DeletedAt.IsZero() {
+		detail.ArchivedAt = timestamppb.New(agent.DeletedAt)
+	}
+	if agent.GroupID != 0 {
+		if group, err := s.db.GetAgentGroup(agent.GroupID); err == nil {
+			detail.Group = group.Name
+		}
+	}
+
+	if si := s.findSessionByAgent(agent.ID); si != nil {
+		si.mu.RLock()
+		detail.SessionId = si.SessionID
+		detail.Connected = true
+		detail.LastSeen = timestamppb.New(si.LastActivity)
+		if si.Stats != nil {
+			detail.Stats = si.Stats
+		} else {
+			detail.Stats = &proto.AgentStats{
+				BytesSent:     si.BytesSent,
+				BytesReceived: si.BytesReceived,
+			}
+		}
+		detail.Health = si.Health
+		detail.ApiVersion = int32(si.apiVersion)
+		detail.Capabilities = si.capabilities
+		for _, prefix := range si.sites {
+			detail.SiteSubnets = append(detail.SiteSubnets, prefix.String())
+		}
+		detail.ConfigGeneration = si.config.applied
+		detail.ConfigDrifted = si.config.drifted
+		detail.ClockSkewMs = si.clock.skew.Milliseconds()
+		detail.NotReady = !si.ready.Load()
+		si.mu.RUnlock()
+		if posture := si.posture.Load(); posture != nil {
+			detail.PostureFailures = posture.failures
+		}
+		detail.FlowControl = si.flowControl(time.Now(), s.slowConsumerStall())
+		detail.Resources = si.resources.stats()
+	}
+
+	return detail
+}
+
+// findSessionByAgent returns the live session of an agent, if any
+func (s *Server) findSessionByAgent(agentID string) *SessionInfo {
+	var found *SessionInfo
+	s.sessions.Range(func(key, value interface{}) bool {
+		si := value.(*SessionInfo)
+		if si.AgentID == agentID {
+			found = si
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// readyGateway reports whether an agent is connected and takes relayed
+// traffic. Gateways do once they finished setting up.
+func (s *Server) readyGateway(agentID string) bool {
+	si := s.findSessionByAgent(agentID)
+	return si != nil && si.ready.Load()
+}
+
+// agentTypeFromString converts a database agent type to proto format
+func agentTypeFromString(agentType string) proto.AgentType {
+	switch strings.ToLower(agentType) {
+	case "client":
+		return proto.AgentType_CLIENT
+	case "gateway":
+		return proto.AgentType_GATEWAY
+	default:
+		return proto.AgentType_AGENT_TYPE_UNSPECIFIED
+	}
+}
+
+// agentStatusFromString converts a database agent status to proto format
+func agentStatusFromString(agentStatus string) proto.AgentStatus {
+	switch agentStatus {
+	case "online":
+		return proto.AgentStatus_ONLINE
+	case "offline":
+		return proto.AgentStatus_OFFLINE
+	case "error":
+		return proto.AgentStatus_ERROR
+	default:
+		return proto.AgentStatus_AGENT_STATUS_UNSPECIFIED
+	}
+}
+
+// ListRoutingRules returns all routing rules of an agent or of an agent
+// group
+func (s *Server) ListRoutingRules(ctx context.Context, req *proto.ListRoutingRulesRequest) (*proto.ListRoutingRulesResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	var rules []*RoutingRule
+	var err error
+	if req.GroupId != 0 {
+		rules, err = s.db.ListGroupRoutingRules(int(req.GroupId))
+	} else {
+		rules, err = s.db.ListRoutingRules(req.AgentId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list routing rules: %v", err)
+	}
+
+	protoRules := make([]*proto.RoutingRule, 0, len(rules))
+	for _, rule := range rules {
+		protoRules = append(protoRules, routingRuleToProto(rule))
+	}
+
+	return &proto.ListRoutingRulesResponse{Rules: protoRules}, nil
+}
+
+// CreateUser creates a user account with a fresh API key
+func (s *Server) CreateUser(ctx context.Context, req *proto.CreateUserRequest) (*proto.UserResponse, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Username == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "username is required")
+	}
+
+	role := req.Role
+	if role == "" {
+		role = "user"
+	}
+	if err := validateUserFields(role, "active", req.MaxAgents, req.MaxBandwidth); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccountChange(ctx, admin, role); err != nil {
+		return nil, err
+	}
+	sitePrefixes, err := normalizeSitePrefixes(req.SitePrefixes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Accounts without a password can only authenticate by API key
+	password := req.Password
+	if password == "" {
+		if password, err = generateAPIKey(); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate password: %v", err)
+		}
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid password: %v", err)
+	}
+
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate API key: %v", err)
+	}
+
+	user := &User{
+		ID:           uuid.New().String(),
+		Username:     req.Username,
+		Email:        req.Email,
+		PasswordHash: string(passwordHash),
+		APIKey:       apiKey,
+		Role:         role,
+		MaxAgents:    int(req.MaxAgents),
+		MaxBandwidth: int(req.MaxBandwidth),
+		TransferCap:  req.TransferCap,
+		SitePrefixes: sitePrefixes,
+	}
+
+	if err := s.db.CreateUser(user); err != nil {
+		if isDuplicateKey(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "a user with username %q or the same email already exists", req.Username)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
+	}
+
+	log.Printf("User %s (%s) created by %s", user.Username, user.ID, admin.Username)
+
+	return userResponse(user), nil
+}
+
+// RotateAPIKey replaces the API key of a user
+func (s *Server) RotateAPIKey(ctx context.Context, req *proto.RotateAPIKeyRequest) (*proto.UserResponse, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.db.GetUserByID(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	if err := s.authorizeAccountChange(ctx, admin, user.Role); err != nil {
+		return nil, err
+	}
+
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate API key: %v", err)
+	}
+
+	if err := s.db.UpdateUserAPIKey(user.ID, apiKey); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to rotate API key: %v", err)
+	}
+	user.APIKey = apiKey
+
+	log.Printf("API key of user %s rotated by %s", user.Username, admin.Username)
+
+	return userResponse(user), nil
+}
+
+// ListUsers returns all user accounts with their quotas and current usage
+func (s *Server) ListUsers(ctx context.Context, req *proto.ListUsersRequest) (*proto.ListUsersResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	users, err := s.db.ListUsers()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list users: %v", err)
+	}
+
+	// Report usage relayed since the last periodic flush
+	s.quotas.flush()
+
+	resp := &proto.ListUsersResponse{Users: make([]*proto.UserDetail, 0, len(users))}
+	for _, user := range users {
+		detail, err := s.userDetail(user, 1)
+		if err != nil {
+			return nil, err
+		}
+		resp.Users = append(resp.Users, detail)
+	}
+
+	return resp, nil
+}
+
+// GetUser returns a user account with its quotas and monthly usage
+func (s *Server) GetUser(ctx context.Context, req *proto.GetUserRequest) (*proto.UserDetail, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	user, err := s.db.GetUserByID(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+
+	months := int(req.UsageMonths)
+	if months <= 0 {
+		months = defaultUsageMonths
+	}
+
+	s.quotas.flush()
+	return s.userDetail(user, months)
+}
+
+// UpdateUser updates the email, role, status and quotas of a user
+func (s *Server) UpdateUser(ctx context.Context, req *proto.UpdateUserRequest) (*proto.UserDetail, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateUserFields(req.Role, req.Status, req.MaxAgents, req.MaxBandwidth); err != nil {
+		return nil, err
+	}
+	sitePrefixes, err := normalizeSitePrefixes(req.SitePrefixes)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.db.GetUserByID(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	if user.ID == admin.ID && (req.Role != admin.Role || req.Status != "active") {
+		return nil, status.Errorf(codes.FailedPrecondition, "admins cannot change their own role or deactivate themselves")
+	}
+	if err := s.authorizeAccountChange(ctx, admin, user.Role, req.Role); err != nil {
+		return nil, err
+	}
+
+	// A status an admin set is not the identity provider's to undo
+	if req.Status != user.Status {
+		user.SCIMDeactivated = false
+	}
+	user.Email = req.Email
+	user.Role = req.Role
+	user.Status = req.Status
+	user.MaxAgents = int(req.MaxAgents)
+	user.MaxBandwidth = int(req.MaxBandwidth)
+	user.TransferCap = req.TransferCap
+	user.SitePrefixes = sitePrefixes
+
+	if err := s.db.UpdateUser(user); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update user: %v", err)
+	}
+
+	// Apply new quotas to connected agents immediately
+	if _, err := s.quotas.load(user); err != nil {
+		log.Printf("Failed to reload quota of user %s: %v", user.Username, err)
+	}
+
+	log.Printf("User %s (%s) updated by %s", user.Username, user.ID, admin.Username)
+
+	// A user that may no longer connect loses its live sessions
+	if user.Status != "active" {
+		s.disconnectUser(user.ID, "user "+user.Status+" by "+admin.Username)
+	}
+
+	return s.userDetail(user, 1)
+}
+
+// DeleteUser deletes a user together with its agents
+func (s *Server) DeleteUser(ctx context.Context, req *proto.DeleteUserRequest) (*proto.DeleteUserResponse, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.db.GetUserByID(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	if user.ID == admin.ID {
+		return nil, status.Errorf(codes.FailedPrecondition, "admins cannot delete themselves")
+	}
+	if err := s.authorizeAccountChange(ctx, admin, user.Role); err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.removeUser(user, admin.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.DeleteUserResponse{
+		Deleted:       true,
+		AgentsDeleted: int32(deleted),
+	}, nil
+}
+
+// removeUser deletes a user together with its agents, ending their
+// sessions first, and returns the number of agents deleted
+func (s *Server) removeUser(user *User, by string) (int, error) {
+	agents, err := s.db.ListAgents(AgentFilter{UserID: user.ID, Limit: math.MaxInt32})
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to list agents: %v", err)
+	}
+
+	// End the relay streams before the agents' overlay IPs can be reused
+	for _, agent := range agents {
+		if si := s.findSessionByAgent(agent.ID); si != nil {
+			s.terminateSession(si, proto.DisconnectReason_DISCONNECT_AUTH_REVOKED, "user deleted by "+by)
+		}
+	}
+
+	// Agents, sessions and usage records are removed by cascade
+	if err := s.db.DeleteUser(user.ID); err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to delete user: %v", err)
+	}
+
+	for _, agent := range agents {
+		s.agents.Delete(agent.ID)
+		s.ipPool.Release(agent.ID)
+		s.alerts.agentRemoved(agent.ID)
+	}
+	s.quotas.forget(user.ID)
+	s.acls.delete(user.ID)
+
+	log.Printf("User %s (%s) and %d agent(s) deleted by %s", user.Username, user.ID, len(agents), by)
+	return len(agents), nil
+}
+
+// userDetail converts a user record to proto format with its agent count
+// and the given number of months of usage
+func (s *Server) userDetail(user *User, months int) (*proto.UserDetail, error) {
+	count, err := s.db.CountAgentsByUser(user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count agents: %v", err)
+	}
+	usage, err := s.db.ListUserUsage(user.ID, months)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get usage: %v", err)
+	}
+
+	detail := &proto.UserDetail{
+		UserId:       user.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+		Role:         user.Role,
+		Status:       user.Status,
+		MaxAgents:    int32(user.MaxAgents),
+		MaxBandwidth: int32(user.MaxBandwidth),
+		TransferCap:  user.TransferCap,
+		AgentCount:   int32(count),
+		Usage:        make([]*proto.UserUsage, 0, len(usage)),
+		CreatedAt:    timestamppb.New(user.CreatedAt),
+		SitePrefixes: user.SitePrefixes,
+	}
+	for _, u := range usage {
+		detail.Usage = append(detail.Usage, &proto.UserUsage{
+			Month:         u.Month,
+			BytesSent:     u.BytesSent,
+			BytesReceived: u.BytesReceived,
+		})
+	}
+
+	return detail, nil
+}
+
+// validateUserFields checks the role, status and quotas of a user
+func validateUserFields(role, userStatus string, maxAgents, maxBandwidth int32) error {
+	if _, staff := staffRoles[role]; !staff && role != "user" {
+		return status.Errorf(codes.InvalidArgument, "role must be 'user', 'owner', 'admin', 'support' or 'auditor'")
+	}
+	if userStatus != "active" && userStatus != "suspended" && userStatus != "disabled" {
+		return status.Errorf(codes.InvalidArgument, "status must be 'active', 'suspended' or 'disabled'")
+	}
+	if maxAgents < 0 || maxBandwidth < 0 {
+		return status.Errorf(codes.InvalidArgument, "quotas must not be negative")
+	}
+	return nil
+}
+
+// normalizeSitePrefixes checks the prefixes a user's gateways may
+// advertise and returns them masked
+func normalizeSitePrefixes(prefixes []string) ([]string, error) {
+	var normalized []string
+	for _, p := range prefixes {
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil || !prefix.Addr().Is4() {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid site prefix %q: must be an IPv4 CIDR", p)
+		}
+		normalized = append(normalized, prefix.Masked().String())
+	}
+	return normalized, nil
+}
+
+// userResponse converts a user record to proto format
+func userResponse(user *User) *proto.UserResponse {
+	return &proto.UserResponse{
+		UserId:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+		ApiKey:   user.APIKey,
+	}
+}
+
+// generateAPIKey returns a random 64-character hex key
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// admissionControl counts sessions against the server's session limit.
+// Registrations in progress are counted too, so concurrent ones cannot
+// overshoot the limit.
+type admissionControl struct {
+	sessions atomic.Int64 // live sessions
+	pending  atomic.Int64 // registrations past the limit check
+	rejected atomic.Uint64
+}
+
+// admitSession reserves room for the session of a registering agent,
+// returning a busy error when the server is full. An agent that still holds
+// a live session is reconnecting and always admitted. The returned function
+// releases the reservation once the session is stored or the registration
+// failed.
+func (s *Server) admitSession(ctx context.Context, agentID string) (func(), error) {
+	ac := &s.admission
+	max := int64(s.config.Network.MaxSessions)
+	if max <= 0 {
+		return func() {}, nil
+	}
+
+	release := func() { ac.pending.Add(-1) }
+	if ac.pending.Add(1)+ac.sessions.Load() <= max || s.findSessionByAgent(agentID) != nil {
+		return release, nil
+	}
+	release()
+
+	// Log the first rejection and then periodically, a full server would
+	// flood the log
+	if n := ac.rejected.Add(1); n == 1 || n%100 == 0 {
+		log.Printf("Session limit of %d reached, %d registrations turned away so far", max, n)
+	}
+	return nil, s.serverBusy(ctx)
+}
+
+// serverBusy builds the error telling an agent to retry later or try an
+// alternate server
+func (s *Server) serverBusy(ctx context.Context) error {
+	retryAfter := s.config.Network.BusyRetryAfter
+	grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, fmt.Sprintf("%d", retryAfter)))
+
+	msg := fmt.Sprintf("server busy, retry after %s", time.Duration(retryAfter)*time.Second)
+	st, err := status.New(codes.ResourceExhausted, msg).WithDetails(&proto.ServerBusy{
+		RetryAfter:       int32(retryAfter),
+		AlternateServers: s.config.Network.AlternateServers,
+	})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, msg)
+	}
+	return st.Err()
+}
+
+// sessionStored counts a session stored by a registration
+func (s *Server) sessionStored() {
+	s.admission.sessions.Add(1)
+}
+
+// removeSession removes a session from the live sessions, reporting
+// whether it was still live
+func (s *Server) removeSession(sessionID string) bool {
+	value, live := s.sessions.LoadAndDelete(sessionID)
+	if !live {
+		return false
+	}
+	s.admission.sessions.Add(-1)
+	s.leaveSiteMesh(value.(*SessionInfo))
+	return true
+}
@@ -0,0 +1,340 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+const (
+	alertTimeout = 10 * time.Second
+
+	// alertMinRelaySample is the number of relayed packets per interval
+	// below which the relay error rate is not evaluated
+	alertMinRelaySample = 100
+)
+
+// alert is a firing or resolved alert
+type alert struct {
+	Key      string // identifies the condition, e.g. "agent_offline:<id>"
+	Summary  string
+	Resolved bool
+}
+
+// alerter evaluates the built-in alert rules and notifies by email and
+// Slack-compatible webhooks when a rule starts or stops firing
+type alerter struct {
+	cfg    config.AlertsConfig
+	client *http.Client
+
+	relayed     atomic.Uint64 // packets relayed since the last evaluation
+	relayErrors atomic.Uint64 // of which failed
+
+	mu           sync.Mutex
+	offlineSince map[string]time.Time // agentID -> when its session ended
+	raised       map[string]string    // alert key -> summary of conditions the server reports as they happen
+	firing       map[string]bool      // alert key -> firing
+}
+
+// newAlerter creates an alerter, or returns nil if no rule or no
+// notification channel is configured
+func newAlerter(cfg config.AlertsConfig) *alerter {
+	rules := cfg.AgentOfflineMinutes > 0 || cfg.RelayErrorRate > 0 || cfg.DBLatencyMs > 0 || cfg.RouteLoops || cfg.ConfigDrift || cfg.ClockSkew
+	channels := cfg.SlackWebhook != "" || cfg.SMTP.Host != ""
+	if !rules || !channels {
+		return nil
+	}
+
+	return &alerter{
+		cfg:          cfg,
+		client:       &http.Client{Timeout: alertTimeout},
+		offlineSince: make(map[string]time.Time),
+		raised:       make(map[string]string),
+		firing:       make(map[string]bool),
+	}
+}
+
+// relayResult counts a relayed packet for the relay error rate rule
+func (a *alerter) relayResult(err error) {
+	if a == nil {
+		return
+	}
+	a.relayed.Add(1)
+	if err != nil {
+		a.relayErrors.Add(1)
+	}
+}
+
+// agentOnline ends the offline period of an agent
+func (a *alerter) agentOnline(agentID string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	delete(a.offlineSince, agentID)
+	a.mu.Unlock()
+}
+
+// agentOffline starts the offline period of an agent
+func (a *alerter) agentOffline(agentID string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	a.offlineSince[agentID] = time.Now()
+	a.mu.Unlock()
+}
+
+// agentRemoved forgets an archived or deleted agent, resolving its
+// offline alert on the next evaluation
+func (a *alerter) agentRemoved(agentID string) {
+	a.agentOnline(agentID)
+}
+
+// raise fires an alert on the next evaluation until it is cleared
+func (a *alerter) raise(key, summary string) {
+	a.mu.Lock()
+	a.raised[key] = summary
+	a.mu.Unlock()
+}
+
+// clear resolves a raised alert on the next evaluation
+func (a *alerter) clear(key string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	delete(a.raised, key)
+	a.mu.Unlock()
+}
+
+// ruleQuarantined fires the route loop alert of a quarantined rule
+func (a *alerter) ruleQuarantined(ruleID int, summary string) {
+	if a != nil && a.cfg.RouteLoops {
+		a.raise("route_loop:"+strconv.Itoa(ruleID), summary)
+	}
+}
+
+// ruleChanged resolves the route loop alert of a rule an operator updated
+// or deleted
+func (a *alerter) ruleChanged(ruleID int) {
+	a.clear("route_loop:" + strconv.Itoa(ruleID))
+}
+
+// configDrifted fires the config drift alert of an agent
+func (a *alerter) configDrifted(agentID, summary string) {
+	if a != nil && a.cfg.ConfigDrift {
+		a.raise("config_drift:"+agentID, summary)
+	}
+}
+
+// configApplied resolves the config drift alert of an agent that applied
+// its config or disconnected
+func (a *alerter) configApplied(agentID string) {
+	a.clear("config_drift:" + agentID)
+}
+
+// clockSkewed fires the clock skew alert of an agent
+func (a *alerter) clockSkewed(agentID, summary string) {
+	if a != nil && a.cfg.ClockSkew {
+		a.raise("clock_skew:"+agentID, summary)
+	}
+}
+
+// clockSynced resolves the clock skew alert of an agent whose clock is
+// back within the limit or that disconnected
+func (a *alerter) clockSynced(agentID string) {
+	a.clear("clock_skew:" + agentID)
+}
+
+// alertLoop evaluates the alert rules every alerts.interval seconds
+func (s *Server) alertLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(time.Duration(s.config.Alerts.Interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.evaluateAlerts()
+		}
+	}
+}
+
+// evaluateAlerts checks every enabled rule and notifies on changes
+func (s *Server) evaluateAlerts() {
+	a := s.alerts
+	active := make(map[string]string) // key -> summary
+
+	if minutes := a.cfg.AgentOfflineMinutes; minutes > 0 {
+		limit := time.Duration(minutes) * time.Minute
+		a.mu.Lock()
+		for agentID, since := range a.offlineSince {
+			if offline := time.Since(since); offline > limit {
+				active["agent_offline:"+agentID] = fmt.Sprintf(
+					"Agent %s has been offline for %d minutes", agentID, int(offline.Minutes()))
+			}
+		}
+		a.mu.Unlock()
+	}
+
+	a.mu.Lock()
+	for key, summary := range a.raised {
+		active[key] = summary
+	}
+	a.mu.Unlock()
+
+	relayed, failed := a.relayed.Swap(0), a.relayErrors.Swap(0)
+	if a.cfg.RelayErrorRate > 0 && relayed >= alertMinRelaySample {
+		if rate := float64(failed) / float64(relayed); rate >= a.cfg.RelayErrorRate {
+			active["relay_error_rate"] = fmt.Sprintf(
+				"Relay error rate is %.1f%% (%d of %d packets), threshold %.1f%%",
+				rate*100, failed, relayed, a.cfg.RelayErrorRate*100)
+		}
+	}
+
+	if a.cfg.DBLatencyMs > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		start := time.Now()
+		err := s.db.Ping(ctx)
+		latency := time.Since(start)
+		cancel()
+
+		switch {
+		case err != nil:
+			active["db_latency"] = fmt.Sprintf("Database ping failed: %v", err)
+		case latency > time.Duration(a.cfg.DBLatencyMs)*time.Millisecond:
+			active["db_latency"] = fmt.Sprintf(
+				"Database ping took %d ms, threshold %d ms", latency.Milliseconds(), a.cfg.DBLatencyMs)
+		}
+	}
+
+	// Notify when rules start or stop firing
+	var changes []alert
+	a.mu.Lock()
+	for key, summary := range active {
+		if !a.firing[key] {
+			a.firing[key] = true
+			changes = append(changes, alert{Key: key, Summary: summary})
+		}
+	}
+	for key := range a.firing {
+		if _, ok := active[key]; !ok {
+			delete(a.firing, key)
+			changes = append(changes, alert{Key: key, Summary: "Resolved: " + key, Resolved: true})
+		}
+	}
+	a.mu.Unlock()
+
+	for _, al := range changes {
+		a.notify(al)
+	}
+}
+
+// notify sends an alert to every configured channel
+func (a *alerter) notify(al alert) {
+	state := "FIRING"
+	if al.Resolved {
+		state = "RESOLVED"
+	}
+	log.Printf("Alert %s: %s", state, al.Summary)
+
+	text := fmt.Sprintf("[EasyAnyLink %s] %s", state, al.Summary)
+	if a.cfg.SlackWebhook != "" {
+		if err := a.postSlack(text); err != nil {
+			log.Printf("Failed to send alert to Slack webhook: %v", err)
+		}
+	}
+	if a.cfg.SMTP.Host != "" {
+		if err := a.sendMail(text, al); err != nil {
+			log.Printf("Failed to send alert email: %v", err)
+		}
+	}
+}
+
+// postSlack posts a message to a Slack-compatible incoming webhook
+func (a *alerter) postSlack(text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Post(a.cfg.SlackWebhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// sendMail sends an alert email, upgrading to TLS when the server
+// supports STARTTLS
+func (a *alerter) sendMail(subject string, al alert) error {
+	cfg := a.cfg.SMTP
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	conn, err := net.DialTimeout("tcp", addr, alertTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(alertTimeout))
+
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := c.Mail(cfg.From); err != nil {
+		return err
+	}
+	for _, to := range cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\n\r\n%s\r\n\r\nAlert: %s\r\n",
+		cfg.From, strings.Join(cfg.To, ", "), subject, time.Now().Format(time.RFC1123Z), al.Summary, al.Key)
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"log"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ApproveAgent approves an agent awaiting approval and assigns its overlay
+// IP. The agent joins the network on its next registration attempt.
+func (s *Server) ApproveAgent(ctx context.Context, req *proto.ApproveAgentRequest) (*proto.AgentDetail, error) {
+	admin, err := s.authorizeAdmin(ctx, permOperate)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.db.GetAgentByID(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s not found", req.AgentId)
+	}
+	if !agent.Pending {
+		return nil, status.Errorf(codes.FailedPrecondition, "agent %s is not pending approval", req.AgentId)
+	}
+
+	ip, err := s.ipPool.Allocate(agent.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.ResourceExhausted, "failed to allocate IP: %v", err)
+	}
+
+	if err := s.db.ApproveAgent(agent.ID, ip.String()); err != nil {
+		s.ipPool.Release(agent.ID)
+		return nil, status.Errorf(codes.Internal, "failed to approve agent: %v", err)
+	}
+
+	log.Printf("Agent %s approved by %s, IP: %s", agent.ID, admin.Username, ip)
+
+	agent, err = s.db.GetAgentByID(agent.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get agent: %v", err)
+	}
+	return s.agentDetail(agent), nil
+}
+
+// RejectAgent deletes an agent awaiting approval. The device may register
+// again and will be pending once more.
+func (s *Server) RejectAgent(ctx context.Context, req *proto.RejectAgentRequest) (*proto.RejectAgentResponse, error) {
+	admin, err := s.authorizeAdmin(ctx, permOperate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.DeletePendingAgent(req.AgentId); err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s is not pending approval", req.AgentId)
+	}
+
+	log.Printf("Agent %s rejected by %s", req.AgentId, admin.Username)
+	return &proto.RejectAgentResponse{Rejected: true}, nil
+}
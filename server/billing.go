@@ -0,0 +1,146 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// usageThreshold is the data of a usage.threshold event
+type usageThreshold struct {
+	UserID           string `json:"user_id"`
+	Username         string `json:"username"`
+	Month            string `json:"month"`
+	BytesUsed        uint64 `json:"bytes_used"`
+	TransferCap      uint64 `json:"transfer_cap,omitempty"`
+	ThresholdBytes   uint64 `json:"threshold_bytes"`
+	ThresholdPercent int    `json:"threshold_percent,omitempty"`
+}
+
+// usageNotifier publishes usage.threshold events when users cross the
+// configured billing thresholds
+type usageNotifier struct {
+	cfg      config.BillingConfig
+	webhooks *webhookDispatcher
+}
+
+// newUsageNotifier creates a notifier, or returns nil if no thresholds
+// or webhooks are configured
+func newUsageNotifier(cfg config.BillingConfig, webhooks *webhookDispatcher) *usageNotifier {
+	if webhooks == nil || (len(cfg.ThresholdPercents) == 0 && len(cfg.ThresholdBytes) == 0) {
+		return nil
+	}
+	return &usageNotifier{cfg: cfg, webhooks: webhooks}
+}
+
+// billingWebhook returns the webhook subscription configured in the
+// billing section, if any
+func billingWebhook(cfg config.BillingConfig) []config.Webhook {
+	if cfg.WebhookURL == "" {
+		return nil
+	}
+	return []config.Webhook{{
+		URL:    cfg.WebhookURL,
+		Secret: cfg.WebhookSecret,
+		Events: []string{EventUsageThreshold},
+	}}
+}
+
+// crossed publishes an event for every threshold passed when the monthly
+// usage of a user grew from prev to used bytes
+func (n *usageNotifier) crossed(userID, username, month string, prev, used, transferCap uint64) {
+	for _, percent := range n.cfg.ThresholdPercents {
+		if transferCap == 0 {
+			break
+		}
+		threshold := transferCap / 100 * uint64(percent)
+		if prev < threshold && used >= threshold {
+			n.webhooks.publish(EventUsageThreshold, &usageThreshold{
+				UserID: userID, Username: username, Month: month,
+				BytesUsed: used, TransferCap: transferCap,
+				ThresholdBytes: threshold, ThresholdPercent: percent,
+			})
+		}
+	}
+	for _, threshold := range n.cfg.ThresholdBytes {
+		if prev < threshold && used >= threshold {
+			n.webhooks.publish(EventUsageThreshold, &usageThreshold{
+				UserID: userID, Username: username, Month: month,
+				BytesUsed: used, TransferCap: transferCap,
+				ThresholdBytes: threshold,
+			})
+		}
+	}
+}
+
+// ExportUsage renders the relayed traffic of every user in a month as CSV
+// or JSON for billing
+func (s *Server) ExportUsage(ctx context.Context, req *proto.ExportUsageRequest) (*proto.ExportUsageResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	month := req.Month
+	if month == "" {
+		month = currentMonth()
+	}
+	if _, err := time.Parse("2006-01", month); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "month must be YYYY-MM")
+	}
+
+	// Include usage relayed since the last periodic flush
+	s.quotas.flush()
+
+	records, err := s.db.ListUsageByMonth(month)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to export usage: %v", err)
+	}
+
+	resp := &proto.ExportUsageResponse{Month: month}
+	switch req.Format {
+	case "", "csv":
+		resp.ContentType = "text/csv"
+		resp.Data, err = usageCSV(records)
+	case "json":
+		resp.ContentType = "application/json"
+		if records == nil {
+			records = []*UsageRecord{}
+		}
+		resp.Data, err = json.MarshalIndent(records, "", "  ")
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "format must be 'csv' or 'json'")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to render usage: %v", err)
+	}
+
+	return resp, nil
+}
+
+// usageCSV renders usage records as CSV with a header line
+func usageCSV(records []*UsageRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write([]string{"month", "user_id", "username", "email", "bytes_sent", "bytes_received", "bytes_total", "transfer_cap"})
+	for _, r := range records {
+		w.Write([]string{
+			r.Month, r.UserID, r.Username, r.Email,
+			strconv.FormatUint(r.BytesSent, 10),
+			strconv.FormatUint(r.BytesReceived, 10),
+			strconv.FormatUint(r.BytesTotal, 10),
+			strconv.FormatUint(r.TransferCap, 10),
+		})
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
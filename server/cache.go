@@ -0,0 +1,230 @@
+package server
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the memory used by a lookup cache
+const maxCacheEntries = 10000
+
+// ttlCache is a lookup cache whose entries expire after a fixed TTL.
+// A nil cache caches nothing.
+type ttlCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// newTTLCache creates a cache, or returns nil if ttl is not positive
+func newTTLCache(ttl time.Duration) *ttlCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &ttlCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// get returns the unexpired value cached under key
+func (c *ttlCache) get(key string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// set caches value under key
+func (c *ttlCache) set(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store(key, value)
+}
+
+// store caches value under key with c.mu held
+func (c *ttlCache) store(key string, value interface{}) {
+	now := time.Now()
+	if len(c.entries) >= maxCacheEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCacheEntries {
+			c.entries = make(map[string]cacheEntry)
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+}
+
+// add caches value under key unless an unexpired entry exists, reporting
+// whether it was added
+func (c *ttlCache) add(key string, value interface{}) bool {
+	if c == nil {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok && !time.Now().After(entry.expires) {
+		return false
+	}
+	c.store(key, value)
+	return true
+}
+
+// take removes the entry cached under key, reporting whether it was
+// unexpired
+func (c *ttlCache) take(key string) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	delete(c.entries, key)
+	return ok && !time.Now().After(entry.expires)
+}
+
+// delete removes the entry cached under key
+func (c *ttlCache) delete(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// deleteIf removes all entries whose value matches
+func (c *ttlCache) deleteIf(match func(value interface{}) bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	for k, entry := range c.entries {
+		if match(entry.value) {
+			delete(c.entries, k)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// clear removes all entries
+func (c *ttlCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.entries = make(map[string]cacheEntry)
+	c.mu.Unlock()
+}
+
+// maxSetEntries bounds the memory used by an expiry set
+const maxSetEntries = 10000
+
+// expirySet is a set of keys that expire after a fixed TTL, for keys that
+// must not be forgotten early, such as nonces already used. Unlike a
+// ttlCache it never evicts unexpired keys: once full it refuses new ones.
+// A nil set remembers nothing.
+type expirySet struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	limit int
+	keys  map[string]time.Time
+	byAge []setEntry // keys in the order added, so also the order they expire in
+}
+
+type setEntry struct {
+	key     string
+	expires time.Time
+}
+
+// newExpirySet creates a set holding up to maxSetEntries keys, or returns
+// nil if ttl is not positive
+func newExpirySet(ttl time.Duration) *expirySet {
+	if ttl <= 0 {
+		return nil
+	}
+	return &expirySet{ttl: ttl, limit: maxSetEntries, keys: make(map[string]time.Time)}
+}
+
+// add adds key unless it is in the set. It returns false if key was
+// already there, or if the set is full with the time until a key expires.
+func (s *expirySet) add(key string) (bool, time.Duration) {
+	if s == nil {
+		return true, 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.expire(now)
+	if _, ok := s.keys[key]; ok {
+		return false, 0
+	}
+	// Keys taken early hold their slot until they expire, bounding byAge
+	if len(s.byAge) >= s.limit {
+		return false, s.byAge[0].expires.Sub(now)
+	}
+
+	expires := now.Add(s.ttl)
+	s.keys[key] = expires
+	s.byAge = append(s.byAge, setEntry{key: key, expires: expires})
+	return true, 0
+}
+
+// take removes key, reporting whether it was in the set
+func (s *expirySet) take(key string) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(time.Now())
+	_, ok := s.keys[key]
+	delete(s.keys, key)
+	return ok
+}
+
+// expire removes the keys expired at now with s.mu held
+func (s *expirySet) expire(now time.Time) {
+	n := 0
+	for n < len(s.byAge) && !now.Before(s.byAge[n].expires) {
+		// The key may have been taken and added again since
+		if e := s.byAge[n]; s.keys[e.key].Equal(e.expires) {
+			delete(s.keys, e.key)
+		}
+		n++
+	}
+	if n > 0 {
+		s.byAge = slices.Delete(s.byAge, 0, n)
+	}
+}
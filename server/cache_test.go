@@ -0,0 +1,52 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpirySet(t *testing.T) {
+	s := newExpirySet(50 * time.Millisecond)
+	s.limit = 2
+
+	steps := []struct {
+		name      string
+		add, take string // one of them
+		want      bool
+		full      bool // add refused with a retry time
+	}{
+		{name: "first", add: "a", want: true},
+		{name: "duplicate", add: "a", want: false},
+		{name: "second", add: "b", want: true},
+		{name: "full", add: "c", want: false, full: true},
+		{name: "take", take: "a", want: true},
+		{name: "taken twice", take: "a", want: false},
+		{name: "taken keeps its slot", add: "c", want: false, full: true},
+		{name: "unknown", take: "c", want: false},
+	}
+	for _, step := range steps {
+		if step.take != "" {
+			if got := s.take(step.take); got != step.want {
+				t.Errorf("%s: take got %v, want %v", step.name, got, step.want)
+			}
+			continue
+		}
+		got, retryAfter := s.add(step.add)
+		if got != step.want || (retryAfter > 0) != step.full {
+			t.Errorf("%s: add got %v, retry after %v, want %v, full %v", step.name, got, retryAfter, step.want, step.full)
+		}
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if added, _ := s.add("a"); !added {
+		t.Error("expired key not added again")
+	}
+	if s.take("b") {
+		t.Error("expired key still in the set")
+	}
+
+	var unset *expirySet
+	if added, _ := unset.add("a"); !added || unset.take("a") {
+		t.Error("nil set remembered a key")
+	}
+}
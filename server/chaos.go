@@ -0,0 +1,62 @@
+//go:build chaos
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+)
+
+// chaosBuild lets the admin API inject faults, see faults. Build with
+// go build -tags chaos for staging only.
+const chaosBuild = true
+
+// withDBFaults reopens db through a connector whose connections fail the
+// calls faults picks
+func withDBFaults(db *sql.DB, dsn string) *sql.DB {
+	wrapped := sql.OpenDB(faultConnector{driver: db.Driver(), dsn: dsn})
+	db.Close()
+	return wrapped
+}
+
+// faultConnector opens connections of the wrapped driver that fail the
+// calls faults picks
+type faultConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c faultConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	if err := faults.failDB(); err != nil {
+		return nil, err
+	}
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return faultConn{conn}, nil
+}
+
+func (c faultConnector) Driver() driver.Driver { return c.driver }
+
+// faultConn fails statements and transactions faults picks. It hides the
+// optional interfaces of the wrapped connection, so every call goes
+// through Prepare or Begin.
+type faultConn struct {
+	driver.Conn
+}
+
+func (c faultConn) Prepare(query string) (driver.Stmt, error) {
+	if err := faults.failDB(); err != nil {
+		return nil, err
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c faultConn) Begin() (driver.Tx, error) {
+	if err := faults.failDB(); err != nil {
+		return nil, err
+	}
+	return c.Conn.Begin()
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// clockSkewEvent is the data of agent.clock_skew events
+type clockSkewEvent struct {
+	AgentID   string `json:"agent_id"`
+	SessionID string `json:"session_id"`
+	SkewMs    int64  `json:"skew_ms"`  // agent clock minus server clock
+	LimitMs   int64  `json:"limit_ms"` // security.clock_skew_warning
+}
+
+// clockSkewState is the clock skew an agent measured, guarded by the
+// session lock
+type clockSkewState struct {
+	skew   time.Duration // agent clock minus server clock
+	skewed bool          // off by more than clock_skew_warning
+}
+
+// checkClockSkew records the clock skew an agent reported with a
+// heartbeat. Operators are warned once when it exceeds
+// security.clock_skew_warning and told again when it is back within.
+func (s *Server) checkClockSkew(si *SessionInfo, skewMs int64) {
+	limit := time.Duration(s.config.Security.ClockSkewWarning) * time.Second
+	skew := time.Duration(skewMs) * time.Millisecond
+
+	si.mu.Lock()
+	c := &si.clock
+	c.skew = skew
+	wasSkewed := c.skewed
+	c.skewed = skew > limit || skew < -limit
+	skewed := c.skewed
+	si.mu.Unlock()
+
+	switch {
+	case skewed && !wasSkewed:
+		summary := fmt.Sprintf("Agent %s clock is %s off the server clock, more than the %s allowed",
+			si.AgentID, skew.Round(time.Second), limit)
+		log.Print(summary)
+		s.alerts.clockSkewed(si.AgentID, summary)
+		s.webhooks.publish(EventClockSkew, &clockSkewEvent{
+			AgentID:   si.AgentID,
+			SessionID: si.SessionID,
+			SkewMs:    skewMs,
+			LimitMs:   limit.Milliseconds(),
+		})
+	case wasSkewed && !skewed:
+		log.Printf("Agent %s clock is back within %s of the server clock", si.AgentID, limit)
+		s.alerts.clockSynced(si.AgentID)
+	}
+}
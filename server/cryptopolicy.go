@@ -0,0 +1,23 @@
+package server
+
+import (
+	"context"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// GetCryptoPolicy reports the crypto policy enforced by the server's TLS
+func (s *Server) GetCryptoPolicy(ctx context.Context, req *proto.GetCryptoPolicyRequest) (*proto.CryptoPolicyResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	return &proto.CryptoPolicyResponse{
+		Policy:       crypto.ActivePolicy(),
+		FipsModule:   crypto.FIPSModuleEnabled(),
+		FipsBuild:    crypto.FIPSBuild(),
+		CipherSuites: crypto.PolicyCipherSuites(),
+		Curves:       crypto.PolicyCurves(),
+	}, nil
+}
@@ -1,26 +1,70 @@
 package server
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/taills/EasyAnyLink/common/config"
 )
 
-// Database represents the database connection
+// Database represents the database connection. Writes go to the primary,
+// read-heavy queries may be served by healthy read replicas.
 type Database struct {
-	db *sql.DB
+	db       *sql.DB
+	replicas *replicaSet // nil without configured replicas
+	users    *ttlCache   // "id:<id>" and "key:<api key>" -> User
+	agents   *ttlCache   // agent ID -> Agent
+	rules    *ttlCache   // agent ID -> []RoutingRule, enabled rules only
+	groups   *ttlCache   // group ID -> AgentGroup
 }
 
 // NewDatabase creates a new database connection
 func NewDatabase(cfg config.DatabaseConfig) (*Database, error) {
+	db, err := openDB(cfg, cfg.Host, cfg.Port, cfg.User, cfg.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	ttl := time.Duration(cfg.CacheTTL) * time.Second
+	d := &Database{
+		db:     db,
+		users:  newTTLCache(ttl),
+		agents: newTTLCache(ttl),
+		rules:  newTTLCache(ttl),
+		groups: newTTLCache(ttl),
+	}
+	if len(cfg.Replicas) > 0 {
+		d.replicas, err = newReplicaSet(cfg)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// openDB opens a connection pool to one database server
+func openDB(cfg config.DatabaseConfig, host string, port int, user, password string) (*sql.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=true&loc=Local",
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
+		user,
+		password,
+		host,
+		port,
 		cfg.Database,
 		cfg.Charset,
 	)
@@ -29,35 +73,66 @@ func NewDatabase(cfg config.DatabaseConfig) (*Database, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	db = withDBFaults(db, dsn)
 
 	// Set connection pool parameters
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+	return db, nil
+}
+
+// queryRead runs a read-heavy query that tolerates replication lag on a
+// healthy replica, falling back to the primary if there is none or the
+// replica fails
+func (d *Database) queryRead(query string, args ...interface{}) (*sql.Rows, error) {
+	if r := d.replicas.pick(); r != nil {
+		rows, err := r.db.Query(query, args...)
+		if err == nil {
+			return rows, nil
+		}
+		d.replicas.failed(r, err)
 	}
+	return d.db.Query(query, args...)
+}
 
-	return &Database{db: db}, nil
+// Ping checks that the database is reachable
+func (d *Database) Ping(ctx context.Context) error {
+	return d.db.PingContext(ctx)
 }
 
 // Close closes the database connection
 func (d *Database) Close() error {
+	d.replicas.close()
 	return d.db.Close()
 }
 
 // User represents a user record
 type User struct {
-	ID           string    `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	APIKey       string    `json:"api_key"`
-	Status       string    `json:"status"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              string    `json:"id"`
+	Username        string    `json:"username"`
+	Email           string    `json:"email"`
+	PasswordHash    string    `json:"-"`
+	APIKey          string    `json:"api_key"`
+	Role            string    `json:"role"` // "user", or a staff role of staffRoles
+	Status          string    `json:"status"`
+	MaxAgents       int       `json:"max_agents"`       // 0 for the server default
+	MaxBandwidth    int       `json:"max_bandwidth"`    // KB/s across all agents, 0 for unlimited
+	TransferCap     uint64    `json:"transfer_cap"`     // bytes per calendar month, 0 for unlimited
+	SitePrefixes    []string  `json:"site_prefixes"`    // CIDRs the user's gateways may advertise as sites, empty for any
+	ExternalID      string    `json:"external_id"`      // ID in the identity provider provisioning the user over SCIM
+	SCIMDeactivated bool      `json:"scim_deactivated"` // disabled by the identity provider, which may reactivate the user
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// UserUsage represents relayed traffic of a user in one calendar month
+type UserUsage struct {
+	UserID        string `json:"user_id"`
+	Month         string `json:"month"` // YYYY-MM in UTC
+	BytesSent     uint64 `json:"bytes_sent"`
+	BytesReceived uint64 `json:"bytes_received"`
 }
 
 // Agent represents an agent record
@@ -75,6 +150,11 @@ type Agent struct {
 	Metadata               string    `json:"metadata"` // JSON string
 	CreatedAt              time.Time `json:"created_at"`
 	UpdatedAt              time.Time `json:"updated_at"`
+	DeletedAt              time.Time `json:"deleted_at"`        // zero unless archived
+	Pending                bool      `json:"pending"`           // awaiting admin approval
+	GroupID                int       `json:"group_id"`          // 0 for none
+	IdentityKey            string    `json:"identity_key"`      // base64 PKIX public key of the hardware identity, empty for none
+	IdentityProvider       string    `json:"identity_provider"` // "tpm2" or "secure-enclave"
 }
 
 // Session represents an active session
@@ -88,10 +168,45 @@ type Session struct {
 	BytesReceived uint64    `json:"bytes_received"`
 }
 
+// SessionRecord represents an ended session kept in the history
+type SessionRecord struct {
+	ID             string    `json:"id"`
+	AgentID        string    `json:"agent_id"`
+	ConnectionID   string    `json:"connection_id"`
+	ConnectedAt    time.Time `json:"connected_at"`
+	DisconnectedAt time.Time `json:"disconnected_at"`
+	BytesSent      uint64    `json:"bytes_sent"`
+	BytesReceived  uint64    `json:"bytes_received"`
+	ReasonCode     string    `json:"disconnect_code"` // e.g. agent_shutdown, empty for old sessions
+	Reason         string    `json:"disconnect_reason"`
+}
+
+// TrafficRollup sums the sessions of an agent that ended in one hour or
+// day of the stats rollups
+type TrafficRollup struct {
+	PeriodStart      time.Time `json:"period_start"`
+	AgentID          string    `json:"agent_id"`
+	UserID           string    `json:"user_id"`
+	Sessions         uint32    `json:"sessions"`
+	ConnectedSeconds uint64    `json:"connected_seconds"`
+	BytesSent        uint64    `json:"bytes_sent"`
+	BytesReceived    uint64    `json:"bytes_received"`
+}
+
+// RollupFilter selects traffic rollups
+type RollupFilter struct {
+	Daily   bool      // daily instead of hourly rollups
+	AgentID string    // empty for all agents
+	UserID  string    // empty for all users
+	Since   time.Time // zero for all kept periods
+	Limit   int
+}
+
 // RoutingRule represents a routing rule
 type RoutingRule struct {
 	ID          int       `json:"id"`
-	AgentID     string    `json:"agent_id"`
+	AgentID     string    `json:"agent_id"` // empty for a group rule
+	GroupID     int       `json:"group_id"` // 0 for an agent rule
 	Action      string    `json:"action"`
 	Destination string    `json:"destination"`
 	GatewayID   string    `json:"gateway_id"`
@@ -99,68 +214,473 @@ type RoutingRule struct {
 	Enabled     bool      `json:"enabled"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	Window  AccessWindow `json:"window"`            // when the rule applies
+	Posture string       `json:"posture,omitempty"` // posture policy the agent must meet, empty for none
+}
+
+// AgentGroup represents a config template shared by the agents of a group
+type AgentGroup struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	DNSServers     []string  `json:"dns_servers"`     // empty keeps the agent setting
+	DNSSearch      []string  `json:"dns_search"`      // used with DNSServers
+	BandwidthLimit int       `json:"bandwidth_limit"` // KB/s per agent, 0 for unlimited
+	AgentCount     int       `json:"agent_count"`     // active agents in the group
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ACLRule represents a relay access control rule of a user
+type ACLRule struct {
+	ID          int       `json:"id"`
+	UserID      string    `json:"user_id"`
+	Source      string    `json:"source"`      // CIDR, empty for any
+	Destination string    `json:"destination"` // CIDR, empty for any
+	Protocol    string    `json:"protocol"`    // "any", "tcp", "udp" or "icmp"
+	PortFrom    int       `json:"port_from"`   // 0 for any port
+	PortTo      int       `json:"port_to"`     // last port of the range
+	Action      string    `json:"action"`      // "allow" or "deny"
+	Priority    int       `json:"priority"`
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Window    AccessWindow `json:"window"`               // when the rule applies
+	SourceUID *uint32      `json:"source_uid,omitempty"` // local user on the agent host, nil for any
+	Posture   string       `json:"posture,omitempty"`    // posture policy the sending agent must meet, empty for none
+}
+
+// Rollout is a routing or ACL rule change staged to a canary of agents
+// before it is promoted to all of them or rolled back
+type Rollout struct {
+	ID        int          `json:"id"`
+	Kind      string       `json:"kind"`              // "routing" or "acl"
+	Operation string       `json:"operation"`         // "add", "update" or "delete"
+	RuleID    int          `json:"rule_id"`           // rule updated or deleted, 0 for an added rule
+	Routing   *RoutingRule `json:"routing,omitempty"` // proposed routing rule, the deleted one for deletions
+	ACL       *ACLRule     `json:"acl,omitempty"`     // proposed ACL rule, the deleted one for deletions
+	Percent   int          `json:"percent"`           // share of agents in the canary, 0 with a canary group
+	GroupID   int          `json:"group_id"`          // agent group forming the canary, 0 for a share of agents
+	State     string       `json:"state"`             // "staged", "promoted" or "rolled_back"
+	CreatedBy string       `json:"created_by"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// AccessRequest is temporary access an agent asked for. Approving it
+// grants the access through rules that are deleted when it expires.
+type AccessRequest struct {
+	ID            int       `json:"id"`
+	AgentID       string    `json:"agent_id"`
+	UserID        string    `json:"user_id"`     // owner of the agent
+	Destination   string    `json:"destination"` // CIDR
+	Protocol      string    `json:"protocol"`    // "any", "tcp", "udp" or "icmp"
+	PortFrom      int       `json:"port_from"`   // 0 for any port
+	PortTo        int       `json:"port_to"`
+	Duration      int       `json:"duration"` // seconds asked for
+	Reason        string    `json:"reason"`
+	State         string    `json:"state"`      // "pending", "approved", "denied" or "expired"
+	DecidedBy     string    `json:"decided_by"` // admin who approved or denied it
+	DecidedAt     time.Time `json:"decided_at"`
+	ExpiresAt     time.Time `json:"expires_at"`      // end of the grant, zero unless approved
+	ACLRuleID     int       `json:"acl_rule_id"`     // ACL rule of the grant, 0 for none
+	RoutingRuleID int       `json:"routing_rule_id"` // routing rule of the grant, 0 for none
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AccessRequestFilter selects access requests
+type AccessRequestFilter struct {
+	State   string // empty for all states
+	AgentID string // empty for all agents
+	Limit   int
+}
+
+// AuditLog is an admin API call by a principal that changed something or
+// that its role did not permit
+type AuditLog struct {
+	ID        int64     `json:"id"`
+	UserID    string    `json:"user_id"` // empty once the user is deleted
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Action    string    `json:"action"`
+	SourceIP  string    `json:"source_ip"`
+	Allowed   bool      `json:"allowed"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditLogFilter selects audit log entries
+type AuditLogFilter struct {
+	Username string    // empty for all principals
+	Since    time.Time // zero for no lower bound
+	Limit    int
 }
 
 // GetUserByAPIKey retrieves a user by API key
 func (d *Database) GetUserByAPIKey(apiKey string) (*User, error) {
-	user := &User{}
-	err := d.db.QueryRow(`
-		SELECT id, username, email, password_hash, api_key, status, created_at, updated_at
+	if cached, ok := d.users.get("key:" + apiKey); ok {
+		user := cached.(User)
+		return &user, nil
+	}
+
+	user, err := scanUser(d.db.QueryRow(`
+		SELECT `+userColumns+`
 		FROM users WHERE api_key = ? AND status = 'active'
-	`, apiKey).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
-		&user.APIKey, &user.Status, &user.CreatedAt, &user.UpdatedAt,
-	)
+	`, apiKey))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+
+	d.users.set("key:"+apiKey, *user)
+	d.users.set("id:"+user.ID, *user)
 	return user, nil
 }
 
-// GetAgentByID retrieves an agent by ID
-func (d *Database) GetAgentByID(agentID string) (*Agent, error) {
+// userColumns lists the user columns read by scanUser
+const userColumns = `id, username, email, password_hash, api_key, role, status,
+		       max_agents, max_bandwidth, monthly_transfer_cap, site_prefixes, external_id,
+		       scim_deactivated, created_at, updated_at`
+
+// scanUser scans a user row selected with userColumns
+func scanUser(row rowScanner) (*User, error) {
+	user := &User{}
+	var email, sitePrefixes, externalID sql.NullString
+	var maxAgents, maxBandwidth sql.NullInt64
+	var transferCap sql.NullInt64
+
+	err := row.Scan(
+		&user.ID, &user.Username, &email, &user.PasswordHash,
+		&user.APIKey, &user.Role, &user.Status,
+		&maxAgents, &maxBandwidth, &transferCap, &sitePrefixes, &externalID,
+		&user.SCIMDeactivated, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Email = email.String
+	user.MaxAgents = int(maxAgents.Int64)
+	user.MaxBandwidth = int(maxBandwidth.Int64)
+	user.TransferCap = uint64(transferCap.Int64)
+	user.SitePrefixes = splitList(sitePrefixes.String)
+	user.ExternalID = externalID.String
+
+	return user, nil
+}
+
+// agentColumns lists the agent columns read by scanAgent
+const agentColumns = `id, user_id, name, type, status, ip_address, public_ip,
+		       last_heartbeat, bandwidth_limit, certificate_fingerprint,
+		       metadata, created_at, updated_at, deleted_at, pending, group_id,
+		       identity_key, identity_provider`
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAgent scans an agent row selected with agentColumns
+func scanAgent(row rowScanner) (*Agent, error) {
 	agent := &Agent{}
-	var lastHeartbeat sql.NullTime
-	var bandwidthLimit sql.NullInt64
+	var name, ipAddress, publicIP, fingerprint, metadata sql.NullString
+	var identityKey, identityProvider sql.NullString
+	var lastHeartbeat, deletedAt sql.NullTime
+	var bandwidthLimit, groupID sql.NullInt64
 
-	err := d.db.QueryRow(`
-		SELECT id, user_id, name, type, status, ip_address, public_ip, 
-		       last_heartbeat, bandwidth_limit, certificate_fingerprint, 
-		       metadata, created_at, updated_at
-		FROM agents WHERE id = ?
-	`, agentID).Scan(
-		&agent.ID, &agent.UserID, &agent.Name, &agent.Type, &agent.Status,
-		&agent.IPAddress, &agent.PublicIP, &lastHeartbeat, &bandwidthLimit,
-		&agent.CertificateFingerprint, &agent.Metadata, &agent.CreatedAt, &agent.UpdatedAt,
+	err := row.Scan(
+		&agent.ID, &agent.UserID, &name, &agent.Type, &agent.Status,
+		&ipAddress, &publicIP, &lastHeartbeat, &bandwidthLimit,
+		&fingerprint, &metadata, &agent.CreatedAt, &agent.UpdatedAt, &deletedAt,
+		&agent.Pending, &groupID, &identityKey, &identityProvider,
 	)
+	if err != nil {
+		return nil, err
+	}
 
+	agent.Name = name.String
+	agent.IPAddress = ipAddress.String
+	agent.PublicIP = publicIP.String
+	agent.CertificateFingerprint = fingerprint.String
+	agent.Metadata = metadata.String
+	agent.IdentityKey = identityKey.String
+	agent.IdentityProvider = identityProvider.String
 	if lastHeartbeat.Valid {
 		agent.LastHeartbeat = lastHeartbeat.Time
 	}
 	if bandwidthLimit.Valid {
 		agent.BandwidthLimit = int(bandwidthLimit.Int64)
 	}
+	if deletedAt.Valid {
+		agent.DeletedAt = deletedAt.Time
+	}
+	agent.GroupID = int(groupID.Int64)
+
+	return agent, nil
+}
+
+// GetUserByID retrieves a user by ID
+func (d *Database) GetUserByID(userID string) (*User, error) {
+	if cached, ok := d.users.get("id:" + userID); ok {
+		user := cached.(User)
+		return &user, nil
+	}
+
+	user, err := scanUser(d.db.QueryRow(`
+		SELECT `+userColumns+`
+		FROM users WHERE id = ?
+	`, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	d.users.set("id:"+userID, *user)
+	return user, nil
+}
+
+// ListUsers retrieves all users, ordered by username
+func (d *Database) ListUsers() ([]*User, error) {
+	rows, err := d.db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY username ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// CreateUser creates a new user
+func (d *Database) CreateUser(user *User) error {
+	_, err := d.db.Exec(`
+		INSERT INTO users (id, username, email, password_hash, api_key, role,
+		                   status, max_agents, max_bandwidth, monthly_transfer_cap, site_prefixes, external_id,
+		                   scim_deactivated)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, user.ID, user.Username, nullString(user.Email), user.PasswordHash, user.APIKey, user.Role,
+		cmp.Or(user.Status, "active"),
+		nullInt(int64(user.MaxAgents)), nullInt(int64(user.MaxBandwidth)), nullInt(int64(user.TransferCap)),
+		nullString(strings.Join(user.SitePrefixes, ",")), nullString(user.ExternalID), user.SCIMDeactivated)
+
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// isDuplicateKey reports whether err is a MySQL duplicate key error
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// UpdateUser updates the username, email, role, status, quotas and
+// SCIM state of a user
+func (d *Database) UpdateUser(user *User) error {
+	result, err := d.db.Exec(`
+		UPDATE users
+		SET username = ?, email = ?, role = ?, status = ?,
+		    max_agents = ?, max_bandwidth = ?, monthly_transfer_cap = ?, site_prefixes = ?,
+		    external_id = ?, scim_deactivated = ?
+		WHERE id = ?
+	`, user.Username, nullString(user.Email), user.Role, user.Status,
+		nullInt(int64(user.MaxAgents)), nullInt(int64(user.MaxBandwidth)), nullInt(int64(user.TransferCap)),
+		nullString(strings.Join(user.SitePrefixes, ",")), nullString(user.ExternalID), user.SCIMDeactivated, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	d.invalidateUser(user.ID)
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		// MySQL reports 0 rows for updates that change nothing
+		if _, err := d.GetUserByID(user.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteUser deletes a user together with its agents and usage records
+func (d *Database) DeleteUser(userID string) error {
+	result, err := d.db.Exec(`DELETE FROM users WHERE id = ?`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	// Agents and their rules are deleted by the foreign keys
+	d.invalidateUser(userID)
+	d.agents.deleteIf(func(v interface{}) bool { return v.(Agent).UserID == userID })
+	d.rules.clear()
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// AddUserUsage adds relayed bytes to the usage of a user in a month
+func (d *Database) AddUserUsage(userID, month string, bytesSent, bytesReceived uint64) error {
+	_, err := d.db.Exec(`
+		INSERT INTO user_usage (user_id, month, bytes_sent, bytes_received)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			bytes_sent = bytes_sent + VALUES(bytes_sent),
+			bytes_received = bytes_received + VALUES(bytes_received)
+	`, userID, month, bytesSent, bytesReceived)
+
+	if err != nil {
+		return fmt.Errorf("failed to add user usage: %w", err)
+	}
+	return nil
+}
+
+// UsageRecord is one line of a monthly usage export
+type UsageRecord struct {
+	Month         string `json:"month"`
+	UserID        string `json:"user_id"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	BytesSent     uint64 `json:"bytes_sent"`
+	BytesReceived uint64 `json:"bytes_received"`
+	BytesTotal    uint64 `json:"bytes_total"`
+	TransferCap   uint64 `json:"transfer_cap"` // 0 for unlimited
+}
+
+// ListUsageByMonth retrieves the usage of every user in a month, ordered
+// by username. Users without traffic are included with zero usage.
+func (d *Database) ListUsageByMonth(month string) ([]*UsageRecord, error) {
+	rows, err := d.db.Query(`
+		SELECT u.id, u.username, u.email, u.monthly_transfer_cap,
+		       COALESCE(uu.bytes_sent, 0), COALESCE(uu.bytes_received, 0)
+		FROM users u
+		LEFT JOIN user_usage uu ON uu.user_id = u.id AND uu.month = ?
+		ORDER BY u.username ASC
+	`, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*UsageRecord
+	for rows.Next() {
+		r := &UsageRecord{Month: month}
+		var email sql.NullString
+		var transferCap sql.NullInt64
+		if err := rows.Scan(&r.UserID, &r.Username, &email, &transferCap, &r.BytesSent, &r.BytesReceived); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		r.Email = email.String
+		r.TransferCap = uint64(transferCap.Int64)
+		r.BytesTotal = r.BytesSent + r.BytesReceived
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+// ListUserUsage retrieves the most recent monthly usage of a user, newest first
+func (d *Database) ListUserUsage(userID string, months int) ([]*UserUsage, error) {
+	rows, err := d.db.Query(`
+		SELECT user_id, month, bytes_sent, bytes_received
+		FROM user_usage WHERE user_id = ?
+		ORDER BY month DESC LIMIT ?
+	`, userID, months)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []*UserUsage
+	for rows.Next() {
+		u := &UserUsage{}
+		if err := rows.Scan(&u.UserID, &u.Month, &u.BytesSent, &u.BytesReceived); err != nil {
+			return nil, fmt.Errorf("failed to scan user usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, nil
+}
+
+// UpdateUserAPIKey replaces the API key of a user
+func (d *Database) UpdateUserAPIKey(userID, apiKey string) error {
+	_, err := d.db.Exec(`UPDATE users SET api_key = ? WHERE id = ?`, apiKey, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+	d.invalidateUser(userID)
+	return nil
+}
+
+// GetAgentByID retrieves an agent by ID
+func (d *Database) GetAgentByID(agentID string) (*Agent, error) {
+	if cached, ok := d.agents.get(agentID); ok {
+		agent := cached.(Agent)
+		return &agent, nil
+	}
 
+	agent, err := scanAgent(d.db.QueryRow(`
+		SELECT `+agentColumns+`
+		FROM agents WHERE id = ?
+	`, agentID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("agent not found")
 		}
 		return nil, fmt.Errorf("failed to get agent: %w", err)
 	}
+
+	d.agents.set(agentID, *agent)
 	return agent, nil
 }
 
+// CountAgentsByUser returns the number of agents registered by a user
+func (d *Database) CountAgentsByUser(userID string) (int, error) {
+	var count int
+	err := d.db.QueryRow(`SELECT COUNT(*) FROM agents WHERE user_id = ? AND deleted_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count agents: %w", err)
+	}
+	return count, nil
+}
+
 // CreateAgent creates a new agent
 func (d *Database) CreateAgent(agent *Agent) error {
-	_, err := d.db.Exec(`
+	if err := createAgent(d.db, agent); err != nil {
+		return err
+	}
+	d.agents.delete(agent.ID)
+	return nil
+}
+
+// createAgent inserts an agent using ex
+func createAgent(ex execer, agent *Agent) error {
+	_, err := ex.Exec(`
 		INSERT INTO agents (id, user_id, name, type, status, ip_address, 
-		                   certificate_fingerprint, metadata)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		                   certificate_fingerprint, metadata, pending,
+		                   identity_key, identity_provider)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, agent.ID, agent.UserID, agent.Name, agent.Type, agent.Status,
-		agent.IPAddress, agent.CertificateFingerprint, agent.Metadata)
+		nullString(agent.IPAddress), agent.CertificateFingerprint, agent.Metadata, agent.Pending,
+		nullString(agent.IdentityKey), nullString(agent.IdentityProvider))
 
 	if err != nil {
 		return fmt.Errorf("failed to create agent: %w", err)
@@ -170,7 +690,16 @@ func (d *Database) CreateAgent(agent *Agent) error {
 
 // UpdateAgentStatus updates agent status and heartbeat
 func (d *Database) UpdateAgentStatus(agentID, status string) error {
-	_, err := d.db.Exec(`
+	if err := updateAgentStatus(d.db, agentID, status); err != nil {
+		return err
+	}
+	d.agents.delete(agentID)
+	return nil
+}
+
+// updateAgentStatus updates agent status and heartbeat using ex
+func updateAgentStatus(ex execer, agentID, status string) error {
+	_, err := ex.Exec(`
 		UPDATE agents 
 		SET status = ?, last_heartbeat = NOW()
 		WHERE id = ?
@@ -182,11 +711,29 @@ func (d *Database) UpdateAgentStatus(agentID, status string) error {
 	return nil
 }
 
+// UpdateAgentMetadata replaces the metadata JSON of an agent
+func (d *Database) UpdateAgentMetadata(agentID, metadata string) error {
+	_, err := d.db.Exec(`UPDATE agents SET metadata = ? WHERE id = ?`, metadata, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to update agent metadata: %w", err)
+	}
+	d.agents.delete(agentID)
+	return nil
+}
+
 // CreateSession creates a new session
 func (d *Database) CreateSession(session *Session) error {
-	_, err := d.db.Exec(`
+	return createSession(d.db, session)
+}
+
+// createSession inserts a session using ex
+func createSession(ex execer, session *Session) error {
+	// A session resumed after an HA takeover or handed over by another
+	// server keeps the row the previous server stored
+	_, err := ex.Exec(`
 		INSERT INTO sessions (id, agent_id, connection_id)
 		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE connection_id = VALUES(connection_id), handoff_until = NULL
 	`, session.ID, session.AgentID, session.ConnectionID)
 
 	if err != nil {
@@ -195,6 +742,34 @@ func (d *Database) CreateSession(session *Session) error {
 	return nil
 }
 
+// RegisterAgent atomically creates a new agent, or marks an existing one
+// online, together with its new session
+func (d *Database) RegisterAgent(agent *Agent, created bool, session *Session) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if created {
+		err = createAgent(tx, agent)
+	} else {
+		err = updateAgentStatus(tx, agent.ID, "online")
+	}
+	if err != nil {
+		return err
+	}
+	if err := createSession(tx, session); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit registration: %w", err)
+	}
+	d.agents.delete(agent.ID)
+	return nil
+}
+
 // DeleteSession deletes a session
 func (d *Database) DeleteSession(sessionID string) error {
 	_, err := d.db.Exec(`DELETE FROM sessions WHERE id = ?`, sessionID)
@@ -204,14 +779,50 @@ func (d *Database) DeleteSession(sessionID string) error {
 	return nil
 }
 
-// GetRoutingRulesByAgentID retrieves routing rules for an agent
+// routingColumns lists the routing_rules columns read by scanRoutingRule
+const routingColumns = `id, agent_id, group_id, action, destination, gateway_id, priority, enabled,
+		       valid_from, valid_until, schedule, posture, created_at, updated_at`
+
+// scanRoutingRule scans a routing rule row selected with routingColumns
+func scanRoutingRule(row rowScanner) (*RoutingRule, error) {
+	rule := &RoutingRule{}
+	var agentID, gatewayID, schedule, posture sql.NullString
+	var groupID sql.NullInt64
+	var validFrom, validUntil sql.NullTime
+
+	err := row.Scan(
+		&rule.ID, &agentID, &groupID, &rule.Action, &rule.Destination, &gatewayID, &rule.Priority,
+		&rule.Enabled, &validFrom, &validUntil, &schedule, &posture, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.AgentID = agentID.String
+	rule.GroupID = int(groupID.Int64)
+	rule.GatewayID = gatewayID.String
+	rule.Window = AccessWindow{ValidFrom: validFrom.Time, ValidUntil: validUntil.Time, Schedule: schedule.String}
+	rule.Posture = posture.String
+	return rule, nil
+}
+
+// GetRoutingRulesByAgentID retrieves the enabled routing rules for an
+// agent, its own and those of its group. On equal priority the agent's own
+// rule comes first. The cache takes the read load off the primary.
 func (d *Database) GetRoutingRulesByAgentID(agentID string) ([]*RoutingRule, error) {
+	if cached, ok := d.rules.get(agentID); ok {
+		return copyRules(cached.([]RoutingRule)), nil
+	}
+
+	// Read from the primary: agents re-fetch right after a rule change, and
+	// a lagging replica would put the old rules back into the cache
 	rows, err := d.db.Query(`
-		SELECT id, agent_id, action, destination, gateway_id, priority, enabled, created_at, updated_at
+		SELECT `+routingColumns+`
 		FROM routing_rules
-		WHERE agent_id = ? AND enabled = 1
-		ORDER BY priority ASC
-	`, agentID)
+		WHERE enabled = 1
+		  AND (agent_id = ? OR group_id = (SELECT group_id FROM agents WHERE id = ?))
+		ORDER BY priority ASC, group_id IS NOT NULL
+	`, agentID, agentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get routing rules: %w", err)
 	}
@@ -219,67 +830,803 @@ func (d *Database) GetRoutingRulesByAgentID(agentID string) ([]*RoutingRule, err
 
 	var rules []*RoutingRule
 	for rows.Next() {
-		rule := &RoutingRule{}
-		var gatewayID sql.NullString
-
-		err := rows.Scan(
-			&rule.ID, &rule.AgentID, &rule.Action, &rule.Destination,
-			&gatewayID, &rule.Priority, &rule.Enabled, &rule.CreatedAt, &rule.UpdatedAt,
-		)
+		rule, err := scanRoutingRule(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan routing rule: %w", err)
 		}
-
-		if gatewayID.Valid {
-			rule.GatewayID = gatewayID.String
-		}
-
 		rules = append(rules, rule)
 	}
 
+	cached := make([]RoutingRule, len(rules))
+	for k, rule := range rules {
+		cached[k] = *rule
+	}
+	d.rules.set(agentID, cached)
+
 	return rules, nil
 }
 
-// GetOnlineAgents retrieves all online agents
-func (d *Database) GetOnlineAgents() ([]*Agent, error) {
+// ListRoutingRules retrieves all routing rules for an agent, including disabled ones
+func (d *Database) ListRoutingRules(agentID string) ([]*RoutingRule, error) {
+	return d.listRoutingRules(`agent_id = ?`, agentID)
+}
+
+// ListGroupRoutingRules retrieves all routing rules of an agent group,
+// including disabled ones
+func (d *Database) ListGroupRoutingRules(groupID int) ([]*RoutingRule, error) {
+	return d.listRoutingRules(`group_id = ?`, groupID)
+}
+
+// listRoutingRules retrieves the routing rules matching where, ordered by
+// priority
+func (d *Database) listRoutingRules(where string, arg interface{}) ([]*RoutingRule, error) {
 	rows, err := d.db.Query(`
-		SELECT id, user_id, name, type, status, ip_address, public_ip,
-		       last_heartbeat, bandwidth_limit, certificate_fingerprint,
-		       metadata, created_at, updated_at
-		FROM agents
-		WHERE status = 'online'
-	`)
+		SELECT `+routingColumns+`
+		FROM routing_rules
+		WHERE `+where+`
+		ORDER BY priority ASC
+	`, arg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get online agents: %w", err)
+		return nil, fmt.Errorf("failed to list routing rules: %w", err)
 	}
 	defer rows.Close()
 
-	var agents []*Agent
+	var rules []*RoutingRule
 	for rows.Next() {
-		agent := &Agent{}
-		var lastHeartbeat sql.NullTime
-		var bandwidthLimit sql.NullInt64
-
-		err := rows.Scan(
-			&agent.ID, &agent.UserID, &agent.Name, &agent.Type, &agent.Status,
-			&agent.IPAddress, &agent.PublicIP, &lastHeartbeat, &bandwidthLimit,
-			&agent.CertificateFingerprint, &agent.Metadata, &agent.CreatedAt, &agent.UpdatedAt,
-		)
+		rule, err := scanRoutingRule(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan agent: %w", err)
+			return nil, fmt.Errorf("failed to scan routing rule: %w", err)
 		}
+		rules = append(rules, rule)
+	}
 
-		if lastHeartbeat.Valid {
-			agent.LastHeartbeat = lastHeartbeat.Time
-		}
-		if bandwidthLimit.Valid {
-			agent.BandwidthLimit = int(bandwidthLimit.Int64)
-		}
+	return rules, nil
+}
 
-		agents = append(agents, agent)
+// GetRoutingRuleByID retrieves a routing rule by ID
+func (d *Database) GetRoutingRuleByID(ruleID int) (*RoutingRule, error) {
+	rule, err := scanRoutingRule(d.db.QueryRow(`SELECT `+routingColumns+` FROM routing_rules WHERE id = ?`, ruleID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("routing rule not found")
+		}
+		return nil, fmt.Errorf("failed to get routing rule: %w", err)
 	}
-
-	return agents, nil
+	return rule, nil
+}
+
+// CreateRoutingRule creates a new routing rule and sets its ID
+func (d *Database) CreateRoutingRule(rule *RoutingRule) error {
+	result, err := d.db.Exec(`
+		INSERT INTO routing_rules (agent_id, group_id, action, destination, gateway_id, priority, enabled,
+		                           valid_from, valid_until, schedule, posture)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, nullString(rule.AgentID), nullInt(int64(rule.GroupID)), rule.Action, rule.Destination, nullString(rule.GatewayID),
+		rule.Priority, rule.Enabled, nullTime(rule.Window.ValidFrom), nullTime(rule.Window.ValidUntil),
+		nullString(rule.Window.Schedule), nullString(rule.Posture))
+	if err != nil {
+		return fmt.Errorf("failed to create routing rule: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get routing rule ID: %w", err)
+	}
+	rule.ID = int(id)
+	if rule.GroupID != 0 {
+		d.rules.clear()
+	} else {
+		d.rules.delete(rule.AgentID)
+	}
+	return nil
+}
+
+// UpdateRoutingRule updates an existing routing rule
+func (d *Database) UpdateRoutingRule(rule *RoutingRule) error {
+	_, err := d.db.Exec(`
+		UPDATE routing_rules
+		SET action = ?, destination = ?, gateway_id = ?, priority = ?, enabled = ?,
+		    valid_from = ?, valid_until = ?, schedule = ?, posture = ?
+		WHERE id = ?
+	`, rule.Action, rule.Destination, nullString(rule.GatewayID),
+		rule.Priority, rule.Enabled, nullTime(rule.Window.ValidFrom), nullTime(rule.Window.ValidUntil),
+		nullString(rule.Window.Schedule), nullString(rule.Posture), rule.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to update routing rule: %w", err)
+	}
+	d.rules.clear()
+	return nil
+}
+
+// DeleteRoutingRule deletes a routing rule
+func (d *Database) DeleteRoutingRule(ruleID int) error {
+	_, err := d.db.Exec(`DELETE FROM routing_rules WHERE id = ?`, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete routing rule: %w", err)
+	}
+	d.rules.clear()
+	return nil
+}
+
+// groupColumns lists the agent_groups columns read by scanAgentGroup, with
+// the number of agents in the group
+const groupColumns = `g.id, g.name, g.description, g.dns_servers, g.dns_search, g.bandwidth_limit,
+		       g.created_at, g.updated_at,
+		       (SELECT COUNT(*) FROM agents a WHERE a.group_id = g.id AND a.deleted_at IS NULL)`
+
+// scanAgentGroup scans an agent group row selected with groupColumns
+func scanAgentGroup(row rowScanner) (*AgentGroup, error) {
+	group := &AgentGroup{}
+	var dnsServers, dnsSearch string
+	err := row.Scan(&group.ID, &group.Name, &group.Description, &dnsServers, &dnsSearch,
+		&group.BandwidthLimit, &group.CreatedAt, &group.UpdatedAt, &group.AgentCount)
+	if err != nil {
+		return nil, err
+	}
+	group.DNSServers = splitList(dnsServers)
+	group.DNSSearch = splitList(dnsSearch)
+	return group, nil
+}
+
+// ListAgentGroups retrieves all agent groups ordered by name
+func (d *Database) ListAgentGroups() ([]*AgentGroup, error) {
+	rows, err := d.db.Query(`SELECT ` + groupColumns + ` FROM agent_groups g ORDER BY g.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*AgentGroup
+	for rows.Next() {
+		group, err := scanAgentGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan agent group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// GetAgentGroup retrieves an agent group by ID. Cached groups may carry a
+// stale agent count.
+func (d *Database) GetAgentGroup(groupID int) (*AgentGroup, error) {
+	key := strconv.Itoa(groupID)
+	if cached, ok := d.groups.get(key); ok {
+		group := cached.(AgentGroup)
+		return &group, nil
+	}
+
+	group, err := scanAgentGroup(d.db.QueryRow(`SELECT `+groupColumns+` FROM agent_groups g WHERE g.id = ?`, groupID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("agent group not found")
+		}
+		return nil, fmt.Errorf("failed to get agent group: %w", err)
+	}
+
+	d.groups.set(key, *group)
+	return group, nil
+}
+
+// CreateAgentGroup creates an agent group and sets its ID
+func (d *Database) CreateAgentGroup(group *AgentGroup) error {
+	result, err := d.db.Exec(`
+		INSERT INTO agent_groups (name, description, dns_servers, dns_search, bandwidth_limit)
+		VALUES (?, ?, ?, ?, ?)
+	`, group.Name, group.Description, strings.Join(group.DNSServers, ","),
+		strings.Join(group.DNSSearch, ","), group.BandwidthLimit)
+	if err != nil {
+		return fmt.Errorf("failed to create agent group: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get agent group ID: %w", err)
+	}
+	group.ID = int(id)
+	return nil
+}
+
+// UpdateAgentGroup replaces the settings of an agent group
+func (d *Database) UpdateAgentGroup(group *AgentGroup) error {
+	_, err := d.db.Exec(`
+		UPDATE agent_groups
+		SET name = ?, description = ?, dns_servers = ?, dns_search = ?, bandwidth_limit = ?
+		WHERE id = ?
+	`, group.Name, group.Description, strings.Join(group.DNSServers, ","),
+		strings.Join(group.DNSSearch, ","), group.BandwidthLimit, group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update agent group: %w", err)
+	}
+	d.groups.delete(strconv.Itoa(group.ID))
+	return nil
+}
+
+// DeleteAgentGroup deletes an agent group. Its routing rules are removed
+// and its agents leave it by the foreign keys.
+func (d *Database) DeleteAgentGroup(groupID int) error {
+	if _, err := d.db.Exec(`DELETE FROM agent_groups WHERE id = ?`, groupID); err != nil {
+		return fmt.Errorf("failed to delete agent group: %w", err)
+	}
+	d.groups.delete(strconv.Itoa(groupID))
+	d.agents.clear()
+	d.rules.clear()
+	return nil
+}
+
+// SetAgentGroup moves an agent into a group, 0 for none
+func (d *Database) SetAgentGroup(agentID string, groupID int) error {
+	_, err := d.db.Exec(`UPDATE agents SET group_id = ? WHERE id = ?`, nullInt(int64(groupID)), agentID)
+	if err != nil {
+		return fmt.Errorf("failed to set agent group: %w", err)
+	}
+	d.agents.delete(agentID)
+	d.rules.delete(agentID)
+	return nil
+}
+
+// aclColumns lists the acl_rules columns read by scanACLRule
+const aclColumns = `id, user_id, source, destination, protocol, port_from, port_to,
+		       action, priority, enabled, valid_from, valid_until, schedule, source_uid, posture, created_at, updated_at`
+
+// scanACLRule scans an ACL rule row selected with aclColumns
+func scanACLRule(row rowScanner) (*ACLRule, error) {
+	rule := &ACLRule{}
+	var source, destination, schedule, posture sql.NullString
+	var validFrom, validUntil sql.NullTime
+	var sourceUID sql.NullInt64
+
+	err := row.Scan(
+		&rule.ID, &rule.UserID, &source, &destination, &rule.Protocol, &rule.PortFrom,
+		&rule.PortTo, &rule.Action, &rule.Priority, &rule.Enabled, &validFrom, &validUntil,
+		&schedule, &sourceUID, &posture, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Source = source.String
+	rule.Destination = destination.String
+	rule.Window = AccessWindow{ValidFrom: validFrom.Time, ValidUntil: validUntil.Time, Schedule: schedule.String}
+	rule.Posture = posture.String
+	if sourceUID.Valid {
+		uid := uint32(sourceUID.Int64)
+		rule.SourceUID = &uid
+	}
+	return rule, nil
+}
+
+// ListACLRules retrieves the ACL rules of a user ordered by priority.
+// Disabled rules are included unless enabledOnly is set.
+func (d *Database) ListACLRules(userID string, enabledOnly bool) ([]*ACLRule, error) {
+	query := `SELECT ` + aclColumns + ` FROM acl_rules WHERE user_id = ?`
+	if enabledOnly {
+		query += ` AND enabled = 1`
+	}
+	query += ` ORDER BY priority ASC, id ASC`
+
+	// Read from the primary so that a rule change takes effect immediately
+	rows, err := d.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ACL rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*ACLRule
+	for rows.Next() {
+		rule, err := scanACLRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ACL rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// GetACLRuleByID retrieves an ACL rule by ID
+func (d *Database) GetACLRuleByID(ruleID int) (*ACLRule, error) {
+	rule, err := scanACLRule(d.db.QueryRow(`SELECT `+aclColumns+` FROM acl_rules WHERE id = ?`, ruleID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("ACL rule not found")
+		}
+		return nil, fmt.Errorf("failed to get ACL rule: %w", err)
+	}
+	return rule, nil
+}
+
+// CreateACLRule creates a new ACL rule and sets its ID
+func (d *Database) CreateACLRule(rule *ACLRule) error {
+	result, err := d.db.Exec(`
+		INSERT INTO acl_rules (user_id, source, destination, protocol, port_from, port_to,
+		                       action, priority, enabled, valid_from, valid_until, schedule, source_uid, posture)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, rule.UserID, nullString(rule.Source), nullString(rule.Destination), rule.Protocol,
+		rule.PortFrom, rule.PortTo, rule.Action, rule.Priority, rule.Enabled,
+		nullTime(rule.Window.ValidFrom), nullTime(rule.Window.ValidUntil), nullString(rule.Window.Schedule),
+		rule.SourceUID, nullString(rule.Posture))
+	if err != nil {
+		return fmt.Errorf("failed to create ACL rule: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get ACL rule ID: %w", err)
+	}
+	rule.ID = int(id)
+	return nil
+}
+
+// UpdateACLRule updates an existing ACL rule
+func (d *Database) UpdateACLRule(rule *ACLRule) error {
+	_, err := d.db.Exec(`
+		UPDATE acl_rules
+		SET source = ?, destination = ?, protocol = ?, port_from = ?, port_to = ?,
+		    action = ?, priority = ?, enabled = ?, valid_from = ?, valid_until = ?, schedule = ?,
+		    source_uid = ?, posture = ?
+		WHERE id = ?
+	`, nullString(rule.Source), nullString(rule.Destination), rule.Protocol, rule.PortFrom,
+		rule.PortTo, rule.Action, rule.Priority, rule.Enabled, nullTime(rule.Window.ValidFrom),
+		nullTime(rule.Window.ValidUntil), nullString(rule.Window.Schedule), rule.SourceUID,
+		nullString(rule.Posture), rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update ACL rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteACLRule deletes an ACL rule
+func (d *Database) DeleteACLRule(ruleID int) error {
+	_, err := d.db.Exec(`DELETE FROM acl_rules WHERE id = ?`, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete ACL rule: %w", err)
+	}
+	return nil
+}
+
+// rolloutColumns lists the rollouts columns read by scanRollout
+const rolloutColumns = `id, kind, operation, rule_id, rule, percent, group_id, state, created_by, created_at, updated_at`
+
+// scanRollout scans a rollout row selected with rolloutColumns
+func scanRollout(row rowScanner) (*Rollout, error) {
+	r := &Rollout{}
+	var ruleID, groupID sql.NullInt64
+	var rule string
+
+	err := row.Scan(&r.ID, &r.Kind, &r.Operation, &ruleID, &rule, &r.Percent, &groupID,
+		&r.State, &r.CreatedBy, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	r.RuleID = int(ruleID.Int64)
+	r.GroupID = int(groupID.Int64)
+	if r.Kind == "acl" {
+		r.ACL = &ACLRule{}
+		err = json.Unmarshal([]byte(rule), r.ACL)
+	} else {
+		r.Routing = &RoutingRule{}
+		err = json.Unmarshal([]byte(rule), r.Routing)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule of rollout %d: %w", r.ID, err)
+	}
+	return r, nil
+}
+
+// CreateRollout stores a staged rollout and sets its ID
+func (d *Database) CreateRollout(r *Rollout) error {
+	var rule interface{} = r.Routing
+	if r.Kind == "acl" {
+		rule = r.ACL
+	}
+	encoded, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to encode rollout rule: %w", err)
+	}
+
+	result, err := d.db.Exec(`
+		INSERT INTO rollouts (kind, operation, rule_id, rule, percent, group_id, state, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, r.Kind, r.Operation, nullInt(int64(r.RuleID)), string(encoded), r.Percent, nullInt(int64(r.GroupID)),
+		r.State, r.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to create rollout: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get rollout ID: %w", err)
+	}
+	r.ID = int(id)
+	return nil
+}
+
+// GetRollout retrieves a rollout by ID
+func (d *Database) GetRollout(id int) (*Rollout, error) {
+	r, err := scanRollout(d.db.QueryRow(`SELECT `+rolloutColumns+` FROM rollouts WHERE id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("rollout not found")
+		}
+		return nil, fmt.Errorf("failed to get rollout: %w", err)
+	}
+	return r, nil
+}
+
+// GetStagedRollout retrieves the staged rollout, nil if there is none
+func (d *Database) GetStagedRollout() (*Rollout, error) {
+	// Read from the primary so that staging takes effect immediately
+	r, err := scanRollout(d.db.QueryRow(`
+		SELECT ` + rolloutColumns + ` FROM rollouts WHERE state = 'staged' ORDER BY id DESC LIMIT 1
+	`))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged rollout: %w", err)
+	}
+	return r, nil
+}
+
+// ListRollouts retrieves the latest rollouts, newest first
+func (d *Database) ListRollouts(limit int) ([]*Rollout, error) {
+	rows, err := d.queryRead(`SELECT `+rolloutColumns+` FROM rollouts ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rollouts: %w", err)
+	}
+	defer rows.Close()
+
+	var rollouts []*Rollout
+	for rows.Next() {
+		r, err := scanRollout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rollout: %w", err)
+		}
+		rollouts = append(rollouts, r)
+	}
+
+	return rollouts, nil
+}
+
+// FinishRollout moves a staged rollout to its final state, promoted or
+// rolled_back. It fails if the rollout is no longer staged.
+func (d *Database) FinishRollout(id int, state string) error {
+	result, err := d.db.Exec(`UPDATE rollouts SET state = ? WHERE id = ? AND state = 'staged'`, state, id)
+	if err != nil {
+		return fmt.Errorf("failed to update rollout: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("rollout %d is not staged", id)
+	}
+	return nil
+}
+
+// accessRequestColumns lists the access_requests columns read by
+// scanAccessRequest, joined with the agents table as a
+const accessRequestColumns = `r.id, r.agent_id, a.user_id, r.destination, r.protocol, r.port_from, r.port_to,
+	r.duration, r.reason, r.state, r.decided_by, r.decided_at, r.expires_at, r.acl_rule_id, r.routing_rule_id, r.created_at`
+
+// scanAccessRequest scans an access request row selected with
+// accessRequestColumns
+func scanAccessRequest(row rowScanner) (*AccessRequest, error) {
+	r := &AccessRequest{}
+	var decidedBy sql.NullString
+	var decidedAt, expiresAt sql.NullTime
+	var aclRuleID, routingRuleID sql.NullInt64
+
+	err := row.Scan(&r.ID, &r.AgentID, &r.UserID, &r.Destination, &r.Protocol, &r.PortFrom, &r.PortTo,
+		&r.Duration, &r.Reason, &r.State, &decidedBy, &decidedAt, &expiresAt, &aclRuleID, &routingRuleID, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	r.DecidedBy = decidedBy.String
+	r.DecidedAt = decidedAt.Time
+	r.ExpiresAt = expiresAt.Time
+	r.ACLRuleID = int(aclRuleID.Int64)
+	r.RoutingRuleID = int(routingRuleID.Int64)
+	return r, nil
+}
+
+// CreateAccessRequest stores a pending access request and sets its ID
+func (d *Database) CreateAccessRequest(r *AccessRequest) error {
+	result, err := d.db.Exec(`
+		INSERT INTO access_requests (agent_id, destination, protocol, port_from, port_to, duration, reason)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, r.AgentID, r.Destination, r.Protocol, r.PortFrom, r.PortTo, r.Duration, r.Reason)
+	if err != nil {
+		return fmt.Errorf("failed to create access request: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get access request ID: %w", err)
+	}
+	r.ID = int(id)
+	return nil
+}
+
+// GetAccessRequest retrieves an access request by ID
+func (d *Database) GetAccessRequest(id int) (*AccessRequest, error) {
+	r, err := scanAccessRequest(d.db.QueryRow(`
+		SELECT `+accessRequestColumns+` FROM access_requests r JOIN agents a ON a.id = r.agent_id WHERE r.id = ?
+	`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("access request not found")
+		}
+		return nil, fmt.Errorf("failed to get access request: %w", err)
+	}
+	return r, nil
+}
+
+// ListAccessRequests retrieves access requests, newest first
+func (d *Database) ListAccessRequests(filter AccessRequestFilter) ([]*AccessRequest, error) {
+	query := `SELECT ` + accessRequestColumns + ` FROM access_requests r JOIN agents a ON a.id = r.agent_id WHERE 1 = 1`
+	var args []interface{}
+	if filter.State != "" {
+		query += ` AND r.state = ?`
+		args = append(args, filter.State)
+	}
+	if filter.AgentID != "" {
+		query += ` AND r.agent_id = ?`
+		args = append(args, filter.AgentID)
+	}
+	query += ` ORDER BY r.id DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := d.queryRead(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*AccessRequest
+	for rows.Next() {
+		r, err := scanAccessRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan access request: %w", err)
+		}
+		requests = append(requests, r)
+	}
+
+	return requests, nil
+}
+
+// CountPendingAccessRequests counts the pending access requests of an agent
+func (d *Database) CountPendingAccessRequests(agentID string) (int, error) {
+	var n int
+	err := d.db.QueryRow(`
+		SELECT COUNT(*) FROM access_requests WHERE agent_id = ? AND state = 'pending'
+	`, agentID).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count access requests: %w", err)
+	}
+	return n, nil
+}
+
+// ApproveAccessRequest records the grant of a pending access request. It
+// fails if the request is no longer pending.
+func (d *Database) ApproveAccessRequest(r *AccessRequest) error {
+	result, err := d.db.Exec(`
+		UPDATE access_requests
+		SET state = 'approved', decided_by = ?, decided_at = ?, expires_at = ?, acl_rule_id = ?, routing_rule_id = ?
+		WHERE id = ? AND state = 'pending'
+	`, r.DecidedBy, r.DecidedAt, r.ExpiresAt, nullInt(int64(r.ACLRuleID)), nullInt(int64(r.RoutingRuleID)), r.ID)
+	if err != nil {
+		return fmt.Errorf("failed to approve access request: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("access request %d is not pending", r.ID)
+	}
+	return nil
+}
+
+// DenyAccessRequest denies a pending access request. It fails if the
+// request is no longer pending.
+func (d *Database) DenyAccessRequest(id int, decidedBy string, decidedAt time.Time) error {
+	result, err := d.db.Exec(`
+		UPDATE access_requests SET state = 'denied', decided_by = ?, decided_at = ?
+		WHERE id = ? AND state = 'pending'
+	`, decidedBy, decidedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to deny access request: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("access request %d is not pending", id)
+	}
+	return nil
+}
+
+// ListExpiredAccessGrants retrieves the approved access requests whose
+// grant ended by now
+func (d *Database) ListExpiredAccessGrants(now time.Time) ([]*AccessRequest, error) {
+	// Read from the primary, a lagging replica would return grants
+	// another server already removed
+	rows, err := d.db.Query(`
+		SELECT `+accessRequestColumns+` FROM access_requests r JOIN agents a ON a.id = r.agent_id
+		WHERE r.state = 'approved' AND r.expires_at <= ?
+	`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired access grants: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*AccessRequest
+	for rows.Next() {
+		r, err := scanAccessRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan access request: %w", err)
+		}
+		requests = append(requests, r)
+	}
+
+	return requests, nil
+}
+
+// ExpireAccessRequest marks an approved access request expired once its
+// rules are deleted. It reports false if another server did first.
+func (d *Database) ExpireAccessRequest(id int) (bool, error) {
+	result, err := d.db.Exec(`
+		UPDATE access_requests SET state = 'expired', acl_rule_id = NULL, routing_rule_id = NULL
+		WHERE id = ? AND state = 'approved'
+	`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to expire access request: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// AddAuditLog records an admin API call
+func (d *Database) AddAuditLog(entry *AuditLog) error {
+	status := "failure"
+	if entry.Allowed {
+		status = "success"
+	}
+	_, err := d.db.Exec(`
+		INSERT INTO audit_logs (user_id, username, role, action, ip_address, status)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, nullString(entry.UserID), entry.Username, entry.Role, entry.Action, nullString(entry.SourceIP), status)
+	if err != nil {
+		return fmt.Errorf("failed to add audit log: %w", err)
+	}
+	return nil
+}
+
+// ListAuditLogs retrieves audit log entries of admin API calls, those
+// naming their principal, newest first
+func (d *Database) ListAuditLogs(filter AuditLogFilter) ([]*AuditLog, error) {
+	query := `
+		SELECT id, user_id, username, role, action, ip_address, status, created_at
+		FROM audit_logs WHERE username IS NOT NULL`
+	var args []interface{}
+	if filter.Username != "" {
+		query += ` AND username = ?`
+		args = append(args, filter.Username)
+	}
+	if !filter.Since.IsZero() {
+		query += ` AND created_at >= ?`
+		args = append(args, filter.Since)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := d.queryRead(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*AuditLog
+	for rows.Next() {
+		entry := &AuditLog{}
+		var userID, role, sourceIP sql.NullString
+		var status string
+		if err := rows.Scan(&entry.ID, &userID, &entry.Username, &role, &entry.Action,
+			&sourceIP, &status, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		entry.UserID = userID.String
+		entry.Role = role.String
+		entry.SourceIP = sourceIP.String
+		entry.Allowed = status == "success"
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetOnlineAgents retrieves all online agents
+func (d *Database) GetOnlineAgents() ([]*Agent, error) {
+	rows, err := d.queryRead(`
+		SELECT ` + agentColumns + `
+		FROM agents
+		WHERE status = 'online' AND deleted_at IS NULL
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get online agents: %w", err)
+	}
+	defer rows.Close()
+
+	var agents []*Agent
+	for rows.Next() {
+		agent, err := scanAgent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan agent: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+
+	return agents, nil
+}
+
+// AgentFilter selects agents in ListAgents
+type AgentFilter struct {
+	Type    string            // "client" or "gateway", empty for all
+	Status  string            // agent status, empty for all
+	UserID  string            // owning user, empty for all
+	Labels  map[string]string // metadata labels that must all match
+	AfterID string            // return agents with ID greater than this
+	Limit   int               // maximum number of agents
+	Deleted bool              // list archived instead of active agents
+	Pending bool              // list agents awaiting approval instead of approved ones
+}
+
+// ListAgents retrieves agents matching a filter, ordered by ID
+func (d *Database) ListAgents(filter AgentFilter) ([]*Agent, error) {
+	query := `SELECT ` + agentColumns + ` FROM agents WHERE id > ?`
+	args := []interface{}{filter.AfterID}
+
+	if filter.Deleted {
+		query += ` AND deleted_at IS NOT NULL`
+	} else {
+		query += ` AND deleted_at IS NULL`
+	}
+	if filter.Pending {
+		query += ` AND pending = 1`
+	} else {
+		query += ` AND pending = 0`
+	}
+	if filter.Type != "" {
+		query += ` AND type = ?`
+		args = append(args, filter.Type)
+	}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	if filter.UserID != "" {
+		query += ` AND user_id = ?`
+		args = append(args, filter.UserID)
+	}
+	for key, value := range filter.Labels {
+		query += ` AND JSON_UNQUOTE(JSON_EXTRACT(metadata, ?)) = ?`
+		args = append(args, fmt.Sprintf(`$.labels."%s"`, strings.ReplaceAll(key, `"`, `\"`)), value)
+	}
+
+	query += ` ORDER BY id ASC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	defer rows.Close()
+
+	var agents []*Agent
+	for rows.Next() {
+		agent, err := scanAgent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan agent: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+
+	return agents, nil
 }
 
 // UpdateSessionStats updates session statistics
@@ -295,3 +1642,395 @@ func (d *Database) UpdateSessionStats(sessionID string, bytesSent, bytesReceived
 	}
 	return nil
 }
+
+// OfferHandoff stores the counters of a session and lets another server
+// continue it until the given time
+func (d *Database) OfferHandoff(sessionID string, bytesSent, bytesReceived uint64, until time.Time) error {
+	_, err := d.db.Exec(`
+		UPDATE sessions
+		SET bytes_sent = ?, bytes_received = ?, handoff_until = ?
+		WHERE id = ?
+	`, bytesSent, bytesReceived, until, sessionID)
+
+	if err != nil {
+		return fmt.Errorf("failed to offer session handoff: %w", err)
+	}
+	return nil
+}
+
+// GetHandoff returns a session of an agent offered to another server,
+// sql.ErrNoRows if there is none or the offer expired
+func (d *Database) GetHandoff(sessionID, agentID string) (*Session, error) {
+	session := &Session{}
+	err := d.db.QueryRow(`
+		SELECT id, agent_id, connection_id, connected_at, last_activity, bytes_sent, bytes_received
+		FROM sessions
+		WHERE id = ? AND agent_id = ? AND handoff_until > NOW()
+	`, sessionID, agentID).Scan(
+		&session.ID, &session.AgentID, &session.ConnectionID, &session.ConnectedAt,
+		&session.LastActivity, &session.BytesSent, &session.BytesReceived,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session handoff: %w", err)
+	}
+	return session, nil
+}
+
+// GetSessionConnection returns the connection a session is stored with,
+// sql.ErrNoRows once it ended
+func (d *Database) GetSessionConnection(sessionID string) (string, error) {
+	var connectionID string
+	err := d.db.QueryRow(`SELECT connection_id FROM sessions WHERE id = ?`, sessionID).Scan(&connectionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get session: %w", err)
+	}
+	return connectionID, nil
+}
+
+// EndSession moves a session to the history with its final counters and
+// the reason it ended
+func (d *Database) EndSession(sessionID string, bytesSent, bytesReceived uint64, code, reason string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO session_history (id, agent_id, connection_id, connected_at, bytes_sent, bytes_received,
+			disconnect_code, disconnect_reason)
+		SELECT id, agent_id, connection_id, connected_at, ?, ?, ?, ?
+		FROM sessions WHERE id = ?
+	`, bytesSent, bytesReceived, code, reason, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to record session history: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM sessions WHERE id = ?`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit session history: %w", err)
+	}
+	return nil
+}
+
+// ArchiveAgent soft-deletes an agent, moving its remaining sessions to
+// the history. Its rules, history and audit trail are kept.
+func (d *Database) ArchiveAgent(agentID string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE agents SET deleted_at = NOW(), status = 'offline'
+		WHERE id = ? AND deleted_at IS NULL
+	`, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to archive agent: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("agent not found or already archived")
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO session_history (id, agent_id, connection_id, connected_at, bytes_sent, bytes_received)
+		SELECT id, agent_id, connection_id, connected_at, bytes_sent, bytes_received
+		FROM sessions WHERE agent_id = ?
+	`, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to record session history: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM sessions WHERE agent_id = ?`, agentID); err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit archive: %w", err)
+	}
+	d.agents.delete(agentID)
+	d.rules.clear()
+	return nil
+}
+
+// RestoreAgent restores an archived agent with its overlay IP
+func (d *Database) RestoreAgent(agentID, ipAddress string) error {
+	result, err := d.db.Exec(`
+		UPDATE agents SET deleted_at = NULL, ip_address = ?
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`, ipAddress, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to restore agent: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("agent not found or not archived")
+	}
+	d.agents.delete(agentID)
+	return nil
+}
+
+// ApproveAgent approves a pending agent and stores its overlay IP
+func (d *Database) ApproveAgent(agentID, ipAddress string) error {
+	result, err := d.db.Exec(`
+		UPDATE agents SET pending = 0, ip_address = ?
+		WHERE id = ? AND pending = 1
+	`, ipAddress, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to approve agent: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("agent not found or not pending")
+	}
+	d.agents.delete(agentID)
+	return nil
+}
+
+// SetAgentIdentity binds an agent to the public key of its hardware
+// identity, or unbinds it with an empty key
+func (d *Database) SetAgentIdentity(agentID, key, provider string) error {
+	result, err := d.db.Exec(`
+		UPDATE agents SET identity_key = ?, identity_provider = ?
+		WHERE id = ?
+	`, nullString(key), nullString(provider), agentID)
+	if err != nil {
+		return fmt.Errorf("failed to set agent identity: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("agent not found")
+	}
+	d.agents.delete(agentID)
+	return nil
+}
+
+// DeletePendingAgent deletes an agent that was never approved
+func (d *Database) DeletePendingAgent(agentID string) error {
+	result, err := d.db.Exec(`DELETE FROM agents WHERE id = ? AND pending = 1`, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete agent: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("agent not found or not pending")
+	}
+	d.agents.delete(agentID)
+	return nil
+}
+
+// ListSessionHistory retrieves the latest ended sessions of an agent
+func (d *Database) ListSessionHistory(agentID string, limit int) ([]*SessionRecord, error) {
+	rows, err := d.db.Query(`
+		SELECT id, agent_id, connection_id, connected_at, disconnected_at, bytes_sent, bytes_received,
+			disconnect_code, disconnect_reason
+		FROM session_history
+		WHERE agent_id = ?
+		ORDER BY disconnected_at DESC
+		LIMIT ?
+	`, agentID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*SessionRecord
+	for rows.Next() {
+		r := &SessionRecord{}
+		err := rows.Scan(&r.ID, &r.AgentID, &r.ConnectionID, &r.ConnectedAt,
+			&r.DisconnectedAt, &r.BytesSent, &r.BytesReceived, &r.ReasonCode, &r.Reason)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session history: %w", err)
+		}
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+// PurgeHistory permanently deletes session history and archived agents
+// older than before, returning the number of each removed
+func (d *Database) PurgeHistory(before time.Time) (sessions, agents int64, err error) {
+	result, err := d.db.Exec(`DELETE FROM session_history WHERE disconnected_at < ?`, before)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to purge session history: %w", err)
+	}
+	sessions, _ = result.RowsAffected()
+
+	// History of purged agents is removed by the foreign keys
+	result, err = d.db.Exec(`DELETE FROM agents WHERE deleted_at < ?`, before)
+	if err != nil {
+		return sessions, 0, fmt.Errorf("failed to purge archived agents: %w", err)
+	}
+	agents, _ = result.RowsAffected()
+	if agents > 0 {
+		d.rules.clear()
+	}
+
+	return sessions, agents, nil
+}
+
+// RollupHourlyStats sums the sessions that ended in completed hours since
+// the last hourly rollup, but not before since, into stats_hourly. It
+// returns the number of rollups written.
+func (d *Database) RollupHourlyStats(since time.Time) (int64, error) {
+	var last sql.NullTime
+	if err := d.db.QueryRow(`SELECT MAX(period_start) FROM stats_hourly`).Scan(&last); err != nil {
+		return 0, fmt.Errorf("failed to read last hourly rollup: %w", err)
+	}
+	from := time.Unix(0, 0)
+	if last.Valid {
+		from = last.Time.Add(time.Hour)
+	}
+	if since.After(from) {
+		from = since
+	}
+
+	result, err := d.db.Exec(`
+		INSERT INTO stats_hourly (period_start, agent_id, user_id, sessions, connected_seconds,
+			bytes_sent, bytes_received)
+		SELECT DATE_FORMAT(h.disconnected_at, '%Y-%m-%d %H:00:00') AS hour, h.agent_id, a.user_id, COUNT(*),
+			SUM(GREATEST(TIMESTAMPDIFF(SECOND, h.connected_at, h.disconnected_at), 0)),
+			SUM(h.bytes_sent), SUM(h.bytes_received)
+		FROM session_history h
+		JOIN agents a ON a.id = h.agent_id
+		WHERE h.disconnected_at >= ? AND h.disconnected_at < DATE_FORMAT(NOW(), '%Y-%m-%d %H:00:00')
+		GROUP BY hour, h.agent_id, a.user_id
+		ON DUPLICATE KEY UPDATE sessions = VALUES(sessions), connected_seconds = VALUES(connected_seconds),
+			bytes_sent = VALUES(bytes_sent), bytes_received = VALUES(bytes_received)
+	`, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll up hourly stats: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// RollupDailyStats sums the hourly rollups of completed days since the
+// last daily rollup into stats_daily, returning the number written. Days
+// follow the time zone of the database.
+func (d *Database) RollupDailyStats() (int64, error) {
+	last := "1000-01-01"
+	var day sql.NullString
+	if err := d.db.QueryRow(`SELECT DATE_FORMAT(MAX(day), '%Y-%m-%d') FROM stats_daily`).Scan(&day); err != nil {
+		return 0, fmt.Errorf("failed to read last daily rollup: %w", err)
+	}
+	if day.Valid {
+		last = day.String
+	}
+
+	result, err := d.db.Exec(`
+		INSERT INTO stats_daily (day, agent_id, user_id, sessions, connected_seconds, bytes_sent, bytes_received)
+		SELECT DATE(period_start) AS day, agent_id, MAX(user_id), SUM(sessions), SUM(connected_seconds),
+			SUM(bytes_sent), SUM(bytes_received)
+		FROM stats_hourly
+		WHERE DATE(period_start) > ? AND period_start < CURDATE()
+		GROUP BY day, agent_id
+		ON DUPLICATE KEY UPDATE sessions = VALUES(sessions), connected_seconds = VALUES(connected_seconds),
+			bytes_sent = VALUES(bytes_sent), bytes_received = VALUES(bytes_received)
+	`, last)
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll up daily stats: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// PurgeStatsRollups deletes hourly rollups of hours before hourlyBefore
+// and daily rollups of days before dailyBefore, a zero time keeps them
+func (d *Database) PurgeStatsRollups(hourlyBefore, dailyBefore time.Time) (hourly, daily int64, err error) {
+	if !hourlyBefore.IsZero() {
+		result, err := d.db.Exec(`DELETE FROM stats_hourly WHERE period_start < ?`, hourlyBefore)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to purge hourly stats: %w", err)
+		}
+		hourly, _ = result.RowsAffected()
+	}
+	if !dailyBefore.IsZero() {
+		result, err := d.db.Exec(`DELETE FROM stats_daily WHERE day < ?`, dailyBefore.Format("2006-01-02"))
+		if err != nil {
+			return hourly, 0, fmt.Errorf("failed to purge daily stats: %w", err)
+		}
+		daily, _ = result.RowsAffected()
+	}
+	return hourly, daily, nil
+}
+
+// ListTrafficRollups retrieves hourly or daily rollups, newest first
+func (d *Database) ListTrafficRollups(filter RollupFilter) ([]*TrafficRollup, error) {
+	table, period := "stats_hourly", "period_start"
+	if filter.Daily {
+		table, period = "stats_daily", "day"
+	}
+
+	query := `SELECT ` + period + `, agent_id, user_id, sessions, connected_seconds, bytes_sent, bytes_received
+		FROM ` + table + ` WHERE 1 = 1`
+	var args []interface{}
+	if filter.AgentID != "" {
+		query += ` AND agent_id = ?`
+		args = append(args, filter.AgentID)
+	}
+	if filter.UserID != "" {
+		query += ` AND user_id = ?`
+		args = append(args, filter.UserID)
+	}
+	if !filter.Since.IsZero() {
+		query += ` AND ` + period + ` >= ?`
+		args = append(args, filter.Since)
+	}
+	query += ` ORDER BY ` + period + ` DESC, agent_id LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list traffic rollups: %w", err)
+	}
+	defer rows.Close()
+
+	var rollups []*TrafficRollup
+	for rows.Next() {
+		r := &TrafficRollup{}
+		err := rows.Scan(&r.PeriodStart, &r.AgentID, &r.UserID, &r.Sessions, &r.ConnectedSeconds,
+			&r.BytesSent, &r.BytesReceived)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan traffic rollup: %w", err)
+		}
+		rollups = append(rollups, r)
+	}
+
+	return rollups, nil
+}
+
+// invalidateUser drops cached lookups of a user
+func (d *Database) invalidateUser(userID string) {
+	d.users.deleteIf(func(v interface{}) bool { return v.(User).ID == userID })
+}
+
+// copyRules returns cached routing rules as new values the caller may modify
+func copyRules(cached []RoutingRule) []*RoutingRule {
+	rules := make([]*RoutingRule, len(cached))
+	for i := range cached {
+		rule := cached[i]
+		rules[i] = &rule
+	}
+	return rules
+}
+
+// splitList splits a comma separated column, nil for an empty one
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// nullString converts an empty string to a SQL NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullTime converts the zero time to a SQL NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// nullInt converts zero to a SQL NULL
+func nullInt(n int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: n, Valid: n != 0}
+}
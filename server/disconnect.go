@@ -0,0 +1,139 @@
+package server
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// endedSessionTTL is how long the reason of an ended session is kept for
+// the agent's next heartbeat or relay stream
+const endedSessionTTL = 10 * time.Minute
+
+// idleCheckInterval is how often the server looks for sessions without a
+// heartbeat within the keepalive timeout
+const idleCheckInterval = 30 * time.Second
+
+// maxReasonDetail bounds the detail kept with a disconnect reason
+const maxReasonDetail = 200
+
+// disconnectCode returns the history code of a disconnect reason, e.g.
+// agent_shutdown, empty for unspecified
+func disconnectCode(reason proto.DisconnectReason) string {
+	if reason == proto.DisconnectReason_DISCONNECT_REASON_UNSPECIFIED {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(reason.String(), "DISCONNECT_"))
+}
+
+// disconnectReasonFromCode parses a history code, unspecified for an
+// unknown or empty one
+func disconnectReasonFromCode(code string) proto.DisconnectReason {
+	if code == "" {
+		return proto.DisconnectReason_DISCONNECT_REASON_UNSPECIFIED
+	}
+	return proto.DisconnectReason(proto.DisconnectReason_value["DISCONNECT_"+strings.ToUpper(code)])
+}
+
+// recordEnd records why a session ended, the first reason wins. It is kept
+// after the session is gone so the agent learns why.
+func (s *Server) recordEnd(si *SessionInfo, reason proto.DisconnectReason, detail string) {
+	si.mu.Lock()
+	if si.endReason == proto.DisconnectReason_DISCONNECT_REASON_UNSPECIFIED {
+		si.endReason, si.endDetail = reason, detail
+	}
+	ended := &proto.SessionEnded{Reason: si.endReason, Detail: si.endDetail}
+	si.mu.Unlock()
+
+	s.ended.set(si.SessionID, ended)
+}
+
+// sessionEndedError is returned for a session that is gone, carrying the
+// reason it ended when the server still knows it
+func (s *Server) sessionEndedError(sessionID string) error {
+	value, ok := s.ended.get(sessionID)
+	if !ok {
+		return status.Errorf(codes.NotFound, "session not found")
+	}
+	ended := value.(*proto.SessionEnded)
+	st, err := status.New(codes.Aborted, "session ended: "+disconnectCode(ended.Reason)).WithDetails(ended)
+	if err != nil {
+		return status.Errorf(codes.Aborted, "session ended: %s", disconnectCode(ended.Reason))
+	}
+	return st.Err()
+}
+
+// disconnectSession ends a live session for reason, closing its relay
+// streams and storing the agent status, unless it already ended
+func (s *Server) disconnectSession(si *SessionInfo, agentStatus string, reason proto.DisconnectReason, detail string) {
+	// The agent of a handed over session is online on the other server
+	if s.movedAway(si) {
+		s.sessionMoved(si)
+		return
+	}
+	s.recordEnd(si, reason, detail)
+	if s.removeSession(si.SessionID) {
+		si.cancel()
+		s.agentOffline(si, agentStatus)
+	}
+}
+
+// disconnectUser ends the live sessions of a user whose access was revoked
+func (s *Server) disconnectUser(userID, detail string) {
+	var revoked []*SessionInfo
+	s.sessions.Range(func(key, value interface{}) bool {
+		if si := value.(*SessionInfo); si.UserID == userID {
+			revoked = append(revoked, si)
+		}
+		return true
+	})
+	for _, si := range revoked {
+		log.Printf("Disconnecting session %s of agent %s: %s", si.SessionID, si.AgentID, detail)
+		s.disconnectSession(si, "offline", proto.DisconnectReason_DISCONNECT_AUTH_REVOKED, detail)
+	}
+}
+
+// idleSessionLoop ends sessions whose agent stopped sending heartbeats
+// without closing its streams, e.g. behind a NAT that dropped the mapping
+func (s *Server) idleSessionLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.checkIdleSessions(now)
+		}
+	}
+}
+
+// checkIdleSessions ends the sessions idle for longer than the keepalive
+// timeout at now
+func (s *Server) checkIdleSessions(now time.Time) {
+	timeout := time.Duration(s.keepalive.Load().Timeout) * time.Second
+
+	var idle []*SessionInfo
+	s.sessions.Range(func(key, value interface{}) bool {
+		si := value.(*SessionInfo)
+		si.mu.RLock()
+		lastActivity := si.LastActivity
+		si.mu.RUnlock()
+		if now.Sub(lastActivity) > timeout {
+			idle = append(idle, si)
+		}
+		return true
+	})
+	for _, si := range idle {
+		detail := "no heartbeat within " + timeout.String()
+		log.Printf("Disconnecting idle session %s of agent %s: %s", si.SessionID, si.AgentID, detail)
+		s.disconnectSession(si, "offline", proto.DisconnectReason_DISCONNECT_IDLE_TIMEOUT, detail)
+	}
+}
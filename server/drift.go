@@ -0,0 +1,117 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// configDriftRetries is the number of pushes an agent may leave
+// unapplied before it counts as drifted
+const configDriftRetries = 3
+
+// configDriftEvent is the data of config.drift events
+type configDriftEvent struct {
+	AgentID           string `json:"agent_id"`
+	SessionID         string `json:"session_id"`
+	AppliedGeneration uint64 `json:"applied_generation"`
+	PushedGeneration  uint64 `json:"pushed_generation"`
+	Retries           int    `json:"retries"` // pushes retried before the agent counted as drifted
+}
+
+// configState tracks the config generation pushed to an agent against the
+// one it reports applying, guarded by the session lock
+type configState struct {
+	tracked  bool      // the agent installs server routes and reports their generation
+	applied  uint64    // generation the agent reported applying
+	pushed   uint64    // latest generation pushed to the agent
+	pushedAt time.Time // when it was pushed, or the push last retried
+	retries  int
+	drifted  bool
+}
+
+// trackConfig starts tracking the config of a new session: agents
+// announcing config generations that install server routes, clients and
+// gateways of the site mesh. They owe the snapshot of the current
+// generation.
+func (s *Server) trackConfig(si *SessionInfo) {
+	if !slices.Contains(si.capabilities, proto.CapabilityConfigGeneration) {
+		return
+	}
+	if si.Type != proto.AgentType_CLIENT && len(si.sites) == 0 {
+		return
+	}
+	si.config = configState{
+		tracked:  true,
+		pushed:   s.generation.Load(),
+		pushedAt: time.Now(),
+	}
+}
+
+// configPushed records that the agent was asked to apply a generation
+func (si *SessionInfo) configPushed(generation uint64) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	if si.config.tracked && generation > si.config.pushed {
+		si.config.pushed = generation
+		si.config.pushedAt = time.Now()
+	}
+}
+
+// checkConfigDrift records the generation an agent reported applying and
+// reports whether the push should be retried: the agent has not applied
+// the pushed generation within three heartbeat intervals. After
+// configDriftRetries retries the agent counts as drifted until it catches
+// up.
+func (s *Server) checkConfigDrift(si *SessionInfo, applied uint64) bool {
+	grace := 3 * time.Duration(s.keepalive.Load().Interval) * time.Second
+
+	si.mu.Lock()
+	c := &si.config
+	if !c.tracked {
+		si.mu.Unlock()
+		return false
+	}
+	c.applied = applied
+
+	if applied >= c.pushed {
+		recovered := c.drifted
+		c.retries, c.drifted = 0, false
+		si.mu.Unlock()
+		if recovered {
+			log.Printf("Agent %s applied config generation %d, no longer drifted", si.AgentID, applied)
+			s.alerts.configApplied(si.AgentID)
+		}
+		return false
+	}
+
+	if time.Since(c.pushedAt) < grace {
+		si.mu.Unlock()
+		return false
+	}
+	c.retries++
+	c.pushedAt = time.Now()
+	drifted := !c.drifted && c.retries >= configDriftRetries
+	c.drifted = c.drifted || drifted
+	pushed, retries := c.pushed, c.retries
+	si.mu.Unlock()
+
+	log.Printf("Agent %s has not applied config generation %d, it runs %d, pushing again", si.AgentID, pushed, applied)
+	if drifted {
+		summary := fmt.Sprintf("Agent %s drifted: config generation %d still not applied after %d retries, it runs generation %d",
+			si.AgentID, pushed, retries, applied)
+		log.Print(summary)
+		s.alerts.configDrifted(si.AgentID, summary)
+		s.webhooks.publish(EventConfigDrift, &configDriftEvent{
+			AgentID:           si.AgentID,
+			SessionID:         si.SessionID,
+			AppliedGeneration: applied,
+			PushedGeneration:  pushed,
+			Retries:           retries,
+		})
+	}
+	return true
+}
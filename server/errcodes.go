@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/taills/EasyAnyLink/common/errcode"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryErrorCodes attaches an ErrorDetail with the fitting code to error
+// statuses of unary calls without one
+func UnaryErrorCodes(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, withErrorCode(err)
+}
+
+// StreamErrorCodes attaches an ErrorDetail with the fitting code to error
+// statuses of streams without one
+func StreamErrorCodes(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return withErrorCode(handler(srv, ss))
+}
+
+// withErrorCode returns the status of err with an ErrorDetail, err if it
+// is no status or no code fits
+func withErrorCode(err error) error {
+	st, ok := status.FromError(err)
+	if err == nil || !ok {
+		return err
+	}
+	code := errcode.Of(err)
+	if code == "" {
+		return err
+	}
+	return errcode.WithCode(st, code).Err()
+}
+
+// gatewayErrorCodes attaches codes to the errors of the REST gateway,
+// whose calls bypass the interceptors, rendered in the details of the
+// JSON error
+func gatewayErrorCodes(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, withErrorCode(err))
+}
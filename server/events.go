@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"google.golang.org/grpc/peer"
+)
+
+// certCheckInterval is how often the server certificate expiry is checked
+const certCheckInterval = 12 * time.Hour
+
+// maxTrackedAuthSources bounds the memory used by auth failure tracking
+const maxTrackedAuthSources = 10000
+
+// agentEvent is the data of agent.online and agent.offline events
+type agentEvent struct {
+	AgentID   string `json:"agent_id"`
+	UserID    string `json:"user_id"`
+	Name      string `json:"name,omitempty"`
+	Type      string `json:"type"`
+	IPAddress string `json:"ip_address"`
+	SessionID string `json:"session_id"`
+}
+
+// authFailureEvent is the data of auth.failure_streak events
+type authFailureEvent struct {
+	SourceIP string `json:"source_ip"`
+	Failures int    `json:"failures"`
+	Method   string `json:"method"` // RPC that failed authentication
+}
+
+// poolExhaustedEvent is the data of pool.exhausted events
+type poolExhaustedEvent struct {
+	AgentID     string `json:"agent_id"`
+	OverlayCIDR string `json:"overlay_cidr"`
+	Allocated   int    `json:"allocated"`
+}
+
+// certExpiringEvent is the data of cert.expiring events
+type certExpiringEvent struct {
+	CertFile string    `json:"cert_file"`
+	NotAfter time.Time `json:"not_after"`
+	DaysLeft int       `json:"days_left"`
+}
+
+// authFailureTracker counts consecutive authentication failures per
+// source IP
+type authFailureTracker struct {
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// newAuthFailureTracker creates an empty auth failure tracker
+func newAuthFailureTracker() *authFailureTracker {
+	return &authFailureTracker{failures: make(map[string]int)}
+}
+
+// failed records a failure from ip and returns the length of its streak
+func (t *authFailureTracker) failed(ip string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.failures[ip]; !ok && len(t.failures) >= maxTrackedAuthSources {
+		// Forget old streaks rather than grow without bound
+		t.failures = make(map[string]int)
+	}
+	t.failures[ip]++
+	return t.failures[ip]
+}
+
+// succeeded ends the failure streak of ip
+func (t *authFailureTracker) succeeded(ip string) {
+	t.mu.Lock()
+	delete(t.failures, ip)
+	t.mu.Unlock()
+}
+
+// authFailed records an authentication failure and publishes an event
+// when the source reaches max_failed_auth consecutive failures
+func (s *Server) authFailed(ctx context.Context, method string) {
+	ip := s.clientIP(ctx)
+	failures := s.authFailures.failed(ip)
+	if failures == s.config.Security.MaxFailedAuth {
+		log.Printf("%d consecutive authentication failures from %s", failures, ip)
+		s.webhooks.publish(EventAuthFailures, &authFailureEvent{
+			SourceIP: ip,
+			Failures: failures,
+			Method:   method,
+		})
+	}
+}
+
+// authSucceeded ends the failure streak of a client
+func (s *Server) authSucceeded(ctx context.Context) {
+	s.authFailures.succeeded(s.clientIP(ctx))
+}
+
+// clientIP returns the source IP of a gRPC call
+func (s *Server) clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return remoteIP(p.Addr)
+}
+
+// agentOffline stores the status of an agent whose session has ended,
+// offline or error, and moves the session to the history
+func (s *Server) agentOffline(si *SessionInfo, status string) {
+	if err := s.db.UpdateAgentStatus(si.AgentID, status); err != nil {
+		log.Printf("Failed to update agent status: %v", err)
+	}
+	s.endSession(si)
+
+	event := &agentEvent{
+		AgentID:   si.AgentID,
+		UserID:    si.UserID,
+		Type:      strings.ToLower(si.Type.String()),
+		SessionID: si.SessionID,
+	}
+	if value, ok := s.agents.Load(si.AgentID); ok {
+		ai := value.(*AgentInfo)
+		event.IPAddress = ai.IPAddress
+		if ai.Metadata != nil {
+			event.Name = ai.Metadata.Hostname
+		}
+	}
+	s.webhooks.publish(EventAgentOffline, event)
+	s.alerts.agentOffline(si.AgentID)
+	s.alerts.configApplied(si.AgentID)
+	s.alerts.clockSynced(si.AgentID)
+}
+
+// certExpiryLoop publishes cert.expiring events while the server
+// certificate is within cert_expiry_days of expiry
+func (s *Server) certExpiryLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(certCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.checkCertExpiry()
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkCertExpiry publishes a cert.expiring event if needed
+func (s *Server) checkCertExpiry() {
+	notAfter, err := crypto.CertificateExpiry(s.config.CertFile)
+	if err != nil {
+		log.Printf("Failed to check certificate expiry: %v", err)
+		return
+	}
+
+	daysLeft := int(time.Until(notAfter).Hours() / 24)
+	if daysLeft > s.config.Security.CertExpiryDays {
+		return
+	}
+
+	log.Printf("Server certificate %s expires in %d days", s.config.CertFile, daysLeft)
+	s.webhooks.publish(EventCertExpiring, &certExpiringEvent{
+		CertFile: s.config.CertFile,
+		NotAfter: notAfter,
+		DaysLeft: daysLeft,
+	})
+}
+
+// validateWebhookEvents checks the event types of webhook subscriptions
+func validateWebhookEvents(hooks []config.Webhook) error {
+	known := map[string]bool{
+		EventAgentOnline: true, EventAgentOffline: true, EventAuthFailures: true,
+		EventPoolExhausted: true, EventCertExpiring: true, EventUsageThreshold: true,
+		EventRouteLoop: true, EventConfigDrift: true, EventClockSkew: true, EventAccessRequested: true,
+	}
+	for _, hook := range hooks {
+		for _, event := range hook.Events {
+			if !known[event] {
+				return fmt.Errorf("unknown webhook event %q for %s", event, hook.URL)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errInjectedFault fails database calls picked by the fault injection
+var errInjectedFault = errors.New("injected fault")
+
+// faults are injected into relaying, heartbeats and database calls of a
+// server built with the chaos tag, so that HA and reconnects can be tested
+// in staging. Other builds never inject any.
+var faults faultInjector
+
+// faultInjector holds the faults to inject, set through the admin API
+type faultInjector struct {
+	settings atomic.Pointer[proto.FaultInjection]
+}
+
+// current returns the faults to inject, nil for none
+func (f *faultInjector) current() *proto.FaultInjection {
+	if !chaosBuild {
+		return nil
+	}
+	return f.settings.Load()
+}
+
+// dropPacket picks relayed packets to drop
+func (f *faultInjector) dropPacket() bool {
+	fi := f.current()
+	return fi != nil && fi.RelayDropRate > 0 && rand.Float64() < fi.RelayDropRate
+}
+
+// delayHeartbeat holds up a heartbeat response until the delay passed or
+// ctx is done
+func (f *faultInjector) delayHeartbeat(ctx context.Context) {
+	fi := f.current()
+	if fi == nil || fi.HeartbeatDelayMs <= 0 {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Duration(fi.HeartbeatDelayMs) * time.Millisecond):
+	}
+}
+
+// failDB picks database calls to fail
+func (f *faultInjector) failDB() error {
+	fi := f.current()
+	if fi != nil && fi.DbFailureRate > 0 && rand.Float64() < fi.DbFailureRate {
+		return errInjectedFault
+	}
+	return nil
+}
+
+// GetFaultInjection reports the faults the server injects
+func (s *Server) GetFaultInjection(ctx context.Context, req *proto.GetFaultInjectionRequest) (*proto.FaultInjection, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+	if !chaosBuild {
+		return nil, status.Errorf(codes.Unimplemented, "server built without the chaos tag")
+	}
+	if fi := faults.current(); fi != nil {
+		return fi, nil
+	}
+	return &proto.FaultInjection{}, nil
+}
+
+// SetFaultInjection replaces the faults the server injects until it
+// restarts
+func (s *Server) SetFaultInjection(ctx context.Context, req *proto.FaultInjection) (*proto.FaultInjection, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+	if !chaosBuild {
+		return nil, status.Errorf(codes.Unimplemented, "server built without the chaos tag")
+	}
+	if req.RelayDropRate < 0 || req.RelayDropRate > 1 || req.DbFailureRate < 0 || req.DbFailureRate > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "relay_drop_rate and db_failure_rate must be between 0 and 1")
+	}
+	if req.HeartbeatDelayMs < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "heartbeat_delay_ms must not be negative")
+	}
+
+	fi := &proto.FaultInjection{
+		RelayDropRate:    req.RelayDropRate,
+		HeartbeatDelayMs: req.HeartbeatDelayMs,
+		DbFailureRate:    req.DbFailureRate,
+	}
+	faults.settings.Store(fi)
+	log.Printf("Warning: fault injection set to %.0f%% relayed packets dropped, heartbeats delayed %dms, %.0f%% database calls failed by %s",
+		fi.RelayDropRate*100, fi.HeartbeatDelayMs, fi.DbFailureRate*100, admin.Username)
+	return fi, nil
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// slowConsumerCheckInterval is how often the server looks for sessions
+// stalled past network.slow_consumer_timeout
+const slowConsumerCheckInterval = time.Second
+
+// flowStats tracks the sends on a relay stream. A send blocks while the
+// agent does not read the stream and its QUIC flow control window is full.
+type flowStats struct {
+	pending   atomic.Int32 // sends waiting for the stream or being sent
+	sendStart atomic.Int64 // Unix nanoseconds the current send started, 0 if none
+	maxStall  atomic.Int64 // longest send in nanoseconds
+	slowSends atomic.Uint64
+	stall     time.Duration // sends blocking longer count as slow
+}
+
+// sending records the start of a send and returns the function recording
+// its end
+func (f *flowStats) sending() func() {
+	start := time.Now()
+	f.sendStart.Store(start.UnixNano())
+	return func() {
+		f.sendStart.Store(0)
+		took := time.Since(start)
+		if took > f.stall {
+			f.slowSends.Add(1)
+		}
+		for {
+			longest := f.maxStall.Load()
+			if int64(took) <= longest || f.maxStall.CompareAndSwap(longest, int64(took)) {
+				return
+			}
+		}
+	}
+}
+
+// stalled returns how long the current send has blocked at now
+func (f *flowStats) stalled(now time.Time) time.Duration {
+	start := f.sendStart.Load()
+	if start == 0 {
+		return 0
+	}
+	return max(now.Sub(time.Unix(0, start)), 0)
+}
+
+// flowControl sums up the sends on the relay streams of the session at
+// now, stall being the duration a blocked send makes a slow consumer
+func (si *SessionInfo) flowControl(now time.Time, stall time.Duration) *proto.FlowControl {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	fc := &proto.FlowControl{}
+	var stalled time.Duration
+	for _, rs := range si.streams {
+		fc.QueueDepth += rs.flow.pending.Load()
+		stalled = max(stalled, rs.flow.stalled(now))
+		fc.MaxStallMs = max(fc.MaxStallMs, time.Duration(rs.flow.maxStall.Load()).Milliseconds())
+		fc.SlowSends += rs.flow.slowSends.Load()
+	}
+	fc.StalledMs = stalled.Milliseconds()
+	fc.MaxStallMs = max(fc.MaxStallMs, fc.StalledMs)
+	fc.SlowConsumer = stalled > stall
+	return fc
+}
+
+// slowConsumerStall is the duration a blocked send makes a slow consumer
+func (s *Server) slowConsumerStall() time.Duration {
+	return time.Duration(s.config.Network.SlowConsumerStall) * time.Millisecond
+}
+
+// slowConsumers returns the sessions with a send blocked past
+// network.slow_consumer_stall at now
+func (s *Server) slowConsumers(now time.Time) []*proto.SlowConsumer {
+	stall := s.slowConsumerStall()
+	var slow []*proto.SlowConsumer
+	s.sessions.Range(func(key, value interface{}) bool {
+		si := value.(*SessionInfo)
+		if fc := si.flowControl(now, stall); fc.SlowConsumer {
+			slow = append(slow, &proto.SlowConsumer{AgentId: si.AgentID, SessionId: si.SessionID, FlowControl: fc})
+		}
+		return true
+	})
+	return slow
+}
+
+// slowConsumerLoop ends sessions whose agents stopped reading their relay
+// streams. Their blocked sends hold up relay workers and the packets
+// queued behind them.
+func (s *Server) slowConsumerLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(slowConsumerCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.checkSlowConsumers(now)
+		}
+	}
+}
+
+// checkSlowConsumers ends the sessions with a send blocked longer than
+// network.slow_consumer_timeout at now
+func (s *Server) checkSlowConsumers(now time.Time) {
+	timeout := time.Duration(s.config.Network.SlowConsumerTimeout) * time.Second
+	for _, slow := range s.slowConsumers(now) {
+		if time.Duration(slow.FlowControl.StalledMs)*time.Millisecond <= timeout {
+			continue
+		}
+		value, ok := s.sessions.Load(slow.SessionId)
+		if !ok {
+			continue
+		}
+		detail := fmt.Sprintf("relay stream not read for %s, %d packets pending", timeout, slow.FlowControl.QueueDepth)
+		log.Printf("Disconnecting slow consumer session %s of agent %s: %s", slow.SessionId, slow.AgentId, detail)
+		s.slowEnded.Add(1)
+		s.disconnectSession(value.(*SessionInfo), "offline", proto.DisconnectReason_DISCONNECT_SLOW_CONSUMER, detail)
+	}
+}
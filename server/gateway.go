@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// GatewayHandler returns the REST gateway: the read-only agent methods and
+// the admin API of the current API version as JSON over HTTP, mapped by
+// gateway.yaml next to the protos, the OpenAPI spec at /openapi.json and
+// SCIM user provisioning under /scim/v2. Calls run in-process, admins pass
+// their API key in the X-Api-Key header, or as bearer token to SCIM.
+func (s *Server) GatewayHandler(ctx context.Context) (http.Handler, error) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(gatewayHeader), runtime.WithErrorHandler(gatewayErrorCodes))
+	if err := proto.RegisterAgentServiceHandlerServer(ctx, mux, s); err != nil {
+		return nil, err
+	}
+	if err := proto.RegisterAdminServiceHandlerServer(ctx, mux, s); err != nil {
+		return nil, err
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(proto.OpenAPISpec)
+	})
+	handler.Handle(scimBasePath+"/", gatewayPeer(s.scimHandler()))
+	handler.Handle("/", gatewayPeer(mux))
+
+	return s.gatewayCORS(handler), nil
+}
+
+// WithGRPC serves native gRPC calls on the gateway's port next to the REST
+// gateway. It is the TCP fallback of agents that cannot use QUIC, e.g.
+// behind a proxy that only passes TCP.
+func WithGRPC(grpcServer *grpc.Server, gateway http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		gateway.ServeHTTP(w, r)
+	})
+}
+
+// gatewayHeader forwards the admin API key next to the headers the
+// gateway passes on by default
+func gatewayHeader(key string) (string, bool) {
+	if strings.EqualFold(key, AdminAPIKeyHeader) {
+		return AdminAPIKeyHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// gatewayPeer attaches the HTTP client address to requests as the gRPC
+// peer, so failed authentications are counted per source IP like those
+// over gRPC
+func gatewayPeer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if addr, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+			ctx := peer.NewContext(r.Context(), &peer.Peer{Addr: net.TCPAddrFromAddrPort(addr)})
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// gatewayCORS lets the configured browser origins call the gateway,
+// answering their preflight requests
+func (s *Server) gatewayCORS(next http.Handler) http.Handler {
+	origins := s.config.Gateway.AllowedOrigins
+	if len(origins) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!slices.Contains(origins, "*") && !slices.Contains(origins, origin)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Add("Vary", "Origin")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+			h.Set("Access-Control-Allow-Headers", "Content-Type, X-Api-Key")
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
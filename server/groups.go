@@ -0,0 +1,278 @@
+package server
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"strings"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxGroupName bounds the length of agent group names
+const maxGroupName = 64
+
+// managedConfig computes the settings pushed to an agent from its group,
+// nil for an agent without a group
+func (s *Server) managedConfig(agent *Agent) *proto.ManagedConfig {
+	if agent.GroupID == 0 {
+		return nil
+	}
+	group, err := s.db.GetAgentGroup(agent.GroupID)
+	if err != nil {
+		log.Printf("Failed to get group %d of agent %s: %v", agent.GroupID, agent.ID, err)
+		return nil
+	}
+	return &proto.ManagedConfig{
+		Group:          group.Name,
+		DnsServers:     group.DNSServers,
+		DnsSearch:      group.DNSSearch,
+		BandwidthLimit: int32(group.BandwidthLimit),
+	}
+}
+
+// applyManagedConfig enforces the settings of an agent's group on its live
+// session
+func (si *SessionInfo) applyManagedConfig(mc *proto.ManagedConfig) {
+	if mc == nil || mc.BandwidthLimit <= 0 {
+		si.bandwidth.Store(nil)
+		return
+	}
+	rate := float64(mc.BandwidthLimit) * 1024
+	// Allow one second of traffic at once, and always a full packet
+	si.bandwidth.Store(newTokenBucket(rate, int(math.Max(rate, 65535))))
+}
+
+// allowBandwidth accounts n bytes sent by the agent against the bandwidth
+// limit of its group, reporting whether the packet may be relayed
+func (si *SessionInfo) allowBandwidth(n int) bool {
+	bucket := si.bandwidth.Load()
+	if bucket == nil {
+		return true
+	}
+	ok, _ := bucket.takeN(float64(n))
+	return ok
+}
+
+// refreshManagedConfig reapplies the group settings of a connected agent
+// and has it fetch them together with its routes
+func (s *Server) refreshManagedConfig(agentID string) {
+	si := s.findSessionByAgent(agentID)
+	if si == nil {
+		return
+	}
+	agent, err := s.db.GetAgentByID(agentID)
+	if err != nil {
+		log.Printf("Failed to refresh group settings of agent %s: %v", agentID, err)
+		return
+	}
+	si.applyManagedConfig(s.managedConfig(agent))
+	// A new group can move the agent in or out of a rollout's canary
+	si.rollout.Store(nil)
+	s.notifyRouteChange(agentID)
+}
+
+// connectedMembers returns the connected agents of a group
+func (s *Server) connectedMembers(groupID int) []string {
+	var members []string
+	s.sessions.Range(func(key, value interface{}) bool {
+		agentID := value.(*SessionInfo).AgentID
+		if agent, err := s.db.GetAgentByID(agentID); err == nil && agent.GroupID == groupID {
+			members = append(members, agentID)
+		}
+		return true
+	})
+	return members
+}
+
+// refreshGroup pushes changed settings or rules of a group to its
+// connected agents
+func (s *Server) refreshGroup(groupID int) {
+	for _, agentID := range s.connectedMembers(groupID) {
+		s.refreshManagedConfig(agentID)
+	}
+}
+
+// ListAgentGroups returns all agent groups
+func (s *Server) ListAgentGroups(ctx context.Context, req *proto.ListAgentGroupsRequest) (*proto.ListAgentGroupsResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.db.ListAgentGroups()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list agent groups: %v", err)
+	}
+	resp := &proto.ListAgentGroupsResponse{Groups: make([]*proto.AgentGroup, 0, len(groups))}
+	for _, group := range groups {
+		resp.Groups = append(resp.Groups, agentGroupToProto(group))
+	}
+	return resp, nil
+}
+
+// CreateAgentGroup creates an agent group
+func (s *Server) CreateAgentGroup(ctx context.Context, req *proto.AgentGroup) (*proto.AgentGroup, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := validateAgentGroup(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.CreateAgentGroup(group); err != nil {
+		if isDuplicateKey(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "agent group %s already exists", group.Name)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create agent group: %v", err)
+	}
+
+	log.Printf("Agent group %s (%d) created by %s", group.Name, group.ID, admin.Username)
+	return agentGroupToProto(group), nil
+}
+
+// UpdateAgentGroup replaces the settings of an agent group and pushes them
+// to its connected agents
+func (s *Server) UpdateAgentGroup(ctx context.Context, req *proto.AgentGroup) (*proto.AgentGroup, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.GetAgentGroup(int(req.GroupId)); err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent group %d not found", req.GroupId)
+	}
+	group, err := validateAgentGroup(req)
+	if err != nil {
+		return nil, err
+	}
+	group.ID = int(req.GroupId)
+
+	if err := s.db.UpdateAgentGroup(group); err != nil {
+		if isDuplicateKey(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "agent group %s already exists", group.Name)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update agent group: %v", err)
+	}
+
+	s.refreshGroup(group.ID)
+	log.Printf("Agent group %s (%d) updated by %s", group.Name, group.ID, admin.Username)
+
+	updated, err := s.db.GetAgentGroup(group.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get agent group: %v", err)
+	}
+	return agentGroupToProto(updated), nil
+}
+
+// DeleteAgentGroup deletes an agent group with its routing rules, its
+// agents keep only their own settings
+func (s *Server) DeleteAgentGroup(ctx context.Context, req *proto.DeleteAgentGroupRequest) (*proto.DeleteAgentGroupResponse, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.db.GetAgentGroup(int(req.GroupId))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent group %d not found", req.GroupId)
+	}
+
+	// Collect the connected members before they leave the group
+	members := s.connectedMembers(group.ID)
+	if err := s.db.DeleteAgentGroup(group.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete agent group: %v", err)
+	}
+	for _, agentID := range members {
+		s.refreshManagedConfig(agentID)
+	}
+
+	log.Printf("Agent group %s (%d) deleted by %s", group.Name, group.ID, admin.Username)
+	return &proto.DeleteAgentGroupResponse{Deleted: true}, nil
+}
+
+// SetAgentGroup moves an agent into a group, or out of its group with
+// group_id 0. A connected agent applies the new settings at once.
+func (s *Server) SetAgentGroup(ctx context.Context, req *proto.SetAgentGroupRequest) (*proto.AgentDetail, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.db.GetAgentByID(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s not found", req.AgentId)
+	}
+	groupName := "no group"
+	if req.GroupId != 0 {
+		group, err := s.db.GetAgentGroup(int(req.GroupId))
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "agent group %d not found", req.GroupId)
+		}
+		groupName = "group " + group.Name
+	}
+
+	if err := s.db.SetAgentGroup(agent.ID, int(req.GroupId)); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set agent group: %v", err)
+	}
+	s.refreshManagedConfig(agent.ID)
+	log.Printf("Agent %s moved to %s by %s", agent.ID, groupName, admin.Username)
+
+	agent, err = s.db.GetAgentByID(agent.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get agent: %v", err)
+	}
+	return s.agentDetail(agent), nil
+}
+
+// validateAgentGroup checks a proto agent group and converts it to a
+// database record
+func validateAgentGroup(pg *proto.AgentGroup) (*AgentGroup, error) {
+	group := &AgentGroup{
+		Name:           strings.TrimSpace(pg.Name),
+		Description:    strings.TrimSpace(pg.Description),
+		BandwidthLimit: int(pg.BandwidthLimit),
+	}
+	if group.Name == "" || len(group.Name) > maxGroupName {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be 1 to %d characters", maxGroupName)
+	}
+	if group.BandwidthLimit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "bandwidth_limit must not be negative")
+	}
+
+	for _, server := range pg.DnsServers {
+		ip := net.ParseIP(strings.TrimSpace(server))
+		if ip == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid DNS server %q", server)
+		}
+		group.DNSServers = append(group.DNSServers, ip.String())
+	}
+	for _, domain := range pg.DnsSearch {
+		domain = strings.TrimSpace(domain)
+		if domain == "" || strings.ContainsAny(domain, ", \t") {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid DNS search domain %q", domain)
+		}
+		group.DNSSearch = append(group.DNSSearch, domain)
+	}
+	if len(group.DNSSearch) > 0 && len(group.DNSServers) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "dns_search requires dns_servers")
+	}
+	return group, nil
+}
+
+// agentGroupToProto converts a database agent group to proto format
+func agentGroupToProto(group *AgentGroup) *proto.AgentGroup {
+	return &proto.AgentGroup{
+		GroupId:        int32(group.ID),
+		Name:           group.Name,
+		Description:    group.Description,
+		DnsServers:     group.DNSServers,
+		DnsSearch:      group.DNSSearch,
+		BandwidthLimit: int32(group.BandwidthLimit),
+		AgentCount:     int32(group.AgentCount),
+	}
+}
@@ -2,43 +2,131 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"log"
+	"net/netip"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/taills/EasyAnyLink/common/config"
-	"github.com/taills/EasyAnyLink/common/proto"
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// agentLockStripes is the number of locks serializing registrations
+	agentLockStripes = 64
+
+	// registrationReplyTTL is how long a registration can be retried with
+	// the same request ID
+	registrationReplyTTL = 5 * time.Minute
+
+	// maxRequestIDLength bounds the idempotency keys kept for retries
+	maxRequestIDLength = 64
 )
 
 // Server represents the gRPC server
 type Server struct {
 	proto.UnimplementedAgentServiceServer
+	proto.UnimplementedAdminServiceServer
 
-	config   *config.ServerConfig
-	db       *Database
-	ipPool   *IPPool
-	sessions sync.Map // sessionID -> *SessionInfo
-	agents   sync.Map // agentID -> *AgentInfo
+	config        *config.ServerConfig
+	db            *Database
+	ipPool        *IPPool
+	sessions      sync.Map      // sessionID -> *SessionInfo
+	agents        sync.Map      // agentID -> *AgentInfo
+	routeUpdates  sync.Map      // agentID -> struct{}, pending route refresh
+	generation    atomic.Uint64 // config generation, grows with every route or group settings change
+	tracer        *relayTracer
+	relay         *relayPool
+	registrations *tokenBucket // nil if registrations are not rate limited
+	admission     admissionControl
+	handshakes    func() crypto.HandshakeStats
+	quotas        *quotaTracker
+	webhooks      *webhookDispatcher // nil if no webhooks are configured
+	authFailures  *authFailureTracker
+	alerts        *alerter // nil if alerting is disabled
+	agentLocks    [agentLockStripes]sync.Mutex
+	replies       *ttlCache    // agentID/requestID -> registrationReply
+	acls          *ttlCache    // userID -> []*aclMatcher
+	routes        *ttlCache    // agentID -> []*forwardRoute
+	nonces        *expirySet   // registration nonces seen within the allowed clock skew
+	challenges    *expirySet   // agentID/challenge of identity challenges not yet used
+	challengeRate *tokenBucket // identity challenges issued, limited even without a registration rate
+	ended         *ttlCache    // sessionID -> *proto.SessionEnded of recently ended sessions
+	trustBundle   []byte       // PEM CA certificates served to new agents, nil if not configured
+	keepalive     atomic.Pointer[proto.KeepaliveResponse]
+	sites         siteMesh
+	multicast     multicastCounters
+	slowEnded     atomic.Uint64 // sessions ended for stalling past network.slow_consumer_timeout
+	loops         *loopDetector
+	rollouts      *rolloutTracker
+	ha            *haNode // nil without HA
+	done          chan struct{}
+	wg            sync.WaitGroup
 }
 
 // SessionInfo holds information about an active session
 type SessionInfo struct {
 	SessionID     string
 	AgentID       string
+	UserID        string
 	Type          proto.AgentType
-	Stream        proto.AgentService_RelayDataServer
+	streams       []*relayStream // one per TUN queue of the agent
 	Created       time.Time
 	LastActivity  time.Time
 	BytesSent     uint64
 	BytesReceived uint64
+	Stats         *proto.AgentStats // latest stats reported by heartbeat
+	ctx           context.Context   // done when the session is terminated by the server
+	cancel        context.CancelFunc
 	mu            sync.RWMutex
+
+	Health *proto.AgentHealth // failing subsystems reported by heartbeat
+
+	endReason proto.DisconnectReason // why the session ended, unspecified while it is live
+	endDetail string
+
+	connectionID  string    // connection the session is stored with, another server's once it continued the session
+	handoffServer string    // server the session is handed over to, empty if it is not
+	handoffUntil  time.Time // end of the handoff, the agent is told to move until then
+
+	bandwidth atomic.Pointer[tokenBucket] // per-agent limit of the agent's group, nil for unlimited
+
+	apiVersion   int      // API version the agent registered with
+	capabilities []string // optional features the agent announced
+	mtu          int      // MTU granted to the agent, larger packets are dropped
+
+	sites []netip.Prefix // LANs of a gateway accepted into the site mesh
+	ready atomic.Bool    // the agent takes relayed traffic, see readyGateway
+
+	ip        netip.Addr   // overlay IP of the agent
+	multicast *tokenBucket // broadcast and multicast packets the session may relay, nil when they are dropped
+
+	config configState    // config generations pushed to and applied by the agent
+	clock  clockSkewState // clock skew the agent measured
+
+	rollout atomic.Pointer[rolloutMembership] // side of the staged rollout the agent is on, nil until placed
+
+	resources sessionResources // goroutines and packet buffers held, see sessionResources
+
+	posture atomic.Pointer[postureState] // device posture reported with the metadata
 }
 
 // AgentInfo holds cached agent information
@@ -60,45 +148,318 @@ func NewServer(cfg *config.ServerConfig, db *Database) (*Server, error) {
 		return nil, fmt.Errorf("failed to create IP pool: %w", err)
 	}
 
+	if chaosBuild {
+		log.Println("Warning: built with the chaos tag, the admin API can inject faults")
+	}
+
+	if err := validateWebhookEvents(cfg.Webhooks); err != nil {
+		return nil, err
+	}
+	webhooks := newWebhookDispatcher(append(billingWebhook(cfg.Billing), cfg.Webhooks...))
+
 	server := &Server{
-		config: cfg,
-		db:     db,
-		ipPool: ipPool,
+		config:        cfg,
+		db:            db,
+		ipPool:        ipPool,
+		tracer:        newRelayTracer(cfg.Debug.TraceBufferSize, cfg.Debug.TraceSampleRate),
+		relay:         newRelayPool(cfg.Network.RelayWorkers, cfg.Network.RelayQueueLen),
+		quotas:        newQuotaTracker(db, newUsageNotifier(cfg.Billing, webhooks)),
+		webhooks:      webhooks,
+		authFailures:  newAuthFailureTracker(),
+		alerts:        newAlerter(cfg.Alerts),
+		replies:       newTTLCache(registrationReplyTTL),
+		acls:          newTTLCache(aclCacheTTL),
+		routes:        newTTLCache(routeCacheTTL),
+		nonces:        newExpirySet(2 * time.Duration(cfg.Security.MaxClockSkew) * time.Second),
+		challenges:    newExpirySet(identityChallengeTTL),
+		challengeRate: newTokenBucket(identityChallengeRate, identityChallengeRate),
+		ended:         newTTLCache(endedSessionTTL),
+		loops:         newLoopDetector(cfg.Network.LoopThreshold),
+		rollouts:      newRolloutTracker(),
+		done:          make(chan struct{}),
+	}
+
+	if cfg.CAFile != "" {
+		bundle, _, err := crypto.LoadTrustBundle(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		server.trustBundle = bundle
+	}
+
+	// Generations keep growing across restarts, agents that reconnect
+	// after one never see a snapshot older than the one they applied
+	server.generation.Store(uint64(time.Now().UnixNano()))
+
+	server.keepalive.Store(&proto.KeepaliveResponse{
+		Interval: int32(cfg.Network.KeepaliveInterval),
+		Timeout:  int32(cfg.Network.KeepaliveTimeout),
+	})
+
+	if rate := cfg.Security.RegistrationsPerSecond; rate > 0 {
+		server.registrations = newTokenBucket(rate, cfg.Security.RegistrationBurst)
+	}
+
+	for _, queue := range server.relay.queues {
+		server.wg.Add(1)
+		go server.relayWorker(queue)
+	}
+
+	if webhooks != nil && cfg.CertFile != "" {
+		server.wg.Add(1)
+		go server.certExpiryLoop()
+	}
+
+	if server.alerts != nil {
+		server.wg.Add(1)
+		go server.alertLoop()
+	}
+
+	server.wg.Add(1)
+	go server.windowLoop()
+
+	server.wg.Add(1)
+	go server.idleSessionLoop()
+
+	if cfg.Network.SlowConsumerTimeout > 0 {
+		server.wg.Add(1)
+		go server.slowConsumerLoop()
+	}
+
+	if cfg.Database.HistoryDays > 0 {
+		server.wg.Add(1)
+		go server.historyPurgeLoop()
+	}
+
+	server.wg.Add(1)
+	go server.statsRollupLoop()
+
+	if cfg.HA.Listen != "" {
+		if server.ha, err = newHANode(server, cfg.HA); err != nil {
+			server.Close()
+			return nil, err
+		}
+		server.wg.Add(1)
+		go server.ha.run()
+	}
+
+	// Under systemd with WatchdogSec, restart the server when it hangs
+	if interval := watchdogInterval(); interval > 0 {
+		server.wg.Add(1)
+		go server.watchdogLoop(interval)
 	}
 
 	return server, nil
 }
 
+// Close stops background work, persists pending usage counters and
+// delivers queued webhooks
+func (s *Server) Close() {
+	close(s.done)
+	s.wg.Wait()
+	s.quotas.stop()
+	s.webhooks.stop()
+}
+
 // Register handles agent registration
 func (s *Server) Register(ctx context.Context, req *proto.RegisterRequest) (*proto.RegisterResponse, error) {
 	log.Printf("Registration request from agent %s, type: %s", req.AgentId, req.Type)
 
+	// Protect the database from registration floods
+	if s.registrations != nil {
+		if ok, retryAfter := s.registrations.take(); !ok {
+			return nil, resourceExhausted(ctx, retryAfter, "registration rate limit exceeded")
+		}
+	}
+
+	// Only the active server of an HA pair takes agents
+	if !s.ha.isActive() {
+		return nil, errcode.Status(codes.FailedPrecondition, errcode.ServerStandby, "server is on standby")
+	}
+
+	if len(req.RequestId) > maxRequestIDLength {
+		return nil, errcode.Status(codes.InvalidArgument, errcode.InvalidArgument,
+			"request_id longer than %d characters", maxRequestIDLength)
+	}
+
 	// Validate protocol version
+	api := apiVersion(ctx)
 	if !s.isProtocolCompatible(req.ProtocolVersion) {
 		return &proto.RegisterResponse{
 			Accepted:                false,
 			ErrorMessage:            "Incompatible protocol version",
-			ServerVersion:           "1.0.0",
-			MinimumSupportedVersion: "1.0.0",
+			ErrorCode:               string(errcode.Incompatible),
+			ServerVersion:           protocolVersion(api),
+			MinimumSupportedVersion: protocolVersionV1,
 		}, nil
 	}
 
+	// Reject registrations altered or replayed by a TLS interception box
+	if psk := s.config.Security.PSK; psk != "" {
+		maxSkew := time.Duration(s.config.Security.MaxClockSkew) * time.Second
+		if err := crypto.VerifyRegistration(psk, req, maxSkew); err != nil {
+			log.Printf("Registration of agent %s rejected: %v", req.AgentId, err)
+			// An agent that holds the key only needs the server time to
+			// sign its next attempt right
+			var skew *crypto.ClockSkewError
+			if errors.As(err, &skew) {
+				msg := "registration timestamp is off the server clock"
+				st := status.New(codes.Unauthenticated, msg)
+				if withTime, err := st.WithDetails(timestamppb.Now()); err == nil {
+					st = withTime
+				}
+				return nil, errcode.WithCode(st, errcode.ClockSkew).Err()
+			}
+			s.authFailed(ctx, "Register")
+			return nil, status.Errorf(codes.Unauthenticated, "authentication failed")
+		}
+		added, retryAfter := s.nonces.add(string(req.Nonce))
+		if retryAfter > 0 {
+			// Forgetting nonces would let replays through, so refuse
+			return nil, resourceExhausted(ctx, retryAfter, "too many signed registrations, retry later")
+		}
+		if !added {
+			log.Printf("Registration of agent %s rejected: replayed nonce", req.AgentId)
+			s.authFailed(ctx, "Register")
+			return nil, errcode.Status(codes.Unauthenticated, errcode.ReplayedRequest, "authentication failed")
+		}
+	}
+
+	// Prove the agent holds the private half of its hardware identity key
+	if err := s.verifyIdentity(ctx, req); err != nil {
+		return nil, err
+	}
+
 	// Authenticate user
 	user, err := s.db.GetUserByAPIKey(req.UserKey)
 	if err != nil {
 		log.Printf("Authentication failed for user key: %v", err)
+		s.authFailed(ctx, "Register")
 		return nil, status.Errorf(codes.Unauthenticated, "authentication failed")
 	}
+	s.authSucceeded(ctx)
+
+	// Apply the user's relay quotas
+	if _, err := s.quotas.load(user); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load user quota: %v", err)
+	}
+	if s.quotas.exceeded(user.ID) {
+		return nil, resourceExhausted(ctx, untilNextMonth(), "monthly transfer cap of %d bytes reached", user.TransferCap)
+	}
+
+	// Serialize registrations of the same agent
+	unlock := s.lockAgent(req.AgentId)
+	defer unlock()
+
+	// Answer a retried registration with the session it created. A request
+	// ID reused for a different registration is a client bug, answering it
+	// with the first one's session would hide it.
+	replyKey := req.AgentId + "/" + req.RequestId
+	digest := registrationDigest(req)
+	if req.RequestId != "" {
+		if cached, ok := s.replies.get(replyKey); ok {
+			reply := cached.(registrationReply)
+			if reply.digest != digest {
+				log.Printf("Agent %s reused request ID %s for a different registration", req.AgentId, req.RequestId)
+				return nil, errcode.Status(codes.AlreadyExists, errcode.Conflict,
+					"request_id %s was used for a different registration", req.RequestId)
+			}
+			if _, active := s.sessions.Load(reply.resp.SessionId); active && reply.userID == user.ID {
+				log.Printf("Agent %s retried registration %s, reusing session %s",
+					req.AgentId, req.RequestId, reply.resp.SessionId)
+				resp := protobuf.Clone(reply.resp).(*proto.RegisterResponse)
+				resp.ServerTime = timestamppb.Now()
+				return resp, nil
+			}
+		}
+	}
+
+	// Turn agents away while the server is full rather than degrade the
+	// connected ones
+	release, err := s.admitSession(ctx, req.AgentId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	// Get or create agent
+	created := false
 	agent, err := s.db.GetAgentByID(req.AgentId)
+	if err == nil && !agent.DeletedAt.IsZero() {
+		return nil, errcode.Status(codes.PermissionDenied, errcode.AgentArchived, "agent %s is archived", req.AgentId)
+	}
+	if err == nil && agent.Pending {
+		return nil, errcode.Status(codes.PermissionDenied, errcode.PendingApproval, "agent %s is pending approval", req.AgentId)
+	}
+
+	// An agent bound to a hardware key only registers with it, so a copied
+	// user key and agent ID are not enough
+	bound := ""
+	if err == nil {
+		bound = agent.IdentityKey
+	}
+	if err := s.checkIdentity(ctx, bound, req); err != nil {
+		return nil, err
+	}
+	identityKey := ""
+	if len(req.IdentityKey) > 0 {
+		identityKey = base64.StdEncoding.EncodeToString(req.IdentityKey)
+	}
+	if err == nil && bound == "" && identityKey != "" {
+		if err := s.db.SetAgentIdentity(agent.ID, identityKey, req.IdentityProvider); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to bind agent identity: %v", err)
+		}
+		log.Printf("Agent %s bound to its %s identity key %s", agent.ID, req.IdentityProvider,
+			crypto.IdentityFingerprint(req.IdentityKey))
+	}
 	if err != nil {
+		// Enforce the per-user agent limit before creating a new agent
+		max := s.config.Security.MaxAgentsPerUser
+		if user.MaxAgents > 0 {
+			max = user.MaxAgents
+		}
+		if max > 0 {
+			count, err := s.db.CountAgentsByUser(user.ID)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to count agents: %v", err)
+			}
+			if count >= max {
+				return nil, resourceExhausted(ctx, 0, "user has reached the limit of %d agents", max)
+			}
+		}
+
 		// Create new agent
 		metadata, _ := json.Marshal(req.Metadata)
 
+		// Hold new devices until an admin approves them
+		if s.config.Security.RequireApproval {
+			agent = &Agent{
+				ID:                     req.AgentId,
+				UserID:                 user.ID,
+				Name:                   req.Metadata.Hostname,
+				Type:                   req.Type.String(),
+				Status:                 "offline",
+				CertificateFingerprint: req.CertificateFingerprint,
+				Metadata:               string(metadata),
+				Pending:                true,
+				IdentityKey:            identityKey,
+				IdentityProvider:       req.IdentityProvider,
+			}
+			if err := s.db.CreateAgent(agent); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to create agent: %v", err)
+			}
+			log.Printf("Agent %s of user %s is pending approval", agent.ID, user.Username)
+			return nil, errcode.Status(codes.PermissionDenied, errcode.PendingApproval, "agent %s is pending approval", req.AgentId)
+		}
+
 		// Allocate IP address
 		ip, err := s.ipPool.Allocate(req.AgentId)
 		if err != nil {
+			s.webhooks.publish(EventPoolExhausted, &poolExhaustedEvent{
+				AgentID:     req.AgentId,
+				OverlayCIDR: s.config.Network.OverlayCIDR,
+				Allocated:   s.ipPool.AllocatedCount(),
+			})
 			return nil, status.Errorf(codes.ResourceExhausted, "failed to allocate IP: %v", err)
 		}
 
@@ -112,21 +473,23 @@ func (s *Server) Register(ctx context.Context, req *proto.RegisterRequest) (*pro
 			BandwidthLimit:         int(req.Bandwidth),
 			CertificateFingerprint: req.CertificateFingerprint,
 			Metadata:               string(metadata),
+			IdentityKey:            identityKey,
+			IdentityProvider:       req.IdentityProvider,
 		}
-
-		if err := s.db.CreateAgent(agent); err != nil {
-			s.ipPool.Release(req.AgentId)
-			return nil, status.Errorf(codes.Internal, "failed to create agent: %v", err)
-		}
-	} else {
-		// Update existing agent status
-		if err := s.db.UpdateAgentStatus(agent.ID, "online"); err != nil {
-			log.Printf("Failed to update agent status: %v", err)
-		}
+		created = true
 	}
 
-	// Create session
+	// Create session, or continue the one the agent had on the failed
+	// server of an HA pair or on the server that handed it over
 	sessionID := uuid.New().String()
+	resumed := s.ha.resume(req.ResumeSessionId, agent.ID, user.ID)
+	if resumed != nil {
+		sessionID = resumed.SessionID
+		log.Printf("Agent %s resumed session %s after the HA takeover", agent.ID, sessionID)
+	} else if resumed = s.takeHandoff(req.ResumeSessionId, agent.ID, user.ID); resumed != nil {
+		sessionID = resumed.SessionID
+		log.Printf("Agent %s continued session %s handed over by another server", agent.ID, sessionID)
+	}
 	connectionID := fmt.Sprintf("%s-%d", req.AgentId, time.Now().Unix())
 
 	session := &Session{
@@ -135,10 +498,49 @@ func (s *Server) Register(ctx context.Context, req *proto.RegisterRequest) (*pro
 		ConnectionID: connectionID,
 	}
 
-	if err := s.db.CreateSession(session); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create session: %v", err)
+	// Store the agent and its session atomically
+	if err := s.db.RegisterAgent(agent, created, session); err != nil {
+		if created {
+			s.ipPool.Release(req.AgentId)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to register agent: %v", err)
 	}
 
+	now := time.Now()
+	sessionCtx, cancel := context.WithCancel(context.Background())
+	si := &SessionInfo{
+		SessionID:    sessionID,
+		AgentID:      agent.ID,
+		UserID:       user.ID,
+		Type:         req.Type,
+		Created:      now,
+		LastActivity: now,
+		ctx:          sessionCtx,
+		cancel:       cancel,
+		connectionID: connectionID,
+		apiVersion:   api,
+		capabilities: req.Capabilities,
+		mtu:          s.sessionMTU(req),
+		multicast:    s.multicastLimit(),
+	}
+	if resumed != nil {
+		si.Created, si.BytesSent, si.BytesReceived = resumed.Created, resumed.BytesSent, resumed.BytesReceived
+	}
+	si.ip, _ = netip.ParseAddr(agent.IPAddress)
+	si.resources.limit = int64(s.config.Network.MaxSessionMemoryKB) << 10
+	s.updatePosture(si, req.Metadata)
+	// Gateways announcing readiness take traffic once they report READY
+	si.ready.Store(req.Type != proto.AgentType_GATEWAY || !slices.Contains(req.Capabilities, proto.CapabilityGatewayReady))
+	managed := s.managedConfig(agent)
+	si.applyManagedConfig(managed)
+	// The agent fetches a full snapshot after registering, it replaces the
+	// updates missed while disconnected
+	s.routeUpdates.Delete(agent.ID)
+	s.joinSiteMesh(si, req.SiteSubnets)
+	s.trackConfig(si)
+	s.sessionStored()
+	s.sessions.Store(sessionID, si)
+
 	// Cache agent info
 	s.agents.Store(agent.ID, &AgentInfo{
 		AgentID:   agent.ID,
@@ -153,53 +555,193 @@ func (s *Server) Register(ctx context.Context, req *proto.RegisterRequest) (*pro
 	log.Printf("Agent %s registered successfully, IP: %s, Session: %s",
 		agent.ID, agent.IPAddress, sessionID)
 
-	return &proto.RegisterResponse{
+	s.alerts.agentOnline(agent.ID)
+	s.webhooks.publish(EventAgentOnline, &agentEvent{
+		AgentID:   agent.ID,
+		UserID:    user.ID,
+		Name:      agent.Name,
+		Type:      strings.ToLower(req.Type.String()),
+		IPAddress: agent.IPAddress,
+		SessionID: sessionID,
+	})
+
+	keepalive := s.keepalive.Load()
+	resp := &proto.RegisterResponse{
 		Accepted:                true,
 		SessionId:               sessionID,
 		AssignedIp:              agent.IPAddress,
-		ServerVersion:           "1.0.0",
-		MinimumSupportedVersion: "1.0.0",
+		ServerVersion:           protocolVersion(api),
+		MinimumSupportedVersion: protocolVersionV1,
 		ServerConfig: &proto.ServerConfig{
 			GatewayIp:         s.config.Network.GatewayIP,
-			Mtu:               int32(s.config.Network.MTU),
-			KeepaliveInterval: int32(s.config.Network.KeepaliveInterval),
-			KeepaliveTimeout:  int32(s.config.Network.KeepaliveTimeout),
+			Mtu:               int32(si.mtu),
+			KeepaliveInterval: keepalive.Interval,
+			KeepaliveTimeout:  keepalive.Timeout,
+			MaxMessageSize:    int32(s.config.GRPC.MaxRecvMsgSize),
+			RekeyInterval:     int32(s.config.Security.RekeyInterval * 60),
+			RekeyBytes:        s.config.Security.RekeyBytes,
 		},
-	}, nil
+		ManagedConfig: managed,
+		Capabilities:  serverCapabilities,
+		ServerTime:    timestamppb.Now(),
+		Servers:       s.config.Network.AlternateServers,
+	}
+	if req.RequestId != "" {
+		s.replies.set(replyKey, registrationReply{userID: user.ID, digest: digest, resp: resp})
+	}
+
+	return resp, nil
+}
+
+// registrationReply is a successful registration kept for retries
+type registrationReply struct {
+	userID string
+	digest [sha256.Size]byte // of the request, see registrationDigest
+	resp   *proto.RegisterResponse
+}
+
+// registrationDigest hashes what a registration asks for, leaving out the
+// signature fields fresh on every attempt and the metadata the agent
+// collects anew, so retries of one registration hash alike
+func registrationDigest(req *proto.RegisterRequest) [sha256.Size]byte {
+	asked := protobuf.Clone(req).(*proto.RegisterRequest)
+	asked.Nonce, asked.Timestamp, asked.Mac, asked.Metadata = nil, 0, nil, nil
+	asked.IdentityChallenge, asked.IdentitySignature = nil, nil
+	data, _ := protobuf.MarshalOptions{Deterministic: true}.Marshal(asked)
+	return sha256.Sum256(data)
+}
+
+// minMTU is the smallest MTU granted, the IPv4 minimum datagram size
+const minMTU = 576
+
+// sessionMTU returns the MTU granted to a registering agent: the one it
+// asked for up to network.max_mtu, or network.mtu if it asked for none,
+// so that relayed packets fit the largest message the agent accepts
+func (s *Server) sessionMTU(req *proto.RegisterRequest) int {
+	mtu := s.config.Network.MTU
+	if req.Mtu > 0 {
+		mtu = min(int(req.Mtu), s.config.Network.MaxMTU)
+	}
+	if req.MaxMessageSize > 0 {
+		mtu = min(mtu, int(req.MaxMessageSize)-proto.DataPacketOverhead)
+	}
+	return max(mtu, minMTU)
+}
+
+// lockAgent serializes registrations of an agent and returns the unlock
+// function
+func (s *Server) lockAgent(agentID string) func() {
+	h := fnv.New32a()
+	h.Write([]byte(agentID))
+	mu := &s.agentLocks[h.Sum32()%agentLockStripes]
+	mu.Lock()
+	return mu.Unlock
 }
 
 // Heartbeat handles agent heartbeat messages
 func (s *Server) Heartbeat(stream proto.AgentService_HeartbeatServer) error {
+	// The stream serves the session it last beat for
+	var beating *SessionInfo
+	done := func() {}
+	defer func() { done() }()
+
 	for {
 		req, err := stream.Recv()
 		if err != nil {
 			return err
 		}
 
-		// Update session activity
-		if sessionInfo, ok := s.sessions.Load(req.SessionId); ok {
-			si := sessionInfo.(*SessionInfo)
-			si.mu.Lock()
-			si.LastActivity = time.Now()
-			if req.Stats != nil {
-				si.BytesSent = req.Stats.BytesSent
-				si.BytesReceived = req.Stats.BytesReceived
-			}
-			si.mu.Unlock()
+		// Send response, with the keepalive settings in case an admin
+		// changed them
+		keepalive := s.keepalive.Load()
+		resp := &proto.HeartbeatResponse{
+			Alive:             true,
+			Timestamp:         timestamppb.Now(),
+			KeepaliveInterval: keepalive.Interval,
+			KeepaliveTimeout:  keepalive.Timeout,
 		}
 
-		// Send response
-		resp := &proto.HeartbeatResponse{
-			Alive:     true,
-			Timestamp: req.Timestamp,
+		// Update session activity. A session that ended or was terminated
+		// ends the heartbeat so the agent reconnects.
+		sessionInfo, ok := s.sessions.Load(req.SessionId)
+		if !ok {
+			return s.sessionEndedError(req.SessionId)
+		}
+		si := sessionInfo.(*SessionInfo)
+		if si != beating {
+			done()
+			beating, done = si, si.resources.spawned()
+		}
+		si.mu.Lock()
+		si.LastActivity = time.Now()
+		if req.Stats != nil {
+			si.BytesSent = req.Stats.BytesSent
+			si.BytesReceived = req.Stats.BytesReceived
+			si.Stats = req.Stats
+		}
+		logHealthChange(si.AgentID, si.Health, req.Health)
+		si.Health = req.Health
+		si.mu.Unlock()
+
+		if req.Metadata != nil {
+			s.updateMetadata(si.AgentID, req.Metadata)
+			s.updatePosture(si, req.Metadata)
+		}
+		if req.Sites != nil {
+			s.updateSiteMesh(si, req.Sites.Subnets)
+		}
+
+		// Tell the agent to re-fetch routes after rule changes, and again
+		// while it has not applied them
+		_, pending := s.routeUpdates.LoadAndDelete(si.AgentID)
+		retry := s.checkConfigDrift(si, req.ConfigGeneration)
+		s.checkClockSkew(si, req.ClockSkewMs)
+		if pending || retry {
+			resp.ShouldRefreshRoutes = true
 		}
+		resp.HandoffServer = si.handoffTarget()
 
+		faults.delayHeartbeat(stream.Context())
 		if err := stream.Send(resp); err != nil {
 			return err
 		}
 	}
 }
 
+// logHealthChange logs an agent becoming degraded or recovering
+func logHealthChange(agentID string, previous, current *proto.AgentHealth) {
+	switch {
+	case current.GetDegraded() && !previous.GetDegraded():
+		for _, sh := range current.Subsystems {
+			if sh.State == "degraded" {
+				log.Printf("Agent %s degraded: subsystem %s keeps failing: %s", agentID, sh.Name, sh.LastError)
+			}
+		}
+	case previous.GetDegraded() && !current.GetDegraded():
+		log.Printf("Agent %s recovered from degraded state", agentID)
+	}
+}
+
+// updateMetadata stores metadata an agent refreshed with a heartbeat, such
+// as its interfaces and default route after a network change
+func (s *Server) updateMetadata(agentID string, metadata *proto.AgentMetadata) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return
+	}
+	if err := s.db.UpdateAgentMetadata(agentID, string(data)); err != nil {
+		log.Printf("Failed to update metadata of agent %s: %v", agentID, err)
+		return
+	}
+
+	// Cached agent info is read without locks, replace it instead
+	if agentInfo, ok := s.agents.Load(agentID); ok {
+		ai := *agentInfo.(*AgentInfo)
+		ai.Metadata = metadata
+		s.agents.Store(agentID, &ai)
+	}
+}
+
 // RelayData handles data packet relay between agents
 func (s *Server) RelayData(stream proto.AgentService_RelayDataServer) error {
 	// Get session from first packet
@@ -211,25 +753,49 @@ func (s *Server) RelayData(stream proto.AgentService_RelayDataServer) error {
 	sessionID := firstPacket.SessionId
 	sessionInfo, ok := s.sessions.Load(sessionID)
 	if !ok {
-		return status.Errorf(codes.NotFound, "session not found")
+		return s.sessionEndedError(sessionID)
 	}
 
 	si := sessionInfo.(*SessionInfo)
-	si.Stream = stream
+	defer si.resources.spawned()()
 
-	// Register session stream
-	s.sessions.Store(sessionID, si)
+	// Register session stream, multi-queue agents open several
+	rs := si.addStream(stream, s.slowConsumerStall())
 
 	log.Printf("Data relay started for session %s, agent %s", sessionID, si.AgentID)
 
-	// Handle incoming packets
+	// Receive in the background so that a terminated session ends the
+	// stream even while the agent is idle
+	errc := make(chan error, 1)
+	go func() {
+		defer si.resources.spawned()()
+		errc <- s.receivePackets(si, rs, stream)
+	}()
+
+	select {
+	case err = <-errc:
+	case <-si.ctx.Done():
+		err = s.sessionEndedError(sessionID)
+	}
+
+	log.Printf("Stream ended for session %s: %v", sessionID, err)
+	if si.removeStream(rs) == 0 && si.ctx.Err() == nil {
+		s.disconnectSession(si, "offline", proto.DisconnectReason_DISCONNECT_TRANSPORT_ERROR, err.Error())
+	}
+	return err
+}
+
+// receivePackets relays the packets received on one stream of a session
+// until the stream fails or the session is terminated
+func (s *Server) receivePackets(si *SessionInfo, rs *relayStream, stream proto.AgentService_RelayDataServer) error {
 	for {
 		packet, err := stream.Recv()
 		if err != nil {
-			log.Printf("Stream ended for session %s: %v", sessionID, err)
-			s.sessions.Delete(sessionID)
 			return err
 		}
+		if si.ctx.Err() != nil {
+			return si.ctx.Err()
+		}
 
 		// Update statistics
 		si.mu.Lock()
@@ -237,74 +803,142 @@ func (s *Server) RelayData(stream proto.AgentService_RelayDataServer) error {
 		si.LastActivity = time.Now()
 		si.mu.Unlock()
 
-		// Route packet to destination
-		if err := s.routePacket(packet); err != nil {
-			log.Printf("Failed to route packet: %v", err)
+		// Drop packets over the group's bandwidth limit or the user's
+		// bandwidth or transfer quota
+		if !si.allowBandwidth(len(packet.Payload)) {
+			continue
+		}
+		if err := s.quotas.allowSend(si.UserID, len(packet.Payload)); err != nil {
+			continue
+		}
+		if faults.dropPacket() {
+			continue
+		}
+
+		// Route packet to destination on a relay worker, unless the session
+		// holds too many packets already
+		if !si.resources.hold(len(packet.Payload)) {
+			continue
+		}
+		if err := s.relay.submit(relayJob{si: si, rs: rs, dp: packet}); err != nil {
+			si.resources.release(len(packet.Payload))
+			s.alerts.relayResult(err)
 		}
 	}
 }
 
+// terminateSession ends a live session of an agent that is being removed:
+// its relay streams are closed so the agent stops relaying before its
+// overlay IP can be reused
+func (s *Server) terminateSession(si *SessionInfo, reason proto.DisconnectReason, detail string) {
+	s.recordEnd(si, reason, detail)
+	s.removeSession(si.SessionID)
+	si.cancel()
+	s.endSession(si)
+}
+
 // GetRoutes handles routing configuration requests
 func (s *Server) GetRoutes(ctx context.Context, req *proto.RouteRequest) (*proto.RouteResponse, error) {
+	// Read before the rules, the snapshot holds at least this generation
+	generation := s.generation.Load()
+
 	// Get routing rules from database
 	rules, err := s.db.GetRoutingRulesByAgentID(req.AgentId)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get routing rules: %v", err)
 	}
+	agent, agentErr := s.db.GetAgentByID(req.AgentId)
+	if agentErr == nil {
+		rules = s.canaryRoutingRules(agent, rules)
+	}
+
+	var si *SessionInfo
+	if value, ok := s.sessions.Load(req.SessionId); ok && value.(*SessionInfo).AgentID == req.AgentId {
+		si = value.(*SessionInfo)
+	}
 
-	// Convert to proto format
+	// Convert to proto format, leaving out rules outside their access
+	// window and those of posture policies the agent fails
+	now := time.Now()
+	var posture *postureState
+	if si != nil {
+		posture = si.posture.Load()
+	}
 	protoRules := make([]*proto.RoutingRule, 0, len(rules))
 	for _, rule := range rules {
-		protoRule := &proto.RoutingRule{
-			RuleId:      int32(rule.ID),
-			Destination: rule.Destination,
-			GatewayId:   rule.GatewayID,
-			Priority:    int32(rule.Priority),
-			Enabled:     rule.Enabled,
-		}
-
-		switch rule.Action {
-		case "forward":
-			protoRule.Action = proto.RouteAction_FORWARD
-		case "direct":
-			protoRule.Action = proto.RouteAction_DIRECT
-		case "deny":
-			protoRule.Action = proto.RouteAction_DENY
+		if !rule.Window.activeAt(now) || !posture.meets(rule.Posture) {
+			continue
 		}
+		protoRules = append(protoRules, routingRuleToProto(rule))
+	}
 
-		protoRules = append(protoRules, protoRule)
+	// Gateways of a site mesh reach the sites of the others
+	if si != nil {
+		protoRules = append(protoRules, s.sites.routes(si.UserID, si.AgentID)...)
 	}
 
-	return &proto.RouteResponse{
-		Rules: protoRules,
-	}, nil
+	resp := &proto.RouteResponse{Rules: protoRules, Generation: generation}
+	if agentErr == nil {
+		resp.ManagedConfig = s.managedConfig(agent)
+	}
+	return resp, nil
 }
 
-// UpdateStatus handles agent status updates
+// UpdateStatus handles agent lifecycle notifications. An agent that shuts
+// down or hits a fatal error ends its session at once, instead of leaving
+// it to its streams to time out, and the reason is kept in the history.
 func (s *Server) UpdateStatus(ctx context.Context, req *proto.StatusUpdate) (*proto.StatusResponse, error) {
-	// Update agent status in database
+	value, ok := s.sessions.Load(req.SessionId)
+	if !ok {
+		return nil, errcode.Status(codes.NotFound, errcode.SessionNotFound, "session not found")
+	}
+	si := value.(*SessionInfo)
+	if si.AgentID != req.AgentId {
+		return nil, status.Errorf(codes.PermissionDenied, "session does not belong to agent %s", req.AgentId)
+	}
+
+	if req.Status == proto.AgentStatus_READY {
+		if si.Type != proto.AgentType_GATEWAY {
+			return nil, status.Errorf(codes.InvalidArgument, "only gateways report readiness")
+		}
+		if !si.ready.Swap(true) {
+			log.Printf("Gateway %s is ready, routing traffic to it", si.AgentID)
+		}
+		return &proto.StatusResponse{Acknowledged: true, Message: "Gateway ready"}, nil
+	}
+
 	var statusStr string
+	var reason proto.DisconnectReason
 	switch req.Status {
 	case proto.AgentStatus_ONLINE:
 		statusStr = "online"
 	case proto.AgentStatus_OFFLINE:
-		statusStr = "offline"
+		statusStr, reason = "offline", proto.DisconnectReason_DISCONNECT_AGENT_SHUTDOWN
 	case proto.AgentStatus_ERROR:
-		statusStr = "error"
+		statusStr, reason = "error", proto.DisconnectReason_DISCONNECT_AGENT_ERROR
 	default:
 		return nil, status.Errorf(codes.InvalidArgument, "invalid status")
 	}
 
-	if err := s.db.UpdateAgentStatus(req.AgentId, statusStr); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to update status: %v", err)
+	if reason == proto.DisconnectReason_DISCONNECT_REASON_UNSPECIFIED {
+		if err := s.db.UpdateAgentStatus(req.AgentId, statusStr); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to update status: %v", err)
+		}
+	} else {
+		detail := req.Message
+		if len(detail) > maxReasonDetail {
+			detail = strings.ToValidUTF8(detail[:maxReasonDetail], "")
+		}
+		log.Printf("Agent %s ended session %s: %s: %s", si.AgentID, si.SessionID, disconnectCode(reason), detail)
+		s.disconnectSession(si, statusStr, reason, detail)
 	}
 
-	// Update cached agent info
+	// Cached agent info is read without locks, replace it instead
 	if agentInfo, ok := s.agents.Load(req.AgentId); ok {
-		ai := agentInfo.(*AgentInfo)
+		ai := *agentInfo.(*AgentInfo)
 		ai.Status = req.Status
 		ai.LastSeen = time.Now()
-		s.agents.Store(req.AgentId, ai)
+		s.agents.Store(req.AgentId, &ai)
 	}
 
 	return &proto.StatusResponse{
@@ -313,8 +947,43 @@ func (s *Server) UpdateStatus(ctx context.Context, req *proto.StatusUpdate) (*pr
 	}, nil
 }
 
-// routePacket routes a packet to the destination agent
-func (s *Server) routePacket(packet *proto.DataPacket) error {
+// notifyRouteChange starts a new config generation and flags an agent to
+// refresh its routes on the next heartbeat
+func (s *Server) notifyRouteChange(agentID string) {
+	generation := s.generation.Add(1)
+	s.routes.delete(agentID)
+	s.routeUpdates.Store(agentID, struct{}{})
+	if si := s.findSessionByAgent(agentID); si != nil {
+		si.configPushed(generation)
+	}
+}
+
+// routingRuleToProto converts a database routing rule to proto format
+func routingRuleToProto(rule *RoutingRule) *proto.RoutingRule {
+	protoRule := &proto.RoutingRule{
+		RuleId:      int32(rule.ID),
+		Destination: rule.Destination,
+		GatewayId:   rule.GatewayID,
+		Priority:    int32(rule.Priority),
+		Enabled:     rule.Enabled,
+		Window:      windowToProto(rule.Window),
+		Posture:     rule.Posture,
+	}
+
+	switch rule.Action {
+	case "forward":
+		protoRule.Action = proto.RouteAction_FORWARD
+	case "direct":
+		protoRule.Action = proto.RouteAction_DIRECT
+	case "deny":
+		protoRule.Action = proto.RouteAction_DENY
+	}
+
+	return protoRule
+}
+
+// routePacket routes a packet to the destination agent and returns its ID
+func (s *Server) routePacket(packet *proto.DataPacket) (string, error) {
 	// Find destination session
 	var destSession *SessionInfo
 
@@ -333,7 +1002,7 @@ func (s *Server) routePacket(packet *proto.DataPacket) error {
 		// Find any online gateway
 		s.sessions.Range(func(key, value interface{}) bool {
 			si := value.(*SessionInfo)
-			if si.Type == proto.AgentType_GATEWAY {
+			if si.Type == proto.AgentType_GATEWAY && si.ready.Load() {
 				destSession = si
 				return false
 			}
@@ -342,11 +1011,21 @@ func (s *Server) routePacket(packet *proto.DataPacket) error {
 	}
 
 	if destSession == nil {
-		return fmt.Errorf("no route to destination")
+		return "", errNoRoute
+	}
+	return destSession.AgentID, s.deliver(destSession, packet)
+}
+
+// deliver sends a packet to a session and counts it as received by the
+// session's agent
+func (s *Server) deliver(destSession *SessionInfo, packet *proto.DataPacket) error {
+	rs := destSession.pickStream(packet.Payload)
+	if rs == nil {
+		return fmt.Errorf("destination has no relay stream: %w", errNoRoute)
 	}
 
 	// Send packet to destination
-	if err := destSession.Stream.Send(packet); err != nil {
+	if err := rs.Send(packet); err != nil {
 		return fmt.Errorf("failed to send packet: %w", err)
 	}
 
@@ -354,16 +1033,11 @@ func (s *Server) routePacket(packet *proto.DataPacket) error {
 	destSession.mu.Lock()
 	destSession.BytesSent += uint64(len(packet.Payload))
 	destSession.mu.Unlock()
+	s.quotas.addReceived(destSession.UserID, len(packet.Payload))
 
 	return nil
 }
 
-// isProtocolCompatible checks if the client protocol version is compatible
-func (s *Server) isProtocolCompatible(version string) bool {
-	// Simple version check - in production, use proper semver comparison
-	return version == "1.0.0"
-}
-
 // GetClientIP extracts client IP from gRPC context
 func (s *Server) GetClientIP(ctx context.Context) string {
 	p, ok := peer.FromContext(ctx)
@@ -0,0 +1,483 @@
+package server
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+const (
+	// haALPN is the TLS protocol of the replication link between the
+	// servers of an HA pair
+	haALPN = "easyanylink-ha"
+
+	// haDialTimeout bounds connecting and authenticating to the peer
+	haDialTimeout = 5 * time.Second
+
+	// haResumeTimeout is how long the sessions of a failed peer can be
+	// resumed after the takeover; the rest are recorded as ended
+	haResumeTimeout = 2 * time.Minute
+)
+
+// haAdvert is what each server of an HA pair sends the other every
+// interval. The active one adds its sessions and IP allocations, the
+// state the standby continues with when it takes over.
+type haAdvert struct {
+	ID          string            `json:"id"`       // random per process, breaks priority ties
+	Priority    int               `json:"priority"` // 0 when the server is shutting down
+	Active      bool              `json:"active"`
+	Sessions    []*haSession      `json:"sessions,omitempty"`
+	Allocations map[string]string `json:"allocations,omitempty"` // agent ID -> overlay IP
+}
+
+// haSession is a live session replicated to the standby
+type haSession struct {
+	SessionID     string    `json:"session_id"`
+	AgentID       string    `json:"agent_id"`
+	UserID        string    `json:"user_id"`
+	Created       time.Time `json:"created"`
+	BytesSent     uint64    `json:"bytes_sent"`
+	BytesReceived uint64    `json:"bytes_received"`
+}
+
+// haNode runs one server of an active/standby pair sharing a virtual IP.
+// Only the active server accepts registrations. Which one is active is
+// decided VRRP-style from the adverts of the peer, or in keepalived mode
+// by which one holds the virtual IP.
+type haNode struct {
+	server   *Server
+	cfg      config.HAConfig
+	vip      netip.Prefix
+	id       string
+	listener net.Listener
+	tls      *tls.Config // client side of the link, authenticated by haProof
+	started  time.Time
+	active   atomic.Bool
+
+	mu         sync.Mutex
+	peer       *haAdvert // last advert of the peer, nil before the first
+	peerSeen   time.Time
+	replicated []*haSession          // sessions of the peer when it was last active
+	resumable  map[string]*haSession // sessions of the failed peer by ID
+	takenOver  time.Time
+}
+
+// newHANode starts listening for the peer's adverts. The server starts
+// on standby and takes over when the peer stays silent.
+func newHANode(s *Server, cfg config.HAConfig) (*haNode, error) {
+	vip, err := netip.ParsePrefix(cfg.VirtualIP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ha virtual_ip: %w", err)
+	}
+	if cfg.Mode == "arp" {
+		if err := checkVirtualIPSupport(); err != nil {
+			return nil, err
+		}
+	}
+
+	serverTLS, err := crypto.LoadServerTLSConfig(s.config.CertFile, s.config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	serverTLS.NextProtos = []string{haALPN}
+	listener, err := tls.Listen("tcp", cfg.Listen, serverTLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the ha peer: %w", err)
+	}
+
+	// The peer's certificate is usually issued for the virtual IP's name,
+	// not the address it is dialed at; both ends prove the shared secret
+	// bound to the TLS session instead
+	clientTLS, err := crypto.LoadClientTLSConfig("", true)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	clientTLS.NextProtos = []string{haALPN}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	h := &haNode{
+		server:    s,
+		cfg:       cfg,
+		vip:       vip,
+		id:        hex.EncodeToString(id),
+		listener:  listener,
+		tls:       clientTLS,
+		started:   time.Now(),
+		resumable: make(map[string]*haSession),
+	}
+	log.Printf("HA enabled in %s mode, virtual IP %s, starting on standby", cfg.Mode, cfg.VirtualIP)
+	return h, nil
+}
+
+// isActive reports whether the server accepts registrations, always
+// without HA
+func (h *haNode) isActive() bool {
+	return h == nil || h.active.Load()
+}
+
+// run serves the peer's connections and sends adverts until the server
+// stops
+func (h *haNode) run() {
+	defer h.server.wg.Done()
+
+	go func() {
+		<-h.server.done
+		h.listener.Close()
+	}()
+	go h.acceptLoop()
+
+	interval := time.Duration(h.cfg.Interval) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var conn net.Conn
+	for {
+		select {
+		case <-h.server.done:
+			// Let the standby take over at once rather than after dead_time
+			if conn != nil {
+				h.send(conn, &haAdvert{ID: h.id})
+				conn.Close()
+			}
+			if h.active.Load() && h.cfg.Mode == "arp" {
+				if err := releaseVirtualIP(h.cfg.Interface, h.vip); err != nil {
+					log.Printf("Failed to release the virtual IP: %v", err)
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+
+		h.elect()
+		h.expireResumable()
+
+		if conn == nil {
+			var err error
+			if conn, err = h.dial(); err != nil {
+				continue
+			}
+		}
+		if err := h.send(conn, h.advert()); err != nil {
+			log.Printf("Lost the HA link to %s: %v", h.cfg.Peer, err)
+			conn.Close()
+			conn = nil
+		}
+	}
+}
+
+// advert returns this server's advert, carrying the replicated state
+// while it is active
+func (h *haNode) advert() *haAdvert {
+	advert := &haAdvert{ID: h.id, Priority: h.cfg.Priority, Active: h.active.Load()}
+	if !advert.Active {
+		return advert
+	}
+
+	h.server.sessions.Range(func(key, value interface{}) bool {
+		si := value.(*SessionInfo)
+		si.mu.RLock()
+		advert.Sessions = append(advert.Sessions, &haSession{
+			SessionID:     si.SessionID,
+			AgentID:       si.AgentID,
+			UserID:        si.UserID,
+			Created:       si.Created,
+			BytesSent:     si.BytesSent,
+			BytesReceived: si.BytesReceived,
+		})
+		si.mu.RUnlock()
+		return true
+	})
+	allocations := h.server.ipPool.Allocations()
+	advert.Allocations = make(map[string]string, len(allocations))
+	for agentID, ip := range allocations {
+		advert.Allocations[agentID] = ip.String()
+	}
+	return advert
+}
+
+// dial connects to the peer and authenticates the link
+func (h *haNode) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: haDialTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", h.cfg.Peer, h.tls)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(haDialTimeout))
+	reader := bufio.NewReader(conn)
+	if err := h.verifyProof(conn, reader, "server"); err != nil {
+		conn.Close()
+		log.Printf("HA peer %s failed authentication: %v", h.cfg.Peer, err)
+		return nil, err
+	}
+	if err := h.sendProof(conn, "client"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	log.Printf("HA link to %s established", h.cfg.Peer)
+	return conn, nil
+}
+
+// send writes an advert as a JSON line
+func (h *haNode) send(conn net.Conn, advert *haAdvert) error {
+	data, err := json.Marshal(advert)
+	if err != nil {
+		return err
+	}
+	conn.SetWriteDeadline(time.Now().Add(time.Duration(h.cfg.DeadTime) * time.Second))
+	_, err = conn.Write(append(data, '\n'))
+	return err
+}
+
+// acceptLoop serves the links the peer opens
+func (h *haNode) acceptLoop() {
+	for {
+		conn, err := h.listener.Accept()
+		if err != nil {
+			return
+		}
+		go h.serveLink(conn.(*tls.Conn))
+	}
+}
+
+// serveLink authenticates a link from the peer and reads its adverts
+func (h *haNode) serveLink(conn *tls.Conn) {
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(haDialTimeout))
+	if err := conn.Handshake(); err != nil {
+		return
+	}
+	reader := bufio.NewReader(conn)
+	if err := h.sendProof(conn, "server"); err != nil {
+		return
+	}
+	if err := h.verifyProof(conn, reader, "client"); err != nil {
+		log.Printf("HA link from %s failed authentication: %v", conn.RemoteAddr(), err)
+		return
+	}
+
+	// An advert is due every interval, a silent link is dead
+	deadTime := time.Duration(h.cfg.DeadTime) * time.Second
+	for {
+		conn.SetReadDeadline(time.Now().Add(deadTime))
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var advert haAdvert
+		if err := json.Unmarshal(line, &advert); err != nil {
+			log.Printf("Invalid advert from the HA peer: %v", err)
+			return
+		}
+		h.received(&advert)
+	}
+}
+
+// haProof proves knowing the shared secret for one end of a TLS session.
+// Bound to the session's exported keying material, a proof relayed by a
+// man in the middle does not verify.
+func (h *haNode) haProof(conn *tls.Conn, role string) (string, error) {
+	state := conn.ConnectionState()
+	material, err := state.ExportKeyingMaterial("easyanylink ha "+role, nil, 32)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, []byte(h.cfg.Secret))
+	mac.Write(material)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// sendProof writes the proof of this end
+func (h *haNode) sendProof(conn net.Conn, role string) error {
+	proof, err := h.haProof(conn.(*tls.Conn), role)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write([]byte(proof + "\n"))
+	return err
+}
+
+// verifyProof reads and checks the proof of the other end
+func (h *haNode) verifyProof(conn net.Conn, reader *bufio.Reader, role string) error {
+	want, err := h.haProof(conn.(*tls.Conn), role)
+	if err != nil {
+		return err
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(line[:len(line)-1]), []byte(want)) {
+		return fmt.Errorf("wrong ha secret")
+	}
+	return nil
+}
+
+// received stores the peer's advert for the next election. The IP
+// allocations of an active peer are taken over right away, so that the
+// standby never assigns an address in use.
+func (h *haNode) received(advert *haAdvert) {
+	h.mu.Lock()
+	h.peer, h.peerSeen = advert, time.Now()
+	if advert.Active {
+		h.replicated = advert.Sessions
+	}
+	h.mu.Unlock()
+
+	for agentID, addr := range advert.Allocations {
+		if ip := net.ParseIP(addr); ip != nil {
+			if _, err := h.server.ipPool.GetAllocated(agentID); err != nil {
+				h.server.ipPool.AllocateSpecific(agentID, ip)
+			}
+		}
+	}
+}
+
+// elect decides whether this server is active. The active one stays
+// active while the peer is alive; priority, then the process ID, settle
+// a tie when both are on standby or both became active during a
+// partition.
+func (h *haNode) elect() {
+	if h.cfg.Mode == "keepalived" {
+		h.setActive(ownsAddr(h.vip.Addr()))
+		return
+	}
+
+	h.mu.Lock()
+	peer, seen := h.peer, h.peerSeen
+	h.mu.Unlock()
+
+	deadTime := time.Duration(h.cfg.DeadTime) * time.Second
+	switch {
+	case peer == nil:
+		// Give the peer dead_time to be heard from after starting
+		if time.Since(h.started) >= deadTime {
+			h.setActive(true)
+		}
+	case time.Since(seen) >= deadTime || peer.Priority == 0:
+		h.setActive(true)
+	case peer.Active && !h.active.Load():
+	case peer.Active || !h.active.Load():
+		h.setActive(h.cfg.Priority > peer.Priority || (h.cfg.Priority == peer.Priority && h.id > peer.ID))
+	}
+}
+
+// setActive takes over or hands back the virtual IP
+func (h *haNode) setActive(active bool) {
+	if h.active.Load() == active {
+		return
+	}
+
+	if !active {
+		log.Printf("HA peer %s is active, going on standby", h.cfg.Peer)
+		h.active.Store(false)
+		if h.cfg.Mode == "arp" {
+			if err := releaseVirtualIP(h.cfg.Interface, h.vip); err != nil {
+				log.Printf("Failed to release the virtual IP: %v", err)
+			}
+		}
+		// Agents that stayed reconnect to the active server
+		var sessions []*SessionInfo
+		h.server.sessions.Range(func(key, value interface{}) bool {
+			sessions = append(sessions, value.(*SessionInfo))
+			return true
+		})
+		for _, si := range sessions {
+			h.server.terminateSession(si, proto.DisconnectReason_DISCONNECT_SERVER_DECISION, "server went on standby")
+		}
+		return
+	}
+
+	if h.cfg.Mode == "arp" {
+		if err := takeVirtualIP(h.cfg.Interface, h.vip); err != nil {
+			log.Printf("Failed to take over the virtual IP: %v", err)
+			return
+		}
+	}
+
+	h.mu.Lock()
+	for _, rs := range h.replicated {
+		h.resumable[rs.SessionID] = rs
+	}
+	h.replicated = nil
+	h.takenOver = time.Now()
+	n := len(h.resumable)
+	h.mu.Unlock()
+
+	h.active.Store(true)
+	log.Printf("Taking over as the active HA server with virtual IP %s, %d sessions can resume", h.vip, n)
+}
+
+// resume returns the replicated session an agent continues after a
+// takeover, nil if there is none
+func (h *haNode) resume(sessionID, agentID, userID string) *haSession {
+	if h == nil || sessionID == "" {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rs, ok := h.resumable[sessionID]
+	if !ok || rs.AgentID != agentID || rs.UserID != userID {
+		return nil
+	}
+	delete(h.resumable, sessionID)
+	return rs
+}
+
+// expireResumable records the sessions of the failed peer that were not
+// resumed in time as ended
+func (h *haNode) expireResumable() {
+	h.mu.Lock()
+	if len(h.resumable) == 0 || time.Since(h.takenOver) < haResumeTimeout {
+		h.mu.Unlock()
+		return
+	}
+	expired := h.resumable
+	h.resumable = make(map[string]*haSession)
+	h.mu.Unlock()
+
+	code := disconnectCode(proto.DisconnectReason_DISCONNECT_TRANSPORT_ERROR)
+	for _, rs := range expired {
+		if err := h.server.db.EndSession(rs.SessionID, rs.BytesSent, rs.BytesReceived, code, "server failover"); err != nil {
+			log.Printf("Failed to record history of session %s: %v", rs.SessionID, err)
+		}
+	}
+	log.Printf("%d sessions of the failed HA peer were not resumed", len(expired))
+}
+
+// ownsAddr reports whether addr is assigned to a local interface
+func ownsAddr(addr netip.Addr) bool {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, a := range addrs {
+		if ipNet, ok := a.(*net.IPNet); ok {
+			if ip, ok := netip.AddrFromSlice(ipNet.IP); ok && ip.Unmap() == addr {
+				return true
+			}
+		}
+	}
+	return false
+}
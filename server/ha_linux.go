@@ -0,0 +1,104 @@
+//go:build linux
+
+package server
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// garpCount is how many gratuitous ARPs announce a taken over virtual IP
+const garpCount = 3
+
+// checkVirtualIPSupport reports whether the server can move the virtual IP
+func checkVirtualIPSupport() error {
+	return nil
+}
+
+// takeVirtualIP adds the virtual IP to iface and announces it, so that
+// neighbours send its traffic to this server
+func takeVirtualIP(iface string, vip netip.Prefix) error {
+	if !ownsAddr(vip.Addr()) {
+		if out, err := exec.Command("ip", "addr", "add", vip.String(), "dev", iface).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to add %s to %s: %s", vip, iface, strings.TrimSpace(string(out)))
+		}
+	}
+	// Linux announces IPv6 addresses itself with unsolicited neighbour
+	// advertisements
+	if vip.Addr().Is4() {
+		if err := sendGratuitousARP(iface, vip.Addr()); err != nil {
+			log.Printf("Failed to announce the virtual IP: %v", err)
+		}
+	}
+	return nil
+}
+
+// releaseVirtualIP removes the virtual IP from iface
+func releaseVirtualIP(iface string, vip netip.Prefix) error {
+	if !ownsAddr(vip.Addr()) {
+		return nil
+	}
+	if out, err := exec.Command("ip", "addr", "del", vip.String(), "dev", iface).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove %s from %s: %s", vip, iface, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// sendGratuitousARP broadcasts ARP requests for addr from iface, updating
+// the ARP caches of the neighbours and the switches' forwarding tables
+func sendGratuitousARP(iface string, addr netip.Addr) error {
+	ifi, err := net.InterfaceByName(iface)
+	if err != nil {
+		return err
+	}
+	if len(ifi.HardwareAddr) != 6 {
+		return fmt.Errorf("%s is not an Ethernet interface", iface)
+	}
+
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW, int(htons(unix.ETH_P_ARP)))
+	if err != nil {
+		return fmt.Errorf("failed to open packet socket: %w", err)
+	}
+	defer unix.Close(fd)
+
+	broadcast := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	ip := addr.As4()
+
+	// Ethernet header, then an ARP request whose sender and target are
+	// both the virtual IP
+	frame := make([]byte, 0, 42)
+	frame = append(frame, broadcast...)
+	frame = append(frame, ifi.HardwareAddr...)
+	frame = append(frame, 0x08, 0x06)             // ARP
+	frame = append(frame, 0x00, 0x01, 0x08, 0x00) // Ethernet, IPv4
+	frame = append(frame, 6, 4, 0x00, 0x01)       // address lengths, request
+	frame = append(frame, ifi.HardwareAddr...)
+	frame = append(frame, ip[:]...)
+	frame = append(frame, 0, 0, 0, 0, 0, 0)
+	frame = append(frame, ip[:]...)
+
+	to := &unix.SockaddrLinklayer{
+		Protocol: htons(unix.ETH_P_ARP),
+		Ifindex:  ifi.Index,
+		Halen:    6,
+	}
+	copy(to.Addr[:], broadcast)
+	for i := 0; i < garpCount; i++ {
+		if err := unix.Sendto(fd, frame, 0, to); err != nil {
+			return fmt.Errorf("failed to send gratuitous ARP: %w", err)
+		}
+	}
+	return nil
+}
+
+// htons converts a 16-bit value to network byte order
+func htons(v uint16) uint16 {
+	return binary.NativeEndian.Uint16(binary.BigEndian.AppendUint16(nil, v))
+}
@@ -0,0 +1,26 @@
+//go:build !linux
+
+package server
+
+import (
+	"errors"
+	"net/netip"
+)
+
+// errNoVirtualIP is returned where the server cannot move the virtual IP
+var errNoVirtualIP = errors.New("ha mode 'arp' requires Linux, use 'keepalived' mode")
+
+// checkVirtualIPSupport reports whether the server can move the virtual IP
+func checkVirtualIPSupport() error {
+	return errNoVirtualIP
+}
+
+// takeVirtualIP is only supported on Linux
+func takeVirtualIP(iface string, vip netip.Prefix) error {
+	return errNoVirtualIP
+}
+
+// releaseVirtualIP is only supported on Linux
+func releaseVirtualIP(iface string, vip netip.Prefix) error {
+	return errNoVirtualIP
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net"
+	"slices"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// handoffTimeout is how long a session handed over to another server
+// can be continued there. An agent that did not move by then keeps its
+// session here.
+const handoffTimeout = time.Minute
+
+// HandOffSessions moves live sessions to another server of the cluster.
+// The sessions are offered through the shared database, and the agents
+// are told with their next heartbeat to re-dial the new server, which
+// continues each session with its overlay IP and routes. The agents move
+// their relay streams before leaving this server, so no packets are lost.
+func (s *Server) HandOffSessions(ctx context.Context, req *proto.HandOffSessionsRequest) (*proto.HandOffSessionsResponse, error) {
+	admin, err := s.authorizeAdmin(ctx, permOperate)
+	if err != nil {
+		return nil, err
+	}
+
+	server := req.Server
+	if server == "" {
+		if len(s.config.Network.AlternateServers) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "server is required without network.alternate_servers")
+		}
+		server = s.config.Network.AlternateServers[0]
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid server %q: %v", server, err)
+	}
+
+	var sessions []*SessionInfo
+	if req.AgentId != "" {
+		si := s.findSessionByAgent(req.AgentId)
+		if si == nil {
+			return nil, status.Errorf(codes.NotFound, "agent %s has no live session", req.AgentId)
+		}
+		sessions = append(sessions, si)
+	} else {
+		s.sessions.Range(func(key, value interface{}) bool {
+			sessions = append(sessions, value.(*SessionInfo))
+			return true
+		})
+	}
+
+	resp := &proto.HandOffSessionsResponse{Server: server}
+	for _, si := range sessions {
+		// Older agents would not follow, they reconnect when this server
+		// goes down
+		if !slices.Contains(si.capabilities, proto.CapabilitySessionHandoff) {
+			resp.Skipped++
+			continue
+		}
+		if err := s.offerHandoff(si, server); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to hand over session %s: %v", si.SessionID, err)
+		}
+		resp.Sessions++
+	}
+
+	log.Printf("%d sessions handed over to %s by %s, %d agents cannot move", resp.Sessions, server, admin.Username, resp.Skipped)
+	return resp, nil
+}
+
+// offerHandoff stores the state of a session for the server it moves to
+// and has the heartbeats tell its agent to re-dial that server
+func (s *Server) offerHandoff(si *SessionInfo, server string) error {
+	until := time.Now().Add(handoffTimeout)
+
+	si.mu.RLock()
+	sent, received := si.BytesSent, si.BytesReceived
+	si.mu.RUnlock()
+	if err := s.db.OfferHandoff(si.SessionID, sent, received, until); err != nil {
+		return err
+	}
+
+	si.mu.Lock()
+	si.handoffServer, si.handoffUntil = server, until
+	si.mu.Unlock()
+	return nil
+}
+
+// handoffTarget returns the server the agent of a session is told to
+// move to, empty when the session is not handed over
+func (si *SessionInfo) handoffTarget() string {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	if time.Now().After(si.handoffUntil) {
+		return ""
+	}
+	return si.handoffServer
+}
+
+// takeHandoff returns the session another server of the cluster handed
+// over for the registering agent to continue, nil if there is none
+func (s *Server) takeHandoff(sessionID, agentID, userID string) *haSession {
+	if sessionID == "" {
+		return nil
+	}
+	// A session handed over to the server it is live on reconnects there
+	if _, live := s.sessions.Load(sessionID); live {
+		return nil
+	}
+
+	session, err := s.db.GetHandoff(sessionID, agentID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Failed to look up handoff of session %s: %v", sessionID, err)
+		}
+		return nil
+	}
+	return &haSession{
+		SessionID:     session.ID,
+		AgentID:       session.AgentID,
+		UserID:        userID,
+		Created:       session.ConnectedAt,
+		BytesSent:     session.BytesSent,
+		BytesReceived: session.BytesReceived,
+	}
+}
+
+// movedAway reports whether a handed over session was continued by the
+// other server: its row names the connection there, or is gone once the
+// session ended there
+func (s *Server) movedAway(si *SessionInfo) bool {
+	si.mu.RLock()
+	offered := si.handoffServer != ""
+	si.mu.RUnlock()
+	if !offered {
+		return false
+	}
+
+	connectionID, err := s.db.GetSessionConnection(si.SessionID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return true
+	case err != nil:
+		log.Printf("Failed to check handoff of session %s: %v", si.SessionID, err)
+		return false
+	}
+	return connectionID != si.connectionID
+}
+
+// sessionMoved drops a session the agent continues on another server,
+// without recording it as ended or the agent as offline
+func (s *Server) sessionMoved(si *SessionInfo) {
+	if !s.removeSession(si.SessionID) {
+		return
+	}
+	si.cancel()
+	s.agents.Delete(si.AgentID)
+
+	si.mu.RLock()
+	server := si.handoffServer
+	si.mu.RUnlock()
+	log.Printf("Session %s of agent %s moved to %s", si.SessionID, si.AgentID, server)
+}
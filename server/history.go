@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// historyPurgeInterval is how often expired history is purged
+const historyPurgeInterval = time.Hour
+
+// endSession records the final counters of a session and why it ended in
+// the history
+func (s *Server) endSession(si *SessionInfo) {
+	si.mu.RLock()
+	sent, received := si.BytesSent, si.BytesReceived
+	reason, detail := si.endReason, si.endDetail
+	si.mu.RUnlock()
+
+	if err := s.db.EndSession(si.SessionID, sent, received, disconnectCode(reason), detail); err != nil {
+		log.Printf("Failed to record history of session %s: %v", si.SessionID, err)
+	}
+}
+
+// ArchiveAgent soft-deletes an agent. Its live session is dropped and its
+// overlay IP released, but its history and rules are kept until restored
+// or purged.
+func (s *Server) ArchiveAgent(ctx context.Context, req *proto.ArchiveAgentRequest) (*proto.AgentDetail, error) {
+	admin, err := s.authorizeAdmin(ctx, permOperate)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.db.GetAgentByID(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s not found", req.AgentId)
+	}
+	if !agent.DeletedAt.IsZero() {
+		return nil, status.Errorf(codes.FailedPrecondition, "agent %s is already archived", req.AgentId)
+	}
+
+	// End the relay streams before the overlay IP can be reused
+	if si := s.findSessionByAgent(agent.ID); si != nil {
+		s.terminateSession(si, proto.DisconnectReason_DISCONNECT_SERVER_DECISION, "agent archived by "+admin.Username)
+	}
+
+	if err := s.db.ArchiveAgent(agent.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to archive agent: %v", err)
+	}
+	s.agents.Delete(agent.ID)
+	s.ipPool.Release(agent.ID)
+	s.alerts.agentRemoved(agent.ID)
+
+	log.Printf("Agent %s archived by %s", agent.ID, admin.Username)
+
+	agent, err = s.db.GetAgentByID(agent.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get agent: %v", err)
+	}
+	return s.agentDetail(agent), nil
+}
+
+// RestoreAgent restores an archived agent, keeping its previous overlay IP
+// if it is still free
+func (s *Server) RestoreAgent(ctx context.Context, req *proto.RestoreAgentRequest) (*proto.AgentDetail, error) {
+	admin, err := s.authorizeAdmin(ctx, permOperate)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.db.GetAgentByID(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s not found", req.AgentId)
+	}
+	if agent.DeletedAt.IsZero() {
+		return nil, status.Errorf(codes.FailedPrecondition, "agent %s is not archived", req.AgentId)
+	}
+
+	ip := net.ParseIP(agent.IPAddress)
+	if ip == nil || s.ipPool.AllocateSpecific(agent.ID, ip) != nil {
+		if ip, err = s.ipPool.Allocate(agent.ID); err != nil {
+			return nil, status.Errorf(codes.ResourceExhausted, "failed to allocate IP: %v", err)
+		}
+	}
+
+	if err := s.db.RestoreAgent(agent.ID, ip.String()); err != nil {
+		s.ipPool.Release(agent.ID)
+		return nil, status.Errorf(codes.Internal, "failed to restore agent: %v", err)
+	}
+
+	log.Printf("Agent %s restored by %s, IP: %s", agent.ID, admin.Username, ip)
+
+	agent, err = s.db.GetAgentByID(agent.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get agent: %v", err)
+	}
+	return s.agentDetail(agent), nil
+}
+
+// ListSessionHistory returns the ended sessions of an agent, newest first
+func (s *Server) ListSessionHistory(ctx context.Context, req *proto.ListSessionHistoryRequest) (*proto.ListSessionHistoryResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	records, err := s.db.ListSessionHistory(req.AgentId, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list session history: %v", err)
+	}
+
+	resp := &proto.ListSessionHistoryResponse{}
+	for _, r := range records {
+		resp.Sessions = append(resp.Sessions, &proto.SessionRecord{
+			SessionId:      r.ID,
+			AgentId:        r.AgentID,
+			ConnectedAt:    timestamppb.New(r.ConnectedAt),
+			DisconnectedAt: timestamppb.New(r.DisconnectedAt),
+			BytesSent:      r.BytesSent,
+			BytesReceived:  r.BytesReceived,
+			Reason:         r.Reason,
+			ReasonCode:     disconnectReasonFromCode(r.ReasonCode),
+		})
+	}
+
+	return resp, nil
+}
+
+// historyPurgeLoop deletes session history and archived agents older than
+// the configured retention
+func (s *Server) historyPurgeLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(historyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		retention := time.Duration(s.config.Database.HistoryDays) * 24 * time.Hour
+		sessions, agents, err := s.db.PurgeHistory(time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("Failed to purge history: %v", err)
+		} else if sessions > 0 || agents > 0 {
+			log.Printf("Purged %d ended sessions and %d archived agents", sessions, agents)
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+	"github.com/taills/EasyAnyLink/common/errcode"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// identityChallengeTTL is how long an agent has to register with a
+// challenge it got
+const identityChallengeTTL = time.Minute
+
+// identityChallengeRate is the number of identity challenges the server
+// issues per second, and at once. Those issued within identityChallengeTTL
+// stay below maxSetEntries, so a flood of requests cannot fill the set of
+// pending challenges and lock out agents.
+const identityChallengeRate = 100
+
+var errUnknownChallenge = errors.New("identity challenge is unknown, expired or used")
+
+// GetIdentityChallenge issues a challenge for an agent to sign with its
+// hardware identity key. Like Register it needs no authentication and
+// counts against the registration rate limit, and challenges are limited
+// to identityChallengeRate on top.
+func (s *Server) GetIdentityChallenge(ctx context.Context, req *proto.IdentityChallengeRequest) (*proto.IdentityChallenge, error) {
+	if req.AgentId == "" || len(req.AgentId) > maxRequestIDLength {
+		return nil, errcode.Status(codes.InvalidArgument, errcode.InvalidArgument, "invalid agent_id")
+	}
+	if s.registrations != nil {
+		if ok, retryAfter := s.registrations.take(); !ok {
+			return nil, resourceExhausted(ctx, retryAfter, "registration rate limit exceeded")
+		}
+	}
+	if ok, retryAfter := s.challengeRate.take(); !ok {
+		return nil, resourceExhausted(ctx, retryAfter, "identity challenge rate limit exceeded")
+	}
+
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate challenge: %v", err)
+	}
+	if added, retryAfter := s.challenges.add(req.AgentId + "/" + string(challenge)); !added {
+		return nil, resourceExhausted(ctx, retryAfter, "too many pending identity challenges")
+	}
+	return &proto.IdentityChallenge{
+		Challenge: challenge,
+		ExpiresIn: int32(identityChallengeTTL / time.Second),
+	}, nil
+}
+
+// verifyIdentity checks the identity signature of a registration that
+// carries an identity key, and that its challenge was issued to the agent
+// and is used once
+func (s *Server) verifyIdentity(ctx context.Context, req *proto.RegisterRequest) error {
+	if len(req.IdentityKey) == 0 {
+		return nil
+	}
+	err := crypto.VerifyIdentity(req)
+	if err == nil && !s.challenges.take(req.AgentId+"/"+string(req.IdentityChallenge)) {
+		err = errUnknownChallenge
+	}
+	if err != nil {
+		log.Printf("Registration of agent %s rejected: %v", req.AgentId, err)
+		s.authFailed(ctx, "Register")
+		return errcode.Status(codes.Unauthenticated, errcode.IdentityRejected, "authentication failed")
+	}
+	return nil
+}
+
+// checkIdentity refuses a registration without the identity key the agent
+// is bound to, bound being empty for an unbound or new agent, and one
+// without any key if security.require_identity is set
+func (s *Server) checkIdentity(ctx context.Context, bound string, req *proto.RegisterRequest) error {
+	presented := ""
+	if len(req.IdentityKey) > 0 {
+		presented = base64.StdEncoding.EncodeToString(req.IdentityKey)
+	}
+	switch {
+	case bound != "" && presented != bound:
+		log.Printf("Registration of agent %s rejected: not signed with the identity key it is bound to", req.AgentId)
+	case presented == "" && s.config.Security.RequireIdentity:
+		log.Printf("Registration of agent %s rejected: no hardware identity key", req.AgentId)
+	default:
+		return nil
+	}
+	s.authFailed(ctx, "Register")
+	return errcode.Status(codes.PermissionDenied, errcode.IdentityRejected,
+		"agent %s must register with its hardware identity key", req.AgentId)
+}
+
+// ResetAgentIdentity unbinds an agent from its hardware identity key, for
+// a device whose TPM was cleared or replaced
+func (s *Server) ResetAgentIdentity(ctx context.Context, req *proto.ResetAgentIdentityRequest) (*proto.AgentDetail, error) {
+	admin, err := s.authorizeAdmin(ctx, permOperate)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.db.GetAgentByID(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s not found", req.AgentId)
+	}
+	if agent.IdentityKey == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "agent %s is not bound to an identity key", req.AgentId)
+	}
+	if err := s.db.SetAgentIdentity(agent.ID, "", ""); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reset agent identity: %v", err)
+	}
+
+	log.Printf("Identity key of agent %s reset by %s", agent.ID, admin.Username)
+
+	agent, err = s.db.GetAgentByID(agent.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get agent: %v", err)
+	}
+	return s.agentDetail(agent), nil
+}
+
+// agentIdentity describes the identity key an agent is bound to, nil for
+// none
+func agentIdentity(agent *Agent) *proto.AgentIdentity {
+	if agent.IdentityKey == "" {
+		return nil
+	}
+	identity := &proto.AgentIdentity{Provider: agent.IdentityProvider}
+	if der, err := base64.StdEncoding.DecodeString(agent.IdentityKey); err == nil {
+		identity.Fingerprint = crypto.IdentityFingerprint(der)
+	}
+	return identity
+}
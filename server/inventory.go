@@ -0,0 +1,207 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// inventoryHost is an agent listed in an inventory export
+type inventoryHost struct {
+	name  string // host name derived from the agent name, unique in the export
+	agent *Agent
+	group string // name of the agent's group, empty for none
+}
+
+// ExportInventory renders the overlay addresses of the approved, active
+// agents as a hosts file fragment or an Ansible dynamic inventory
+func (s *Server) ExportInventory(ctx context.Context, req *proto.ExportInventoryRequest) (*proto.ExportInventoryResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	domain := strings.ToLower(strings.Trim(req.Domain, "."))
+	if domain != "" && !validDomain(domain) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid domain %q", req.Domain)
+	}
+	if req.Format != "" && req.Format != "hosts" && req.Format != "ansible" {
+		return nil, status.Errorf(codes.InvalidArgument, "format must be 'hosts' or 'ansible'")
+	}
+
+	hosts, err := s.inventoryHosts()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to export inventory: %v", err)
+	}
+
+	resp := &proto.ExportInventoryResponse{AgentCount: int32(len(hosts))}
+	if req.Format == "ansible" {
+		resp.ContentType = "application/json"
+		resp.Data, err = ansibleInventory(hosts, domain)
+	} else {
+		resp.ContentType = "text/plain"
+		resp.Data = hostsFile(hosts, domain)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to render inventory: %v", err)
+	}
+
+	return resp, nil
+}
+
+// inventoryHosts lists the agents with an overlay address, giving each a
+// unique host name. Agents whose name collides with an earlier one get
+// the start of their ID appended.
+func (s *Server) inventoryHosts() ([]*inventoryHost, error) {
+	var hosts []*inventoryHost
+	names := make(map[string]bool)
+
+	filter := AgentFilter{Limit: maxPageSize}
+	for {
+		agents, err := s.db.ListAgents(filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, agent := range agents {
+			if agent.IPAddress == "" {
+				continue
+			}
+
+			name := hostName(agent.Name)
+			if name == "" || names[name] {
+				name = strings.TrimPrefix(name+"-", "-") + hostName(agent.ID[:8])
+			}
+			names[name] = true
+
+			host := &inventoryHost{name: name, agent: agent}
+			if agent.GroupID != 0 {
+				if group, err := s.db.GetAgentGroup(agent.GroupID); err == nil {
+					host.group = group.Name
+				}
+			}
+			hosts = append(hosts, host)
+		}
+		if len(agents) < filter.Limit {
+			break
+		}
+		filter.AfterID = agents[len(agents)-1].ID
+	}
+
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].name < hosts[j].name })
+	return hosts, nil
+}
+
+// hostsFile renders hosts as /etc/hosts lines, with the name in domain as
+// the canonical name if a domain is given
+func hostsFile(hosts []*inventoryHost, domain string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# EasyAnyLink agents, generated %s\n", time.Now().UTC().Format(time.RFC3339))
+	for _, h := range hosts {
+		if domain != "" {
+			fmt.Fprintf(&buf, "%s\t%s.%s %s\n", h.agent.IPAddress, h.name, domain, h.name)
+		} else {
+			fmt.Fprintf(&buf, "%s\t%s\n", h.agent.IPAddress, h.name)
+		}
+	}
+	return buf.Bytes()
+}
+
+// ansibleInventory renders hosts in the JSON format of Ansible dynamic
+// inventory scripts. Agents are grouped by type and by agent group.
+func ansibleInventory(hosts []*inventoryHost, domain string) ([]byte, error) {
+	type hostGroup struct {
+		Hosts    []string `json:"hosts,omitempty"`
+		Children []string `json:"children,omitempty"`
+	}
+
+	inventory := map[string]interface{}{}
+	hostvars := map[string]map[string]interface{}{}
+	groups := map[string]*hostGroup{}
+
+	addToGroup := func(group, host string) {
+		if groups[group] == nil {
+			groups[group] = &hostGroup{}
+		}
+		groups[group].Hosts = append(groups[group].Hosts, host)
+	}
+
+	for _, h := range hosts {
+		name := h.name
+		if domain != "" {
+			name += "." + domain
+		}
+		vars := map[string]interface{}{
+			"ansible_host":          h.agent.IPAddress,
+			"easyanylink_agent_id":  h.agent.ID,
+			"easyanylink_user_id":   h.agent.UserID,
+			"easyanylink_type":      h.agent.Type,
+			"easyanylink_status":    h.agent.Status,
+			"easyanylink_public_ip": h.agent.PublicIP,
+		}
+		if h.group != "" {
+			vars["easyanylink_group"] = h.group
+			addToGroup("group_"+ansibleGroupName(h.group), name)
+		}
+		hostvars[name] = vars
+		addToGroup(ansibleGroupName(h.agent.Type), name)
+	}
+
+	all := &hostGroup{}
+	for name, group := range groups {
+		inventory[name] = group
+		all.Children = append(all.Children, name)
+	}
+	sort.Strings(all.Children)
+	inventory["all"] = all
+	inventory["_meta"] = map[string]interface{}{"hostvars": hostvars}
+
+	return json.MarshalIndent(inventory, "", "  ")
+}
+
+// hostName turns an agent name into a host name label: lowercase letters,
+// digits and inner dashes
+func hostName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	label := strings.Trim(b.String(), "-")
+	if len(label) > 63 {
+		label = strings.TrimRight(label[:63], "-")
+	}
+	return label
+}
+
+// ansibleGroupName turns a name into a valid Ansible group name
+func ansibleGroupName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// validDomain reports whether domain consists of valid host name labels
+func validDomain(domain string) bool {
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || hostName(label) != label {
+			return false
+		}
+	}
+	return true
+}
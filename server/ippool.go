@@ -116,6 +116,20 @@ func (p *IPPool) IsAllocated(ip net.IP) bool {
 	return false
 }
 
+// IsBroadcast reports whether ip is the broadcast address of the overlay
+func (p *IPPool) IsBroadcast(ip net.IP) bool {
+	ip4, network := ip.To4(), p.cidr.IP.To4()
+	if ip4 == nil || network == nil || len(p.cidr.Mask) != net.IPv4len {
+		return false
+	}
+	for i := range ip4 {
+		if ip4[i] != network[i]|^p.cidr.Mask[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // AllocateSpecific allocates a specific IP address
 func (p *IPPool) AllocateSpecific(agentID string, ip net.IP) error {
 	p.mu.Lock()
@@ -150,6 +164,18 @@ func (p *IPPool) AllocateSpecific(agentID string, ip net.IP) error {
 	return nil
 }
 
+// Allocations returns a copy of the allocated IPs by agent ID
+func (p *IPPool) Allocations() map[string]net.IP {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	allocations := make(map[string]net.IP, len(p.allocated))
+	for agentID, ip := range p.allocated {
+		allocations[agentID] = ip
+	}
+	return allocations
+}
+
 // AvailableCount returns the number of available IPs
 func (p *IPPool) AvailableCount() int {
 	p.mu.RLock()
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"log"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetKeepalive reports the heartbeat settings sent to agents
+func (s *Server) GetKeepalive(ctx context.Context, req *proto.GetKeepaliveRequest) (*proto.KeepaliveResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+	return s.keepalive.Load(), nil
+}
+
+// SetKeepalive changes the heartbeat settings until the server restarts.
+// Heartbeat responses carry them, so connected agents follow without
+// reconnecting.
+func (s *Server) SetKeepalive(ctx context.Context, req *proto.SetKeepaliveRequest) (*proto.KeepaliveResponse, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+	if req.Interval < 0 || req.Timeout < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "interval and timeout must not be negative")
+	}
+
+	current := s.keepalive.Load()
+	keepalive := &proto.KeepaliveResponse{Interval: current.Interval, Timeout: current.Timeout}
+	if req.Interval > 0 {
+		keepalive.Interval = req.Interval
+	}
+	if req.Timeout > 0 {
+		keepalive.Timeout = req.Timeout
+	}
+	if keepalive.Timeout <= keepalive.Interval {
+		return nil, status.Errorf(codes.InvalidArgument, "timeout %ds must be longer than the interval %ds",
+			keepalive.Timeout, keepalive.Interval)
+	}
+
+	s.keepalive.Store(keepalive)
+	log.Printf("Keepalive set to interval %ds, timeout %ds by %s", keepalive.Interval, keepalive.Timeout, admin.Username)
+	return keepalive, nil
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+)
+
+const (
+	// loopSampleRate is the 1 in n fingerprints the loop detector tracks.
+	// A looping packet keeps its fingerprint, so it is tracked on every
+	// pass or on none.
+	loopSampleRate = 16
+
+	// loopTableSize is the number of relayed packets remembered
+	loopTableSize = 4096
+
+	// loopWindow is how long a relayed packet is remembered and how long
+	// the looped packets of a gateway are counted
+	loopWindow = 2 * time.Second
+
+	// loopHoldoff is how long a gateway whose loop was handled only has its
+	// looped packets dropped, giving agents time to re-fetch their routes
+	loopHoldoff = time.Minute
+)
+
+// routeLoopEvent is the data of route.loop events
+type routeLoopEvent struct {
+	GatewayID        string `json:"gateway_id"`      // agent the packets looped through
+	SourceAgentID    string `json:"source_agent_id"` // agent that sent the packets into the loop
+	Destination      string `json:"destination"`
+	Packets          int    `json:"packets"` // looped packets seen within 2 seconds
+	QuarantinedRules []int  `json:"quarantined_rules"`
+}
+
+// relayedPacket is a packet the relay sent to an agent
+type relayedPacket struct {
+	fingerprint uint64
+	source      string // agent the packet came from
+	dest        string // agent the packet was sent to
+	at          time.Time
+}
+
+// loopState counts the looped packets of an agent
+type loopState struct {
+	hits  int
+	since time.Time // first hit of the count
+	held  time.Time // when the loop was handed to quarantine
+}
+
+// routeLoop is a forwarding loop through a gateway
+type routeLoop struct {
+	gateway string
+	source  string
+	dst     net.IP
+	packets int
+}
+
+// loopDetector finds packets that the agent they were relayed to sends
+// back into the relay, e.g. because a gateway routes the overlay CIDR
+// into its TUN. Every hop decrements the TTL, so a loop ends on its own,
+// but only after a packet crossed the relay dozens of times.
+type loopDetector struct {
+	threshold int // looped packets within loopWindow that make a loop
+
+	mu      sync.Mutex
+	relayed [loopTableSize]relayedPacket
+	agents  map[string]*loopState // agentID -> looped packets
+}
+
+// newLoopDetector creates a loop detector
+func newLoopDetector(threshold int) *loopDetector {
+	return &loopDetector{
+		threshold: threshold,
+		agents:    make(map[string]*loopState),
+	}
+}
+
+// sample returns the fingerprint of a packet and whether it is tracked
+func (d *loopDetector) sample(b []byte, h *packet.IPv4) (uint64, bool) {
+	fp := packet.Fingerprint(b, h)
+	return fp, fp%loopSampleRate == 0
+}
+
+// sent remembers a tracked packet relayed from source to dest
+func (d *loopDetector) sent(fp uint64, source, dest string) {
+	d.mu.Lock()
+	d.relayed[fp/loopSampleRate%loopTableSize] = relayedPacket{
+		fingerprint: fp,
+		source:      source,
+		dest:        dest,
+		at:          time.Now(),
+	}
+	d.mu.Unlock()
+}
+
+// received checks a tracked packet from agentID and reports whether it
+// was relayed to the agent before. The returned loop is non-nil once the
+// agent reached the threshold, it is returned once per loopHoldoff.
+func (d *loopDetector) received(fp uint64, agentID string, dst net.IP) (*routeLoop, bool) {
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	p := d.relayed[fp/loopSampleRate%loopTableSize]
+	if p.fingerprint != fp || p.dest != agentID || now.Sub(p.at) > loopWindow {
+		return nil, false
+	}
+
+	state := d.agents[agentID]
+	if state == nil {
+		state = &loopState{}
+		d.agents[agentID] = state
+	}
+	if now.Sub(state.since) > loopWindow {
+		state.hits, state.since = 0, now
+	}
+	state.hits++
+	if state.hits < d.threshold || now.Sub(state.held) < loopHoldoff {
+		return nil, true
+	}
+
+	state.held = now
+	return &routeLoop{gateway: agentID, source: p.source, dst: append(net.IP(nil), dst...), packets: state.hits}, true
+}
+
+// forget drops the loop state of agents that have not looped recently
+func (d *loopDetector) forget() {
+	d.mu.Lock()
+	for agentID, state := range d.agents {
+		if time.Since(state.since) > loopHoldoff && time.Since(state.held) > loopHoldoff {
+			delete(d.agents, agentID)
+		}
+	}
+	d.mu.Unlock()
+}
+
+// quarantineLoop disables the forward rules that send the packets of a
+// loop back through the gateway: the gateway's own rules for the
+// destination, or else the source agent's rules forwarding it to the
+// gateway. Operators re-enable a rule once its gateway is fixed.
+func (s *Server) quarantineLoop(l *routeLoop) {
+	defer s.wg.Done()
+	defer s.loops.forget()
+
+	log.Printf("Forwarding loop: %d packets from %s to %s looped through gateway %s",
+		l.packets, l.source, l.dst, l.gateway)
+
+	rules, err := s.loopRules(l)
+	if err != nil {
+		log.Printf("Failed to find the routing rules of the loop through %s: %v", l.gateway, err)
+	}
+
+	var quarantined []int
+	for _, rule := range rules {
+		rule.Enabled = false
+		if err := s.db.UpdateRoutingRule(rule); err != nil {
+			log.Printf("Failed to quarantine routing rule %d: %v", rule.ID, err)
+			continue
+		}
+		s.notifyRuleChange(rule)
+		quarantined = append(quarantined, rule.ID)
+
+		summary := fmt.Sprintf("Routing rule %d of %s (%s via %s) quarantined: packets to %s loop through gateway %s",
+			rule.ID, ruleOwner(rule), rule.Destination, rule.GatewayID, l.dst, l.gateway)
+		log.Print(summary)
+		s.alerts.ruleQuarantined(rule.ID, summary)
+	}
+
+	s.webhooks.publish(EventRouteLoop, &routeLoopEvent{
+		GatewayID:        l.gateway,
+		SourceAgentID:    l.source,
+		Destination:      l.dst.String(),
+		Packets:          l.packets,
+		QuarantinedRules: quarantined,
+	})
+}
+
+// loopRules returns the enabled forward rules that route the destination
+// of a loop into it
+func (s *Server) loopRules(l *routeLoop) ([]*RoutingRule, error) {
+	dst, ok := netip.AddrFromSlice(l.dst)
+	if !ok {
+		return nil, nil
+	}
+	dst = dst.Unmap()
+
+	matching := func(agentID, gatewayID string) ([]*RoutingRule, error) {
+		rules, err := s.db.GetRoutingRulesByAgentID(agentID)
+		if err != nil {
+			return nil, err
+		}
+		var matched []*RoutingRule
+		for _, rule := range rules {
+			prefix, err := netip.ParsePrefix(rule.Destination)
+			if err != nil || rule.Action != "forward" || !prefix.Contains(dst) {
+				continue
+			}
+			if gatewayID == "" || rule.GatewayID == gatewayID {
+				matched = append(matched, rule)
+			}
+		}
+		return matched, nil
+	}
+
+	rules, err := matching(l.gateway, "")
+	if err != nil || len(rules) > 0 || l.source == "" {
+		return rules, err
+	}
+	return matching(l.source, l.gateway)
+}
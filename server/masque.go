@@ -0,0 +1,128 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/taills/EasyAnyLink/common/config"
+	"github.com/taills/EasyAnyLink/common/crypto"
+)
+
+// MasqueEndpoint is the HTTP/3 web server agents in MASQUE mode reach the
+// server through. A CONNECT-UDP request (RFC 9298) on the configured path
+// opens a tunnel to the QUIC listener and carries the agent's QUIC
+// connection in HTTP datagrams; any other request gets the decoy site.
+// Serve gRPC on a QUIC listener over PacketConn to accept the tunnels.
+type MasqueEndpoint struct {
+	server  *http3.Server
+	udpConn *net.UDPConn
+	conn    *crypto.MasqueConn
+	path    string
+	port    int // UDP port of the QUIC listener, the only target allowed
+	decoy   http.Handler
+}
+
+// NewMasqueEndpoint creates the MASQUE endpoint of the QUIC listener at
+// tunnel, listening on the configured UDP address
+func NewMasqueEndpoint(cfg config.MasqueConfig, tlsConfig *tls.Config, tunnel net.Addr) (*MasqueEndpoint, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", cfg.Listen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve UDP address: %w", err)
+	}
+	udpConn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen UDP: %w", err)
+	}
+
+	e := &MasqueEndpoint{
+		udpConn: udpConn,
+		conn:    crypto.NewMasqueConn(udpConn.LocalAddr()),
+		path:    cfg.Path,
+		decoy:   http.NotFoundHandler(),
+	}
+	if addr, ok := tunnel.(*net.UDPAddr); ok {
+		e.port = addr.Port
+	}
+	if cfg.SiteDir != "" {
+		e.decoy = http.FileServer(http.Dir(cfg.SiteDir))
+	}
+
+	h3TLS := tlsConfig.Clone()
+	h3TLS.NextProtos = []string{http3.NextProtoH3}
+	e.server = &http3.Server{
+		Handler:         e,
+		TLSConfig:       h3TLS,
+		EnableDatagrams: true,
+		QUICConfig: &quic.Config{
+			MaxIdleTimeout:  300 * time.Second,
+			KeepAlivePeriod: 30 * time.Second,
+		},
+	}
+	return e, nil
+}
+
+// Serve answers HTTP/3 requests until the endpoint is closed
+func (e *MasqueEndpoint) Serve() error {
+	return e.server.Serve(e.udpConn)
+}
+
+// PacketConn returns the conn the tunnels deliver QUIC packets to
+func (e *MasqueEndpoint) PacketConn() net.PacketConn {
+	return e.conn
+}
+
+// Addr returns the UDP address of the endpoint
+func (e *MasqueEndpoint) Addr() net.Addr {
+	return e.udpConn.LocalAddr()
+}
+
+// Close stops the endpoint, ending the tunnels
+func (e *MasqueEndpoint) Close() error {
+	err := e.server.Close()
+	e.udpConn.Close()
+	e.conn.Close()
+	return err
+}
+
+func (e *MasqueEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect || !strings.HasPrefix(r.URL.Path, e.path) {
+		e.decoy.ServeHTTP(w, r)
+		return
+	}
+	if r.Proto != crypto.MasqueProtocol {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	// The path ends in {target_host}/{target_port}/; whatever name the
+	// agent knows the server by, only the QUIC listener can be reached so
+	// the endpoint is no open proxy
+	target := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, e.path), "/"), "/")
+	if len(target) != 2 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if port, err := strconv.Atoi(target[1]); err != nil || port != e.port {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	remote, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Capsule-Protocol", "?1")
+	w.WriteHeader(http.StatusOK)
+	str := w.(http3.HTTPStreamer).HTTPStream()
+	e.conn.Serve(r.Context(), str, net.UDPAddrFromAddrPort(remote))
+	str.Close()
+}
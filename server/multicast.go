@@ -0,0 +1,108 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"sync/atomic"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// multicastCounters counts the decisions of the multicast policy
+type multicastCounters struct {
+	dropped     atomic.Uint64
+	relayed     atomic.Uint64
+	unicast     atomic.Uint64
+	rateLimited atomic.Uint64
+	copies      atomic.Uint64
+}
+
+// stats returns the counters in their proto form
+func (c *multicastCounters) stats(policy string) *proto.MulticastStats {
+	return &proto.MulticastStats{
+		Policy:      policy,
+		Dropped:     c.dropped.Load(),
+		Relayed:     c.relayed.Load(),
+		Unicast:     c.unicast.Load(),
+		RateLimited: c.rateLimited.Load(),
+		Copies:      c.copies.Load(),
+	}
+}
+
+// multicastLimit returns the broadcast and multicast rate limit of a new
+// session, nil when the policy drops them
+func (s *Server) multicastLimit() *tokenBucket {
+	if s.config.Network.Multicast == "drop" {
+		return nil
+	}
+	return newTokenBucket(float64(s.config.Network.MulticastRate), s.config.Network.MulticastBurst)
+}
+
+// isMulticast reports whether dst addresses every agent of a link: a
+// multicast group, the limited broadcast or the overlay broadcast address
+func (s *Server) isMulticast(dst net.IP) bool {
+	return packet.IsMulticast(dst) || s.ipPool.IsBroadcast(dst)
+}
+
+// forwardMulticast applies the multicast policy to a broadcast or
+// multicast packet. The agents of a user share one link: "relay" copies
+// the packet to the user's other agents, "unicast" copies discovery
+// packets (mDNS, SSDP, LLMNR, NetBIOS) addressed to each agent's overlay
+// IP and drops the rest, and "drop" drops them all.
+func (s *Server) forwardMulticast(si *SessionInfo, dp *proto.DataPacket, h *packet.IPv4) (string, error) {
+	unicast := s.config.Network.Multicast == "unicast"
+	if si.multicast == nil || (unicast && !packet.IsDiscovery(h)) {
+		s.multicast.dropped.Add(1)
+		return decisionMulticastDropped, nil
+	}
+	if ok, _ := si.multicast.take(); !ok {
+		s.multicast.rateLimited.Add(1)
+		return decisionRateLimited, nil
+	}
+
+	var errs []error
+	for _, peer := range s.userSessions(si.UserID, si.SessionID) {
+		payload := append([]byte(nil), dp.Payload...)
+		if unicast {
+			if !peer.ip.IsValid() {
+				continue
+			}
+			if err := packet.SetDestination(payload, peer.ip.AsSlice()); err != nil {
+				return decisionMalformed, err
+			}
+		}
+
+		err := s.deliver(peer, &proto.DataPacket{
+			SessionId:          dp.SessionId,
+			SourceAgentId:      dp.SourceAgentId,
+			DestinationAgentId: peer.AgentID,
+			Payload:            payload,
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.multicast.copies.Add(1)
+	}
+
+	if unicast {
+		s.multicast.unicast.Add(1)
+		return decisionMulticastUnicast, errors.Join(errs...)
+	}
+	s.multicast.relayed.Add(1)
+	return decisionMulticastRelayed, errors.Join(errs...)
+}
+
+// userSessions returns the live sessions of a user other than exclude
+func (s *Server) userSessions(userID, exclude string) []*SessionInfo {
+	var sessions []*SessionInfo
+	s.sessions.Range(func(key, value interface{}) bool {
+		si := value.(*SessionInfo)
+		if si.UserID == userID && si.SessionID != exclude {
+			sessions = append(sessions, si)
+		}
+		return true
+	})
+	return sessions
+}
@@ -0,0 +1,13 @@
+//go:build !chaos
+
+package server
+
+import "database/sql"
+
+// chaosBuild is set in servers built with the chaos tag, see faults
+const chaosBuild = false
+
+// withDBFaults returns db, faults are injected in chaos builds only
+func withDBFaults(db *sql.DB, dsn string) *sql.DB {
+	return db
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"maps"
+	"strconv"
+	"strings"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// postureState is the device posture an agent reported, checked against
+// the posture policies of security.posture_policies
+type postureState struct {
+	met      map[string]bool // names of the policies the agent meets
+	failures []string        // the policies it fails and why, in policy order
+}
+
+// meets reports whether a rule naming policy applies to the agent. Rules
+// without a policy always do; a nil state, before the agent reported its
+// posture, and a policy that is not configured fail.
+func (p *postureState) meets(policy string) bool {
+	return policy == "" || p != nil && p.met[policy]
+}
+
+// evaluatePosture checks the posture in the metadata of an agent against
+// the posture policies. An agent that reports no posture fails every
+// policy with a requirement.
+func (s *Server) evaluatePosture(metadata *proto.AgentMetadata) *postureState {
+	state := &postureState{met: make(map[string]bool)}
+	posture := metadata.GetPosture()
+	for _, policy := range s.config.Security.PosturePolicies {
+		var reasons []string
+		if minimum := policy.MinOSVersion[metadata.GetOs()]; minimum != "" {
+			switch {
+			case posture.GetOsVersion() == "":
+				reasons = append(reasons, "OS version unknown")
+			case compareVersions(posture.GetOsVersion(), minimum) < 0:
+				reasons = append(reasons, fmt.Sprintf("OS version %s below %s", posture.GetOsVersion(), minimum))
+			}
+		}
+		if policy.RequireDiskEncryption && posture.GetDiskEncryption() != proto.PostureState_POSTURE_STATE_ENABLED {
+			reasons = append(reasons, "disk encryption "+postureStateName(posture.GetDiskEncryption()))
+		}
+		if policy.RequireFirewall && posture.GetFirewall() != proto.PostureState_POSTURE_STATE_ENABLED {
+			reasons = append(reasons, "firewall "+postureStateName(posture.GetFirewall()))
+		}
+
+		if len(reasons) == 0 {
+			state.met[policy.Name] = true
+		} else {
+			state.failures = append(state.failures, policy.Name+": "+strings.Join(reasons, ", "))
+		}
+	}
+	return state
+}
+
+// updatePosture evaluates the posture an agent reported with its metadata
+// and keeps it with the session. When the agent starts or stops meeting a
+// policy it refreshes its routes, and ACL rules of the policy apply to its
+// next packet.
+func (s *Server) updatePosture(si *SessionInfo, metadata *proto.AgentMetadata) {
+	state := s.evaluatePosture(metadata)
+	previous := si.posture.Swap(state)
+	if previous != nil && maps.Equal(previous.met, state.met) {
+		return
+	}
+
+	for _, failure := range state.failures {
+		log.Printf("Agent %s fails posture policy %s", si.AgentID, failure)
+	}
+	// The agent fetches its routes after registering
+	if previous != nil {
+		s.notifyRouteChange(si.AgentID)
+	}
+}
+
+// agentPosture returns the posture of the live session of an agent, nil
+// if it is not connected
+func (s *Server) agentPosture(agentID string) *postureState {
+	if si := s.findSessionByAgent(agentID); si != nil {
+		return si.posture.Load()
+	}
+	return nil
+}
+
+// validatePosture checks the posture policy of a rule. Only rules granting
+// access take one: withholding a deny rule from a failing agent would grant
+// it more.
+func (s *Server) validatePosture(policy string, deny bool) error {
+	if policy == "" {
+		return nil
+	}
+	if deny {
+		return status.Errorf(codes.InvalidArgument, "posture applies to rules granting access, not to deny rules")
+	}
+	for _, p := range s.config.Security.PosturePolicies {
+		if p.Name == policy {
+			return nil
+		}
+	}
+	return status.Errorf(codes.InvalidArgument, "posture policy %q is not configured", policy)
+}
+
+// postureStateName describes the outcome of a posture check
+func postureStateName(state proto.PostureState) string {
+	switch state {
+	case proto.PostureState_POSTURE_STATE_ENABLED:
+		return "enabled"
+	case proto.PostureState_POSTURE_STATE_DISABLED:
+		return "disabled"
+	}
+	return "unknown"
+}
+
+// compareVersions compares two dotted versions by the leading number of
+// each component, so that "6.8.0-45-generic" compares as 6.8.0. Missing
+// components count as 0.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x = leadingNumber(as[i])
+		}
+		if i < len(bs) {
+			y = leadingNumber(bs[i])
+		}
+		if c := cmp.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// leadingNumber parses the digits a version component starts with
+func leadingNumber(s string) int {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(s[:end])
+	return n
+}
@@ -0,0 +1,330 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// CheckStatus is the outcome of a preflight check
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+	CheckSkip CheckStatus = "skip" // a check it depends on failed
+)
+
+// CheckResult is the result of a preflight check, with a hint on what to
+// do about a failure
+type CheckResult struct {
+	Name   string      `json:"name"`
+	Status CheckStatus `json:"status"`
+	Detail string      `json:"detail"`
+	Hint   string      `json:"hint,omitempty"`
+}
+
+// schemaMigrations are the migrations in scripts/migrations with a column
+// or table each adds, to tell which ones a database lacks. A new
+// migration is added here.
+var schemaMigrations = []struct {
+	name, table, column string // an empty column probes for the table
+}{
+	{"001_user_management", "users", "role"},
+	{"002_archived_agents", "agents", "deleted_at"},
+	{"003_acl_and_approval", "agents", "pending"},
+	{"004_access_windows", "routing_rules", "valid_from"},
+	{"005_disconnect_reason", "session_history", "disconnect_reason"},
+	{"006_disconnect_code", "session_history", "disconnect_code"},
+	{"007_agent_groups", "agents", "group_id"},
+	{"008_stats_rollups", "stats_hourly", ""},
+	{"009_rollouts", "rollouts", ""},
+	{"010_acl_source_uid", "acl_rules", "source_uid"},
+	{"011_site_prefixes", "users", "site_prefixes"},
+	{"012_agent_identity", "agents", "identity_key"},
+	{"013_posture", "acl_rules", "posture"},
+	{"014_access_requests", "access_requests", ""},
+	{"015_scim", "users", "external_id"},
+	{"016_admin_roles", "audit_logs", "role"},
+	{"017_session_handoff", "sessions", "handoff_until"},
+	{"018_scim_deactivation", "users", "scim_deactivated"},
+}
+
+// Preflight checks what the server needs to start with a validated cfg:
+// the database and its schema, the certificate, the listen addresses and
+// the overlay network. It changes nothing; the listen addresses are bound
+// and released at once, so they fail while a server is running.
+func Preflight(cfg *config.ServerConfig) []CheckResult {
+	var results []CheckResult
+	results = append(results, checkDatabase(cfg.Database)...)
+	results = append(results, checkCertificate(cfg)...)
+	results = append(results, checkListeners(cfg)...)
+	results = append(results, checkOverlay(cfg.Network, cfg.Network.MaxSessions)...)
+	return results
+}
+
+// checkDatabase connects to the database and looks for the migrations it
+// lacks
+func checkDatabase(cfg config.DatabaseConfig) []CheckResult {
+	conn := CheckResult{Name: "database"}
+	db, err := NewDatabase(cfg)
+	if err != nil {
+		conn.Status, conn.Detail = CheckFail, err.Error()
+		conn.Hint = "check database.host, port, user and password, and that the database accepts connections from this host"
+		return []CheckResult{conn, {Name: "schema", Status: CheckSkip, Detail: "no database connection"}}
+	}
+	defer db.Close()
+	conn.Status, conn.Detail = CheckOK, fmt.Sprintf("connected to %s:%d/%s", cfg.Host, cfg.Port, cfg.Database)
+
+	schema := CheckResult{Name: "schema"}
+	missing, err := db.missingMigrations()
+	switch {
+	case err != nil:
+		schema.Status, schema.Detail = CheckFail, err.Error()
+		schema.Hint = "initialize the database with scripts/init_db.sql"
+	case len(missing) > 0:
+		schema.Status, schema.Detail = CheckFail, "missing migrations "+strings.Join(missing, ", ")
+		schema.Hint = "apply them from scripts/migrations in order"
+	default:
+		schema.Status, schema.Detail = CheckOK, "up to date with "+schemaMigrations[len(schemaMigrations)-1].name
+	}
+	return []CheckResult{conn, schema}
+}
+
+// missingMigrations returns the schema migrations the database lacks
+func (d *Database) missingMigrations() ([]string, error) {
+	var missing []string
+	for _, m := range schemaMigrations {
+		var n int
+		var err error
+		if m.column == "" {
+			err = d.db.QueryRow(`SELECT COUNT(*) FROM information_schema.tables
+				WHERE table_schema = DATABASE() AND table_name = ?`, m.table).Scan(&n)
+		} else {
+			err = d.db.QueryRow(`SELECT COUNT(*) FROM information_schema.columns
+				WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?`, m.table, m.column).Scan(&n)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect the schema: %w", err)
+		}
+		if n == 0 {
+			missing = append(missing, m.name)
+		}
+	}
+	return missing, nil
+}
+
+// checkCertificate checks that the certificate matches its key, is valid
+// for longer than security.cert_expiry_days and chains to ca_file if set
+func checkCertificate(cfg *config.ServerConfig) []CheckResult {
+	pair := CheckResult{Name: "certificate"}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		pair.Status, pair.Detail = CheckFail, err.Error()
+		pair.Hint = "cert_file and key_file must be a matching PEM certificate and key, see \"server gen-cert\""
+		return []CheckResult{pair}
+	}
+	leaf := cert.Leaf
+	if leaf == nil {
+		if leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			pair.Status, pair.Detail = CheckFail, err.Error()
+			return []CheckResult{pair}
+		}
+	}
+
+	now := time.Now()
+	daysLeft := int(leaf.NotAfter.Sub(now).Hours() / 24)
+	switch {
+	case now.Before(leaf.NotBefore):
+		pair.Status, pair.Detail = CheckFail, fmt.Sprintf("not valid before %s", leaf.NotBefore.Format(time.RFC3339))
+		pair.Hint = "check the clock of this host"
+	case now.After(leaf.NotAfter):
+		pair.Status, pair.Detail = CheckFail, fmt.Sprintf("expired on %s", leaf.NotAfter.Format(time.RFC3339))
+		pair.Hint = "renew the certificate"
+	case daysLeft <= cfg.Security.CertExpiryDays:
+		pair.Status, pair.Detail = CheckWarn, fmt.Sprintf("matches its key, expires in %d days", daysLeft)
+		pair.Hint = "renew the certificate"
+	default:
+		pair.Status, pair.Detail = CheckOK, fmt.Sprintf("matches its key, expires in %d days", daysLeft)
+	}
+	if cfg.CAFile == "" {
+		return []CheckResult{pair}
+	}
+
+	trust := CheckResult{Name: "trust bundle"}
+	bundle, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		trust.Status, trust.Detail = CheckFail, err.Error()
+		return []CheckResult{pair, trust}
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(bundle) {
+		trust.Status, trust.Detail = CheckFail, "no PEM certificates in "+cfg.CAFile
+		return []CheckResult{pair, trust}
+	}
+	intermediates := x509.NewCertPool()
+	for _, der := range cert.Certificate[1:] {
+		if c, err := x509.ParseCertificate(der); err == nil {
+			intermediates.AddCert(c)
+		}
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates}); err != nil {
+		trust.Status, trust.Detail = CheckFail, err.Error()
+		trust.Hint = "agents trusting ca_file would reject the certificate, serve the bundle of the CA that issued it"
+		return []CheckResult{pair, trust}
+	}
+	trust.Status, trust.Detail = CheckOK, "the certificate chains to "+cfg.CAFile
+	return []CheckResult{pair, trust}
+}
+
+// checkListeners binds and releases each configured listen address
+func checkListeners(cfg *config.ServerConfig) []CheckResult {
+	listeners := []struct{ name, network, addr string }{{"QUIC", "udp", cfg.Listen}}
+	if cfg.Masque.Listen != "" {
+		listeners = append(listeners, struct{ name, network, addr string }{"MASQUE", "udp", cfg.Masque.Listen})
+	}
+	if cfg.Gateway.Listen != "" {
+		listeners = append(listeners, struct{ name, network, addr string }{"REST gateway", "tcp", cfg.Gateway.Listen})
+	}
+	if cfg.HA.Listen != "" {
+		listeners = append(listeners, struct{ name, network, addr string }{"HA", "tcp", cfg.HA.Listen})
+	}
+
+	var results []CheckResult
+	for _, l := range listeners {
+		result := CheckResult{Name: fmt.Sprintf("listen %s %s/%s", l.name, l.addr, l.network)}
+		var err error
+		if l.network == "udp" {
+			var conn net.PacketConn
+			if conn, err = net.ListenPacket(l.network, l.addr); err == nil {
+				conn.Close()
+			}
+		} else {
+			var ln net.Listener
+			if ln, err = net.Listen(l.network, l.addr); err == nil {
+				ln.Close()
+			}
+		}
+		if err != nil {
+			result.Status, result.Detail = CheckFail, err.Error()
+			result.Hint = "stop what holds the port, a running server does; ports below 1024 need root or CAP_NET_BIND_SERVICE"
+		} else {
+			result.Status, result.Detail = CheckOK, "bindable"
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Ranges the overlay must not use, and the private ones it should
+var (
+	unusableRanges = []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/8"),
+		netip.MustParsePrefix("127.0.0.0/8"),
+		netip.MustParsePrefix("169.254.0.0/16"),
+		netip.MustParsePrefix("224.0.0.0/3"), // multicast and reserved
+	}
+	privateRanges = []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("100.64.0.0/10"),
+		netip.MustParsePrefix("172.16.0.0/12"),
+		netip.MustParsePrefix("192.168.0.0/16"),
+	}
+)
+
+// checkOverlay checks that the overlay is a usable IPv4 network holding
+// the gateway IP, sized as agents configure it, clear of the networks of
+// this host and large enough for max_sessions
+func checkOverlay(network config.NetworkConfig, maxSessions int) []CheckResult {
+	result := CheckResult{Name: "overlay " + network.OverlayCIDR}
+	prefix, err := netip.ParsePrefix(network.OverlayCIDR)
+	if err != nil || !prefix.Addr().Is4() {
+		result.Status, result.Detail = CheckFail, "overlay_cidr is not an IPv4 CIDR"
+		return []CheckResult{result}
+	}
+	masked := prefix.Masked()
+	for _, r := range unusableRanges {
+		if r.Overlaps(masked) {
+			result.Status, result.Detail = CheckFail, fmt.Sprintf("overlaps the reserved range %s", r)
+			result.Hint = "use a private range such as 10.200.0.0/16"
+			return []CheckResult{result}
+		}
+	}
+	if gateway, err := netip.ParseAddr(network.GatewayIP); err != nil || !masked.Contains(gateway) {
+		result.Status, result.Detail = CheckFail, fmt.Sprintf("gateway_ip %q is not in the overlay", network.GatewayIP)
+		result.Hint = "set gateway_ip to the .1 address of the overlay"
+		return []CheckResult{result}
+	}
+
+	var warnings, hints []string
+	if masked != prefix {
+		warnings = append(warnings, fmt.Sprintf("has host bits set, the network is %s", masked))
+	}
+	if prefix.Bits() != 16 {
+		warnings = append(warnings, "is not a /16, the mask agents configure their TUN with")
+		hints = append(hints, "use a /16 overlay")
+	}
+	if !isPrivate(masked) {
+		warnings = append(warnings, "is not a private range")
+		hints = append(hints, "use a private range such as 10.200.0.0/16")
+	}
+	if local := localNetworkOverlapping(masked); local != "" {
+		warnings = append(warnings, "overlaps "+local+" of this host")
+		hints = append(hints, "move the overlay away from the networks of the server")
+	}
+	// .0, the gateway and the broadcast address are not assigned
+	if size := 1<<(32-prefix.Bits()) - 3; maxSessions > size {
+		warnings = append(warnings, fmt.Sprintf("has %d addresses for max_sessions %d", size, maxSessions))
+		hints = append(hints, "use a larger overlay or lower max_sessions")
+	}
+
+	if len(warnings) > 0 {
+		result.Status, result.Detail = CheckWarn, strings.Join(warnings, "; ")
+		result.Hint = strings.Join(hints, "; ")
+		return []CheckResult{result}
+	}
+	result.Status, result.Detail = CheckOK, fmt.Sprintf("gateway %s, %d addresses", network.GatewayIP, 1<<(32-prefix.Bits())-3)
+	return []CheckResult{result}
+}
+
+// isPrivate reports whether prefix lies in a private range
+func isPrivate(prefix netip.Prefix) bool {
+	for _, r := range privateRanges {
+		if r.Bits() <= prefix.Bits() && r.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+// localNetworkOverlapping returns the first network of a local interface
+// overlapping prefix, as "network of interface", empty if none does
+func localNetworkOverlapping(prefix netip.Prefix) string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+	for _, iface := range ifaces {
+		addrs, _ := iface.Addrs()
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil || ipNet.IP.IsLoopback() {
+				continue
+			}
+			local, _ := netip.AddrFromSlice(ipNet.IP.To4())
+			ones, _ := ipNet.Mask.Size()
+			if network := netip.PrefixFrom(local, ones).Masked(); network.IsValid() && network.Overlaps(prefix) {
+				return network.String() + " of " + iface.Name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// usageFlushInterval is how often relayed usage is written to the database
+const usageFlushInterval = time.Minute
+
+var (
+	errTransferCap  = errors.New("monthly transfer cap reached")
+	errBandwidthCap = errors.New("bandwidth limit exceeded")
+)
+
+// userQuota holds the relay limits and current month usage of a user
+type userQuota struct {
+	mu          sync.Mutex
+	username    string
+	transferCap uint64       // bytes per month, 0 for unlimited
+	bandwidth   *tokenBucket // bytes per second, nil for unlimited
+	month       string       // month of used, sent and received
+	used        uint64       // bytes this month, including persisted usage
+	sent        uint64       // bytes sent this month, not yet persisted
+	received    uint64       // bytes received this month, not yet persisted
+	stale       []UserUsage  // unpersisted usage of previous months
+	capLogged   bool
+}
+
+// quotaTracker enforces per-user relay quotas and accumulates usage,
+// which is persisted periodically for reporting
+type quotaTracker struct {
+	db       *Database
+	notifier *usageNotifier // nil if usage webhooks are disabled
+	users    sync.Map       // userID -> *userQuota
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newQuotaTracker creates a quota tracker and starts persisting usage
+func newQuotaTracker(db *Database, notifier *usageNotifier) *quotaTracker {
+	t := &quotaTracker{
+		db:       db,
+		notifier: notifier,
+		done:     make(chan struct{}),
+	}
+
+	t.wg.Add(1)
+	go t.flushLoop()
+
+	return t
+}
+
+// stop persists pending usage and stops the flush loop
+func (t *quotaTracker) stop() {
+	close(t.done)
+	t.wg.Wait()
+	t.flush()
+}
+
+// flushLoop persists usage every usageFlushInterval
+func (t *quotaTracker) flushLoop() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			t.flush()
+		}
+	}
+}
+
+// load applies the limits of a user, reading this month's usage from the
+// database the first time the user is seen
+func (t *quotaTracker) load(user *User) (*userQuota, error) {
+	value, ok := t.users.Load(user.ID)
+	if !ok {
+		q := &userQuota{month: currentMonth()}
+		usage, err := t.db.ListUserUsage(user.ID, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(usage) > 0 && usage[0].Month == q.month {
+			q.used = usage[0].BytesSent + usage[0].BytesReceived
+		}
+		value, _ = t.users.LoadOrStore(user.ID, q)
+	}
+
+	q := value.(*userQuota)
+	q.mu.Lock()
+	q.username = user.Username
+	q.transferCap = user.TransferCap
+	q.bandwidth = nil
+	if user.MaxBandwidth > 0 {
+		rate := float64(user.MaxBandwidth) * 1024
+		// Allow one second of traffic at once, and always a full packet
+		q.bandwidth = newTokenBucket(rate, int(math.Max(rate, 65535)))
+	}
+	q.capLogged = false
+	q.mu.Unlock()
+
+	return q, nil
+}
+
+// forget drops the tracked state of a deleted user
+func (t *quotaTracker) forget(userID string) {
+	t.users.Delete(userID)
+}
+
+// exceeded reports whether the monthly transfer cap of a user is reached
+func (t *quotaTracker) exceeded(userID string) bool {
+	value, ok := t.users.Load(userID)
+	if !ok {
+		return false
+	}
+
+	q := value.(*userQuota)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover(currentMonth())
+	return q.transferCap > 0 && q.used >= q.transferCap
+}
+
+// allowSend accounts n bytes relayed from an agent of a user. It returns
+// an error if the packet must be dropped because of the user's quotas.
+func (t *quotaTracker) allowSend(userID string, n int) error {
+	value, ok := t.users.Load(userID)
+	if !ok {
+		return nil
+	}
+
+	q := value.(*userQuota)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover(currentMonth())
+	if q.transferCap > 0 && q.used >= q.transferCap {
+		if !q.capLogged {
+			log.Printf("User %s reached its monthly transfer cap of %d bytes", userID, q.transferCap)
+			q.capLogged = true
+		}
+		return errTransferCap
+	}
+	if q.bandwidth != nil {
+		if ok, _ := q.bandwidth.takeN(float64(n)); !ok {
+			return errBandwidthCap
+		}
+	}
+
+	t.account(userID, q, n)
+	q.sent += uint64(n)
+	return nil
+}
+
+// addReceived accounts n bytes relayed to an agent of a user
+func (t *quotaTracker) addReceived(userID string, n int) {
+	value, ok := t.users.Load(userID)
+	if !ok {
+		return
+	}
+
+	q := value.(*userQuota)
+	q.mu.Lock()
+	q.rollover(currentMonth())
+	t.account(userID, q, n)
+	q.received += uint64(n)
+	q.mu.Unlock()
+}
+
+// account adds n bytes to the monthly usage of a user and reports crossed
+// billing thresholds. Must be called with q.mu held.
+func (t *quotaTracker) account(userID string, q *userQuota, n int) {
+	prev := q.used
+	q.used += uint64(n)
+	if t.notifier != nil {
+		t.notifier.crossed(userID, q.username, q.month, prev, q.used, q.transferCap)
+	}
+}
+
+// rollover starts a new month, keeping unpersisted usage of the old one.
+// Must be called with q.mu held.
+func (q *userQuota) rollover(month string) {
+	if q.month == month {
+		return
+	}
+	if q.sent > 0 || q.received > 0 {
+		q.stale = append(q.stale, UserUsage{Month: q.month, BytesSent: q.sent, BytesReceived: q.received})
+	}
+	q.month = month
+	q.used, q.sent, q.received = 0, 0, 0
+	q.capLogged = false
+}
+
+// flush writes pending usage of all users to the database
+func (t *quotaTracker) flush() {
+	t.users.Range(func(key, value interface{}) bool {
+		userID := key.(string)
+		q := value.(*userQuota)
+
+		q.mu.Lock()
+		pending := q.stale
+		if q.sent > 0 || q.received > 0 {
+			pending = append(pending, UserUsage{Month: q.month, BytesSent: q.sent, BytesReceived: q.received})
+		}
+		q.stale = nil
+		q.sent, q.received = 0, 0
+		q.mu.Unlock()
+
+		for i, u := range pending {
+			if err := t.db.AddUserUsage(userID, u.Month, u.BytesSent, u.BytesReceived); err != nil {
+				log.Printf("Failed to persist usage of user %s: %v", userID, err)
+				// Keep the rest for the next flush
+				q.mu.Lock()
+				q.stale = append(pending[i:len(pending):len(pending)], q.stale...)
+				q.mu.Unlock()
+				break
+			}
+		}
+		return true
+	})
+}
+
+// currentMonth returns the current calendar month as YYYY-MM in UTC
+func currentMonth() string {
+	return time.Now().UTC().Format("2006-01")
+}
+
+// untilNextMonth returns the time until the next calendar month in UTC
+func untilNextMonth() time.Duration {
+	now := time.Now().UTC()
+	return time.Until(time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC))
+}
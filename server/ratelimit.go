@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/crypto"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfterHeader is the response metadata key telling clients how many
+// seconds to wait before retrying a RESOURCE_EXHAUSTED call
+const RetryAfterHeader = "retry-after"
+
+// tokenBucket is a simple token bucket rate limiter
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full bucket refilled at rate tokens per second
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = int(math.Ceil(rate))
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// take consumes a token. If none is available it returns false and the
+// time until the next token.
+func (b *tokenBucket) take() (bool, time.Duration) {
+	return b.takeN(1)
+}
+
+// takeN consumes n tokens. If not enough are available it returns false
+// and the time until they are.
+func (b *tokenBucket) takeN(n float64) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens >= n {
+		b.tokens -= n
+		return true, 0
+	}
+	return false, time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+}
+
+// resourceExhausted returns a RESOURCE_EXHAUSTED error, setting the
+// Retry-After response metadata when retryAfter is positive
+func resourceExhausted(ctx context.Context, retryAfter time.Duration, format string, args ...interface{}) error {
+	if retryAfter > 0 {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, fmt.Sprintf("%d", seconds)))
+	}
+	return status.Errorf(codes.ResourceExhausted, format, args...)
+}
+
+// LimitListener caps the number of concurrent connections per source IP.
+// Connections over the cap are closed right after accept.
+func LimitListener(l net.Listener, maxPerIP int) net.Listener {
+	if maxPerIP <= 0 {
+		return l
+	}
+	return &limitListener{
+		Listener: l,
+		maxPerIP: maxPerIP,
+		conns:    make(map[string]int),
+	}
+}
+
+type limitListener struct {
+	net.Listener
+	maxPerIP int
+
+	mu    sync.Mutex
+	conns map[string]int
+}
+
+// Accept returns the next connection within the per-IP cap
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		ip := remoteIP(conn.RemoteAddr())
+
+		l.mu.Lock()
+		if l.conns[ip] >= l.maxPerIP {
+			l.mu.Unlock()
+			log.Printf("Rejecting connection from %s: per-IP connection limit %d reached", ip, l.maxPerIP)
+			conn.Close()
+			continue
+		}
+		l.conns[ip]++
+		l.mu.Unlock()
+
+		return &limitConn{Conn: conn, release: func() { l.release(ip) }}, nil
+	}
+}
+
+// release decrements the connection count of ip
+func (l *limitListener) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.conns[ip]--
+	if l.conns[ip] <= 0 {
+		delete(l.conns, ip)
+	}
+}
+
+// limitConn releases its slot once when closed
+type limitConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
+
+// remoteIP returns the IP part of a remote address
+func remoteIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// SetHandshakeStats sets the source of QUIC handshake counters reported
+// to admins, normally the QUIC listener
+func (s *Server) SetHandshakeStats(stats func() crypto.HandshakeStats) {
+	s.handshakes = stats
+}
+
+// GetHandshakeStats returns QUIC handshake address validation counters
+func (s *Server) GetHandshakeStats(ctx context.Context, req *proto.GetHandshakeStatsRequest) (*proto.HandshakeStatsResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+	if s.handshakes == nil {
+		return nil, status.Errorf(codes.Unavailable, "handshake statistics are not available")
+	}
+
+	stats := s.handshakes()
+	return &proto.HandshakeStatsResponse{
+		Validated:      stats.Validated,
+		Unvalidated:    stats.Unvalidated,
+		Retries:        stats.Retries,
+		InvalidTokens:  stats.InvalidTokens,
+		RetryThreshold: int32(s.config.Security.RetryThreshold),
+		RetryActive:    stats.RetryActive,
+	}, nil
+}
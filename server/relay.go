@@ -0,0 +1,372 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// relayStream is one RelayData stream of a session. gRPC streams do not
+// allow concurrent sends, so sends are serialized.
+type relayStream struct {
+	stream proto.AgentService_RelayDataServer
+	pad    bool // the agent negotiated padded packets
+	mu     sync.Mutex
+
+	flow      flowStats
+	resources *sessionResources // of the session, sends pending on the stream count against it
+}
+
+// Send sends a packet on the stream, padded if the agent asked for it.
+// Forwarded packets drop the padding of their sender.
+func (r *relayStream) Send(packet *proto.DataPacket) error {
+	// Past its memory limit the agent loses the packet as if the link had
+	// dropped it
+	n := len(packet.Payload)
+	if !r.resources.hold(n) {
+		return errSessionMemory
+	}
+	defer r.resources.release(n)
+
+	r.flow.pending.Add(1)
+	defer r.flow.pending.Add(-1)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pad {
+		proto.Pad(packet)
+	} else {
+		packet.Padding = nil
+	}
+	defer r.flow.sending()()
+	return r.stream.Send(packet)
+}
+
+// addStream attaches a relay stream to the session, whose sends count as
+// slow when blocking longer than stall
+func (si *SessionInfo) addStream(stream proto.AgentService_RelayDataServer, stall time.Duration) *relayStream {
+	rs := &relayStream{
+		stream:    stream,
+		pad:       slices.Contains(si.capabilities, proto.CapabilityPadding),
+		flow:      flowStats{stall: stall},
+		resources: &si.resources,
+	}
+
+	si.mu.Lock()
+	si.streams = append(si.streams, rs)
+	si.mu.Unlock()
+
+	return rs
+}
+
+// removeStream detaches a relay stream and returns the number left
+func (si *SessionInfo) removeStream(rs *relayStream) int {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	for i, s := range si.streams {
+		if s == rs {
+			si.streams = append(si.streams[:i], si.streams[i+1:]...)
+			break
+		}
+	}
+	return len(si.streams)
+}
+
+// pickStream selects the relay stream for a packet. Packets of the same
+// flow always use the same stream so they are not reordered.
+func (si *SessionInfo) pickStream(payload []byte) *relayStream {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	switch len(si.streams) {
+	case 0:
+		return nil
+	case 1:
+		return si.streams[0]
+	}
+	return si.streams[flowHash(payload)%uint32(len(si.streams))]
+}
+
+// flowHash hashes the IPv4 addresses, protocol and TCP/UDP ports of a packet
+func flowHash(payload []byte) uint32 {
+	if len(payload) < 20 || payload[0]>>4 != 4 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	h.Write(payload[9:10])  // protocol
+	h.Write(payload[12:20]) // source and destination address
+
+	ihl := int(payload[0]&0x0f) * 4
+	if protocol := payload[9]; (protocol == 6 || protocol == 17) && len(payload) >= ihl+4 {
+		h.Write(payload[ihl : ihl+4]) // source and destination port
+	}
+
+	return h.Sum32()
+}
+
+// errNoRoute is returned when no session can take a packet
+var errNoRoute = errors.New("no route to destination")
+
+// Relay decisions recorded by the relay tracer
+const (
+	decisionForwarded    = "forwarded"
+	decisionNoRoute      = "no_route"
+	decisionTTLExceeded  = "ttl_exceeded"
+	decisionSendFailed   = "send_failed"
+	decisionEchoAnswered = "echo_answered"
+	decisionMalformed    = "malformed"
+	decisionACLDenied    = "acl_denied"
+
+	decisionMulticastDropped = "multicast_dropped"
+	decisionMulticastRelayed = "multicast_relayed"
+	decisionMulticastUnicast = "multicast_unicast"
+	decisionRateLimited      = "rate_limited"
+	decisionLoopDetected     = "loop_detected"
+	decisionMemoryDropped    = "memory_dropped"
+)
+
+// relayPacket forwards a packet received from rs, records sampled packets
+// with their routing decision in the relay tracer and counts the packet
+// for a staged rollout
+func (s *Server) relayPacket(si *SessionInfo, rs *relayStream, dp *proto.DataPacket) error {
+	trace := s.tracer.start(dp)
+	decision, destAgentID, err := s.forwardPacket(si, rs, dp)
+	s.tracer.finish(trace, decision, destAgentID, err)
+	s.countRollout(si, len(dp.Payload), decision, err)
+	return err
+}
+
+// forwardPacket routes a packet. The server acts as a hop: it decrements
+// the TTL and answers expired, unroutable or ACL denied packets with ICMP
+// errors from the gateway IP so ping and traceroute behave sensibly.
+func (s *Server) forwardPacket(si *SessionInfo, rs *relayStream, dp *proto.DataPacket) (string, string, error) {
+	if dp.Echo != nil {
+		return s.relayEcho(si, rs, dp)
+	}
+
+	// Drop oversized and malformed payloads before they reach a peer's TUN
+	h, err := packet.Validate(dp.Payload, si.mtu)
+	if err != nil {
+		return decisionMalformed, "", fmt.Errorf("dropping %d byte payload: %w", len(dp.Payload), err)
+	}
+
+	allowed, ruleID, err := s.aclAllows(si, dp, h)
+	if err != nil {
+		return decisionACLDenied, "", fmt.Errorf("dropping packet, ACL rules unavailable: %w", err)
+	}
+	if !allowed {
+		s.sendICMPError(rs, dp, packet.ICMPUnreachable, packet.ICMPAdminProhibited)
+		return decisionACLDenied, "", fmt.Errorf("%s -> %s denied by ACL rule %d", h.Src, h.Dst, ruleID)
+	}
+
+	// Broadcast and multicast stay on the overlay link, they are not routed
+	if s.isMulticast(h.Dst) {
+		decision, err := s.forwardMulticast(si, dp, h)
+		return decision, "", err
+	}
+
+	// Drop tracked packets the agent they were relayed to sent back, and
+	// quarantine the rules of a loop once it is certain
+	fp, tracked := s.loops.sample(dp.Payload, h)
+	if tracked {
+		if loop, looped := s.loops.received(fp, si.AgentID, h.Dst); looped {
+			if loop != nil {
+				s.wg.Add(1)
+				go s.quarantineLoop(loop)
+			}
+			return decisionLoopDetected, "", fmt.Errorf("%s -> %s looped through %s", h.Src, h.Dst, si.AgentID)
+		}
+	}
+
+	if h.TTL <= 1 {
+		s.sendICMPError(rs, dp, packet.ICMPTimeExceeded, packet.ICMPTTLExceeded)
+		return decisionTTLExceeded, "", nil
+	}
+	packet.DecrementTTL(dp.Payload)
+
+	// Packets for a site LAN go to the gateway advertising it, others to
+	// the gateway the forward rules of the sender pick
+	if dp.DestinationAgentId == "" {
+		if dst, ok := netip.AddrFromSlice(h.Dst); ok {
+			if site := s.sites.lookup(si.UserID, dst.Unmap(), si.AgentID); site != "" && s.readyGateway(site) {
+				dp.DestinationAgentId = site
+			}
+			if dp.DestinationAgentId == "" {
+				dp.DestinationAgentId = s.routeGateway(si, dp.Payload, dst.Unmap())
+			}
+		}
+	}
+
+	destAgentID, err := s.routePacket(dp)
+	if errors.Is(err, errNoRoute) {
+		s.sendICMPError(rs, dp, packet.ICMPUnreachable, packet.ICMPNetUnreachable)
+	}
+	if tracked && err == nil {
+		s.loops.sent(fp, si.AgentID, destAgentID)
+	}
+	return routeDecision(err), destAgentID, err
+}
+
+// routeDecision maps a routePacket error to a relay decision
+func routeDecision(err error) string {
+	switch {
+	case err == nil:
+		return decisionForwarded
+	case errors.Is(err, errNoRoute):
+		return decisionNoRoute
+	case errors.Is(err, errSessionMemory):
+		return decisionMemoryDropped
+	default:
+		return decisionSendFailed
+	}
+}
+
+// sendICMPError sends an ICMP error about dp back to its sender
+func (s *Server) sendICMPError(rs *relayStream, dp *proto.DataPacket, icmpType, code uint8) {
+	gatewayIP := net.ParseIP(s.config.Network.GatewayIP)
+	if gatewayIP == nil {
+		return
+	}
+
+	reply, err := packet.ICMPError(gatewayIP, dp.Payload, icmpType, code)
+	if err != nil || reply == nil {
+		return
+	}
+
+	if err := rs.Send(&proto.DataPacket{
+		SessionId:          dp.SessionId,
+		DestinationAgentId: dp.SourceAgentId,
+		Payload:            reply,
+	}); err != nil {
+		log.Printf("Failed to send ICMP error: %v", err)
+	}
+}
+
+// relayEcho forwards an overlay echo probe or its reply, answering
+// directly for the gateway IP and for unreachable targets. Probes only
+// reach agents of the sender's user and are subject to its ACL rules like
+// ICMP echoes between the two overlay IPs. They carry no payload, and the
+// sender is the agent of the session whatever the packet claims.
+func (s *Server) relayEcho(si *SessionInfo, rs *relayStream, dp *proto.DataPacket) (string, string, error) {
+	dp.SourceAgentId = si.AgentID
+	dp.Payload = nil
+	dp.Echo.Error = ""
+
+	if dp.Echo.Reply {
+		target := s.echoPeer(si.UserID, dp.DestinationAgentId)
+		if target == nil {
+			return decisionNoRoute, "", fmt.Errorf("dropping echo reply to unknown agent %s", dp.DestinationAgentId)
+		}
+		if decision, err := s.echoAllowed(si, dp, target.IPAddress); err != nil {
+			return decision, "", err
+		}
+		destAgentID, err := s.routePacket(dp)
+		return routeDecision(err), destAgentID, err
+	}
+
+	target := dp.Echo.Target
+	if target == s.config.Network.GatewayIP {
+		reply := echoReply(dp, "")
+		reply.Echo.Address = s.config.Network.GatewayIP
+		return decisionEchoAnswered, "", rs.Send(reply)
+	}
+
+	ai := s.resolveEchoTarget(si.UserID, target)
+	if ai == nil {
+		return decisionNoRoute, "", rs.Send(echoReply(dp, "unknown target "+target))
+	}
+	if decision, err := s.echoAllowed(si, dp, ai.IPAddress); err != nil {
+		if sendErr := rs.Send(echoReply(dp, "target "+target+" is not reachable")); sendErr != nil {
+			log.Printf("Failed to send echo reply: %v", sendErr)
+		}
+		return decision, "", err
+	}
+
+	// The target echoes its address back, resolving names for the sender
+	dp.DestinationAgentId = ai.AgentID
+	dp.Echo.Address = ai.IPAddress
+	destAgentID, err := s.routePacket(dp)
+	if errors.Is(err, errNoRoute) {
+		return decisionNoRoute, ai.AgentID, rs.Send(echoReply(dp, "target "+target+" is not connected"))
+	}
+	return routeDecision(err), destAgentID, err
+}
+
+// echoAllowed checks an echo probe of a session to the overlay IP address
+// against the ACL rules as an ICMP packet, and returns the relay decision
+// and error of a denied probe
+func (s *Server) echoAllowed(si *SessionInfo, dp *proto.DataPacket, address string) (string, error) {
+	h := &packet.IPv4{
+		Protocol: packet.ProtocolICMP,
+		Src:      net.IP(si.ip.AsSlice()),
+		Dst:      net.ParseIP(address),
+	}
+	if h.Dst == nil {
+		return decisionNoRoute, errors.New("dropping echo probe, agent has no overlay IP")
+	}
+
+	allowed, ruleID, err := s.aclAllows(si, dp, h)
+	if err != nil {
+		return decisionACLDenied, fmt.Errorf("dropping echo probe, ACL rules unavailable: %w", err)
+	}
+	if !allowed {
+		return decisionACLDenied, fmt.Errorf("echo %s -> %s denied by ACL rule %d", h.Src, h.Dst, ruleID)
+	}
+	return "", nil
+}
+
+// resolveEchoTarget finds the agent of a user with the given overlay IP or
+// name, nil if there is none
+func (s *Server) resolveEchoTarget(userID, target string) *AgentInfo {
+	var found *AgentInfo
+	s.agents.Range(func(key, value interface{}) bool {
+		ai := value.(*AgentInfo)
+		if ai.UserID != userID {
+			return true
+		}
+		if ai.IPAddress == target || (ai.Metadata != nil && ai.Metadata.Hostname == target) {
+			found = ai
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// echoPeer returns the agent of a user an echo reply is addressed to, nil
+// if it belongs to another user or is not known
+func (s *Server) echoPeer(userID, agentID string) *AgentInfo {
+	value, ok := s.agents.Load(agentID)
+	if !ok {
+		return nil
+	}
+	if ai := value.(*AgentInfo); ai.UserID == userID {
+		return ai
+	}
+	return nil
+}
+
+// echoReply builds the server's reply to an echo probe
+func echoReply(dp *proto.DataPacket, errMsg string) *proto.DataPacket {
+	return &proto.DataPacket{
+		SessionId:          dp.SessionId,
+		DestinationAgentId: dp.SourceAgentId,
+		Echo: &proto.EchoProbe{
+			Id:     dp.Echo.Id,
+			Reply:  true,
+			SentAt: dp.Echo.SentAt,
+			Error:  errMsg,
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// errRelayQueueFull is returned when a packet arrives while the queue of
+// its relay worker is full
+var errRelayQueueFull = errors.New("relay queue full")
+
+// relayJob is a received packet waiting to be routed
+type relayJob struct {
+	si *SessionInfo
+	rs *relayStream
+	dp *proto.DataPacket
+}
+
+// relayPool routes received packets on a fixed set of workers, so a burst
+// on one stream does not hold up routing for the others. Packets of a flow
+// always go to the same worker and stay in order, and each worker routes
+// interactive traffic ahead of bulk transfers.
+type relayPool struct {
+	queues   []*packet.ClassQueue[relayJob]
+	counters packet.ClassCounters
+	dropped  atomic.Uint64
+}
+
+// newRelayPool creates the class queues of queueLen packets of each relay
+// worker
+func newRelayPool(workers, queueLen int) *relayPool {
+	p := &relayPool{queues: make([]*packet.ClassQueue[relayJob], workers)}
+	for i := range p.queues {
+		p.queues[i] = packet.NewClassQueue[relayJob](queueLen, &p.counters)
+	}
+	return p
+}
+
+// submit queues a packet without blocking the receiving stream, dropping
+// it when its worker is overloaded
+func (p *relayPool) submit(job relayJob) error {
+	class := packet.Classify(job.dp.Payload)
+	if job.dp.Echo != nil {
+		class = packet.ClassInteractive // latency probes
+	}
+	if p.queues[p.shard(job)].Push(class, job) {
+		return nil
+	}
+
+	// Log the first drop and then periodically, overload would flood the log
+	if n := p.dropped.Add(1); n == 1 || n%1000 == 0 {
+		log.Printf("Relay queue full, %d packets dropped so far", n)
+	}
+	return errRelayQueueFull
+}
+
+// shard selects the worker of a packet from its session and flow
+func (p *relayPool) shard(job relayJob) int {
+	h := fnv.New32a()
+	h.Write([]byte(job.si.SessionID))
+	return int((h.Sum32() ^ flowHash(job.dp.Payload)) % uint32(len(p.queues)))
+}
+
+// relayWorker routes the packets of one relay queue until the server closes
+func (s *Server) relayWorker(queue *packet.ClassQueue[relayJob]) {
+	defer s.wg.Done()
+
+	for {
+		job, ok := queue.Pop(s.done)
+		if !ok {
+			return
+		}
+
+		// Packets queued before the session was terminated are dropped
+		if job.si.ctx.Err() != nil {
+			job.si.resources.release(len(job.dp.Payload))
+			continue
+		}
+		err := s.relayPacket(job.si, job.rs, job.dp)
+		job.si.resources.release(len(job.dp.Payload))
+		s.alerts.relayResult(err)
+		// Memory drops are counted per session, not logged one by one
+		if err != nil && !errors.Is(err, errSessionMemory) {
+			log.Printf("Failed to route packet: %v", err)
+		}
+	}
+}
+
+// GetRelayQueueStats reports the per traffic class counters of the relay
+// workers, the decisions of the multicast policy and the slow consumers
+func (s *Server) GetRelayQueueStats(ctx context.Context, req *proto.GetRelayQueueStatsRequest) (*proto.RelayQueueStatsResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+	return &proto.RelayQueueStatsResponse{
+		Classes:                 trafficClassStats(s.relay.counters.Stats()),
+		Multicast:               s.multicast.stats(s.config.Network.Multicast),
+		SlowConsumers:           s.slowConsumers(time.Now()),
+		SlowConsumersTerminated: s.slowEnded.Load(),
+	}, nil
+}
+
+// trafficClassStats converts traffic class counters to their proto form
+func trafficClassStats(stats []packet.ClassStats) []*proto.TrafficClassStats {
+	result := make([]*proto.TrafficClassStats, 0, len(stats))
+	for _, cs := range stats {
+		result = append(result, &proto.TrafficClassStats{
+			Class:   cs.Class.String(),
+			Queued:  cs.Queued,
+			Dropped: cs.Dropped,
+		})
+	}
+	return result
+}
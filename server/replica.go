@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// replicaPingTimeout bounds a replica health check
+const replicaPingTimeout = 5 * time.Second
+
+// replica is a read replica of the database
+type replica struct {
+	addr    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// replicaSet balances reads across healthy replicas and health checks
+// them periodically, so failed replicas are taken out of rotation and
+// return once they recover
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint32
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newReplicaSet opens the configured replicas and starts health checking.
+// Unreachable replicas are not an error; reads go to the primary until
+// they recover.
+func newReplicaSet(cfg config.DatabaseConfig) (*replicaSet, error) {
+	rs := &replicaSet{done: make(chan struct{})}
+
+	for _, rc := range cfg.Replicas {
+		user, password := rc.User, rc.Password
+		if user == "" {
+			user, password = cfg.User, cfg.Password
+		}
+		port := rc.Port
+		if port == 0 {
+			port = cfg.Port
+		}
+
+		db, err := openDB(cfg, rc.Host, port, user, password)
+		if err != nil {
+			rs.close()
+			return nil, fmt.Errorf("failed to open replica %s: %w", rc.Host, err)
+		}
+		rs.replicas = append(rs.replicas, &replica{
+			addr: net.JoinHostPort(rc.Host, strconv.Itoa(port)),
+			db:   db,
+		})
+	}
+
+	rs.check()
+	for _, r := range rs.replicas {
+		if !r.healthy.Load() {
+			log.Printf("Database replica %s is unreachable, reading from primary", r.addr)
+		}
+	}
+
+	rs.wg.Add(1)
+	go rs.checkLoop(time.Duration(cfg.HealthCheck) * time.Second)
+
+	return rs, nil
+}
+
+// pick returns the next healthy replica, or nil if there is none
+func (rs *replicaSet) pick() *replica {
+	if rs == nil {
+		return nil
+	}
+
+	n := uint32(len(rs.replicas))
+	start := rs.next.Add(1)
+	for i := uint32(0); i < n; i++ {
+		if r := rs.replicas[(start+i)%n]; r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// failed takes a replica out of rotation after a failed query until the
+// next successful health check
+func (rs *replicaSet) failed(r *replica, err error) {
+	if r.healthy.CompareAndSwap(true, false) {
+		log.Printf("Database replica %s failed, reading from primary: %v", r.addr, err)
+	}
+}
+
+// checkLoop health checks the replicas every interval
+func (rs *replicaSet) checkLoop(interval time.Duration) {
+	defer rs.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rs.done:
+			return
+		case <-ticker.C:
+			rs.check()
+		}
+	}
+}
+
+// check pings every replica and updates its health
+func (rs *replicaSet) check() {
+	for _, r := range rs.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaPingTimeout)
+		err := r.db.PingContext(ctx)
+		cancel()
+
+		switch {
+		case err == nil && r.healthy.CompareAndSwap(false, true):
+			log.Printf("Database replica %s is healthy", r.addr)
+		case err != nil:
+			rs.failed(r, err)
+		}
+	}
+}
+
+// close stops health checking and closes the replica connections
+func (rs *replicaSet) close() {
+	if rs == nil {
+		return
+	}
+
+	close(rs.done)
+	rs.wg.Wait()
+	for _, r := range rs.replicas {
+		r.db.Close()
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"errors"
+	"sync/atomic"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// errSessionMemory is returned for a packet dropped because its session
+// holds network.max_session_memory_kb of packets already
+var errSessionMemory = errors.New("session memory limit reached")
+
+// sessionResources accounts the goroutines and packet buffers a session
+// holds, so that leaking sessions show and none can hold more packets than
+// network.max_session_memory_kb
+type sessionResources struct {
+	goroutines atomic.Int32
+	buffered   atomic.Int64 // bytes of packets queued for relay or pending sends to the agent
+	drops      atomic.Uint64
+	limit      int64 // bytes, 0 for unlimited
+}
+
+// hold accounts n bytes of a packet buffered for the session, false if it
+// would exceed the limit and must be dropped
+func (r *sessionResources) hold(n int) bool {
+	if r.limit <= 0 {
+		r.buffered.Add(int64(n))
+		return true
+	}
+	for {
+		buffered := r.buffered.Load()
+		if buffered+int64(n) > r.limit {
+			r.drops.Add(1)
+			return false
+		}
+		if r.buffered.CompareAndSwap(buffered, buffered+int64(n)) {
+			return true
+		}
+	}
+}
+
+// release returns n bytes accounted by hold
+func (r *sessionResources) release(n int) {
+	r.buffered.Add(-int64(n))
+}
+
+// spawned accounts a goroutine serving the session and returns the
+// function to call when it ends
+func (r *sessionResources) spawned() func() {
+	r.goroutines.Add(1)
+	return func() { r.goroutines.Add(-1) }
+}
+
+// stats returns the proto form of the accounting
+func (r *sessionResources) stats() *proto.SessionResources {
+	return &proto.SessionResources{
+		Goroutines:       r.goroutines.Load(),
+		BufferedBytes:    r.buffered.Load(),
+		MemoryLimitBytes: r.limit,
+		MemoryDrops:      r.drops.Load(),
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"log"
+	"path"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// permission is what an admin API call needs of the role of its caller.
+// A role has the permissions up to its own.
+type permission int
+
+const (
+	permRead      permission = iota + 1 // view agents, rules, users, statistics and the audit log
+	permOperate                         // handle agents: approve, archive, reset identity, trace relays
+	permConfigure                       // change rules, groups, rollouts, users and runtime settings
+	permOwn                             // manage the accounts of staff, users with a role other than user
+)
+
+// staffRoles maps the roles that may use the admin API to their highest
+// permission
+var staffRoles = map[string]permission{
+	"auditor": permRead,
+	"support": permOperate,
+	"admin":   permConfigure,
+	"owner":   permOwn,
+}
+
+// rolePermits reports whether a role has a permission; roles other than
+// the staff roles have none
+func rolePermits(role string, need permission) bool {
+	return staffRoles[role] >= need
+}
+
+// maxAuditAction is the length of the action column of audit_logs
+const maxAuditAction = 100
+
+// defaultAuditLogLimit is the number of entries ListAuditLog returns by
+// default
+const defaultAuditLogLimit = 100
+
+// auditActionKey is the context key naming an admin call that is not a
+// gRPC method, e.g. a SCIM request
+type auditActionKey struct{}
+
+// adminAction names the admin call of a context: the action it carries,
+// else its gRPC method
+func adminAction(ctx context.Context) string {
+	if action, ok := ctx.Value(auditActionKey{}).(string); ok {
+		return action
+	}
+	if method, ok := grpc.Method(ctx); ok && method != "" {
+		return method
+	}
+	// Calls through the REST gateway run in-process
+	if method, ok := runtime.RPCMethod(ctx); ok {
+		return method
+	}
+	return "unknown"
+}
+
+// audit records an admin call by a principal. The calls a role permits are
+// recorded before they run, whether they then succeed or not.
+func (s *Server) audit(ctx context.Context, user *User, action string, allowed bool) {
+	if !allowed {
+		log.Printf("%s denied to %s (%s)", action, user.Username, user.Role)
+	}
+	entry := &AuditLog{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		Action:   action[:min(len(action), maxAuditAction)],
+		SourceIP: s.clientIP(ctx),
+		Allowed:  allowed,
+	}
+	if err := s.db.AddAuditLog(entry); err != nil {
+		log.Printf("Failed to audit %s by %s: %v", action, user.Username, err)
+	}
+}
+
+// authorizeAccountChange checks that only owners change the accounts of
+// staff or give a user a staff role
+func (s *Server) authorizeAccountChange(ctx context.Context, admin *User, roles ...string) error {
+	if rolePermits(admin.Role, permOwn) {
+		return nil
+	}
+	for _, role := range roles {
+		if _, staff := staffRoles[role]; staff {
+			s.audit(ctx, admin, adminAction(ctx), false)
+			return status.Errorf(codes.PermissionDenied, "only owners manage %s accounts", role)
+		}
+	}
+	return nil
+}
+
+// ListAuditLog lists the admin calls that changed something or were
+// denied, newest first
+func (s *Server) ListAuditLog(ctx context.Context, req *proto.ListAuditLogRequest) (*proto.ListAuditLogResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultAuditLogLimit
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	filter := AuditLogFilter{Username: req.Username, Limit: limit}
+	if req.Since != nil {
+		filter.Since = req.Since.AsTime()
+	}
+
+	entries, err := s.db.ListAuditLogs(filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list audit log: %v", err)
+	}
+
+	resp := &proto.ListAuditLogResponse{Entries: make([]*proto.AuditEntry, 0, len(entries))}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &proto.AuditEntry{
+			EntryId:  e.ID,
+			Time:     timestamppb.New(e.CreatedAt),
+			UserId:   e.UserID,
+			Username: e.Username,
+			Role:     e.Role,
+			Action:   e.Action,
+			Allowed:  e.Allowed,
+			SourceIp: e.SourceIP,
+		})
+	}
+	return resp, nil
+}
+
+// deniedMessage describes a call the role of its caller does not permit
+func deniedMessage(role, action string) string {
+	if _, staff := staffRoles[role]; !staff {
+		return "admin role required"
+	}
+	return "role " + role + " does not permit " + path.Base(action)
+}
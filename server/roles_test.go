@@ -0,0 +1,26 @@
+package server
+
+import "testing"
+
+func TestRolePermits(t *testing.T) {
+	tests := []struct {
+		role string
+		want [4]bool // permRead, permOperate, permConfigure, permOwn
+	}{
+		{"owner", [4]bool{true, true, true, true}},
+		{"admin", [4]bool{true, true, true, false}},
+		{"support", [4]bool{true, true, false, false}},
+		{"auditor", [4]bool{true, false, false, false}},
+		{"user", [4]bool{false, false, false, false}},
+		{"", [4]bool{false, false, false, false}},
+		{"Admin", [4]bool{false, false, false, false}},
+	}
+	perms := []permission{permRead, permOperate, permConfigure, permOwn}
+	for _, tt := range tests {
+		for i, need := range perms {
+			if got := rolePermits(tt.role, need); got != tt.want[i] {
+				t.Errorf("%q with permission %d: got %v, want %v", tt.role, need, got, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,608 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultRolloutLimit is the number of rollouts listed by default
+const defaultRolloutLimit = 20
+
+// rolloutCohort counts the packets relayed from the agents of one side of
+// a staged rollout
+type rolloutCohort struct {
+	packets atomic.Uint64
+	bytes   atomic.Uint64
+	errors  atomic.Uint64
+	denied  atomic.Uint64
+}
+
+// rolloutMembership is the side of a staged rollout an agent is on.
+// Agents the changed rule does not apply to are out of scope and not
+// counted.
+type rolloutMembership struct {
+	rolloutID int
+	inScope   bool
+	canary    bool
+}
+
+// rolloutTracker keeps the staged rollout and the traffic of its canary
+// and of the other agents in scope, counted by this server instance
+type rolloutTracker struct {
+	staged *ttlCache // "staged" -> *Rollout, nil if none is staged
+
+	mu      sync.Mutex
+	cohorts map[int]*[2]rolloutCohort // rolloutID -> canary, baseline
+}
+
+// newRolloutTracker creates a rollout tracker. A change staged through
+// another server instance applies here within aclCacheTTL.
+func newRolloutTracker() *rolloutTracker {
+	return &rolloutTracker{
+		staged:  newTTLCache(aclCacheTTL),
+		cohorts: make(map[int]*[2]rolloutCohort),
+	}
+}
+
+// cohort returns the counters of one side of a rollout
+func (t *rolloutTracker) cohort(rolloutID int, canary bool) *rolloutCohort {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cohorts := t.cohorts[rolloutID]
+	if cohorts == nil {
+		cohorts = &[2]rolloutCohort{}
+		t.cohorts[rolloutID] = cohorts
+	}
+	if canary {
+		return &cohorts[0]
+	}
+	return &cohorts[1]
+}
+
+// keep drops the counters of rollouts other than the staged one
+func (t *rolloutTracker) keep(r *Rollout) {
+	t.mu.Lock()
+	for id := range t.cohorts {
+		if r == nil || id != r.ID {
+			delete(t.cohorts, id)
+		}
+	}
+	t.mu.Unlock()
+}
+
+// stagedRollout returns the staged rollout, nil if there is none
+func (s *Server) stagedRollout() *Rollout {
+	if cached, ok := s.rollouts.staged.get("staged"); ok {
+		return cached.(*Rollout)
+	}
+	r, err := s.db.GetStagedRollout()
+	if err != nil {
+		// Agents keep the promoted rules until the next attempt
+		log.Printf("Failed to get staged rollout: %v", err)
+	}
+	s.rollouts.staged.set("staged", r)
+	s.rollouts.keep(r)
+	return r
+}
+
+// rolloutMember reports whether a rollout's rule applies to an agent and
+// whether the agent is in the canary: a member of the canary group, or
+// in the share of agents picked by hashing the rollout and agent IDs,
+// so the canary stays the same across reconnects
+func rolloutMember(r *Rollout, agent *Agent) (inScope, canary bool) {
+	switch {
+	case r.Kind == "acl":
+		inScope = agent.UserID == r.ACL.UserID
+	case r.Routing.GroupID != 0:
+		inScope = agent.GroupID == r.Routing.GroupID
+	default:
+		inScope = agent.ID == r.Routing.AgentID
+	}
+	if !inScope {
+		return false, false
+	}
+
+	if r.GroupID != 0 {
+		return true, agent.GroupID == r.GroupID
+	}
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d/%s", r.ID, agent.ID)
+	return true, int(h.Sum32()%100) < r.Percent
+}
+
+// sessionRollout returns the staged rollout and the side the agent of a
+// session is on, nil if no rollout is staged
+func (s *Server) sessionRollout(si *SessionInfo) (*Rollout, *rolloutMembership) {
+	r := s.stagedRollout()
+	if r == nil {
+		return nil, nil
+	}
+	if m := si.rollout.Load(); m != nil && m.rolloutID == r.ID {
+		return r, m
+	}
+
+	m := &rolloutMembership{rolloutID: r.ID}
+	agent, err := s.db.GetAgentByID(si.AgentID)
+	if err != nil {
+		log.Printf("Failed to place agent %s in rollout %d: %v", si.AgentID, r.ID, err)
+		return r, m
+	}
+	m.inScope, m.canary = rolloutMember(r, agent)
+	si.rollout.Store(m)
+	return r, m
+}
+
+// countRollout counts a packet relayed from a session for the side of the
+// staged rollout its agent is on
+func (s *Server) countRollout(si *SessionInfo, n int, decision string, err error) {
+	r, m := s.sessionRollout(si)
+	if r == nil || !m.inScope {
+		return
+	}
+	c := s.rollouts.cohort(r.ID, m.canary)
+	c.packets.Add(1)
+	c.bytes.Add(uint64(n))
+	switch {
+	case decision == decisionACLDenied:
+		c.denied.Add(1)
+	case err != nil:
+		c.errors.Add(1)
+	}
+}
+
+// sessionACLMatchers returns the prepared ACL rules that apply to the
+// agent of a session, with the staged change applied for canary agents
+func (s *Server) sessionACLMatchers(si *SessionInfo) ([]*aclMatcher, error) {
+	r, m := s.sessionRollout(si)
+	if r == nil || r.Kind != "acl" || !m.canary {
+		return s.aclMatchers(si.UserID)
+	}
+
+	key := "canary:" + strconv.Itoa(r.ID)
+	if cached, ok := s.acls.get(key); ok {
+		return cached.([]*aclMatcher), nil
+	}
+
+	rules, err := s.db.ListACLRules(si.UserID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	matchers := prepareACLMatchers(proposedACLRules(rules, r))
+	s.acls.set(key, matchers)
+	return matchers, nil
+}
+
+// proposedACLRules applies the change of an ACL rollout to the enabled
+// rules of its user, in evaluation order
+func proposedACLRules(rules []*ACLRule, r *Rollout) []*ACLRule {
+	rules = applyRollout(rules, r.Operation, r.RuleID, r.ACL, func(rule *ACLRule) int { return rule.ID })
+	rules = slices.DeleteFunc(rules, func(rule *ACLRule) bool { return !rule.Enabled })
+	slices.SortStableFunc(rules, func(a, b *ACLRule) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.ID, b.ID))
+	})
+	return rules
+}
+
+// canaryRoutingRules applies the staged routing change to the rules of an
+// agent in its canary
+func (s *Server) canaryRoutingRules(agent *Agent, rules []*RoutingRule) []*RoutingRule {
+	r := s.stagedRollout()
+	if r == nil || r.Kind != "routing" {
+		return rules
+	}
+	if _, canary := rolloutMember(r, agent); !canary {
+		return rules
+	}
+	return proposedRoutingRules(rules, r)
+}
+
+// proposedRoutingRules applies the change of a routing rollout to the
+// enabled rules of an agent it applies to, in the order agents get them
+func proposedRoutingRules(rules []*RoutingRule, r *Rollout) []*RoutingRule {
+	rules = applyRollout(rules, r.Operation, r.RuleID, r.Routing, func(rule *RoutingRule) int { return rule.ID })
+	rules = slices.DeleteFunc(rules, func(rule *RoutingRule) bool { return !rule.Enabled })
+	// Agents order rules by priority, an agent's own rule first
+	slices.SortStableFunc(rules, func(a, b *RoutingRule) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(min(a.GroupID, 1), min(b.GroupID, 1)))
+	})
+	return rules
+}
+
+// applyRollout applies a staged operation to a copy of a list of enabled
+// rules. An updated rule that is disabled now is not in the list yet.
+func applyRollout[T any](rules []*T, operation string, ruleID int, rule *T, id func(*T) int) []*T {
+	rules = slices.Clone(rules)
+	switch operation {
+	case "add":
+		return append(rules, rule)
+	case "update":
+		i := slices.IndexFunc(rules, func(r *T) bool { return id(r) == ruleID })
+		if i < 0 {
+			return append(rules, rule)
+		}
+		rules[i] = rule
+		return rules
+	default:
+		return slices.DeleteFunc(rules, func(r *T) bool { return id(r) == ruleID })
+	}
+}
+
+// notifyRollout has the agents a rollout's rule applies to pick up its
+// change, or drop it again
+func (s *Server) notifyRollout(r *Rollout) {
+	s.rollouts.staged.delete("staged")
+	if r.Kind == "acl" {
+		s.acls.delete(r.ACL.UserID)
+		s.acls.delete("canary:" + strconv.Itoa(r.ID))
+		return
+	}
+	s.notifyRuleChange(r.Routing)
+}
+
+// StageRollout stages a routing or ACL rule change to a canary of agents
+func (s *Server) StageRollout(ctx context.Context, req *proto.StageRolloutRequest) (*proto.Rollout, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Rollout{
+		Kind:      req.Kind,
+		Operation: req.Operation,
+		Percent:   int(req.Percent),
+		GroupID:   int(req.CanaryGroupId),
+		State:     "staged",
+		CreatedBy: admin.Username,
+	}
+
+	switch {
+	case r.GroupID != 0:
+		if r.Percent != 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "percent and canary_group_id are exclusive")
+		}
+		if _, err := s.db.GetAgentGroup(r.GroupID); err != nil {
+			return nil, status.Errorf(codes.NotFound, "agent group %d not found", r.GroupID)
+		}
+	case r.Percent < 1 || r.Percent > 100:
+		return nil, status.Errorf(codes.InvalidArgument, "percent must be between 1 and 100")
+	}
+
+	switch req.Kind {
+	case "routing":
+		err = s.stageRoutingChange(r, req)
+	case "acl":
+		err = s.stageACLChange(r, req)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "kind must be routing or acl")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	staged, err := s.db.GetStagedRollout()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get staged rollout: %v", err)
+	}
+	if staged != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "rollout %d is staged, promote or roll it back first", staged.ID)
+	}
+
+	if err := s.db.CreateRollout(r); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create rollout: %v", err)
+	}
+	s.notifyRollout(r)
+
+	log.Printf("Rollout %d staged by %s: %s %s rule %s to %s", r.ID, admin.Username, r.Operation, r.Kind,
+		rolloutRule(r), rolloutCanary(r))
+
+	created, err := s.db.GetRollout(r.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get rollout: %v", err)
+	}
+	return s.rolloutToProto(created), nil
+}
+
+// stageRoutingChange validates a staged routing rule change like the
+// routing rule RPCs do
+func (s *Server) stageRoutingChange(r *Rollout, req *proto.StageRolloutRequest) error {
+	if req.RoutingRule == nil {
+		return status.Errorf(codes.InvalidArgument, "routing_rule is required")
+	}
+
+	if r.Operation == "add" {
+		switch {
+		case (req.AgentId == "") == (req.GroupId == 0):
+			return status.Errorf(codes.InvalidArgument, "exactly one of agent_id and group_id is required")
+		case req.GroupId != 0:
+			if _, err := s.db.GetAgentGroup(int(req.GroupId)); err != nil {
+				return status.Errorf(codes.NotFound, "agent group %d not found", req.GroupId)
+			}
+		default:
+			if _, err := s.db.GetAgentByID(req.AgentId); err != nil {
+				return status.Errorf(codes.NotFound, "agent %s not found", req.AgentId)
+			}
+		}
+		rule, err := s.validateRoutingRule(req.AgentId, int(req.GroupId), 0, req.RoutingRule)
+		r.Routing = rule
+		return err
+	}
+
+	existing, err := s.db.GetRoutingRuleByID(int(req.RoutingRule.RuleId))
+	if err != nil {
+		return status.Errorf(codes.NotFound, "routing rule %d not found", req.RoutingRule.RuleId)
+	}
+	r.RuleID = existing.ID
+
+	switch r.Operation {
+	case "update":
+		rule, err := s.validateRoutingRule(existing.AgentID, existing.GroupID, existing.ID, req.RoutingRule)
+		if err != nil {
+			return err
+		}
+		rule.ID = existing.ID
+		r.Routing = rule
+	case "delete":
+		r.Routing = existing
+	default:
+		return status.Errorf(codes.InvalidArgument, "operation must be add, update or delete")
+	}
+	return nil
+}
+
+// stageACLChange validates a staged ACL rule change like the ACL rule
+// RPCs do
+func (s *Server) stageACLChange(r *Rollout, req *proto.StageRolloutRequest) error {
+	if req.AclRule == nil {
+		return status.Errorf(codes.InvalidArgument, "acl_rule is required")
+	}
+
+	if r.Operation == "add" {
+		rule, err := s.validateACLRule(req.AclRule)
+		if err != nil {
+			return err
+		}
+		if _, err := s.db.GetUserByID(rule.UserID); err != nil {
+			return status.Errorf(codes.NotFound, "user %s not found", rule.UserID)
+		}
+		r.ACL = rule
+		return nil
+	}
+
+	existing, err := s.db.GetACLRuleByID(int(req.AclRule.RuleId))
+	if err != nil {
+		return status.Errorf(codes.NotFound, "ACL rule %d not found", req.AclRule.RuleId)
+	}
+	r.RuleID = existing.ID
+
+	switch r.Operation {
+	case "update":
+		if req.AclRule.UserId != "" && req.AclRule.UserId != existing.UserID {
+			return status.Errorf(codes.InvalidArgument, "the user of an ACL rule cannot be changed")
+		}
+		req.AclRule.UserId = existing.UserID
+		rule, err := s.validateACLRule(req.AclRule)
+		if err != nil {
+			return err
+		}
+		rule.ID = existing.ID
+		r.ACL = rule
+	case "delete":
+		r.ACL = existing
+	default:
+		return status.Errorf(codes.InvalidArgument, "operation must be add, update or delete")
+	}
+	return nil
+}
+
+// ListRollouts returns the latest rollouts, newest first
+func (s *Server) ListRollouts(ctx context.Context, req *proto.ListRolloutsRequest) (*proto.ListRolloutsResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultRolloutLimit
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	rollouts, err := s.db.ListRollouts(limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list rollouts: %v", err)
+	}
+	resp := &proto.ListRolloutsResponse{Rollouts: make([]*proto.Rollout, 0, len(rollouts))}
+	for _, r := range rollouts {
+		resp.Rollouts = append(resp.Rollouts, s.rolloutToProto(r))
+	}
+	return resp, nil
+}
+
+// GetRollout returns a rollout with the traffic of its canary and of the
+// other agents in scope while it is staged
+func (s *Server) GetRollout(ctx context.Context, req *proto.GetRolloutRequest) (*proto.Rollout, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	r, err := s.db.GetRollout(int(req.RolloutId))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "rollout %d not found", req.RolloutId)
+	}
+	return s.rolloutToProto(r), nil
+}
+
+// PromoteRollout applies a staged change to all agents through the rule
+// tables
+func (s *Server) PromoteRollout(ctx context.Context, req *proto.PromoteRolloutRequest) (*proto.Rollout, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.finishRollout(int(req.RolloutId), "promoted")
+	if err != nil {
+		return nil, err
+	}
+
+	// The rollout is claimed, a concurrent promotion cannot apply it twice
+	err = s.applyRollout(r)
+	s.notifyRollout(r)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "rollout %d withdrawn from its canary, but applying it failed: %v", r.ID, err)
+	}
+	if r.Kind == "routing" && r.Operation != "add" {
+		s.alerts.ruleChanged(r.RuleID)
+	}
+
+	log.Printf("Rollout %d promoted by %s: %s %s rule %s", r.ID, admin.Username, r.Operation, r.Kind, rolloutRule(r))
+	return s.getFinishedRollout(r.ID)
+}
+
+// RollBackRollout withdraws a staged change from its canary
+func (s *Server) RollBackRollout(ctx context.Context, req *proto.RollBackRolloutRequest) (*proto.Rollout, error) {
+	admin, err := s.authorizeAdmin(ctx, permConfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.finishRollout(int(req.RolloutId), "rolled_back")
+	if err != nil {
+		return nil, err
+	}
+	s.notifyRollout(r)
+
+	log.Printf("Rollout %d rolled back by %s: %s %s rule %s", r.ID, admin.Username, r.Operation, r.Kind, rolloutRule(r))
+	return s.getFinishedRollout(r.ID)
+}
+
+// finishRollout moves a staged rollout to its final state
+func (s *Server) finishRollout(id int, state string) (*Rollout, error) {
+	r, err := s.db.GetRollout(id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "rollout %d not found", id)
+	}
+	if r.State != "staged" {
+		return nil, status.Errorf(codes.FailedPrecondition, "rollout %d is %s", id, r.State)
+	}
+	if err := s.db.FinishRollout(id, state); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return r, nil
+}
+
+// getFinishedRollout returns a promoted or rolled back rollout
+func (s *Server) getFinishedRollout(id int) (*proto.Rollout, error) {
+	r, err := s.db.GetRollout(id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get rollout: %v", err)
+	}
+	return s.rolloutToProto(r), nil
+}
+
+// applyRollout stores the change of a promoted rollout in the rule tables
+func (s *Server) applyRollout(r *Rollout) error {
+	if r.Kind == "acl" {
+		switch r.Operation {
+		case "add":
+			r.ACL.ID = 0
+			return s.db.CreateACLRule(r.ACL)
+		case "update":
+			return s.db.UpdateACLRule(r.ACL)
+		default:
+			return s.db.DeleteACLRule(r.RuleID)
+		}
+	}
+
+	switch r.Operation {
+	case "add":
+		r.Routing.ID = 0
+		return s.db.CreateRoutingRule(r.Routing)
+	case "update":
+		return s.db.UpdateRoutingRule(r.Routing)
+	default:
+		return s.db.DeleteRoutingRule(r.RuleID)
+	}
+}
+
+// rolloutRule describes the rule of a rollout
+func rolloutRule(r *Rollout) string {
+	if r.Kind == "acl" {
+		return fmt.Sprintf("%s -> %s %s of user %s", cmp.Or(r.ACL.Source, "any"), cmp.Or(r.ACL.Destination, "any"),
+			r.ACL.Action, r.ACL.UserID)
+	}
+	return fmt.Sprintf("%s %s of %s", r.Routing.Action, r.Routing.Destination, ruleOwner(r.Routing))
+}
+
+// rolloutCanary describes the canary of a rollout
+func rolloutCanary(r *Rollout) string {
+	if r.GroupID != 0 {
+		return fmt.Sprintf("group %d", r.GroupID)
+	}
+	return fmt.Sprintf("%d%% of agents", r.Percent)
+}
+
+// rolloutToProto converts a rollout to proto format, with the traffic of
+// its sides while it is staged
+func (s *Server) rolloutToProto(r *Rollout) *proto.Rollout {
+	pr := &proto.Rollout{
+		RolloutId:     int32(r.ID),
+		Kind:          r.Kind,
+		Operation:     r.Operation,
+		State:         r.State,
+		Percent:       int32(r.Percent),
+		CanaryGroupId: int32(r.GroupID),
+		CreatedBy:     r.CreatedBy,
+		CreatedAt:     timestamppb.New(r.CreatedAt),
+		UpdatedAt:     timestamppb.New(r.UpdatedAt),
+	}
+	if r.Kind == "acl" {
+		pr.AclRule = aclRuleToProto(r.ACL)
+	} else {
+		pr.RoutingRule = &proto.RoutingRuleResponse{
+			AgentId: r.Routing.AgentID,
+			GroupId: int32(r.Routing.GroupID),
+			Rule:    routingRuleToProto(r.Routing),
+		}
+	}
+	if r.State != "staged" {
+		return pr
+	}
+
+	pr.Canary, pr.Baseline = &proto.RolloutCohort{}, &proto.RolloutCohort{}
+	s.sessions.Range(func(key, value interface{}) bool {
+		if staged, m := s.sessionRollout(value.(*SessionInfo)); staged != nil && staged.ID == r.ID && m.inScope {
+			if m.canary {
+				pr.Canary.Sessions++
+			} else {
+				pr.Baseline.Sessions++
+			}
+		}
+		return true
+	})
+	for _, side := range []struct {
+		canary bool
+		pc     *proto.RolloutCohort
+	}{{true, pr.Canary}, {false, pr.Baseline}} {
+		c := s.rollouts.cohort(r.ID, side.canary)
+		side.pc.Packets = c.packets.Load()
+		side.pc.Bytes = c.bytes.Load()
+		side.pc.Errors = c.errors.Load()
+		side.pc.Denied = c.denied.Load()
+	}
+	return pr
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// statsRollupInterval is how often ended sessions are rolled up
+const statsRollupInterval = time.Hour
+
+// statsRollupLoop rolls ended sessions up into hourly and the hourly
+// rollups into daily statistics, and purges rollups older than their
+// retention. Rollups keep long-term reporting possible after the session
+// history is purged.
+func (s *Server) statsRollupLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(statsRollupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.rollupStats()
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// rollupStats runs one rollup and purge pass
+func (s *Server) rollupStats() {
+	now := time.Now()
+	hourlyBefore := retentionCutoff(now, s.config.Database.HourlyStatsDays)
+	dailyBefore := retentionCutoff(now, s.config.Database.DailyStatsDays)
+
+	hourly, err := s.db.RollupHourlyStats(hourlyBefore)
+	if err != nil {
+		log.Printf("Failed to roll up stats: %v", err)
+		return
+	}
+	// Days are only complete once their last hour is rolled up
+	daily, err := s.db.RollupDailyStats()
+	if err != nil {
+		log.Printf("Failed to roll up stats: %v", err)
+		return
+	}
+	if hourly > 0 || daily > 0 {
+		log.Printf("Rolled up stats: %d hourly and %d daily rows written", hourly, daily)
+	}
+
+	hourly, daily, err = s.db.PurgeStatsRollups(hourlyBefore, dailyBefore)
+	if err != nil {
+		log.Printf("Failed to purge stats rollups: %v", err)
+	} else if hourly > 0 || daily > 0 {
+		log.Printf("Purged %d hourly and %d daily stats rollups", hourly, daily)
+	}
+}
+
+// retentionCutoff returns the time before which data kept for days is
+// removed, zero to keep it forever
+func retentionCutoff(now time.Time, days int) time.Time {
+	if days < 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -days)
+}
+
+// ListTrafficRollups returns hourly or daily session rollups, newest first
+func (s *Server) ListTrafficRollups(ctx context.Context, req *proto.ListTrafficRollupsRequest) (*proto.ListTrafficRollupsResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	filter := RollupFilter{AgentID: req.AgentId, UserID: req.UserId, Limit: int(req.Limit)}
+	switch req.Period {
+	case "", "hourly":
+	case "daily":
+		filter.Daily = true
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "period must be 'hourly' or 'daily'")
+	}
+	if req.Since != nil {
+		filter.Since = req.Since.AsTime()
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+
+	rollups, err := s.db.ListTrafficRollups(filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list traffic rollups: %v", err)
+	}
+
+	resp := &proto.ListTrafficRollupsResponse{}
+	for _, r := range rollups {
+		resp.Rollups = append(resp.Rollups, &proto.TrafficRollup{
+			PeriodStart:      timestamppb.New(r.PeriodStart),
+			AgentId:          r.AgentID,
+			UserId:           r.UserID,
+			Sessions:         r.Sessions,
+			ConnectedSeconds: r.ConnectedSeconds,
+			BytesSent:        r.BytesSent,
+			BytesReceived:    r.BytesReceived,
+		})
+	}
+
+	return resp, nil
+}
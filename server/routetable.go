@@ -0,0 +1,123 @@
+package server
+
+import (
+	"cmp"
+	"log"
+	"net/netip"
+	"slices"
+	"time"
+)
+
+// routeCacheTTL bounds how long rules changed through another server
+// instance take to steer relayed packets here; changes made here apply
+// immediately
+const routeCacheTTL = 30 * time.Second
+
+// forwardRoute is an enabled forward rule of an agent prepared for
+// choosing the gateway of its packets
+type forwardRoute struct {
+	prefix   netip.Prefix
+	priority int
+	gateway  string
+	window   *accessWindow // nil if the rule always applies
+	posture  string        // policy the agent must meet, empty for none
+}
+
+// routeGateway picks the gateway for a packet by the forward rules of its
+// sender: among the rules containing dst the most specific prefix wins,
+// then the lowest priority value with a ready gateway, so a gateway that
+// is down or still setting up fails over to the next rule. Flows are
+// spread across the ready gateways of equal rules with network.ecmp,
+// otherwise the first one takes them. It returns an empty ID if no rule
+// has a ready gateway. Rules of a posture policy the agent fails are
+// skipped, like the agent does not get them.
+func (s *Server) routeGateway(si *SessionInfo, payload []byte, dst netip.Addr) string {
+	routes, err := s.forwardRoutes(si.AgentID)
+	if err != nil {
+		log.Printf("Failed to get forward routes of agent %s: %v", si.AgentID, err)
+		return ""
+	}
+
+	now := time.Now()
+	posture := si.posture.Load()
+	var tier *forwardRoute
+	var gateways []string
+	for _, r := range routes {
+		if !r.prefix.Contains(dst) || !r.window.active(now) || !posture.meets(r.posture) {
+			continue
+		}
+		if tier != nil && (r.prefix.Bits() != tier.prefix.Bits() || r.priority != tier.priority) {
+			if len(gateways) > 0 {
+				break
+			}
+			tier = nil
+		}
+		if tier == nil {
+			tier = r
+		}
+		if r.gateway == si.AgentID || slices.Contains(gateways, r.gateway) || !s.readyGateway(r.gateway) {
+			continue
+		}
+		if !s.config.Network.ECMP {
+			return r.gateway
+		}
+		gateways = append(gateways, r.gateway)
+	}
+
+	switch len(gateways) {
+	case 0:
+		return ""
+	case 1:
+		return gateways[0]
+	}
+	// The same flow always takes the same gateway, whose NAT holds its state
+	slices.Sort(gateways)
+	return gateways[flowHash(payload)%uint32(len(gateways))]
+}
+
+// forwardRoutes returns the forward routes of an agent, most specific
+// prefix first and then by priority
+func (s *Server) forwardRoutes(agentID string) ([]*forwardRoute, error) {
+	if cached, ok := s.routes.get(agentID); ok {
+		return cached.([]*forwardRoute), nil
+	}
+
+	rules, err := s.db.GetRoutingRulesByAgentID(agentID)
+	if err != nil {
+		return nil, err
+	}
+	if agent, err := s.db.GetAgentByID(agentID); err == nil {
+		rules = s.canaryRoutingRules(agent, rules)
+	}
+
+	var routes []*forwardRoute
+	for _, rule := range rules {
+		if !rule.Enabled || rule.Action != "forward" || rule.GatewayID == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(rule.Destination)
+		if err != nil || !prefix.Addr().Is4() {
+			continue // the overlay carries IPv4 only
+		}
+		// Rules are validated when stored, a broken one is skipped
+		window, err := rule.Window.parse()
+		if err != nil {
+			log.Printf("Skipping routing rule %d: %v", rule.ID, err)
+			continue
+		}
+		routes = append(routes, &forwardRoute{
+			prefix:   prefix.Masked(),
+			priority: rule.Priority,
+			gateway:  rule.GatewayID,
+			window:   window,
+			posture:  rule.Posture,
+		})
+	}
+	// Rules come by priority, an agent's own rule before a group rule
+	slices.SortStableFunc(routes, func(a, b *forwardRoute) int {
+		return cmp.Or(cmp.Compare(b.prefix.Bits(), a.prefix.Bits()), cmp.Compare(a.priority, b.priority))
+	})
+
+	s.routes.set(agentID, routes)
+	return routes, nil
+}
@@ -0,0 +1,634 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// scimBasePath is where the REST gateway serves SCIM 2.0 (RFC 7643, RFC
+// 7644), through which identity providers such as Entra ID or Okta
+// provision and deprovision users
+const scimBasePath = "/scim/v2"
+
+const (
+	scimUserSchema         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimConfigSchema       = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	scimResourceTypeSchema = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	scimListSchema         = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimErrorSchema        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// scimMaxResults is the largest page of users a listing returns
+const scimMaxResults = 200
+
+// maxSCIMRequest bounds the size of request bodies
+const maxSCIMRequest = 64 << 10
+
+// scimFilter matches the filters identity providers look users up with
+// before creating them: userName or externalId equal to a string
+var scimFilter = regexp.MustCompile(`(?i)^(userName|externalId)\s+eq\s+("(?:[^"\\]|\\.)*")$`)
+
+// scimUser is the SCIM representation of a user. Attributes the server
+// does not store, such as name, are ignored.
+type scimUser struct {
+	Schemas    []string    `json:"schemas"`
+	ID         string      `json:"id,omitempty"`
+	ExternalID string      `json:"externalId,omitempty"`
+	UserName   string      `json:"userName"`
+	Active     *bool       `json:"active,omitempty"` // nil in a request leaves the status unchanged
+	Emails     []scimEmail `json:"emails,omitempty"`
+	Password   string      `json:"password,omitempty"` // only read when the user is created
+	Meta       *scimMeta   `json:"meta,omitempty"`
+}
+
+type scimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type scimMeta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location"`
+}
+
+type scimListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// scimPatchOp is the body of a PATCH request
+type scimPatchOp struct {
+	Operations []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	} `json:"Operations"`
+}
+
+type scimErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// scimError is a request the server rejects with a SCIM error type, e.g.
+// "invalidFilter"
+type scimError struct {
+	scimType, detail string
+}
+
+func (e *scimError) Error() string { return e.detail }
+
+// scimInvalid returns a bad request error of a SCIM error type
+func scimInvalid(scimType, format string, args ...any) error {
+	return &scimError{scimType: scimType, detail: fmt.Sprintf(format, args...)}
+}
+
+// scimFunc handles a SCIM request of an admin, returning the HTTP status
+// and the body of the response
+type scimFunc func(r *http.Request, admin *User) (int, any, error)
+
+// scimHandler serves the SCIM endpoints under scimBasePath
+func (s *Server) scimHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+scimBasePath+"/ServiceProviderConfig", s.scim(s.scimServiceProviderConfig))
+	mux.HandleFunc("GET "+scimBasePath+"/ResourceTypes", s.scim(s.scimResourceTypes))
+	mux.HandleFunc("GET "+scimBasePath+"/Users", s.scim(s.scimListUsers))
+	mux.HandleFunc("POST "+scimBasePath+"/Users", s.scim(s.scimCreateUser))
+	mux.HandleFunc("GET "+scimBasePath+"/Users/{id}", s.scim(s.scimGetUser))
+	mux.HandleFunc("PUT "+scimBasePath+"/Users/{id}", s.scim(s.scimReplaceUser))
+	mux.HandleFunc("PATCH "+scimBasePath+"/Users/{id}", s.scim(s.scimPatchUser))
+	mux.HandleFunc("DELETE "+scimBasePath+"/Users/{id}", s.scim(s.scimDeleteUser))
+	return mux
+}
+
+// scim authenticates a SCIM request like an AdminService call, with the
+// API key of an admin as bearer token, and writes the response or error
+func (s *Server) scim(handle scimFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		code, body, err := 0, any(nil), error(nil)
+
+		token := ""
+		if scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+			token = strings.TrimSpace(value)
+		}
+		if token == "" {
+			err = status.Errorf(codes.Unauthenticated, "missing bearer token")
+		} else {
+			ctx := metadata.NewIncomingContext(r.Context(), metadata.Pairs(AdminAPIKeyHeader, token))
+			ctx = context.WithValue(ctx, auditActionKey{}, "SCIM "+r.Method+" "+r.URL.Path)
+			var admin *User
+			if admin, err = s.authorizeAdmin(ctx, scimPermission(r.Method)); err == nil {
+				r = r.WithContext(ctx)
+				r.Body = http.MaxBytesReader(w, r.Body, maxSCIMRequest)
+				code, body, err = handle(r, admin)
+			}
+		}
+
+		if err != nil {
+			code, body = scimErrorBody(err)
+			if code == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+			}
+		}
+		w.Header().Set("Content-Type", "application/scim+json")
+		w.WriteHeader(code)
+		if body != nil {
+			json.NewEncoder(w).Encode(body)
+		}
+	}
+}
+
+// scimPermission returns the permission a SCIM request needs: reading
+// users, or managing them
+func scimPermission(method string) permission {
+	if method == http.MethodGet {
+		return permRead
+	}
+	return permConfigure
+}
+
+// scimErrorBody converts an error to a SCIM error response and its HTTP
+// status
+func scimErrorBody(err error) (int, *scimErrorResponse) {
+	body := &scimErrorResponse{Schemas: []string{scimErrorSchema}, Detail: err.Error()}
+	code := http.StatusBadRequest
+
+	var invalid *scimError
+	if errors.As(err, &invalid) {
+		body.ScimType = invalid.scimType
+	} else {
+		st := status.Convert(err)
+		code = runtime.HTTPStatusFromCode(st.Code())
+		body.Detail = st.Message()
+		switch st.Code() {
+		case codes.AlreadyExists:
+			body.ScimType = "uniqueness"
+		case codes.InvalidArgument:
+			body.ScimType = "invalidValue"
+		}
+	}
+
+	body.Status = strconv.Itoa(code)
+	return code, body
+}
+
+// scimServiceProviderConfig describes the SCIM features the server supports
+func (s *Server) scimServiceProviderConfig(r *http.Request, _ *User) (int, any, error) {
+	unsupported := map[string]bool{"supported": false}
+	return http.StatusOK, map[string]any{
+		"schemas":        []string{scimConfigSchema},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": scimMaxResults},
+		"changePassword": unsupported,
+		"sort":           unsupported,
+		"etag":           unsupported,
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Admin API key",
+			"description": "The API key of an admin as bearer token",
+			"primary":     true,
+		}},
+		"meta": &scimMeta{ResourceType: "ServiceProviderConfig", Location: scimLocation(r, "/ServiceProviderConfig")},
+	}, nil
+}
+
+// scimResourceTypes lists the resource types served, users only
+func (s *Server) scimResourceTypes(r *http.Request, _ *User) (int, any, error) {
+	return http.StatusOK, &scimListResponse{
+		Schemas:      []string{scimListSchema},
+		TotalResults: 1,
+		StartIndex:   1,
+		ItemsPerPage: 1,
+		Resources: []any{map[string]any{
+			"schemas":  []string{scimResourceTypeSchema},
+			"id":       "User",
+			"name":     "User",
+			"endpoint": "/Users",
+			"schema":   scimUserSchema,
+			"meta":     &scimMeta{ResourceType: "ResourceType", Location: scimLocation(r, "/ResourceTypes/User")},
+		}},
+	}, nil
+}
+
+// scimListUsers lists the users matching the filter, a page at a time
+func (s *Server) scimListUsers(r *http.Request, _ *User) (int, any, error) {
+	query := r.URL.Query()
+	match, err := parseSCIMFilter(query.Get("filter"))
+	if err != nil {
+		return 0, nil, err
+	}
+	startIndex, count := 1, scimMaxResults
+	if n, err := strconv.Atoi(query.Get("startIndex")); err == nil && n > 1 {
+		startIndex = n
+	}
+	if n, err := strconv.Atoi(query.Get("count")); err == nil && n >= 0 {
+		count = min(n, scimMaxResults)
+	}
+
+	users, err := s.db.ListUsers()
+	if err != nil {
+		return 0, nil, status.Errorf(codes.Internal, "failed to list users: %v", err)
+	}
+	users = slices.DeleteFunc(users, func(user *User) bool { return !match(user) })
+
+	page := users[min(startIndex-1, len(users)):]
+	page = page[:min(count, len(page))]
+	resp := &scimListResponse{
+		Schemas:      []string{scimListSchema},
+		TotalResults: len(users),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    make([]any, 0, len(page)),
+	}
+	for _, user := range page {
+		resp.Resources = append(resp.Resources, scimUserResource(r, user))
+	}
+	return http.StatusOK, resp, nil
+}
+
+// scimCreateUser provisions a user. Like users created by an admin, it
+// authenticates with an API key the admin hands out with users rotate-key.
+func (s *Server) scimCreateUser(r *http.Request, admin *User) (int, any, error) {
+	var in scimUser
+	if err := decodeSCIM(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.UserName == "" {
+		return 0, nil, scimInvalid("invalidValue", "userName is required")
+	}
+
+	// Users without a password can only authenticate by API key
+	password := in.Password
+	if password == "" {
+		var err error
+		if password, err = generateAPIKey(); err != nil {
+			return 0, nil, status.Errorf(codes.Internal, "failed to generate password: %v", err)
+		}
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, nil, scimInvalid("invalidValue", "invalid password: %v", err)
+	}
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return 0, nil, status.Errorf(codes.Internal, "failed to generate API key: %v", err)
+	}
+
+	user := &User{
+		ID:           uuid.New().String(),
+		Username:     in.UserName,
+		Email:        primaryEmail(in.Emails),
+		PasswordHash: string(passwordHash),
+		APIKey:       apiKey,
+		Role:         "user",
+		Status:       "active",
+		ExternalID:   in.ExternalID,
+	}
+	if in.Active != nil && !*in.Active {
+		user.Status, user.SCIMDeactivated = "disabled", true
+	}
+
+	if err := s.db.CreateUser(user); err != nil {
+		if isDuplicateKey(err) {
+			return 0, nil, status.Errorf(codes.AlreadyExists, "a user with username %q or the same email already exists", in.UserName)
+		}
+		return 0, nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
+	}
+
+	log.Printf("User %s (%s) provisioned over SCIM by %s", user.Username, user.ID, admin.Username)
+
+	if user, err = s.db.GetUserByID(user.ID); err != nil {
+		return 0, nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	return http.StatusCreated, scimUserResource(r, user), nil
+}
+
+// scimGetUser returns a user
+func (s *Server) scimGetUser(r *http.Request, _ *User) (int, any, error) {
+	user, err := s.scimLookup(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, scimUserResource(r, user), nil
+}
+
+// scimReplaceUser replaces the attributes of a user
+func (s *Server) scimReplaceUser(r *http.Request, admin *User) (int, any, error) {
+	user, err := s.scimLookup(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var in scimUser
+	if err := decodeSCIM(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.UserName == "" {
+		return 0, nil, scimInvalid("invalidValue", "userName is required")
+	}
+
+	if err := s.authorizeAccountChange(r.Context(), admin, user.Role); err != nil {
+		return 0, nil, err
+	}
+
+	user.Username = in.UserName
+	user.Email = primaryEmail(in.Emails)
+	user.ExternalID = in.ExternalID
+	return s.scimUpdate(r, admin, user, in.Active)
+}
+
+// scimPatchUser applies PATCH operations to a user
+func (s *Server) scimPatchUser(r *http.Request, admin *User) (int, any, error) {
+	user, err := s.scimLookup(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.authorizeAccountChange(r.Context(), admin, user.Role); err != nil {
+		return 0, nil, err
+	}
+	var patch scimPatchOp
+	if err := decodeSCIM(r, &patch); err != nil {
+		return 0, nil, err
+	}
+
+	var active *bool
+	for _, op := range patch.Operations {
+		if err := applySCIMPatch(user, &active, op.Op, op.Path, op.Value); err != nil {
+			return 0, nil, err
+		}
+	}
+	return s.scimUpdate(r, admin, user, active)
+}
+
+// scimDeleteUser deletes a user together with its agents
+func (s *Server) scimDeleteUser(r *http.Request, admin *User) (int, any, error) {
+	user, err := s.scimLookup(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if user.ID == admin.ID {
+		return 0, nil, status.Errorf(codes.FailedPrecondition, "admins cannot delete themselves")
+	}
+	if err := s.authorizeAccountChange(r.Context(), admin, user.Role); err != nil {
+		return 0, nil, err
+	}
+
+	if _, err := s.removeUser(user, admin.Username); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusNoContent, nil, nil
+}
+
+// scimUpdate stores the attributes of a user an identity provider changed.
+// A user it deactivates is disabled and deprovisioned at once; one it
+// activates again needs a new API key from an admin.
+func (s *Server) scimUpdate(r *http.Request, admin, user *User, active *bool) (int, any, error) {
+	if user.Username == "" {
+		return 0, nil, scimInvalid("invalidValue", "userName is required")
+	}
+
+	deprovision := active != nil && setSCIMActive(user, *active)
+	if user.ID == admin.ID && user.Status != "active" {
+		return 0, nil, status.Errorf(codes.FailedPrecondition, "admins cannot deactivate themselves")
+	}
+
+	if err := s.db.UpdateUser(user); err != nil {
+		if isDuplicateKey(err) {
+			return 0, nil, status.Errorf(codes.AlreadyExists, "a user with username %q or the same email already exists", user.Username)
+		}
+		return 0, nil, status.Errorf(codes.Internal, "failed to update user: %v", err)
+	}
+	log.Printf("User %s (%s) updated over SCIM by %s", user.Username, user.ID, admin.Username)
+
+	if deprovision {
+		if err := s.deprovisionUser(user, admin.Username); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	user, err := s.db.GetUserByID(user.ID)
+	if err != nil {
+		return 0, nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	return http.StatusOK, scimUserResource(r, user), nil
+}
+
+// deprovisionUser revokes the API key of a user an identity provider
+// deactivated and ends the sessions of its agents. The key is replaced by
+// one nobody knows, so that reactivating the user does not bring the old
+// key back.
+func (s *Server) deprovisionUser(user *User, by string) error {
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to generate API key: %v", err)
+	}
+	if err := s.db.UpdateUserAPIKey(user.ID, apiKey); err != nil {
+		return status.Errorf(codes.Internal, "failed to revoke API key: %v", err)
+	}
+
+	s.disconnectUser(user.ID, "user deprovisioned by "+by)
+	log.Printf("User %s (%s) deprovisioned over SCIM by %s, API key revoked", user.Username, user.ID, by)
+	return nil
+}
+
+// scimLookup returns the user of the request path
+func (s *Server) scimLookup(r *http.Request) (*User, error) {
+	id := r.PathValue("id")
+	user, err := s.db.GetUserByID(id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", id)
+	}
+	return user, nil
+}
+
+// setSCIMActive applies the active attribute an identity provider set and
+// reports whether the user must be deprovisioned. Only users it deactivated
+// itself are reactivated; those an admin suspended or disabled stay so.
+func setSCIMActive(user *User, active bool) bool {
+	switch {
+	case active && user.Status != "active" && user.SCIMDeactivated:
+		user.Status, user.SCIMDeactivated = "active", false
+	case !active && user.Status != "disabled":
+		user.SCIMDeactivated = user.Status == "active"
+		user.Status = "disabled"
+		return true
+	}
+	return false
+}
+
+// applySCIMPatch applies a PATCH operation to the attributes of a user the
+// server stores, setting active when the operation changes it
+func applySCIMPatch(user *User, active **bool, op, path string, value json.RawMessage) error {
+	switch strings.ToLower(op) {
+	case "add", "replace":
+	case "remove":
+		value = nil
+	default:
+		return scimInvalid("invalidSyntax", "unsupported operation %q", op)
+	}
+
+	if path != "" {
+		return setSCIMAttribute(user, active, path, value)
+	}
+	if value == nil {
+		return scimInvalid("noTarget", "remove needs a path")
+	}
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(value, &attributes); err != nil {
+		return scimInvalid("invalidValue", "value without a path must be an object")
+	}
+	for name, v := range attributes {
+		if err := setSCIMAttribute(user, active, name, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setSCIMAttribute sets an attribute of a user, removing it for a nil
+// value. Attributes the server does not store are ignored.
+func setSCIMAttribute(user *User, active **bool, name string, value json.RawMessage) error {
+	switch name := strings.ToLower(name); {
+	case name == "username":
+		var username string
+		if value == nil || json.Unmarshal(value, &username) != nil || username == "" {
+			return scimInvalid("invalidValue", "userName must be a non-empty string")
+		}
+		user.Username = username
+	case name == "externalid":
+		var id string
+		if value != nil && json.Unmarshal(value, &id) != nil {
+			return scimInvalid("invalidValue", "externalId must be a string")
+		}
+		user.ExternalID = id
+	case name == "active":
+		if value == nil {
+			return scimInvalid("mutability", "active cannot be removed")
+		}
+		b, err := scimBool(value)
+		if err != nil {
+			return err
+		}
+		*active = &b
+	case name == "emails":
+		var emails []scimEmail
+		if value != nil && json.Unmarshal(value, &emails) != nil {
+			return scimInvalid("invalidValue", "emails must be a list of emails")
+		}
+		user.Email = primaryEmail(emails)
+	case name == "emails.value" || strings.HasPrefix(name, "emails[") && strings.HasSuffix(name, "].value"):
+		var email string
+		if value != nil && json.Unmarshal(value, &email) != nil {
+			return scimInvalid("invalidValue", "email must be a string")
+		}
+		user.Email = email
+	}
+	return nil
+}
+
+// scimBool parses a boolean, which some identity providers send as the
+// string "True" or "False"
+func scimBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if json.Unmarshal(value, &b) == nil {
+		return b, nil
+	}
+	var s string
+	if json.Unmarshal(value, &s) == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, scimInvalid("invalidValue", "active must be a boolean")
+}
+
+// parseSCIMFilter returns a function matching the users a filter selects,
+// any user for an empty filter
+func parseSCIMFilter(filter string) (func(*User) bool, error) {
+	if filter == "" {
+		return func(*User) bool { return true }, nil
+	}
+	m := scimFilter.FindStringSubmatch(strings.TrimSpace(filter))
+	var value string
+	if m == nil || json.Unmarshal([]byte(m[2]), &value) != nil {
+		return nil, scimInvalid("invalidFilter", "unsupported filter %q, only userName or externalId eq a string are", filter)
+	}
+	// userName is case-insensitive, externalId case-exact (RFC 7643)
+	if strings.EqualFold(m[1], "userName") {
+		return func(user *User) bool { return strings.EqualFold(user.Username, value) }, nil
+	}
+	return func(user *User) bool { return user.ExternalID == value }, nil
+}
+
+// decodeSCIM reads a SCIM request body
+func decodeSCIM(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return scimInvalid("invalidSyntax", "invalid request body: %v", err)
+	}
+	return nil
+}
+
+// primaryEmail returns the primary email of a list, else the first one
+func primaryEmail(emails []scimEmail) string {
+	for _, email := range emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
+
+// scimUserResource converts a user record to its SCIM representation
+func scimUserResource(r *http.Request, user *User) *scimUser {
+	active := user.Status == "active"
+	resource := &scimUser{
+		Schemas:    []string{scimUserSchema},
+		ID:         user.ID,
+		ExternalID: user.ExternalID,
+		UserName:   user.Username,
+		Active:     &active,
+		Meta: &scimMeta{
+			ResourceType: "User",
+			Created:      &user.CreatedAt,
+			LastModified: &user.UpdatedAt,
+			Location:     scimLocation(r, "/Users/"+user.ID),
+		},
+	}
+	if user.Email != "" {
+		resource.Emails = []scimEmail{{Value: user.Email, Type: "work", Primary: true}}
+	}
+	return resource
+}
+
+// scimLocation returns the URL of a SCIM resource, the gateway being
+// served over TLS
+func scimLocation(r *http.Request, path string) string {
+	return "https://" + r.Host + scimBasePath + path
+}
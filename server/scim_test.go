@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseSCIMFilter(t *testing.T) {
+	alice := &User{Username: "Alice", ExternalID: "00u1"}
+	tests := []struct {
+		name     string
+		filter   string
+		wantType string // SCIM error type, empty for a valid filter
+		match    bool
+	}{
+		{"empty", "", "", true},
+		{"userName eq", `userName eq "alice"`, "", true},
+		{"userName other", `userName eq "bob"`, "", false},
+		{"externalId eq", `externalId eq "00u1"`, "", true},
+		{"externalId case-exact", `externalId eq "00U1"`, "", false},
+		{"operator case", `userName EQ "Alice"`, "", true},
+		{"co", `userName co "ali"`, "invalidFilter", false},
+		{"sw", `userName sw "A"`, "invalidFilter", false},
+		{"and", `userName eq "alice" and active eq true`, "invalidFilter", false},
+		{"other attribute", `emails eq "alice@example.com"`, "invalidFilter", false},
+		{"unquoted", `userName eq alice`, "invalidFilter", false},
+	}
+	for _, tt := range tests {
+		match, err := parseSCIMFilter(tt.filter)
+		if got := scimType(err); got != tt.wantType {
+			t.Errorf("%s: got error %v, want type %q", tt.name, err, tt.wantType)
+			continue
+		}
+		if err == nil && match(alice) != tt.match {
+			t.Errorf("%s: got match %v, want %v", tt.name, !tt.match, tt.match)
+		}
+	}
+}
+
+func TestApplySCIMPatch(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name     string
+		op, path string
+		value    string // JSON, empty for none
+		wantType string
+		active   *bool // active after the operation, nil if unchanged
+		email    string
+	}{
+		{"replace active", "replace", "active", `false`, "", &off, "alice@example.com"},
+		{"replace active string", "Replace", "active", `"True"`, "", &on, "alice@example.com"},
+		{"replace without path", "replace", "", `{"active":false}`, "", &off, "alice@example.com"},
+		{"replace email", "replace", `emails[type eq "work"].value`, `"a@example.com"`, "", nil, "a@example.com"},
+		{"remove email", "remove", "emails", "", "", nil, ""},
+		{"remove active", "remove", "active", "", "mutability", nil, "alice@example.com"},
+		{"active not boolean", "replace", "active", `"maybe"`, "invalidValue", nil, "alice@example.com"},
+		{"unknown op", "move", "active", `false`, "invalidSyntax", nil, "alice@example.com"},
+		{"remove without path", "remove", "", "", "noTarget", nil, "alice@example.com"},
+		{"value not object", "add", "", `[1]`, "invalidValue", nil, "alice@example.com"},
+	}
+	for _, tt := range tests {
+		user := &User{Username: "alice", Email: "alice@example.com"}
+		var active *bool
+		var value json.RawMessage
+		if tt.value != "" {
+			value = json.RawMessage(tt.value)
+		}
+
+		err := applySCIMPatch(user, &active, tt.op, tt.path, value)
+		if got := scimType(err); got != tt.wantType {
+			t.Errorf("%s: got error %v, want type %q", tt.name, err, tt.wantType)
+			continue
+		}
+		if (active == nil) != (tt.active == nil) || active != nil && *active != *tt.active {
+			t.Errorf("%s: got active %v, want %v", tt.name, active, tt.active)
+		}
+		if user.Email != tt.email {
+			t.Errorf("%s: got email %q, want %q", tt.name, user.Email, tt.email)
+		}
+	}
+}
+
+func TestSetSCIMActive(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          string
+		deactivated     bool
+		active          bool
+		wantStatus      string
+		wantDeactivated bool
+		wantDeprovision bool
+	}{
+		{"deactivate active", "active", false, false, "disabled", true, true},
+		{"deactivate suspended", "suspended", false, false, "disabled", false, true},
+		{"deactivate disabled", "disabled", true, false, "disabled", true, false},
+		{"reactivate SCIM disabled", "disabled", true, true, "active", false, false},
+		{"keep admin disabled", "disabled", false, true, "disabled", false, false},
+		{"keep admin suspended", "suspended", false, true, "suspended", false, false},
+		{"activate active", "active", false, true, "active", false, false},
+	}
+	for _, tt := range tests {
+		user := &User{Username: "alice", Status: tt.status, SCIMDeactivated: tt.deactivated}
+		deprovision := setSCIMActive(user, tt.active)
+		if user.Status != tt.wantStatus || user.SCIMDeactivated != tt.wantDeactivated || deprovision != tt.wantDeprovision {
+			t.Errorf("%s: got status %q, deactivated %v, deprovision %v, want %q, %v, %v", tt.name,
+				user.Status, user.SCIMDeactivated, deprovision, tt.wantStatus, tt.wantDeactivated, tt.wantDeprovision)
+		}
+	}
+}
+
+// scimType returns the SCIM error type of err, empty for nil
+func scimType(err error) string {
+	if err == nil {
+		return ""
+	}
+	var se *scimError
+	if !errors.As(err, &se) {
+		return "not a SCIM error"
+	}
+	return se.scimType
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultSimulatedFlows is the number of affected flows returned by default
+const defaultSimulatedFlows = 100
+
+// flowKey identifies a flow in the relay traces
+type flowKey struct {
+	agentID string
+	src     string
+	dst     string
+	proto   uint32
+	port    uint32
+}
+
+// routeOutcome is what the routing rules of an agent do with a destination
+type routeOutcome struct {
+	action  string // "forward", "direct", "deny", or empty without a matching rule
+	gateway string
+	ruleID  int
+}
+
+// String describes the outcome for the simulation report
+func (o routeOutcome) String() string {
+	rule := "the new rule"
+	if o.ruleID != 0 {
+		rule = fmt.Sprintf("rule %d", o.ruleID)
+	}
+	switch o.action {
+	case "forward":
+		return fmt.Sprintf("forward via %s (%s)", o.gateway, rule)
+	case "direct":
+		return fmt.Sprintf("direct (%s)", rule)
+	case "deny":
+		return "deny by " + rule
+	default:
+		return "no rule"
+	}
+}
+
+// routeFor returns the outcome of the rules of an agent for a destination:
+// the rule with the longest matching prefix, the first in rule order
+// among equally long ones, of those applying to its posture
+func routeFor(rules []*RoutingRule, dst netip.Addr, now time.Time, posture *postureState) routeOutcome {
+	var best *RoutingRule
+	bestBits := -1
+	for _, rule := range rules {
+		prefix, err := netip.ParsePrefix(rule.Destination)
+		if err != nil || !rule.Enabled || !prefix.Contains(dst) || !rule.Window.activeAt(now) || !posture.meets(rule.Posture) {
+			continue
+		}
+		if prefix.Bits() > bestBits {
+			best, bestBits = rule, prefix.Bits()
+		}
+	}
+	if best == nil {
+		return routeOutcome{}
+	}
+	return routeOutcome{action: best.Action, gateway: best.GatewayID, ruleID: best.ID}
+}
+
+// aclOutcome describes the decision of the first matching ACL rule for
+// the simulation report, nil if no rule matched. Only the proposed rule of
+// an added rule has no ID.
+func aclOutcome(m *aclMatcher) string {
+	if m == nil {
+		return "allow"
+	}
+	rule := "the new rule"
+	if m.id != 0 {
+		rule = fmt.Sprintf("rule %d", m.id)
+	}
+	if m.allow {
+		return "allow by " + rule
+	}
+	return "deny by " + rule
+}
+
+// SimulatePolicy evaluates a proposed routing or ACL rule change against
+// the flows of the relay traces and reports those it would deny, allow
+// again or reroute. Nothing is applied; the change is validated like the
+// rule RPCs would.
+func (s *Server) SimulatePolicy(ctx context.Context, req *proto.SimulatePolicyRequest) (*proto.SimulatePolicyResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	r := &Rollout{Kind: req.Kind, Operation: req.Operation, Percent: 100}
+	change := &proto.StageRolloutRequest{
+		Kind:        req.Kind,
+		Operation:   req.Operation,
+		RoutingRule: req.RoutingRule,
+		AgentId:     req.AgentId,
+		GroupId:     req.GroupId,
+		AclRule:     req.AclRule,
+	}
+	var err error
+	switch req.Kind {
+	case "routing":
+		err = s.stageRoutingChange(r, change)
+	case "acl":
+		err = s.stageACLChange(r, change)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "kind must be routing or acl")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultSimulatedFlows
+	}
+
+	// Group the sampled packets into flows, newest trace first
+	var keys []flowKey
+	flows := make(map[flowKey]*proto.SimulatedFlow)
+	for _, trace := range s.tracer.recent("", 0) {
+		if trace.SourceIp == "" || trace.SourceAgentId == "" {
+			continue
+		}
+		key := flowKey{trace.SourceAgentId, trace.SourceIp, trace.DestinationIp, trace.Protocol, trace.DestinationPort}
+		if flow := flows[key]; flow != nil {
+			flow.Packets++
+			continue
+		}
+		flows[key] = &proto.SimulatedFlow{
+			SourceAgentId:   trace.SourceAgentId,
+			SourceIp:        trace.SourceIp,
+			DestinationIp:   trace.DestinationIp,
+			Protocol:        trace.Protocol,
+			DestinationPort: trace.DestinationPort,
+			Packets:         1,
+			LastSeen:        trace.Time,
+		}
+		keys = append(keys, key)
+	}
+
+	resp := &proto.SimulatePolicyResponse{
+		FlowsEvaluated: int32(len(keys)),
+		SampleRate:     s.tracer.sampleRate.Load(),
+	}
+
+	now := time.Now()
+	agents := make(map[string]*Agent)
+	proposedACLs := make(map[string][]*aclMatcher) // userID -> proposed ACL rules
+	for _, key := range keys {
+		agent, ok := agents[key.agentID]
+		if !ok {
+			agent, _ = s.db.GetAgentByID(key.agentID)
+			agents[key.agentID] = agent
+		}
+		if agent == nil {
+			continue
+		}
+		if inScope, _ := rolloutMember(r, agent); !inScope {
+			continue
+		}
+
+		flow := flows[key]
+		if r.Kind == "acl" {
+			err = s.simulateACL(r, agent, flow, proposedACLs)
+		} else {
+			err = s.simulateRouting(r, agent, flow, now)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to evaluate the rules of agent %s: %v", agent.ID, err)
+		}
+		if flow.Change != "" {
+			resp.Flows = append(resp.Flows, flow)
+		}
+	}
+
+	slices.SortStableFunc(resp.Flows, func(a, b *proto.SimulatedFlow) int {
+		return cmp.Compare(b.Packets, a.Packets)
+	})
+	if len(resp.Flows) > limit {
+		resp.Flows = resp.Flows[:limit]
+	}
+	return resp, nil
+}
+
+// simulateACL evaluates a flow against the current ACL rules of the
+// agent's user and against the proposed ones, prepared once per user
+func (s *Server) simulateACL(r *Rollout, agent *Agent, flow *proto.SimulatedFlow, proposedACLs map[string][]*aclMatcher) error {
+	current, err := s.aclMatchers(agent.UserID)
+	if err != nil {
+		return err
+	}
+	proposed, ok := proposedACLs[agent.UserID]
+	if !ok {
+		rules, err := s.db.ListACLRules(agent.UserID, true)
+		if err != nil {
+			return err
+		}
+		proposed = prepareACLMatchers(proposedACLRules(rules, r))
+		proposedACLs[agent.UserID] = proposed
+	}
+
+	h := &packet.IPv4{
+		Protocol: uint8(flow.Protocol),
+		Src:      net.ParseIP(flow.SourceIp),
+		Dst:      net.ParseIP(flow.DestinationIp),
+	}
+	port, hasPort := uint16(flow.DestinationPort), flow.DestinationPort != 0
+	posture := s.agentPosture(agent.ID)
+	before, after := firstMatch(current, h, port, hasPort, nil, posture), firstMatch(proposed, h, port, hasPort, nil, posture)
+	flow.Before, flow.After = aclOutcome(before), aclOutcome(after)
+	switch allowedBefore, allowedAfter := before == nil || before.allow, after == nil || after.allow; {
+	case allowedBefore && !allowedAfter:
+		flow.Change = "denied"
+	case !allowedBefore && allowedAfter:
+		flow.Change = "allowed"
+	}
+	return nil
+}
+
+// simulateRouting evaluates a flow against the current routing rules of
+// its source agent and against the proposed ones
+func (s *Server) simulateRouting(r *Rollout, agent *Agent, flow *proto.SimulatedFlow, now time.Time) error {
+	dst, err := netip.ParseAddr(flow.DestinationIp)
+	if err != nil {
+		return nil
+	}
+	rules, err := s.db.GetRoutingRulesByAgentID(agent.ID)
+	if err != nil {
+		return err
+	}
+
+	posture := s.agentPosture(agent.ID)
+	before := routeFor(rules, dst, now, posture)
+	after := routeFor(proposedRoutingRules(rules, r), dst, now, posture)
+	flow.Before, flow.After = before.String(), after.String()
+	switch {
+	case before.action != "deny" && after.action == "deny":
+		flow.Change = "denied"
+	case before.action == "deny" && after.action != "deny":
+		flow.Change = "allowed"
+	case before.action != after.action || before.gateway != after.gateway:
+		flow.Change = "rerouted"
+	}
+	return nil
+}
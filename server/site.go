@@ -0,0 +1,235 @@
+package server
+
+import (
+	"log"
+	"net/netip"
+	"slices"
+	"sort"
+	"sync"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// siteMesh indexes the LANs gateways advertise for site-to-site routing.
+// The gateways of a user that advertise sites form a mesh: each gets
+// routes to the sites of the others, and the relay sends packets for a
+// site to its gateway.
+type siteMesh struct {
+	mu    sync.RWMutex
+	sites map[string][]site // userID -> sites of the user's gateways
+}
+
+// site is a LAN behind a connected gateway
+type site struct {
+	prefix    netip.Prefix
+	agentID   string
+	sessionID string
+}
+
+// join adds the sites of a gateway session, replacing those of a previous
+// session of the agent. Sites outside the prefixes the user may advertise,
+// if limited, and sites overlapping the overlay or a site of another
+// gateway of the user are left out. It returns the accepted sites and the
+// other gateways of the mesh, whose routes changed.
+func (m *siteMesh) join(si *SessionInfo, advertised []string, allowed []netip.Prefix, overlay netip.Prefix) (accepted []netip.Prefix, peers []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sites := m.without(si.UserID, func(s site) bool { return s.agentID == si.AgentID })
+	for _, subnet := range advertised {
+		prefix, err := netip.ParsePrefix(subnet)
+		if err != nil || !prefix.Addr().Is4() {
+			log.Printf("Agent %s advertised invalid site %q", si.AgentID, subnet)
+			continue
+		}
+		prefix = prefix.Masked()
+		if len(allowed) > 0 && !slices.ContainsFunc(allowed, func(a netip.Prefix) bool {
+			return a.Bits() <= prefix.Bits() && a.Contains(prefix.Addr())
+		}) {
+			log.Printf("Agent %s advertised site %s outside the prefixes its user may advertise, ignoring it", si.AgentID, prefix)
+			continue
+		}
+		if prefix.Overlaps(overlay) {
+			log.Printf("Agent %s advertised site %s overlapping the overlay, ignoring it", si.AgentID, prefix)
+			continue
+		}
+		if owner := overlapping(sites, prefix); owner != "" {
+			log.Printf("Agent %s advertised site %s overlapping a site of agent %s, ignoring it", si.AgentID, prefix, owner)
+			continue
+		}
+		sites = append(sites, site{prefix: prefix, agentID: si.AgentID, sessionID: si.SessionID})
+		accepted = append(accepted, prefix)
+	}
+	m.set(si.UserID, sites)
+
+	if len(accepted) == 0 {
+		return nil, nil
+	}
+	log.Printf("Agent %s joined the site mesh with %v", si.AgentID, accepted)
+	return accepted, peerAgents(sites, si.AgentID)
+}
+
+// leave removes the sites of a session, returning the other gateways of
+// the mesh if any were removed
+func (m *siteMesh) leave(si *SessionInfo) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sites := m.sites[si.UserID]
+	remaining := m.without(si.UserID, func(s site) bool { return s.sessionID == si.SessionID })
+	if len(remaining) == len(sites) {
+		return nil
+	}
+	m.set(si.UserID, remaining)
+	log.Printf("Agent %s left the site mesh", si.AgentID)
+	return peerAgents(remaining, si.AgentID)
+}
+
+// lookup returns the gateway of a user whose site contains dst, preferring
+// the most specific site, empty if none other than exclude has one
+func (m *siteMesh) lookup(userID string, dst netip.Addr, exclude string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	best := -1
+	var agentID string
+	for _, s := range m.sites[userID] {
+		if s.agentID != exclude && s.prefix.Bits() > best && s.prefix.Contains(dst) {
+			best, agentID = s.prefix.Bits(), s.agentID
+		}
+	}
+	return agentID
+}
+
+// routes returns forward rules to the sites of the other gateways of the
+// mesh, nil if the agent advertises no site
+func (m *siteMesh) routes(userID, agentID string) []*proto.RoutingRule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sites := m.sites[userID]
+	if !slices.ContainsFunc(sites, func(s site) bool { return s.agentID == agentID }) {
+		return nil
+	}
+
+	var rules []*proto.RoutingRule
+	for _, s := range sites {
+		if s.agentID == agentID {
+			continue
+		}
+		rules = append(rules, &proto.RoutingRule{
+			Action:      proto.RouteAction_FORWARD,
+			Destination: s.prefix.String(),
+			GatewayId:   s.agentID,
+			Enabled:     true,
+		})
+	}
+	return rules
+}
+
+// without returns the sites of a user that do not match drop. The caller
+// holds the lock.
+func (m *siteMesh) without(userID string, drop func(site) bool) []site {
+	var sites []site
+	for _, s := range m.sites[userID] {
+		if !drop(s) {
+			sites = append(sites, s)
+		}
+	}
+	return sites
+}
+
+// set replaces the sites of a user. The caller holds the lock.
+func (m *siteMesh) set(userID string, sites []site) {
+	if m.sites == nil {
+		m.sites = make(map[string][]site)
+	}
+	if len(sites) == 0 {
+		delete(m.sites, userID)
+		return
+	}
+	m.sites[userID] = sites
+}
+
+// overlapping returns the agent of the first site overlapping prefix
+func overlapping(sites []site, prefix netip.Prefix) string {
+	for _, s := range sites {
+		if s.prefix.Overlaps(prefix) {
+			return s.agentID
+		}
+	}
+	return ""
+}
+
+// peerAgents returns the agents with sites other than exclude, sorted
+func peerAgents(sites []site, exclude string) []string {
+	seen := make(map[string]bool)
+	var peers []string
+	for _, s := range sites {
+		if s.agentID != exclude && !seen[s.agentID] {
+			seen[s.agentID] = true
+			peers = append(peers, s.agentID)
+		}
+	}
+	sort.Strings(peers)
+	return peers
+}
+
+// joinSiteMesh adds the sites a registering gateway advertises and tells
+// the other gateways of the mesh to refresh their routes
+func (s *Server) joinSiteMesh(si *SessionInfo, advertised []string) {
+	if si.Type != proto.AgentType_GATEWAY || len(advertised) == 0 {
+		return
+	}
+	overlay, err := netip.ParsePrefix(s.config.Network.OverlayCIDR)
+	if err != nil {
+		return
+	}
+
+	user, err := s.db.GetUserByID(si.UserID)
+	if err != nil {
+		log.Printf("Agent %s left out of the site mesh: %v", si.AgentID, err)
+		return
+	}
+	var allowed []netip.Prefix
+	for _, p := range user.SitePrefixes {
+		// Prefixes are validated when stored
+		if prefix, err := netip.ParsePrefix(p); err == nil {
+			allowed = append(allowed, prefix)
+		}
+	}
+
+	var peers []string
+	si.sites, peers = s.sites.join(si, advertised, allowed, overlay.Masked())
+	for _, peer := range peers {
+		s.notifyRouteChange(peer)
+	}
+}
+
+// updateSiteMesh replaces the sites of a gateway session with those it
+// advertises now, learned over BGP for instance, and tells the gateways
+// whose routes changed to refresh them
+func (s *Server) updateSiteMesh(si *SessionInfo, advertised []string) {
+	if si.Type != proto.AgentType_GATEWAY {
+		return
+	}
+	peers := s.sites.leave(si)
+	si.sites = nil
+	s.joinSiteMesh(si, advertised)
+	for _, peer := range peers {
+		s.notifyRouteChange(peer)
+	}
+	// Its routes to the other sites come with sites of its own
+	s.notifyRouteChange(si.AgentID)
+}
+
+// leaveSiteMesh removes the sites of an ended session and tells the other
+// gateways of the mesh to refresh their routes
+func (s *Server) leaveSiteMesh(si *SessionInfo) {
+	if len(si.sites) == 0 {
+		return
+	}
+	for _, peer := range s.sites.leave(si) {
+		s.notifyRouteChange(peer)
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// listenFDsStart is the first descriptor systemd passes with socket
+// activation
+const listenFDsStart = 3
+
+// SystemdSockets are the sockets systemd passed with socket activation
+// (LISTEN_FDS): the UDP socket of QUIC and the TCP socket of the REST
+// gateway, nil if not passed
+type SystemdSockets struct {
+	UDP *net.UDPConn
+	TCP net.Listener
+}
+
+// ActivatedSockets returns the sockets systemd passed the process,
+// telling them apart by type. Without socket activation both are nil.
+// The sockets an old server process hands over on a binary upgrade
+// (UpgradeFDsEnv) are taken the same way.
+func ActivatedSockets() (*SystemdSockets, error) {
+	sockets := &SystemdSockets{}
+	n := 0
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err == nil && pid == os.Getpid() {
+		n, _ = strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	} else {
+		n, _ = strconv.Atoi(os.Getenv(UpgradeFDsEnv))
+	}
+	// Commands the server runs must not take the sockets for theirs
+	for _, name := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES", UpgradeFDsEnv} {
+		os.Unsetenv(name)
+	}
+	if n <= 0 {
+		return sockets, nil
+	}
+
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		file := os.NewFile(uintptr(fd), "systemd-socket-"+strconv.Itoa(fd))
+		if conn, err := net.FilePacketConn(file); err == nil {
+			file.Close()
+			udp, ok := conn.(*net.UDPConn)
+			if !ok || sockets.UDP != nil {
+				conn.Close()
+				return nil, fmt.Errorf("unexpected datagram socket %d from systemd", fd)
+			}
+			sockets.UDP = udp
+			continue
+		}
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil || sockets.TCP != nil {
+			return nil, fmt.Errorf("unexpected socket %d from systemd", fd)
+		}
+		sockets.TCP = listener
+	}
+	return sockets, nil
+}
+
+// SdNotify sends a state change such as "READY=1" to systemd, doing
+// nothing when systemd does not supervise the server with Type=notify
+func SdNotify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	// A leading @ names an abstract socket, which net understands
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// watchdogInterval returns the interval systemd expects watchdog pings
+// within (WatchdogSec), 0 without a watchdog
+func watchdogInterval() time.Duration {
+	usec, err := strconv.Atoi(os.Getenv("WATCHDOG_USEC"))
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// watchdogLoop pings the systemd watchdog twice per interval while the
+// relay workers make progress. A worker that took nothing from its queue
+// since the last check while packets wait is hung; without the pings
+// systemd restarts the server.
+func (s *Server) watchdogLoop(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	popped := make([]uint64, len(s.relay.queues))
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		hung := -1
+		for i, queue := range s.relay.queues {
+			n := queue.Popped()
+			if n == popped[i] && queue.Len() > 0 {
+				hung = i
+			}
+			popped[i] = n
+		}
+		if hung >= 0 {
+			log.Printf("Relay worker %d made no progress with %d packets queued, withholding the watchdog ping",
+				hung, s.relay.queues[hung].Len())
+			continue
+		}
+
+		sessions := 0
+		s.sessions.Range(func(key, value interface{}) bool {
+			sessions++
+			return true
+		})
+		if err := SdNotify(fmt.Sprintf("WATCHDOG=1\nSTATUS=%d sessions", sessions)); err != nil {
+			log.Printf("Failed to ping the systemd watchdog: %v", err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/taills/EasyAnyLink/common/packet"
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultTraceBufferSize is the number of relay traces kept by default
+const defaultTraceBufferSize = 1000
+
+// relayTracer samples relayed packets and keeps their routing decisions
+// in a fixed-size ring buffer
+type relayTracer struct {
+	sampleRate atomic.Uint32
+	counter    atomic.Uint64
+
+	mu     sync.Mutex
+	traces []*proto.RelayTrace
+	next   int
+	full   bool
+}
+
+// newRelayTracer creates a tracer keeping size traces
+func newRelayTracer(size int, sampleRate uint32) *relayTracer {
+	if size <= 0 {
+		size = defaultTraceBufferSize
+	}
+	t := &relayTracer{traces: make([]*proto.RelayTrace, size)}
+	t.sampleRate.Store(sampleRate)
+	return t
+}
+
+// start returns a trace for dp if it is sampled, or nil. Header fields are
+// captured before the packet is modified by forwarding.
+func (t *relayTracer) start(dp *proto.DataPacket) *proto.RelayTrace {
+	rate := t.sampleRate.Load()
+	if rate == 0 || t.counter.Add(1)%uint64(rate) != 0 {
+		return nil
+	}
+
+	trace := &proto.RelayTrace{
+		Time:          timestamppb.New(time.Now()),
+		SessionId:     dp.SessionId,
+		SourceAgentId: dp.SourceAgentId,
+		Length:        uint32(len(dp.Payload)),
+	}
+	if h, err := packet.ParseIPv4(dp.Payload); err == nil {
+		trace.SourceIp = h.Src.String()
+		trace.DestinationIp = h.Dst.String()
+		trace.Protocol = uint32(h.Protocol)
+		trace.Ttl = uint32(h.TTL)
+		if port, ok := destinationPort(dp.Payload, h); ok {
+			trace.DestinationPort = uint32(port)
+		}
+	}
+	return trace
+}
+
+// finish records the decision of a sampled packet
+func (t *relayTracer) finish(trace *proto.RelayTrace, decision, destAgentID string, err error) {
+	if trace == nil {
+		return
+	}
+
+	trace.Decision = decision
+	trace.DestinationAgentId = destAgentID
+	if err != nil {
+		trace.Detail = err.Error()
+	}
+
+	t.mu.Lock()
+	t.traces[t.next] = trace
+	t.next = (t.next + 1) % len(t.traces)
+	if t.next == 0 {
+		t.full = true
+	}
+	t.mu.Unlock()
+}
+
+// recent returns up to limit traces involving agentID (all if empty),
+// newest first
+func (t *relayTracer) recent(agentID string, limit int) []*proto.RelayTrace {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := t.next
+	if t.full {
+		count = len(t.traces)
+	}
+
+	var result []*proto.RelayTrace
+	for i := 0; i < count; i++ {
+		trace := t.traces[(t.next-1-i+len(t.traces))%len(t.traces)]
+		if agentID != "" && trace.SourceAgentId != agentID && trace.DestinationAgentId != agentID {
+			continue
+		}
+		result = append(result, trace)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// SetRelayTracing changes the relay trace sampling rate
+func (s *Server) SetRelayTracing(ctx context.Context, req *proto.SetRelayTracingRequest) (*proto.RelayTracingResponse, error) {
+	admin, err := s.authorizeAdmin(ctx, permOperate)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tracer.sampleRate.Store(req.SampleRate)
+	log.Printf("Relay trace sampling set to %d by %s", req.SampleRate, admin.Username)
+
+	return &proto.RelayTracingResponse{
+		SampleRate: req.SampleRate,
+		BufferSize: uint32(len(s.tracer.traces)),
+	}, nil
+}
+
+// ListRelayTraces returns recently sampled relay decisions
+func (s *Server) ListRelayTraces(ctx context.Context, req *proto.ListRelayTracesRequest) (*proto.ListRelayTracesResponse, error) {
+	if _, err := s.authorizeAdmin(ctx, permRead); err != nil {
+		return nil, err
+	}
+
+	return &proto.ListRelayTracesResponse{
+		Traces:     s.tracer.recent(req.AgentId, int(req.Limit)),
+		SampleRate: s.tracer.sampleRate.Load(),
+	}, nil
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/pem"
+	"fmt"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetTrustBundle returns the CA certificates configured with ca_file. It
+// needs no authentication: new agents fetch it before they can verify the
+// server, and check it against a fingerprint handed out at enrollment.
+func (s *Server) GetTrustBundle(ctx context.Context, req *proto.TrustBundleRequest) (*proto.TrustBundleResponse, error) {
+	if s.trustBundle == nil {
+		return nil, status.Errorf(codes.NotFound, "server has no trust bundle configured")
+	}
+
+	resp := &proto.TrustBundleResponse{Bundle: s.trustBundle}
+	if block, _ := pem.Decode(s.trustBundle); block != nil {
+		resp.Fingerprint = fmt.Sprintf("%x", sha256.Sum256(block.Bytes))
+	}
+	return resp, nil
+}
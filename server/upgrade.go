@@ -0,0 +1,29 @@
+package server
+
+import (
+	"log"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+)
+
+// UpgradeFDsEnv names the environment variable telling a server started
+// for a binary upgrade how many listening sockets it inherited from the
+// old process, passed like systemd does from descriptor 3 on
+const UpgradeFDsEnv = "EASYANYLINK_UPGRADE_FDS"
+
+// HandOver ends every live session for a binary upgrade once the new
+// process accepts connections. The agents reconnect to it and keep their
+// overlay IPs; they are not marked offline in between. It returns the
+// number of sessions ended.
+func (s *Server) HandOver() int {
+	var sessions []*SessionInfo
+	s.sessions.Range(func(key, value interface{}) bool {
+		sessions = append(sessions, value.(*SessionInfo))
+		return true
+	})
+	for _, si := range sessions {
+		s.terminateSession(si, proto.DisconnectReason_DISCONNECT_SERVER_UPGRADE, "server upgrade")
+	}
+	log.Printf("Handed %d sessions over to the new server process", len(sessions))
+	return len(sessions)
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	_ "github.com/taills/EasyAnyLink/common/proto/easyanylink/v1" // v1 descriptors for reflection
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc"
+)
+
+// Protocol versions agents send in RegisterRequest, by API version
+const (
+	protocolVersionV1 = "1.0.0"
+	protocolVersionV2 = "2.0.0"
+)
+
+// legacyPackages are the older package names the v2 services are also
+// served under. v2 is wire compatible with v1, so the v2 handlers decode
+// v1 calls as they are. Releases before API versioning used "proto".
+var legacyPackages = []string{"easyanylink.v1", "proto"}
+
+// serverCapabilities are the optional features announced to agents
+var serverCapabilities = []string{
+	proto.CapabilityEcho,
+	proto.CapabilityManagedConfig,
+	proto.CapabilityServerBusy,
+	proto.CapabilitySessionEnded,
+	proto.CapabilitySiteMesh,
+	proto.CapabilityConfigGeneration,
+	proto.CapabilityPadding,
+	proto.CapabilityGatewayReady,
+	proto.CapabilityAccessRequests,
+	proto.CapabilitySessionHandoff,
+}
+
+// RegisterServices registers the agent and admin services on a gRPC
+// server under the current and every legacy API version
+func RegisterServices(gs *grpc.Server, s *Server) {
+	proto.RegisterAgentServiceServer(gs, s)
+	proto.RegisterAdminServiceServer(gs, s)
+
+	for _, pkg := range legacyPackages {
+		gs.RegisterService(legacyService(&proto.AgentService_ServiceDesc, pkg), s)
+		gs.RegisterService(legacyService(&proto.AdminService_ServiceDesc, pkg), s)
+	}
+}
+
+// legacyService returns a copy of a v2 service description named after
+// the service in an older package, pointing reflection at the v1 file
+func legacyService(desc *grpc.ServiceDesc, pkg string) *grpc.ServiceDesc {
+	legacy := *desc
+	legacy.ServiceName = pkg + desc.ServiceName[strings.LastIndex(desc.ServiceName, "."):]
+	legacy.Metadata = strings.Replace(desc.Metadata.(string), "/v2/", "/v1/", 1)
+	return &legacy
+}
+
+// apiVersion returns the API version of the service a call was made on,
+// 1 for easyanylink.v1 and the unversioned legacy names
+func apiVersion(ctx context.Context) int {
+	method, _ := grpc.Method(ctx)
+	if strings.HasPrefix(method, "/easyanylink.v2.") {
+		return 2
+	}
+	return 1
+}
+
+// isProtocolCompatible checks if the client protocol version is compatible
+func (s *Server) isProtocolCompatible(version string) bool {
+	return version == protocolVersionV1 || version == protocolVersionV2
+}
+
+// protocolVersion returns the protocol version the server reports to a
+// caller of the given API version
+func protocolVersion(api int) string {
+	if api >= 2 {
+		return protocolVersionV2
+	}
+	return protocolVersionV1
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/taills/EasyAnyLink/common/config"
+)
+
+// Webhook request headers
+const (
+	WebhookSignatureHeader = "X-EasyAnyLink-Signature" // "sha256=" + hex HMAC of the body
+	WebhookEventHeader     = "X-EasyAnyLink-Event"     // event type
+)
+
+// Webhook event types
+const (
+	EventAgentOnline     = "agent.online"
+	EventAgentOffline    = "agent.offline"
+	EventAuthFailures    = "auth.failure_streak"
+	EventPoolExhausted   = "pool.exhausted"
+	EventCertExpiring    = "cert.expiring"
+	EventUsageThreshold  = "usage.threshold"
+	EventRouteLoop       = "route.loop"
+	EventConfigDrift     = "config.drift"
+	EventClockSkew       = "agent.clock_skew"
+	EventAccessRequested = "access.requested"
+)
+
+const (
+	webhookTimeout  = 10 * time.Second
+	webhookAttempts = 3
+	webhookQueueLen = 256
+)
+
+// webhookEvent is the body of every webhook request
+type webhookEvent struct {
+	ID   string      `json:"id"`
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// webhookDispatcher delivers events to the configured webhook
+// subscriptions. A nil dispatcher discards all events.
+type webhookDispatcher struct {
+	subscribers []*webhookSubscriber
+	wg          sync.WaitGroup
+	mu          sync.RWMutex
+	stopped     bool // events published after stop are discarded
+}
+
+// webhookSubscriber delivers events of its types to one URL, in order
+type webhookSubscriber struct {
+	cfg    config.Webhook
+	events map[string]bool // nil for all events
+	client *http.Client
+	queue  chan *webhookEvent
+}
+
+// newWebhookDispatcher starts a delivery worker per subscription, or
+// returns nil if there are none
+func newWebhookDispatcher(hooks []config.Webhook) *webhookDispatcher {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	d := &webhookDispatcher{}
+	for _, hook := range hooks {
+		sub := &webhookSubscriber{
+			cfg:    hook,
+			client: &http.Client{Timeout: webhookTimeout},
+			queue:  make(chan *webhookEvent, webhookQueueLen),
+		}
+		if len(hook.Events) > 0 {
+			sub.events = make(map[string]bool)
+			for _, event := range hook.Events {
+				sub.events[event] = true
+			}
+		}
+		d.subscribers = append(d.subscribers, sub)
+
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			sub.run()
+		}()
+	}
+
+	return d
+}
+
+// publish queues an event for every subscription of its type without
+// blocking the caller
+func (d *webhookDispatcher) publish(eventType string, data interface{}) {
+	if d == nil {
+		return
+	}
+
+	event := &webhookEvent{
+		ID:   uuid.New().String(),
+		Type: eventType,
+		Time: time.Now().UTC(),
+		Data: data,
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.stopped {
+		return
+	}
+
+	for _, sub := range d.subscribers {
+		if sub.events != nil && !sub.events[eventType] {
+			continue
+		}
+		select {
+		case sub.queue <- event:
+		default:
+			log.Printf("Webhook queue for %s full, dropping %s event", sub.cfg.URL, eventType)
+		}
+	}
+}
+
+// stop delivers queued events and stops all workers
+func (d *webhookDispatcher) stop() {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	if !d.stopped {
+		d.stopped = true
+		for _, sub := range d.subscribers {
+			close(sub.queue)
+		}
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+// run delivers queued events, retrying failed deliveries
+func (s *webhookSubscriber) run() {
+	for event := range s.queue {
+		body, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("Failed to encode %s webhook: %v", event.Type, err)
+			continue
+		}
+
+		for attempt := 1; ; attempt++ {
+			err = s.post(event.Type, body)
+			if err == nil || attempt == webhookAttempts {
+				break
+			}
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		if err != nil {
+			log.Printf("Failed to deliver %s webhook to %s: %v", event.Type, s.cfg.URL, err)
+		}
+	}
+}
+
+// post sends one webhook request
+func (s *webhookSubscriber) post(eventType string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, eventType)
+	if s.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+		mac.Write(body)
+		req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
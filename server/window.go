@@ -0,0 +1,275 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	proto "github.com/taills/EasyAnyLink/common/proto/easyanylink/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// windowCheckInterval is how often the server looks for access windows
+// that opened or closed, so agents re-fetch their routes
+const windowCheckInterval = time.Minute
+
+// AccessWindow limits when a routing or ACL rule applies, e.g. office
+// hours for a contractor or an emergency grant that expires. The zero
+// value always applies.
+type AccessWindow struct {
+	ValidFrom  time.Time `json:"valid_from"`  // zero for no start
+	ValidUntil time.Time `json:"valid_until"` // zero for no end
+	Schedule   string    `json:"schedule"`    // weekly schedule, empty for every day
+}
+
+// IsZero reports whether the window always applies
+func (w AccessWindow) IsZero() bool {
+	return w.ValidFrom.IsZero() && w.ValidUntil.IsZero() && w.Schedule == ""
+}
+
+// accessWindow is a parsed AccessWindow, nil for one that always applies
+type accessWindow struct {
+	from     time.Time
+	until    time.Time
+	schedule *weeklySchedule // nil for every day
+}
+
+// parse prepares the window for evaluation
+func (w AccessWindow) parse() (*accessWindow, error) {
+	if w.IsZero() {
+		return nil, nil
+	}
+	window := &accessWindow{from: w.ValidFrom, until: w.ValidUntil}
+	if w.Schedule != "" {
+		schedule, err := parseSchedule(w.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		window.schedule = schedule
+	}
+	return window, nil
+}
+
+// active reports whether the window is open at now
+func (w *accessWindow) active(now time.Time) bool {
+	if w == nil {
+		return true
+	}
+	if !w.from.IsZero() && now.Before(w.from) {
+		return false
+	}
+	if !w.until.IsZero() && !now.Before(w.until) {
+		return false
+	}
+	return w.schedule == nil || w.schedule.contains(now)
+}
+
+// activeAt reports whether the window is open at now. Windows are
+// validated when stored, a broken one never applies.
+func (w AccessWindow) activeAt(now time.Time) bool {
+	window, err := w.parse()
+	if err != nil {
+		log.Printf("Ignoring invalid access window: %v", err)
+		return false
+	}
+	return window.active(now)
+}
+
+// weeklySchedule is a daily time range on selected weekdays. A range that
+// ends before it starts runs past midnight into the next day.
+type weeklySchedule struct {
+	days  [7]bool // indexed by time.Weekday
+	start int     // minutes after midnight
+	end   int
+	loc   *time.Location
+}
+
+// weekdays maps schedule day names to weekdays
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseSchedule parses "DAYS HH:MM-HH:MM [TIMEZONE]", where DAYS is
+// "daily" or a comma separated list of days and day ranges such as
+// "mon-fri" or "sat,sun". Times are in the server's local time unless an
+// IANA time zone is given.
+func parseSchedule(s string) (*weeklySchedule, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) != 2 && len(fields) != 3 {
+		return nil, fmt.Errorf("invalid schedule %q: want \"DAYS HH:MM-HH:MM [TIMEZONE]\"", s)
+	}
+
+	schedule := &weeklySchedule{loc: time.Local}
+	if fields[0] == "daily" {
+		for i := range schedule.days {
+			schedule.days[i] = true
+		}
+	} else {
+		for _, part := range strings.Split(fields[0], ",") {
+			first, last, isRange := strings.Cut(part, "-")
+			from, ok := weekdays[first]
+			if !ok {
+				return nil, fmt.Errorf("invalid schedule day %q", first)
+			}
+			to := from
+			if isRange {
+				if to, ok = weekdays[last]; !ok {
+					return nil, fmt.Errorf("invalid schedule day %q", last)
+				}
+			}
+			for d := from; ; d = (d + 1) % 7 {
+				schedule.days[d] = true
+				if d == to {
+					break
+				}
+			}
+		}
+	}
+
+	start, end, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid schedule time range %q", fields[1])
+	}
+	var err error
+	if schedule.start, err = parseClock(start); err != nil {
+		return nil, err
+	}
+	if schedule.end, err = parseClock(end); err != nil {
+		return nil, err
+	}
+	if schedule.start == schedule.end {
+		return nil, fmt.Errorf("schedule time range %q is empty", fields[1])
+	}
+
+	if len(fields) == 3 {
+		// Time zone names are case sensitive
+		name := strings.Fields(s)[2]
+		if schedule.loc, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("invalid schedule time zone %q: %w", name, err)
+		}
+	}
+	return schedule, nil
+}
+
+// parseClock parses HH:MM into minutes after midnight, 24:00 is the end
+// of the day
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if !ok || err1 != nil || err2 != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid schedule time %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// contains reports whether now falls into the schedule
+func (s *weeklySchedule) contains(now time.Time) bool {
+	local := now.In(s.loc)
+	minute := local.Hour()*60 + local.Minute()
+	day := local.Weekday()
+
+	if s.start < s.end {
+		return s.days[day] && minute >= s.start && minute < s.end
+	}
+	// Past midnight: the evening belongs to today, the morning to the
+	// range that started yesterday
+	return (s.days[day] && minute >= s.start) || (s.days[(day+6)%7] && minute < s.end)
+}
+
+// windowFromProto validates a proto access window
+func windowFromProto(pw *proto.AccessWindow) (AccessWindow, error) {
+	var w AccessWindow
+	if pw == nil {
+		return w, nil
+	}
+	if pw.ValidFrom != nil {
+		w.ValidFrom = pw.ValidFrom.AsTime()
+	}
+	if pw.ValidUntil != nil {
+		w.ValidUntil = pw.ValidUntil.AsTime()
+	}
+	w.Schedule = strings.TrimSpace(pw.Schedule)
+
+	if !w.ValidFrom.IsZero() && !w.ValidUntil.IsZero() && !w.ValidUntil.After(w.ValidFrom) {
+		return w, status.Errorf(codes.InvalidArgument, "valid_until must be after valid_from")
+	}
+	if w.Schedule != "" {
+		if _, err := parseSchedule(w.Schedule); err != nil {
+			return w, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+	return w, nil
+}
+
+// windowToProto converts an access window to proto format, nil if it
+// always applies
+func windowToProto(w AccessWindow) *proto.AccessWindow {
+	if w.IsZero() {
+		return nil
+	}
+	pw := &proto.AccessWindow{Schedule: w.Schedule}
+	if !w.ValidFrom.IsZero() {
+		pw.ValidFrom = timestamppb.New(w.ValidFrom)
+	}
+	if !w.ValidUntil.IsZero() {
+		pw.ValidUntil = timestamppb.New(w.ValidUntil)
+	}
+	return pw
+}
+
+// windowLoop flags connected agents to re-fetch their routes when an
+// access window of one of their routing rules opens or closes, so expired
+// grants are revoked without an admin action, and deletes the rules of
+// access requests whose grant ended
+func (s *Server) windowLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(windowCheckInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.expireAccessGrants(now)
+			s.checkWindows(last, now)
+			last = now
+		}
+	}
+}
+
+// checkWindows flags agents with a routing rule whose window changed state
+// between last and now
+func (s *Server) checkWindows(last, now time.Time) {
+	agents := make(map[string]bool)
+	s.sessions.Range(func(key, value interface{}) bool {
+		agents[value.(*SessionInfo).AgentID] = true
+		return true
+	})
+
+	for agentID := range agents {
+		rules, err := s.db.GetRoutingRulesByAgentID(agentID)
+		if err != nil {
+			log.Printf("Failed to check access windows of agent %s: %v", agentID, err)
+			continue
+		}
+		for _, rule := range rules {
+			if rule.Window.IsZero() {
+				continue
+			}
+			if rule.Window.activeAt(last) != rule.Window.activeAt(now) {
+				log.Printf("Access window of routing rule %d changed, refreshing routes of agent %s", rule.ID, agentID)
+				s.notifyRouteChange(agentID)
+				break
+			}
+		}
+	}
+}